@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// runAuth runs the auth subcommands, which manage the token stored for the MCP
+// server.
+func runAuth(logger *slog.Logger, args []string) {
+	if len(args) < 1 {
+		logger.Error("no auth command provided",
+			slog.String("available_commands", "login, logout, status"),
+		)
+		exit(exitCodeSetupFailure)
+	}
+
+	store, err := newCredentialStore()
+	if err != nil {
+		logger.Error("failed to open credentials store",
+			slog.String("error", err.Error()),
+		)
+		exit(exitCodeSetupFailure)
+	}
+	logger = logger.With(
+		slog.String("mcp_url", *mcpURL),
+		slog.String("store", store.String()),
+	)
+	if activeProfile != "" {
+		logger = logger.With(slog.String("profile", activeProfile))
+	}
+
+	switch args[0] {
+	case "login":
+		token := *mcpToken
+		if token == "" {
+			token, err = readToken()
+			if err != nil {
+				logger.Error("failed to read token",
+					slog.String("error", err.Error()),
+				)
+				exit(exitCodeSetupFailure)
+			}
+		}
+		if err := store.Set(credentialKey(), token); err != nil {
+			logger.Error("failed to store token",
+				slog.String("error", err.Error()),
+			)
+			exit(exitCodeRunFailure)
+		}
+		logger.Info("token stored successfully")
+
+	case "logout":
+		if err := store.Delete(credentialKey()); errors.Is(err, errCredentialNotFound) {
+			logger.Info("no token stored")
+		} else if err != nil {
+			logger.Error("failed to remove token",
+				slog.String("error", err.Error()),
+			)
+			exit(exitCodeRunFailure)
+		} else {
+			logger.Info("token removed successfully")
+		}
+
+	case "status":
+		token, err := store.Get(credentialKey())
+		if errors.Is(err, errCredentialNotFound) {
+			logger.Info("not logged in")
+			return
+		} else if err != nil {
+			logger.Error("failed to read token",
+				slog.String("error", err.Error()),
+			)
+			exit(exitCodeRunFailure)
+		}
+		logger.Info("logged in",
+			slog.String("token", maskToken(token)),
+		)
+
+	default:
+		logger.Error("unknown auth command",
+			slog.String("command", args[0]),
+			slog.String("available_commands", "login, logout, status"),
+		)
+		exit(exitCodeSetupFailure)
+	}
+}
+
+// storedToken returns the token stored for the profile or MCP server, or an
+// empty string if there is none.
+func storedToken(logger *slog.Logger) string {
+	store, err := newCredentialStore()
+	if err != nil {
+		logger.Debug("credentials store not available",
+			slog.String("error", err.Error()),
+		)
+		return ""
+	}
+	token, err := store.Get(credentialKey())
+	if err != nil && !errors.Is(err, errCredentialNotFound) {
+		logger.Warn("failed to read stored token",
+			slog.String("error", err.Error()),
+		)
+	}
+	return token
+}
+
+// readToken reads the token from the first line of the standard input, so it
+// doesn't end up in the shell history.
+func readToken() (string, error) {
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	token := strings.TrimSpace(line)
+	if token == "" {
+		return "", errors.New("empty token")
+	}
+	return token, nil
+}
+
+// maskToken hides all but the last characters of the token.
+func maskToken(token string) string {
+	if len(token) <= 8 {
+		return strings.Repeat("*", len(token))
+	}
+	return strings.Repeat("*", len(token)-4) + token[len(token)-4:]
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// credentialsService is the service name of the credentials stored in the OS
+	// keychain.
+	credentialsService = "teamwork-mcp"
+	// credentialsKeyIterations is the number of PBKDF2 iterations used to derive
+	// the key of the encrypted credentials file.
+	credentialsKeyIterations = 600_000
+)
+
+// errCredentialNotFound is returned when there are no credentials stored for
+// the MCP server.
+var errCredentialNotFound = errors.New("credential not found")
+
+// credentialStore stores the tokens used to authenticate with the MCP servers,
+// identified by their URL.
+type credentialStore interface {
+	Get(server string) (string, error)
+	Set(server, token string) error
+	Delete(server string) error
+	String() string
+}
+
+// newCredentialStore returns the store selected by the TW_MCP_CREDENTIALS_STORE
+// environment variable ("keychain" or "file"). By default the OS keychain is
+// used when available, falling back to the encrypted file (e.g. in containers
+// without a secret service).
+func newCredentialStore() (credentialStore, error) {
+	switch backend := os.Getenv("TW_MCP_CREDENTIALS_STORE"); backend {
+	case "":
+		if store, ok := newKeychainStore(); ok {
+			return store, nil
+		}
+		return newFileCredentialStore()
+	case "keychain":
+		store, ok := newKeychainStore()
+		if !ok {
+			return nil, errors.New("the OS keychain is not available")
+		}
+		return store, nil
+	case "file":
+		return newFileCredentialStore()
+	default:
+		return nil, fmt.Errorf("unknown credentials store %q", backend)
+	}
+}
+
+// fileCredentialStore stores the credentials in a file encrypted with AES-GCM,
+// using a key derived from the TW_MCP_CREDENTIALS_KEY environment variable.
+type fileCredentialStore struct {
+	path       string
+	passphrase string
+}
+
+// encryptedCredentials is the content of the encrypted credentials file.
+type encryptedCredentials struct {
+	Salt  []byte `json:"salt"`
+	Nonce []byte `json:"nonce"`
+	Data  []byte `json:"data"`
+}
+
+func newFileCredentialStore() (*fileCredentialStore, error) {
+	passphrase := os.Getenv("TW_MCP_CREDENTIALS_KEY")
+	if passphrase == "" {
+		return nil, errors.New("TW_MCP_CREDENTIALS_KEY is required to store credentials in a file")
+	}
+	path := os.Getenv("TW_MCP_CREDENTIALS_FILE")
+	if path == "" {
+		configDir, err := os.UserConfigDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to find the config directory: %w", err)
+		}
+		path = filepath.Join(configDir, credentialsService, "credentials.json")
+	}
+	return &fileCredentialStore{
+		path:       path,
+		passphrase: passphrase,
+	}, nil
+}
+
+func (f *fileCredentialStore) String() string {
+	return "encrypted file " + f.path
+}
+
+func (f *fileCredentialStore) Get(server string) (string, error) {
+	credentials, err := f.load()
+	if err != nil {
+		return "", err
+	}
+	token, ok := credentials[server]
+	if !ok {
+		return "", errCredentialNotFound
+	}
+	return token, nil
+}
+
+func (f *fileCredentialStore) Set(server, token string) error {
+	credentials, err := f.load()
+	if err != nil {
+		return err
+	}
+	credentials[server] = token
+	return f.save(credentials)
+}
+
+func (f *fileCredentialStore) Delete(server string) error {
+	credentials, err := f.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := credentials[server]; !ok {
+		return errCredentialNotFound
+	}
+	delete(credentials, server)
+	return f.save(credentials)
+}
+
+func (f *fileCredentialStore) load() (map[string]string, error) {
+	content, err := os.ReadFile(f.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return make(map[string]string), nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read credentials file: %w", err)
+	}
+
+	var encrypted encryptedCredentials
+	if err := json.Unmarshal(content, &encrypted); err != nil {
+		return nil, fmt.Errorf("failed to decode credentials file: %w", err)
+	}
+	gcm, err := f.cipher(encrypted.Salt)
+	if err != nil {
+		return nil, err
+	}
+	data, err := gcm.Open(nil, encrypted.Nonce, encrypted.Data, nil)
+	if err != nil {
+		return nil, errors.New("failed to decrypt credentials file, check TW_MCP_CREDENTIALS_KEY")
+	}
+
+	credentials := make(map[string]string)
+	if err := json.Unmarshal(data, &credentials); err != nil {
+		return nil, fmt.Errorf("failed to decode credentials: %w", err)
+	}
+	return credentials, nil
+}
+
+func (f *fileCredentialStore) save(credentials map[string]string) error {
+	data, err := json.Marshal(credentials)
+	if err != nil {
+		return fmt.Errorf("failed to encode credentials: %w", err)
+	}
+
+	encrypted := encryptedCredentials{
+		Salt: make([]byte, 16),
+	}
+	if _, err := rand.Read(encrypted.Salt); err != nil {
+		return fmt.Errorf("failed to generate salt: %w", err)
+	}
+	gcm, err := f.cipher(encrypted.Salt)
+	if err != nil {
+		return err
+	}
+	encrypted.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(encrypted.Nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	encrypted.Data = gcm.Seal(nil, encrypted.Nonce, data, nil)
+
+	content, err := json.Marshal(encrypted)
+	if err != nil {
+		return fmt.Errorf("failed to encode credentials file: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create credentials directory: %w", err)
+	}
+	// the file is replaced atomically, so it is never left half written
+	tmpPath := f.path + ".tmp"
+	if err := os.WriteFile(tmpPath, content, 0o600); err != nil {
+		return fmt.Errorf("failed to write credentials file: %w", err)
+	}
+	if err := os.Rename(tmpPath, f.path); err != nil {
+		return fmt.Errorf("failed to write credentials file: %w", err)
+	}
+	return nil
+}
+
+func (f *fileCredentialStore) cipher(salt []byte) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, f.passphrase, salt, credentialsKeyIterations, 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileCredentialStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials.json")
+	t.Setenv("TW_MCP_CREDENTIALS_FILE", path)
+	t.Setenv("TW_MCP_CREDENTIALS_KEY", "correct horse battery staple")
+
+	store, err := newFileCredentialStore()
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	if _, err := store.Get("https://mcp.example.com"); !errors.Is(err, errCredentialNotFound) {
+		t.Errorf("expected credential not found, got %v", err)
+	}
+
+	const token = "tkn.secret-token"
+	if err := store.Set("https://mcp.example.com", token); err != nil {
+		t.Fatalf("failed to set credential: %v", err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read credentials file: %v", err)
+	}
+	if strings.Contains(string(content), token) {
+		t.Error("expected the token to be encrypted in the credentials file")
+	}
+
+	got, err := store.Get("https://mcp.example.com")
+	if err != nil {
+		t.Fatalf("failed to get credential: %v", err)
+	}
+	if got != token {
+		t.Errorf("expected token %q, got %q", token, got)
+	}
+
+	if err := store.Delete("https://mcp.example.com"); err != nil {
+		t.Fatalf("failed to delete credential: %v", err)
+	}
+	if _, err := store.Get("https://mcp.example.com"); !errors.Is(err, errCredentialNotFound) {
+		t.Errorf("expected credential not found after deleting it, got %v", err)
+	}
+}
+
+func TestFileCredentialStoreWrongKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials.json")
+	t.Setenv("TW_MCP_CREDENTIALS_FILE", path)
+	t.Setenv("TW_MCP_CREDENTIALS_KEY", "correct horse battery staple")
+
+	store, err := newFileCredentialStore()
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	if err := store.Set("https://mcp.example.com", "tkn.secret-token"); err != nil {
+		t.Fatalf("failed to set credential: %v", err)
+	}
+
+	t.Setenv("TW_MCP_CREDENTIALS_KEY", "wrong key")
+	store, err = newFileCredentialStore()
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	_, err = store.Get("https://mcp.example.com")
+	if err == nil || !strings.Contains(err.Error(), "failed to decrypt") {
+		t.Errorf("expected a decryption error, got %v", err)
+	}
+	// the credentials encrypted with the other key aren't overwritten
+	if err := store.Set("https://other.example.com", "tkn.other-token"); err == nil {
+		t.Error("expected an error storing a credential with the wrong key")
+	}
+}
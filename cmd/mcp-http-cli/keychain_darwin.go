@@ -0,0 +1,70 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// keychainStore stores the credentials in the macOS keychain, using the
+// security command.
+type keychainStore struct{}
+
+func newKeychainStore() (credentialStore, bool) {
+	if _, err := exec.LookPath("security"); err != nil {
+		return nil, false
+	}
+	return keychainStore{}, true
+}
+
+func (keychainStore) String() string {
+	return "macOS keychain"
+}
+
+func (keychainStore) Get(server string) (string, error) {
+	output, err := exec.Command("security", "find-generic-password",
+		"-s", credentialsService, "-a", server, "-w").Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return "", errCredentialNotFound
+		}
+		return "", fmt.Errorf("failed to read keychain: %w", err)
+	}
+	return strings.TrimRight(string(output), "\n"), nil
+}
+
+func (keychainStore) Set(server, token string) error {
+	// the command is read from stdin in interactive mode, so the token isn't
+	// visible in the arguments of the process (e.g. with ps)
+	command := exec.Command("security", "-i")
+	command.Stdin = strings.NewReader(fmt.Sprintf("add-generic-password -U -s %s -a %s -w %s\n",
+		keychainQuote(credentialsService), keychainQuote(server), keychainQuote(token)))
+	output, err := command.CombinedOutput()
+	// the interactive mode prints a prompt, and doesn't fail when a command
+	// fails, but reports it
+	message := strings.TrimSpace(strings.ReplaceAll(string(output), "security>", ""))
+	if err != nil || message != "" {
+		return fmt.Errorf("failed to write keychain: %s", message)
+	}
+	return nil
+}
+
+// keychainQuote quotes an argument of the security interactive mode, escaping
+// the quotes and backslashes.
+func keychainQuote(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
+func (keychainStore) Delete(server string) error {
+	if err := exec.Command("security", "delete-generic-password",
+		"-s", credentialsService, "-a", server).Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return errCredentialNotFound
+		}
+		return fmt.Errorf("failed to write keychain: %w", err)
+	}
+	return nil
+}
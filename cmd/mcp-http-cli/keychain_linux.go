@@ -0,0 +1,63 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// keychainStore stores the credentials in the Secret Service (e.g. GNOME
+// Keyring or KWallet), using the secret-tool command.
+type keychainStore struct{}
+
+func newKeychainStore() (credentialStore, bool) {
+	// containers and headless hosts usually don't run a session bus
+	if os.Getenv("DBUS_SESSION_BUS_ADDRESS") == "" {
+		return nil, false
+	}
+	if _, err := exec.LookPath("secret-tool"); err != nil {
+		return nil, false
+	}
+	return keychainStore{}, true
+}
+
+func (keychainStore) String() string {
+	return "Secret Service"
+}
+
+func (keychainStore) Get(server string) (string, error) {
+	output, err := exec.Command("secret-tool", "lookup",
+		"service", credentialsService, "account", server).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return "", errCredentialNotFound
+		}
+		return "", fmt.Errorf("failed to read secret service: %w", err)
+	}
+	return string(output), nil
+}
+
+func (keychainStore) Set(server, token string) error {
+	cmd := exec.Command("secret-tool", "store", "--label=Teamwork MCP ("+server+")",
+		"service", credentialsService, "account", server)
+	cmd.Stdin = strings.NewReader(token)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to write secret service: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+func (k keychainStore) Delete(server string) error {
+	// secret-tool clear succeeds even when there is nothing to remove
+	if _, err := k.Get(server); err != nil {
+		return err
+	}
+	if output, err := exec.Command("secret-tool", "clear",
+		"service", credentialsService, "account", server).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to write secret service: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,9 @@
+//go:build !darwin && !linux && !windows
+
+package main
+
+// newKeychainStore reports that there is no supported keychain in this OS, so
+// the encrypted file is used.
+func newKeychainStore() (credentialStore, bool) {
+	return nil, false
+}
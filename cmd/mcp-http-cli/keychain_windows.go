@@ -0,0 +1,119 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	credTypeGeneric         = 1
+	credPersistLocalMachine = 2
+	errorNotFound           = syscall.Errno(1168)
+)
+
+var (
+	advapi32       = syscall.NewLazyDLL("advapi32.dll")
+	procCredReadW  = advapi32.NewProc("CredReadW")
+	procCredWriteW = advapi32.NewProc("CredWriteW")
+	procCredDelete = advapi32.NewProc("CredDeleteW")
+	procCredFree   = advapi32.NewProc("CredFree")
+)
+
+// winCredential is the CREDENTIALW structure of the Windows Credential Manager.
+//
+// https://learn.microsoft.com/en-us/windows/win32/api/wincred/ns-wincred-credentialw
+type winCredential struct {
+	Flags              uint32
+	Type               uint32
+	TargetName         *uint16
+	Comment            *uint16
+	LastWritten        syscall.Filetime
+	CredentialBlobSize uint32
+	CredentialBlob     *byte
+	Persist            uint32
+	AttributeCount     uint32
+	Attributes         uintptr
+	TargetAlias        *uint16
+	UserName           *uint16
+}
+
+// keychainStore stores the credentials in the Windows Credential Manager.
+type keychainStore struct{}
+
+func newKeychainStore() (credentialStore, bool) {
+	if err := advapi32.Load(); err != nil {
+		return nil, false
+	}
+	return keychainStore{}, true
+}
+
+func (keychainStore) String() string {
+	return "Windows Credential Manager"
+}
+
+func (keychainStore) Get(server string) (string, error) {
+	target, err := syscall.UTF16PtrFromString(credentialTarget(server))
+	if err != nil {
+		return "", err
+	}
+	var credential *winCredential
+	ret, _, err := procCredReadW.Call(uintptr(unsafe.Pointer(target)), credTypeGeneric, 0,
+		uintptr(unsafe.Pointer(&credential)))
+	if ret == 0 {
+		if errors.Is(err, errorNotFound) {
+			return "", errCredentialNotFound
+		}
+		return "", fmt.Errorf("failed to read credential manager: %w", err)
+	}
+	defer func() {
+		_, _, _ = procCredFree.Call(uintptr(unsafe.Pointer(credential)))
+	}()
+	return string(unsafe.Slice(credential.CredentialBlob, credential.CredentialBlobSize)), nil
+}
+
+func (keychainStore) Set(server, token string) error {
+	target, err := syscall.UTF16PtrFromString(credentialTarget(server))
+	if err != nil {
+		return err
+	}
+	userName, err := syscall.UTF16PtrFromString(server)
+	if err != nil {
+		return err
+	}
+	blob := []byte(token)
+	credential := winCredential{
+		Type:               credTypeGeneric,
+		TargetName:         target,
+		CredentialBlobSize: uint32(len(blob)),
+		Persist:            credPersistLocalMachine,
+		UserName:           userName,
+	}
+	if len(blob) > 0 {
+		credential.CredentialBlob = &blob[0]
+	}
+	if ret, _, err := procCredWriteW.Call(uintptr(unsafe.Pointer(&credential)), 0); ret == 0 {
+		return fmt.Errorf("failed to write credential manager: %w", err)
+	}
+	return nil
+}
+
+func (keychainStore) Delete(server string) error {
+	target, err := syscall.UTF16PtrFromString(credentialTarget(server))
+	if err != nil {
+		return err
+	}
+	if ret, _, err := procCredDelete.Call(uintptr(unsafe.Pointer(target)), credTypeGeneric, 0); ret == 0 {
+		if errors.Is(err, errorNotFound) {
+			return errCredentialNotFound
+		}
+		return fmt.Errorf("failed to write credential manager: %w", err)
+	}
+	return nil
+}
+
+// credentialTarget returns the name of the credential of the MCP server.
+func credentialTarget(server string) string {
+	return credentialsService + ":" + server
+}
@@ -17,12 +17,20 @@ var (
 		"The URL of the MCP server to connect to")
 	mcpToken = flag.String("mcp-token", os.Getenv("TW_MCP_BEARER_TOKEN"),
 		"The token to use for authentication with the MCP server")
+	profileName = flag.String("profile", os.Getenv("TW_MCP_PROFILE"),
+		"The profile to use, defaults to the current profile")
 )
 
 func main() {
 	defer handleExit()
 
-	resources, teardown := config.Load(os.Stdout)
+	resources, teardown, err := config.Load(os.Stdout)
+	if err != nil {
+		resources.Logger().Error("invalid configuration",
+			slog.String("error", err.Error()),
+		)
+		exit(exitCodeSetupFailure)
+	}
 	defer teardown()
 
 	if err := flag.CommandLine.Parse(os.Args[1:]); err != nil {
@@ -37,6 +45,32 @@ func main() {
 		exit(exitCodeSetupFailure)
 	}
 
+	args := flag.CommandLine.Args()
+	if len(args) < 1 {
+		resources.Logger().Error("no command provided")
+		exit(exitCodeSetupFailure)
+	}
+
+	// the profiles commands manage the profiles and don't connect to the MCP
+	// server
+	if args[0] == "profiles" {
+		runProfiles(resources.Logger(), args[1:])
+		return
+	}
+
+	applyProfile(resources.Logger())
+
+	// the auth commands manage the stored credentials and don't connect to the
+	// MCP server
+	if args[0] == "auth" {
+		runAuth(resources.Logger(), args[1:])
+		return
+	}
+
+	if *mcpToken == "" {
+		*mcpToken = storedToken(resources.Logger())
+	}
+
 	httpClient := resources.TeamworkHTTPClient()
 	if *mcpToken != "" {
 		httpClient.Transport = newAuthRoundTripper(*mcpToken, httpClient.Transport)
@@ -70,12 +104,6 @@ func main() {
 		slog.String("protocol_version", initResult.ProtocolVersion),
 	)
 
-	args := flag.CommandLine.Args()
-	if len(args) < 1 {
-		resources.Logger().Error("no command provided")
-		exit(exitCodeSetupFailure)
-	}
-
 	switch args[0] {
 	case "list-tools":
 		toolsResult, err := mcpClientSession.ListTools(ctx, &mcp.ListToolsParams{})
@@ -137,7 +165,7 @@ func main() {
 	default:
 		resources.Logger().Error("unknown command",
 			slog.String("command", args[0]),
-			slog.String("available_commands", "list-tools, call-tool"),
+			slog.String("available_commands", "auth, profiles, list-tools, call-tool"),
 		)
 		exit(exitCodeSetupFailure)
 	}
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// activeProfile is the name of the profile in use, if any.
+var activeProfile string
+
+// profile is a named MCP server. Its token is kept in the credentials store,
+// so installations behind the same MCP URL can still use different tokens.
+type profile struct {
+	URL string `json:"url"`
+}
+
+// profileConfig is the content of the profiles file.
+type profileConfig struct {
+	Current  string             `json:"current,omitempty"`
+	Profiles map[string]profile `json:"profiles"`
+
+	path string
+}
+
+// loadProfiles reads the profiles file, from the TW_MCP_PROFILES_FILE
+// environment variable or the user config directory. A missing file has no
+// profiles.
+func loadProfiles() (*profileConfig, error) {
+	path := os.Getenv("TW_MCP_PROFILES_FILE")
+	if path == "" {
+		configDir, err := os.UserConfigDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to find the config directory: %w", err)
+		}
+		path = filepath.Join(configDir, credentialsService, "profiles.json")
+	}
+
+	config := &profileConfig{
+		Profiles: make(map[string]profile),
+		path:     path,
+	}
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return config, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read profiles file: %w", err)
+	}
+	if err := json.Unmarshal(content, config); err != nil {
+		return nil, fmt.Errorf("failed to decode profiles file: %w", err)
+	}
+	if config.Profiles == nil {
+		config.Profiles = make(map[string]profile)
+	}
+	return config, nil
+}
+
+func (p *profileConfig) save() error {
+	content, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode profiles: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(p.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create profiles directory: %w", err)
+	}
+	tmpPath := p.path + ".tmp"
+	if err := os.WriteFile(tmpPath, content, 0o600); err != nil {
+		return fmt.Errorf("failed to write profiles file: %w", err)
+	}
+	if err := os.Rename(tmpPath, p.path); err != nil {
+		return fmt.Errorf("failed to write profiles file: %w", err)
+	}
+	return nil
+}
+
+// applyProfile selects the profile from the -profile flag or, when empty, the
+// current profile, using its URL unless -mcp-url was provided.
+func applyProfile(logger *slog.Logger) {
+	profiles, err := loadProfiles()
+	if err != nil {
+		logger.Error("failed to load profiles",
+			slog.String("error", err.Error()),
+		)
+		exit(exitCodeSetupFailure)
+	}
+
+	name := *profileName
+	if name == "" {
+		name = profiles.Current
+	}
+	if name == "" {
+		return
+	}
+	selected, ok := profiles.Profiles[name]
+	if !ok {
+		logger.Error("unknown profile",
+			slog.String("profile", name),
+		)
+		exit(exitCodeSetupFailure)
+	}
+	activeProfile = name
+
+	var urlProvided bool
+	flag.Visit(func(f *flag.Flag) {
+		urlProvided = urlProvided || f.Name == "mcp-url"
+	})
+	if !urlProvided {
+		*mcpURL = selected.URL
+	}
+}
+
+// credentialKey returns the key of the token in the credentials store: the
+// profile when one is in use, otherwise the MCP server URL.
+func credentialKey() string {
+	if activeProfile != "" {
+		return "profile:" + activeProfile
+	}
+	return *mcpURL
+}
+
+// runProfiles runs the profiles subcommands, which manage the named MCP servers
+// and their tokens.
+func runProfiles(logger *slog.Logger, args []string) {
+	if len(args) < 1 {
+		logger.Error("no profiles command provided",
+			slog.String("available_commands", "list, add, use, remove"),
+		)
+		exit(exitCodeSetupFailure)
+	}
+
+	profiles, err := loadProfiles()
+	if err != nil {
+		logger.Error("failed to load profiles",
+			slog.String("error", err.Error()),
+		)
+		exit(exitCodeSetupFailure)
+	}
+
+	if args[0] == "list" {
+		if len(profiles.Profiles) == 0 {
+			logger.Info("no profiles")
+			return
+		}
+		for _, name := range slices.Sorted(maps.Keys(profiles.Profiles)) {
+			logger.Info("profile",
+				slog.String("name", name),
+				slog.String("mcp_url", profiles.Profiles[name].URL),
+				slog.Bool("current", name == profiles.Current),
+			)
+		}
+		return
+	}
+
+	if len(args) < 2 {
+		logger.Error("no profile name provided")
+		exit(exitCodeSetupFailure)
+	}
+	name := args[1]
+	logger = logger.With(slog.String("profile", name))
+	activeProfile = name
+
+	switch args[0] {
+	case "add":
+		token := *mcpToken
+		if token == "" {
+			token, err = readToken()
+			if err != nil {
+				logger.Error("failed to read token",
+					slog.String("error", err.Error()),
+				)
+				exit(exitCodeSetupFailure)
+			}
+		}
+		store, err := newCredentialStore()
+		if err != nil {
+			logger.Error("failed to open credentials store",
+				slog.String("error", err.Error()),
+			)
+			exit(exitCodeSetupFailure)
+		}
+		if err := store.Set(credentialKey(), token); err != nil {
+			logger.Error("failed to store token",
+				slog.String("error", err.Error()),
+			)
+			exit(exitCodeRunFailure)
+		}
+		profiles.Profiles[name] = profile{URL: *mcpURL}
+		if profiles.Current == "" {
+			profiles.Current = name
+		}
+		if err := profiles.save(); err != nil {
+			logger.Error("failed to save profiles",
+				slog.String("error", err.Error()),
+			)
+			exit(exitCodeRunFailure)
+		}
+		logger.Info("profile added successfully",
+			slog.String("mcp_url", *mcpURL),
+			slog.Bool("current", profiles.Current == name),
+		)
+
+	case "use":
+		if _, ok := profiles.Profiles[name]; !ok {
+			logger.Error("unknown profile")
+			exit(exitCodeSetupFailure)
+		}
+		profiles.Current = name
+		if err := profiles.save(); err != nil {
+			logger.Error("failed to save profiles",
+				slog.String("error", err.Error()),
+			)
+			exit(exitCodeRunFailure)
+		}
+		logger.Info("profile switched successfully")
+
+	case "remove":
+		if _, ok := profiles.Profiles[name]; !ok {
+			logger.Error("unknown profile")
+			exit(exitCodeSetupFailure)
+		}
+		delete(profiles.Profiles, name)
+		if profiles.Current == name {
+			profiles.Current = ""
+		}
+		if err := profiles.save(); err != nil {
+			logger.Error("failed to save profiles",
+				slog.String("error", err.Error()),
+			)
+			exit(exitCodeRunFailure)
+		}
+		// the token is removed on a best effort basis, as the profile may have
+		// been added with another credentials store
+		if store, err := newCredentialStore(); err == nil {
+			if err := store.Delete(credentialKey()); err != nil && !errors.Is(err, errCredentialNotFound) {
+				logger.Warn("failed to remove token",
+					slog.String("error", err.Error()),
+				)
+			}
+		}
+		logger.Info("profile removed successfully")
+
+	default:
+		logger.Error("unknown profiles command",
+			slog.String("command", args[0]),
+			slog.String("available_commands", "list, add, use, remove"),
+		)
+		exit(exitCodeSetupFailure)
+	}
+}
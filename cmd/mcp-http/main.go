@@ -3,15 +3,21 @@ package main
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
+	"net/netip"
 	"os"
 	"os/signal"
 	"regexp"
 	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -21,10 +27,14 @@ import (
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/teamwork/mcp/internal/auth"
 	"github.com/teamwork/mcp/internal/config"
+	"github.com/teamwork/mcp/internal/notifier"
 	"github.com/teamwork/mcp/internal/request"
+	"github.com/teamwork/mcp/internal/scheduler"
+	"github.com/teamwork/mcp/internal/textextract"
 	"github.com/teamwork/mcp/internal/toolsets"
 	"github.com/teamwork/mcp/internal/twdesk"
 	"github.com/teamwork/mcp/internal/twprojects"
+	"github.com/teamwork/mcp/internal/webhook"
 	"github.com/teamwork/twapi-go-sdk/session"
 )
 
@@ -33,38 +43,91 @@ var reBearerToken = regexp.MustCompile(`^Bearer (.+)$`)
 func main() {
 	defer handleExit()
 
-	resources, teardown := config.Load(os.Stdout)
+	resources, teardown, err := config.Load(os.Stdout)
+	if err != nil {
+		resources.Logger().Error("invalid configuration",
+			slog.String("error", err.Error()),
+		)
+		exit(exitCodeSetupFailure)
+	}
 	defer teardown()
 
 	done := make(chan os.Signal, 1)
 	signal.Notify(done, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
 
-	mcpServer, err := newMCPServer(resources)
-	if err != nil {
+	mcpServers := newMCPServerPool(resources)
+	// build the default server upfront, so any setup failure is detected on
+	// startup instead of on the first request
+	if _, err := mcpServers.get(defaultSessionOptions()); err != nil {
 		resources.Logger().Error("failed to create MCP server",
 			slog.String("error", err.Error()),
 		)
 		exit(exitCodeSetupFailure)
 	}
-	mcpHTTPServer := mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server {
-		return mcpServer
+	mcpHTTPServer := mcp.NewStreamableHTTPHandler(func(r *http.Request) *mcp.Server {
+		return mcpServerFromContext(r.Context())
 	}, &mcp.StreamableHTTPOptions{
 		Stateless: true,
 	})
 
 	mux := newRouter(resources)
-	mux.Handle("/", mcpHTTPServer)
+	mux.Handle("/", sessionOptionsMiddleware(resources, mcpServers, mcpHTTPServer))
+
+	tlsConfig, err := newTLSConfig(resources)
+	if err != nil {
+		resources.Logger().Error("failed to load tls configuration",
+			slog.String("error", err.Error()),
+		)
+		exit(exitCodeSetupFailure)
+	}
+
+	ipAllowlist, err := newIPAllowlist(resources)
+	if err != nil {
+		resources.Logger().Error("failed to load ip allowlist",
+			slog.String("error", err.Error()),
+		)
+		exit(exitCodeSetupFailure)
+	}
+
+	jobScheduler, err := scheduler.New(resources, resources.FileConfig().Schedules, schedulerTools(resources))
+	if err != nil {
+		resources.Logger().Error("failed to load scheduled jobs",
+			slog.String("error", err.Error()),
+		)
+		exit(exitCodeSetupFailure)
+	}
+	webhookHandler, err := webhook.New(resources, resources.FileConfig().Webhooks, webhookTools(resources))
+	if err != nil {
+		resources.Logger().Error("failed to load webhooks",
+			slog.String("error", err.Error()),
+		)
+		exit(exitCodeSetupFailure)
+	}
+	mux.Handle("/webhooks/{name}", webhookHandler)
+
+	schedulerCtx, cancelScheduler := context.WithCancel(context.Background())
+	defer cancelScheduler()
+	go jobScheduler.Run(schedulerCtx)
 
 	httpServer := &http.Server{
-		Addr:    resources.Info.ServerAddress,
-		Handler: addRouterMiddlewares(resources, mux),
+		Addr:      resources.Info.ServerAddress,
+		Handler:   addRouterMiddlewares(resources, ipAllowlist, mux),
+		TLSConfig: tlsConfig,
 	}
 
 	resources.Logger().Info("starting http server",
 		slog.String("address", resources.Info.ServerAddress),
+		slog.Bool("tls", tlsConfig != nil),
+		slog.Bool("mtls", tlsConfig != nil && tlsConfig.ClientCAs != nil),
 	)
 	go func() {
-		if err := httpServer.ListenAndServe(); err != nil {
+		listenAndServe := httpServer.ListenAndServe
+		if tlsConfig != nil {
+			listenAndServe = func() error {
+				return httpServer.ListenAndServeTLS(resources.Info.TLS.CertFile, resources.Info.TLS.KeyFile)
+			}
+		}
+		if err := listenAndServe(); err != nil {
 			if err != http.ErrServerClosed {
 				resources.Logger().Error("failed to start server",
 					slog.String("address", resources.Info.ServerAddress),
@@ -80,6 +143,7 @@ func main() {
 	}()
 
 	<-done
+	cancelScheduler()
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer func() {
 		cancel()
@@ -92,18 +156,333 @@ func main() {
 	resources.Logger().Info("server stopped")
 }
 
-func newMCPServer(resources config.Resources) (*mcp.Server, error) {
-	projectsGroup := twprojects.DefaultToolsetGroup(false, false, resources.TeamworkEngine())
-	if err := projectsGroup.EnableToolsets(toolsets.MethodAll); err != nil {
-		return nil, fmt.Errorf("failed to enable toolsets: %w", err)
+// newTLSConfig builds the TLS configuration for the HTTP server. It returns nil
+// when TLS is disabled. When a client CA is configured, clients must present a
+// certificate signed by it (mTLS).
+func newTLSConfig(resources config.Resources) (*tls.Config, error) {
+	certFile, keyFile := resources.Info.TLS.CertFile, resources.Info.TLS.KeyFile
+	if certFile == "" && keyFile == "" {
+		if resources.Info.TLS.ClientCAFile != "" {
+			return nil, errors.New("client CA requires the server certificate and key")
+		}
+		return nil, nil
+	}
+	if certFile == "" || keyFile == "" {
+		return nil, errors.New("both server certificate and key must be provided")
+	}
+	// fail fast on invalid certificates, instead of when starting to listen
+	if _, err := tls.LoadX509KeyPair(certFile, keyFile); err != nil {
+		return nil, fmt.Errorf("failed to load server certificate: %w", err)
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+	if resources.Info.TLS.ClientCAFile != "" {
+		caCert, err := os.ReadFile(resources.Info.TLS.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client CA: %w", err)
+		}
+		clientCAs := x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(caCert) {
+			return nil, errors.New("client CA does not contain any valid certificate")
+		}
+		tlsConfig.ClientCAs = clientCAs
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return tlsConfig, nil
+}
+
+const (
+	// headerToolsets allows the client to restrict the toolsets exposed in the
+	// session. It contains a comma-separated list of toolset names (e.g.
+	// "projects,desk"), where "all" enables everything.
+	headerToolsets = "X-MCP-Toolsets"
+	// headerReadOnly allows the client to restrict the session to read-only
+	// tools.
+	headerReadOnly = "X-MCP-Readonly"
+	// headerFinance allows the client to hide the tools and result fields with
+	// financial data (e.g. rates, costs and budgets).
+	headerFinance = "X-MCP-Finance"
+	// headerCompanyID allows the client to bind the session to the projects and
+	// people of a client company (e.g. for a client-facing chatbot).
+	headerCompanyID = "X-MCP-Company-ID"
+)
+
+// sessionOptions defines the capabilities exposed to a MCP session.
+type sessionOptions struct {
+	toolsets  []toolsets.Method
+	readOnly  bool
+	noFinance bool
+	companyID int64
+}
+
+func defaultSessionOptions() sessionOptions {
+	return sessionOptions{
+		toolsets: []toolsets.Method{toolsets.MethodAll},
+	}
+}
+
+// newSessionOptions extracts the session capabilities from the request
+// headers. As the server is stateless, the headers are evaluated on every
+// request, so clients must send them from the initialization onwards.
+func newSessionOptions(r *http.Request) (sessionOptions, error) {
+	options := defaultSessionOptions()
+
+	if value := strings.TrimSpace(r.Header.Get(headerToolsets)); value != "" {
+		options.toolsets = nil
+		for name := range strings.SplitSeq(value, ",") {
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name == "" {
+				continue
+			}
+			if method := toolsets.Method(name); !slices.Contains(options.toolsets, method) {
+				options.toolsets = append(options.toolsets, method)
+			}
+		}
+		if len(options.toolsets) == 0 {
+			return options, fmt.Errorf("header %s does not contain any toolset", headerToolsets)
+		}
+		slices.Sort(options.toolsets)
+	}
+
+	if value := strings.TrimSpace(r.Header.Get(headerReadOnly)); value != "" {
+		readOnly, err := strconv.ParseBool(value)
+		if err != nil {
+			return options, fmt.Errorf("header %s must be a boolean: %w", headerReadOnly, err)
+		}
+		options.readOnly = readOnly
+	}
+
+	if value := strings.TrimSpace(r.Header.Get(headerFinance)); value != "" {
+		finance, err := strconv.ParseBool(value)
+		if err != nil {
+			return options, fmt.Errorf("header %s must be a boolean: %w", headerFinance, err)
+		}
+		options.noFinance = !finance
+	}
+
+	if value := strings.TrimSpace(r.Header.Get(headerCompanyID)); value != "" {
+		companyID, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || companyID <= 0 {
+			return options, fmt.Errorf("header %s must be a positive integer", headerCompanyID)
+		}
+		options.companyID = companyID
+	}
+
+	return options, nil
+}
+
+// key returns an unique identifier for the session options, used to share
+// MCP servers between sessions with the same capabilities.
+func (o sessionOptions) key() string {
+	methods := make([]string, len(o.toolsets))
+	for i, method := range o.toolsets {
+		methods[i] = method.String()
+	}
+	return strings.Join(methods, ",") + ";readonly=" + strconv.FormatBool(o.readOnly) +
+		";finance=" + strconv.FormatBool(!o.noFinance) + ";company=" + strconv.FormatInt(o.companyID, 10)
+}
+
+// mcpServerPoolSize is the maximum number of MCP servers kept in the pool.
+const mcpServerPoolSize = 100
+
+// mcpServerPool lazily creates and caches the MCP servers for each distinct
+// set of session options. The clients choose the options, e.g. any company ID,
+// so the least recently used server is discarded when the pool is full. The
+// requests in flight keep using the discarded server.
+type mcpServerPool struct {
+	resources config.Resources
+	servers   map[string]*pooledMCPServer
+	mutex     sync.Mutex
+}
+
+type pooledMCPServer struct {
+	server *mcp.Server
+	usedAt time.Time
+}
+
+func newMCPServerPool(resources config.Resources) *mcpServerPool {
+	return &mcpServerPool{
+		resources: resources,
+		servers:   make(map[string]*pooledMCPServer),
+	}
+}
+
+func (p *mcpServerPool) get(options sessionOptions) (*mcp.Server, error) {
+	key := options.key()
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if pooled, ok := p.servers[key]; ok {
+		pooled.usedAt = time.Now()
+		return pooled.server, nil
+	}
+	mcpServer, err := newMCPServer(p.resources, options)
+	if err != nil {
+		return nil, err
 	}
+	if len(p.servers) >= mcpServerPoolSize {
+		p.evict()
+	}
+	p.servers[key] = &pooledMCPServer{server: mcpServer, usedAt: time.Now()}
+	return mcpServer, nil
+}
 
-	deskGroup := twdesk.DefaultToolsetGroup(resources.DeskClient())
-	if err := deskGroup.EnableToolsets(toolsets.MethodAll); err != nil {
-		return nil, fmt.Errorf("failed to enable desk toolsets: %w", err)
+// evict removes the least recently used server. It must be called with the
+// mutex locked.
+func (p *mcpServerPool) evict() {
+	var oldestKey string
+	var oldest time.Time
+	for key, pooled := range p.servers {
+		if oldestKey == "" || pooled.usedAt.Before(oldest) {
+			oldestKey, oldest = key, pooled.usedAt
+		}
 	}
+	delete(p.servers, oldestKey)
+}
+
+type mcpServerKey struct{}
+
+type demoSessionKey struct{}
 
-	return config.NewMCPServer(resources, projectsGroup, deskGroup), nil
+// isDemoSession checks if the request is an unauthenticated session served by
+// the demo installation.
+func isDemoSession(ctx context.Context) bool {
+	demo, _ := ctx.Value(demoSessionKey{}).(bool)
+	return demo
+}
+
+func mcpServerFromContext(ctx context.Context) *mcp.Server {
+	mcpServer, _ := ctx.Value(mcpServerKey{}).(*mcp.Server)
+	return mcpServer
+}
+
+func newMCPServer(resources config.Resources, options sessionOptions) (*mcp.Server, error) {
+	projectsGroup := twprojects.DefaultToolsetGroup(options.readOnly, false, resources.TeamworkEngine(),
+		twprojects.WithReportTemplates(resources.FileConfig().Reports),
+		twprojects.WithTokenBudget(resources.Info.SessionTokenBudget),
+		twprojects.WithAllowedProjects(resources.Info.AllowedProjectIDs),
+		twprojects.WithFinance(!options.noFinance),
+		twprojects.WithCompany(options.companyID),
+		twprojects.WithPassthrough(resources.Info.Passthrough),
+		twprojects.WithCompaction(resources.Info.CompactResponses),
+		twprojects.WithDisplayTimezone(resources.DisplayTimezone()),
+		twprojects.WithExportMemoryLimit(resources.Info.ExportMemoryLimit),
+		twprojects.WithSLARules(resources.FileConfig().SLARules),
+		twprojects.WithRoleTemplates(resources.FileConfig().RoleTemplates),
+		twprojects.WithTextExtractors(textextract.Commands(resources.FileConfig().TextExtractors)),
+		twprojects.WithMacros(resources.FileConfig().Macros),
+		twprojects.WithTaskDefaults(resources.FileConfig().TaskDefaults),
+		twprojects.WithWriteLog(resources.WriteLog()),
+	)
+	deskGroup := twdesk.DefaultToolsetGroup(options.readOnly, resources.DeskClient())
+	groups := []*toolsets.ToolsetGroup{projectsGroup}
+	// the desk tools aren't restricted to the customers and tickets of a client
+	// company, so they aren't available in the sessions bound to one
+	if options.companyID == 0 {
+		groups = append(groups, deskGroup)
+	}
+
+	for _, method := range options.toolsets {
+		if method == toolsets.MethodAll {
+			for _, group := range groups {
+				if err := group.EnableToolsets(toolsets.MethodAll); err != nil {
+					return nil, fmt.Errorf("failed to enable toolsets: %w", err)
+				}
+			}
+			continue
+		}
+
+		var found bool
+		for _, group := range groups {
+			if _, err := group.GetToolset(method); err != nil {
+				continue
+			}
+			if err := group.EnableToolset(method); err != nil {
+				return nil, fmt.Errorf("failed to enable toolset %q: %w", method, err)
+			}
+			found = true
+		}
+		if !found {
+			if _, err := deskGroup.GetToolset(method); err == nil {
+				return nil, fmt.Errorf("sessions bound to a company can't use the desk toolsets: %w",
+					toolsets.NewToolsetDoesNotExistError(method))
+			}
+			return nil, toolsets.NewToolsetDoesNotExistError(method)
+		}
+	}
+
+	if resources.Info.Slack.WebhookURL != "" {
+		slack := notifier.NewSlack(resources.Info.Slack.WebhookURL)
+		for _, group := range groups {
+			notifier.Apply(group, slack, resources.Info.Slack.Tools, resources.Logger())
+		}
+	}
+
+	return config.NewMCPServer(resources, groups...), nil
+}
+
+// schedulerTools returns the tools available for scheduled jobs. Jobs run
+// unattended, so only read-only tools are allowed.
+func schedulerTools(resources config.Resources) []toolsets.ToolWrapper {
+	groups := []*toolsets.ToolsetGroup{
+		twprojects.DefaultToolsetGroup(true, false, resources.TeamworkEngine(),
+			twprojects.WithReportTemplates(resources.FileConfig().Reports),
+			twprojects.WithTokenBudget(resources.Info.SessionTokenBudget),
+			twprojects.WithAllowedProjects(resources.Info.AllowedProjectIDs),
+			twprojects.WithFinance(resources.Info.Finance),
+			twprojects.WithCompany(resources.Info.CompanyID),
+			twprojects.WithPassthrough(resources.Info.Passthrough),
+			twprojects.WithCompaction(resources.Info.CompactResponses),
+			twprojects.WithDisplayTimezone(resources.DisplayTimezone()),
+			twprojects.WithExportMemoryLimit(resources.Info.ExportMemoryLimit),
+			twprojects.WithSLARules(resources.FileConfig().SLARules),
+			twprojects.WithRoleTemplates(resources.FileConfig().RoleTemplates),
+			twprojects.WithTextExtractors(textextract.Commands(resources.FileConfig().TextExtractors)),
+			twprojects.WithMacros(resources.FileConfig().Macros),
+		),
+	}
+	// the desk tools aren't restricted to a client company
+	if resources.Info.CompanyID == 0 {
+		groups = append(groups, twdesk.DefaultToolsetGroup(true, resources.DeskClient()))
+	}
+
+	var tools []toolsets.ToolWrapper
+	for _, group := range groups {
+		for _, toolset := range group.Toolsets {
+			tools = append(tools, toolset.GetAvailableTools()...)
+		}
+	}
+	return tools
+}
+
+// webhookTools returns the tools available for webhooks. Webhooks convert
+// events into changes (e.g. creating tasks), so write tools are allowed, except
+// the delete tools.
+func webhookTools(resources config.Resources) []toolsets.ToolWrapper {
+	groups := []*toolsets.ToolsetGroup{
+		twprojects.DefaultToolsetGroup(false, false, resources.TeamworkEngine(),
+			twprojects.WithAllowedProjects(resources.Info.AllowedProjectIDs),
+			twprojects.WithFinance(resources.Info.Finance),
+			twprojects.WithCompany(resources.Info.CompanyID),
+			twprojects.WithMacros(resources.FileConfig().Macros),
+			twprojects.WithTaskDefaults(resources.FileConfig().TaskDefaults),
+		),
+	}
+	// the desk tools aren't restricted to a client company
+	if resources.Info.CompanyID == 0 {
+		groups = append(groups, twdesk.DefaultToolsetGroup(false, resources.DeskClient()))
+	}
+
+	var tools []toolsets.ToolWrapper
+	for _, group := range groups {
+		for _, toolset := range group.Toolsets {
+			tools = append(tools, toolset.GetAvailableTools()...)
+		}
+	}
+	return tools
 }
 
 func newRouter(resources config.Resources) *http.ServeMux {
@@ -141,8 +520,181 @@ func newRouter(resources config.Resources) *http.ServeMux {
 	return mux
 }
 
-func addRouterMiddlewares(resources config.Resources, mux *http.ServeMux) http.Handler {
-	return sentryMiddleware(resources, requestInfoMiddleware(tracerMiddleware(resources, authMiddleware(resources, mux))))
+// sessionOptionsMiddleware resolves the MCP server matching the capabilities
+// requested by the client.
+func sessionOptionsMiddleware(resources config.Resources, servers *mcpServerPool, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		options, err := newSessionOptions(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if isDemoSession(r.Context()) {
+			options.readOnly = true
+		}
+		// the clients can't enable the financial data hidden by the operator
+		if !resources.Info.Finance {
+			options.noFinance = true
+		}
+		// the company configured by the operator can't be changed by the client
+		if resources.Info.CompanyID > 0 {
+			options.companyID = resources.Info.CompanyID
+		}
+
+		mcpServer, err := servers.get(options)
+		if errors.Is(err, &toolsets.ToolsetDoesNotExistError{}) {
+			http.Error(w, fmt.Sprintf("invalid %s header: %s", headerToolsets, err), http.StatusBadRequest)
+			return
+		} else if err != nil {
+			resources.Logger().ErrorContext(r.Context(), "failed to create MCP server",
+				slog.String("toolsets", options.key()),
+				slog.String("error", err.Error()),
+			)
+			http.Error(w, "Failed to create MCP server", http.StatusInternalServerError)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), mcpServerKey{}, mcpServer)
+		// the session IDs of the stateless handler are chosen by the clients
+		ctx = config.WithStatelessTransport(ctx)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func addRouterMiddlewares(resources config.Resources, ipAllowlist []netip.Prefix, mux *http.ServeMux) http.Handler {
+	return sentryMiddleware(resources,
+		ipAllowlistMiddleware(ipAllowlist,
+			corsMiddleware(resources,
+				requestSizeMiddleware(resources,
+					requestInfoMiddleware(tracerMiddleware(resources, authMiddleware(resources, mux))),
+				),
+			),
+		),
+	)
+}
+
+// newIPAllowlist parses the allowed IP addresses and CIDR ranges. An empty
+// allowlist allows all clients.
+func newIPAllowlist(resources config.Resources) ([]netip.Prefix, error) {
+	ipAllowlist := make([]netip.Prefix, 0, len(resources.Info.IPAllowlist))
+	for _, entry := range resources.Info.IPAllowlist {
+		if strings.Contains(entry, "/") {
+			prefix, err := netip.ParsePrefix(entry)
+			if err != nil {
+				return nil, fmt.Errorf("invalid CIDR %q: %w", entry, err)
+			}
+			ipAllowlist = append(ipAllowlist, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid IP address %q: %w", entry, err)
+		}
+		ipAllowlist = append(ipAllowlist, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+	}
+	return ipAllowlist, nil
+}
+
+// ipAllowlistMiddleware rejects clients whose IP address is not in the
+// allowlist. The IP address is taken from the connection, as forwarded headers
+// can be spoofed by the client.
+func ipAllowlistMiddleware(ipAllowlist []netip.Prefix, next http.Handler) http.Handler {
+	if len(ipAllowlist) == 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		addrPort, err := netip.ParseAddrPort(r.RemoteAddr)
+		if err != nil {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		addr := addrPort.Addr().Unmap()
+		if !slices.ContainsFunc(ipAllowlist, func(prefix netip.Prefix) bool { return prefix.Contains(addr) }) {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// corsMiddleware adds the CORS headers for browser-based MCP clients. Preflight
+// requests are answered directly, as browsers don't send credentials on them.
+func corsMiddleware(resources config.Resources, next http.Handler) http.Handler {
+	cors := resources.Info.CORS
+	if len(cors.AllowedOrigins) == 0 {
+		return next
+	}
+	allowAnyOrigin := slices.Contains(cors.AllowedOrigins, "*")
+	allowedMethods := strings.Join([]string{http.MethodGet, http.MethodPost, http.MethodDelete, http.MethodOptions}, ", ")
+	allowedHeaders := strings.Join(cors.AllowedHeaders, ", ")
+	exposedHeaders := strings.Join([]string{"Mcp-Session-Id", "Mcp-Protocol-Version", "WWW-Authenticate"}, ", ")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Add("Vary", "Origin")
+
+		allowed := allowAnyOrigin || slices.ContainsFunc(cors.AllowedOrigins, func(allowedOrigin string) bool {
+			return strings.EqualFold(allowedOrigin, origin)
+		})
+		preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+
+		if !allowed {
+			if preflight {
+				http.Error(w, "Origin not allowed", http.StatusForbidden)
+				return
+			}
+			// the browser blocks the response without the CORS headers
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if allowAnyOrigin && !cors.AllowCredentials {
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+		} else {
+			// the wildcard is not accepted by browsers when credentials are allowed
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+		}
+		if cors.AllowCredentials {
+			w.Header().Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		if !preflight {
+			w.Header().Set("Access-Control-Expose-Headers", exposedHeaders)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Add("Vary", "Access-Control-Request-Method")
+		w.Header().Add("Vary", "Access-Control-Request-Headers")
+		w.Header().Set("Access-Control-Allow-Methods", allowedMethods)
+		w.Header().Set("Access-Control-Allow-Headers", allowedHeaders)
+		if cors.MaxAge > 0 {
+			w.Header().Set("Access-Control-Max-Age", strconv.Itoa(cors.MaxAge))
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// requestSizeMiddleware limits the size of the request body, which contains the
+// JSON-RPC message.
+func requestSizeMiddleware(resources config.Resources, next http.Handler) http.Handler {
+	maxRequestSize := resources.Info.MaxRequestSize
+	if maxRequestSize <= 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ContentLength > maxRequestSize {
+			http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, maxRequestSize)
+		next.ServeHTTP(w, r)
+	})
 }
 
 func sentryMiddleware(resources config.Resources, next http.Handler) http.Handler {
@@ -192,8 +744,13 @@ func authMiddleware(resources config.Resources, next http.Handler) http.Handler
 	whitelistPrefixEndpoints := map[string][]string{
 		// OAuth2 endpoints cannot require authentication
 		"/.well-known": {"GET", "OPTIONS"},
+		// webhooks are authenticated by their signature
+		"/webhooks/": {"POST"},
 	}
 
+	// signed JWTs are validated locally when a JWKS endpoint is configured
+	jwtValidator := auth.NewJWTValidator(resources)
+
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		requestLogger := resources.Logger().With(
 			slog.String("method", r.Method),
@@ -201,7 +758,9 @@ func authMiddleware(resources config.Resources, next http.Handler) http.Handler
 			slog.String("query", r.URL.RawQuery),
 		)
 
-		if r.Header.Get("Authorization") == "" {
+		authorization := r.Header.Get("Authorization")
+		var demo bool
+		if authorization == "" {
 			// some endpoints don't require auth
 
 			if methods, ok := whitelistEndpoints[r.URL.Path]; ok && slices.Contains(methods, r.Method) {
@@ -216,7 +775,11 @@ func authMiddleware(resources config.Resources, next http.Handler) http.Handler
 			}
 
 			content, err := io.ReadAll(r.Body)
-			if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+				return
+			} else if err != nil {
 				requestLogger.ErrorContext(r.Context(), "failed to read request body",
 					slog.String("error", err.Error()),
 				)
@@ -224,25 +787,37 @@ func authMiddleware(resources config.Resources, next http.Handler) http.Handler
 				return
 			}
 
+			r.Body = io.NopCloser(bytes.NewBuffer(content))
+
 			bypass, err := auth.Bypass(content)
 			switch {
-			case err != nil, !bypass:
-				http.Error(w, "Unauthorized", http.StatusUnauthorized)
-			default:
-				r.Body = io.NopCloser(bytes.NewBuffer(content))
+			case err == nil && bypass:
 				next.ServeHTTP(w, r)
+				return
+			case resources.Info.DemoBearerToken != "":
+				// unauthenticated sessions fall back to the demo installation
+				authorization = "Bearer " + resources.Info.DemoBearerToken
+				demo = true
+			default:
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
 			}
-			return
 		}
 
-		matches := reBearerToken.FindStringSubmatch(r.Header.Get("Authorization"))
+		matches := reBearerToken.FindStringSubmatch(authorization)
 		if len(matches) < 2 {
 			http.Error(w, "Unauthorized", http.StatusUnauthorized)
 			return
 		}
 		bearerToken := matches[1]
 
-		info, err := auth.GetBearerInfo(r.Context(), resources, bearerToken)
+		var info *auth.BearerInfo
+		var err error
+		if jwtValidator != nil && auth.IsJWT(bearerToken) {
+			info, err = jwtValidator.Validate(r.Context(), bearerToken)
+		} else {
+			info, err = auth.GetBearerInfo(r.Context(), resources, bearerToken)
+		}
 		if err == auth.ErrBearerInfoUnauthorized {
 			http.Error(w, "Unauthorized", http.StatusUnauthorized)
 			return
@@ -267,8 +842,14 @@ func authMiddleware(resources config.Resources, next http.Handler) http.Handler
 		ctx = config.WithCustomerURL(ctx, info.URL)
 		// inject scopes
 		ctx = config.WithScopes(ctx, info.Meta.Scopes)
+		// inject user
+		ctx = config.WithUserID(ctx, info.UserID)
 		// inject session
 		ctx = session.WithBearerTokenContext(ctx, session.NewBearerToken(bearerToken, info.URL))
+		// demo sessions are restricted to read-only tools
+		if demo {
+			ctx = context.WithValue(ctx, demoSessionKey{}, true)
+		}
 
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
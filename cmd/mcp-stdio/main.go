@@ -3,26 +3,41 @@ package main
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"flag"
 	"fmt"
 	"log/slog"
+	"net"
+	"net/http"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
-	"github.com/teamwork/mcp/internal/auth"
-	"github.com/teamwork/mcp/internal/config"
 	"github.com/teamwork/mcp/internal/toolsets"
-	"github.com/teamwork/mcp/internal/twdesk"
-	"github.com/teamwork/mcp/internal/twprojects"
-	"github.com/teamwork/twapi-go-sdk/session"
+	"github.com/teamwork/mcp/internal/twstub"
+	"github.com/teamwork/mcp/pkg/teamworkmcp"
 )
 
+// Transports supported by the server.
+const (
+	transportStdio = "stdio"
+	transportHTTP  = "http"
+	transportSSE   = "sse"
+)
+
+// shutdownTimeout is the maximum time waiting for the HTTP requests in flight
+// when stopping the server.
+const shutdownTimeout = 5 * time.Second
+
 var (
 	methods   = methodsInput([]toolsets.Method{toolsets.MethodAll})
 	readOnly  bool
 	logToFile string
+	transport string
+	address   string
+	mock      bool
 )
 
 func main() {
@@ -31,8 +46,22 @@ func main() {
 	flag.Var(&methods, "toolsets", "Comma-separated list of toolsets to enable")
 	flag.StringVar(&logToFile, "log-to-file", "", "Path to log file (if empty, logs to stderr)")
 	flag.BoolVar(&readOnly, "read-only", false, "Restrict the server to read-only operations")
+	flag.StringVar(&transport, "transport", transportStdio, "Transport to serve: stdio, http or sse")
+	flag.StringVar(&address, "address", "localhost:8080", "Address to listen on with the http and sse transports")
+	flag.BoolVar(&mock, "mock", false, "Serve the tools with an in-memory Teamwork API, without credentials")
 	flag.Parse()
 
+	if transport != transportStdio && transport != transportHTTP && transport != transportSSE {
+		fmt.Fprintf(os.Stderr, "invalid transport %q: use stdio, http or sse\n", transport)
+		exit(exitCodeSetupFailure)
+	}
+	// the network transports serve the requests with the credentials of the
+	// server, so the clients must authenticate with a shared token
+	if transport != transportStdio && os.Getenv("TW_MCP_HTTP_TOKEN") == "" {
+		fmt.Fprintf(os.Stderr, "the %s transport requires the TW_MCP_HTTP_TOKEN environment variable\n", transport)
+		exit(exitCodeSetupFailure)
+	}
+
 	f := os.Stderr
 	if logToFile != "" {
 		var err error
@@ -44,59 +73,106 @@ func main() {
 	}
 
 	defer f.Close() //nolint:errcheck
-	resources, teardown := config.Load(f)
-	defer teardown()
 
 	ctx := context.Background()
 
-	var authenticated bool
-	if resources.Info.BearerToken != "" {
-		// detect the installation from the bearer token
-		if info, err := auth.GetBearerInfo(ctx, resources, resources.Info.BearerToken); err != nil {
-			resources.Logger().Error("failed to get bearer info",
-				slog.String("error", err.Error()),
-			)
-		} else {
-			authenticated = true
-			// inject customer URL in the context
-			ctx = config.WithCustomerURL(ctx, info.URL)
-			// inject bearer token in the context
-			ctx = session.WithBearerTokenContext(ctx, session.NewBearerToken(resources.Info.BearerToken, info.URL))
+	opts := []teamworkmcp.Option{
+		teamworkmcp.WithToolsets(methods.names()...),
+		teamworkmcp.WithReadOnly(readOnly),
+		teamworkmcp.WithLogOutput(f),
+	}
+	if mock {
+		apiURL, stop, err := serveMock()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to serve the mock API: %s\n", err)
+			exit(exitCodeSetupFailure)
+		}
+		defer stop()
+
+		// the server reads the API URL from the environment, and any bearer
+		// token is accepted by the mock API
+		if err := os.Setenv("TW_MCP_API_URL", apiURL); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to set the mock API URL: %s\n", err)
+			exit(exitCodeSetupFailure)
 		}
+		opts = append(opts, teamworkmcp.WithBearerToken("mock"))
 	}
 
-	mcpServer, err := newMCPServer(resources)
+	server, err := teamworkmcp.New(ctx, opts...)
 	if err != nil {
-		mcpError(resources.Logger(), fmt.Errorf("failed to create MCP server: %s", err), jsonRPCErrorCodeInternalError)
+		mcpError(slog.New(slog.NewTextHandler(f, nil)), fmt.Errorf("failed to create MCP server: %s", err),
+			jsonRPCErrorCodeInternalError)
 		exit(exitCodeSetupFailure)
 	}
-	mcpServer.AddReceivingMiddleware(func(next mcp.MethodHandler) mcp.MethodHandler {
-		return func(ctx context.Context, method string, req mcp.Request) (result mcp.Result, err error) {
-			if !authenticated && !auth.BypassMethod(method) {
-				return nil, errors.New("not authenticated")
-			}
-			return next(ctx, method, req)
+	defer server.Close()
+
+	if transport != transportStdio {
+		if err := serveHTTP(ctx, server); err != nil {
+			server.Logger().Error("failed to serve", slog.String("error", err.Error()))
+			exit(exitCodeSetupFailure)
 		}
-	})
+		return
+	}
 
-	if err := mcpServer.Run(ctx, &mcp.StdioTransport{}); err != nil {
-		mcpError(resources.Logger(), fmt.Errorf("failed to serve: %s", err), jsonRPCErrorCodeInternalError)
+	// the stdio transport frames the JSON-RPC messages (one per line, buffering
+	// partial reads), so the authentication check applies to each decoded
+	// message, regardless of how the input is split
+	if err := server.Run(ctx, &mcp.StdioTransport{}); err != nil {
+		mcpError(server.Logger(), fmt.Errorf("failed to serve: %s", err), jsonRPCErrorCodeInternalError)
 		exit(exitCodeSetupFailure)
 	}
 }
 
-func newMCPServer(resources config.Resources) (*mcp.Server, error) {
-	projectsGroup := twprojects.DefaultToolsetGroup(readOnly, false, resources.TeamworkEngine())
-	if err := projectsGroup.EnableToolsets(methods...); err != nil {
-		return nil, fmt.Errorf("failed to enable projects toolsets: %w", err)
+// serveHTTP serves the server over the http or sse transport until an
+// interrupt signal is received.
+func serveHTTP(ctx context.Context, server *teamworkmcp.Server) error {
+	handler := server.HTTPHandler()
+	if transport == transportSSE {
+		handler = server.SSEHandler()
 	}
+	httpServer := &http.Server{
+		Addr:              address,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errs := make(chan error, 1)
+	go func() {
+		server.Logger().Info("starting http server",
+			slog.String("address", address),
+			slog.String("transport", transport),
+		)
+		errs <- httpServer.ListenAndServe()
+	}()
 
-	deskGroup := twdesk.DefaultToolsetGroup(resources.DeskClient())
-	if err := deskGroup.EnableToolsets(methods...); err != nil {
-		return nil, fmt.Errorf("failed to enable desk toolsets: %w", err)
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
 	}
 
-	return config.NewMCPServer(resources, projectsGroup, deskGroup), nil
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shutdownTimeout)
+	defer cancel()
+	return httpServer.Shutdown(shutdownCtx)
+}
+
+// serveMock serves the in-memory Teamwork API on a random local port, so the
+// tools can be tried without credentials. It returns the URL of the API and a
+// function stopping it.
+func serveMock() (string, func(), error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", nil, err
+	}
+	mockServer := &http.Server{
+		Handler:           twstub.New(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go mockServer.Serve(listener) //nolint:errcheck
+	return "http://" + listener.Addr().String(), func() { _ = mockServer.Close() }, nil
 }
 
 func mcpError(logger *slog.Logger, err error, code jsonRPCErrorCode) {
@@ -116,11 +192,15 @@ func mcpError(logger *slog.Logger, err error, code jsonRPCErrorCode) {
 type methodsInput []toolsets.Method
 
 func (t methodsInput) String() string {
+	return strings.Join(t.names(), ", ")
+}
+
+func (t methodsInput) names() []string {
 	methods := make([]string, len(t))
 	for i, m := range t {
 		methods[i] = m.String()
 	}
-	return strings.Join(methods, ", ")
+	return methods
 }
 
 func (t *methodsInput) Set(value string) error {
@@ -129,15 +209,12 @@ func (t *methodsInput) Set(value string) error {
 	}
 	*t = (*t)[:0] // reset slice
 
-	var errs error
+	// the methods are validated when creating the server, as custom toolsets
+	// are only registered then
 	for methodString := range strings.SplitSeq(value, ",") {
-		if method := toolsets.Method(strings.TrimSpace(methodString)); method.IsRegistered() {
-			*t = append(*t, method)
-		} else {
-			errs = errors.Join(errs, fmt.Errorf("invalid toolset method: %q", methodString))
-		}
+		*t = append(*t, toolsets.Method(strings.TrimSpace(methodString)))
 	}
-	return errs
+	return nil
 }
 
 type jsonRPCErrorCode int64
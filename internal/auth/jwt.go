@@ -0,0 +1,285 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/teamwork/mcp/internal/config"
+)
+
+const (
+	// jwksRefreshInterval is the maximum time the signing keys are cached
+	// before being fetched again.
+	jwksRefreshInterval = time.Hour
+	// jwksMinRefreshInterval protects the JWKS endpoint from being flooded when
+	// tokens with unknown key IDs are received.
+	jwksMinRefreshInterval = time.Minute
+)
+
+// JWTValidator validates signed JWTs locally using the keys published in a
+// JWKS endpoint, avoiding a round-trip to Teamwork API for each session.
+type JWTValidator struct {
+	resources config.Resources
+	parser    *jwt.Parser
+
+	mutex     sync.Mutex
+	keys      map[string]any
+	fetchedAt time.Time
+}
+
+// NewJWTValidator creates a new JWTValidator using the JWT configuration from
+// the resources. It returns nil if no JWKS URL is configured, which means that
+// only opaque bearer tokens are supported.
+func NewJWTValidator(resources config.Resources) *JWTValidator {
+	if resources.Info.JWT.JWKSURL == "" {
+		return nil
+	}
+
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(30 * time.Second),
+	}
+	if resources.Info.JWT.Issuer != "" {
+		options = append(options, jwt.WithIssuer(resources.Info.JWT.Issuer))
+	}
+	if resources.Info.JWT.Audience != "" {
+		options = append(options, jwt.WithAudience(resources.Info.JWT.Audience))
+	}
+
+	return &JWTValidator{
+		resources: resources,
+		parser:    jwt.NewParser(options...),
+	}
+}
+
+// IsJWT checks if the token has the JWT compact serialization format. Opaque
+// bearer tokens should still be resolved with GetBearerInfo.
+func IsJWT(token string) bool {
+	return strings.Count(token, ".") == 2
+}
+
+// jwtClaims contains the claims expected in the JWT. The custom claims mirror
+// the fields returned by Teamwork API when resolving an opaque bearer token.
+type jwtClaims struct {
+	jwt.RegisteredClaims
+
+	UserID         int64    `json:"user_id"`
+	InstallationID int64    `json:"installation_id"`
+	Region         string   `json:"awsRegion"`
+	URL            string   `json:"url"`
+	Scope          string   `json:"scope"`
+	Scopes         []string `json:"scopes"`
+}
+
+// Validate checks the signature and the standard claims of the JWT, and
+// converts it to a BearerInfo. If the token is invalid, it returns
+// ErrBearerInfoUnauthorized.
+func (v *JWTValidator) Validate(ctx context.Context, token string) (*BearerInfo, error) {
+	var claims jwtClaims
+	_, err := v.parser.ParseWithClaims(token, &claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		return v.key(ctx, kid)
+	})
+	if err != nil {
+		v.resources.Logger().DebugContext(ctx, "invalid jwt",
+			slog.String("error", err.Error()),
+		)
+		return nil, ErrBearerInfoUnauthorized
+	}
+
+	if claims.UserID == 0 && claims.Subject != "" {
+		if claims.UserID, err = strconv.ParseInt(claims.Subject, 10, 64); err != nil {
+			return nil, ErrBearerInfoUnauthorized
+		}
+	}
+	if claims.UserID == 0 || claims.URL == "" {
+		return nil, ErrBearerInfoUnauthorized
+	}
+
+	info := BearerInfo{
+		UserID:         claims.UserID,
+		InstallationID: claims.InstallationID,
+		Region:         claims.Region,
+		URL:            claims.URL,
+	}
+	info.Meta.Scopes = claims.Scopes
+	if len(info.Meta.Scopes) == 0 && claims.Scope != "" {
+		info.Meta.Scopes = strings.Fields(claims.Scope)
+	}
+	return &info, nil
+}
+
+// key returns the public key for the given key ID. The keys are refreshed
+// periodically or when the key ID is unknown, to support key rotation.
+func (v *JWTValidator) key(ctx context.Context, kid string) (any, error) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	key, ok := v.lookup(kid)
+	if ok && time.Since(v.fetchedAt) < jwksRefreshInterval {
+		return key, nil
+	}
+	if !ok && time.Since(v.fetchedAt) < jwksMinRefreshInterval {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+
+	keys, err := v.fetchKeys(ctx)
+	if err != nil {
+		if ok {
+			// keep using the cached key when the JWKS endpoint is unavailable
+			v.resources.Logger().WarnContext(ctx, "failed to refresh jwks",
+				slog.String("error", err.Error()),
+			)
+			return key, nil
+		}
+		return nil, err
+	}
+	v.keys = keys
+	v.fetchedAt = time.Now()
+
+	if key, ok = v.lookup(kid); !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	return key, nil
+}
+
+func (v *JWTValidator) lookup(kid string) (any, bool) {
+	if key, ok := v.keys[kid]; ok {
+		return key, true
+	}
+	// tokens without key ID are accepted when there's a single key
+	if kid == "" && len(v.keys) == 1 {
+		for _, key := range v.keys {
+			return key, true
+		}
+	}
+	return nil, false
+}
+
+type jsonWebKey struct {
+	KeyID   string `json:"kid"`
+	KeyType string `json:"kty"`
+	Use     string `json:"use"`
+	N       string `json:"n"`
+	E       string `json:"e"`
+	Curve   string `json:"crv"`
+	X       string `json:"x"`
+	Y       string `json:"y"`
+}
+
+func (v *JWTValidator) fetchKeys(ctx context.Context) (map[string]any, error) {
+	jwksRequest, err := http.NewRequestWithContext(ctx, http.MethodGet, v.resources.Info.JWT.JWKSURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create jwks request: %w", err)
+	}
+
+	response, err := v.resources.TeamworkHTTPClient().Do(jwksRequest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to perform jwks request: %w", err)
+	}
+	defer func() {
+		if err := response.Body.Close(); err != nil {
+			v.resources.Logger().ErrorContext(ctx, "failed to close jwks response body",
+				slog.String("error", err.Error()),
+			)
+		}
+	}()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected jwks response status: %d", response.StatusCode)
+	}
+
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	decoder := json.NewDecoder(response.Body)
+	if err := decoder.Decode(&jwks); err != nil {
+		return nil, fmt.Errorf("failed to decode jwks response: %w", err)
+	}
+
+	keys := make(map[string]any, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			v.resources.Logger().WarnContext(ctx, "ignoring invalid jwk",
+				slog.String("kid", jwk.KeyID),
+				slog.String("error", err.Error()),
+			)
+			continue
+		}
+		keys[jwk.KeyID] = key
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("jwks does not contain any signing key")
+	}
+	return keys, nil
+}
+
+func (k jsonWebKey) publicKey() (any, error) {
+	switch k.KeyType {
+	case "RSA":
+		n, err := decodeBase64URLInt(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid modulus: %w", err)
+		}
+		e, err := decodeBase64URLInt(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exponent: %w", err)
+		}
+		if !e.IsInt64() || e.Int64() > int64(^uint32(0)>>1) {
+			return nil, errors.New("exponent too large")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Curve {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Curve)
+		}
+		x, err := decodeBase64URLInt(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid x coordinate: %w", err)
+		}
+		y, err := decodeBase64URLInt(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("invalid y coordinate: %w", err)
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.KeyType)
+	}
+}
+
+func decodeBase64URLInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(data), nil
+}
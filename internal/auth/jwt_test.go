@@ -0,0 +1,323 @@
+package auth_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/teamwork/mcp/internal/auth"
+	"github.com/teamwork/mcp/internal/config"
+)
+
+const (
+	jwtTestIssuer   = "https://auth.example.com"
+	jwtTestAudience = "teamwork-mcp"
+)
+
+func TestJWTValidatorValidate(t *testing.T) {
+	rsaKey := generateRSAKey(t)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate EC key: %v", err)
+	}
+	otherKey := generateRSAKey(t)
+
+	jwks := &jwksHandler{}
+	jwks.setKeys(rsaJWK("rsa-key", &rsaKey.PublicKey), ecJWK("ec-key", &ecKey.PublicKey))
+	server := httptest.NewServer(jwks)
+	t.Cleanup(server.Close)
+
+	validClaims := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":             jwtTestIssuer,
+			"aud":             jwtTestAudience,
+			"exp":             time.Now().Add(time.Hour).Unix(),
+			"sub":             "123",
+			"installation_id": 456,
+			"url":             "https://example.teamwork.com",
+			"scope":           "projects desk",
+		}
+	}
+	withClaim := func(name string, value any) jwt.MapClaims {
+		claims := validClaims()
+		if value == nil {
+			delete(claims, name)
+		} else {
+			claims[name] = value
+		}
+		return claims
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		isError bool
+	}{{
+		name:  "RSA signature",
+		token: signJWT(t, jwt.SigningMethodRS256, "rsa-key", rsaKey, validClaims()),
+	}, {
+		name:  "EC signature",
+		token: signJWT(t, jwt.SigningMethodES256, "ec-key", ecKey, validClaims()),
+	}, {
+		name:    "signature of another key",
+		token:   signJWT(t, jwt.SigningMethodRS256, "rsa-key", otherKey, validClaims()),
+		isError: true,
+	}, {
+		name:    "tampered payload",
+		token:   tamperJWT(t, signJWT(t, jwt.SigningMethodRS256, "rsa-key", rsaKey, validClaims())),
+		isError: true,
+	}, {
+		name:    "symmetric signature",
+		token:   signJWT(t, jwt.SigningMethodHS256, "rsa-key", []byte("secret"), validClaims()),
+		isError: true,
+	}, {
+		name:    "unknown key ID",
+		token:   signJWT(t, jwt.SigningMethodRS256, "other-key", rsaKey, validClaims()),
+		isError: true,
+	}, {
+		name:    "issuer mismatch",
+		token:   signJWT(t, jwt.SigningMethodRS256, "rsa-key", rsaKey, withClaim("iss", "https://evil.example.com")),
+		isError: true,
+	}, {
+		name:    "audience mismatch",
+		token:   signJWT(t, jwt.SigningMethodRS256, "rsa-key", rsaKey, withClaim("aud", "other-service")),
+		isError: true,
+	}, {
+		name: "expired",
+		token: signJWT(t, jwt.SigningMethodRS256, "rsa-key", rsaKey,
+			withClaim("exp", time.Now().Add(-time.Hour).Unix())),
+		isError: true,
+	}, {
+		name:    "without expiration",
+		token:   signJWT(t, jwt.SigningMethodRS256, "rsa-key", rsaKey, withClaim("exp", nil)),
+		isError: true,
+	}, {
+		name:    "without installation URL",
+		token:   signJWT(t, jwt.SigningMethodRS256, "rsa-key", rsaKey, withClaim("url", nil)),
+		isError: true,
+	}}
+
+	validator, _ := newJWTValidator(t, server.URL, jwtTestIssuer, jwtTestAudience)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := validator.Validate(t.Context(), tt.token)
+			if tt.isError {
+				if !errors.Is(err, auth.ErrBearerInfoUnauthorized) {
+					t.Errorf("expected unauthorized error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if info.UserID != 123 || info.InstallationID != 456 || info.URL != "https://example.teamwork.com" {
+				t.Errorf("unexpected bearer info: %+v", info)
+			}
+			if len(info.Meta.Scopes) != 2 {
+				t.Errorf("expected the scopes of the token, got %v", info.Meta.Scopes)
+			}
+		})
+	}
+}
+
+func TestJWTValidatorKeyRotation(t *testing.T) {
+	oldKey := generateRSAKey(t)
+	newKey := generateRSAKey(t)
+
+	jwks := &jwksHandler{}
+	jwks.setKeys(rsaJWK("old-key", &oldKey.PublicKey))
+	validator, client := newJWTValidator(t, "https://auth.example.com/jwks.json", "", "")
+	// the fake clock of the test only advances without network I/O, so the JWKS
+	// is served in memory
+	client.Transport = jwks
+
+	synctest.Test(t, func(t *testing.T) {
+		claims := func() jwt.MapClaims {
+			return jwt.MapClaims{
+				"exp": time.Now().Add(24 * time.Hour).Unix(),
+				"sub": "123",
+				"url": "https://example.teamwork.com",
+			}
+		}
+		validate := func(key *rsa.PrivateKey, kid string) error {
+			_, err := validator.Validate(t.Context(), signJWT(t, jwt.SigningMethodRS256, kid, key, claims()))
+			return err
+		}
+
+		if err := validate(oldKey, "old-key"); err != nil {
+			t.Fatalf("unexpected error with the old key: %v", err)
+		}
+
+		// the keys are rotated, but the JWKS isn't fetched again right away for
+		// unknown key IDs
+		jwks.setKeys(rsaJWK("new-key", &newKey.PublicKey))
+		if err := validate(newKey, "new-key"); !errors.Is(err, auth.ErrBearerInfoUnauthorized) {
+			t.Errorf("expected unauthorized error before the refresh interval, got %v", err)
+		}
+		if err := validate(oldKey, "old-key"); err != nil {
+			t.Errorf("expected the cached old key to be valid, got %v", err)
+		}
+
+		time.Sleep(2 * time.Minute)
+		if err := validate(newKey, "new-key"); err != nil {
+			t.Errorf("unexpected error with the new key: %v", err)
+		}
+		requests := jwks.requestCount()
+
+		// the cached key is used while the JWKS endpoint is unavailable
+		jwks.setUnavailable()
+		time.Sleep(2 * time.Hour)
+		if err := validate(newKey, "new-key"); err != nil {
+			t.Errorf("expected the cached new key to be valid, got %v", err)
+		}
+		if jwks.requestCount() != requests+1 {
+			t.Errorf("expected the JWKS to be refreshed after the cache expired")
+		}
+
+		// the old key was removed from the JWKS
+		if err := validate(oldKey, "old-key"); !errors.Is(err, auth.ErrBearerInfoUnauthorized) {
+			t.Errorf("expected unauthorized error with the rotated key, got %v", err)
+		}
+	})
+}
+
+// jwksHandler serves the JSON Web Key Set of the tests.
+type jwksHandler struct {
+	mutex       sync.Mutex
+	keys        []map[string]string
+	unavailable bool
+	requests    int
+}
+
+func (s *jwksHandler) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.requests++
+	if s.unavailable {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{"keys": s.keys})
+}
+
+// RoundTrip serves the requests without network I/O.
+func (s *jwksHandler) RoundTrip(r *http.Request) (*http.Response, error) {
+	recorder := httptest.NewRecorder()
+	s.ServeHTTP(recorder, r)
+	return recorder.Result(), nil
+}
+
+func (s *jwksHandler) setKeys(keys ...map[string]string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.keys = keys
+}
+
+func (s *jwksHandler) setUnavailable() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.unavailable = true
+}
+
+func (s *jwksHandler) requestCount() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.requests
+}
+
+// newJWTValidator creates a validator fetching the keys from the JWKS URL. The
+// HTTP client of the validator is returned, so the transport can be replaced.
+func newJWTValidator(t *testing.T, jwksURL, issuer, audience string) (*auth.JWTValidator, *http.Client) {
+	t.Helper()
+
+	t.Setenv("TW_MCP_JWT_JWKS_URL", jwksURL)
+	t.Setenv("TW_MCP_JWT_ISSUER", issuer)
+	t.Setenv("TW_MCP_JWT_AUDIENCE", audience)
+	resources, teardown, err := config.Load(io.Discard)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	t.Cleanup(teardown)
+
+	validator := auth.NewJWTValidator(resources)
+	if validator == nil {
+		t.Fatal("expected a validator")
+	}
+	return validator, resources.TeamworkHTTPClient()
+}
+
+func generateRSAKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate RSA key: %v", err)
+	}
+	return key
+}
+
+func rsaJWK(kid string, key *rsa.PublicKey) map[string]string {
+	return map[string]string{
+		"kid": kid,
+		"kty": "RSA",
+		"use": "sig",
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+func ecJWK(kid string, key *ecdsa.PublicKey) map[string]string {
+	return map[string]string{
+		"kid": kid,
+		"kty": "EC",
+		"crv": key.Curve.Params().Name,
+		"x":   base64.RawURLEncoding.EncodeToString(key.X.Bytes()),
+		"y":   base64.RawURLEncoding.EncodeToString(key.Y.Bytes()),
+	}
+}
+
+func signJWT(t *testing.T, method jwt.SigningMethod, kid string, key any, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return signed
+}
+
+// tamperJWT changes the payload of the token, keeping the signature.
+func tamperJWT(t *testing.T, token string) string {
+	t.Helper()
+
+	parser := jwt.NewParser()
+	claims := jwt.MapClaims{}
+	_, parts, err := parser.ParseUnverified(token, claims)
+	if err != nil {
+		t.Fatalf("failed to parse token: %v", err)
+	}
+	claims["sub"] = "999"
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatalf("failed to encode claims: %v", err)
+	}
+	parts[1] = base64.RawURLEncoding.EncodeToString(payload)
+	return parts[0] + "." + parts[1] + "." + parts[2]
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"maps"
 	"net/http"
 	"net/url"
 	"slices"
@@ -17,8 +18,10 @@ import (
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
 	"github.com/DataDog/dd-trace-go/v2/instrumentation/httptrace"
 	"github.com/getsentry/sentry-go"
+	"github.com/google/jsonschema-go/jsonschema"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	desksdk "github.com/teamwork/desksdkgo/client"
+	"github.com/teamwork/mcp/internal/i18n"
 	"github.com/teamwork/mcp/internal/network"
 	"github.com/teamwork/mcp/internal/request"
 	"github.com/teamwork/mcp/internal/toolsets"
@@ -31,12 +34,38 @@ const (
 	sentryFlushTimeout = 2 * time.Second
 )
 
-// Load loads the configuration for the MCP service.
-func Load(logOutput io.Writer) (Resources, func()) {
-	resources := newResources()
+// Load loads the configuration for the MCP service. It returns an error when an
+// environment variable or the configuration file is invalid, so the service
+// doesn't start with a setting silently disabled. The logger is available even
+// then, to report the error.
+func Load(logOutput io.Writer) (Resources, func(), error) {
+	resources, err := newResources()
 	resources.logger = slog.New(newCustomLogHandler(resources, logOutput))
+	if err != nil {
+		return resources, func() {}, err
+	}
 	resources.teamworkHTTPClient = new(http.Client)
 
+	if resources.Info.ConfigFile != "" {
+		// the file configures the redaction, so the service must not start
+		// without it
+		fileConfig, err := loadFileConfig(resources.Info.ConfigFile)
+		if err != nil {
+			return resources, func() {}, fmt.Errorf("failed to load config file %s: %w", resources.Info.ConfigFile, err)
+		}
+		resources.fileConfig = fileConfig
+	}
+
+	localizer, err := i18n.NewLocalizer(resources.Info.Language, resources.fileConfig.Translations)
+	if err != nil {
+		resources.logger.Error("failed to load translations",
+			slog.String("language", resources.Info.Language),
+			slog.String("error", err.Error()),
+		)
+	} else {
+		resources.localizer = localizer
+	}
+
 	var haProxyURL *url.URL
 	if resources.Info.HAProxyURL != "" {
 		var err error
@@ -79,13 +108,19 @@ func Load(logOutput io.Writer) (Resources, func()) {
 		)
 	}
 
+	// Limit the concurrent requests per installation, respecting the API quotas
+	resources.teamworkHTTPClient.Transport = network.NewConcurrencyLimiter(
+		resources.Info.MaxConcurrentRequests,
+		resources.teamworkHTTPClient.Transport,
+	)
+
 	// Allow logging HTTP requests
 	resources.teamworkHTTPClient.Transport = network.NewLoggingRoundTripper(
 		resources.logger,
 		resources.teamworkHTTPClient.Transport,
 	)
 
-	resources.teamworkEngine = twapi.NewEngine(session.NewBearerTokenContext(),
+	engineOptions := []twapi.EngineOption{
 		twapi.WithHTTPClient(resources.teamworkHTTPClient),
 		twapi.WithMiddleware(func(next twapi.HTTPClient) twapi.HTTPClient {
 			return twapi.HTTPClientFunc(func(req *http.Request) (*http.Response, error) {
@@ -103,6 +138,8 @@ func Load(logOutput io.Writer) (Resources, func()) {
 			return twapi.HTTPClientFunc(func(req *http.Request) (*http.Response, error) {
 				// add proxy headers
 				request.SetProxyHeaders(req)
+				// add the correlation ID of the tool call
+				request.SetCorrelationHeader(req)
 				// add user agent
 				req.Header.Set("User-Agent", "Teamwork MCP/"+resources.Info.Version)
 				return next.Do(req)
@@ -120,7 +157,13 @@ func Load(logOutput io.Writer) (Resources, func()) {
 			})
 		}),
 		twapi.WithLogger(resources.logger),
-	)
+	}
+
+	// Allow auditing duplicated write requests
+	if resources.writeLog = NewWriteLog(resources.Info.WriteLogSize); resources.writeLog != nil {
+		engineOptions = append(engineOptions, twapi.WithMiddleware(resources.writeLog.Middleware))
+	}
+	resources.teamworkEngine = twapi.NewEngine(session.NewBearerTokenContext(), engineOptions...)
 
 	resources.deskClient = desksdk.NewClient(
 		resources.Info.APIURL+"/desk/api/v2",
@@ -139,6 +182,7 @@ func Load(logOutput io.Writer) (Resources, func()) {
 				}
 
 				request.SetProxyHeaders(req)
+				request.SetCorrelationHeader(req)
 				req.Header.Set("User-Agent", "Teamwork MCP/"+resources.Info.Version)
 				return next(ctx, req)
 			}),
@@ -159,12 +203,21 @@ func Load(logOutput io.Writer) (Resources, func()) {
 		if resources.Info.Log.SentryDSN != "" {
 			sentry.Flush(sentryFlushTimeout)
 		}
-	}
+	}, nil
 }
 
 // NewMCPServer creates a new MCP server with the given resources and toolset
 // group.
 func NewMCPServer(resources Resources, groups ...*toolsets.ToolsetGroup) *mcp.Server {
+	completers := make(map[string]toolsets.ArgumentCompleter)
+	for _, group := range groups {
+		maps.Copy(completers, group.GetActiveCompleters())
+	}
+
+	// the catalog version only covers the Teamwork tools
+	info := newServerInfo(resources, groups...)
+	groups = append(slices.Clip(groups), serverToolsetGroup(info, groups...))
+
 	// Determine if any group has tools
 	hasTools := false
 	for _, group := range groups {
@@ -174,13 +227,28 @@ func NewMCPServer(resources Resources, groups ...*toolsets.ToolsetGroup) *mcp.Se
 		}
 	}
 
+	serverOptions := &mcp.ServerOptions{
+		HasTools: hasTools,
+	}
+	if len(completers) > 0 {
+		serverOptions.CompletionHandler = completionHandler(completers)
+	}
+	if slices.ContainsFunc(groups, (*toolsets.ToolsetGroup).HasResourceSubscriptions) {
+		serverOptions.SubscribeHandler = subscribeHandler(groups)
+		serverOptions.UnsubscribeHandler = func(context.Context, *mcp.UnsubscribeRequest) error {
+			return nil
+		}
+	}
+
 	mcpServer := mcp.NewServer(&mcp.Implementation{
 		Name:    mcpName,
 		Title:   "Teamwork.com Model Context Protocol",
 		Version: strings.TrimPrefix(resources.Info.Version, "v"),
-	}, &mcp.ServerOptions{
-		HasTools: hasTools,
-	})
+	}, serverOptions)
+	mcpServer.AddReceivingMiddleware(serverInfoMiddleware(info))
+	// the same resource URIs reference different entities in each installation
+	mcpServer.AddReceivingMiddleware(resourceScopeMiddleware)
+	mcpServer.AddSendingMiddleware(resourceUnscopeMiddleware)
 	mcpServer.AddSendingMiddleware(func(next mcp.MethodHandler) mcp.MethodHandler {
 		return func(ctx context.Context, method string, req mcp.Request) (result mcp.Result, err error) {
 			result, err = next(ctx, method, req)
@@ -210,14 +278,177 @@ func NewMCPServer(resources Resources, groups ...*toolsets.ToolsetGroup) *mcp.Se
 		}
 	})
 
+	// adapt the results to the protocol version negotiated at initialize, so
+	// older clients aren't sent content types they can't parse
+	mcpServer.AddReceivingMiddleware(func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (result mcp.Result, err error) {
+			result, err = next(ctx, method, req)
+			if err != nil {
+				return result, err
+			}
+			session, ok := req.GetSession().(*mcp.ServerSession)
+			if !ok || session == nil {
+				return result, nil
+			}
+			features := NegotiatedFeatures(session.InitializeParams())
+			switch result := result.(type) {
+			case *mcp.CallToolResult:
+				return AdaptToolResult(result, features), nil
+			case *mcp.ListToolsResult:
+				if result != nil {
+					result.Tools = AdaptTools(result.Tools, features)
+				}
+			}
+			return result, nil
+		}
+	})
+
+	// the tool list only changes when a toolset is enabled or disabled at
+	// runtime, so it is cached until then
+	toolListCache := newToolListCache()
+	mcpServer.AddReceivingMiddleware(toolListCache.middleware)
+	for _, group := range groups {
+		group.OnChange(toolListCache.invalidate)
+	}
+
+	middlewares := []toolsets.ToolMiddleware{
+		toolCorrelationMiddleware(),
+		toolLoggingMiddleware(resources.logger),
+		toolThrottleMiddleware(resources.logger),
+	}
+	if tools := resources.fileConfig.Tools; len(tools) > 0 {
+		middlewares = append(middlewares, toolSettingsMiddleware(tools, readOnlyTools(groups), resources.logger))
+	}
+	if resources.Info.DatadogAPM.Enabled {
+		middlewares = append(middlewares, toolTracingMiddleware())
+	}
+	// the resources are rewritten like the tool results
+	var resourceRewrites []func(data []byte) ([]byte, bool)
+	if redaction := resources.fileConfig.Redaction; redaction.Enabled() {
+		middlewares = append(middlewares, ToolRedactionMiddleware(redaction))
+		resourceRewrites = append(resourceRewrites, Redactor(redaction))
+	}
+	if resources.Info.StringIDs {
+		middlewares = append(middlewares, toolStringIDsMiddleware())
+		resourceRewrites = append(resourceRewrites, stringifyIDs)
+	}
+	if len(resourceRewrites) > 0 {
+		mcpServer.AddReceivingMiddleware(resourceContentsMiddleware(resourceRewrites...))
+	}
+
 	// Register all toolset groups
 	for _, group := range groups {
+		if localizer := resources.Localizer(); localizer != nil {
+			group.WrapTools(func(tool toolsets.ToolWrapper) toolsets.ToolWrapper {
+				return localizeTool(localizer, tool)
+			})
+		}
+		if resources.Info.StringIDs {
+			group.WrapTools(stringIDsTool)
+		}
+		group.Use(middlewares...)
 		group.RegisterAll(mcpServer)
 	}
 
 	return mcpServer
 }
 
+// readOnlyTools returns the names of the tools annotated as read-only in the
+// toolset groups.
+func readOnlyTools(groups []*toolsets.ToolsetGroup) map[string]bool {
+	names := make(map[string]bool)
+	for _, group := range groups {
+		for _, toolset := range group.Toolsets {
+			for _, tool := range toolset.GetAvailableTools() {
+				if tool.Tool.Annotations != nil && tool.Tool.Annotations.ReadOnlyHint {
+					names[tool.Tool.Name] = true
+				}
+			}
+		}
+	}
+	return names
+}
+
+// completionMaxValues is the maximum number of values of a completion response,
+// as defined by the MCP specification.
+const completionMaxValues = 100
+
+// completionHandler answers the completion requests with the completer of the
+// argument. Arguments without a completer have no suggestions.
+func completionHandler(
+	completers map[string]toolsets.ArgumentCompleter,
+) func(context.Context, *mcp.CompleteRequest) (*mcp.CompleteResult, error) {
+	return func(ctx context.Context, req *mcp.CompleteRequest) (*mcp.CompleteResult, error) {
+		result := &mcp.CompleteResult{
+			Completion: mcp.CompletionResultDetails{
+				Values: []string{},
+			},
+		}
+		completer, ok := completers[req.Params.Argument.Name]
+		if !ok {
+			return result, nil
+		}
+
+		var arguments map[string]string
+		if req.Params.Context != nil {
+			arguments = req.Params.Context.Arguments
+		}
+		values, err := completer(ctx, req.Params.Argument.Value, arguments)
+		if err != nil {
+			return nil, fmt.Errorf("failed to complete argument %q: %w", req.Params.Argument.Name, err)
+		}
+		if len(values) > completionMaxValues {
+			result.Completion.HasMore = true
+			result.Completion.Total = len(values)
+			values = values[:completionMaxValues]
+		}
+		if values != nil {
+			result.Completion.Values = values
+		}
+		return result, nil
+	}
+}
+
+// subscribeHandler accepts the subscriptions to the resources of the enabled
+// toolsets that support them. The MCP server keeps track of the subscribed
+// sessions, by the URI scoped to their installation.
+func subscribeHandler(groups []*toolsets.ToolsetGroup) func(context.Context, *mcp.SubscribeRequest) error {
+	return func(_ context.Context, req *mcp.SubscribeRequest) error {
+		for _, group := range groups {
+			if group.CanSubscribe(unscopedResourceURI(req.Params.URI)) {
+				return nil
+			}
+		}
+		return mcp.ResourceNotFoundError(req.Params.URI)
+	}
+}
+
+// localizeTool translates the tool metadata and the messages of its results.
+func localizeTool(localizer *i18n.Localizer, tool toolsets.ToolWrapper) toolsets.ToolWrapper {
+	// copy the tool, so the original metadata is kept for other servers
+	localizedTool := *tool.Tool
+	if schema, ok := tool.Tool.InputSchema.(*jsonschema.Schema); ok {
+		localizedTool.InputSchema = schema.CloneSchemas()
+	}
+	if tool.Tool.Annotations != nil {
+		annotations := *tool.Tool.Annotations
+		localizedTool.Annotations = &annotations
+	}
+	localizer.LocalizeTool(&localizedTool)
+
+	handler := tool.Handler
+	return toolsets.ToolWrapper{
+		Tool: &localizedTool,
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := handler(ctx, request)
+			if err == nil {
+				localizer.LocalizeResult(result)
+			}
+			return result, err
+		},
+	}
+}
+
 // NewMCPClient creates a new MCP client.
 func NewMCPClient(
 	ctx context.Context,
@@ -0,0 +1,437 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/teamwork/mcp/internal/i18n"
+)
+
+// FileConfig contains the operator-defined configuration loaded from the JSON
+// file referenced by the TW_MCP_CONFIG_FILE environment variable.
+type FileConfig struct {
+	// Reports are the report templates exposed as prompts and through the
+	// report tool.
+	Reports []ReportTemplate `json:"reports"`
+	// Schedules are the jobs executed periodically by the HTTP server.
+	Schedules []ScheduledJob `json:"schedules"`
+	// Webhooks are the inbound webhooks served by the HTTP server, converting
+	// the received events into tool calls.
+	Webhooks []WebhookRule `json:"webhooks"`
+	// Tools are the operating parameters of specific tools, by tool name, for
+	// the tools that need different limits than the defaults (e.g. report-style
+	// tools that take longer).
+	Tools map[string]ToolSettings `json:"tools"`
+	// Macros are shortcut tools calling another tool with fixed arguments.
+	Macros []ToolMacro `json:"macros"`
+	// SLARules are the service level agreements the tasks are evaluated against
+	// by the SLA breaches tool.
+	SLARules []SLARule `json:"sla_rules"`
+	// RoleTemplates are the named sets of project permissions the users are
+	// compared against by the role template tool.
+	RoleTemplates []RoleTemplate `json:"role_templates"`
+	// TextExtractors are the external commands extracting the plain text of
+	// the attached files, by file extension (e.g. ".pdf"). Each command is the
+	// program name followed by its arguments, receiving the file in its
+	// standard input and writing the text to its standard output.
+	TextExtractors map[string][]string `json:"text_extractors"`
+	// Translations are custom translations for the language defined in the
+	// TW_MCP_LANGUAGE environment variable, taking precedence over the built-in
+	// ones.
+	Translations *i18n.Catalog `json:"translations"`
+	// Redaction hides the personal and financial data of the tool results, for
+	// deployments where the agents should only see the work structure.
+	Redaction Redaction `json:"redaction"`
+	// TaskDefaults are the tasklist and assignees of the tasks created without
+	// them.
+	TaskDefaults TaskDefaults `json:"task_defaults"`
+}
+
+// ReportTemplate defines a named report, where each section maps to a tool
+// call whose result is assembled into a single Markdown document.
+type ReportTemplate struct {
+	// Name is the unique identifier of the report.
+	Name string `json:"name"`
+	// Title is the human-readable title of the report, used as the document
+	// heading.
+	Title string `json:"title"`
+	// Description explains the purpose of the report.
+	Description string `json:"description"`
+	// Arguments are the arguments accepted by the report. They can be referenced
+	// in the section arguments using the "{{name}}" syntax.
+	Arguments []ReportArgument `json:"arguments"`
+	// Sections are the report sections, in the order they are rendered.
+	Sections []ReportSection `json:"sections"`
+}
+
+// ReportArgument defines an argument accepted by a report.
+type ReportArgument struct {
+	// Name is the argument name.
+	Name string `json:"name"`
+	// Description explains the argument.
+	Description string `json:"description"`
+	// Required indicates if the argument must be provided.
+	Required bool `json:"required"`
+}
+
+// ReportSection defines a report section, built from a tool call.
+type ReportSection struct {
+	// Title is the section heading.
+	Title string `json:"title"`
+	// Tool is the name of the read-only tool to call.
+	Tool string `json:"tool"`
+	// Arguments are the tool arguments. String values can reference report
+	// arguments using the "{{name}}" syntax.
+	Arguments map[string]any `json:"arguments"`
+}
+
+// ScheduledJob defines a tool executed periodically, with the result posted to
+// a project message board or a webhook.
+type ScheduledJob struct {
+	// Name is the unique identifier of the job.
+	Name string `json:"name"`
+	// Schedule is a cron expression with 5 fields (minute, hour, day of month,
+	// month and day of week) or one of the descriptors "@hourly", "@daily",
+	// "@weekly" and "@monthly".
+	Schedule string `json:"schedule"`
+	// Timezone is the IANA timezone used to evaluate the schedule. When empty,
+	// UTC is used.
+	Timezone string `json:"timezone"`
+	// Tool is the name of the read-only tool to call.
+	Tool string `json:"tool"`
+	// Arguments are the tool arguments.
+	Arguments map[string]any `json:"arguments"`
+	// TokenEnv is the name of the environment variable with the bearer token
+	// used to call the tool, so secrets are kept out of the config file.
+	TokenEnv string `json:"token_env"`
+	// Output defines where the result is posted.
+	Output ScheduledJobOutput `json:"output"`
+}
+
+// ScheduledJobOutput defines the destinations of a scheduled job result. At
+// least one destination must be set.
+type ScheduledJobOutput struct {
+	// ProjectID is the project where the result is posted as a message.
+	ProjectID int64 `json:"project_id"`
+	// WebhookURL is the URL where the result is posted as JSON.
+	WebhookURL string `json:"webhook_url"`
+}
+
+// WebhookRule defines an inbound webhook, served at /webhooks/{name}, calling a
+// tool for each signed event received (e.g. creating a task for each new email
+// or ticket).
+type WebhookRule struct {
+	// Name is the unique identifier of the webhook, used in its path.
+	Name string `json:"name"`
+	// SecretEnv is the name of the environment variable with the secret used to
+	// verify the HMAC-SHA256 signature of the payloads.
+	SecretEnv string `json:"secret_env"`
+	// SignatureHeader is the header with the hex encoded signature. When empty,
+	// the Teamwork.com "X-Projects-Signature" header is used.
+	SignatureHeader string `json:"signature_header"`
+	// TimestampHeader is the header with the signing time, in Unix seconds,
+	// which is signed with the payload. When empty, the "X-Webhook-Timestamp"
+	// header is used.
+	TimestampHeader string `json:"timestamp_header"`
+	// Events are the accepted events, matched with the "X-Projects-Event" header
+	// or the "event" field of the payload. When empty, all events are accepted.
+	Events []string `json:"events"`
+	// Tool is the name of the tool to call.
+	Tool string `json:"tool"`
+	// Arguments are the tool arguments. String values can reference payload
+	// fields using the "{{path.to.field}}" syntax.
+	Arguments map[string]any `json:"arguments"`
+	// TokenEnv is the name of the environment variable with the bearer token
+	// used to call the tool, so secrets are kept out of the config file.
+	TokenEnv string `json:"token_env"`
+}
+
+// ToolSettings overrides the operating parameters of a tool call.
+type ToolSettings struct {
+	// Timeout is the maximum duration of each attempt of the tool call (e.g.
+	// "30s"). When empty, the call isn't limited.
+	Timeout Duration `json:"timeout"`
+	// Retries is the number of times the tool call is retried when it fails with
+	// a transient error, such as a network or server error. Only read-only tools
+	// are retried, as a failed write may still have been applied.
+	Retries int `json:"retries"`
+	// RPS is the maximum number of tool calls per second for each Teamwork.com
+	// installation. When zero, the calls aren't rate limited.
+	RPS float64 `json:"rps"`
+}
+
+// ToolMacro defines a named shortcut exposed as an additional tool, calling
+// another tool with fixed arguments merged with the remaining arguments
+// provided at call time (e.g. logging 15 minutes of standup time in a fixed
+// project).
+type ToolMacro struct {
+	// Name is the unique identifier of the macro, used in the tool name.
+	Name string `json:"name"`
+	// Description explains the purpose of the macro. When empty, the
+	// description of the called tool is used.
+	Description string `json:"description"`
+	// Tool is the name of the tool to call.
+	Tool string `json:"tool"`
+	// Arguments are the fixed arguments of the tool. They take precedence over
+	// the arguments provided at call time.
+	Arguments map[string]any `json:"arguments"`
+}
+
+// SLARule defines the maximum time a task matching the rule can stay open
+// since its creation (e.g. high priority tasks must be completed within 3
+// days).
+type SLARule struct {
+	// Name is the unique identifier of the rule, reported with the breaches.
+	Name string `json:"name"`
+	// Priority is the task priority matched by the rule ("low", "medium" or
+	// "high"). When empty, tasks of any priority are matched.
+	Priority string `json:"priority"`
+	// TagIDs are the tags matched by the rule, where a task matches when it has
+	// any of them. When empty, tasks with any tags are matched.
+	TagIDs []int64 `json:"tag_ids"`
+	// CompleteWithin is the maximum time between the creation and the
+	// completion of a task (e.g. "72h").
+	CompleteWithin Duration `json:"complete_within"`
+}
+
+// RoleTemplate defines a named set of project permissions (e.g. "developer" or
+// "client"), applied to the users of many projects in a consistent way.
+type RoleTemplate struct {
+	// Name is the unique identifier of the template.
+	Name string `json:"name"`
+	// Description explains the role.
+	Description string `json:"description"`
+	// Permissions are the project permissions of the role, by permission name
+	// (e.g. "add-tasks" or "view-time"). Permissions not listed aren't compared
+	// nor changed.
+	Permissions map[string]bool `json:"permissions"`
+}
+
+// Redaction defines the data replaced in the tool results. The values of the
+// matching fields are replaced as a whole, while the matching texts are
+// replaced anywhere (e.g. an email address in a comment).
+type Redaction struct {
+	// Emails redacts the email addresses and the email fields.
+	Emails bool `json:"emails"`
+	// Phones redacts the phone numbers and the phone, mobile and fax fields.
+	Phones bool `json:"phones"`
+	// Rates redacts the rate, cost, price, budget, billing and invoice fields.
+	Rates bool `json:"rates"`
+	// Fields are additional field names whose values are redacted, compared
+	// case-insensitively (e.g. "address").
+	Fields []string `json:"fields"`
+	// Patterns are additional regular expressions whose matches are redacted
+	// (e.g. national ID numbers).
+	Patterns []string `json:"patterns"`
+}
+
+// Enabled reports whether anything is redacted.
+func (r Redaction) Enabled() bool {
+	return r.Emails || r.Phones || r.Rates || len(r.Fields) > 0 || len(r.Patterns) > 0
+}
+
+// TaskDefaults defines the values used when a task is created without a
+// tasklist or assignees, smoothing quick-capture workflows (e.g. "add 'follow
+// up with ACME' to my list").
+type TaskDefaults struct {
+	// AssignToMe assigns the tasks created without assignees to the user calling
+	// the tool, when no default assignees apply.
+	AssignToMe bool `json:"assign_to_me"`
+	// AssigneeUserIDs are the users assigned to the tasks created without
+	// assignees, when the project doesn't define its own.
+	AssigneeUserIDs []int64 `json:"assignee_user_ids"`
+	// Projects are the defaults of specific projects.
+	Projects []ProjectTaskDefaults `json:"projects"`
+}
+
+// ProjectTaskDefaults defines the task defaults of a project.
+type ProjectTaskDefaults struct {
+	// ProjectID is the project the defaults apply to.
+	ProjectID int64 `json:"project_id"`
+	// TasklistID is the tasklist of the tasks created in the project without a
+	// tasklist.
+	TasklistID int64 `json:"tasklist_id"`
+	// AssigneeUserIDs are the users assigned to the tasks created in the project
+	// without assignees.
+	AssigneeUserIDs []int64 `json:"assignee_user_ids"`
+}
+
+// Enabled reports whether any default is defined.
+func (t TaskDefaults) Enabled() bool {
+	return t.AssignToMe || len(t.AssigneeUserIDs) > 0 || len(t.Projects) > 0
+}
+
+// Project returns the defaults of the project, if any.
+func (t TaskDefaults) Project(projectID int64) (ProjectTaskDefaults, bool) {
+	for _, project := range t.Projects {
+		if project.ProjectID == projectID {
+			return project, true
+		}
+	}
+	return ProjectTaskDefaults{}, false
+}
+
+// Duration is a time.Duration decoded from a JSON string, such as "1m30s".
+type Duration time.Duration
+
+// UnmarshalJSON decodes the duration from a string parsed by
+// time.ParseDuration.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("duration must be a string: %w", err)
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+	*d = Duration(duration)
+	return nil
+}
+
+// loadFileConfig reads and validates the configuration file.
+func loadFileConfig(path string) (FileConfig, error) {
+	var fileConfig FileConfig
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fileConfig, fmt.Errorf("failed to read config file: %w", err)
+	}
+	if err := json.Unmarshal(content, &fileConfig); err != nil {
+		return fileConfig, fmt.Errorf("failed to decode config file: %w", err)
+	}
+
+	names := make(map[string]struct{}, len(fileConfig.Reports))
+	for _, report := range fileConfig.Reports {
+		if report.Name == "" {
+			return fileConfig, errors.New("report without name")
+		}
+		if _, ok := names[report.Name]; ok {
+			return fileConfig, fmt.Errorf("duplicated report %q", report.Name)
+		}
+		names[report.Name] = struct{}{}
+		if len(report.Sections) == 0 {
+			return fileConfig, fmt.Errorf("report %q without sections", report.Name)
+		}
+		for _, section := range report.Sections {
+			if section.Tool == "" {
+				return fileConfig, fmt.Errorf("report %q has a section without tool", report.Name)
+			}
+		}
+	}
+
+	names = make(map[string]struct{}, len(fileConfig.Schedules))
+	for _, job := range fileConfig.Schedules {
+		if job.Name == "" {
+			return fileConfig, errors.New("scheduled job without name")
+		}
+		if _, ok := names[job.Name]; ok {
+			return fileConfig, fmt.Errorf("duplicated scheduled job %q", job.Name)
+		}
+		names[job.Name] = struct{}{}
+		if job.Schedule == "" || job.Tool == "" || job.TokenEnv == "" {
+			return fileConfig, fmt.Errorf("scheduled job %q requires schedule, tool and token_env", job.Name)
+		}
+		if job.Output.ProjectID == 0 && job.Output.WebhookURL == "" {
+			return fileConfig, fmt.Errorf("scheduled job %q without output", job.Name)
+		}
+	}
+
+	names = make(map[string]struct{}, len(fileConfig.Webhooks))
+	for _, webhook := range fileConfig.Webhooks {
+		if webhook.Name == "" {
+			return fileConfig, errors.New("webhook without name")
+		}
+		if _, ok := names[webhook.Name]; ok {
+			return fileConfig, fmt.Errorf("duplicated webhook %q", webhook.Name)
+		}
+		names[webhook.Name] = struct{}{}
+		if webhook.SecretEnv == "" || webhook.Tool == "" || webhook.TokenEnv == "" {
+			return fileConfig, fmt.Errorf("webhook %q requires secret_env, tool and token_env", webhook.Name)
+		}
+	}
+
+	names = make(map[string]struct{}, len(fileConfig.Macros))
+	for _, macro := range fileConfig.Macros {
+		if macro.Name == "" {
+			return fileConfig, errors.New("macro without name")
+		}
+		if _, ok := names[macro.Name]; ok {
+			return fileConfig, fmt.Errorf("duplicated macro %q", macro.Name)
+		}
+		names[macro.Name] = struct{}{}
+		if macro.Tool == "" {
+			return fileConfig, fmt.Errorf("macro %q without tool", macro.Name)
+		}
+	}
+
+	names = make(map[string]struct{}, len(fileConfig.SLARules))
+	for _, rule := range fileConfig.SLARules {
+		if rule.Name == "" {
+			return fileConfig, errors.New("SLA rule without name")
+		}
+		if _, ok := names[rule.Name]; ok {
+			return fileConfig, fmt.Errorf("duplicated SLA rule %q", rule.Name)
+		}
+		names[rule.Name] = struct{}{}
+		if rule.CompleteWithin <= 0 {
+			return fileConfig, fmt.Errorf("SLA rule %q requires a positive complete_within", rule.Name)
+		}
+	}
+
+	names = make(map[string]struct{}, len(fileConfig.RoleTemplates))
+	for _, template := range fileConfig.RoleTemplates {
+		if template.Name == "" {
+			return fileConfig, errors.New("role template without name")
+		}
+		if _, ok := names[template.Name]; ok {
+			return fileConfig, fmt.Errorf("duplicated role template %q", template.Name)
+		}
+		names[template.Name] = struct{}{}
+		if len(template.Permissions) == 0 {
+			return fileConfig, fmt.Errorf("role template %q without permissions", template.Name)
+		}
+	}
+
+	for extension, command := range fileConfig.TextExtractors {
+		if !strings.HasPrefix(extension, ".") {
+			return fileConfig, fmt.Errorf("text extractor %q must be a file extension starting with a dot", extension)
+		}
+		if len(command) == 0 || command[0] == "" {
+			return fileConfig, fmt.Errorf("text extractor %q without command", extension)
+		}
+	}
+
+	for name, settings := range fileConfig.Tools {
+		if settings.Timeout < 0 || settings.Retries < 0 || settings.RPS < 0 {
+			return fileConfig, fmt.Errorf("tool %q has negative settings", name)
+		}
+	}
+
+	projectIDs := make(map[int64]struct{}, len(fileConfig.TaskDefaults.Projects))
+	for _, project := range fileConfig.TaskDefaults.Projects {
+		if project.ProjectID <= 0 {
+			return fileConfig, errors.New("task defaults without project_id")
+		}
+		if _, ok := projectIDs[project.ProjectID]; ok {
+			return fileConfig, fmt.Errorf("duplicated task defaults of project %d", project.ProjectID)
+		}
+		projectIDs[project.ProjectID] = struct{}{}
+		if project.TasklistID <= 0 && len(project.AssigneeUserIDs) == 0 {
+			return fileConfig, fmt.Errorf("task defaults of project %d require tasklist_id or assignee_user_ids",
+				project.ProjectID)
+		}
+	}
+
+	for _, pattern := range fileConfig.Redaction.Patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fileConfig, fmt.Errorf("invalid redaction pattern %q: %w", pattern, err)
+		}
+	}
+	return fileConfig, nil
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/getsentry/sentry-go"
 	sentryslog "github.com/getsentry/sentry-go/slog"
+	"github.com/teamwork/mcp/internal/request"
 )
 
 // customLogHandler is a slog.Handler that wraps another slog.Handler and
@@ -23,8 +24,13 @@ func (h *customLogHandler) Enabled(ctx context.Context, level slog.Level) bool {
 }
 
 // Handle processes a log record. If the log level is error or higher and
-// Sentry is configured, it also sends the log to Sentry.
+// Sentry is configured, it also sends the log to Sentry. Records logged within
+// a tool call include its correlation ID.
 func (h *customLogHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id, ok := request.CorrelationIDFromContext(ctx); ok {
+		record = record.Clone()
+		record.AddAttrs(slog.String("correlation_id", id))
+	}
 	if h.sentry != nil && h.sentry.Enabled(ctx, record.Level) {
 		if err := h.sentry.Handle(ctx, record); err != nil {
 			return err
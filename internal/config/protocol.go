@@ -0,0 +1,81 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ProtocolVersion20250618 is the MCP protocol version that introduced
+// structured content, resource links and elicitation.
+//
+// https://modelcontextprotocol.io/specification/2025-06-18/changelog
+const ProtocolVersion20250618 = "2025-06-18"
+
+// ProtocolFeatures lists the MCP features that can be sent to a client.
+type ProtocolFeatures struct {
+	// StructuredContent is true when tool results can have structured content
+	// and tools can declare an output schema.
+	StructuredContent bool
+	// ResourceLinks is true when tool results can have resource link content.
+	ResourceLinks bool
+}
+
+// NegotiatedFeatures returns the features supported by the client, based on the
+// protocol version and capabilities sent at initialize. When the session wasn't
+// initialized (e.g. stateless HTTP requests) all features are assumed to be
+// supported.
+func NegotiatedFeatures(params *mcp.InitializeParams) ProtocolFeatures {
+	if params == nil {
+		return ProtocolFeatures{
+			StructuredContent: true,
+			ResourceLinks:     true,
+		}
+	}
+	// protocol versions are dates, so they can be compared as strings
+	current := params.ProtocolVersion >= ProtocolVersion20250618
+	return ProtocolFeatures{
+		StructuredContent: current,
+		ResourceLinks:     current,
+	}
+}
+
+// AdaptToolResult removes from the tool result the content types the client
+// can't parse. Resource links are replaced by text content with the same
+// information.
+func AdaptToolResult(result *mcp.CallToolResult, features ProtocolFeatures) *mcp.CallToolResult {
+	if result == nil || (features.StructuredContent && features.ResourceLinks) {
+		return result
+	}
+	adapted := *result
+	if !features.StructuredContent {
+		adapted.StructuredContent = nil
+	}
+	if !features.ResourceLinks {
+		adapted.Content = make([]mcp.Content, len(result.Content))
+		for i, content := range result.Content {
+			if link, ok := content.(*mcp.ResourceLink); ok {
+				content = &mcp.TextContent{
+					Text: fmt.Sprintf("%s: %s", link.Name, link.URI),
+				}
+			}
+			adapted.Content[i] = content
+		}
+	}
+	return &adapted
+}
+
+// AdaptTools removes from the tools the fields the client can't parse. The
+// tools are copied, as they are shared between sessions.
+func AdaptTools(tools []*mcp.Tool, features ProtocolFeatures) []*mcp.Tool {
+	if features.StructuredContent {
+		return tools
+	}
+	adapted := make([]*mcp.Tool, len(tools))
+	for i, tool := range tools {
+		toolCopy := *tool
+		toolCopy.OutputSchema = nil
+		adapted[i] = &toolCopy
+	}
+	return adapted
+}
@@ -0,0 +1,110 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/config"
+)
+
+func TestNegotiatedFeatures(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   *mcp.InitializeParams
+		expected config.ProtocolFeatures
+	}{{
+		name:     "stateless request",
+		expected: config.ProtocolFeatures{StructuredContent: true, ResourceLinks: true},
+	}, {
+		name:     "previous protocol version",
+		params:   &mcp.InitializeParams{ProtocolVersion: "2025-03-26"},
+		expected: config.ProtocolFeatures{},
+	}, {
+		name:     "current protocol version",
+		params:   &mcp.InitializeParams{ProtocolVersion: config.ProtocolVersion20250618},
+		expected: config.ProtocolFeatures{StructuredContent: true, ResourceLinks: true},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if features := config.NegotiatedFeatures(tt.params); features != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, features)
+			}
+		})
+	}
+}
+
+func TestAdaptToolResult(t *testing.T) {
+	newResult := func() *mcp.CallToolResult {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: "Task created"},
+				&mcp.ResourceLink{Name: "Task 123", URI: "twprojects://tasks/123"},
+			},
+			StructuredContent: map[string]any{"id": 123},
+		}
+	}
+
+	t.Run("nil result", func(t *testing.T) {
+		if result := config.AdaptToolResult(nil, config.ProtocolFeatures{}); result != nil {
+			t.Errorf("expected nil result, got %v", result)
+		}
+	})
+
+	t.Run("all features", func(t *testing.T) {
+		result := newResult()
+		features := config.ProtocolFeatures{StructuredContent: true, ResourceLinks: true}
+		if adapted := config.AdaptToolResult(result, features); adapted != result {
+			t.Errorf("expected the result unchanged, got %v", adapted)
+		}
+	})
+
+	t.Run("previous protocol version", func(t *testing.T) {
+		result := newResult()
+		adapted := config.AdaptToolResult(result, config.ProtocolFeatures{})
+		if adapted.StructuredContent != nil {
+			t.Errorf("expected no structured content, got %v", adapted.StructuredContent)
+		}
+		if len(adapted.Content) != 2 {
+			t.Fatalf("expected 2 contents, got %d", len(adapted.Content))
+		}
+		text, ok := adapted.Content[1].(*mcp.TextContent)
+		if !ok || text.Text != "Task 123: twprojects://tasks/123" {
+			t.Errorf("expected the resource link as text, got %v", adapted.Content[1])
+		}
+
+		// the original result is shared, so it isn't changed
+		if result.StructuredContent == nil {
+			t.Error("expected the original structured content")
+		}
+		if _, ok := result.Content[1].(*mcp.ResourceLink); !ok {
+			t.Errorf("expected the original resource link, got %v", result.Content[1])
+		}
+	})
+}
+
+func TestAdaptTools(t *testing.T) {
+	tools := []*mcp.Tool{{
+		Name:         "get_task",
+		InputSchema:  &jsonschema.Schema{Type: "object"},
+		OutputSchema: &jsonschema.Schema{Type: "object"},
+	}}
+
+	adapted := config.AdaptTools(tools, config.ProtocolFeatures{StructuredContent: true, ResourceLinks: true})
+	if adapted[0] != tools[0] {
+		t.Error("expected the tools unchanged")
+	}
+
+	adapted = config.AdaptTools(tools, config.ProtocolFeatures{})
+	if adapted[0].OutputSchema != nil {
+		t.Errorf("expected no output schema, got %v", adapted[0].OutputSchema)
+	}
+	if adapted[0].Name != "get_task" || adapted[0].InputSchema == nil {
+		t.Errorf("expected the other fields of the tool, got %+v", adapted[0])
+	}
+	// the tools are shared between sessions, so they aren't changed
+	if tools[0].OutputSchema == nil {
+		t.Error("expected the original output schema")
+	}
+}
@@ -0,0 +1,239 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/toolsets"
+)
+
+// redactedValue replaces the redacted values and texts.
+const redactedValue = "[redacted]"
+
+var (
+	// reEmail matches the email addresses.
+	reEmail = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	// rePhone matches the phone numbers with separators between the digit
+	// groups (e.g. "+353 1 234 5678" or "(555) 123-4567"), so dates and IDs
+	// aren't mistaken for phone numbers.
+	rePhone = regexp.MustCompile(`(?:\+\d{1,3}[\s.-]?)?(?:\(\d{1,4}\)|\b\d{1,4})[\s.-]?\d{3,4}[\s.-]\d{3,4}\b`)
+)
+
+// redactionFieldWords are the words of the field names redacted by each
+// built-in category (e.g. "hourlyRate" or "phone-number-mobile").
+var (
+	redactionEmailWords = []string{"email"}
+	redactionPhoneWords = []string{"phone", "mobile", "fax"}
+	redactionRateWords  = []string{"rate", "rates", "cost", "costs", "price", "prices", "budget", "budgets", "revenue",
+		"profit", "billable", "billed", "billing", "invoice", "invoices", "invoiced", "amount", "amounts", "fee", "fees",
+		"expense", "expenses", "currency", "currencies"}
+)
+
+// redactor replaces the personal and financial data of the tool results.
+type redactor struct {
+	words    []string
+	fields   []string
+	patterns []*regexp.Regexp
+}
+
+func newRedactor(redaction Redaction) *redactor {
+	var r redactor
+	if redaction.Emails {
+		r.words = append(r.words, redactionEmailWords...)
+		r.patterns = append(r.patterns, reEmail)
+	}
+	if redaction.Phones {
+		r.words = append(r.words, redactionPhoneWords...)
+		r.patterns = append(r.patterns, rePhone)
+	}
+	if redaction.Rates {
+		r.words = append(r.words, redactionRateWords...)
+	}
+	for _, field := range redaction.Fields {
+		r.fields = append(r.fields, strings.ToLower(field))
+	}
+	for _, pattern := range redaction.Patterns {
+		// the patterns are validated when the configuration file is loaded
+		if re, err := regexp.Compile(pattern); err == nil {
+			r.patterns = append(r.patterns, re)
+		}
+	}
+	return &r
+}
+
+// ToolRedactionMiddleware redacts the text and structured content of the tool
+// results, including the errors, as they can quote the submitted data. It is
+// also used by the toolsets hiding the financial data of the results.
+func ToolRedactionMiddleware(redaction Redaction) toolsets.ToolMiddleware {
+	r := newRedactor(redaction)
+	return func(next mcp.ToolHandler) mcp.ToolHandler {
+		return func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := next(ctx, request)
+			if result == nil {
+				return result, err
+			}
+			for _, content := range result.Content {
+				text, ok := content.(*mcp.TextContent)
+				if !ok {
+					continue
+				}
+				if redacted, ok := r.redact([]byte(text.Text)); ok {
+					text.Text = string(redacted)
+				}
+			}
+			if result.StructuredContent != nil {
+				if encoded, err := json.Marshal(result.StructuredContent); err == nil {
+					if redacted, ok := r.redact(encoded); ok {
+						var decoded any
+						if err := json.Unmarshal(redacted, &decoded); err == nil {
+							result.StructuredContent = decoded
+						}
+					}
+				}
+			}
+			return result, err
+		}
+	}
+}
+
+// UnattendedToolMiddlewares returns the middlewares redacting the results of
+// the tools called outside the MCP server (e.g. scheduled jobs and webhooks),
+// like the server does. The financial data is redacted as well when disabled.
+func UnattendedToolMiddlewares(resources Resources) []toolsets.ToolMiddleware {
+	var middlewares []toolsets.ToolMiddleware
+	if redaction := resources.fileConfig.Redaction; redaction.Enabled() {
+		middlewares = append(middlewares, ToolRedactionMiddleware(redaction))
+	}
+	if !resources.Info.Finance {
+		middlewares = append(middlewares, ToolRedactionMiddleware(Redaction{Rates: true}))
+	}
+	return middlewares
+}
+
+// Redactor returns a function redacting the data like ToolRedactionMiddleware,
+// for the data that isn't returned by the tools (e.g. resources). It reports
+// whether anything was redacted.
+func Redactor(redaction Redaction) func(data []byte) ([]byte, bool) {
+	return newRedactor(redaction).redact
+}
+
+// redact replaces the redacted fields and texts of the JSON data, or the
+// redacted texts of other data. It returns the data unchanged, and false, when
+// nothing was redacted.
+func (r *redactor) redact(data []byte) ([]byte, bool) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 || (trimmed[0] != '{' && trimmed[0] != '[') {
+		text, changed := r.redactText(string(data))
+		return []byte(text), changed
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(trimmed))
+	decoder.UseNumber()
+	var decoded any
+	if err := decoder.Decode(&decoded); err != nil {
+		text, changed := r.redactText(string(data))
+		return []byte(text), changed
+	}
+	if !r.redactValue(&decoded) {
+		return data, false
+	}
+
+	var encoded bytes.Buffer
+	encoder := json.NewEncoder(&encoded)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(decoded); err != nil {
+		return data, false
+	}
+	return bytes.TrimSuffix(encoded.Bytes(), []byte("\n")), true
+}
+
+// redactValue redacts the fields and texts found anywhere in the value. It
+// reports whether anything was redacted.
+func (r *redactor) redactValue(value *any) bool {
+	var changed bool
+	switch v := (*value).(type) {
+	case map[string]any:
+		for key, item := range v {
+			if item != nil && r.redactedField(key) {
+				if item != redactedValue {
+					v[key] = redactedValue
+					changed = true
+				}
+				continue
+			}
+			if r.redactValue(&item) {
+				v[key] = item
+				changed = true
+			}
+		}
+	case []any:
+		for i := range v {
+			changed = r.redactValue(&v[i]) || changed
+		}
+	case string:
+		if text, ok := r.redactText(v); ok {
+			*value = text
+			changed = true
+		}
+	}
+	return changed
+}
+
+// redactText replaces the matches of the patterns in the text.
+func (r *redactor) redactText(text string) (string, bool) {
+	var changed bool
+	for _, pattern := range r.patterns {
+		if pattern.MatchString(text) {
+			text = pattern.ReplaceAllLiteralString(text, redactedValue)
+			changed = true
+		}
+	}
+	return text, changed
+}
+
+// redactedField checks if the values of the field are redacted. The ID fields
+// are never redacted, as they reference other entities (e.g. "budgetId").
+func (r *redactor) redactedField(key string) bool {
+	if isIDField(key) || isIDListField(key) {
+		return false
+	}
+	if slices.Contains(r.fields, strings.ToLower(key)) {
+		return true
+	}
+	return slices.ContainsFunc(fieldWords(key), func(word string) bool {
+		return slices.Contains(r.words, word)
+	})
+}
+
+// fieldWords splits a field name in lowercase words, in any of the naming
+// conventions of the API versions (e.g. "hourlyRate", "phone-number-mobile" or
+// "user_cost").
+func fieldWords(key string) []string {
+	var words []string
+	var word strings.Builder
+	flush := func() {
+		if word.Len() > 0 {
+			words = append(words, strings.ToLower(word.String()))
+			word.Reset()
+		}
+	}
+	runes := []rune(key)
+	for i, char := range runes {
+		switch {
+		case !unicode.IsLetter(char) && !unicode.IsDigit(char):
+			flush()
+			continue
+		case unicode.IsUpper(char) && i > 0 && unicode.IsLower(runes[i-1]):
+			flush()
+		}
+		word.WriteRune(char)
+	}
+	flush()
+	return words
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"context"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// resourceScopeSeparator separates the installation from the URI of the
+// subscribed resources. The customer URLs never contain it.
+const resourceScopeSeparator = "\n"
+
+// ScopedResourceURI returns the URI the updates of the resource are notified
+// with. In HTTP mode the MCP servers are shared by all customers, and the same
+// URI (e.g. "twprojects://tasks/5") references a different entity in each
+// installation, so the subscriptions are kept per installation.
+func ScopedResourceURI(ctx context.Context, uri string) string {
+	customerURL, _ := CustomerURLFromContext(ctx)
+	if customerURL == "" {
+		return uri
+	}
+	return customerURL + resourceScopeSeparator + uri
+}
+
+// unscopedResourceURI returns the URI of the resource, as seen by the clients,
+// from the URI returned by ScopedResourceURI.
+func unscopedResourceURI(uri string) string {
+	if _, unscoped, ok := strings.Cut(uri, resourceScopeSeparator); ok {
+		return unscoped
+	}
+	return uri
+}
+
+// resourceScopeMiddleware subscribes the sessions to the resources of their
+// installation. See ScopedResourceURI.
+func resourceScopeMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		switch req := req.(type) {
+		case *mcp.SubscribeRequest:
+			if req.Params != nil {
+				params := *req.Params
+				params.URI = ScopedResourceURI(ctx, params.URI)
+				req.Params = &params
+			}
+		case *mcp.UnsubscribeRequest:
+			if req.Params != nil {
+				params := *req.Params
+				params.URI = ScopedResourceURI(ctx, params.URI)
+				req.Params = &params
+			}
+		}
+		return next(ctx, method, req)
+	}
+}
+
+// resourceUnscopeMiddleware notifies the updated resources with the URI the
+// clients subscribed to. The parameters are copied, as they are shared by the
+// notifications of all the subscribed sessions.
+func resourceUnscopeMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		notification, ok := req.(*mcp.ServerRequest[*mcp.ResourceUpdatedNotificationParams])
+		if !ok || notification.Params == nil {
+			return next(ctx, method, req)
+		}
+		params := *notification.Params
+		params.URI = unscopedResourceURI(params.URI)
+		return next(ctx, method, &mcp.ServerRequest[*mcp.ResourceUpdatedNotificationParams]{
+			Session: notification.Session,
+			Params:  &params,
+			Extra:   notification.Extra,
+		})
+	}
+}
+
+// resourceContentsMiddleware rewrites the text of the resources read by the
+// clients, e.g. redacting them like the tool results.
+func resourceContentsMiddleware(rewrites ...func(data []byte) ([]byte, bool)) mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			result, err := next(ctx, method, req)
+			readResult, ok := result.(*mcp.ReadResourceResult)
+			if err != nil || !ok || readResult == nil {
+				return result, err
+			}
+			for _, contents := range readResult.Contents {
+				if contents == nil || contents.Text == "" {
+					continue
+				}
+				for _, rewrite := range rewrites {
+					if rewritten, ok := rewrite([]byte(contents.Text)); ok {
+						contents.Text = string(rewritten)
+					}
+				}
+			}
+			return readResult, nil
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package config_test
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/config"
+	"github.com/teamwork/mcp/internal/toolsets"
+)
+
+func TestResourceContentsRedaction(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.json")
+	err := os.WriteFile(configFile, []byte(`{"redaction":{"emails":true}}`), 0o600)
+	if err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	t.Setenv("TW_MCP_CONFIG_FILE", configFile)
+	t.Setenv("TW_MCP_STRING_IDS", "true")
+	resources, teardown, err := config.Load(io.Discard)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	t.Cleanup(teardown)
+
+	group, _ := resourceToolsetGroup(t, func(string) string {
+		return `{"id":1,"email":"jane@example.com","notes":"ask jane@example.com"}`
+	}, nil)
+	clientSession := connectClient(t, t.Context(), config.NewMCPServer(resources, group), nil)
+
+	resource, err := clientSession.ReadResource(t.Context(), &mcp.ReadResourceParams{URI: "test://items/1"})
+	if err != nil {
+		t.Fatalf("failed to read resource: %v", err)
+	}
+	expected := `{"email":"[redacted]","id":"1","notes":"ask [redacted]"}`
+	if text := resource.Contents[0].Text; text != expected {
+		t.Errorf("expected %s, got %s", expected, text)
+	}
+}
+
+func TestResourceSubscriptionsInstallations(t *testing.T) {
+	resources, teardown, err := config.Load(io.Discard)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	t.Cleanup(teardown)
+
+	var subscriptions *toolsets.ResourceSubscriptions
+	var group *toolsets.ToolsetGroup
+	group, subscriptions = resourceToolsetGroup(t, func(string) string {
+		return `{}`
+	}, func(ctx context.Context) {
+		subscriptions.Notify(ctx, config.ScopedResourceURI(ctx, "test://items/1"))
+	})
+	mcpServer := config.NewMCPServer(resources, group)
+
+	updated := make(map[string]chan string)
+	sessions := make(map[string]*mcp.ClientSession)
+	for _, customerURL := range []string{"https://a.teamwork.com", "https://b.teamwork.com"} {
+		updated[customerURL] = make(chan string, 1)
+		sessions[customerURL] = connectClient(t, config.WithCustomerURL(t.Context(), customerURL), mcpServer,
+			&mcp.ClientOptions{
+				ResourceUpdatedHandler: func(_ context.Context, req *mcp.ResourceUpdatedNotificationRequest) {
+					updated[customerURL] <- req.Params.URI
+				},
+			})
+		err := sessions[customerURL].Subscribe(t.Context(), &mcp.SubscribeParams{URI: "test://items/1"})
+		if err != nil {
+			t.Fatalf("failed to subscribe: %v", err)
+		}
+	}
+
+	result, err := sessions["https://a.teamwork.com"].CallTool(t.Context(), &mcp.CallToolParams{Name: "test-update"})
+	if err != nil || result.IsError {
+		t.Fatalf("failed to call tool: %v %v", err, result)
+	}
+	select {
+	case uri := <-updated["https://a.teamwork.com"]:
+		if uri != "test://items/1" {
+			t.Errorf("expected an update of test://items/1, got %s", uri)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the client of the installation to be notified")
+	}
+	select {
+	case uri := <-updated["https://b.teamwork.com"]:
+		t.Errorf("unexpected update of %s in another installation", uri)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+// resourceToolsetGroup returns a group with a resource template, whose
+// contents are returned by the read function, and a tool calling the update
+// function. The clients can subscribe to the resources.
+func resourceToolsetGroup(
+	t *testing.T,
+	read func(uri string) string,
+	update func(ctx context.Context),
+) (*toolsets.ToolsetGroup, *toolsets.ResourceSubscriptions) {
+	t.Helper()
+
+	toolset := toolsets.NewToolset("test", "Test tools")
+	toolset.AddWriteTools(toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name:        "test-update",
+			Annotations: &mcp.ToolAnnotations{},
+			InputSchema: &jsonschema.Schema{Type: "object"},
+		},
+		Handler: func(ctx context.Context, _ *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if update != nil {
+				update(ctx)
+			}
+			return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "ok"}}}, nil
+		},
+	})
+	toolset.AddResourceTemplates(toolsets.NewServerResourceTemplate(&mcp.ResourceTemplate{
+		Name:        "item",
+		URITemplate: "test://items/{id}",
+		MIMEType:    "application/json",
+	}, func(_ context.Context, request *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		return &mcp.ReadResourceResult{
+			Contents: []*mcp.ResourceContents{{
+				URI:      request.Params.URI,
+				MIMEType: "application/json",
+				Text:     read(request.Params.URI),
+			}},
+		}, nil
+	}))
+	subscriptions := toolsets.NewResourceSubscriptions(func(uri string) bool {
+		return strings.HasPrefix(uri, "test://items/")
+	})
+	toolset.SetResourceSubscriptions(subscriptions)
+	group := toolsets.NewToolsetGroup(false)
+	group.AddToolset(toolset)
+	if err := group.EnableToolsets(toolsets.MethodAll); err != nil {
+		t.Fatalf("failed to enable toolsets: %v", err)
+	}
+	return group, subscriptions
+}
+
+// connectClient connects a client to the server, with the session of the
+// server using the given context.
+func connectClient(
+	t *testing.T,
+	ctx context.Context,
+	mcpServer *mcp.Server,
+	options *mcp.ClientOptions,
+) *mcp.ClientSession {
+	t.Helper()
+
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	if _, err := mcpServer.Connect(ctx, serverTransport, nil); err != nil {
+		t.Fatalf("failed to connect to server: %v", err)
+	}
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, options)
+	clientSession, err := client.Connect(t.Context(), clientTransport, nil)
+	if err != nil {
+		t.Fatalf("failed to connect to client: %v", err)
+	}
+	t.Cleanup(func() { _ = clientSession.Close() })
+	return clientSession
+}
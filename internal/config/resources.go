@@ -1,12 +1,17 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	desksdk "github.com/teamwork/desksdkgo/client"
+	"github.com/teamwork/mcp/internal/i18n"
 	twapi "github.com/teamwork/twapi-go-sdk"
 )
 
@@ -15,17 +20,27 @@ import (
 // If not set, it defaults to "dev".
 var Version = "dev"
 
+// Revision is the git revision the MCP server was built from. It is set at
+// build time using -ldflags "-X 'github.com/teamwork/mcp/internal/config.Revision=abc1234'".
+// If not set, it defaults to the revision recorded by the Go toolchain, if any.
+var Revision = ""
+
 // Resources stores all the resources loaded in the startup.
 type Resources struct {
 	teamworkHTTPClient *http.Client
 	teamworkEngine     *twapi.Engine
 	deskClient         *desksdk.Client
 	logger             *slog.Logger
+	fileConfig         FileConfig
+	localizer          *i18n.Localizer
+	writeLog           *WriteLog
 
 	// Info stores environment variables mappings.
 	Info struct {
 		// Version is the current version of the MCP server.
 		Version string
+		// Revision is the git revision the MCP server was built from.
+		Revision string
 		// ServerAddress is the address of the server. This is useful for the MCP
 		// server in HTTP mode.
 		ServerAddress string
@@ -41,9 +56,131 @@ type Resources struct {
 		// HAProxyURL is the URL of the HAProxy instance. This is useful for the MCP
 		// server in HTTP mode.
 		HAProxyURL string
+		// ConfigFile is the path of the JSON file with the operator-defined
+		// configuration (e.g. report templates).
+		ConfigFile string
+		// Language is the language of the tool descriptions and result messages
+		// (e.g. "es"). Texts without translation are served in English.
+		Language string
+		// DefaultProjectID is the project used by the tools when the project_id
+		// argument is omitted and the session has no default project. This is
+		// useful for the MCP server in STDIO mode.
+		DefaultProjectID int64
+		// AllowedProjectIDs restricts the tools to the entities of these projects,
+		// so the server can be piloted on a few projects. When empty, all projects
+		// are allowed.
+		AllowedProjectIDs []int64
+		// Finance enables the tools and result fields with financial data (e.g.
+		// rates, costs and budgets), independently of the read-only mode.
+		Finance bool
+		// CompanyID binds the sessions to the projects and people of a client
+		// company, for client-facing agents. Zero doesn't bind them.
+		CompanyID int64
+		// SessionTokenBudget is the approximate number of response tokens of a
+		// session before the list tools switch to a summary mode. Zero or negative
+		// disables the budget.
+		SessionTokenBudget int
+		// Passthrough makes the get and list tools return the Teamwork API
+		// responses as is, without web links, reducing the CPU and memory used by
+		// large responses.
+		Passthrough bool
+		// CompactResponses removes the null and empty fields from the results of
+		// the get and list tools, shrinking them without losing information.
+		CompactResponses bool
+		// StringIDs rewrites the numeric IDs of the tool results as strings, for
+		// JavaScript clients that lose the precision of large IDs.
+		StringIDs bool
+		// Timezone is the IANA timezone of the installation (e.g.
+		// "Europe/Dublin"). When set, the tool results include the timestamps in
+		// this timezone next to the UTC ones.
+		Timezone string
+		// ExportMemoryLimit is the size in bytes above which large results (e.g.
+		// reports) are written to a temporary file, exposed as an MCP resource,
+		// instead of being kept in memory. Zero or negative uses the default.
+		ExportMemoryLimit int
+		// MaxConcurrentRequests is the maximum number of concurrent requests to
+		// Teamwork API per installation. The requests above the limit are queued.
+		// Zero or negative disables the limit.
+		MaxConcurrentRequests int
+		// WriteLogSize is the number of write requests to Teamwork API recorded
+		// per installation, by hash, to audit duplicated mutations. Zero or
+		// negative disables the log.
+		WriteLogSize int
 		// BearerToken is the bearer token to be used to authenticate with Teamwork
 		// API. This is useful for the MCP server in STDIO mode.
 		BearerToken string
+		// HTTPToken is the token the clients of the http and sse transports of the
+		// STDIO binary must send as a bearer token, as the requests run with the
+		// credentials of the server. When empty, those transports reject all
+		// requests.
+		HTTPToken string
+		// HTTPAllowedHosts are the hosts, besides the loopback ones, accepted in
+		// the Host and Origin headers by the http and sse transports of the STDIO
+		// binary, preventing DNS rebinding attacks.
+		HTTPAllowedHosts []string
+		// DemoBearerToken is the bearer token of a demo installation, used to serve
+		// unauthenticated sessions with read-only tools. When empty, the demo mode
+		// is disabled. This is useful for the MCP server in HTTP mode.
+		DemoBearerToken string
+		// IPAllowlist contains the IP addresses or CIDR ranges allowed to reach the
+		// server. When empty, all clients are allowed. This is useful for the MCP
+		// server in HTTP mode.
+		IPAllowlist []string
+		// MaxRequestSize is the maximum size in bytes of a request body (JSON-RPC
+		// message). Zero or negative disables the limit. This is useful for the MCP
+		// server in HTTP mode.
+		MaxRequestSize int64
+		// CORS contains the configuration for browser-based MCP clients. This is
+		// useful for the MCP server in HTTP mode.
+		CORS struct {
+			// AllowedOrigins is the list of origins allowed to connect, where "*"
+			// allows any origin. When empty, CORS is disabled.
+			AllowedOrigins []string
+			// AllowedHeaders is the list of request headers allowed in cross-origin
+			// requests.
+			AllowedHeaders []string
+			// AllowCredentials indicates if cross-origin requests can include
+			// credentials (cookies or HTTP authentication).
+			AllowCredentials bool
+			// MaxAge is the number of seconds the preflight response can be cached.
+			MaxAge int
+		}
+		// TLS contains the configuration to serve over HTTPS, optionally requiring
+		// client certificates (mTLS). This is useful for the MCP server in HTTP
+		// mode.
+		TLS struct {
+			// CertFile is the path of the PEM encoded server certificate. When empty,
+			// the server listens over plain HTTP.
+			CertFile string
+			// KeyFile is the path of the PEM encoded server private key.
+			KeyFile string
+			// ClientCAFile is the path of the PEM encoded CA bundle used to verify
+			// client certificates. When set, clients must present a valid
+			// certificate.
+			ClientCAFile string
+		}
+		// JWT contains the configuration to validate signed JWTs locally, instead
+		// of resolving each bearer token with Teamwork API. This is useful for the
+		// MCP server in HTTP mode.
+		JWT struct {
+			// JWKSURL is the URL of the JWKS endpoint with the signing keys. When
+			// empty, JWT validation is disabled.
+			JWKSURL string
+			// Issuer is the expected "iss" claim. When empty, it isn't checked.
+			Issuer string
+			// Audience is the expected "aud" claim. When empty, it isn't checked.
+			Audience string
+		}
+		// Slack contains the configuration to notify the write operations in a
+		// Slack channel.
+		Slack struct {
+			// WebhookURL is the Slack incoming webhook URL. When empty, the
+			// notifications are disabled.
+			WebhookURL string
+			// Tools are the write tools that notify. When empty, all write tools
+			// notify.
+			Tools []string
+		}
 		// Log contains the logging configuration.
 		Log struct {
 			// Format is the format of the logs. It can be "json" or "text".
@@ -75,16 +212,53 @@ type Resources struct {
 	}
 }
 
-func newResources() Resources {
+func newResources() (Resources, error) {
 	var resources Resources
+	var errs []error
 	resources.Info.Version = getEnv("TW_MCP_VERSION", Version)
+	resources.Info.Revision = buildRevision()
 	resources.Info.ServerAddress = getEnv("TW_MCP_SERVER_ADDRESS", ":8080")
 	resources.Info.Environment = getEnv("TW_MCP_ENV", "dev")
 	resources.Info.AWSRegion = getEnv("TW_MCP_AWS_REGION", "us-east-1")
 	resources.Info.MCPURL = strings.TrimSuffix(getEnv("TW_MCP_URL", "https://mcp.ai.teamwork.com"), "/")
 	resources.Info.APIURL = strings.TrimSuffix(getEnv("TW_MCP_API_URL", "https://teamwork.com"), "/")
 	resources.Info.HAProxyURL = getEnv("TW_MCP_HAPROXY_URL", "")
+	resources.Info.ConfigFile = getEnv("TW_MCP_CONFIG_FILE", "")
+	resources.Info.Language = getEnv("TW_MCP_LANGUAGE", i18n.DefaultLanguage)
+	resources.Info.DefaultProjectID, _ = strconv.ParseInt(getEnv("TW_MCP_DEFAULT_PROJECT_ID", "0"), 10, 64)
+	resources.Info.AllowedProjectIDs = parseIDList(getEnv("TW_MCP_ALLOWED_PROJECT_IDS", ""))
+	resources.Info.Finance = !strings.EqualFold(getEnv("TW_MCP_FINANCE", "true"), "false")
+	resources.Info.CompanyID, errs = parseEnvInt64(errs, "TW_MCP_COMPANY_ID", 0)
+	if resources.Info.CompanyID < 0 {
+		errs = append(errs, errors.New("invalid TW_MCP_COMPANY_ID: must be a positive company ID"))
+	}
+	resources.Info.SessionTokenBudget, _ = strconv.Atoi(getEnv("TW_MCP_SESSION_TOKEN_BUDGET", "0"))
+	resources.Info.Passthrough = strings.EqualFold(getEnv("TW_MCP_PASSTHROUGH", "false"), "true")
+	resources.Info.CompactResponses = !strings.EqualFold(getEnv("TW_MCP_COMPACT_RESPONSES", "true"), "false")
+	resources.Info.StringIDs = strings.EqualFold(getEnv("TW_MCP_STRING_IDS", "false"), "true")
+	resources.Info.Timezone = getEnv("TW_MCP_TIMEZONE", "")
+	resources.Info.ExportMemoryLimit, _ = strconv.Atoi(getEnv("TW_MCP_EXPORT_MEMORY_LIMIT", "0"))
+	resources.Info.MaxConcurrentRequests, _ = strconv.Atoi(getEnv("TW_MCP_MAX_CONCURRENT_REQUESTS", "10"))
+	resources.Info.WriteLogSize, _ = strconv.Atoi(getEnv("TW_MCP_WRITE_LOG_SIZE", "0"))
 	resources.Info.BearerToken = getEnv("TW_MCP_BEARER_TOKEN", "")
+	resources.Info.HTTPToken = getEnv("TW_MCP_HTTP_TOKEN", "")
+	resources.Info.HTTPAllowedHosts = splitEnvList(getEnv("TW_MCP_HTTP_ALLOWED_HOSTS", ""))
+	resources.Info.DemoBearerToken = getEnv("TW_MCP_DEMO_BEARER_TOKEN", "")
+	resources.Info.IPAllowlist = splitEnvList(getEnv("TW_MCP_IP_ALLOWLIST", ""))
+	resources.Info.MaxRequestSize, errs = parseEnvInt64(errs, "TW_MCP_MAX_REQUEST_SIZE", 4194304)
+	resources.Info.CORS.AllowedOrigins = splitEnvList(getEnv("TW_MCP_CORS_ALLOWED_ORIGINS", ""))
+	resources.Info.CORS.AllowedHeaders = splitEnvList(getEnv("TW_MCP_CORS_ALLOWED_HEADERS",
+		"Authorization,Content-Type,Accept,Last-Event-ID,Mcp-Session-Id,Mcp-Protocol-Version,X-MCP-Toolsets,X-MCP-Readonly,X-MCP-Finance,X-MCP-Company-ID"))
+	resources.Info.CORS.AllowCredentials = strings.EqualFold(getEnv("TW_MCP_CORS_ALLOW_CREDENTIALS", "false"), "true")
+	resources.Info.CORS.MaxAge, _ = strconv.Atoi(getEnv("TW_MCP_CORS_MAX_AGE", "600"))
+	resources.Info.TLS.CertFile = getEnv("TW_MCP_TLS_CERT_FILE", "")
+	resources.Info.TLS.KeyFile = getEnv("TW_MCP_TLS_KEY_FILE", "")
+	resources.Info.TLS.ClientCAFile = getEnv("TW_MCP_TLS_CLIENT_CA_FILE", "")
+	resources.Info.JWT.JWKSURL = getEnv("TW_MCP_JWT_JWKS_URL", "")
+	resources.Info.JWT.Issuer = getEnv("TW_MCP_JWT_ISSUER", "")
+	resources.Info.JWT.Audience = getEnv("TW_MCP_JWT_AUDIENCE", "")
+	resources.Info.Slack.WebhookURL = getEnv("TW_MCP_SLACK_WEBHOOK_URL", "")
+	resources.Info.Slack.Tools = splitEnvList(getEnv("TW_MCP_SLACK_NOTIFY_TOOLS", ""))
 	resources.Info.Log.Format = strings.ToLower(getEnv("TW_MCP_LOG_FORMAT", "text"))
 	resources.Info.Log.Level = strings.ToLower(getEnv("TW_MCP_LOG_LEVEL", "info"))
 	resources.Info.Log.SentryDSN = getEnv("TW_MCP_SENTRY_DSN", "")
@@ -98,7 +272,7 @@ func newResources() Resources {
 	resources.Info.DatadogAPM.Environment = getEnv("DD_ENV", resources.Info.Environment)
 	resources.Info.DatadogAPM.Version = getEnv("DD_VERSION", resources.Info.Version)
 
-	return resources
+	return resources, errors.Join(errs...)
 }
 
 // Logger returns the logger resource.
@@ -118,11 +292,82 @@ func (r *Resources) TeamworkEngine() *twapi.Engine {
 	return r.teamworkEngine
 }
 
+// WriteLog returns the log of the write requests sent to Teamwork API. It
+// returns nil when the log is disabled.
+func (r *Resources) WriteLog() *WriteLog {
+	return r.writeLog
+}
+
 // DeskClient returns the Teamwork Desk Client for use.
 func (r *Resources) DeskClient() *desksdk.Client {
 	return r.deskClient
 }
 
+// splitEnvList splits a comma-separated environment variable value, ignoring
+// empty entries.
+func splitEnvList(value string) []string {
+	var list []string
+	for item := range strings.SplitSeq(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// parseIDList parses a comma-separated list of IDs. The invalid entries are kept
+// as zero, which matches no entity, so a typo never lifts a restriction.
+func parseIDList(value string) []int64 {
+	var ids []int64
+	for _, item := range splitEnvList(value) {
+		id, _ := strconv.ParseInt(item, 10, 64)
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// DisplayTimezone returns the location of the configured timezone. It returns
+// nil when no timezone is configured or it's invalid.
+func (r *Resources) DisplayTimezone() *time.Location {
+	if r.Info.Timezone == "" {
+		return nil
+	}
+	location, err := time.LoadLocation(r.Info.Timezone)
+	if err != nil {
+		r.logger.Error("invalid timezone", slog.String("timezone", r.Info.Timezone),
+			slog.String("error", err.Error()))
+		return nil
+	}
+	return location
+}
+
+// Localizer returns the localizer of the configured language. It returns nil
+// when no translation is needed.
+func (r *Resources) Localizer() *i18n.Localizer {
+	return r.localizer
+}
+
+// FileConfig returns the operator-defined configuration loaded from the config
+// file.
+func (r *Resources) FileConfig() FileConfig {
+	return r.fileConfig
+}
+
+// parseEnvInt64 parses an integer environment variable, which is the fallback
+// when not set. Invalid values are appended to the errors instead of being ignored, as
+// falling back to zero disables the setting (e.g. a restriction) silently.
+func parseEnvInt64(errs []error, key string, fallback int64) (int64, []error) {
+	value := strings.TrimSpace(getEnv(key, ""))
+	if value == "" {
+		return fallback, errs
+	}
+	number, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, append(errs, fmt.Errorf("invalid %s: %w", key, err))
+	}
+	return number, errs
+}
+
 func getEnv(key, fallback string) string {
 	if value, ok := os.LookupEnv(key); ok {
 		return value
@@ -0,0 +1,55 @@
+package config_test
+
+import (
+	"io"
+	"testing"
+
+	"github.com/teamwork/mcp/internal/config"
+)
+
+func TestLoadInvalidEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		isError bool
+	}{{
+		name: "defaults",
+	}, {
+		name: "valid max request size",
+		env:  map[string]string{"TW_MCP_MAX_REQUEST_SIZE": "1048576"},
+	}, {
+		name:    "invalid max request size",
+		env:     map[string]string{"TW_MCP_MAX_REQUEST_SIZE": "4MB"},
+		isError: true,
+	}, {
+		name:    "invalid company ID",
+		env:     map[string]string{"TW_MCP_COMPANY_ID": "acme"},
+		isError: true,
+	}, {
+		name:    "negative company ID",
+		env:     map[string]string{"TW_MCP_COMPANY_ID": "-1"},
+		isError: true,
+	}, {
+		name:    "missing config file",
+		env:     map[string]string{"TW_MCP_CONFIG_FILE": "testdata/missing.json"},
+		isError: true,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			resources, teardown, err := config.Load(io.Discard)
+			if err == nil {
+				teardown()
+			}
+			if (err != nil) != tt.isError {
+				t.Fatalf("unexpected error state: %v", err)
+			}
+			if !tt.isError && resources.Info.MaxRequestSize <= 0 {
+				t.Errorf("expected the request size limit, got %d", resources.Info.MaxRequestSize)
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"runtime"
+	"runtime/debug"
+	"slices"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/toolsets"
+)
+
+// MethodServerInfoGet is the tool describing the versions of the server. It
+// isn't namespaced like the Teamwork tools, as it describes the server itself.
+const MethodServerInfoGet toolsets.Method = "get_server_info"
+
+// methodServer is the toolset of the tools describing the server. It can't be
+// selected, as it is enabled with all the toolsets.
+const methodServer toolsets.Method = "server"
+
+// serverInfoMetaKey is the initialize result "_meta" field with the versions of
+// the server.
+const serverInfoMetaKey = "com.teamwork/server"
+
+// ServerInfo describes the versions of the server, so clients can adapt their
+// behavior and bug reports include the exact build.
+type ServerInfo struct {
+	// Version is the release of the server (e.g. "1.4.0").
+	Version string `json:"version"`
+	// Revision is the git revision the server was built from, if known.
+	Revision string `json:"revision,omitempty"`
+	// CatalogVersion identifies the tools available in the server, including
+	// their input schemas. It changes whenever a tool is added, removed or
+	// changes its arguments.
+	CatalogVersion string `json:"catalogVersion"`
+	// GoVersion is the version of the Go toolchain that built the server.
+	GoVersion string `json:"goVersion"`
+}
+
+// newServerInfo describes the server with the tools of the groups.
+func newServerInfo(resources Resources, groups ...*toolsets.ToolsetGroup) ServerInfo {
+	return ServerInfo{
+		Version:        strings.TrimPrefix(resources.Info.Version, "v"),
+		Revision:       resources.Info.Revision,
+		CatalogVersion: catalogVersion(groups...),
+		GoVersion:      runtime.Version(),
+	}
+}
+
+// buildRevision returns the git revision set at build time or, if not set, the
+// revision recorded by the Go toolchain when building from a git checkout.
+func buildRevision() string {
+	if Revision != "" {
+		return Revision
+	}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	var revision string
+	var modified bool
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			revision = setting.Value
+		case "vcs.modified":
+			modified = setting.Value == "true"
+		}
+	}
+	if revision != "" && modified {
+		revision += "-dirty"
+	}
+	return revision
+}
+
+// catalogVersion hashes the names and input schemas of the tools available in
+// the groups, whether their toolsets are enabled or not, so it only depends on
+// the build and the read-only mode.
+func catalogVersion(groups ...*toolsets.ToolsetGroup) string {
+	schemas := make(map[string]string)
+	for _, group := range groups {
+		for _, toolset := range group.Toolsets {
+			for _, tool := range toolset.GetAvailableTools() {
+				// the schemas are built from literals, so they always encode
+				encoded, _ := json.Marshal(tool.Tool.InputSchema)
+				schemas[tool.Tool.Name] = string(encoded)
+			}
+		}
+	}
+
+	hash := sha256.New()
+	for _, name := range slices.Sorted(maps.Keys(schemas)) {
+		_, _ = fmt.Fprintf(hash, "%s\x00%s\x00", name, schemas[name])
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:12]
+}
+
+// serverInfoMiddleware adds the versions of the server to the initialize result
+// metadata.
+func serverInfoMiddleware(info ServerInfo) mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			result, err := next(ctx, method, req)
+			initializeResult, ok := result.(*mcp.InitializeResult)
+			if err != nil || !ok || initializeResult == nil {
+				return result, err
+			}
+			initializeResult.Meta = maps.Clone(initializeResult.Meta)
+			if initializeResult.Meta == nil {
+				initializeResult.Meta = make(mcp.Meta)
+			}
+			initializeResult.Meta[serverInfoMetaKey] = info
+			return initializeResult, nil
+		}
+	}
+}
+
+// serverToolsetGroup returns the group with the tools describing the server. It
+// is only enabled when all the toolsets of the groups are, so servers restricted
+// to some toolsets or tools only expose those.
+func serverToolsetGroup(info ServerInfo, groups ...*toolsets.ToolsetGroup) *toolsets.ToolsetGroup {
+	group := toolsets.NewToolsetGroup(false)
+	group.AddToolset(toolsets.NewToolset(methodServer, "Tools describing the MCP server itself").
+		AddReadTools(serverInfoGet(info)))
+
+	enabled := len(groups) > 0
+	for _, g := range groups {
+		enabled = enabled && g.IsEnabled(toolsets.MethodAll)
+	}
+	if enabled {
+		// the toolset was just added, so it always exists
+		_ = group.EnableToolset(methodServer)
+	}
+	return group
+}
+
+// serverInfoGet returns the tool describing the versions of the server.
+func serverInfoGet(info ServerInfo) toolsets.ToolWrapper {
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name: string(MethodServerInfoGet),
+			Description: "Get the version, git revision and tool catalog version of the Teamwork.com MCP server. " +
+				"Include them when reporting a problem with the tools.",
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "Get Server Info",
+				ReadOnlyHint: true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:       "object",
+				Properties: map[string]*jsonschema.Schema{},
+			},
+		},
+		Handler: func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			encoded, err := json.Marshal(info)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode server info: %w", err)
+			}
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: string(encoded)},
+				},
+				StructuredContent: info,
+			}, nil
+		},
+	}
+}
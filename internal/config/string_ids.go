@@ -0,0 +1,194 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/toolsets"
+)
+
+// toolStringIDsMiddleware rewrites the numeric IDs of the tool results as
+// strings, both in the text and the structured content. JavaScript clients
+// parse the numbers as float64, losing the precision of the IDs above 2^53. The
+// output schemas are widened accordingly by stringIDsTool.
+func toolStringIDsMiddleware() toolsets.ToolMiddleware {
+	return func(next mcp.ToolHandler) mcp.ToolHandler {
+		return func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := next(ctx, request)
+			if err != nil || result == nil || result.IsError {
+				return result, err
+			}
+			for _, content := range result.Content {
+				text, ok := content.(*mcp.TextContent)
+				if !ok {
+					continue
+				}
+				if rewritten, ok := stringifyIDs([]byte(text.Text)); ok {
+					text.Text = string(rewritten)
+				}
+			}
+			if result.StructuredContent != nil {
+				encoded, err := json.Marshal(result.StructuredContent)
+				if err != nil {
+					return nil, fmt.Errorf("failed to encode structured content: %w", err)
+				}
+				if rewritten, ok := stringifyIDs(encoded); ok {
+					result.StructuredContent = json.RawMessage(rewritten)
+				}
+			}
+			return result, err
+		}
+	}
+}
+
+// stringIDsTool widens the output schema of the tool, so the ID fields accept
+// the strings returned by toolStringIDsMiddleware. The schema is copied, as it
+// may be shared with other tools.
+func stringIDsTool(tool toolsets.ToolWrapper) toolsets.ToolWrapper {
+	schema, ok := tool.Tool.OutputSchema.(*jsonschema.Schema)
+	if !ok || schema == nil {
+		return tool
+	}
+	schema = schema.CloneSchemas()
+	widenIDSchemas(schema)
+
+	toolCopy := *tool.Tool
+	toolCopy.OutputSchema = schema
+	tool.Tool = &toolCopy
+	return tool
+}
+
+// widenIDSchemas allows strings in the integer ID fields found anywhere in the
+// schema.
+func widenIDSchemas(schema *jsonschema.Schema) {
+	if schema == nil {
+		return
+	}
+	for key, property := range schema.Properties {
+		switch {
+		case property == nil:
+			continue
+		case isIDField(key):
+			widenIntegerSchema(property)
+		case isIDListField(key):
+			widenIntegerSchema(property.Items)
+		}
+		widenIDSchemas(property)
+	}
+	for _, subschema := range slices.Concat(
+		[]*jsonschema.Schema{schema.Items, schema.AdditionalProperties},
+		schema.PrefixItems, schema.AllOf, schema.AnyOf, schema.OneOf,
+		slices.Collect(maps.Values(schema.Defs)),
+		slices.Collect(maps.Values(schema.Definitions)),
+		slices.Collect(maps.Values(schema.PatternProperties)),
+	) {
+		widenIDSchemas(subschema)
+	}
+}
+
+// widenIntegerSchema allows strings in an integer schema.
+func widenIntegerSchema(schema *jsonschema.Schema) {
+	switch {
+	case schema == nil:
+	case schema.Type == "integer":
+		schema.Type = ""
+		schema.Types = []string{"integer", "string"}
+	case slices.Contains(schema.Types, "integer") && !slices.Contains(schema.Types, "string"):
+		schema.Types = append(slices.Clone(schema.Types), "string")
+	}
+}
+
+// stringifyIDs rewrites the integer values of the ID fields of the JSON data as
+// strings. It returns the data unchanged, and false, when there are no numeric
+// IDs or the data isn't a JSON object or array.
+func stringifyIDs(data []byte) ([]byte, bool) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 || (trimmed[0] != '{' && trimmed[0] != '[') {
+		return data, false
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(trimmed))
+	decoder.UseNumber()
+	var decoded any
+	if err := decoder.Decode(&decoded); err != nil {
+		return data, false
+	}
+	if !stringifyIDValues(decoded) {
+		return data, false
+	}
+
+	var encoded bytes.Buffer
+	encoder := json.NewEncoder(&encoded)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(decoded); err != nil {
+		return data, false
+	}
+	return bytes.TrimSuffix(encoded.Bytes(), []byte("\n")), true
+}
+
+// stringifyIDValues rewrites the IDs found anywhere in the value. It reports
+// whether any ID was rewritten.
+func stringifyIDValues(value any) bool {
+	var changed bool
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			switch {
+			case isIDField(key):
+				if id, ok := integerID(item); ok {
+					v[key] = id
+					changed = true
+					continue
+				}
+			case isIDListField(key):
+				if items, ok := item.([]any); ok {
+					for i, listItem := range items {
+						if id, ok := integerID(listItem); ok {
+							items[i] = id
+							changed = true
+						}
+					}
+					continue
+				}
+			}
+			changed = stringifyIDValues(item) || changed
+		}
+	case []any:
+		for _, item := range v {
+			changed = stringifyIDValues(item) || changed
+		}
+	}
+	return changed
+}
+
+// isIDField checks if the field contains an ID (e.g. "id", "projectId" or
+// "project_id").
+func isIDField(key string) bool {
+	return key == "id" || strings.HasSuffix(key, "Id") || strings.HasSuffix(key, "ID") ||
+		strings.HasSuffix(key, "_id")
+}
+
+// isIDListField checks if the field contains a list of IDs (e.g. "tagIds" or
+// "tag_ids").
+func isIDListField(key string) bool {
+	return strings.HasSuffix(key, "Ids") || strings.HasSuffix(key, "IDs") || strings.HasSuffix(key, "_ids")
+}
+
+// integerID returns the integer value as a string.
+func integerID(value any) (string, bool) {
+	number, ok := value.(json.Number)
+	if !ok {
+		return "", false
+	}
+	if _, err := number.Int64(); err != nil {
+		return "", false
+	}
+	return number.String(), true
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// toolListCacheKey identifies a tools/list result. The result depends on the
+// features negotiated by the client and the requested page.
+type toolListCacheKey struct {
+	features ProtocolFeatures
+	cursor   string
+}
+
+// toolListCache caches the tools/list results, as building them from the large
+// tool schemas on every request adds measurable latency. It must be invalidated
+// when the tools change, e.g. when a toolset is enabled at runtime.
+type toolListCache struct {
+	mu      sync.RWMutex
+	results map[toolListCacheKey]*mcp.ListToolsResult
+}
+
+func newToolListCache() *toolListCache {
+	return &toolListCache{
+		results: make(map[toolListCacheKey]*mcp.ListToolsResult),
+	}
+}
+
+// invalidate drops the cached results.
+func (c *toolListCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.results)
+}
+
+// middleware answers the tools/list requests from the cache, storing the
+// results of the next handlers on a miss. It must be the outermost middleware
+// changing the tools/list results, so the cached results are final.
+func (c *toolListCache) middleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		listToolsRequest, ok := req.(*mcp.ListToolsRequest)
+		if !ok {
+			return next(ctx, method, req)
+		}
+		key := toolListCacheKey{
+			features: NegotiatedFeatures(nil),
+		}
+		if session, ok := req.GetSession().(*mcp.ServerSession); ok && session != nil {
+			key.features = NegotiatedFeatures(session.InitializeParams())
+		}
+		if listToolsRequest.Params != nil {
+			key.cursor = listToolsRequest.Params.Cursor
+		}
+
+		c.mu.RLock()
+		cached, ok := c.results[key]
+		c.mu.RUnlock()
+		if ok {
+			return copyListToolsResult(cached), nil
+		}
+
+		result, err := next(ctx, method, req)
+		if err != nil {
+			return result, err
+		}
+		if listToolsResult, ok := result.(*mcp.ListToolsResult); ok && listToolsResult != nil {
+			c.mu.Lock()
+			c.results[key] = copyListToolsResult(listToolsResult)
+			c.mu.Unlock()
+		}
+		return result, nil
+	}
+}
+
+// copyListToolsResult returns a copy of the result, so the cached tool list
+// isn't changed by the callers.
+func copyListToolsResult(result *mcp.ListToolsResult) *mcp.ListToolsResult {
+	copied := *result
+	copied.Tools = slices.Clone(result.Tools)
+	return &copied
+}
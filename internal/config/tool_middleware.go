@@ -0,0 +1,277 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/network"
+	"github.com/teamwork/mcp/internal/request"
+	"github.com/teamwork/mcp/internal/toolsets"
+	"golang.org/x/time/rate"
+)
+
+// correlationIDMetaKey is the tool result "_meta" field with the correlation
+// ID of the tool call.
+const correlationIDMetaKey = "com.teamwork/correlation_id"
+
+// toolCorrelationMiddleware generates a correlation ID for each tool call. It's
+// included in the logs, the Teamwork API requests and the tool results, so an
+// error reported by the agent can be traced to the exact API exchange.
+func toolCorrelationMiddleware() toolsets.ToolMiddleware {
+	return func(next mcp.ToolHandler) mcp.ToolHandler {
+		return func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			ctx, id := request.EnsureCorrelationID(ctx)
+			result, err := next(ctx, req)
+			if err != nil {
+				return result, fmt.Errorf("%w (correlation ID %s)", err, id)
+			}
+			return withCorrelationID(result, id), nil
+		}
+	}
+}
+
+// withCorrelationID returns a copy of the result with the correlation ID in
+// the metadata and, for errors, in the message.
+func withCorrelationID(result *mcp.CallToolResult, id string) *mcp.CallToolResult {
+	if result == nil {
+		return nil
+	}
+	tagged := *result
+	tagged.Meta = maps.Clone(result.Meta)
+	if tagged.Meta == nil {
+		tagged.Meta = make(mcp.Meta)
+	}
+	tagged.Meta[correlationIDMetaKey] = id
+
+	if result.IsError && len(result.Content) > 0 {
+		if text, ok := result.Content[0].(*mcp.TextContent); ok {
+			tagged.Content = slices.Clone(result.Content)
+			tagged.Content[0] = &mcp.TextContent{
+				Text:        fmt.Sprintf("%s (correlation ID %s)", text.Text, id),
+				Annotations: text.Annotations,
+				Meta:        text.Meta,
+			}
+		}
+	}
+	return &tagged
+}
+
+// toolLoggingMiddleware logs each tool call with its duration and outcome.
+func toolLoggingMiddleware(logger *slog.Logger) toolsets.ToolMiddleware {
+	return func(next mcp.ToolHandler) mcp.ToolHandler {
+		return func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			start := time.Now()
+			result, err := next(ctx, request)
+
+			attrs := []any{
+				slog.String("tool", request.Params.Name),
+				slog.Duration("duration", time.Since(start)),
+			}
+			switch {
+			case err != nil:
+				logger.ErrorContext(ctx, "tool call failed", append(attrs, slog.String("error", err.Error()))...)
+			case result != nil && result.IsError:
+				logger.DebugContext(ctx, "tool call returned an error", attrs...)
+			default:
+				logger.DebugContext(ctx, "tool call", attrs...)
+			}
+			return result, err
+		}
+	}
+}
+
+// toolTracingMiddleware creates a Datadog span for each tool call, so the
+// calls can be measured per tool.
+func toolTracingMiddleware() toolsets.ToolMiddleware {
+	return func(next mcp.ToolHandler) mcp.ToolHandler {
+		return func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			span, ctx := tracer.StartSpanFromContext(ctx, "mcp.tool", tracer.ResourceName(request.Params.Name))
+			result, err := next(ctx, request)
+			if result != nil && result.IsError {
+				span.SetTag("tool.error", true)
+			}
+			span.Finish(tracer.WithError(err))
+			return result, err
+		}
+	}
+}
+
+// throttledMetaKey is the progress notification "_meta" field describing the
+// throttling of the tool call.
+const throttledMetaKey = "com.teamwork/throttled"
+
+// toolThrottleMiddleware informs the client when the Teamwork API requests of
+// the tool call are queued because the installation reached the concurrency
+// limit. A progress notification is sent when the client asked for progress.
+func toolThrottleMiddleware(logger *slog.Logger) toolsets.ToolMiddleware {
+	return func(next mcp.ToolHandler) mcp.ToolHandler {
+		return func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var throttled float64
+			ctx = network.WithThrottleNotifier(ctx, func(installation string, queued int) {
+				throttled++
+				logger.Debug("tool call throttled",
+					slog.String("tool", request.Params.Name),
+					slog.String("installation", installation),
+					slog.Int("queued", queued),
+				)
+
+				progressToken := request.Params.GetProgressToken()
+				if progressToken == nil || request.Session == nil {
+					return
+				}
+				err := request.Session.NotifyProgress(ctx, &mcp.ProgressNotificationParams{
+					Meta: mcp.Meta{
+						throttledMetaKey: map[string]any{
+							"installation": installation,
+							"queued":       queued,
+						},
+					},
+					ProgressToken: progressToken,
+					Message: fmt.Sprintf("throttled: waiting for %d queued request(s) to respect the Teamwork.com "+
+						"API limits", queued),
+					Progress: throttled,
+				})
+				if err != nil {
+					logger.Debug("failed to notify throttling", slog.String("error", err.Error()))
+				}
+			})
+			return next(ctx, request)
+		}
+	}
+}
+
+// toolRetryBackoff is the delay before the first retry of a tool call, growing
+// linearly with each attempt.
+const toolRetryBackoff = 500 * time.Millisecond
+
+// toolSettingsMiddleware enforces the timeout, retries and rate limit defined
+// in the config file for each tool. Tools without settings are called as is.
+// Only the read-only tools, indexed by name, are retried: a write that timed out
+// or failed with a server error may still have been applied, so retrying it
+// could duplicate the change (e.g. create the same task twice).
+func toolSettingsMiddleware(
+	settings map[string]ToolSettings,
+	readOnlyTools map[string]bool,
+	logger *slog.Logger,
+) toolsets.ToolMiddleware {
+	limiters := newToolRateLimiters()
+	return func(next mcp.ToolHandler) mcp.ToolHandler {
+		return func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			toolSettings, ok := settings[request.Params.Name]
+			if !ok {
+				return next(ctx, request)
+			}
+
+			if toolSettings.RPS > 0 {
+				installation, _ := CustomerURLFromContext(ctx)
+				limiter := limiters.get(request.Params.Name, installation, toolSettings.RPS)
+				if err := limiter.Wait(ctx); err != nil {
+					return nil, fmt.Errorf("failed to wait for the tool rate limit: %w", err)
+				}
+			}
+
+			retries := toolSettings.Retries
+			if !readOnlyTools[request.Params.Name] {
+				retries = 0
+			}
+			for attempt := 1; ; attempt++ {
+				result, err := callToolWithTimeout(ctx, next, request, time.Duration(toolSettings.Timeout))
+				if attempt > retries || ctx.Err() != nil || !retryableToolCall(result, err) {
+					return result, err
+				}
+				logger.Debug("retrying tool call",
+					slog.String("tool", request.Params.Name),
+					slog.Int("attempt", attempt),
+				)
+				select {
+				case <-ctx.Done():
+					return result, err
+				case <-time.After(time.Duration(attempt) * toolRetryBackoff):
+				}
+			}
+		}
+	}
+}
+
+// callToolWithTimeout calls the tool, limiting its duration when the timeout is
+// positive. A timeout is reported as a tool error, so the client can adjust the
+// request (e.g. narrowing the filters).
+func callToolWithTimeout(
+	ctx context.Context,
+	next mcp.ToolHandler,
+	request *mcp.CallToolRequest,
+	timeout time.Duration,
+) (*mcp.CallToolResult, error) {
+	if timeout <= 0 {
+		return next(ctx, request)
+	}
+	attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	result, err := next(attemptCtx, request)
+	if ctx.Err() == nil && errors.Is(attemptCtx.Err(), context.DeadlineExceeded) {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: fmt.Sprintf("tool call timed out after %s", timeout)},
+			},
+			IsError: true,
+		}, nil
+	}
+	return result, err
+}
+
+// retryableToolCall reports if the tool call failed with a transient error: a
+// timeout, a network error or a Teamwork API server error. Errors advising
+// against retrying are never retried.
+func retryableToolCall(result *mcp.CallToolResult, err error) bool {
+	if err != nil {
+		return true
+	}
+	if result == nil || !result.IsError || len(result.Content) == 0 {
+		return false
+	}
+	if advice, ok := result.StructuredContent.(interface{ IsRetryable() bool }); ok && !advice.IsRetryable() {
+		return false
+	}
+	text, ok := result.Content[0].(*mcp.TextContent)
+	if !ok {
+		return false
+	}
+	return strings.HasPrefix(text.Text, "server error:") || strings.HasPrefix(text.Text, "tool call timed out")
+}
+
+// toolRateLimiters keeps the rate limiters of the tools for each installation,
+// so an installation calling a tool heavily doesn't limit the others.
+type toolRateLimiters struct {
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+}
+
+func newToolRateLimiters() *toolRateLimiters {
+	return &toolRateLimiters{
+		limiters: make(map[string]*rate.Limiter),
+	}
+}
+
+func (t *toolRateLimiters) get(tool, installation string, rps float64) *rate.Limiter {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := tool + " " + installation
+	limiter, ok := t.limiters[key]
+	if !ok {
+		limiter = rate.NewLimiter(rate.Limit(rps), int(math.Max(1, math.Ceil(rps))))
+		t.limiters[key] = limiter
+	}
+	return limiter
+}
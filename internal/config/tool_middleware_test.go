@@ -0,0 +1,308 @@
+package config_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/config"
+	"github.com/teamwork/mcp/internal/toolsets"
+)
+
+func TestToolSettingsRetries(t *testing.T) {
+	tests := []struct {
+		name          string
+		readOnly      bool
+		failure       func(ctx context.Context) (*mcp.CallToolResult, error)
+		expectedCalls int
+		isError       bool
+	}{{
+		name:          "read-only tool with server error",
+		readOnly:      true,
+		failure:       serverErrorResult,
+		expectedCalls: 2,
+	}, {
+		name:          "read-only tool with timeout",
+		readOnly:      true,
+		failure:       timeoutResult,
+		expectedCalls: 2,
+	}, {
+		name:          "read-only tool with invalid parameters",
+		readOnly:      true,
+		failure:       invalidParametersResult,
+		expectedCalls: 1,
+		isError:       true,
+	}, {
+		name:          "write tool with server error",
+		failure:       serverErrorResult,
+		expectedCalls: 1,
+		isError:       true,
+	}, {
+		name:          "write tool with timeout",
+		failure:       timeoutResult,
+		expectedCalls: 1,
+		isError:       true,
+	}}
+
+	configFile := filepath.Join(t.TempDir(), "config.json")
+	err := os.WriteFile(configFile, []byte(`{"tools":{"test-tool":{"timeout":"50ms","retries":2}}}`), 0o600)
+	if err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	t.Setenv("TW_MCP_CONFIG_FILE", configFile)
+	resources, teardown, err := config.Load(io.Discard)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	t.Cleanup(teardown)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int
+			tool := toolsets.ToolWrapper{
+				Tool: &mcp.Tool{
+					Name:        "test-tool",
+					Annotations: &mcp.ToolAnnotations{ReadOnlyHint: tt.readOnly},
+					InputSchema: &jsonschema.Schema{Type: "object"},
+				},
+				Handler: func(ctx context.Context, _ *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+					calls++
+					if calls == 1 {
+						return tt.failure(ctx)
+					}
+					return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "ok"}}}, nil
+				},
+			}
+			toolset := toolsets.NewToolset("test", "Test tools")
+			if tt.readOnly {
+				toolset.AddReadTools(tool)
+			} else {
+				toolset.AddWriteTools(tool)
+			}
+			group := toolsets.NewToolsetGroup(false)
+			group.AddToolset(toolset)
+			if err := group.EnableToolsets(toolsets.MethodAll); err != nil {
+				t.Fatalf("failed to enable toolsets: %v", err)
+			}
+
+			result := callTool(t, config.NewMCPServer(resources, group), "test-tool")
+			if result.IsError != tt.isError {
+				t.Errorf("unexpected error state %v: %v", result.IsError, result.Content)
+			}
+			if calls != tt.expectedCalls {
+				t.Errorf("expected %d calls, got %d", tt.expectedCalls, calls)
+			}
+		})
+	}
+}
+
+func TestToolThrottleNotification(t *testing.T) {
+	received := make(chan struct{}, 2)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		received <- struct{}{}
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	t.Setenv("TW_MCP_MAX_CONCURRENT_REQUESTS", "1")
+	resources, teardown, err := config.Load(io.Discard)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	t.Cleanup(teardown)
+
+	get := func(ctx context.Context) error {
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		if err != nil {
+			return err
+		}
+		response, err := resources.TeamworkHTTPClient().Do(request)
+		if err != nil {
+			return err
+		}
+		return response.Body.Close()
+	}
+
+	// the tool performs two parallel requests, so the second is queued
+	toolset := toolsets.NewToolset("test", "Test tools")
+	toolset.AddReadTools(toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name:        "test-tool",
+			Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+			InputSchema: &jsonschema.Schema{Type: "object"},
+		},
+		Handler: func(ctx context.Context, _ *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			first := make(chan error, 1)
+			go func() { first <- get(ctx) }()
+			<-received
+			if err := get(ctx); err != nil {
+				return nil, err
+			}
+			if err := <-first; err != nil {
+				return nil, err
+			}
+			return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "ok"}}}, nil
+		},
+	})
+	group := toolsets.NewToolsetGroup(false)
+	group.AddToolset(toolset)
+	if err := group.EnableToolsets(toolsets.MethodAll); err != nil {
+		t.Fatalf("failed to enable toolsets: %v", err)
+	}
+
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	serverSession, err := config.NewMCPServer(resources, group).Connect(t.Context(), serverTransport, nil)
+	if err != nil {
+		t.Fatalf("failed to connect to server: %v", err)
+	}
+	t.Cleanup(func() { _ = serverSession.Close() })
+
+	var notification *mcp.ProgressNotificationParams
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, &mcp.ClientOptions{
+		ProgressNotificationHandler: func(_ context.Context, request *mcp.ProgressNotificationClientRequest) {
+			// the first request completes once the client knows about the queue
+			if notification == nil {
+				notification = request.Params
+				close(release)
+			}
+		},
+	})
+	clientSession, err := client.Connect(t.Context(), clientTransport, nil)
+	if err != nil {
+		t.Fatalf("failed to connect client: %v", err)
+	}
+	t.Cleanup(func() { _ = clientSession.Close() })
+
+	result, err := clientSession.CallTool(t.Context(), &mcp.CallToolParams{
+		Meta:      mcp.Meta{"progressToken": "progress"},
+		Name:      "test-tool",
+		Arguments: map[string]any{},
+	})
+	if err != nil {
+		t.Fatalf("failed to call tool: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("tool failed to execute: %v", result.Content)
+	}
+
+	if notification == nil {
+		t.Fatal("expected a throttle notification")
+	}
+	if !strings.Contains(notification.Message, "throttled") || notification.ProgressToken != "progress" {
+		t.Errorf("unexpected notification: %+v", notification)
+	}
+	if _, ok := notification.Meta["com.teamwork/throttled"]; !ok {
+		t.Errorf("expected the throttling metadata, got %v", notification.Meta)
+	}
+}
+
+func serverErrorResult(context.Context) (*mcp.CallToolResult, error) {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: "server error: bad gateway"}},
+		IsError: true,
+	}, nil
+}
+
+func timeoutResult(ctx context.Context) (*mcp.CallToolResult, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func invalidParametersResult(context.Context) (*mcp.CallToolResult, error) {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: "invalid parameters: missing id"}},
+		IsError: true,
+	}, nil
+}
+
+func TestUnattendedToolMiddlewares(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   string
+		env      map[string]string
+		expected string
+	}{{
+		name:     "defaults",
+		expected: `{"email":"jane@example.com","userRate":10}`,
+	}, {
+		name:     "redaction",
+		config:   `{"redaction":{"emails":true}}`,
+		expected: `{"email":"[redacted]","userRate":10}`,
+	}, {
+		name:     "without finance",
+		env:      map[string]string{"TW_MCP_FINANCE": "false"},
+		expected: `{"email":"jane@example.com","userRate":"[redacted]"}`,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.config != "" {
+				configFile := filepath.Join(t.TempDir(), "config.json")
+				if err := os.WriteFile(configFile, []byte(tt.config), 0o600); err != nil {
+					t.Fatalf("failed to write config file: %v", err)
+				}
+				t.Setenv("TW_MCP_CONFIG_FILE", configFile)
+			}
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			resources, teardown, err := config.Load(io.Discard)
+			if err != nil {
+				t.Fatalf("failed to load config: %v", err)
+			}
+			t.Cleanup(teardown)
+
+			tools := toolsets.UseToolMiddlewares([]toolsets.ToolWrapper{{
+				Tool: &mcp.Tool{Name: "test-tool"},
+				Handler: func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+					return &mcp.CallToolResult{Content: []mcp.Content{
+						&mcp.TextContent{Text: `{"email":"jane@example.com","userRate":10}`},
+					}}, nil
+				},
+			}}, config.UnattendedToolMiddlewares(resources)...)
+			result, err := tools[0].Handler(t.Context(), &mcp.CallToolRequest{
+				Params: &mcp.CallToolParamsRaw{Name: "test-tool"},
+			})
+			if err != nil {
+				t.Fatalf("failed to call tool: %v", err)
+			}
+			if text := result.Content[0].(*mcp.TextContent).Text; text != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, text)
+			}
+		})
+	}
+}
+
+// callTool calls the tool through an in-memory client session.
+func callTool(t *testing.T, mcpServer *mcp.Server, name string) *mcp.CallToolResult {
+	t.Helper()
+
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	serverSession, err := mcpServer.Connect(t.Context(), serverTransport, nil)
+	if err != nil {
+		t.Fatalf("failed to connect to server: %v", err)
+	}
+	t.Cleanup(func() { _ = serverSession.Close() })
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	clientSession, err := client.Connect(t.Context(), clientTransport, nil)
+	if err != nil {
+		t.Fatalf("failed to connect client: %v", err)
+	}
+	t.Cleanup(func() { _ = clientSession.Close() })
+
+	result, err := clientSession.CallTool(t.Context(), &mcp.CallToolParams{Name: name, Arguments: map[string]any{}})
+	if err != nil {
+		t.Fatalf("failed to call tool: %v", err)
+	}
+	return result
+}
@@ -0,0 +1,19 @@
+package config
+
+import "context"
+
+type statelessTransportKey struct{}
+
+// WithStatelessTransport returns a new context indicating that the requests are
+// served by a stateless transport, where the session IDs are chosen by the
+// clients and not validated by the server.
+func WithStatelessTransport(ctx context.Context) context.Context {
+	return context.WithValue(ctx, statelessTransportKey{}, true)
+}
+
+// IsStatelessTransport reports whether the requests are served by a stateless
+// transport.
+func IsStatelessTransport(ctx context.Context) bool {
+	stateless, ok := ctx.Value(statelessTransportKey{}).(bool)
+	return ok && stateless
+}
@@ -0,0 +1,17 @@
+package config
+
+import "context"
+
+type userIDKey struct{}
+
+// WithUserID returns a new context with the ID of the authenticated user.
+func WithUserID(ctx context.Context, userID int64) context.Context {
+	return context.WithValue(ctx, userIDKey{}, userID)
+}
+
+// UserIDFromContext returns the ID of the authenticated user from the context,
+// if any.
+func UserIDFromContext(ctx context.Context) (int64, bool) {
+	userID, ok := ctx.Value(userIDKey{}).(int64)
+	return userID, ok
+}
@@ -0,0 +1,121 @@
+package config
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/teamwork/mcp/internal/request"
+	twapi "github.com/teamwork/twapi-go-sdk"
+)
+
+// WriteLogEntry is a write request sent to Teamwork API. The body isn't kept,
+// only its hash, so the log doesn't store the customer data.
+type WriteLogEntry struct {
+	// Hash is the SHA-256 of the method, path, query and body of the request.
+	// Requests with the same hash are the same mutation.
+	Hash          string    `json:"hash"`
+	Method        string    `json:"method"`
+	Path          string    `json:"path"`
+	CorrelationID string    `json:"correlationId,omitempty"`
+	Status        int       `json:"status,omitempty"`
+	SentAt        time.Time `json:"sentAt"`
+}
+
+// WriteLog records the hash of the write requests sent to Teamwork API per
+// installation, so the administrators can verify after the fact whether an
+// agent issued the same mutation more than once. Only the most recent entries
+// of each installation are kept.
+type WriteLog struct {
+	size int
+
+	mu            sync.Mutex
+	installations map[string][]WriteLogEntry
+}
+
+// NewWriteLog creates a log keeping up to size entries per installation. It
+// returns nil when size is zero or negative, disabling the log.
+func NewWriteLog(size int) *WriteLog {
+	if size <= 0 {
+		return nil
+	}
+	return &WriteLog{
+		size:          size,
+		installations: make(map[string][]WriteLogEntry),
+	}
+}
+
+// Middleware records the write requests sent by the engine. The read requests
+// are sent as is.
+func (l *WriteLog) Middleware(next twapi.HTTPClient) twapi.HTTPClient {
+	return twapi.HTTPClientFunc(func(req *http.Request) (*http.Response, error) {
+		switch req.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			return next.Do(req)
+		}
+
+		hash := sha256.New()
+		_, _ = io.WriteString(hash, req.Method+" "+req.URL.RequestURI()+"\n")
+		if req.Body != nil {
+			body, err := io.ReadAll(req.Body)
+			if err != nil {
+				return nil, err
+			}
+			_ = req.Body.Close()
+			req.Body = io.NopCloser(bytes.NewReader(body))
+			_, _ = hash.Write(body)
+		}
+
+		entry := WriteLogEntry{
+			Hash:   hex.EncodeToString(hash.Sum(nil)),
+			Method: req.Method,
+			Path:   req.URL.Path,
+			SentAt: time.Now().UTC(),
+		}
+		entry.CorrelationID, _ = request.CorrelationIDFromContext(req.Context())
+
+		resp, err := next.Do(req)
+		if resp != nil {
+			entry.Status = resp.StatusCode
+		}
+		installation, _ := CustomerURLFromContext(req.Context())
+		l.add(installation, entry)
+		return resp, err
+	})
+}
+
+func (l *WriteLog) add(installation string, entry WriteLogEntry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entries := append(l.installations[installation], entry)
+	if len(entries) > l.size {
+		entries = slices.Delete(entries, 0, len(entries)-l.size)
+	}
+	l.installations[installation] = entries
+}
+
+// Entries returns the write requests of the installation sent since the given
+// time, from the oldest to the newest.
+func (l *WriteLog) Entries(installation string, since time.Time) []WriteLogEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var entries []WriteLogEntry
+	for _, entry := range l.installations[installation] {
+		if !entry.SentAt.Before(since) {
+			entries = append(entries, entry)
+		}
+	}
+	// the entries are added once the response is received, so concurrent
+	// requests can be out of order
+	slices.SortStableFunc(entries, func(a, b WriteLogEntry) int {
+		return a.SentAt.Compare(b.SentAt)
+	})
+	return entries
+}
@@ -0,0 +1,115 @@
+package helpers
+
+import (
+	"context"
+	"encoding/json"
+)
+
+const (
+	// AutoPaginationMaxPageSize is the maximum page size accepted by Teamwork
+	// API.
+	AutoPaginationMaxPageSize = 500
+	// AutoPaginationMaxPageBytes is the default size cap, in bytes, of the
+	// pages fetched while auto-paginating.
+	AutoPaginationMaxPageBytes = 1 << 20
+)
+
+// AutoPagination fetches all pages of a list, adapting the page size to the
+// size of the responses: it grows up to the API maximum while the pages are
+// small, minimizing the round-trips, and shrinks when the pages approach the
+// size cap.
+//
+// The API paginates by page number, so the page size is only changed when the
+// items already fetched are a multiple of the new size, keeping the offset of
+// the next page.
+type AutoPagination struct {
+	// PageSize is the page size of the first page.
+	PageSize int64
+	// MaxPageSize is the maximum page size. Defaults to
+	// AutoPaginationMaxPageSize.
+	MaxPageSize int64
+	// MaxPageBytes is the size cap of a page. Defaults to
+	// AutoPaginationMaxPageBytes.
+	MaxPageBytes int
+	// MaxItems is the maximum number of items fetched. Zero means no limit.
+	MaxItems int
+}
+
+// PageFetcher fetches a page of a list, reporting if there are more pages.
+type PageFetcher[T any] func(ctx context.Context, page, pageSize int64) (items []T, hasMore bool, err error)
+
+// FetchAllPages fetches the pages of the list until there are no more items or
+// the maximum number of items is reached, reporting if the list was truncated.
+func FetchAllPages[T any](ctx context.Context, pagination AutoPagination, fetch PageFetcher[T]) ([]T, bool, error) {
+	pagination.defaults()
+
+	var items []T
+	pageSize := pagination.PageSize
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, false, err
+		}
+		page := int64(len(items))/pageSize + 1
+		pageItems, hasMore, err := fetch(ctx, page, pageSize)
+		if err != nil {
+			return nil, false, err
+		}
+		items = append(items, pageItems...)
+
+		if pagination.MaxItems > 0 && len(items) >= pagination.MaxItems {
+			truncated := hasMore || len(items) > pagination.MaxItems
+			return items[:pagination.MaxItems], truncated, nil
+		}
+		if !hasMore || len(pageItems) == 0 {
+			return items, false, nil
+		}
+		pageSize = pagination.nextPageSize(pageSize, int64(len(items)), pageBytes(pageItems), len(pageItems))
+	}
+}
+
+func (p *AutoPagination) defaults() {
+	if p.MaxPageSize <= 0 {
+		p.MaxPageSize = AutoPaginationMaxPageSize
+	}
+	if p.MaxPageBytes <= 0 {
+		p.MaxPageBytes = AutoPaginationMaxPageBytes
+	}
+	if p.PageSize <= 0 {
+		p.PageSize = 50
+	}
+	p.PageSize = min(p.PageSize, p.MaxPageSize)
+}
+
+// nextPageSize returns the size of the next page from the size of the last
+// one, keeping the offset of the items already fetched.
+func (p *AutoPagination) nextPageSize(pageSize, fetched int64, bytes, count int) int64 {
+	desired := pageSize
+	switch itemBytes := max(bytes/count, 1); {
+	case bytes > p.MaxPageBytes*3/4:
+		// approaching the cap: aim at half of it
+		desired = max(int64(p.MaxPageBytes/2/itemBytes), 1)
+	case bytes < p.MaxPageBytes/4:
+		desired = min(pageSize*2, int64(p.MaxPageBytes/2/itemBytes))
+	}
+	desired = min(max(desired, 1), p.MaxPageSize)
+	if remaining := int64(p.MaxItems) - fetched; p.MaxItems > 0 && remaining < desired {
+		desired = max(remaining, 1)
+	}
+
+	// the largest size not above the desired one that keeps the offset
+	for size := desired; size > 0; size-- {
+		if fetched%size == 0 {
+			return size
+		}
+	}
+	return pageSize
+}
+
+// pageBytes estimates the response size of the page items.
+func pageBytes[T any](items []T) int {
+	encoded, err := json.Marshal(items)
+	if err != nil {
+		return 0
+	}
+	return len(encoded)
+}
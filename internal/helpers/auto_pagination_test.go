@@ -0,0 +1,94 @@
+package helpers_test
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/teamwork/mcp/internal/helpers"
+)
+
+func TestFetchAllPages(t *testing.T) {
+	tests := []struct {
+		name          string
+		total         int
+		itemSize      int
+		pagination    helpers.AutoPagination
+		wantItems     int
+		wantTruncated bool
+		checkSizes    func(t *testing.T, sizes []int64)
+	}{{
+		name:       "small items grow the page size",
+		total:      1000,
+		itemSize:   10,
+		pagination: helpers.AutoPagination{PageSize: 50},
+		wantItems:  1000,
+		checkSizes: func(t *testing.T, sizes []int64) {
+			if slices.Max(sizes) <= sizes[0] {
+				t.Errorf("expected the page size to grow, got %v", sizes)
+			}
+			if slices.Max(sizes) > helpers.AutoPaginationMaxPageSize {
+				t.Errorf("expected the page size to respect the API maximum, got %v", sizes)
+			}
+		},
+	}, {
+		name:       "large items shrink the page size",
+		total:      200,
+		itemSize:   1000,
+		pagination: helpers.AutoPagination{PageSize: 50, MaxPageBytes: 20000},
+		wantItems:  200,
+		checkSizes: func(t *testing.T, sizes []int64) {
+			if len(sizes) < 2 || sizes[1] >= sizes[0] {
+				t.Errorf("expected the page size to shrink, got %v", sizes)
+			}
+		},
+	}, {
+		name:          "maximum items",
+		total:         1000,
+		itemSize:      10,
+		pagination:    helpers.AutoPagination{PageSize: 50, MaxItems: 120},
+		wantItems:     120,
+		wantTruncated: true,
+	}, {
+		name:       "single page",
+		total:      10,
+		itemSize:   10,
+		pagination: helpers.AutoPagination{PageSize: 50},
+		wantItems:  10,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list := make([]string, tt.total)
+			for i := range list {
+				list[i] = fmt.Sprintf("%0*d", tt.itemSize, i)
+			}
+
+			var sizes []int64
+			fetch := func(_ context.Context, page, pageSize int64) ([]string, bool, error) {
+				sizes = append(sizes, pageSize)
+				start := min(int((page-1)*pageSize), len(list))
+				end := min(start+int(pageSize), len(list))
+				return list[start:end], end < len(list), nil
+			}
+
+			items, truncated, err := helpers.FetchAllPages(t.Context(), tt.pagination, fetch)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(items) != tt.wantItems {
+				t.Errorf("expected %d items, got %d", tt.wantItems, len(items))
+			}
+			if truncated != tt.wantTruncated {
+				t.Errorf("expected truncated %t, got %t", tt.wantTruncated, truncated)
+			}
+			if !slices.Equal(items, list[:tt.wantItems]) {
+				t.Error("expected the items in order without gaps or duplicates")
+			}
+			if tt.checkSizes != nil {
+				tt.checkSizes(t, sizes)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package helpers_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/teamwork/mcp/internal/config"
+	"github.com/teamwork/mcp/internal/helpers"
+	twapi "github.com/teamwork/twapi-go-sdk"
+)
+
+// benchmarkEntities is the number of entities in the benchmark payloads, close
+// to the largest page returned by the Teamwork.com API.
+const benchmarkEntities = 500
+
+// benchmarkTask mirrors the shape of a task returned by the Teamwork.com API.
+type benchmarkTask struct {
+	ID          int64          `json:"id"`
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Priority    string         `json:"priority"`
+	Progress    int64          `json:"progress"`
+	StartDate   *time.Time     `json:"startDate"`
+	DueDate     *time.Time     `json:"dueDate"`
+	TagIDs      []int64        `json:"tagIds"`
+	AssigneeIDs []int64        `json:"assigneeUserIds"`
+	Tasklist    map[string]any `json:"tasklist"`
+}
+
+type benchmarkTaskList struct {
+	Meta struct {
+		Page struct {
+			PageOffset int64 `json:"pageOffset"`
+			PageSize   int64 `json:"pageSize"`
+			HasMore    bool  `json:"hasMore"`
+		} `json:"page"`
+	} `json:"meta"`
+	Tasks    []benchmarkTask `json:"tasks"`
+	Included map[string]any  `json:"included"`
+}
+
+func benchmarkTaskListPayload() benchmarkTaskList {
+	date := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var list benchmarkTaskList
+	list.Meta.Page.PageSize = benchmarkEntities
+	list.Meta.Page.HasMore = true
+	for i := range benchmarkEntities {
+		list.Tasks = append(list.Tasks, benchmarkTask{
+			ID:          int64(i + 1),
+			Name:        fmt.Sprintf("Task %d", i+1),
+			Description: "Review the quarterly report and send the feedback to the finance team before the deadline.",
+			Priority:    "high",
+			Progress:    int64(i % 100),
+			StartDate:   &date,
+			DueDate:     &date,
+			TagIDs:      []int64{1, 2, 3},
+			AssigneeIDs: []int64{10, 20},
+			Tasklist:    map[string]any{"id": 99, "type": "tasklists"},
+		})
+	}
+	list.Included = map[string]any{
+		"tasklists": map[string]any{"99": map[string]any{"id": 99, "name": "Backlog"}},
+	}
+	return list
+}
+
+func BenchmarkParamGroup(b *testing.B) {
+	arguments := map[string]any{
+		"search_term":       "report",
+		"tag_ids":           []any{float64(1), float64(2), float64(3)},
+		"assignee_user_ids": []any{float64(10), float64(20)},
+		"match_all_tags":    true,
+		"start_date":        "2024-01-01",
+		"due_date":          "2024-12-31",
+		"priority":          "high",
+		"page":              float64(2),
+		"page_size":         float64(50),
+	}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		var (
+			searchTerm   string
+			tagIDs       []int64
+			assigneeIDs  []int64
+			matchAllTags *bool
+			startDate    twapi.Date
+			dueDate      twapi.Date
+			priority     string
+			page         int64
+			pageSize     int64
+		)
+		err := helpers.ParamGroup(arguments,
+			helpers.OptionalParam(&searchTerm, "search_term"),
+			helpers.OptionalNumericListParam(&tagIDs, "tag_ids"),
+			helpers.OptionalNumericListParam(&assigneeIDs, "assignee_user_ids"),
+			helpers.OptionalPointerParam(&matchAllTags, "match_all_tags"),
+			helpers.OptionalDateParam(&startDate, "start_date"),
+			helpers.OptionalDateParam(&dueDate, "due_date"),
+			helpers.OptionalParam(&priority, "priority",
+				helpers.RestrictValues("low", "medium", "high"),
+			),
+			helpers.OptionalNumericParam(&page, "page"),
+			helpers.OptionalNumericParam(&pageSize, "page_size"),
+		)
+		if err != nil {
+			b.Fatalf("failed to parse parameters: %v", err)
+		}
+	}
+}
+
+func BenchmarkNewToolResultJSON(b *testing.B) {
+	payload := benchmarkTaskListPayload()
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := helpers.NewToolResultJSON(payload); err != nil {
+			b.Fatalf("failed to encode result: %v", err)
+		}
+	}
+}
+
+func BenchmarkWebLinker(b *testing.B) {
+	ctx := config.WithCustomerURL(context.Background(), "https://example.teamwork.com")
+	data, err := json.Marshal(benchmarkTaskListPayload())
+	if err != nil {
+		b.Fatalf("failed to encode payload: %v", err)
+	}
+	builder := helpers.WebLinkerWithIDPathBuilder("app/tasks")
+
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	for b.Loop() {
+		helpers.WebLinker(ctx, data, builder)
+	}
+}
+
+func BenchmarkWebLinkedEntitiesMeta(b *testing.B) {
+	ctx := config.WithCustomerURL(context.Background(), "https://example.teamwork.com")
+	data, err := json.Marshal(benchmarkTaskListPayload())
+	if err != nil {
+		b.Fatalf("failed to encode payload: %v", err)
+	}
+	data = helpers.WebLinker(ctx, data, helpers.WebLinkerWithIDPathBuilder("app/tasks"))
+
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	for b.Loop() {
+		if helpers.WebLinkedEntitiesMeta(data) == nil {
+			b.Fatal("expected web linked entities")
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package helpers_test
+
+import (
+	"testing"
+
+	"github.com/teamwork/mcp/internal/helpers"
+)
+
+func TestCompactJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected string
+		changed  bool
+	}{{
+		name: "empty fields",
+		data: `{"tasks":[{"id":1,"name":"Example","description":"","parentTask":null,"tags":[],` +
+			`"progress":0,"private":false,"meta":{"webLink":null}},null]}`,
+		expected: `{"tasks":[{"id":1,"name":"Example","private":false,"progress":0},null]}`,
+		changed:  true,
+	}, {
+		name:     "nothing to remove",
+		data:     `{"task":{"id":1,"name":"Example"}}`,
+		expected: `{"task":{"id":1,"name":"Example"}}`,
+	}, {
+		name:     "not JSON",
+		data:     `Task created successfully with ID 123`,
+		expected: `Task created successfully with ID 123`,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			compacted, changed := helpers.CompactJSON([]byte(tt.data))
+			if string(compacted) != tt.expected || changed != tt.changed {
+				t.Errorf("expected %s (changed %t), got %s (changed %t)", tt.expected, tt.changed, compacted, changed)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ExpectedUpdatedAtParam is the name of the optional parameter of the update
+// tools used for optimistic concurrency.
+const ExpectedUpdatedAtParam = "expected_updated_at"
+
+// ExpectedUpdatedAtSchema returns the schema of the optimistic concurrency
+// parameter for the given entity.
+func ExpectedUpdatedAtSchema(entity string) *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:   "string",
+		Format: "date-time",
+		Description: fmt.Sprintf("The last update date of the %s when it was retrieved, in RFC3339 format. When "+
+			"provided, the update is aborted with a conflict error if the %s was changed since then, avoiding "+
+			"overwriting concurrent edits.", entity, entity),
+	}
+}
+
+// Conflict describes an entity that was changed after it was retrieved by the
+// client.
+type Conflict struct {
+	Error             string     `json:"error"`
+	Entity            string     `json:"entity"`
+	ID                int64      `json:"id"`
+	ExpectedUpdatedAt time.Time  `json:"expectedUpdatedAt"`
+	CurrentUpdatedAt  *time.Time `json:"currentUpdatedAt"`
+}
+
+// CheckConflict compares the current last update date of an entity with the
+// one expected by the client. It returns a structured error result when the
+// entity was changed since then, or nil otherwise. The dates are compared with
+// a second precision, as that is the precision returned by the API.
+func CheckConflict(entity string, id int64, expected time.Time, current *time.Time) *mcp.CallToolResult {
+	if current == nil || !current.Truncate(time.Second).After(expected.Truncate(time.Second)) {
+		return nil
+	}
+
+	conflict := Conflict{
+		Error:             "conflict",
+		Entity:            entity,
+		ID:                id,
+		ExpectedUpdatedAt: expected,
+		CurrentUpdatedAt:  current,
+	}
+	encoded, err := json.Marshal(conflict)
+	if err != nil {
+		return NewToolResultTextError(fmt.Sprintf("conflict: %s %d was updated at %s", entity, id, current))
+	}
+	return &mcp.CallToolResult{
+		IsError: true,
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: fmt.Sprintf("conflict: %s %d was updated at %s, after the expected %s. Retrieve it again "+
+					"and review the changes before updating.", entity, id, current.Format(time.RFC3339),
+					expected.Format(time.RFC3339)),
+			},
+			&mcp.TextContent{
+				Text: string(encoded),
+			},
+		},
+		StructuredContent: conflict,
+	}
+}
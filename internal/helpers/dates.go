@@ -0,0 +1,127 @@
+package helpers
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// DateDisplaySuffix is the suffix of the fields added next to the normalized
+// timestamps, with the timestamp in the display timezone.
+const DateDisplaySuffix = "Display"
+
+// dateDisplayLayout is the layout of the timestamps in the display timezone.
+const dateDisplayLayout = "Mon 2 Jan 2006 15:04 MST"
+
+// timestampLayouts are the timestamp formats returned by the Teamwork API
+// endpoints. The timestamps without offset are in UTC.
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+}
+
+// NormalizeDates rewrites the timestamps of the JSON data in the RFC3339
+// format in UTC, as the API versions return them in different formats and
+// offsets, which confuses the date calculations. The dates of the fields named
+// like a date (e.g. "dueDate") in the compact format of the legacy API
+// ("20060102") are rewritten in the ISO 8601 format.
+//
+// When the display location is not nil, a field with the DateDisplaySuffix is
+// added next to each timestamp, with a human-readable timestamp in that
+// location.
+//
+// It returns the data unchanged, and false, when nothing was rewritten or the
+// data isn't a JSON object or array.
+func NormalizeDates(data []byte, display *time.Location) ([]byte, bool) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 || (trimmed[0] != '{' && trimmed[0] != '[') {
+		return data, false
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(trimmed))
+	decoder.UseNumber()
+	var decoded any
+	if err := decoder.Decode(&decoded); err != nil {
+		return data, false
+	}
+	if !normalizeDateValues(decoded, display) {
+		return data, false
+	}
+
+	var encoded bytes.Buffer
+	encoder := json.NewEncoder(&encoded)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(decoded); err != nil {
+		return data, false
+	}
+	return bytes.TrimSuffix(encoded.Bytes(), []byte("\n")), true
+}
+
+// normalizeDateValues rewrites the dates found anywhere in the value. It
+// reports whether anything was rewritten.
+func normalizeDateValues(value any, display *time.Location) bool {
+	var changed bool
+	switch v := value.(type) {
+	case map[string]any:
+		displays := make(map[string]string)
+		for key, item := range v {
+			text, ok := item.(string)
+			if !ok {
+				changed = normalizeDateValues(item, display) || changed
+				continue
+			}
+			if timestamp, ok := parseTimestamp(text); ok {
+				if normalized := timestamp.UTC().Format(time.RFC3339); normalized != text {
+					v[key] = normalized
+					changed = true
+				}
+				if display != nil && !strings.HasSuffix(key, DateDisplaySuffix) {
+					displays[key+DateDisplaySuffix] = timestamp.In(display).Format(dateDisplayLayout)
+				}
+				continue
+			}
+			if date, ok := parseCompactDate(key, text); ok {
+				v[key] = date.Format(time.DateOnly)
+				changed = true
+			}
+		}
+		for key, text := range displays {
+			if _, ok := v[key]; !ok {
+				v[key] = text
+				changed = true
+			}
+		}
+	case []any:
+		for _, item := range v {
+			changed = normalizeDateValues(item, display) || changed
+		}
+	}
+	return changed
+}
+
+// parseTimestamp parses the text in any of the timestamp formats of the API.
+func parseTimestamp(text string) (time.Time, bool) {
+	// quick check, avoiding parsing texts that can't be timestamps
+	if len(text) < len("2006-01-02 15:04:05") || text[4] != '-' || text[7] != '-' {
+		return time.Time{}, false
+	}
+	for _, layout := range timestampLayouts {
+		if timestamp, err := time.Parse(layout, text); err == nil {
+			return timestamp, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// parseCompactDate parses the dates in the compact format of the legacy API,
+// only for the fields named like a date, as other fields (e.g. codes) may
+// contain 8 digits.
+func parseCompactDate(key, text string) (time.Time, bool) {
+	if len(text) != len("20060102") || !strings.Contains(strings.ToLower(key), "date") {
+		return time.Time{}, false
+	}
+	date, err := time.Parse("20060102", text)
+	return date, err == nil
+}
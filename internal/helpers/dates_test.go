@@ -0,0 +1,56 @@
+package helpers_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/teamwork/mcp/internal/helpers"
+)
+
+func TestNormalizeDates(t *testing.T) {
+	dublin, err := time.LoadLocation("Europe/Dublin")
+	if err != nil {
+		t.Skipf("timezone database not available: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		data     string
+		display  *time.Location
+		expected string
+		changed  bool
+	}{{
+		name:     "offset timestamp",
+		data:     `{"task":{"id":1,"createdAt":"2025-03-01T10:00:00+02:00"}}`,
+		expected: `{"task":{"createdAt":"2025-03-01T08:00:00Z","id":1}}`,
+		changed:  true,
+	}, {
+		name:     "legacy formats",
+		data:     `[{"last-changed-on":"2025-03-01 10:00:00","dueDate":"20250305","code":"20250305"}]`,
+		expected: `[{"code":"20250305","dueDate":"2025-03-05","last-changed-on":"2025-03-01T10:00:00Z"}]`,
+		changed:  true,
+	}, {
+		name:     "already normalized",
+		data:     `{"createdAt":"2025-03-01T08:00:00Z","startDate":"2025-03-01"}`,
+		expected: `{"createdAt":"2025-03-01T08:00:00Z","startDate":"2025-03-01"}`,
+	}, {
+		name:     "display timezone",
+		data:     `{"createdAt":"2025-07-01T08:00:00Z"}`,
+		display:  dublin,
+		expected: `{"createdAt":"2025-07-01T08:00:00Z","createdAtDisplay":"Tue 1 Jul 2025 09:00 IST"}`,
+		changed:  true,
+	}, {
+		name:     "not JSON",
+		data:     `Task created successfully with ID 123`,
+		expected: `Task created successfully with ID 123`,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			normalized, changed := helpers.NormalizeDates([]byte(tt.data), tt.display)
+			if string(normalized) != tt.expected || changed != tt.changed {
+				t.Errorf("expected %s (changed %t), got %s (changed %t)", tt.expected, tt.changed, normalized, changed)
+			}
+		})
+	}
+}
@@ -1,13 +1,26 @@
 package helpers
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	twapi "github.com/teamwork/twapi-go-sdk"
 )
 
+// Suggested wait times before retrying a request, when the API doesn't provide
+// one in the Retry-After header.
+const (
+	retryAfterRateLimited = time.Minute
+	retryAfterUnavailable = 30 * time.Second
+	retryAfterServerError = 5 * time.Second
+)
+
 // NewToolResultTextError creates a new MCP tool result representing an error with the
 // given text message.
 func NewToolResultTextError(text string) *mcp.CallToolResult {
@@ -21,23 +34,116 @@ func NewToolResultTextError(text string) *mcp.CallToolResult {
 	}
 }
 
+// APIError describes an error returned by the Teamwork API, advising the
+// client if the request can be retried and how long to wait before doing it.
+type APIError struct {
+	Error             string `json:"error"`
+	StatusCode        int    `json:"statusCode"`
+	Retryable         bool   `json:"retryable"`
+	RetryAfterSeconds int64  `json:"retryAfterSeconds,omitempty"`
+}
+
+// IsRetryable reports if the request can be retried. It allows checking the
+// advice without depending on this package.
+func (e APIError) IsRetryable() bool {
+	return e.Retryable
+}
+
+// ClassifyAPIError classifies the HTTP error returned by the Teamwork API as
+// retryable or not. The suggested wait time comes from the Retry-After header
+// when available, or from heuristics based on the status code otherwise.
+func ClassifyAPIError(httpErr *twapi.HTTPError, now time.Time) APIError {
+	apiErr := APIError{StatusCode: httpErr.StatusCode}
+
+	var wait time.Duration
+	switch status := httpErr.StatusCode; {
+	case status == http.StatusTooManyRequests:
+		apiErr.Error, apiErr.Retryable, wait = "rate_limited", true, retryAfterRateLimited
+	case status == http.StatusRequestTimeout:
+		apiErr.Error, apiErr.Retryable, wait = "timeout", true, retryAfterServerError
+	case status == http.StatusServiceUnavailable:
+		apiErr.Error, apiErr.Retryable, wait = "server_error", true, retryAfterUnavailable
+	case status == http.StatusNotImplemented || status == http.StatusHTTPVersionNotSupported:
+		apiErr.Error = "server_error"
+	case status >= 500:
+		apiErr.Error, apiErr.Retryable, wait = "server_error", true, retryAfterServerError
+	case status >= 400:
+		apiErr.Error = "bad_request"
+	default:
+		apiErr.Error = "unexpected_status"
+	}
+	if !apiErr.Retryable {
+		return apiErr
+	}
+
+	if retryAfter, ok := parseRetryAfter(httpErr.Headers.Get("Retry-After"), now); ok {
+		wait = retryAfter
+	}
+	apiErr.RetryAfterSeconds = int64((wait + time.Second - 1) / time.Second)
+	return apiErr
+}
+
+// parseRetryAfter parses the Retry-After header, which can be a number of
+// seconds or an HTTP date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Duration(max(seconds, 0)) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0), true
+	}
+	return 0, false
+}
+
 // HandleAPIError processes an error returned from the Teamwork API and converts
-// it into an appropriate MCP tool result or error.
+// it into an appropriate MCP tool result or error. The HTTP errors are returned
+// with a structured payload advising if the request can be retried.
 func HandleAPIError(err error, label string) (*mcp.CallToolResult, error) {
 	if err == nil {
 		return nil, nil
 	}
 
 	var httpErr *twapi.HTTPError
-	if errors.As(err, &httpErr) {
-		switch {
-		case httpErr.StatusCode >= 500:
-			return NewToolResultTextError(fmt.Sprintf("server error: %s", err.Error())), nil
-		case httpErr.StatusCode >= 400:
-			return NewToolResultTextError(fmt.Sprintf("bad request: %s", err.Error())), nil
-		default:
-			return NewToolResultTextError(fmt.Sprintf("unexpected HTTP status: %s", err.Error())), nil
-		}
-	}
-	return nil, fmt.Errorf("%s: %w", label, err)
+	if !errors.As(err, &httpErr) {
+		return nil, fmt.Errorf("%s: %w", label, err)
+	}
+
+	apiErr := ClassifyAPIError(httpErr, time.Now())
+	var text string
+	switch apiErr.Error {
+	case "rate_limited":
+		text = fmt.Sprintf("rate limited: %s", err.Error())
+	case "timeout", "server_error":
+		text = fmt.Sprintf("server error: %s", err.Error())
+	case "bad_request":
+		text = fmt.Sprintf("bad request: %s", err.Error())
+	default:
+		text = fmt.Sprintf("unexpected HTTP status: %s", err.Error())
+	}
+	if apiErr.Retryable {
+		text += fmt.Sprintf(". The request can be retried after %d seconds.", apiErr.RetryAfterSeconds)
+	} else {
+		text += ". Retrying the same request will not help."
+	}
+
+	encoded, encodeErr := json.Marshal(apiErr)
+	if encodeErr != nil {
+		return NewToolResultTextError(text), nil
+	}
+	return &mcp.CallToolResult{
+		IsError: true,
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: text,
+			},
+			&mcp.TextContent{
+				Text: string(encoded),
+			},
+		},
+		StructuredContent: apiErr,
+	}, nil
 }
@@ -0,0 +1,66 @@
+package helpers_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/teamwork/mcp/internal/helpers"
+	twapi "github.com/teamwork/twapi-go-sdk"
+)
+
+func TestClassifyAPIError(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		statusCode int
+		retryAfter string
+		expected   helpers.APIError
+	}{{
+		name:       "rate limited with seconds",
+		statusCode: http.StatusTooManyRequests,
+		retryAfter: "15",
+		expected:   helpers.APIError{Error: "rate_limited", StatusCode: 429, Retryable: true, RetryAfterSeconds: 15},
+	}, {
+		name:       "rate limited with date",
+		statusCode: http.StatusTooManyRequests,
+		retryAfter: now.Add(90 * time.Second).Format(http.TimeFormat),
+		expected:   helpers.APIError{Error: "rate_limited", StatusCode: 429, Retryable: true, RetryAfterSeconds: 90},
+	}, {
+		name:       "rate limited without header",
+		statusCode: http.StatusTooManyRequests,
+		expected:   helpers.APIError{Error: "rate_limited", StatusCode: 429, Retryable: true, RetryAfterSeconds: 60},
+	}, {
+		name:       "service unavailable",
+		statusCode: http.StatusServiceUnavailable,
+		expected:   helpers.APIError{Error: "server_error", StatusCode: 503, Retryable: true, RetryAfterSeconds: 30},
+	}, {
+		name:       "bad gateway",
+		statusCode: http.StatusBadGateway,
+		retryAfter: "invalid",
+		expected:   helpers.APIError{Error: "server_error", StatusCode: 502, Retryable: true, RetryAfterSeconds: 5},
+	}, {
+		name:       "not implemented",
+		statusCode: http.StatusNotImplemented,
+		expected:   helpers.APIError{Error: "server_error", StatusCode: 501},
+	}, {
+		name:       "not found",
+		statusCode: http.StatusNotFound,
+		retryAfter: "10",
+		expected:   helpers.APIError{Error: "bad_request", StatusCode: 404},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers := make(http.Header)
+			if tt.retryAfter != "" {
+				headers.Set("Retry-After", tt.retryAfter)
+			}
+			apiErr := helpers.ClassifyAPIError(&twapi.HTTPError{StatusCode: tt.statusCode, Headers: headers}, now)
+			if apiErr != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, apiErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package helpers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"maps"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/toolsets"
+)
+
+// PaginationKey is the root field added to the list tool responses with the
+// pagination metadata.
+const PaginationKey = "pagination"
+
+// Pagination is the standard pagination metadata of the list tools.
+type Pagination struct {
+	Page     int64 `json:"page"`
+	PageSize int64 `json:"page_size"`
+	HasMore  bool  `json:"has_more"`
+	// NextPageArguments are the arguments to call the same tool for the next
+	// page. It is only set when there are more results.
+	NextPageArguments map[string]any `json:"next_page_arguments,omitempty"`
+}
+
+// PaginationParams describes the pagination parameters of a group of list
+// tools.
+type PaginationParams struct {
+	// Page is the name of the page number parameter.
+	Page string
+	// PageSize is the name of the page size parameter.
+	PageSize string
+	// DefaultPageSize is the page size used by the API when the parameter isn't
+	// provided.
+	DefaultPageSize int64
+}
+
+// Paginate wraps the list tools, identified by the page parameter in the input
+// schema, adding the standard pagination metadata to their responses. The API
+// responses only report if there are more results in "meta.page.hasMore", so
+// the page and page size are taken from the request arguments.
+func Paginate(tools []toolsets.ToolWrapper, params PaginationParams) []toolsets.ToolWrapper {
+	return toolsets.WrapToolList(tools, func(tool toolsets.ToolWrapper) toolsets.ToolWrapper {
+		if !hasInputProperty(tool.Tool, params.Page) {
+			return tool
+		}
+		handler := tool.Handler
+		tool.Handler = func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := handler(ctx, request)
+			if err != nil || result == nil || result.IsError {
+				return result, err
+			}
+			var arguments map[string]any
+			if request.Params != nil && len(request.Params.Arguments) > 0 {
+				if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+					return result, nil
+				}
+			}
+			addPagination(result, arguments, params)
+			return result, nil
+		}
+		return tool
+	})
+}
+
+// NewPagination builds the pagination metadata of a response from the request
+// arguments.
+func NewPagination(arguments map[string]any, params PaginationParams, hasMore bool) Pagination {
+	pagination := Pagination{
+		Page:     1,
+		PageSize: params.DefaultPageSize,
+		HasMore:  hasMore,
+	}
+	if page, ok := toFloat64(arguments[params.Page]); ok && page > 0 {
+		pagination.Page = int64(page)
+	}
+	if pageSize, ok := toFloat64(arguments[params.PageSize]); ok && pageSize > 0 {
+		pagination.PageSize = int64(pageSize)
+	}
+	if hasMore {
+		pagination.NextPageArguments = maps.Clone(arguments)
+		if pagination.NextPageArguments == nil {
+			pagination.NextPageArguments = make(map[string]any)
+		}
+		pagination.NextPageArguments[params.Page] = pagination.Page + 1
+		pagination.NextPageArguments[params.PageSize] = pagination.PageSize
+	}
+	return pagination
+}
+
+func addPagination(result *mcp.CallToolResult, arguments map[string]any, params PaginationParams) {
+	for _, content := range result.Content {
+		text, ok := content.(*mcp.TextContent)
+		if !ok {
+			continue
+		}
+		decoder := json.NewDecoder(strings.NewReader(text.Text))
+		decoder.UseNumber()
+		var decoded map[string]any
+		if err := decoder.Decode(&decoded); err != nil {
+			continue
+		}
+		meta, _ := decoded["meta"].(map[string]any)
+		page, _ := meta["page"].(map[string]any)
+		hasMore, ok := page["hasMore"].(bool)
+		if !ok {
+			continue
+		}
+		decoded[PaginationKey] = NewPagination(arguments, params, hasMore)
+
+		var encoded bytes.Buffer
+		encoder := json.NewEncoder(&encoded)
+		encoder.SetEscapeHTML(false)
+		if err := encoder.Encode(decoded); err == nil {
+			text.Text = strings.TrimSuffix(encoded.String(), "\n")
+		}
+		return
+	}
+}
+
+func hasInputProperty(tool *mcp.Tool, name string) bool {
+	schema, ok := tool.InputSchema.(*jsonschema.Schema)
+	if !ok || schema == nil {
+		return false
+	}
+	_, ok = schema.Properties[name]
+	return ok
+}
@@ -0,0 +1,303 @@
+package helpers
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/config"
+	"github.com/teamwork/mcp/internal/toolsets"
+)
+
+const (
+	// DefaultSpillMemoryLimit is the default size, in bytes, above which a
+	// SpillWriter moves the payload to a temporary file.
+	DefaultSpillMemoryLimit = 4 << 20
+	// DefaultSpillTTL is the default time a spilled payload can be read through
+	// its resource.
+	DefaultSpillTTL = time.Hour
+
+	// spillExpireInterval is the maximum interval between the removals of the
+	// expired payloads.
+	spillExpireInterval = time.Minute
+)
+
+// SpillStore keeps the payloads spilled to disk by the SpillWriters, exposing
+// them as MCP resources under the URI prefix. Large exports are written to a
+// temporary file instead of accumulating in memory, so small containers aren't
+// killed for running out of memory.
+type SpillStore struct {
+	uriPrefix   string
+	memoryLimit int
+	ttl         time.Duration
+
+	mu    sync.Mutex
+	files map[string]spilledFile
+}
+
+type spilledFile struct {
+	path     string
+	mimeType string
+	// owner is the customer URL of the installation that created the payload.
+	// In HTTP mode the stores are shared by all customers, so the payload is
+	// only served to sessions of the same installation.
+	owner   string
+	created time.Time
+}
+
+// NewSpillStore creates a store exposing the spilled payloads under the URI
+// prefix (e.g. "twprojects://exports/"). Payloads above memoryLimit bytes are
+// spilled and kept for ttl. Zero or negative values use the defaults.
+//
+// The expired payloads are removed periodically, so the temporary files don't
+// pile up on disk when no other export is created or read.
+func NewSpillStore(uriPrefix string, memoryLimit int, ttl time.Duration) *SpillStore {
+	if memoryLimit <= 0 {
+		memoryLimit = DefaultSpillMemoryLimit
+	}
+	if ttl <= 0 {
+		ttl = DefaultSpillTTL
+	}
+	store := &SpillStore{
+		uriPrefix:   uriPrefix,
+		memoryLimit: memoryLimit,
+		ttl:         ttl,
+		files:       make(map[string]spilledFile),
+	}
+	go store.expireEvery(min(ttl, spillExpireInterval))
+	return store
+}
+
+// NewWriter creates a writer keeping the payload in memory until it reaches
+// the memory limit of the store. The payload is owned by the installation of
+// the context.
+func (s *SpillStore) NewWriter(ctx context.Context) *SpillWriter {
+	owner, _ := config.CustomerURLFromContext(ctx)
+	return &SpillWriter{store: s, owner: owner}
+}
+
+// ResourceTemplate returns the resource template serving the spilled payloads.
+func (s *SpillStore) ResourceTemplate() toolsets.ServerResourceTemplate {
+	return toolsets.NewServerResourceTemplate(&mcp.ResourceTemplate{
+		Name:        "export",
+		Title:       "Export",
+		Description: "Large tool results, stored temporarily instead of being returned inline.",
+		URITemplate: s.uriPrefix + "{id}",
+	}, s.read)
+}
+
+func (s *SpillStore) read(ctx context.Context, request *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	uri := request.Params.URI
+	id, ok := strings.CutPrefix(uri, s.uriPrefix)
+	if !ok {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+
+	s.mu.Lock()
+	s.expire()
+	file, ok := s.files[id]
+	s.mu.Unlock()
+	owner, _ := config.CustomerURLFromContext(ctx)
+	if !ok || file.owner != owner {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+
+	content, err := os.ReadFile(file.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read export: %w", err)
+	}
+	contents := &mcp.ResourceContents{
+		URI:      uri,
+		MIMEType: file.mimeType,
+	}
+	if isTextMIMEType(file.mimeType) {
+		contents.Text = string(content)
+	} else {
+		contents.Blob = content
+	}
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{contents},
+	}, nil
+}
+
+// isTextMIMEType reports whether the payloads of the MIME type are returned as
+// text, instead of binary blobs.
+func isTextMIMEType(mimeType string) bool {
+	return strings.HasPrefix(mimeType, "text/") || mimeType == "application/json"
+}
+
+// add keeps the spilled file of the owner, returning its resource URI.
+func (s *SpillStore) add(path, mimeType, owner string) (string, error) {
+	var random [16]byte
+	if _, err := rand.Read(random[:]); err != nil {
+		return "", fmt.Errorf("failed to generate export ID: %w", err)
+	}
+	id := hex.EncodeToString(random[:])
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expire()
+	s.files[id] = spilledFile{
+		path:     path,
+		mimeType: mimeType,
+		owner:    owner,
+		created:  time.Now(),
+	}
+	return s.uriPrefix + id, nil
+}
+
+// expire removes the files older than the TTL. It must be called with the lock
+// held.
+func (s *SpillStore) expire() {
+	for id, file := range s.files {
+		if time.Since(file.created) > s.ttl {
+			_ = os.Remove(file.path)
+			delete(s.files, id)
+		}
+	}
+}
+
+// expireEvery removes the expired files at every interval. The stores live as
+// long as the MCP servers, so it runs until the process exits.
+func (s *SpillStore) expireEvery(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		s.mu.Lock()
+		s.expire()
+		s.mu.Unlock()
+	}
+}
+
+// SpillWriter accumulates a payload in memory, moving it to a temporary file
+// once it reaches the memory limit of its store.
+type SpillWriter struct {
+	store  *SpillStore
+	owner  string
+	buffer bytes.Buffer
+	file   *os.File
+	err    error
+}
+
+// Write writes the data to memory or, when the memory limit is reached, to the
+// temporary file.
+func (w *SpillWriter) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	if w.file == nil && w.buffer.Len()+len(p) > w.store.memoryLimit {
+		if !w.spill() {
+			return 0, w.err
+		}
+	}
+	if w.file == nil {
+		return w.buffer.Write(p)
+	}
+	n, err := w.file.Write(p)
+	if err != nil {
+		w.err = fmt.Errorf("failed to write export file: %w", err)
+		return n, w.err
+	}
+	return n, nil
+}
+
+// Result creates the tool result of the payload. Payloads in memory are
+// returned inline, while spilled payloads are returned as a link to their
+// resource, identified by the name and MIME type.
+func (w *SpillWriter) Result(name, mimeType string) (*mcp.CallToolResult, error) {
+	if w.err != nil {
+		w.discard()
+		return nil, w.err
+	}
+	if w.file == nil {
+		return NewToolResultText("%s", w.buffer.String()), nil
+	}
+	return w.link(name, mimeType, func(size int64, uri string) string {
+		return fmt.Sprintf("The result is too large to be returned inline (%d bytes). It is available for %s in the "+
+			"resource %s.", size, w.store.ttl, uri)
+	})
+}
+
+// Link creates the tool result of the payload as a link to its resource, no
+// matter its size, identified by the name and MIME type. It's used for
+// documents that aren't meant to be returned inline, such as PDF files.
+func (w *SpillWriter) Link(name, mimeType string) (*mcp.CallToolResult, error) {
+	if w.err == nil && w.file == nil {
+		w.spill()
+	}
+	if w.err != nil {
+		w.discard()
+		return nil, w.err
+	}
+	return w.link(name, mimeType, func(size int64, uri string) string {
+		return fmt.Sprintf("The document %s (%d bytes) is available for %s in the resource %s.", name, size,
+			w.store.ttl, uri)
+	})
+}
+
+// link stores the spilled file, returning a tool result with the message built
+// from its size and URI, and the link to its resource.
+func (w *SpillWriter) link(
+	name, mimeType string,
+	message func(size int64, uri string) string,
+) (*mcp.CallToolResult, error) {
+	info, err := w.file.Stat()
+	if err != nil {
+		w.discard()
+		return nil, fmt.Errorf("failed to read export file: %w", err)
+	}
+	if err := w.file.Close(); err != nil {
+		w.discard()
+		return nil, fmt.Errorf("failed to close export file: %w", err)
+	}
+	uri, err := w.store.add(w.file.Name(), mimeType, w.owner)
+	if err != nil {
+		w.discard()
+		return nil, err
+	}
+	size := info.Size()
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: message(size, uri),
+			},
+			&mcp.ResourceLink{
+				URI:      uri,
+				Name:     name,
+				MIMEType: mimeType,
+				Size:     &size,
+			},
+		},
+	}, nil
+}
+
+// spill moves the payload in memory to a temporary file, reporting whether it
+// succeeded.
+func (w *SpillWriter) spill() bool {
+	file, err := os.CreateTemp("", "tw-mcp-export-*")
+	if err != nil {
+		w.err = fmt.Errorf("failed to create export file: %w", err)
+		return false
+	}
+	w.file = file
+	if _, err := w.buffer.WriteTo(file); err != nil {
+		w.err = fmt.Errorf("failed to write export file: %w", err)
+		return false
+	}
+	return true
+}
+
+// discard removes the temporary file, if any.
+func (w *SpillWriter) discard() {
+	if w.file != nil {
+		_ = w.file.Close()
+		_ = os.Remove(w.file.Name())
+	}
+}
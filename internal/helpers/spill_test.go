@@ -0,0 +1,164 @@
+package helpers_test
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/config"
+	"github.com/teamwork/mcp/internal/helpers"
+	"github.com/teamwork/mcp/internal/toolsets"
+)
+
+func TestSpillWriter(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	store := helpers.NewSpillStore("test://exports/", 10, 0)
+
+	t.Run("small payloads are returned inline", func(t *testing.T) {
+		writer := store.NewWriter(t.Context())
+		if _, err := writer.Write([]byte("small")); err != nil {
+			t.Fatalf("failed to write: %v", err)
+		}
+		result, err := writer.Result("small.md", "text/markdown")
+		if err != nil {
+			t.Fatalf("failed to create result: %v", err)
+		}
+		if text := result.Content[0].(*mcp.TextContent).Text; text != "small" {
+			t.Errorf("expected inline payload, got %q", text)
+		}
+	})
+
+	t.Run("documents are always linked", func(t *testing.T) {
+		writer := store.NewWriter(t.Context())
+		if _, err := writer.Write([]byte("%PDF")); err != nil {
+			t.Fatalf("failed to write: %v", err)
+		}
+		result, err := writer.Link("small.pdf", "application/pdf")
+		if err != nil {
+			t.Fatalf("failed to create result: %v", err)
+		}
+		if len(result.Content) != 2 {
+			t.Fatalf("expected a text and a resource link, got %d contents", len(result.Content))
+		}
+		link, ok := result.Content[1].(*mcp.ResourceLink)
+		if !ok || link.MIMEType != "application/pdf" || link.Size == nil || *link.Size != 4 {
+			t.Errorf("unexpected resource link %+v", result.Content[1])
+		}
+	})
+
+	t.Run("large payloads are spilled to a resource", func(t *testing.T) {
+		writer := store.NewWriter(t.Context())
+		for range 3 {
+			if _, err := writer.Write([]byte("large payload\n")); err != nil {
+				t.Fatalf("failed to write: %v", err)
+			}
+		}
+		result, err := writer.Result("large.md", "text/markdown")
+		if err != nil {
+			t.Fatalf("failed to create result: %v", err)
+		}
+		if len(result.Content) != 2 {
+			t.Fatalf("expected a text and a resource link, got %d contents", len(result.Content))
+		}
+		link, ok := result.Content[1].(*mcp.ResourceLink)
+		if !ok {
+			t.Fatalf("unexpected content type %T", result.Content[1])
+		}
+		if !strings.HasPrefix(link.URI, "test://exports/") || link.Size == nil || *link.Size != 42 {
+			t.Errorf("unexpected resource link %+v", link)
+		}
+
+		resource, err := readSpilled(t, t.Context(), store, link.URI)
+		if err != nil {
+			t.Fatalf("failed to read resource: %v", err)
+		}
+		if expected := strings.Repeat("large payload\n", 3); resource.Contents[0].Text != expected {
+			t.Errorf("expected %q, got %q", expected, resource.Contents[0].Text)
+		}
+	})
+}
+
+func TestSpillStoreInstallations(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	store := helpers.NewSpillStore("test://exports/", 10, 0)
+
+	ctx := config.WithCustomerURL(t.Context(), "https://a.teamwork.com")
+	writer := store.NewWriter(ctx)
+	if _, err := writer.Write([]byte("%PDF")); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+	result, err := writer.Link("small.pdf", "application/pdf")
+	if err != nil {
+		t.Fatalf("failed to create result: %v", err)
+	}
+	uri := result.Content[1].(*mcp.ResourceLink).URI
+
+	if _, err := readSpilled(t, ctx, store, uri); err != nil {
+		t.Errorf("failed to read the export of the installation: %v", err)
+	}
+	otherCtx := config.WithCustomerURL(t.Context(), "https://b.teamwork.com")
+	if _, err := readSpilled(t, otherCtx, store, uri); err == nil {
+		t.Error("expected the export to be hidden from another installation")
+	}
+}
+
+func TestSpillStoreExpire(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	store := helpers.NewSpillStore("test://exports/", 10, 10*time.Millisecond)
+
+	writer := store.NewWriter(t.Context())
+	if _, err := writer.Write([]byte("%PDF")); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+	if _, err := writer.Link("small.pdf", "application/pdf"); err != nil {
+		t.Fatalf("failed to create result: %v", err)
+	}
+
+	// the files are removed without any other export being created or read
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		files, err := os.ReadDir(os.Getenv("TMPDIR"))
+		if err != nil {
+			t.Fatalf("failed to list temporary files: %v", err)
+		}
+		if len(files) == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the expired export to be removed, found %d files", len(files))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// readSpilled reads the resource of the store, with the session of the server
+// using the given context.
+func readSpilled(
+	t *testing.T,
+	ctx context.Context,
+	store *helpers.SpillStore,
+	uri string,
+) (*mcp.ReadResourceResult, error) {
+	t.Helper()
+
+	mcpServer := mcp.NewServer(&mcp.Implementation{Name: "test-server", Version: "1.0.0"}, nil)
+	toolset := toolsets.NewToolset("exports", "Exports").AddResourceTemplates(store.ResourceTemplate())
+	toolset.Enabled = true
+	toolset.RegisterResourcesTemplates(mcpServer)
+
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	if _, err := mcpServer.Connect(ctx, serverTransport, nil); err != nil {
+		t.Fatalf("failed to connect to server: %v", err)
+	}
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	clientSession, err := client.Connect(t.Context(), clientTransport, nil)
+	if err != nil {
+		t.Fatalf("failed to connect to client: %v", err)
+	}
+	defer clientSession.Close() //nolint:errcheck
+
+	return clientSession.ReadResource(t.Context(), &mcp.ReadResourceParams{URI: uri})
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"reflect"
 	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	twapi "github.com/teamwork/twapi-go-sdk"
@@ -209,11 +211,18 @@ func numericParam[T int8 | int16 | int32 | int64 |
 		}
 		return fmt.Errorf("parameter %s is required", key)
 	}
-	v, ok := toFloat64(value)
-	if !ok {
-		return fmt.Errorf("invalid type for %s: expected %T, got %T", key, *target, value)
+	var vType T
+	if vStr, isString := value.(string); isString {
+		if vType, ok = numericFromString[T](vStr); !ok {
+			return fmt.Errorf("invalid value for %s: expected %T, got %q", key, *target, vStr)
+		}
+	} else {
+		v, ok := toFloat64(value)
+		if !ok {
+			return fmt.Errorf("invalid type for %s: expected %T, got %T", key, *target, value)
+		}
+		vType = T(v)
 	}
-	vType := T(v)
 	for _, middleware := range middlewares {
 		var err error
 		if ok, err = middleware(&vType); err != nil || !ok {
@@ -656,6 +665,14 @@ func OptionalNumericListParam[T int8 | int16 | int32 | int64 |
 		}
 		*target = make([]T, 0, len(array))
 		for _, item := range array {
+			if itemStr, isString := item.(string); isString {
+				v, ok := numericFromString[T](itemStr)
+				if !ok {
+					return fmt.Errorf("invalid value in %s: expected %T, got %q", key, v, itemStr)
+				}
+				*target = append(*target, v)
+				continue
+			}
 			v, ok := toFloat64(item)
 			if !ok {
 				return fmt.Errorf("invalid type in %s: expected float64, got %T", key, item)
@@ -681,6 +698,14 @@ func OptionalCustomNumericListParam[T interface{ Add(float64) }](target T, key s
 			return fmt.Errorf("invalid type for %s: expected []any, got %T", key, value)
 		}
 		for _, item := range array {
+			if itemStr, isString := item.(string); isString {
+				v, ok := numericFromString[float64](itemStr)
+				if !ok {
+					return fmt.Errorf("invalid value in %s: expected float64, got %q", key, itemStr)
+				}
+				target.Add(v)
+				continue
+			}
 			v, ok := toFloat64(item)
 			if !ok {
 				return fmt.Errorf("invalid type in %s: expected float64, got %T", key, item)
@@ -734,3 +759,38 @@ func toFloat64(value any) (float64, bool) {
 	}
 	return v, true
 }
+
+// numericFromString parses a number sent as a string, such as the IDs returned
+// as strings to the JavaScript clients. The integers are parsed directly, as
+// converting them from float64 loses the precision of the IDs above 2^53.
+func numericFromString[T int8 | int16 | int32 | int64 |
+	uint8 | uint16 | uint32 | uint64 |
+	float32 | float64 |
+	projects.LegacyNumber](value string) (T, bool) {
+	var v T
+	target := reflect.ValueOf(&v).Elem()
+	value = strings.TrimSpace(value)
+	switch target.Kind() {
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, target.Type().Bits())
+		if err != nil {
+			return v, false
+		}
+		target.SetInt(n)
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, target.Type().Bits())
+		if err != nil {
+			return v, false
+		}
+		target.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(value, target.Type().Bits())
+		if err != nil {
+			return v, false
+		}
+		target.SetFloat(n)
+	default:
+		return v, false
+	}
+	return v, true
+}
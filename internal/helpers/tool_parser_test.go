@@ -0,0 +1,59 @@
+package helpers_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/teamwork/mcp/internal/helpers"
+)
+
+func TestNumericParamString(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   map[string]any
+		expected int64
+		isError  bool
+	}{{
+		name:     "number",
+		params:   map[string]any{"id": float64(123)},
+		expected: 123,
+	}, {
+		name:     "string above 2^53",
+		params:   map[string]any{"id": "9007199254740993"},
+		expected: 9007199254740993,
+	}, {
+		name:    "string not integer",
+		params:  map[string]any{"id": "12.5"},
+		isError: true,
+	}, {
+		name:    "string not numeric",
+		params:  map[string]any{"id": "last_task"},
+		isError: true,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var id int64
+			err := helpers.ParamGroup(tt.params, helpers.RequiredNumericParam(&id, "id"))
+			if (err != nil) != tt.isError {
+				t.Fatalf("unexpected error state: %v", err)
+			}
+			if !tt.isError && id != tt.expected {
+				t.Errorf("expected %d, got %d", tt.expected, id)
+			}
+		})
+	}
+}
+
+func TestNumericListParamString(t *testing.T) {
+	var ids []int64
+	err := helpers.ParamGroup(map[string]any{"tag_ids": []any{float64(3), "9007199254740993"}},
+		helpers.OptionalNumericListParam(&ids, "tag_ids"),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []int64{3, 9007199254740993}; !slices.Equal(ids, expected) {
+		t.Errorf("expected %v, got %v", expected, ids)
+	}
+}
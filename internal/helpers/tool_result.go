@@ -3,6 +3,9 @@ package helpers
 import (
 	"encoding/json"
 	"fmt"
+	"mime"
+	"net/url"
+	"path"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
@@ -38,3 +41,18 @@ func NewToolResultJSON(v any) (*mcp.CallToolResult, error) {
 		StructuredContent: v,
 	}, nil
 }
+
+// NewImageResourceLink creates a resource link to an image (e.g. avatars or
+// logos), so clients can render it without downloading it through the MCP
+// server. The MIME type is guessed from the URL file extension.
+func NewImageResourceLink(name, title, uri string) *mcp.ResourceLink {
+	link := &mcp.ResourceLink{
+		URI:   uri,
+		Name:  name,
+		Title: title,
+	}
+	if parsedURI, err := url.Parse(uri); err == nil {
+		link.MIMEType = mime.TypeByExtension(path.Ext(parsedURI.Path))
+	}
+	return link
+}
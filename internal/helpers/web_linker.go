@@ -9,6 +9,7 @@ import (
 	"slices"
 	"strings"
 
+	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/teamwork/mcp/internal/config"
 )
 
@@ -155,3 +156,97 @@ func WebLinkerWithIDPathBuilder(prefix string) func(map[string]any) string {
 		return fmt.Sprintf("%s/%v", prefix, id)
 	}
 }
+
+// WebLinkedEntitiesMetaKey is the tool result metadata key listing the entities
+// that received a web link, so rich clients can render links to Teamwork.com
+// without parsing the text content.
+const WebLinkedEntitiesMetaKey = "com.teamwork/entities"
+
+// WebLinkedEntity identifies an entity with a web link in a tool result.
+type WebLinkedEntity struct {
+	// Type is the entity type, derived from the top-level JSON field (e.g.
+	// "task" for both "task" and "tasks" fields).
+	Type string `json:"type"`
+	// ID is the entity identifier.
+	ID any `json:"id"`
+	// URL is the canonical web link of the entity.
+	URL string `json:"url"`
+}
+
+// WebLinkedEntitiesMeta builds the tool result metadata from JSON data already
+// processed by WebLinker. It collects the type, ID and web link of each entity,
+// storing them under WebLinkedEntitiesMetaKey. Returns nil if no entity has a
+// web link.
+func WebLinkedEntitiesMeta(data []byte, opts ...WebLinkerOption) mcp.Meta {
+	options := WebLinkerOptions{
+		ignoreFields: knownRootFields,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil
+	}
+
+	var entities []WebLinkedEntity
+	collect := func(key string, object map[string]any) {
+		meta, _ := object["meta"].(map[string]any)
+		link, _ := meta["webLink"].(string)
+		id, ok := object["id"]
+		if link == "" || !ok {
+			return
+		}
+		// round float64 IDs to int64 to keep the same format as the API
+		if numeric, ok := id.(float64); ok && math.Trunc(numeric) == numeric {
+			id = int64(numeric)
+		}
+		entities = append(entities, WebLinkedEntity{
+			Type: entityType(key),
+			ID:   id,
+			URL:  link,
+		})
+	}
+
+	keys := make([]string, 0, len(decoded))
+	for key := range decoded {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		if slices.Contains(options.ignoreFields, key) {
+			continue
+		}
+		switch v := decoded[key].(type) {
+		case map[string]any:
+			collect(key, v)
+		case []any:
+			for _, item := range v {
+				if m, ok := item.(map[string]any); ok {
+					collect(key, m)
+				}
+			}
+		}
+	}
+
+	if len(entities) == 0 {
+		return nil
+	}
+	return mcp.Meta{WebLinkedEntitiesMetaKey: entities}
+}
+
+// entityType converts a top-level JSON field name into a singular entity type.
+func entityType(key string) string {
+	switch {
+	case key == "people":
+		return "person"
+	case strings.HasSuffix(key, "ies"):
+		return strings.TrimSuffix(key, "ies") + "y"
+	case strings.HasSuffix(key, "ss"):
+		return key
+	default:
+		return strings.TrimSuffix(key, "s")
+	}
+}
@@ -205,3 +205,56 @@ func TestWebLinkerWithIDPathBuilder(t *testing.T) {
 		})
 	}
 }
+
+//nolint:lll
+func TestWebLinkedEntitiesMeta(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want []helpers.WebLinkedEntity
+	}{{
+		name: "single entity",
+		data: []byte(`{"task":{"id":123,"meta":{"webLink":"https://example.com/app/tasks/123"}}}`),
+		want: []helpers.WebLinkedEntity{
+			{Type: "task", ID: int64(123), URL: "https://example.com/app/tasks/123"},
+		},
+	}, {
+		name: "multiple entities",
+		data: []byte(`{"companies":[{"id":1,"meta":{"webLink":"https://example.com/app/clients/1"}},{"id":2,"meta":{"webLink":"https://example.com/app/clients/2"}}]}`),
+		want: []helpers.WebLinkedEntity{
+			{Type: "company", ID: int64(1), URL: "https://example.com/app/clients/1"},
+			{Type: "company", ID: int64(2), URL: "https://example.com/app/clients/2"},
+		},
+	}, {
+		name: "ignores known root fields and entities without web link",
+		data: []byte(`{"meta":{"page":1},"included":{"users":{"1":{"id":1}}},"people":[{"id":5,"meta":{"webLink":"https://example.com/app/people/5"}},{"id":6}]}`),
+		want: []helpers.WebLinkedEntity{
+			{Type: "person", ID: int64(5), URL: "https://example.com/app/people/5"},
+		},
+	}, {
+		name: "no web links",
+		data: []byte(`{"task":{"id":123}}`),
+	}, {
+		name: "non-JSON data",
+		data: []byte(`Not a JSON`),
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			meta := helpers.WebLinkedEntitiesMeta(tt.data)
+			if tt.want == nil {
+				if meta != nil {
+					t.Errorf("expected no metadata, got %v", meta)
+				}
+				return
+			}
+			got, ok := meta[helpers.WebLinkedEntitiesMetaKey].([]helpers.WebLinkedEntity)
+			if !ok {
+				t.Fatalf("expected metadata with key %q, got %v", helpers.WebLinkedEntitiesMetaKey, meta)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+// Package i18n localizes the tool metadata (titles, descriptions and parameter
+// descriptions) and the result messages to the language configured for the
+// installation. Texts without a translation are kept in English, so catalogs
+// can be partial.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"regexp"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// DefaultLanguage is the language of the texts in the source code.
+const DefaultLanguage = "en"
+
+//go:embed locales/*.json
+var locales embed.FS
+
+// reVerb matches the formatting verbs supported in the message keys.
+var reVerb = regexp.MustCompile(`%[dsv]`)
+
+// Catalog contains the translations of a language.
+type Catalog struct {
+	// Tools contains the translations of the tool metadata, by tool name.
+	Tools map[string]ToolTranslation `json:"tools"`
+	// Messages contains the translations of the result messages, by English
+	// format (e.g. "Task created successfully with ID %d"). The translation must
+	// contain the same verbs, in the same order.
+	Messages map[string]string `json:"messages"`
+}
+
+// ToolTranslation contains the translations of a tool metadata.
+type ToolTranslation struct {
+	// Title is the human-readable title of the tool.
+	Title string `json:"title"`
+	// Description is the description of the tool.
+	Description string `json:"description"`
+	// Parameters contains the parameter descriptions, by parameter name. Nested
+	// parameters use the dot notation (e.g. "assignees.user_ids").
+	Parameters map[string]string `json:"parameters"`
+}
+
+type message struct {
+	pattern     *regexp.Regexp
+	translation string
+}
+
+// Localizer translates tools and results to a language.
+type Localizer struct {
+	language string
+	catalog  Catalog
+	messages []message
+}
+
+// NewLocalizer creates a Localizer for the language, using the built-in catalog
+// merged with the given custom catalog, where the custom translations take
+// precedence. It returns nil for the default language without custom
+// translations.
+func NewLocalizer(language string, custom *Catalog) (*Localizer, error) {
+	language = strings.ToLower(strings.TrimSpace(language))
+	if language == "" {
+		language = DefaultLanguage
+	}
+
+	var catalog Catalog
+	if content, err := locales.ReadFile("locales/" + language + ".json"); err == nil {
+		if err := json.Unmarshal(content, &catalog); err != nil {
+			return nil, fmt.Errorf("failed to decode %q catalog: %w", language, err)
+		}
+	} else if language != DefaultLanguage && custom == nil {
+		return nil, fmt.Errorf("language %q is not supported", language)
+	}
+
+	if custom != nil {
+		if catalog.Tools == nil {
+			catalog.Tools = make(map[string]ToolTranslation)
+		}
+		if catalog.Messages == nil {
+			catalog.Messages = make(map[string]string)
+		}
+		maps.Copy(catalog.Tools, custom.Tools)
+		maps.Copy(catalog.Messages, custom.Messages)
+	}
+	if len(catalog.Tools) == 0 && len(catalog.Messages) == 0 {
+		return nil, nil
+	}
+
+	localizer := &Localizer{
+		language: language,
+		catalog:  catalog,
+	}
+	for format, translation := range catalog.Messages {
+		pattern := reVerb.ReplaceAllStringFunc(regexp.QuoteMeta(format), func(verb string) string {
+			if verb == "%d" {
+				return `(-?\d+)`
+			}
+			return `(.+?)`
+		})
+		localizer.messages = append(localizer.messages, message{
+			pattern:     regexp.MustCompile("^" + pattern + "$"),
+			translation: reVerb.ReplaceAllString(translation, "%s"),
+		})
+	}
+	return localizer, nil
+}
+
+// Language returns the language of the localizer.
+func (l *Localizer) Language() string {
+	return l.language
+}
+
+// LocalizeTool translates the tool metadata in place.
+func (l *Localizer) LocalizeTool(tool *mcp.Tool) {
+	translation, ok := l.catalog.Tools[tool.Name]
+	if !ok {
+		return
+	}
+	if translation.Description != "" {
+		tool.Description = translation.Description
+	}
+	if translation.Title != "" {
+		if tool.Annotations == nil {
+			tool.Annotations = new(mcp.ToolAnnotations)
+		}
+		tool.Annotations.Title = translation.Title
+	}
+	if schema, ok := tool.InputSchema.(*jsonschema.Schema); ok && len(translation.Parameters) > 0 {
+		localizeSchema(schema, "", translation.Parameters)
+	}
+}
+
+func localizeSchema(schema *jsonschema.Schema, prefix string, parameters map[string]string) {
+	for name, property := range schema.Properties {
+		if property == nil {
+			continue
+		}
+		path := prefix + name
+		if description, ok := parameters[path]; ok {
+			property.Description = description
+		}
+		localizeSchema(property, path+".", parameters)
+		if property.Items != nil {
+			localizeSchema(property.Items, path+".", parameters)
+		}
+	}
+}
+
+// LocalizeMessage translates a result message. Messages without translation
+// are returned unchanged.
+func (l *Localizer) LocalizeMessage(text string) string {
+	for _, message := range l.messages {
+		matches := message.pattern.FindStringSubmatch(text)
+		if matches == nil {
+			continue
+		}
+		args := make([]any, len(matches)-1)
+		for i, match := range matches[1:] {
+			args[i] = match
+		}
+		return fmt.Sprintf(message.translation, args...)
+	}
+	return text
+}
+
+// LocalizeResult translates the text messages of a tool result in place. JSON
+// content is never translated.
+func (l *Localizer) LocalizeResult(result *mcp.CallToolResult) {
+	if result == nil {
+		return
+	}
+	for _, content := range result.Content {
+		text, ok := content.(*mcp.TextContent)
+		if !ok || json.Valid([]byte(text.Text)) {
+			continue
+		}
+		text.Text = l.LocalizeMessage(text.Text)
+	}
+}
@@ -0,0 +1,96 @@
+package i18n_test
+
+import (
+	"testing"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/i18n"
+)
+
+func TestNewLocalizer(t *testing.T) {
+	localizer, err := i18n.NewLocalizer("en", nil)
+	if err != nil || localizer != nil {
+		t.Errorf("expected no localizer for the default language, got %v (%v)", localizer, err)
+	}
+
+	if _, err := i18n.NewLocalizer("xx", nil); err == nil {
+		t.Errorf("expected error for unsupported language")
+	}
+
+	localizer, err = i18n.NewLocalizer("xx", &i18n.Catalog{
+		Messages: map[string]string{"Task deleted successfully": "Custom"},
+	})
+	if err != nil || localizer == nil {
+		t.Fatalf("expected localizer for custom catalog, got %v (%v)", localizer, err)
+	}
+	if got := localizer.LocalizeMessage("Task deleted successfully"); got != "Custom" {
+		t.Errorf("unexpected custom message: %s", got)
+	}
+}
+
+func TestLocalizerLocalizeMessage(t *testing.T) {
+	localizer, err := i18n.NewLocalizer("es", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		text string
+		want string
+	}{{
+		text: "Task created successfully with ID 123",
+		want: "Tarea creada correctamente con el ID 123",
+	}, {
+		text: "Task updated successfully",
+		want: "Tarea actualizada correctamente",
+	}, {
+		text: "Something without translation",
+		want: "Something without translation",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := localizer.LocalizeMessage(tt.text); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestLocalizerLocalizeTool(t *testing.T) {
+	localizer, err := i18n.NewLocalizer("es", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tool := &mcp.Tool{
+		Name:        "twprojects-create_task",
+		Description: "Create a new task in Teamwork.com.",
+		Annotations: &mcp.ToolAnnotations{Title: "Create Task"},
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"name": {Type: "string", Description: "The name of the task."},
+				"assignees": {
+					Type: "object",
+					Properties: map[string]*jsonschema.Schema{
+						"user_ids": {Type: "array", Description: "List of user IDs assigned to the task."},
+					},
+				},
+			},
+		},
+	}
+	localizer.LocalizeTool(tool)
+
+	if tool.Annotations.Title != "Crear tarea" {
+		t.Errorf("unexpected title: %s", tool.Annotations.Title)
+	}
+	schema := tool.InputSchema.(*jsonschema.Schema)
+	if schema.Properties["name"].Description != "El nombre de la tarea." {
+		t.Errorf("unexpected parameter description: %s", schema.Properties["name"].Description)
+	}
+	if description := schema.Properties["assignees"].Properties["user_ids"].Description; description !=
+		"Lista de IDs de usuarios asignados a la tarea." {
+		t.Errorf("unexpected nested parameter description: %s", description)
+	}
+}
@@ -0,0 +1,127 @@
+package network
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+// ThrottleNotifier is called when a request is queued because the installation
+// reached the concurrency limit. The number of requests waiting, including this
+// one, is informed.
+type ThrottleNotifier func(installation string, queued int)
+
+type throttleNotifierKey struct{}
+
+// WithThrottleNotifier returns a new context with the notifier called when the
+// requests made with it are queued.
+func WithThrottleNotifier(ctx context.Context, notifier ThrottleNotifier) context.Context {
+	return context.WithValue(ctx, throttleNotifierKey{}, notifier)
+}
+
+// ConcurrencyLimiter is an http.RoundTripper that limits the number of
+// concurrent requests per installation, queueing the requests above the limit.
+// It avoids tripping the rate limits of Teamwork API with bursts of parallel
+// requests.
+type ConcurrencyLimiter struct {
+	Base  http.RoundTripper
+	Limit int
+
+	mu            sync.Mutex
+	installations map[string]*installationSlots
+}
+
+// installationSlots tracks the requests in flight and queued of an
+// installation.
+type installationSlots struct {
+	slots chan struct{}
+	users int
+}
+
+// NewConcurrencyLimiter creates a new ConcurrencyLimiter allowing up to limit
+// concurrent requests per installation. A zero or negative limit disables it.
+func NewConcurrencyLimiter(limit int, base http.RoundTripper) *ConcurrencyLimiter {
+	return &ConcurrencyLimiter{
+		Base:          base,
+		Limit:         limit,
+		installations: make(map[string]*installationSlots),
+	}
+}
+
+// RoundTrip implements the RoundTripper interface
+func (cl *ConcurrencyLimiter) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := cl.Base
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	if cl.Limit <= 0 {
+		return transport.RoundTrip(req)
+	}
+
+	installation := requestInstallation(req)
+	slots := cl.acquire(installation)
+	defer cl.release(installation)
+
+	select {
+	case slots.slots <- struct{}{}:
+	default:
+		if notifier, ok := req.Context().Value(throttleNotifierKey{}).(ThrottleNotifier); ok {
+			notifier(installation, cl.queued(installation))
+		}
+		select {
+		case slots.slots <- struct{}{}:
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+	defer func() { <-slots.slots }()
+
+	return transport.RoundTrip(req)
+}
+
+// acquire returns the slots of the installation, creating them when needed.
+func (cl *ConcurrencyLimiter) acquire(installation string) *installationSlots {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+
+	slots, ok := cl.installations[installation]
+	if !ok {
+		slots = &installationSlots{slots: make(chan struct{}, cl.Limit)}
+		cl.installations[installation] = slots
+	}
+	slots.users++
+	return slots
+}
+
+// release forgets the slots of the installation when they are no longer used,
+// so idle installations don't accumulate.
+func (cl *ConcurrencyLimiter) release(installation string) {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+
+	if slots, ok := cl.installations[installation]; ok {
+		if slots.users--; slots.users <= 0 {
+			delete(cl.installations, installation)
+		}
+	}
+}
+
+// queued returns the number of requests of the installation waiting for a slot.
+func (cl *ConcurrencyLimiter) queued(installation string) int {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+
+	if slots, ok := cl.installations[installation]; ok {
+		return max(slots.users-len(slots.slots), 1)
+	}
+	return 1
+}
+
+// requestInstallation returns the installation of the request. The Host header
+// is preferred, as the URL host is replaced when using an internal proxy.
+func requestInstallation(req *http.Request) string {
+	if host := req.Header.Get("Host"); host != "" {
+		return host
+	}
+	return req.URL.Host
+}
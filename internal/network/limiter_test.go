@@ -0,0 +1,155 @@
+package network_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"testing/synctest"
+
+	"github.com/teamwork/mcp/internal/network"
+)
+
+// blockingTransport holds the requests until they are released.
+type blockingTransport struct {
+	release  chan struct{}
+	inFlight atomic.Int32
+	maximum  atomic.Int32
+	requests atomic.Int32
+}
+
+func newBlockingTransport() *blockingTransport {
+	return &blockingTransport{release: make(chan struct{})}
+}
+
+func (b *blockingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	b.requests.Add(1)
+	inFlight := b.inFlight.Add(1)
+	defer b.inFlight.Add(-1)
+	for {
+		maximum := b.maximum.Load()
+		if inFlight <= maximum || b.maximum.CompareAndSwap(maximum, inFlight) {
+			break
+		}
+	}
+
+	select {
+	case <-b.release:
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+	recorder := httptest.NewRecorder()
+	recorder.WriteHeader(http.StatusOK)
+	return recorder.Result(), nil
+}
+
+func TestConcurrencyLimiterQueue(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		transport := newBlockingTransport()
+		limiter := network.NewConcurrencyLimiter(2, transport)
+
+		var mu sync.Mutex
+		var notifications []int
+		ctx := network.WithThrottleNotifier(t.Context(), func(installation string, queued int) {
+			mu.Lock()
+			defer mu.Unlock()
+			if installation != "a.teamwork.com" {
+				t.Errorf("unexpected installation %q", installation)
+			}
+			notifications = append(notifications, queued)
+		})
+
+		var wg sync.WaitGroup
+		for range 4 {
+			wg.Go(func() {
+				req := httptest.NewRequestWithContext(ctx, http.MethodGet, "https://a.teamwork.com/projects.json", nil)
+				response, err := limiter.RoundTrip(req)
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+					return
+				}
+				_ = response.Body.Close()
+			})
+		}
+		synctest.Wait()
+		if inFlight := transport.inFlight.Load(); inFlight != 2 {
+			t.Errorf("expected 2 requests in flight, got %d", inFlight)
+		}
+
+		// other installations have their own slots
+		otherDone := make(chan struct{})
+		go func() {
+			defer close(otherDone)
+			req := httptest.NewRequestWithContext(ctx, http.MethodGet, "https://b.teamwork.com/projects.json", nil)
+			if _, err := limiter.RoundTrip(req); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+		synctest.Wait()
+		if inFlight := transport.inFlight.Load(); inFlight != 3 {
+			t.Errorf("expected the other installation in flight, got %d requests", inFlight)
+		}
+
+		close(transport.release)
+		wg.Wait()
+		<-otherDone
+
+		if requests := transport.requests.Load(); requests != 5 {
+			t.Errorf("expected 5 requests, got %d", requests)
+		}
+		if maximum := transport.maximum.Load(); maximum != 3 {
+			t.Errorf("expected at most 3 requests in flight, got %d", maximum)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if len(notifications) != 2 {
+			t.Errorf("expected 2 throttle notifications, got %v", notifications)
+		}
+	})
+}
+
+func TestConcurrencyLimiterCancel(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		transport := newBlockingTransport()
+		limiter := network.NewConcurrencyLimiter(1, transport)
+
+		firstDone := make(chan struct{})
+		go func() {
+			defer close(firstDone)
+			req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "https://a.teamwork.com/tasks.json", nil)
+			if _, err := limiter.RoundTrip(req); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+		synctest.Wait()
+
+		// the queued request is released when its context is cancelled
+		ctx, cancel := context.WithCancel(t.Context())
+		queuedDone := make(chan error)
+		go func() {
+			req := httptest.NewRequestWithContext(ctx, http.MethodGet, "https://a.teamwork.com/tasks.json", nil)
+			_, err := limiter.RoundTrip(req)
+			queuedDone <- err
+		}()
+		synctest.Wait()
+		cancel()
+		if err := <-queuedDone; !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context canceled, got %v", err)
+		}
+		if requests := transport.requests.Load(); requests != 1 {
+			t.Errorf("expected the cancelled request not to be sent, got %d requests", requests)
+		}
+
+		close(transport.release)
+		<-firstDone
+
+		// the slot of the cancelled request doesn't stay taken
+		req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "https://a.teamwork.com/tasks.json", nil)
+		if _, err := limiter.RoundTrip(req); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
@@ -42,7 +42,7 @@ func (lrt *LoggingRoundTripper) RoundTrip(req *http.Request) (*http.Response, er
 	}
 
 	// Log the request
-	lrt.Log.Info("HTTP request",
+	lrt.Log.InfoContext(req.Context(), "HTTP request",
 		"url", req.URL.String(),
 		"method", req.Method,
 		"headers", headers,
@@ -56,7 +56,7 @@ func (lrt *LoggingRoundTripper) RoundTrip(req *http.Request) (*http.Response, er
 
 	resp, err := transport.RoundTrip(req)
 	if err != nil {
-		lrt.Log.Error("HTTP request failed", "error", err)
+		lrt.Log.ErrorContext(req.Context(), "HTTP request failed", "error", err)
 		return resp, err
 	}
 
@@ -70,7 +70,7 @@ func (lrt *LoggingRoundTripper) RoundTrip(req *http.Request) (*http.Response, er
 		resp.Body = io.NopCloser(bytes.NewBuffer(respBody))
 	}
 
-	lrt.Log.Info("HTTP response",
+	lrt.Log.InfoContext(req.Context(), "HTTP response",
 		"url", req.URL.String(),
 		"method", req.Method,
 		"status", resp.Status,
@@ -0,0 +1,158 @@
+// Package notifier posts a summary of the write operations performed by the
+// tools (who, what and a link) to external channels, such as Slack. It gives
+// teams visibility into the automated changes without checking audit logs.
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/config"
+	"github.com/teamwork/mcp/internal/helpers"
+	reqctx "github.com/teamwork/mcp/internal/request"
+	"github.com/teamwork/mcp/internal/toolsets"
+)
+
+// notifyTimeout is the maximum duration of a notification.
+const notifyTimeout = 10 * time.Second
+
+// Notification describes a write operation performed by a tool.
+type Notification struct {
+	// Tool is the name of the tool.
+	Tool string
+	// Summary is the text result of the tool (e.g. "Task created successfully
+	// with ID 123").
+	Summary string
+	// UserID is the Teamwork.com user that performed the operation, if known.
+	UserID int64
+	// Client is the name of the MCP client (e.g. the agent), if known.
+	Client string
+	// Installation is the Teamwork.com installation URL, if known.
+	Installation string
+	// Link is the web link of the changed entity, if known.
+	Link string
+	// At is when the operation was performed.
+	At time.Time
+	// CorrelationID identifies the tool call in the logs and in the Teamwork.com
+	// API requests.
+	CorrelationID string
+}
+
+// Notifier posts notifications to an external channel.
+type Notifier interface {
+	Notify(ctx context.Context, notification Notification) error
+}
+
+// Apply wraps the write tools of the group, so a notification is posted each
+// time they succeed. When tools is not empty, only the listed tools notify.
+// Notifications are posted in the background, so they don't delay the tool
+// results, and failures are only logged.
+func Apply(group *toolsets.ToolsetGroup, notifier Notifier, tools []string, logger *slog.Logger) {
+	group.WrapTools(func(tool toolsets.ToolWrapper) toolsets.ToolWrapper {
+		if tool.Tool.Annotations != nil && tool.Tool.Annotations.ReadOnlyHint {
+			return tool
+		}
+		if len(tools) > 0 && !slices.Contains(tools, tool.Tool.Name) {
+			return tool
+		}
+		tool.Handler = middleware(notifier, logger)(tool.Handler)
+		return tool
+	})
+}
+
+func middleware(notifier Notifier, logger *slog.Logger) toolsets.ToolMiddleware {
+	return func(next mcp.ToolHandler) mcp.ToolHandler {
+		return func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			// the notification shares the correlation ID of the tool call
+			ctx, _ = reqctx.EnsureCorrelationID(ctx)
+			result, err := next(ctx, request)
+			if err != nil || result == nil || result.IsError {
+				return result, err
+			}
+
+			notification := newNotification(ctx, request, result)
+			go func() {
+				ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), notifyTimeout)
+				defer cancel()
+				if err := notifier.Notify(ctx, notification); err != nil {
+					logger.Error("failed to post notification",
+						slog.String("tool", notification.Tool),
+						slog.String("error", err.Error()),
+					)
+				}
+			}()
+			return result, err
+		}
+	}
+}
+
+func newNotification(ctx context.Context, request *mcp.CallToolRequest, result *mcp.CallToolResult) Notification {
+	notification := Notification{
+		Tool: request.Params.Name,
+		At:   time.Now().UTC(),
+	}
+	notification.UserID, _ = config.UserIDFromContext(ctx)
+	notification.Installation, _ = config.CustomerURLFromContext(ctx)
+	notification.CorrelationID, _ = reqctx.CorrelationIDFromContext(ctx)
+	if request.Session != nil {
+		if params := request.Session.InitializeParams(); params != nil && params.ClientInfo != nil {
+			notification.Client = params.ClientInfo.Name
+		}
+	}
+
+	for _, content := range result.Content {
+		text, ok := content.(*mcp.TextContent)
+		if !ok {
+			continue
+		}
+		if notification.Summary == "" {
+			notification.Summary = text.Text
+		}
+		if notification.Link == "" {
+			notification.Link = webLink(text.Text)
+		}
+	}
+	return notification
+}
+
+// webLink returns the web link of the first entity in the JSON text, if any.
+func webLink(text string) string {
+	meta := helpers.WebLinkedEntitiesMeta([]byte(text))
+	entities, _ := meta[helpers.WebLinkedEntitiesMetaKey].([]helpers.WebLinkedEntity)
+	if len(entities) == 0 {
+		return ""
+	}
+	return entities[0].URL
+}
+
+// describe builds the human-readable text of the notification.
+func describe(notification Notification) string {
+	var who []string
+	if notification.UserID > 0 {
+		who = append(who, fmt.Sprintf("user %d", notification.UserID))
+	}
+	if notification.Client != "" {
+		who = append(who, "via "+notification.Client)
+	}
+	if len(who) == 0 {
+		who = append(who, "an agent")
+	}
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "%s ran %s", strings.Join(who, " "), notification.Tool)
+	if notification.Installation != "" {
+		fmt.Fprintf(&text, " on %s", notification.Installation)
+	}
+	if summary := strings.TrimSpace(notification.Summary); summary != "" && !strings.HasPrefix(summary, "{") {
+		fmt.Fprintf(&text, ": %s", summary)
+	}
+	if notification.CorrelationID != "" {
+		fmt.Fprintf(&text, " (correlation ID %s)", notification.CorrelationID)
+	}
+	return text.String()
+}
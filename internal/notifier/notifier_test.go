@@ -0,0 +1,113 @@
+package notifier_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/config"
+	"github.com/teamwork/mcp/internal/notifier"
+	"github.com/teamwork/mcp/internal/toolsets"
+)
+
+type notifierFunc func(ctx context.Context, notification notifier.Notification) error
+
+func (f notifierFunc) Notify(ctx context.Context, notification notifier.Notification) error {
+	return f(ctx, notification)
+}
+
+func TestApply(t *testing.T) {
+	handler := func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: "Task created successfully with ID 123"}},
+		}, nil
+	}
+	toolset := toolsets.NewToolset("test", "Test tools.")
+	toolset.AddWriteTools(toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name:        "test-create_task",
+			Annotations: &mcp.ToolAnnotations{},
+			InputSchema: &jsonschema.Schema{Type: "object"},
+		},
+		Handler: handler,
+	})
+	toolset.AddReadTools(toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name:        "test-get_task",
+			Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+			InputSchema: &jsonschema.Schema{Type: "object"},
+		},
+		Handler: handler,
+	})
+	group := toolsets.NewToolsetGroup(false)
+	group.AddToolset(toolset)
+
+	notifications := make(chan notifier.Notification, 2)
+	notifier.Apply(group, notifierFunc(func(_ context.Context, notification notifier.Notification) error {
+		notifications <- notification
+		return nil
+	}), nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	ctx := config.WithUserID(t.Context(), 42)
+	for _, tool := range toolset.GetAvailableTools() {
+		if _, err := tool.Handler(ctx, &mcp.CallToolRequest{
+			Params: &mcp.CallToolParamsRaw{Name: tool.Tool.Name},
+		}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	select {
+	case notification := <-notifications:
+		if notification.Tool != "test-create_task" || notification.UserID != 42 || notification.CorrelationID == "" {
+			t.Errorf("unexpected notification: %+v", notification)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected a notification")
+	}
+	select {
+	case notification := <-notifications:
+		t.Errorf("unexpected notification of a read tool: %+v", notification)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestSlack(t *testing.T) {
+	var text string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var message struct {
+			Text string `json:"text"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&message); err != nil {
+			t.Errorf("failed to decode message: %v", err)
+		}
+		text = message.Text
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	err := notifier.NewSlack(server.URL).Notify(t.Context(), notifier.Notification{
+		Tool:         "twprojects-create_task",
+		Summary:      "Task created successfully with ID 123",
+		UserID:       42,
+		Client:       "agent",
+		Installation: "https://example.teamwork.com",
+		Link:         "https://example.teamwork.com/app/tasks/123",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"user 42", "via agent", "twprojects-create_task", "ID 123", "/app/tasks/123"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in the message, got %q", want, text)
+		}
+	}
+}
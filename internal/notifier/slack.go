@@ -0,0 +1,63 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Slack posts the notifications to a Slack incoming webhook.
+type Slack struct {
+	webhookURL string
+	httpClient *http.Client
+}
+
+// NewSlack creates a Slack notifier posting to the incoming webhook URL.
+func NewSlack(webhookURL string) *Slack {
+	return &Slack{
+		webhookURL: webhookURL,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// slackMessage is the JSON body of a Slack incoming webhook message.
+//
+// https://api.slack.com/messaging/webhooks
+type slackMessage struct {
+	Text string `json:"text"`
+}
+
+// Notify posts the notification as a Slack message.
+func (s *Slack) Notify(ctx context.Context, notification Notification) error {
+	text := describe(notification)
+	if notification.Link != "" {
+		text += fmt.Sprintf("\n<%s|Open in Teamwork.com>", notification.Link)
+	}
+	payload, err := json.Marshal(slackMessage{Text: text})
+	if err != nil {
+		return fmt.Errorf("failed to encode payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.webhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package printout
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+)
+
+// htmlTemplate is the standalone HTML page of a document, with inline styles
+// so it can be opened or attached anywhere.
+var htmlTemplate = template.Must(template.New("printout").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{ .Title }}</title>
+<style>
+body { font-family: Helvetica, Arial, sans-serif; color: #222; max-width: 800px; margin: 40px auto; padding: 0 20px; }
+h1 { font-size: 24px; margin-bottom: 4px; }
+h2 { font-size: 16px; margin-top: 28px; border-bottom: 1px solid #ddd; padding-bottom: 4px; }
+.subtitle { color: #666; margin-top: 0; }
+table { border-collapse: collapse; margin-top: 16px; }
+th { text-align: left; color: #666; font-weight: normal; padding: 4px 24px 4px 0; vertical-align: top; }
+td { padding: 4px 0; }
+p { white-space: pre-wrap; line-height: 1.4; }
+</style>
+</head>
+<body>
+<h1>{{ .Title }}</h1>
+{{- if .Subtitle }}
+<p class="subtitle">{{ .Subtitle }}</p>
+{{- end }}
+{{- if .Fields }}
+<table>
+{{- range .Fields }}
+<tr><th>{{ .Label }}</th><td>{{ .Value }}</td></tr>
+{{- end }}
+</table>
+{{- end }}
+{{- range .Sections }}
+<h2>{{ .Title }}</h2>
+{{- range .Paragraphs }}
+<p>{{ . }}</p>
+{{- end }}
+{{- if .Items }}
+<ul>
+{{- range .Items }}
+<li>{{ . }}</li>
+{{- end }}
+</ul>
+{{- end }}
+{{- end }}
+</body>
+</html>
+`))
+
+// HTML writes the document as a standalone HTML page.
+func HTML(w io.Writer, document Document) error {
+	if err := htmlTemplate.Execute(w, document); err != nil {
+		return fmt.Errorf("failed to render HTML document: %w", err)
+	}
+	return nil
+}
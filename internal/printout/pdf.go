@@ -0,0 +1,289 @@
+package printout
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// The pages are A4, in points.
+const (
+	pdfPageWidth  = 595
+	pdfPageHeight = 842
+	pdfMargin     = 56
+	// pdfFieldWidth is the width of the column with the labels of the fields.
+	pdfFieldWidth = 130
+	// pdfItemIndent is the indentation of the text of the bullet items.
+	pdfItemIndent = 14
+)
+
+// The fonts are the standard Helvetica fonts, available in every PDF reader,
+// so they don't need to be embedded.
+const (
+	pdfFontRegular = "F1"
+	pdfFontBold    = "F2"
+)
+
+// helveticaWidths are the widths of the printable ASCII characters in the
+// Helvetica font, in thousandths of the font size, starting from the space.
+// The bold font is slightly wider, so it's measured with a margin instead of
+// its own metrics.
+var helveticaWidths = [...]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278, // space to /
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556, // 0 to ?
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778, // @ to O
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556, // P to _
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556, // ` to o
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584, // p to ~
+}
+
+// winAnsiRunes maps the characters out of the Latin-1 range to the
+// WinAnsiEncoding of the fonts.
+var winAnsiRunes = map[rune]byte{
+	'€': 0x80, '‚': 0x82, '„': 0x84, '…': 0x85, '‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95,
+	'–': 0x96, '—': 0x97, '™': 0x99,
+}
+
+// PDF writes the document as a PDF file, with the text wrapped in A4 pages.
+func PDF(w io.Writer, document Document) error {
+	layout := newPDFLayout()
+	layout.text(pdfFontBold, 18, 0, 0, document.Title)
+	if document.Subtitle != "" {
+		layout.text(pdfFontRegular, 11, 0, 4, document.Subtitle)
+	}
+	for i, field := range document.Fields {
+		spacing := 4.0
+		if i == 0 {
+			spacing = 16
+		}
+		layout.field(field, spacing)
+	}
+	for _, section := range document.Sections {
+		layout.text(pdfFontBold, 13, 0, 20, section.Title)
+		for _, paragraph := range section.Paragraphs {
+			for i, line := range strings.Split(paragraph, "\n") {
+				spacing := 0.0
+				if i == 0 {
+					spacing = 8
+				}
+				layout.text(pdfFontRegular, 10, 0, spacing, line)
+			}
+		}
+		for _, item := range section.Items {
+			layout.item(item)
+		}
+	}
+	return layout.write(w)
+}
+
+// pdfLayout places the text of a document in pages, keeping the content
+// stream of each page.
+type pdfLayout struct {
+	pages []*bytes.Buffer
+	y     float64
+}
+
+func newPDFLayout() *pdfLayout {
+	layout := &pdfLayout{}
+	layout.newPage()
+	return layout
+}
+
+func (l *pdfLayout) newPage() {
+	l.pages = append(l.pages, new(bytes.Buffer))
+	l.y = pdfPageHeight - pdfMargin
+}
+
+// line moves to the next line, starting a new page when there's no room left,
+// and writes the text at the indentation.
+func (l *pdfLayout) line(font string, size, indent, spacing float64, text []byte) {
+	leading := size * 1.4
+	if l.y-spacing-leading < pdfMargin {
+		l.newPage()
+	} else {
+		l.y -= spacing
+	}
+	l.y -= leading
+	l.show(font, size, indent, text)
+}
+
+// show writes the text in the current line.
+func (l *pdfLayout) show(font string, size, indent float64, text []byte) {
+	if len(text) == 0 {
+		return
+	}
+	fmt.Fprintf(l.pages[len(l.pages)-1], "BT /%s %g Tf %g %g Td (%s) Tj ET\n", font, size, pdfMargin+indent, l.y,
+		pdfEscape(text))
+}
+
+// text writes a block of text, wrapped in the width of the page.
+func (l *pdfLayout) text(font string, size, indent, spacing float64, text string) {
+	for i, line := range pdfWrap(pdfEncode(text), font, size, pdfPageWidth-2*pdfMargin-indent) {
+		if i > 0 {
+			spacing = 0
+		}
+		l.line(font, size, indent, spacing, line)
+	}
+}
+
+// field writes a labelled value, with the label in its own column.
+func (l *pdfLayout) field(field Field, spacing float64) {
+	const size = 10
+	lines := pdfWrap(pdfEncode(field.Value), pdfFontRegular, size, pdfPageWidth-2*pdfMargin-pdfFieldWidth)
+	if len(lines) == 0 {
+		lines = [][]byte{nil}
+	}
+	for i, line := range lines {
+		if i > 0 {
+			spacing = 0
+		}
+		l.line(pdfFontRegular, size, pdfFieldWidth, spacing, line)
+		if i == 0 {
+			l.show(pdfFontBold, size, 0, pdfEncode(field.Label))
+		}
+	}
+}
+
+// item writes a bullet item, with its text indented.
+func (l *pdfLayout) item(item string) {
+	const size = 10
+	lines := pdfWrap(pdfEncode(item), pdfFontRegular, size, pdfPageWidth-2*pdfMargin-pdfItemIndent)
+	for i, line := range lines {
+		spacing := 0.0
+		if i == 0 {
+			spacing = 4
+		}
+		l.line(pdfFontRegular, size, pdfItemIndent, spacing, line)
+		if i == 0 {
+			l.show(pdfFontRegular, size, 0, []byte{0x95})
+		}
+	}
+}
+
+// write writes the PDF file, with the catalog, the page tree, the fonts and
+// the pages with their content streams.
+func (l *pdfLayout) write(w io.Writer) error {
+	var buffer bytes.Buffer
+	var offsets []int
+	object := func(content string) {
+		offsets = append(offsets, buffer.Len())
+		fmt.Fprintf(&buffer, "%d 0 obj\n%s\nendobj\n", len(offsets), content)
+	}
+
+	// the first objects are the catalog, the page tree and the fonts, followed
+	// by each page and its content stream
+	const firstPage = 5
+	kids := make([]string, len(l.pages))
+	for i := range l.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+
+	buffer.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(l.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	for i, page := range l.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /%s 3 0 R "+
+			"/%s 4 0 R >> >> /Contents %d 0 R >>", pdfPageWidth, pdfPageHeight, pdfFontRegular, pdfFontBold,
+			firstPage+2*i+1))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", page.Len(), page.String()))
+	}
+
+	xref := buffer.Len()
+	fmt.Fprintf(&buffer, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buffer, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buffer, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	if _, err := buffer.WriteTo(w); err != nil {
+		return fmt.Errorf("failed to write PDF document: %w", err)
+	}
+	return nil
+}
+
+// pdfEncode encodes the text in the WinAnsiEncoding of the fonts, replacing the
+// unsupported characters with a question mark.
+func pdfEncode(text string) []byte {
+	encoded := make([]byte, 0, len(text))
+	for _, r := range text {
+		switch {
+		case r == '\t':
+			encoded = append(encoded, ' ')
+		case r >= 0x20 && r <= 0x7e, r >= 0xa0 && r <= 0xff:
+			encoded = append(encoded, byte(r))
+		case r < 0x20:
+			// control characters are dropped
+		default:
+			if b, ok := winAnsiRunes[r]; ok {
+				encoded = append(encoded, b)
+			} else {
+				encoded = append(encoded, '?')
+			}
+		}
+	}
+	return encoded
+}
+
+// pdfEscape escapes the characters with a special meaning in the PDF strings.
+func pdfEscape(text []byte) []byte {
+	escaped := make([]byte, 0, len(text))
+	for _, b := range text {
+		if b == '(' || b == ')' || b == '\\' {
+			escaped = append(escaped, '\\')
+		}
+		escaped = append(escaped, b)
+	}
+	return escaped
+}
+
+// pdfWidth returns the width of the encoded text, in points.
+func pdfWidth(text []byte, font string, size float64) float64 {
+	var width int
+	for _, b := range text {
+		if b >= 0x20 && b <= 0x7e {
+			width += helveticaWidths[b-0x20]
+		} else {
+			width += 556
+		}
+	}
+	if font == pdfFontBold {
+		width += width / 10
+	}
+	return float64(width) * size / 1000
+}
+
+// pdfWrap splits the encoded text in lines fitting in the width, breaking them
+// at the spaces. Words longer than the width are broken anywhere.
+func pdfWrap(text []byte, font string, size, width float64) [][]byte {
+	var lines [][]byte
+	var line []byte
+	for _, word := range bytes.Fields(text) {
+		candidate := word
+		if len(line) > 0 {
+			candidate = append(append(append([]byte{}, line...), ' '), word...)
+		}
+		if pdfWidth(candidate, font, size) <= width {
+			line = candidate
+			continue
+		}
+		if len(line) > 0 {
+			lines = append(lines, line)
+		}
+		line = word
+		for len(line) > 1 && pdfWidth(line, font, size) > width {
+			split := len(line) - 1
+			for split > 1 && pdfWidth(line[:split], font, size) > width {
+				split--
+			}
+			lines = append(lines, line[:split])
+			line = line[split:]
+		}
+	}
+	if len(line) > 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,60 @@
+// Package printout renders documents, such as a task or a milestone report, as
+// standalone HTML or PDF files, so they can be shared with people without a
+// Teamwork.com account. The documents are simple: a title, a list of fields
+// and sections of paragraphs or bullet items.
+package printout
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+// Document is the content of a printout.
+type Document struct {
+	// Title is the main heading of the document.
+	Title string
+	// Subtitle is shown below the title, e.g. the project of a task.
+	Subtitle string
+	// Fields are the labelled values shown below the title.
+	Fields []Field
+	// Sections are the blocks of the body of the document.
+	Sections []Section
+}
+
+// Field is a labelled value of a document.
+type Field struct {
+	Label string
+	Value string
+}
+
+// Section is a block of the body of a document, with paragraphs and bullet
+// items.
+type Section struct {
+	Title      string
+	Paragraphs []string
+	Items      []string
+}
+
+var (
+	reHTMLBreak     = regexp.MustCompile(`(?i)<br\s*/?>|</(li|tr)>`)
+	reHTMLParagraph = regexp.MustCompile(`(?i)</(p|div|h[1-6]|ul|ol|table)>`)
+	reHTMLTag       = regexp.MustCompile(`<[^>]*>`)
+	reBlankLine     = regexp.MustCompile(`\n\s*\n+`)
+)
+
+// PlainText converts the HTML content of a rich text field, such as the
+// description of a task, to plain text paragraphs.
+func PlainText(content string) []string {
+	content = reHTMLBreak.ReplaceAllString(content, "\n")
+	content = reHTMLParagraph.ReplaceAllString(content, "\n\n")
+	content = html.UnescapeString(reHTMLTag.ReplaceAllString(content, ""))
+
+	var paragraphs []string
+	for _, paragraph := range reBlankLine.Split(strings.TrimSpace(content), -1) {
+		if paragraph = strings.TrimSpace(paragraph); paragraph != "" {
+			paragraphs = append(paragraphs, paragraph)
+		}
+	}
+	return paragraphs
+}
@@ -0,0 +1,90 @@
+package printout_test
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/teamwork/mcp/internal/printout"
+)
+
+func testDocument() printout.Document {
+	return printout.Document{
+		Title:    "Launch <website>",
+		Subtitle: "Marketing / Release",
+		Fields: []printout.Field{
+			{Label: "Status", Value: "new"},
+			{Label: "Due date", Value: "2025-03-01"},
+		},
+		Sections: []printout.Section{{
+			Title:      "Description",
+			Paragraphs: []string{strings.Repeat("Review the copy (final) and publish it. ", 40)},
+			Items:      []string{"Design – done", "Copy"},
+		}},
+	}
+}
+
+func TestHTML(t *testing.T) {
+	var output bytes.Buffer
+	if err := printout.HTML(&output, testDocument()); err != nil {
+		t.Fatalf("failed to render HTML: %v", err)
+	}
+	html := output.String()
+	for _, expected := range []string{
+		"<h1>Launch &lt;website&gt;</h1>",
+		"<tr><th>Due date</th><td>2025-03-01</td></tr>",
+		"<li>Design – done</li>",
+	} {
+		if !strings.Contains(html, expected) {
+			t.Errorf("expected %q in %s", expected, html)
+		}
+	}
+}
+
+func TestPDF(t *testing.T) {
+	var output bytes.Buffer
+	if err := printout.PDF(&output, testDocument()); err != nil {
+		t.Fatalf("failed to render PDF: %v", err)
+	}
+	pdf := output.Bytes()
+	if !bytes.HasPrefix(pdf, []byte("%PDF-1.4\n")) || !bytes.HasSuffix(pdf, []byte("%%EOF\n")) {
+		t.Fatalf("unexpected PDF envelope")
+	}
+
+	// the cross-reference table must point to the objects
+	match := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(pdf)
+	if match == nil {
+		t.Fatalf("missing startxref")
+	}
+	xref, err := strconv.Atoi(string(match[1]))
+	if err != nil || !bytes.HasPrefix(pdf[xref:], []byte("xref\n")) {
+		t.Fatalf("startxref doesn't point to the cross-reference table")
+	}
+	offsets := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(pdf[xref:], -1)
+	for i, offset := range offsets {
+		position, _ := strconv.Atoi(string(offset[1]))
+		if expected := strconv.Itoa(i+1) + " 0 obj"; !bytes.HasPrefix(pdf[position:], []byte(expected)) {
+			t.Errorf("object %d not found at offset %d", i+1, position)
+		}
+	}
+
+	for _, expected := range []string{
+		"(Launch <website>) Tj",
+		"(Review the copy \\(final\\) and publish it.",
+		"(Design \x96 done) Tj",
+	} {
+		if !bytes.Contains(pdf, []byte(expected)) {
+			t.Errorf("expected %q in the PDF", expected)
+		}
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	paragraphs := printout.PlainText("<p>First &amp; foremost</p><p><strong>Second</strong><br>line</p>")
+	expected := []string{"First & foremost", "Second\nline"}
+	if strings.Join(paragraphs, "|") != strings.Join(expected, "|") {
+		t.Errorf("expected %q, got %q", expected, paragraphs)
+	}
+}
@@ -0,0 +1,45 @@
+package request
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+type correlationIDKey struct{}
+
+// CorrelationIDHeader is the header carrying the correlation ID in the
+// requests to the Teamwork API.
+const CorrelationIDHeader = "X-Request-ID"
+
+// EnsureCorrelationID returns the correlation ID of the context, generating a
+// new one when there is none. The correlation ID identifies a tool call in the
+// logs, errors, audit entries and upstream requests.
+func EnsureCorrelationID(ctx context.Context) (context.Context, string) {
+	if id, ok := CorrelationIDFromContext(ctx); ok {
+		return ctx, id
+	}
+	id := newCorrelationID()
+	return context.WithValue(ctx, correlationIDKey{}, id), id
+}
+
+// CorrelationIDFromContext returns the correlation ID stored in the context.
+func CorrelationIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(correlationIDKey{}).(string)
+	return id, ok && id != ""
+}
+
+func newCorrelationID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// SetCorrelationHeader sets the correlation ID of the request context in the
+// request headers, so the upstream request can be traced to the tool call.
+func SetCorrelationHeader(r *http.Request) {
+	if id, ok := CorrelationIDFromContext(r.Context()); ok {
+		r.Header.Set(CorrelationIDHeader, id)
+	}
+}
@@ -0,0 +1,152 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// scheduleDescriptors maps the supported descriptors to their cron expression.
+var scheduleDescriptors = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+}
+
+// maxScheduleLookup limits the search for the next activation, so impossible
+// schedules (e.g. February 30th) don't loop forever.
+const maxScheduleLookup = 5 * 366 * 24 * time.Hour
+
+// Schedule is a parsed cron expression. Each field is stored as a bitset of the
+// allowed values.
+type Schedule struct {
+	minute     uint64
+	hour       uint64
+	dayOfMonth uint64
+	month      uint64
+	dayOfWeek  uint64
+	// anyDay indicates if the day of month or day of week is unrestricted. When
+	// both are restricted, a day matching any of them is accepted, as in cron.
+	anyDay bool
+}
+
+// ParseSchedule parses a cron expression with 5 fields (minute, hour, day of
+// month, month and day of week) or one of the descriptors "@hourly", "@daily",
+// "@weekly" and "@monthly".
+func ParseSchedule(expression string) (Schedule, error) {
+	var schedule Schedule
+
+	expression = strings.TrimSpace(expression)
+	if cron, ok := scheduleDescriptors[strings.ToLower(expression)]; ok {
+		expression = cron
+	}
+
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return schedule, fmt.Errorf("invalid schedule %q: expected 5 fields", expression)
+	}
+
+	var err error
+	if schedule.minute, err = parseScheduleField(fields[0], 0, 59); err != nil {
+		return schedule, fmt.Errorf("invalid minute: %w", err)
+	}
+	if schedule.hour, err = parseScheduleField(fields[1], 0, 23); err != nil {
+		return schedule, fmt.Errorf("invalid hour: %w", err)
+	}
+	if schedule.dayOfMonth, err = parseScheduleField(fields[2], 1, 31); err != nil {
+		return schedule, fmt.Errorf("invalid day of month: %w", err)
+	}
+	if schedule.month, err = parseScheduleField(fields[3], 1, 12); err != nil {
+		return schedule, fmt.Errorf("invalid month: %w", err)
+	}
+	if schedule.dayOfWeek, err = parseScheduleField(fields[4], 0, 7); err != nil {
+		return schedule, fmt.Errorf("invalid day of week: %w", err)
+	}
+	// both 0 and 7 represent Sunday
+	if schedule.dayOfWeek&(1<<7) != 0 {
+		schedule.dayOfWeek |= 1
+	}
+	schedule.anyDay = strings.HasPrefix(fields[2], "*") || strings.HasPrefix(fields[4], "*")
+	return schedule, nil
+}
+
+// parseScheduleField parses a comma-separated list of values, ranges ("1-5"),
+// wildcards ("*") and steps ("*/15" or "1-30/5").
+func parseScheduleField(field string, minValue, maxValue int) (uint64, error) {
+	var bits uint64
+	for part := range strings.SplitSeq(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepPart); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step %q", stepPart)
+			}
+		}
+
+		start, end := minValue, maxValue
+		if rangePart != "*" {
+			startPart, endPart, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if start, err = strconv.Atoi(startPart); err != nil {
+				return 0, fmt.Errorf("invalid value %q", startPart)
+			}
+			end = start
+			if isRange {
+				if end, err = strconv.Atoi(endPart); err != nil {
+					return 0, fmt.Errorf("invalid value %q", endPart)
+				}
+			} else if hasStep {
+				// "5/10" means from 5 to the maximum value, every 10
+				end = maxValue
+			}
+		}
+		if start < minValue || end > maxValue || start > end {
+			return 0, fmt.Errorf("value out of range [%d, %d] in %q", minValue, maxValue, part)
+		}
+		for value := start; value <= end; value += step {
+			bits |= 1 << value
+		}
+	}
+	return bits, nil
+}
+
+// Next returns the next activation time strictly after the given time, in the
+// same location. It returns the zero time if there is no activation in the next
+// 5 years.
+func (s Schedule) Next(t time.Time) time.Time {
+	limit := t.Add(maxScheduleLookup)
+	t = t.Truncate(time.Minute).Add(time.Minute)
+
+	for t.Before(limit) {
+		if s.month&(1<<t.Month()) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.hour&(1<<t.Hour()) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.minute&(1<<t.Minute()) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (s Schedule) matchDay(t time.Time) bool {
+	dayOfMonth := s.dayOfMonth&(1<<t.Day()) != 0
+	dayOfWeek := s.dayOfWeek&(1<<t.Weekday()) != 0
+	if s.anyDay {
+		return dayOfMonth && dayOfWeek
+	}
+	return dayOfMonth || dayOfWeek
+}
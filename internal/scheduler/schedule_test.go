@@ -0,0 +1,87 @@
+package scheduler_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/teamwork/mcp/internal/scheduler"
+)
+
+func TestScheduleNext(t *testing.T) {
+	// Friday
+	from := time.Date(2025, time.January, 10, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		expression string
+		want       time.Time
+	}{{
+		name:       "every minute",
+		expression: "* * * * *",
+		want:       time.Date(2025, time.January, 10, 10, 31, 0, 0, time.UTC),
+	}, {
+		name:       "every 15 minutes",
+		expression: "*/15 * * * *",
+		want:       time.Date(2025, time.January, 10, 10, 45, 0, 0, time.UTC),
+	}, {
+		name:       "weekly on monday",
+		expression: "0 9 * * 1",
+		want:       time.Date(2025, time.January, 13, 9, 0, 0, 0, time.UTC),
+	}, {
+		name:       "weekdays range",
+		expression: "0 8 * * 1-5",
+		want:       time.Date(2025, time.January, 13, 8, 0, 0, 0, time.UTC),
+	}, {
+		name:       "sunday as 7",
+		expression: "0 0 * * 7",
+		want:       time.Date(2025, time.January, 12, 0, 0, 0, 0, time.UTC),
+	}, {
+		name:       "day of month or day of week",
+		expression: "0 0 11 * 1",
+		want:       time.Date(2025, time.January, 11, 0, 0, 0, 0, time.UTC),
+	}, {
+		name:       "list of months",
+		expression: "0 0 1 3,6 *",
+		want:       time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC),
+	}, {
+		name:       "daily descriptor",
+		expression: "@daily",
+		want:       time.Date(2025, time.January, 11, 0, 0, 0, 0, time.UTC),
+	}, {
+		name:       "impossible date",
+		expression: "0 0 30 2 *",
+		want:       time.Time{},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := scheduler.ParseSchedule(tt.expression)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got := schedule.Next(from); !got.Equal(tt.want) {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParseScheduleInvalid(t *testing.T) {
+	for _, expression := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"@yearly",
+	} {
+		t.Run(expression, func(t *testing.T) {
+			if _, err := scheduler.ParseSchedule(expression); err == nil {
+				t.Errorf("expected error for %q", expression)
+			}
+		})
+	}
+}
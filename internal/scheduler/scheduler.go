@@ -0,0 +1,295 @@
+// Package scheduler runs operator-defined tools on a cron-like schedule,
+// posting the results to a Teamwork.com message board or a webhook. It allows
+// unattended digests without an external orchestrator.
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/auth"
+	"github.com/teamwork/mcp/internal/config"
+	"github.com/teamwork/mcp/internal/toolsets"
+	twapi "github.com/teamwork/twapi-go-sdk"
+	"github.com/teamwork/twapi-go-sdk/session"
+)
+
+// jobTimeout is the maximum duration of a single job execution, including
+// posting the result.
+const jobTimeout = 5 * time.Minute
+
+// Scheduler runs the scheduled jobs defined in the config file.
+type Scheduler struct {
+	resources  config.Resources
+	tools      []toolsets.ToolWrapper
+	jobs       []job
+	httpClient *http.Client
+}
+
+type job struct {
+	config.ScheduledJob
+	schedule Schedule
+	location *time.Location
+}
+
+// New creates a Scheduler for the given jobs. Only the provided tools can be
+// referenced by the jobs, and an error is returned if any job is invalid, so
+// misconfigurations are detected on startup. The results of the tools are
+// redacted like in the MCP server, as they are posted elsewhere.
+func New(resources config.Resources, jobs []config.ScheduledJob, tools []toolsets.ToolWrapper) (*Scheduler, error) {
+	scheduler := &Scheduler{
+		resources: resources,
+		tools:     toolsets.UseToolMiddlewares(tools, config.UnattendedToolMiddlewares(resources)...),
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+	for _, scheduledJob := range jobs {
+		schedule, err := ParseSchedule(scheduledJob.Schedule)
+		if err != nil {
+			return nil, fmt.Errorf("scheduled job %q: %w", scheduledJob.Name, err)
+		}
+		location := time.UTC
+		if scheduledJob.Timezone != "" {
+			if location, err = time.LoadLocation(scheduledJob.Timezone); err != nil {
+				return nil, fmt.Errorf("scheduled job %q: invalid timezone: %w", scheduledJob.Name, err)
+			}
+		}
+		if scheduler.tool(scheduledJob.Tool) == nil {
+			return nil, fmt.Errorf("scheduled job %q: tool %q is not available for scheduling",
+				scheduledJob.Name, scheduledJob.Tool)
+		}
+		scheduler.jobs = append(scheduler.jobs, job{
+			ScheduledJob: scheduledJob,
+			schedule:     schedule,
+			location:     location,
+		})
+	}
+	return scheduler, nil
+}
+
+// Run executes the jobs on their schedule until the context is canceled.
+func (s *Scheduler) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, job := range s.jobs {
+		wg.Go(func() {
+			s.loop(ctx, job)
+		})
+	}
+	wg.Wait()
+}
+
+func (s *Scheduler) loop(ctx context.Context, job job) {
+	for {
+		next := job.schedule.Next(time.Now().In(job.location))
+		if next.IsZero() {
+			s.resources.Logger().Warn("scheduled job will never run",
+				slog.String("job", job.Name),
+			)
+			return
+		}
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if err := s.runJob(ctx, job); err != nil {
+			s.resources.Logger().Error("failed to run scheduled job",
+				slog.String("job", job.Name),
+				slog.String("error", err.Error()),
+			)
+			continue
+		}
+		s.resources.Logger().Info("scheduled job executed",
+			slog.String("job", job.Name),
+		)
+	}
+}
+
+func (s *Scheduler) runJob(ctx context.Context, job job) error {
+	ctx, cancel := context.WithTimeout(ctx, jobTimeout)
+	defer cancel()
+
+	token := os.Getenv(job.TokenEnv)
+	if token == "" {
+		return fmt.Errorf("environment variable %s is empty", job.TokenEnv)
+	}
+	info, err := auth.GetBearerInfo(ctx, s.resources, token)
+	if err != nil {
+		return fmt.Errorf("failed to get bearer info: %w", err)
+	}
+	ctx = config.WithCrossRegion(ctx, !strings.EqualFold(s.resources.Info.AWSRegion, info.Region))
+	ctx = config.WithCustomerURL(ctx, info.URL)
+	ctx = config.WithScopes(ctx, info.Meta.Scopes)
+	ctx = config.WithUserID(ctx, info.UserID)
+	ctx = session.WithBearerTokenContext(ctx, session.NewBearerToken(token, info.URL))
+
+	content, err := s.callTool(ctx, job)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	if job.Output.ProjectID > 0 {
+		if err := s.postMessage(ctx, job, content); err != nil {
+			errs = append(errs, fmt.Errorf("failed to post message: %w", err))
+		}
+	}
+	if job.Output.WebhookURL != "" {
+		if err := s.postWebhook(ctx, job, content); err != nil {
+			errs = append(errs, fmt.Errorf("failed to post webhook: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *Scheduler) tool(name string) *toolsets.ToolWrapper {
+	index := slices.IndexFunc(s.tools, func(tool toolsets.ToolWrapper) bool {
+		return tool.Tool.Name == name
+	})
+	if index == -1 {
+		return nil
+	}
+	return &s.tools[index]
+}
+
+// callTool calls the job tool, returning the text content of the result.
+func (s *Scheduler) callTool(ctx context.Context, job job) (string, error) {
+	arguments, err := json.Marshal(job.Arguments)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode arguments: %w", err)
+	}
+
+	result, err := s.tool(job.Tool).Handler(ctx, &mcp.CallToolRequest{
+		Params: &mcp.CallToolParamsRaw{
+			Name:      job.Tool,
+			Arguments: arguments,
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to call tool: %w", err)
+	}
+
+	var texts []string
+	for _, item := range result.Content {
+		if text, ok := item.(*mcp.TextContent); ok {
+			texts = append(texts, text.Text)
+		}
+	}
+	content := strings.Join(texts, "\n")
+	if result.IsError {
+		return "", fmt.Errorf("tool returned an error: %s", content)
+	}
+	return content, nil
+}
+
+func (s *Scheduler) postMessage(ctx context.Context, job job, content string) error {
+	if json.Valid([]byte(content)) {
+		content = "```json\n" + content + "\n```"
+	}
+	_, err := twapi.Execute[messageCreateRequest, *messageCreateResponse](ctx, s.resources.TeamworkEngine(),
+		messageCreateRequest{
+			projectID: job.Output.ProjectID,
+			Title:     fmt.Sprintf("%s (%s)", job.Name, time.Now().In(job.location).Format(time.DateOnly)),
+			Body:      content,
+		},
+	)
+	return err
+}
+
+// webhookPayload is the JSON body posted to the webhook.
+type webhookPayload struct {
+	Job     string    `json:"job"`
+	Tool    string    `json:"tool"`
+	RanAt   time.Time `json:"ranAt"`
+	Content string    `json:"content"`
+}
+
+func (s *Scheduler) postWebhook(ctx context.Context, job job, content string) error {
+	payload, err := json.Marshal(webhookPayload{
+		Job:     job.Name,
+		Tool:    job.Tool,
+		RanAt:   time.Now().UTC(),
+		Content: content,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, job.Output.WebhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// messageCreateRequest creates a message in the project message board.
+type messageCreateRequest struct {
+	projectID int64
+	Title     string `json:"title"`
+	Body      string `json:"body"`
+}
+
+// HTTPRequest creates an HTTP request for the messageCreateRequest.
+func (m messageCreateRequest) HTTPRequest(ctx context.Context, server string) (*http.Request, error) {
+	uri := fmt.Sprintf("%s/projects/%d/posts.json", server, m.projectID)
+
+	payload := struct {
+		Post messageCreateRequest `json:"post"`
+	}{Post: m}
+
+	var body bytes.Buffer
+	if err := json.NewEncoder(&body).Encode(payload); err != nil {
+		return nil, fmt.Errorf("failed to encode create message request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, uri, &body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+// messageCreateResponse is the response of the message creation.
+type messageCreateResponse struct {
+	ID string `json:"id"`
+}
+
+// HandleHTTPResponse handles the HTTP response for the messageCreateResponse.
+func (m *messageCreateResponse) HandleHTTPResponse(resp *http.Response) error {
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return twapi.NewHTTPError(resp, "failed to create message")
+	}
+	if err := json.NewDecoder(resp.Body).Decode(m); err != nil {
+		return fmt.Errorf("failed to decode create message response: %w", err)
+	}
+	return nil
+}
@@ -33,8 +33,17 @@ func (s ProjectsSessionMock) Server() string {
 
 // ProjectsEngineMock creates a mock twapi.Engine with the given HTTP response
 func ProjectsEngineMock(status int, response []byte) *twapi.Engine {
+	return ProjectsEngineMockFunc(func(*http.Request) (int, []byte) {
+		return status, response
+	})
+}
+
+// ProjectsEngineMockFunc creates a mock twapi.Engine answering each request
+// with the HTTP status and response returned by the function
+func ProjectsEngineMockFunc(f func(*http.Request) (int, []byte)) *twapi.Engine {
 	return twapi.NewEngine(ProjectsSessionMock{}, twapi.WithMiddleware(func(twapi.HTTPClient) twapi.HTTPClient {
-		return twapi.HTTPClientFunc(func(*http.Request) (*http.Response, error) {
+		return twapi.HTTPClientFunc(func(req *http.Request) (*http.Response, error) {
+			status, response := f(req)
 			return &http.Response{
 				StatusCode: status,
 				Status:     http.StatusText(status),
@@ -63,13 +72,31 @@ func DeskClientMock(status int, response []byte) (*deskclient.Client, *httptest.
 }
 
 // ProjectsMCPServerMock creates a mock MCP server for twprojects testing
-func ProjectsMCPServerMock(t *testing.T, status int, response []byte) *mcp.Server {
+func ProjectsMCPServerMock(
+	t *testing.T,
+	status int,
+	response []byte,
+	opts ...twprojects.ToolsetGroupOption,
+) *mcp.Server {
+	return ProjectsMCPServerMockFunc(t, func(*http.Request) (int, []byte) {
+		return status, response
+	}, opts...)
+}
+
+// ProjectsMCPServerMockFunc creates a mock MCP server for twprojects testing,
+// answering each Teamwork API request with the HTTP status and response
+// returned by the function
+func ProjectsMCPServerMockFunc(
+	t *testing.T,
+	f func(*http.Request) (int, []byte),
+	opts ...twprojects.ToolsetGroupOption,
+) *mcp.Server {
 	mcpServer := mcp.NewServer(&mcp.Implementation{
 		Name:    "test-server",
 		Version: "1.0.0",
 	}, &mcp.ServerOptions{})
 
-	toolsetGroup := twprojects.DefaultToolsetGroup(false, true, ProjectsEngineMock(status, response))
+	toolsetGroup := twprojects.DefaultToolsetGroup(false, true, ProjectsEngineMockFunc(f), opts...)
 	if err := toolsetGroup.EnableToolsets(toolsets.MethodAll); err != nil {
 		t.Fatalf("failed to enable toolsets: %v", err)
 	}
@@ -78,6 +105,21 @@ func ProjectsMCPServerMock(t *testing.T, status int, response []byte) *mcp.Serve
 	return mcpServer
 }
 
+// ProjectsMCPServerStub creates an MCP server for twprojects testing, serving
+// the Teamwork API requests with the handler, e.g. the in-memory API of the
+// twstub package
+func ProjectsMCPServerStub(
+	t *testing.T,
+	handler http.Handler,
+	opts ...twprojects.ToolsetGroupOption,
+) *mcp.Server {
+	return ProjectsMCPServerMockFunc(t, func(r *http.Request) (int, []byte) {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, r)
+		return recorder.Code, recorder.Body.Bytes()
+	}, opts...)
+}
+
 // DeskMCPServerMock creates a mock MCP server for twdesk testing
 func DeskMCPServerMock(t *testing.T, status int, response []byte) (*mcp.Server, func()) {
 	mcpServer := mcp.NewServer(&mcp.Implementation{
@@ -90,7 +132,7 @@ func DeskMCPServerMock(t *testing.T, status int, response []byte) (*mcp.Server,
 		testServer.Close()
 	}
 
-	toolsetGroup := twdesk.DefaultToolsetGroup(client)
+	toolsetGroup := twdesk.DefaultToolsetGroup(false, client)
 	if err := toolsetGroup.EnableToolsets(toolsets.MethodAll); err != nil {
 		cleanup()
 		t.Fatalf("failed to enable toolsets: %v", err)
@@ -125,6 +167,7 @@ func CheckMessage(t *testing.T, result mcp.Result) {
 // ExecuteToolRequestOptions represents options for ExecuteToolRequest.
 type ExecuteToolRequestOptions struct {
 	checkMessage func(t *testing.T, result mcp.Result)
+	ctx          context.Context
 }
 
 // ExecuteToolRequestOption is a function that modifies
@@ -141,6 +184,17 @@ func ExecuteToolRequestWithCheckMessage(f func(t *testing.T, result mcp.Result))
 	}
 }
 
+// ExecuteToolRequestWithContext connects the session with the given context,
+// e.g. carrying the installation of the request like the STDIO transport. Any
+// nil context will be ignored.
+func ExecuteToolRequestWithContext(ctx context.Context) ExecuteToolRequestOption {
+	return func(opts *ExecuteToolRequestOptions) {
+		if ctx != nil {
+			opts.ctx = ctx
+		}
+	}
+}
+
 // ExecuteToolRequest executes a tool request and validates the response
 func ExecuteToolRequest(
 	t *testing.T,
@@ -153,13 +207,14 @@ func ExecuteToolRequest(
 
 	options := &ExecuteToolRequestOptions{
 		checkMessage: CheckMessage,
+		ctx:          t.Context(),
 	}
 	for _, fn := range optFuncs {
 		fn(options)
 	}
 
 	clientTransport, serverTransport := mcp.NewInMemoryTransports()
-	_, err := mcpServer.Connect(t.Context(), serverTransport, nil)
+	_, err := mcpServer.Connect(options.ctx, serverTransport, nil)
 	if err != nil {
 		t.Fatalf("failed to connect to server: %v", err)
 	}
@@ -0,0 +1,168 @@
+// Package textextract extracts the plain text of documents, such as the files
+// attached to the projects, so their content can be read by the agents. Plain
+// text and Word (.docx) documents are supported out of the box, and other
+// formats (e.g. PDF) can be plugged in, for instance with external commands.
+package textextract
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// docxMaxBodySize is the maximum uncompressed size of the body of a Word
+// document, protecting from compression bombs.
+const docxMaxBodySize = 64 << 20
+
+// ErrUnsupported is returned when there is no extractor for the document
+// type.
+var ErrUnsupported = errors.New("unsupported document type")
+
+// Extractor extracts the plain text of a document.
+type Extractor interface {
+	Extract(ctx context.Context, content []byte) (string, error)
+}
+
+// ExtractorFunc is a function implementing the Extractor interface.
+type ExtractorFunc func(ctx context.Context, content []byte) (string, error)
+
+// Extract calls the function.
+func (f ExtractorFunc) Extract(ctx context.Context, content []byte) (string, error) {
+	return f(ctx, content)
+}
+
+// Registry contains the extractors by lowercase file extension, including the
+// dot (e.g. ".pdf").
+type Registry map[string]Extractor
+
+// Default returns a registry with the built-in extractors.
+func Default() Registry {
+	registry := Registry{
+		".docx": ExtractorFunc(extractDocx),
+	}
+	for _, extension := range []string{".txt", ".md", ".csv", ".tsv", ".json", ".xml", ".log", ".yaml", ".yml"} {
+		registry[extension] = ExtractorFunc(extractPlainText)
+	}
+	return registry
+}
+
+// With returns a copy of the registry with the extractors added, replacing the
+// ones of the same extensions.
+func (r Registry) With(extractors map[string]Extractor) Registry {
+	registry := make(Registry, len(r)+len(extractors))
+	for extension, extractor := range r {
+		registry[extension] = extractor
+	}
+	for extension, extractor := range extractors {
+		registry[strings.ToLower(extension)] = extractor
+	}
+	return registry
+}
+
+// Extract extracts the plain text of the document, choosing the extractor by
+// the extension of its name. ErrUnsupported is returned when there is no
+// extractor for the extension.
+func (r Registry) Extract(ctx context.Context, name string, content []byte) (string, error) {
+	extension := strings.ToLower(filepath.Ext(name))
+	extractor, ok := r[extension]
+	if !ok {
+		return "", fmt.Errorf("%w %q", ErrUnsupported, extension)
+	}
+	return extractor.Extract(ctx, content)
+}
+
+// Command returns an extractor running an external command, which receives
+// the document in its standard input and writes the plain text to its
+// standard output (e.g. "pdftotext - -").
+func Command(name string, args ...string) Extractor {
+	return ExtractorFunc(func(ctx context.Context, content []byte) (string, error) {
+		var stdout, stderr bytes.Buffer
+		cmd := exec.CommandContext(ctx, name, args...)
+		cmd.Stdin = bytes.NewReader(content)
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			return "", fmt.Errorf("failed to run %s: %w: %s", name, err, strings.TrimSpace(stderr.String()))
+		}
+		return extractPlainText(ctx, stdout.Bytes())
+	})
+}
+
+// Commands returns the extractors running the external commands, by file
+// extension. Each command is the program name followed by its arguments.
+func Commands(commands map[string][]string) map[string]Extractor {
+	extractors := make(map[string]Extractor, len(commands))
+	for extension, command := range commands {
+		if len(command) == 0 {
+			continue
+		}
+		extractors[extension] = Command(command[0], command[1:]...)
+	}
+	return extractors
+}
+
+// extractPlainText returns the document as is, as long as it's valid UTF-8.
+func extractPlainText(_ context.Context, content []byte) (string, error) {
+	content = bytes.TrimPrefix(content, []byte("\xef\xbb\xbf"))
+	if !utf8.Valid(content) {
+		return "", errors.New("the document isn't valid UTF-8 text")
+	}
+	return string(content), nil
+}
+
+// extractDocx returns the text of the paragraphs of a Word document.
+func extractDocx(_ context.Context, content []byte) (string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return "", fmt.Errorf("failed to open document: %w", err)
+	}
+	file, err := archive.Open("word/document.xml")
+	if err != nil {
+		return "", fmt.Errorf("failed to open document body: %w", err)
+	}
+	defer file.Close() //nolint:errcheck
+
+	var text strings.Builder
+	decoder := xml.NewDecoder(io.LimitReader(file, docxMaxBodySize))
+	inText := false
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to decode document body: %w", err)
+		}
+		switch element := token.(type) {
+		case xml.StartElement:
+			switch element.Name.Local {
+			case "t":
+				inText = true
+			case "tab":
+				text.WriteByte('\t')
+			case "br", "cr":
+				text.WriteByte('\n')
+			}
+		case xml.EndElement:
+			switch element.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				text.WriteByte('\n')
+			}
+		case xml.CharData:
+			if inText {
+				text.Write(element)
+			}
+		}
+	}
+	return text.String(), nil
+}
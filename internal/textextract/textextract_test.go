@@ -0,0 +1,89 @@
+package textextract_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"os/exec"
+	"testing"
+
+	"github.com/teamwork/mcp/internal/textextract"
+)
+
+func TestRegistryExtract(t *testing.T) {
+	var docx bytes.Buffer
+	archive := zip.NewWriter(&docx)
+	file, err := archive.Create("word/document.xml")
+	if err != nil {
+		t.Fatalf("failed to create document: %v", err)
+	}
+	_, err = file.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>` +
+		`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+		`<w:p><w:r><w:t>Project</w:t></w:r><w:r><w:tab/><w:t xml:space="preserve"> brief</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:t>Launch &amp; review</w:t></w:r></w:p>` +
+		`</w:body></w:document>`))
+	if err != nil {
+		t.Fatalf("failed to write document: %v", err)
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatalf("failed to close document: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		fileName string
+		content  []byte
+		expected string
+		err      error
+	}{{
+		name:     "plain text",
+		fileName: "notes.TXT",
+		content:  []byte("\xef\xbb\xbfhello"),
+		expected: "hello",
+	}, {
+		name:     "word document",
+		fileName: "brief.docx",
+		content:  docx.Bytes(),
+		expected: "Project\t brief\nLaunch & review\n",
+	}, {
+		name:     "unsupported",
+		fileName: "image.png",
+		content:  []byte{0x89, 'P', 'N', 'G'},
+		err:      textextract.ErrUnsupported,
+	}}
+
+	registry := textextract.Default()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, err := registry.Extract(t.Context(), tt.fileName, tt.content)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("expected error %v, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to extract text: %v", err)
+			}
+			if text != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, text)
+			}
+		})
+	}
+}
+
+func TestCommand(t *testing.T) {
+	if _, err := exec.LookPath("tr"); err != nil {
+		t.Skip("tr command not available")
+	}
+	registry := textextract.Default().With(map[string]textextract.Extractor{
+		".PDF": textextract.Command("tr", "a-z", "A-Z"),
+	})
+	text, err := registry.Extract(t.Context(), "brief.pdf", []byte("launch plan"))
+	if err != nil {
+		t.Fatalf("failed to extract text: %v", err)
+	}
+	if text != "LAUNCH PLAN" {
+		t.Errorf("expected %q, got %q", "LAUNCH PLAN", text)
+	}
+}
@@ -0,0 +1,49 @@
+package toolsets
+
+import "github.com/modelcontextprotocol/go-sdk/mcp"
+
+// ToolMiddleware decorates a tool handler, e.g. to log the calls, collect
+// metrics or change the results. The tool name is available in the request
+// parameters.
+type ToolMiddleware func(next mcp.ToolHandler) mcp.ToolHandler
+
+// ChainToolMiddlewares composes the middlewares into a single one. The first
+// middleware is the outermost, so it is the first to receive the request.
+func ChainToolMiddlewares(middlewares ...ToolMiddleware) ToolMiddleware {
+	return func(next mcp.ToolHandler) mcp.ToolHandler {
+		for i := len(middlewares) - 1; i >= 0; i-- {
+			next = middlewares[i](next)
+		}
+		return next
+	}
+}
+
+// UseToolMiddlewares returns a copy of the tools with the handlers decorated by
+// the middlewares. See ChainToolMiddlewares for the order.
+func UseToolMiddlewares(tools []ToolWrapper, middlewares ...ToolMiddleware) []ToolWrapper {
+	middleware := ChainToolMiddlewares(middlewares...)
+	wrapped := make([]ToolWrapper, len(tools))
+	for i, tool := range tools {
+		tool.Handler = middleware(tool.Handler)
+		wrapped[i] = tool
+	}
+	return wrapped
+}
+
+// Use decorates the handlers of the read and write tools of the Toolset with
+// the middlewares. See ChainToolMiddlewares for the order.
+func (t *Toolset) Use(middlewares ...ToolMiddleware) {
+	middleware := ChainToolMiddlewares(middlewares...)
+	t.WrapTools(func(tool ToolWrapper) ToolWrapper {
+		tool.Handler = middleware(tool.Handler)
+		return tool
+	})
+}
+
+// Use decorates the handlers of the tools of all Toolsets in the ToolsetGroup
+// with the middlewares. See ChainToolMiddlewares for the order.
+func (tg *ToolsetGroup) Use(middlewares ...ToolMiddleware) {
+	for _, toolset := range tg.Toolsets {
+		toolset.Use(middlewares...)
+	}
+}
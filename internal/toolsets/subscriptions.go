@@ -0,0 +1,91 @@
+package toolsets
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ResourceSubscriptions allows the clients to subscribe to the resources of a
+// Toolset, notifying them when the resources change. The MCP servers the
+// Toolset is registered with send the notifications.
+//
+// https://modelcontextprotocol.io/specification/2025-06-18/server/resources#subscriptions
+type ResourceSubscriptions struct {
+	match func(uri string) bool
+
+	mu      sync.Mutex
+	servers []*mcp.Server
+}
+
+// NewResourceSubscriptions creates the subscriptions of the resources whose URI
+// is accepted by the match function.
+func NewResourceSubscriptions(match func(uri string) bool) *ResourceSubscriptions {
+	return &ResourceSubscriptions{match: match}
+}
+
+// Matches returns true when the clients can subscribe to the resource.
+func (r *ResourceSubscriptions) Matches(uri string) bool {
+	return r.match(uri)
+}
+
+// Notify informs the clients subscribed to the resource that it changed, so
+// they can read it again.
+func (r *ResourceSubscriptions) Notify(ctx context.Context, uri string) {
+	r.mu.Lock()
+	servers := slices.Clone(r.servers)
+	r.mu.Unlock()
+
+	for _, s := range servers {
+		_ = s.ResourceUpdated(ctx, &mcp.ResourceUpdatedNotificationParams{URI: uri})
+	}
+}
+
+func (r *ResourceSubscriptions) attach(s *mcp.Server) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !slices.Contains(r.servers, s) {
+		r.servers = append(r.servers, s)
+	}
+}
+
+func (r *ResourceSubscriptions) detach(s *mcp.Server) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.servers = slices.DeleteFunc(r.servers, func(server *mcp.Server) bool {
+		return server == s
+	})
+}
+
+// SetResourceSubscriptions allows the clients to subscribe to the resources of
+// the Toolset.
+func (t *Toolset) SetResourceSubscriptions(subscriptions *ResourceSubscriptions) *Toolset {
+	t.subscriptions = subscriptions
+	return t
+}
+
+// HasResourceSubscriptions returns true when any Toolset of the ToolsetGroup,
+// enabled or not, allows subscribing to its resources.
+func (tg *ToolsetGroup) HasResourceSubscriptions() bool {
+	for _, toolset := range tg.Toolsets {
+		if toolset.subscriptions != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// CanSubscribe returns true when an enabled Toolset of the ToolsetGroup allows
+// subscribing to the resource.
+func (tg *ToolsetGroup) CanSubscribe(uri string) bool {
+	for _, toolset := range tg.Toolsets {
+		if toolset.Enabled && toolset.subscriptions != nil && toolset.subscriptions.Matches(uri) {
+			return true
+		}
+	}
+	return false
+}
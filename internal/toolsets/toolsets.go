@@ -5,7 +5,9 @@
 package toolsets
 
 import (
+	"context"
 	"fmt"
+	"maps"
 	"sync"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -109,6 +111,14 @@ func NewServerPrompt(prompt *mcp.Prompt, handler mcp.PromptHandler) ServerPrompt
 	}
 }
 
+// ArgumentCompleter suggests values for an argument, given the partial value
+// typed by the user and the arguments that were already resolved. It is used to
+// answer the MCP completion requests, so clients can present pickers instead of
+// requiring raw IDs.
+//
+// https://modelcontextprotocol.io/specification/2025-06-18/server/utilities/completion
+type ArgumentCompleter func(ctx context.Context, value string, arguments map[string]string) ([]string, error)
+
 // ToolWrapper is a simple struct that wraps an MCP tool and its handler.
 type ToolWrapper struct {
 	Tool *mcp.Tool
@@ -136,6 +146,11 @@ type Toolset struct {
 	resourceTemplates []ServerResourceTemplate
 	// prompts are also not tools but are namespaced similarly
 	prompts []ServerPrompt
+	// completers suggest values for the arguments, indexed by argument name
+	completers map[string]ArgumentCompleter
+	// subscriptions notify the clients subscribed to the resources when they
+	// change
+	subscriptions *ResourceSubscriptions
 }
 
 // NewToolset creates a new Toolset with the given method and description. The
@@ -185,6 +200,58 @@ func (t *Toolset) RegisterTools(s *mcp.Server) {
 	}
 }
 
+// UnregisterAll removes the active tools, resource templates and prompts of the
+// Toolset from the MCP server.
+func (t *Toolset) UnregisterAll(s *mcp.Server) {
+	if !t.Enabled {
+		return
+	}
+	tools := t.GetActiveTools()
+	toolNames := make([]string, len(tools))
+	for i, tool := range tools {
+		toolNames[i] = tool.Tool.Name
+	}
+	s.RemoveTools(toolNames...)
+
+	uriTemplates := make([]string, len(t.resourceTemplates))
+	for i, resource := range t.resourceTemplates {
+		uriTemplates[i] = resource.resourceTemplate.URITemplate
+	}
+	s.RemoveResourceTemplates(uriTemplates...)
+	if t.subscriptions != nil {
+		t.subscriptions.detach(s)
+	}
+
+	promptNames := make([]string, len(t.prompts))
+	for i, prompt := range t.prompts {
+		promptNames[i] = prompt.Prompt.Name
+	}
+	s.RemovePrompts(promptNames...)
+}
+
+// WrapTools replaces the read and write tools of the Toolset by the result of
+// the given function, allowing to decorate the tools (e.g. change the metadata
+// or intercept the handler).
+func (t *Toolset) WrapTools(fn func(ToolWrapper) ToolWrapper) {
+	for i, tool := range t.readTools {
+		t.readTools[i] = fn(tool)
+	}
+	for i, tool := range t.writeTools {
+		t.writeTools[i] = fn(tool)
+	}
+}
+
+// WrapToolList returns a copy of the tools decorated by the given function. It
+// is the counterpart of Toolset.WrapTools for the tools not added to a Toolset
+// yet.
+func WrapToolList(tools []ToolWrapper, fn func(ToolWrapper) ToolWrapper) []ToolWrapper {
+	wrapped := make([]ToolWrapper, len(tools))
+	for i, tool := range tools {
+		wrapped[i] = fn(tool)
+	}
+	return wrapped
+}
+
 // AddResourceTemplates adds resource templates to the Toolset. These templates
 // can be used to define resources that the MCP server can manage.
 func (t *Toolset) AddResourceTemplates(templates ...ServerResourceTemplate) *Toolset {
@@ -199,6 +266,26 @@ func (t *Toolset) AddPrompts(prompts ...ServerPrompt) *Toolset {
 	return t
 }
 
+// AddCompleter adds a completer for the argument with the given name. The same
+// argument name has the same meaning in all tools and prompts (e.g.
+// "project_id"), so the completer isn't tied to any of them.
+func (t *Toolset) AddCompleter(argument string, completer ArgumentCompleter) *Toolset {
+	if t.completers == nil {
+		t.completers = make(map[string]ArgumentCompleter)
+	}
+	t.completers[argument] = completer
+	return t
+}
+
+// GetActiveCompleters returns the argument completers of the Toolset, if it is
+// enabled.
+func (t *Toolset) GetActiveCompleters() map[string]ArgumentCompleter {
+	if !t.Enabled {
+		return nil
+	}
+	return t.completers
+}
+
 // GetActiveResourceTemplates returns the resource templates that are currently
 // active in the Toolset. If the Toolset is enabled, it returns all resource
 // templates.
@@ -217,7 +304,8 @@ func (t *Toolset) GetAvailableResourceTemplates() []ServerResourceTemplate {
 }
 
 // RegisterResourcesTemplates registers the resource templates in the Toolset
-// with the MCP server.
+// with the MCP server. The server notifies the clients subscribed to the
+// resources of the Toolset when they change.
 func (t *Toolset) RegisterResourcesTemplates(s *mcp.Server) {
 	if !t.Enabled {
 		return
@@ -225,6 +313,9 @@ func (t *Toolset) RegisterResourcesTemplates(s *mcp.Server) {
 	for _, resource := range t.resourceTemplates {
 		s.AddResourceTemplate(resource.resourceTemplate, resource.handler)
 	}
+	if t.subscriptions != nil {
+		t.subscriptions.attach(s)
+	}
 }
 
 // RegisterPrompts registers the prompts in the Toolset with the MCP server.
@@ -272,6 +363,37 @@ func (t *Toolset) AddReadTools(tools ...ToolWrapper) *Toolset {
 	return t
 }
 
+// ToolsetProvider provides the tools of a Toolset. It allows registering
+// custom toolsets (e.g. company-specific composite tools) in the same way as
+// the built-in ones.
+type ToolsetProvider interface {
+	// Method identifies the Toolset.
+	Method() Method
+	// Description describes the Toolset.
+	Description() string
+	// Methods lists the methods of the tools. They are registered, so the tools
+	// can be validated when enabling Toolsets.
+	Methods() []Method
+	// ReadTools returns the tools that don't change any data. They must be
+	// annotated as read-only.
+	ReadTools() []ToolWrapper
+	// WriteTools returns the tools that change data. They are ignored in
+	// read-only mode.
+	WriteTools() []ToolWrapper
+}
+
+// NewToolsetFromProvider creates a new Toolset with the tools of the provider,
+// registering its methods.
+func NewToolsetFromProvider(provider ToolsetProvider) *Toolset {
+	RegisterMethod(provider.Method())
+	for _, method := range provider.Methods() {
+		RegisterMethod(method)
+	}
+	return NewToolset(provider.Method(), provider.Description()).
+		AddWriteTools(provider.WriteTools()...).
+		AddReadTools(provider.ReadTools()...)
+}
+
 // ToolsetGroup is a collection of Toolsets that can be enabled or disabled as a
 // group. It allows for managing multiple Toolsets and their states
 // collectively.
@@ -279,6 +401,8 @@ type ToolsetGroup struct {
 	Toolsets     map[Method]*Toolset
 	everythingOn bool
 	readOnly     bool
+	// onChange are called when a Toolset is enabled or disabled at runtime
+	onChange []func()
 }
 
 // NewToolsetGroup creates a new ToolsetGroup. If readOnly is true, all Toolsets
@@ -301,6 +425,15 @@ func (tg *ToolsetGroup) AddToolset(ts *Toolset) {
 	tg.Toolsets[ts.Method] = ts
 }
 
+// AddProvider adds a Toolset with the tools of the provider to the
+// ToolsetGroup. The Toolset is returned, so other features (e.g. prompts) can be
+// added to it.
+func (tg *ToolsetGroup) AddProvider(provider ToolsetProvider) *Toolset {
+	toolset := NewToolsetFromProvider(provider)
+	tg.AddToolset(toolset)
+	return toolset
+}
+
 // IsEnabled checks if a Toolset with the given method is enabled in the
 // ToolsetGroup.
 func (tg *ToolsetGroup) IsEnabled(method Method) bool {
@@ -353,6 +486,57 @@ func (tg *ToolsetGroup) EnableToolset(method Method) error {
 	return nil
 }
 
+// DisableToolset disables a Toolset by its method. If the Toolset does not
+// exist, it returns a ToolsetDoesNotExistError.
+func (tg *ToolsetGroup) DisableToolset(method Method) error {
+	toolset, exists := tg.Toolsets[method]
+	if !exists {
+		return NewToolsetDoesNotExistError(method)
+	}
+	toolset.Enabled = false
+	tg.everythingOn = false
+	return nil
+}
+
+// SetToolsetEnabled enables or disables a Toolset of a running MCP server,
+// adding or removing its tools, resource templates and prompts. The server
+// notifies the connected clients that the lists changed (e.g.
+// notifications/tools/list_changed) and the OnChange functions are called. If
+// the Toolset does not exist, it returns a ToolsetDoesNotExistError.
+func (tg *ToolsetGroup) SetToolsetEnabled(s *mcp.Server, method Method, enabled bool) error {
+	toolset, exists := tg.Toolsets[method]
+	if !exists {
+		return NewToolsetDoesNotExistError(method)
+	}
+	if toolset.Enabled == enabled {
+		return nil
+	}
+
+	if enabled {
+		if err := tg.EnableToolset(method); err != nil {
+			return err
+		}
+		toolset.RegisterTools(s)
+		toolset.RegisterResourcesTemplates(s)
+		toolset.RegisterPrompts(s)
+	} else {
+		toolset.UnregisterAll(s)
+		if err := tg.DisableToolset(method); err != nil {
+			return err
+		}
+	}
+	for _, fn := range tg.onChange {
+		fn()
+	}
+	return nil
+}
+
+// OnChange registers a function called each time a Toolset is enabled or
+// disabled at runtime with SetToolsetEnabled (e.g. to invalidate caches).
+func (tg *ToolsetGroup) OnChange(fn func()) {
+	tg.onChange = append(tg.onChange, fn)
+}
+
 // RegisterAll registers all Toolsets in the ToolsetGroup with the MCP server.
 func (tg *ToolsetGroup) RegisterAll(s *mcp.Server) {
 	for _, toolset := range tg.Toolsets {
@@ -362,6 +546,24 @@ func (tg *ToolsetGroup) RegisterAll(s *mcp.Server) {
 	}
 }
 
+// WrapTools decorates the tools of all Toolsets in the ToolsetGroup. See
+// Toolset.WrapTools.
+func (tg *ToolsetGroup) WrapTools(fn func(ToolWrapper) ToolWrapper) {
+	for _, toolset := range tg.Toolsets {
+		toolset.WrapTools(fn)
+	}
+}
+
+// GetActiveCompleters returns the argument completers of all enabled Toolsets in
+// the ToolsetGroup, indexed by argument name.
+func (tg *ToolsetGroup) GetActiveCompleters() map[string]ArgumentCompleter {
+	completers := make(map[string]ArgumentCompleter)
+	for _, toolset := range tg.Toolsets {
+		maps.Copy(completers, toolset.GetActiveCompleters())
+	}
+	return completers
+}
+
 // GetToolset retrieves a Toolset by its method from the ToolsetGroup. If the
 // Toolset does not exist, it returns a ToolsetDoesNotExistError.
 func (tg *ToolsetGroup) GetToolset(method Method) (*Toolset, error) {
@@ -1,13 +1,34 @@
 package twdesk
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"net/url"
 
 	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	deskmodels "github.com/teamwork/desksdkgo/models"
 	"github.com/teamwork/mcp/internal/helpers"
+	"github.com/teamwork/mcp/internal/toolsets"
 )
 
+// defaultPageSize is the page size of the list tools when the pageSize
+// parameter isn't provided.
+const defaultPageSize = 10
+
+// countOnlyParam is the parameter of the list tools to only return the total
+// number of matching records.
+const countOnlyParam = "countOnly"
+
+// paginationParams are the pagination parameters of the list tools, added by
+// paginationOptions.
+var paginationParams = helpers.PaginationParams{
+	Page:            "page",
+	PageSize:        "pageSize",
+	DefaultPageSize: defaultPageSize,
+}
+
 func paginationOptions(properties map[string]*jsonschema.Schema) map[string]*jsonschema.Schema {
 	if properties == nil {
 		properties = make(map[string]*jsonschema.Schema)
@@ -28,12 +49,60 @@ func paginationOptions(properties map[string]*jsonschema.Schema) map[string]*jso
 		Type:        "string",
 		Description: "The direction to order the results by (asc, desc).",
 	}
+	properties[countOnlyParam] = &jsonschema.Schema{
+		Type: "boolean",
+		Description: "If true, only the total number of matching records is returned, instead of the records " +
+			"themselves. Pagination parameters are ignored.",
+	}
 	return properties
 }
 
 func setPagination(v *url.Values, arguments helpers.ToolArguments) {
-	v.Set("page", fmt.Sprintf("%d", arguments.GetInt("page", 1)))
-	v.Set("pageSize", fmt.Sprintf("%d", arguments.GetInt("pageSize", 10)))
+	if arguments.GetBool(countOnlyParam, false) {
+		// the total number of records is in the metadata of the response
+		v.Set("page", "1")
+		v.Set("pageSize", "1")
+	} else {
+		v.Set("page", fmt.Sprintf("%d", arguments.GetInt("page", 1)))
+		v.Set("pageSize", fmt.Sprintf("%d", arguments.GetInt("pageSize", defaultPageSize)))
+	}
 	v.Set("orderBy", arguments.GetString("orderBy", "createdAt"))
 	v.Set("orderMode", arguments.GetString("orderDirection", "desc"))
 }
+
+// countResults wraps the list tools, replacing the response with the total
+// number of records when the countOnly parameter is set. The structured content
+// is discarded, as it must follow the output schema of the list tool.
+func countResults(tools []toolsets.ToolWrapper) []toolsets.ToolWrapper {
+	return toolsets.WrapToolList(tools, func(tool toolsets.ToolWrapper) toolsets.ToolWrapper {
+		if schema, ok := tool.Tool.InputSchema.(*jsonschema.Schema); !ok || schema.Properties[countOnlyParam] == nil {
+			return tool
+		}
+		handler := tool.Handler
+		tool.Handler = func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := handler(ctx, request)
+			if err != nil || result == nil || result.IsError {
+				return result, err
+			}
+			arguments, err := helpers.NewToolArguments(request)
+			if err != nil || !arguments.GetBool(countOnlyParam, false) {
+				return result, nil
+			}
+			for _, content := range result.Content {
+				text, ok := content.(*mcp.TextContent)
+				if !ok {
+					continue
+				}
+				var response struct {
+					Meta deskmodels.Meta `json:"meta"`
+				}
+				if err := json.Unmarshal([]byte(text.Text), &response); err != nil {
+					continue
+				}
+				return helpers.NewToolResultText(`{"count":%d}`, response.Meta.Page.Count), nil
+			}
+			return result, nil
+		}
+		return tool
+	})
+}
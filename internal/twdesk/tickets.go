@@ -94,12 +94,14 @@ func TicketGet(client *deskclient.Client) toolsets.ToolWrapper {
 				return nil, err
 			}
 
+			linked := helpers.WebLinker(ctx, encoded,
+				helpers.WebLinkerWithIDPathBuilder("/desk/tickets"),
+			)
 			return &mcp.CallToolResult{
+				Meta: helpers.WebLinkedEntitiesMeta(linked),
 				Content: []mcp.Content{
 					&mcp.TextContent{
-						Text: string(helpers.WebLinker(ctx, encoded,
-							helpers.WebLinkerWithIDPathBuilder("/desk/tickets"),
-						)),
+						Text: string(linked),
 					},
 				},
 				StructuredContent: ticket,
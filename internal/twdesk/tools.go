@@ -2,11 +2,12 @@ package twdesk
 
 import (
 	deskclient "github.com/teamwork/desksdkgo/client"
+	"github.com/teamwork/mcp/internal/helpers"
 	"github.com/teamwork/mcp/internal/toolsets"
 )
 
-// DefaultToolsetGroup creates a default ToolsetGroup for Teamwork Projects.
-func DefaultToolsetGroup(client *deskclient.Client) *toolsets.ToolsetGroup {
+// DefaultToolsetGroup creates a default ToolsetGroup for Teamwork Desk.
+func DefaultToolsetGroup(readOnly bool, client *deskclient.Client) *toolsets.ToolsetGroup {
 	readTools := []toolsets.ToolWrapper{
 		CompanyGet(client),
 		CompanyList(client),
@@ -29,6 +30,8 @@ func DefaultToolsetGroup(client *deskclient.Client) *toolsets.ToolsetGroup {
 		UserList(client),
 	}
 
+	readTools = helpers.Paginate(countResults(readTools), paginationParams)
+
 	writeTools := []toolsets.ToolWrapper{
 		CompanyCreate(client),
 		CompanyUpdate(client),
@@ -48,7 +51,7 @@ func DefaultToolsetGroup(client *deskclient.Client) *toolsets.ToolsetGroup {
 		TypeUpdate(client),
 	}
 
-	group := toolsets.NewToolsetGroup(false)
+	group := toolsets.NewToolsetGroup(readOnly)
 	group.AddToolset(toolsets.NewToolset("desk", projectDescription).
 		AddWriteTools(writeTools...).
 		AddReadTools(readTools...))
@@ -75,30 +75,7 @@ func ActivityList(engine *twapi.Engine) toolsets.ToolWrapper {
 						Description: "Filter activities by item types.",
 						Items: &jsonschema.Schema{
 							Type: "string",
-							Enum: []any{
-								"message",
-								"comment",
-								"task",
-								"tasklist",
-								"taskgroup",
-								"milestone",
-								"file",
-								"form",
-								"notebook",
-								"timelog",
-								"task_comment",
-								"notebook_comment",
-								"file_comment",
-								"link_comment",
-								"milestone_comment",
-								"project",
-								"link",
-								"billingInvoice",
-								"risk",
-								"projectUpdate",
-								"reacted",
-								"budget",
-							},
+							Enum: enumSchemaValues(activityLogItemTypes),
 						},
 					},
 					"page": {
@@ -109,12 +86,14 @@ func ActivityList(engine *twapi.Engine) toolsets.ToolWrapper {
 						Type:        "integer",
 						Description: "Number of results per page for pagination.",
 					},
+					"count_only": countOnlySchema(),
 				},
 			},
 			OutputSchema: activityListOutputSchema,
 		},
 		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			var activityListRequest projects.ActivityListRequest
+			var countOnly bool
 
 			var arguments map[string]any
 			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
@@ -126,11 +105,16 @@ func ActivityList(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalListParam(&activityListRequest.Filters.LogItemTypes, "log_item_types"),
 				helpers.OptionalNumericParam(&activityListRequest.Filters.Page, "page"),
 				helpers.OptionalNumericParam(&activityListRequest.Filters.PageSize, "page_size"),
+				helpers.OptionalParam(&countOnly, "count_only"),
 			)
 			if err != nil {
 				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
 			}
 
+			if countOnly {
+				return listCount(ctx, engine, activityListRequest)
+			}
+
 			activityList, err := projects.ActivityList(ctx, engine, activityListRequest)
 			if err != nil {
 				return helpers.HandleAPIError(err, "failed to list activities")
@@ -172,30 +156,7 @@ func ActivityListByProject(engine *twapi.Engine) toolsets.ToolWrapper {
 						Description: "Filter activities by item types.",
 						Items: &jsonschema.Schema{
 							Type: "string",
-							Enum: []any{
-								"message",
-								"comment",
-								"task",
-								"tasklist",
-								"taskgroup",
-								"milestone",
-								"file",
-								"form",
-								"notebook",
-								"timelog",
-								"task_comment",
-								"notebook_comment",
-								"file_comment",
-								"link_comment",
-								"milestone_comment",
-								"project",
-								"link",
-								"billingInvoice",
-								"risk",
-								"projectUpdate",
-								"reacted",
-								"budget",
-							},
+							Enum: enumSchemaValues(activityLogItemTypes),
 						},
 					},
 					"page": {
@@ -206,12 +167,14 @@ func ActivityListByProject(engine *twapi.Engine) toolsets.ToolWrapper {
 						Type:        "integer",
 						Description: "Number of results per page for pagination.",
 					},
+					"count_only": countOnlySchema(),
 				},
 			},
 			OutputSchema: activityListOutputSchema,
 		},
 		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			var activityListRequest projects.ActivityListRequest
+			var countOnly bool
 
 			var arguments map[string]any
 			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
@@ -224,11 +187,16 @@ func ActivityListByProject(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalListParam(&activityListRequest.Filters.LogItemTypes, "log_item_types"),
 				helpers.OptionalNumericParam(&activityListRequest.Filters.Page, "page"),
 				helpers.OptionalNumericParam(&activityListRequest.Filters.PageSize, "page_size"),
+				helpers.OptionalParam(&countOnly, "count_only"),
 			)
 			if err != nil {
 				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
 			}
 
+			if countOnly {
+				return listCount(ctx, engine, activityListRequest)
+			}
+
 			activityList, err := projects.ActivityList(ctx, engine, activityListRequest)
 			if err != nil {
 				return helpers.HandleAPIError(err, "failed to list activities")
@@ -0,0 +1,293 @@
+package twprojects
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/config"
+	"github.com/teamwork/mcp/internal/helpers"
+	"github.com/teamwork/mcp/internal/toolsets"
+)
+
+// previewIDParam is the name of the parameter of the bulk tools referencing the
+// preview to execute.
+const previewIDParam = "preview_id"
+
+// previewsURIPrefix is the URI prefix of the resources with the previews of the
+// bulk tools.
+const previewsURIPrefix = "twprojects://previews/"
+
+// bulkPreviewTTL is the time a preview can be reviewed and executed.
+const bulkPreviewTTL = time.Hour
+
+// bulkPreviewModes returns, for each bulk tool, whether a call changes data and
+// the arguments of the same call without changes, whose result lists the
+// intended changes. Tools without a dry-run mode return nil arguments, and the
+// call arguments are the intended changes.
+var bulkPreviewModes = map[toolsets.Method]func(arguments map[string]any) (map[string]any, bool){
+	MethodStaleTaskSweep: func(arguments map[string]any) (map[string]any, bool) {
+		action, _ := arguments["action"].(string)
+		if action == "" || action == "none" {
+			return nil, false
+		}
+		if action == "move" && arguments["stale_tasklist_id"] == nil {
+			// the tool rejects the call itself, there is nothing to preview
+			return nil, false
+		}
+		dryRun := maps.Clone(arguments)
+		dryRun["action"] = "none"
+		return dryRun, true
+	},
+	MethodUserImport: func(map[string]any) (map[string]any, bool) {
+		return nil, true
+	},
+	MethodRoleTemplateApply: func(arguments map[string]any) (map[string]any, bool) {
+		if apply, _ := arguments["apply"].(bool); !apply {
+			return nil, false
+		}
+		dryRun := maps.Clone(arguments)
+		dryRun["apply"] = false
+		return dryRun, true
+	},
+}
+
+// bulkPreview contains the changes a bulk tool call intends to do, waiting to
+// be executed.
+type bulkPreview struct {
+	ID              string          `json:"id"`
+	Tool            string          `json:"tool"`
+	Arguments       map[string]any  `json:"arguments"`
+	IntendedChanges json.RawMessage `json:"intendedChanges"`
+	CreatedAt       time.Time       `json:"createdAt"`
+	ExpiresAt       time.Time       `json:"expiresAt"`
+
+	// owner is the installation and the user that created the preview, so it
+	// can't be reviewed or executed by someone else.
+	owner string
+}
+
+// bulkPreviews makes the bulk tools work in two phases. A call without a
+// preview ID only stores the intended changes as an MCP resource, so they can
+// be inspected by a human reviewer, and a follow-up call referencing the
+// preview ID executes them.
+type bulkPreviews struct {
+	mu       sync.Mutex
+	previews map[string]bulkPreview
+}
+
+func newBulkPreviews() *bulkPreviews {
+	return &bulkPreviews{
+		previews: make(map[string]bulkPreview),
+	}
+}
+
+// apply wraps the bulk tools with the preview phase. Other tools are returned
+// unchanged.
+func (b *bulkPreviews) apply(tools []toolsets.ToolWrapper) []toolsets.ToolWrapper {
+	return toolsets.WrapToolList(tools, func(tool toolsets.ToolWrapper) toolsets.ToolWrapper {
+		mode, ok := bulkPreviewModes[toolsets.Method(tool.Tool.Name)]
+		if !ok {
+			return tool
+		}
+		toolCopy, ok := withInputSchema(tool.Tool, func(schema *jsonschema.Schema) {
+			schema.Properties[previewIDParam] = &jsonschema.Schema{
+				Type: "string",
+				Description: "The ID of the preview to execute. Changes are only made when it's provided, with the " +
+					"same arguments of the call that created the preview.",
+			}
+		})
+		if !ok {
+			return tool
+		}
+		toolCopy.Description += " Changes are made in two phases: a call without preview_id only creates a preview " +
+			"with the intended changes, stored as a resource for review, and a second call with the same arguments " +
+			"and the preview_id executes it."
+		tool.Tool = toolCopy
+		tool.Handler = b.middleware(mode)(tool.Handler)
+		return tool
+	})
+}
+
+// middleware creates the preview of the calls without a preview ID, and
+// executes the preview referenced by the other calls.
+func (b *bulkPreviews) middleware(mode func(map[string]any) (map[string]any, bool)) toolsets.ToolMiddleware {
+	return func(handler mcp.ToolHandler) mcp.ToolHandler {
+		return func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var arguments map[string]any
+			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+				return handler(ctx, request)
+			}
+			owner := previewOwner(ctx)
+
+			if previewID, ok := arguments[previewIDParam].(string); ok {
+				delete(arguments, previewIDParam)
+				preview, found := b.take(previewID, owner)
+				if !found || preview.Tool != request.Params.Name {
+					return helpers.NewToolResultTextError(fmt.Sprintf("preview %q not found or expired, call the tool "+
+						"without preview_id to create a new preview", previewID)), nil
+				}
+				if !reflect.DeepEqual(preview.Arguments, arguments) {
+					return helpers.NewToolResultTextError(fmt.Sprintf("the arguments don't match the ones of preview %q, "+
+						"call the tool with the same arguments or create a new preview", previewID)), nil
+				}
+				return callWithArguments(ctx, handler, request, arguments)
+			}
+
+			dryRun, writes := mode(arguments)
+			if !writes {
+				return handler(ctx, request)
+			}
+
+			intendedChanges, err := json.Marshal(arguments)
+			if err != nil {
+				return nil, err
+			}
+			if dryRun != nil {
+				result, err := callWithArguments(ctx, handler, request, dryRun)
+				if err != nil || result == nil || result.IsError {
+					return result, err
+				}
+				if len(result.Content) > 0 {
+					if text, ok := result.Content[0].(*mcp.TextContent); ok && json.Valid([]byte(text.Text)) {
+						intendedChanges = []byte(text.Text)
+					}
+				}
+			}
+
+			preview, err := b.add(request.Params.Name, arguments, intendedChanges, owner)
+			if err != nil {
+				return nil, err
+			}
+			encoded, err := json.Marshal(preview)
+			if err != nil {
+				return nil, err
+			}
+			uri := previewsURIPrefix + preview.ID
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					&mcp.TextContent{
+						Text: fmt.Sprintf("Preview %s created, nothing was changed yet. The intended changes are available "+
+							"for review in the resource %s. Call the tool again with the same arguments and preview_id %q "+
+							"to execute them before %s. Preview: %s", preview.ID, uri, preview.ID,
+							preview.ExpiresAt.Format(time.RFC3339), encoded),
+					},
+					&mcp.ResourceLink{
+						URI:      uri,
+						Name:     "preview-" + preview.ID,
+						MIMEType: "application/json",
+					},
+				},
+			}, nil
+		}
+	}
+}
+
+// add stores a new preview.
+func (b *bulkPreviews) add(
+	tool string,
+	arguments map[string]any,
+	intendedChanges json.RawMessage,
+	owner string,
+) (bulkPreview, error) {
+	var random [16]byte
+	if _, err := rand.Read(random[:]); err != nil {
+		return bulkPreview{}, fmt.Errorf("failed to generate preview ID: %w", err)
+	}
+	now := time.Now().UTC()
+	preview := bulkPreview{
+		ID:              hex.EncodeToString(random[:]),
+		Tool:            tool,
+		Arguments:       arguments,
+		IntendedChanges: intendedChanges,
+		CreatedAt:       now,
+		ExpiresAt:       now.Add(bulkPreviewTTL),
+		owner:           owner,
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.expire()
+	b.previews[preview.ID] = preview
+	return preview, nil
+}
+
+// take removes and returns a preview, so it's executed only once.
+func (b *bulkPreviews) take(id, owner string) (bulkPreview, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.expire()
+	preview, ok := b.previews[id]
+	if !ok || preview.owner != owner {
+		return bulkPreview{}, false
+	}
+	delete(b.previews, id)
+	return preview, true
+}
+
+// expire removes the expired previews. It must be called with the lock held.
+func (b *bulkPreviews) expire() {
+	now := time.Now()
+	for id, preview := range b.previews {
+		if now.After(preview.ExpiresAt) {
+			delete(b.previews, id)
+		}
+	}
+}
+
+// previewOwner identifies the installation and the user of the request.
+func previewOwner(ctx context.Context) string {
+	customerURL, _ := config.CustomerURLFromContext(ctx)
+	userID, _ := config.UserIDFromContext(ctx)
+	return customerURL + "|" + strconv.FormatInt(userID, 10)
+}
+
+// ResourceTemplate returns the resource template serving the previews.
+func (b *bulkPreviews) ResourceTemplate() toolsets.ServerResourceTemplate {
+	return toolsets.NewServerResourceTemplate(&mcp.ResourceTemplate{
+		Name:        "preview",
+		Title:       "Bulk Change Preview",
+		Description: "Changes intended by a bulk tool call, waiting to be executed with the preview ID.",
+		URITemplate: previewsURIPrefix + "{id}",
+		MIMEType:    "application/json",
+	}, b.read)
+}
+
+func (b *bulkPreviews) read(ctx context.Context, request *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	uri := request.Params.URI
+	id, ok := strings.CutPrefix(uri, previewsURIPrefix)
+	if !ok {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+	owner := previewOwner(ctx)
+
+	b.mu.Lock()
+	b.expire()
+	preview, found := b.previews[id]
+	b.mu.Unlock()
+	if !found || preview.owner != owner {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+
+	encoded, err := json.MarshalIndent(preview, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{{
+			URI:      uri,
+			MIMEType: "application/json",
+			Text:     string(encoded),
+		}},
+	}, nil
+}
@@ -0,0 +1,171 @@
+package twprojects_test
+
+import (
+	"maps"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/config"
+	"github.com/teamwork/mcp/internal/testutil"
+	"github.com/teamwork/mcp/internal/twprojects"
+)
+
+var rePreviewID = regexp.MustCompile(`preview_id "([0-9a-f]+)"`)
+
+// createPreview calls a bulk tool without preview ID, returning the ID of the
+// created preview.
+func createPreview(
+	t *testing.T,
+	mcpServer *mcp.Server,
+	toolName string,
+	args map[string]any,
+	optFuncs ...testutil.ExecuteToolRequestOption,
+) string {
+	t.Helper()
+
+	var previewID string
+	testutil.ExecuteToolRequest(t, mcpServer, toolName, args, append(optFuncs,
+		testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+			toolResult, ok := result.(*mcp.CallToolResult)
+			if !ok {
+				t.Fatalf("unexpected result type: %T", result)
+			}
+			if toolResult.IsError {
+				t.Fatalf("tool failed to create the preview: %v", toolResult.Content)
+			}
+			text := toolResult.Content[0].(*mcp.TextContent).Text
+			matches := rePreviewID.FindStringSubmatch(text)
+			if len(matches) < 2 {
+				t.Fatalf("preview ID not found in %s", text)
+			}
+			previewID = matches[1]
+		}),
+	)...)
+	return previewID
+}
+
+// executePreviewedToolRequest executes a bulk tool in two phases, creating the
+// preview and executing it with the same arguments.
+func executePreviewedToolRequest(
+	t *testing.T,
+	mcpServer *mcp.Server,
+	toolName string,
+	args map[string]any,
+	optFuncs ...testutil.ExecuteToolRequestOption,
+) {
+	t.Helper()
+
+	previewID := createPreview(t, mcpServer, toolName, args)
+	args = maps.Clone(args)
+	args["preview_id"] = previewID
+	testutil.ExecuteToolRequest(t, mcpServer, toolName, args, optFuncs...)
+}
+
+func TestBulkPreview(t *testing.T) {
+	var creates int
+	mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(r *http.Request) (int, []byte) {
+		if r.Method != http.MethodPost || r.URL.Path != "/people.json" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		creates++
+		return http.StatusCreated, []byte(`{"id":"1"}`)
+	})
+	args := map[string]any{
+		"users": []any{
+			map[string]any{"first_name": "Ada", "last_name": "Lovelace", "email": "ada@example.com"},
+		},
+	}
+	previewID := createPreview(t, mcpServer, twprojects.MethodUserImport.String(), args)
+	if creates != 0 {
+		t.Fatalf("expected no changes when creating the preview, got %d users created", creates)
+	}
+
+	// the preview is available for review as a resource
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	if _, err := mcpServer.Connect(t.Context(), serverTransport, nil); err != nil {
+		t.Fatalf("failed to connect to server: %v", err)
+	}
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	clientSession, err := client.Connect(t.Context(), clientTransport, nil)
+	if err != nil {
+		t.Fatalf("failed to connect to client: %v", err)
+	}
+	defer clientSession.Close() //nolint:errcheck
+	resource, err := clientSession.ReadResource(t.Context(), &mcp.ReadResourceParams{
+		URI: "twprojects://previews/" + previewID,
+	})
+	if err != nil {
+		t.Fatalf("failed to read preview: %v", err)
+	}
+	if text := resource.Contents[0].Text; !strings.Contains(text, `"ada@example.com"`) {
+		t.Errorf("expected the preview to contain the users, got %s", text)
+	}
+
+	checkError := func(fragment string) testutil.ExecuteToolRequestOption {
+		return testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+			toolResult, ok := result.(*mcp.CallToolResult)
+			if !ok {
+				t.Fatalf("unexpected result type: %T", result)
+			}
+			if !toolResult.IsError {
+				t.Fatalf("expected an error result")
+			}
+			if text := toolResult.Content[0].(*mcp.TextContent).Text; !strings.Contains(text, fragment) {
+				t.Errorf("expected error containing %q, got %s", fragment, text)
+			}
+		})
+	}
+
+	// the arguments must match the reviewed ones
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodUserImport.String(), map[string]any{
+		"users": []any{
+			map[string]any{"first_name": "Eve", "last_name": "Hacker", "email": "eve@example.com"},
+		},
+		"preview_id": previewID,
+	}, checkError("don't match"))
+
+	// the preview can only be executed once
+	previewID = createPreview(t, mcpServer, twprojects.MethodUserImport.String(), args)
+	args["preview_id"] = previewID
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodUserImport.String(), args)
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodUserImport.String(), args, checkError("not found"))
+	if creates != 1 {
+		t.Errorf("expected 1 user created, got %d", creates)
+	}
+}
+
+func TestBulkPreviewOtherUser(t *testing.T) {
+	var creates int
+	mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(*http.Request) (int, []byte) {
+		creates++
+		return http.StatusCreated, []byte(`{"id":"1"}`)
+	})
+	ctx := config.WithCustomerURL(t.Context(), "https://example.teamwork.com")
+	args := map[string]any{
+		"users": []any{
+			map[string]any{"first_name": "Ada", "last_name": "Lovelace", "email": "ada@example.com"},
+		},
+	}
+	args["preview_id"] = createPreview(t, mcpServer, twprojects.MethodUserImport.String(), args,
+		testutil.ExecuteToolRequestWithContext(config.WithUserID(ctx, 1)))
+
+	// the preview of another user of the installation can't be executed
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodUserImport.String(), args,
+		testutil.ExecuteToolRequestWithContext(config.WithUserID(ctx, 2)),
+		testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+			toolResult, ok := result.(*mcp.CallToolResult)
+			if !ok {
+				t.Fatalf("unexpected result type: %T", result)
+			}
+			if !toolResult.IsError {
+				t.Errorf("expected an error result, got %v", toolResult.Content)
+			}
+		}),
+	)
+	if creates != 0 {
+		t.Errorf("expected no users created, got %d", creates)
+	}
+}
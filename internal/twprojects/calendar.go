@@ -0,0 +1,266 @@
+package twprojects
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/helpers"
+	"github.com/teamwork/mcp/internal/toolsets"
+	twapi "github.com/teamwork/twapi-go-sdk"
+	"github.com/teamwork/twapi-go-sdk/projects"
+)
+
+// List of methods available in the Teamwork.com MCP service.
+//
+// The naming convention for methods follows a pattern described here:
+// https://github.com/github/github-mcp-server/issues/333
+const (
+	MethodProjectCalendarGet toolsets.Method = "twprojects-get_project_calendar"
+)
+
+// calendarURIPrefix and calendarURISuffix surround the project ID in the URI of
+// the calendar feed resources.
+const (
+	calendarURIPrefix = "twprojects://projects/"
+	calendarURISuffix = "/calendar.ics"
+)
+
+// calendarMaxTasks is the maximum number of tasks in a calendar feed.
+const calendarMaxTasks = 1000
+
+func init() {
+	// register the toolset methods
+	toolsets.RegisterMethod(MethodProjectCalendarGet)
+}
+
+// calendarEvent is an all-day event of a calendar feed.
+type calendarEvent struct {
+	uid         string
+	summary     string
+	description string
+	date        time.Time
+}
+
+// ProjectCalendarGet generates the calendar feed, in the iCalendar (ICS)
+// format, with the milestones and the task due dates of a project.
+func ProjectCalendarGet(engine *twapi.Engine) toolsets.ToolWrapper {
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name: string(MethodProjectCalendarGet),
+			Description: "Generate a calendar feed, in the iCalendar (ICS) format, with the milestones and the due " +
+				"dates of the open tasks of a project in Teamwork.com, so they can be imported in calendar apps. " +
+				"The feed is also available, always up to date, in the resource " + calendarURIPrefix +
+				"{project_id}" + calendarURISuffix + ".",
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "Get Project Calendar",
+				ReadOnlyHint: true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"project_id": {
+						Type:        "integer",
+						Description: "The ID of the project.",
+					},
+				},
+				Required: []string{"project_id"},
+			},
+		},
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var projectID int64
+
+			var arguments map[string]any
+			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("failed to decode request: %s", err.Error())), nil
+			}
+			err := helpers.ParamGroup(arguments,
+				helpers.RequiredNumericParam(&projectID, "project_id"),
+			)
+			if err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
+			}
+
+			events, err := projectCalendarEvents(ctx, engine, projectID)
+			if err != nil {
+				return helpers.HandleAPIError(err, "failed to generate project calendar")
+			}
+			uri := calendarURIPrefix + strconv.FormatInt(projectID, 10) + calendarURISuffix
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					&mcp.TextContent{
+						Text: fmt.Sprintf("Calendar feed with %d events. It's always up to date in the resource %s.",
+							len(events), uri),
+					},
+					&mcp.EmbeddedResource{
+						Resource: &mcp.ResourceContents{
+							URI:      uri,
+							MIMEType: "text/calendar",
+							Text:     encodeCalendar(projectID, events),
+						},
+					},
+				},
+			}, nil
+		},
+	}
+}
+
+// ProjectCalendarResourceTemplate returns the resource template serving the
+// calendar feeds of the projects, generated when read. The feeds of the
+// projects outside the sandbox are reported as not found.
+func ProjectCalendarResourceTemplate(engine *twapi.Engine, sandbox *projectSandbox) toolsets.ServerResourceTemplate {
+	return toolsets.NewServerResourceTemplate(&mcp.ResourceTemplate{
+		Name:        "project_calendar",
+		Title:       "Project Calendar",
+		Description: "Calendar feed, in the iCalendar (ICS) format, with the milestones and task due dates of a project.",
+		URITemplate: calendarURIPrefix + "{project_id}" + calendarURISuffix,
+		MIMEType:    "text/calendar",
+	}, func(ctx context.Context, request *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		uri := request.Params.URI
+		id, ok := strings.CutPrefix(uri, calendarURIPrefix)
+		if ok {
+			id, ok = strings.CutSuffix(id, calendarURISuffix)
+		}
+		projectID, err := strconv.ParseInt(id, 10, 64)
+		if !ok || err != nil {
+			return nil, mcp.ResourceNotFoundError(uri)
+		}
+		if err := sandbox.checkResource(ctx, uri, sandboxReference{entity: "project", id: projectID}); err != nil {
+			return nil, err
+		}
+
+		events, err := projectCalendarEvents(ctx, engine, projectID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate project calendar: %w", err)
+		}
+		return &mcp.ReadResourceResult{
+			Contents: []*mcp.ResourceContents{{
+				URI:      uri,
+				MIMEType: "text/calendar",
+				Text:     encodeCalendar(projectID, events),
+			}},
+		}, nil
+	})
+}
+
+// projectCalendarEvents loads the milestones and the open tasks with due dates
+// of the project as calendar events.
+func projectCalendarEvents(ctx context.Context, engine *twapi.Engine, projectID int64) ([]calendarEvent, error) {
+	milestones, _, err := helpers.FetchAllPages(ctx, helpers.AutoPagination{PageSize: 100},
+		func(ctx context.Context, page, pageSize int64) ([]projects.Milestone, bool, error) {
+			milestoneListRequest := projects.NewMilestoneListRequest()
+			milestoneListRequest.Path.ProjectID = projectID
+			milestoneListRequest.Filters.Page = page
+			milestoneListRequest.Filters.PageSize = pageSize
+
+			response, err := projects.MilestoneList(ctx, engine, milestoneListRequest)
+			if err != nil {
+				return nil, false, err
+			}
+			return response.Milestones, response.Meta.Page.HasMore, nil
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, _, err := helpers.FetchAllPages(ctx, helpers.AutoPagination{PageSize: 100, MaxItems: calendarMaxTasks},
+		func(ctx context.Context, page, pageSize int64) ([]projects.Task, bool, error) {
+			taskListRequest := projects.NewTaskListRequest()
+			taskListRequest.Path.ProjectID = projectID
+			taskListRequest.Filters.Page = page
+			taskListRequest.Filters.PageSize = pageSize
+
+			response, err := executeWithQuery[*projects.TaskListResponse](ctx, engine, taskListRequest, url.Values{
+				"hasDueDate": []string{"true"},
+			})
+			if err != nil {
+				return nil, false, err
+			}
+			return response.Tasks, response.Meta.Page.HasMore, nil
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]calendarEvent, 0, len(milestones)+len(tasks))
+	for _, milestone := range milestones {
+		events = append(events, calendarEvent{
+			uid:         fmt.Sprintf("milestone-%d", milestone.ID),
+			summary:     "Milestone: " + milestone.Name,
+			description: milestone.Description,
+			date:        milestone.DueAt,
+		})
+	}
+	for _, task := range tasks {
+		if task.DueAt == nil {
+			continue
+		}
+		events = append(events, calendarEvent{
+			uid:     fmt.Sprintf("task-%d", task.ID),
+			summary: task.Name,
+			date:    *task.DueAt,
+		})
+	}
+	return events, nil
+}
+
+// encodeCalendar encodes the events as an iCalendar document. The events are
+// all-day events, as the due dates in Teamwork.com don't have a time.
+func encodeCalendar(projectID int64, events []calendarEvent) string {
+	now := time.Now().UTC()
+	var calendar bytes.Buffer
+	writeLine := func(name, value string) {
+		calendarFold(&calendar, name+":"+value)
+	}
+
+	writeLine("BEGIN", "VCALENDAR")
+	writeLine("VERSION", "2.0")
+	writeLine("PRODID", "-//Teamwork.com//MCP//EN")
+	writeLine("CALSCALE", "GREGORIAN")
+	writeLine("X-WR-CALNAME", fmt.Sprintf("Teamwork.com project %d", projectID))
+	for _, event := range events {
+		date := event.date.UTC()
+		writeLine("BEGIN", "VEVENT")
+		writeLine("UID", fmt.Sprintf("%s-project-%d@teamwork.com", event.uid, projectID))
+		writeLine("DTSTAMP", now.Format("20060102T150405Z"))
+		writeLine("DTSTART;VALUE=DATE", date.Format("20060102"))
+		writeLine("DTEND;VALUE=DATE", date.AddDate(0, 0, 1).Format("20060102"))
+		writeLine("SUMMARY", calendarEscape(event.summary))
+		if event.description != "" {
+			writeLine("DESCRIPTION", calendarEscape(event.description))
+		}
+		writeLine("TRANSP", "TRANSPARENT")
+		writeLine("END", "VEVENT")
+	}
+	writeLine("END", "VCALENDAR")
+	return calendar.String()
+}
+
+// calendarEscape escapes the special characters of the iCalendar text values.
+func calendarEscape(value string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(value)
+}
+
+// calendarFold writes a content line, folded in lines of 75 octets at most as
+// required by the iCalendar format, without splitting UTF-8 characters.
+func calendarFold(calendar *bytes.Buffer, line string) {
+	const maxOctets = 75
+	octets := 0
+	for _, r := range line {
+		size := len(string(r))
+		if octets+size > maxOctets {
+			calendar.WriteString("\r\n ")
+			octets = 1
+		}
+		calendar.WriteRune(r)
+		octets += size
+	}
+	calendar.WriteString("\r\n")
+}
@@ -0,0 +1,154 @@
+package twprojects_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/testutil"
+	"github.com/teamwork/mcp/internal/twprojects"
+)
+
+func TestProjectCalendarGet(t *testing.T) {
+	mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(r *http.Request) (int, []byte) {
+		switch r.URL.Path {
+		case "/projects/api/v3/projects/123/milestones.json":
+			return http.StatusOK, []byte(`{"milestones":[{"id":1,"name":"Launch, phase 1",` +
+				`"deadline":"2025-03-01T00:00:00Z"}],"meta":{"page":{"hasMore":false}}}`)
+		case "/projects/api/v3/projects/123/tasks.json":
+			return http.StatusOK, []byte(`{"tasks":[{"id":2,"name":"Review copy","dueDate":"2025-02-20T00:00:00Z"},` +
+				`{"id":3,"name":"No due date"}],"meta":{"page":{"hasMore":false}}}`)
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			return http.StatusNotFound, nil
+		}
+	})
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodProjectCalendarGet.String(), map[string]any{
+		"project_id": float64(123),
+	}, testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+		toolResult, ok := result.(*mcp.CallToolResult)
+		if !ok {
+			t.Fatalf("unexpected result type: %T", result)
+		}
+		if toolResult.IsError || len(toolResult.Content) != 2 {
+			t.Fatalf("unexpected result: %v", toolResult.Content)
+		}
+		resource, ok := toolResult.Content[1].(*mcp.EmbeddedResource)
+		if !ok {
+			t.Fatalf("unexpected content type %T", toolResult.Content[1])
+		}
+		if resource.Resource.URI != "twprojects://projects/123/calendar.ics" {
+			t.Errorf("unexpected resource URI %s", resource.Resource.URI)
+		}
+		calendar := resource.Resource.Text
+		for _, expected := range []string{
+			"BEGIN:VCALENDAR\r\n",
+			"UID:milestone-1-project-123@teamwork.com\r\nDTSTAMP:",
+			"DTSTART;VALUE=DATE:20250301\r\nDTEND;VALUE=DATE:20250302\r\nSUMMARY:Milestone: Launch\\, phase 1\r\n",
+			"DTSTART;VALUE=DATE:20250220\r\nDTEND;VALUE=DATE:20250221\r\nSUMMARY:Review copy\r\n",
+		} {
+			if !strings.Contains(calendar, expected) {
+				t.Errorf("expected %q in %q", expected, calendar)
+			}
+		}
+		if strings.Count(calendar, "BEGIN:VEVENT") != 2 {
+			t.Errorf("expected 2 events in %q", calendar)
+		}
+	}))
+}
+
+func TestProjectCalendarResourceSandbox(t *testing.T) {
+	tests := []struct {
+		name     string
+		uri      string
+		notFound bool
+	}{{
+		name: "allowed project",
+		uri:  "twprojects://projects/123/calendar.ics",
+	}, {
+		name:     "project outside the sandbox",
+		uri:      "twprojects://projects/456/calendar.ics",
+		notFound: true,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(r *http.Request) (int, []byte) {
+				switch r.URL.Path {
+				case "/projects/api/v3/projects/123/milestones.json":
+					return http.StatusOK, []byte(`{"milestones":[],"meta":{"page":{"hasMore":false}}}`)
+				case "/projects/api/v3/projects/123/tasks.json":
+					return http.StatusOK, []byte(`{"tasks":[],"meta":{"page":{"hasMore":false}}}`)
+				}
+				t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				return http.StatusNotFound, []byte(`{}`)
+			}, twprojects.WithAllowedProjects([]int64{123}))
+
+			readCalendarResource(t, mcpServer, tt.uri, tt.notFound)
+		})
+	}
+}
+
+func TestProjectCalendarResourceCompany(t *testing.T) {
+	tests := []struct {
+		name     string
+		uri      string
+		notFound bool
+	}{{
+		name: "company project",
+		uri:  "twprojects://projects/123/calendar.ics",
+	}, {
+		name:     "other company project",
+		uri:      "twprojects://projects/456/calendar.ics",
+		notFound: true,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(r *http.Request) (int, []byte) {
+				switch {
+				case r.URL.Path == "/projects/api/v3/projects.json" && r.URL.Query().Get("projectCompanyIds") == "7":
+					return http.StatusOK, []byte(`{"projects":[{"id":123}],"meta":{"page":{"hasMore":false}}}`)
+				case r.URL.Path == "/projects/api/v3/projects/123/milestones.json":
+					return http.StatusOK, []byte(`{"milestones":[],"meta":{"page":{"hasMore":false}}}`)
+				case r.URL.Path == "/projects/api/v3/projects/123/tasks.json":
+					return http.StatusOK, []byte(`{"tasks":[],"meta":{"page":{"hasMore":false}}}`)
+				}
+				t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				return http.StatusNotFound, []byte(`{}`)
+			}, twprojects.WithCompany(7))
+
+			readCalendarResource(t, mcpServer, tt.uri, tt.notFound)
+		})
+	}
+}
+
+func readCalendarResource(t *testing.T, mcpServer *mcp.Server, uri string, notFound bool) {
+	t.Helper()
+
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	if _, err := mcpServer.Connect(t.Context(), serverTransport, nil); err != nil {
+		t.Fatalf("failed to connect to server: %v", err)
+	}
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	clientSession, err := client.Connect(t.Context(), clientTransport, nil)
+	if err != nil {
+		t.Fatalf("failed to connect to client: %v", err)
+	}
+	defer clientSession.Close() //nolint:errcheck
+
+	resource, err := clientSession.ReadResource(t.Context(), &mcp.ReadResourceParams{URI: uri})
+	if notFound {
+		if err == nil {
+			t.Fatalf("expected the calendar not to be found, got %v", resource.Contents[0].Text)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("failed to read calendar: %v", err)
+	}
+	if text := resource.Contents[0].Text; !strings.Contains(text, "BEGIN:VCALENDAR") {
+		t.Errorf("unexpected calendar %s", text)
+	}
+}
@@ -0,0 +1,441 @@
+package twprojects
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/helpers"
+	"github.com/teamwork/mcp/internal/toolsets"
+	twapi "github.com/teamwork/twapi-go-sdk"
+	"github.com/teamwork/twapi-go-sdk/projects"
+)
+
+// List of methods available in the Teamwork.com MCP service.
+//
+// The naming convention for methods follows a pattern described here:
+// https://github.com/github/github-mcp-server/issues/333
+const (
+	MethodClientInvite           toolsets.Method = "twprojects-invite_client"
+	MethodClientInvitationResend toolsets.Method = "twprojects-resend_client_invitation"
+	MethodClientInvitationCancel toolsets.Method = "twprojects-cancel_client_invitation"
+)
+
+const clientInvitationDescription = "Client contacts are invited to a project as collaborators, users of the client " +
+	"company with restricted permissions, and receive an email to set up their account and join the project."
+
+// clientPermissions are the permissions of the invited clients in the project,
+// from only viewing the work to contributing tasks, messages and files.
+var clientPermissions = map[string]map[string]bool{
+	"view": {
+		"view-messages-and-files":   true,
+		"view-tasks-and-milestones": true,
+		"view-notebooks":            true,
+	},
+	"comment": {
+		"view-messages-and-files":   true,
+		"view-tasks-and-milestones": true,
+		"view-notebooks":            true,
+		"add-comments":              true,
+	},
+	"contribute": {
+		"view-messages-and-files":   true,
+		"view-tasks-and-milestones": true,
+		"view-notebooks":            true,
+		"add-comments":              true,
+		"add-tasks":                 true,
+		"add-messages":              true,
+		"add-files":                 true,
+	},
+}
+
+// clientPermissionLevels are the names of the client permissions.
+var clientPermissionLevels = []string{"view", "comment", "contribute"}
+
+func init() {
+	// register the toolset methods
+	toolsets.RegisterMethod(MethodClientInvite)
+	toolsets.RegisterMethod(MethodClientInvitationResend)
+	toolsets.RegisterMethod(MethodClientInvitationCancel)
+}
+
+// clientPermissionsRequest represents the request to set the permissions of a
+// user in a project.
+type clientPermissionsRequest struct {
+	ProjectID   int64
+	UserID      int64
+	Permissions map[string]bool
+}
+
+// HTTPRequest creates an HTTP request for the clientPermissionsRequest.
+func (c clientPermissionsRequest) HTTPRequest(ctx context.Context, server string) (*http.Request, error) {
+	uri := fmt.Sprintf("%s/projects/%d/people/%d.json", server, c.ProjectID, c.UserID)
+
+	permissions := make(map[string]int, len(clientPermissions["contribute"]))
+	for name := range clientPermissions["contribute"] {
+		if c.Permissions[name] {
+			permissions[name] = 1
+		} else {
+			permissions[name] = 0
+		}
+	}
+	payload := struct {
+		Permissions map[string]int `json:"permissions"`
+	}{Permissions: permissions}
+
+	var body bytes.Buffer
+	if err := json.NewEncoder(&body).Encode(payload); err != nil {
+		return nil, fmt.Errorf("failed to encode project permissions request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, uri, &body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+// clientInvitationResendRequest represents the request to send again the
+// invitation email of a user.
+type clientInvitationResendRequest struct {
+	UserID int64
+}
+
+// HTTPRequest creates an HTTP request for the clientInvitationResendRequest.
+func (c clientInvitationResendRequest) HTTPRequest(ctx context.Context, server string) (*http.Request, error) {
+	uri := fmt.Sprintf("%s/people/%d/resendinvite.json", server, c.UserID)
+	return http.NewRequestWithContext(ctx, http.MethodPost, uri, nil)
+}
+
+// projectMemberRemoveRequest represents the request to remove users from a
+// project.
+type projectMemberRemoveRequest struct {
+	ProjectID int64
+	UserIDs   []int64
+}
+
+// HTTPRequest creates an HTTP request for the projectMemberRemoveRequest.
+func (p projectMemberRemoveRequest) HTTPRequest(ctx context.Context, server string) (*http.Request, error) {
+	uri := fmt.Sprintf("%s/projects/%d/people.json", server, p.ProjectID)
+
+	userIDs := make([]string, len(p.UserIDs))
+	for i, id := range p.UserIDs {
+		userIDs[i] = strconv.FormatInt(id, 10)
+	}
+	payload := struct {
+		Remove struct {
+			UserIDList string `json:"userIdList"`
+		} `json:"remove"`
+	}{}
+	payload.Remove.UserIDList = strings.Join(userIDs, ",")
+
+	var body bytes.Buffer
+	if err := json.NewEncoder(&body).Encode(payload); err != nil {
+		return nil, fmt.Errorf("failed to encode remove project members request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, uri, &body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+// clientInvitationResponse handles the response of the client invitation
+// requests.
+type clientInvitationResponse struct{}
+
+// HandleHTTPResponse handles the HTTP response for the
+// clientInvitationResponse.
+func (c *clientInvitationResponse) HandleHTTPResponse(resp *http.Response) error {
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusNoContent:
+		return nil
+	}
+	return twapi.NewHTTPError(resp, "failed to update client invitation")
+}
+
+// findUserByEmail returns the user with the email, ignoring the case, or nil
+// when there is none.
+func findUserByEmail(ctx context.Context, engine *twapi.Engine, email string) (*projects.User, error) {
+	userListRequest := projects.NewUserListRequest()
+	userListRequest.Filters.SearchTerm = email
+	response, err := projects.UserList(ctx, engine, userListRequest)
+	if err != nil {
+		return nil, err
+	}
+	index := slices.IndexFunc(response.Users, func(user projects.User) bool {
+		return strings.EqualFold(user.Email, email)
+	})
+	if index == -1 {
+		return nil, nil
+	}
+	return &response.Users[index], nil
+}
+
+// ClientInvite invites a client contact to a project in Teamwork.com.
+func ClientInvite(engine *twapi.Engine) toolsets.ToolWrapper {
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name: string(MethodClientInvite),
+			Description: "Invite a client contact to a project in Teamwork.com in a single call: the collaborator user " +
+				"is created when the email isn't known, added to the project with the chosen permissions, and sent the " +
+				"invitation email. Existing users are added to the project without creating a new account. When a " +
+				"step fails, the changes made before the failure are reverted. " + clientInvitationDescription,
+			Annotations: &mcp.ToolAnnotations{
+				Title: "Invite Client",
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"project_id": {
+						Type:        "integer",
+						Description: "The ID of the project the client is invited to.",
+					},
+					"email": {
+						Type:        "string",
+						Description: "The email address of the client contact.",
+					},
+					"first_name": {
+						Type:        "string",
+						Description: "The first name of the client contact. Required when the email isn't known.",
+					},
+					"last_name": {
+						Type:        "string",
+						Description: "The last name of the client contact. Required when the email isn't known.",
+					},
+					"title": {
+						Type:        "string",
+						Description: "The job title of the client contact.",
+					},
+					"company_id": {
+						Type:        "integer",
+						Description: "The ID of the client/company of the contact, used when the user is created.",
+					},
+					"permissions": {
+						Type: "string",
+						Description: "The permissions of the client in the project: only viewing the work (view), also " +
+							"commenting (comment), or also adding tasks, messages and files (contribute). Defaults to " +
+							"comment.",
+						Enum: enumSchemaValues(clientPermissionLevels),
+					},
+				},
+				Required: []string{"project_id", "email"},
+			},
+		},
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var projectID int64
+			var email, firstName, lastName string
+			var title *string
+			var companyID *int64
+			permissions := "comment"
+
+			var arguments map[string]any
+			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("failed to decode request: %s", err.Error())), nil
+			}
+			err := helpers.ParamGroup(arguments,
+				helpers.RequiredNumericParam(&projectID, "project_id"),
+				helpers.RequiredParam(&email, "email"),
+				helpers.OptionalParam(&firstName, "first_name"),
+				helpers.OptionalParam(&lastName, "last_name"),
+				helpers.OptionalPointerParam(&title, "title"),
+				helpers.OptionalNumericPointerParam(&companyID, "company_id"),
+				helpers.OptionalParam(&permissions, "permissions",
+					helpers.RestrictValues(clientPermissionLevels...),
+				),
+			)
+			if err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
+			}
+
+			user, err := findUserByEmail(ctx, engine, email)
+			if err != nil {
+				return helpers.HandleAPIError(err, "failed to search the client user")
+			}
+
+			compensations := newCompensationLog(engine)
+			var userID int64
+			if user != nil {
+				userID = user.ID
+			} else {
+				if firstName == "" || lastName == "" {
+					return helpers.NewToolResultTextError("invalid parameters: first_name and last_name are required, " +
+						"as no user has the email"), nil
+				}
+				userCreateRequest := projects.NewUserCreateRequest(firstName, lastName, email)
+				userCreateRequest.Title = title
+				userCreateRequest.Type = twapi.Ptr("collaborator")
+				userCreateRequest.CompanyID = companyID
+				response, err := twapi.Execute[userImportRequest, *projects.UserCreateResponse](ctx, engine,
+					userImportRequest{UserCreateRequest: userCreateRequest, SendInvite: true},
+				)
+				if err != nil {
+					return helpers.HandleAPIError(err, "failed to create the client user")
+				}
+				userID = int64(response.ID)
+				compensations.record(fmt.Sprintf("delete user %d", userID), func(ctx context.Context) error {
+					_, err := projects.UserDelete(ctx, engine, projects.NewUserDeleteRequest(userID))
+					return err
+				})
+			}
+
+			_, err = projects.ProjectMemberAdd(ctx, engine, projects.NewProjectMemberAddRequest(projectID, userID))
+			if err != nil {
+				return compensations.fail(ctx, err, "failed to add the client to the project")
+			}
+			compensations.record(fmt.Sprintf("remove user %d from project %d", userID, projectID),
+				func(ctx context.Context) error {
+					_, err := twapi.Execute[projectMemberRemoveRequest, *clientInvitationResponse](ctx, engine,
+						projectMemberRemoveRequest{ProjectID: projectID, UserIDs: []int64{userID}},
+					)
+					return err
+				},
+			)
+
+			_, err = twapi.Execute[clientPermissionsRequest, *clientInvitationResponse](ctx, engine,
+				clientPermissionsRequest{ProjectID: projectID, UserID: userID, Permissions: clientPermissions[permissions]},
+			)
+			if err != nil {
+				return compensations.fail(ctx, err, "failed to set the client permissions")
+			}
+
+			if user != nil {
+				return helpers.NewToolResultText("Existing user %d added to project %d with %s permissions, no "+
+					"invitation email was needed", userID, projectID, permissions), nil
+			}
+			return helpers.NewToolResultText("Client user %d invited to project %d with %s permissions, the invitation "+
+				"email was sent to %s", userID, projectID, permissions, email), nil
+		},
+	}
+}
+
+// ClientInvitationResend sends again the invitation email of a client in
+// Teamwork.com.
+func ClientInvitationResend(engine *twapi.Engine) toolsets.ToolWrapper {
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name: string(MethodClientInvitationResend),
+			Description: "Send again the invitation email of a client contact in Teamwork.com, e.g. when the first " +
+				"email expired or was lost. " + clientInvitationDescription,
+			Annotations: &mcp.ToolAnnotations{
+				Title: "Resend Client Invitation",
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"user_id": {
+						Type:        "integer",
+						Description: "The ID of the invited user.",
+					},
+				},
+				Required: []string{"user_id"},
+			},
+		},
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var resendRequest clientInvitationResendRequest
+
+			var arguments map[string]any
+			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("failed to decode request: %s", err.Error())), nil
+			}
+			err := helpers.ParamGroup(arguments,
+				helpers.RequiredNumericParam(&resendRequest.UserID, "user_id"),
+			)
+			if err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
+			}
+
+			_, err = twapi.Execute[clientInvitationResendRequest, *clientInvitationResponse](ctx, engine, resendRequest)
+			if err != nil {
+				return helpers.HandleAPIError(err, "failed to resend the invitation")
+			}
+			return helpers.NewToolResultText("Invitation of user %d sent again successfully", resendRequest.UserID), nil
+		},
+	}
+}
+
+// ClientInvitationCancel cancels the invitation of a client to a project in
+// Teamwork.com. The invited user can only be deleted when allowDelete is true.
+func ClientInvitationCancel(engine *twapi.Engine, allowDelete bool) toolsets.ToolWrapper {
+	properties := map[string]*jsonschema.Schema{
+		"project_id": {
+			Type:        "integer",
+			Description: "The ID of the project the client was invited to.",
+		},
+		"user_id": {
+			Type:        "integer",
+			Description: "The ID of the invited user.",
+		},
+	}
+	if allowDelete {
+		properties["delete_user"] = &jsonschema.Schema{
+			Type:        "boolean",
+			Description: "If true, the user is also deleted. Defaults to false.",
+		}
+	}
+
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name: string(MethodClientInvitationCancel),
+			Description: "Cancel the invitation of a client contact to a project in Teamwork.com, removing the user " +
+				"from the project. " + clientInvitationDescription,
+			Annotations: &mcp.ToolAnnotations{
+				Title: "Cancel Client Invitation",
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:       "object",
+				Properties: properties,
+				Required:   []string{"project_id", "user_id"},
+			},
+		},
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var projectID, userID int64
+			var deleteUser bool
+
+			var arguments map[string]any
+			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("failed to decode request: %s", err.Error())), nil
+			}
+			err := helpers.ParamGroup(arguments,
+				helpers.RequiredNumericParam(&projectID, "project_id"),
+				helpers.RequiredNumericParam(&userID, "user_id"),
+				helpers.OptionalParam(&deleteUser, "delete_user"),
+			)
+			if err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
+			}
+			if deleteUser && !allowDelete {
+				return helpers.NewToolResultTextError("invalid parameters: delete_user isn't available, as deleting " +
+					"is disabled"), nil
+			}
+
+			_, err = twapi.Execute[projectMemberRemoveRequest, *clientInvitationResponse](ctx, engine,
+				projectMemberRemoveRequest{ProjectID: projectID, UserIDs: []int64{userID}},
+			)
+			if err != nil {
+				return helpers.HandleAPIError(err, "failed to remove the client from the project")
+			}
+			if !deleteUser {
+				return helpers.NewToolResultText("Invitation of user %d to project %d cancelled successfully",
+					userID, projectID), nil
+			}
+
+			if _, err := projects.UserDelete(ctx, engine, projects.NewUserDeleteRequest(userID)); err != nil {
+				return helpers.HandleAPIError(err, fmt.Sprintf("user %d was removed from project %d, but failed to "+
+					"delete the user", userID, projectID))
+			}
+			return helpers.NewToolResultText("Invitation of user %d to project %d cancelled successfully and the user "+
+				"deleted", userID, projectID), nil
+		},
+	}
+}
@@ -0,0 +1,106 @@
+package twprojects_test
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/testutil"
+	"github.com/teamwork/mcp/internal/twprojects"
+)
+
+func TestClientInvite(t *testing.T) {
+	tests := []struct {
+		name              string
+		users             string
+		permissionsStatus int
+		requests          []string
+		isError           bool
+	}{{
+		name:              "new client",
+		users:             `[]`,
+		permissionsStatus: http.StatusOK,
+		requests: []string{
+			"POST /people.json",
+			"PUT /projects/api/v3/projects/5/people.json",
+			"PUT /projects/5/people/42.json",
+		},
+	}, {
+		name:              "existing user",
+		users:             `[{"id":42,"email":"JANE@example.com"}]`,
+		permissionsStatus: http.StatusOK,
+		requests: []string{
+			"PUT /projects/api/v3/projects/5/people.json",
+			"PUT /projects/5/people/42.json",
+		},
+	}, {
+		name:              "failure reverts the invitation",
+		users:             `[]`,
+		permissionsStatus: http.StatusBadRequest,
+		requests: []string{
+			"POST /people.json",
+			"PUT /projects/api/v3/projects/5/people.json",
+			"PUT /projects/5/people/42.json",
+			"PUT /projects/5/people.json",
+			"DELETE /people/42.json",
+		},
+		isError: true,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []string
+			mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(r *http.Request) (int, []byte) {
+				if r.Method == http.MethodGet {
+					if r.URL.Path == "/projects/api/v3/people.json" {
+						return http.StatusOK, []byte(`{"people":` + tt.users + `}`)
+					}
+					return http.StatusOK, []byte(`{}`)
+				}
+				requests = append(requests, r.Method+" "+r.URL.Path)
+				switch r.URL.Path {
+				case "/people.json":
+					return http.StatusCreated, []byte(`{"id":"42"}`)
+				case "/projects/5/people/42.json":
+					return tt.permissionsStatus, []byte(`{}`)
+				}
+				return http.StatusOK, []byte(`{}`)
+			})
+
+			testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodClientInvite.String(), map[string]any{
+				"project_id": float64(5),
+				"email":      "jane@example.com",
+				"first_name": "Jane",
+				"last_name":  "Doe",
+			}, testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+				toolResult, ok := result.(*mcp.CallToolResult)
+				if !ok {
+					t.Fatalf("unexpected result type: %T", result)
+				}
+				if toolResult.IsError != tt.isError {
+					t.Errorf("unexpected error state %v: %v", toolResult.IsError, toolResult.Content)
+				}
+			}))
+			if !slices.Equal(requests, tt.requests) {
+				t.Errorf("expected requests %v, got %v", tt.requests, requests)
+			}
+		})
+	}
+}
+
+func TestClientInvitationResend(t *testing.T) {
+	mcpServer := mcpServerMock(t, http.StatusOK, []byte(`{}`))
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodClientInvitationResend.String(), map[string]any{
+		"user_id": float64(42),
+	})
+}
+
+func TestClientInvitationCancel(t *testing.T) {
+	mcpServer := mcpServerMock(t, http.StatusOK, []byte(`{}`))
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodClientInvitationCancel.String(), map[string]any{
+		"project_id":  float64(5),
+		"user_id":     float64(42),
+		"delete_user": true,
+	})
+}
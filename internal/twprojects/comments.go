@@ -1,12 +1,17 @@
 package twprojects
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"math"
+	"net/http"
+	"net/url"
 	"reflect"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/google/jsonschema-go/jsonschema"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -87,12 +92,7 @@ func CommentCreate(engine *twapi.Engine) toolsets.ToolWrapper {
 							"type": {
 								Type:        "string",
 								Description: "The type of object to create the comment for.",
-								Enum: []any{
-									"tasks",
-									"milestones",
-									"files",
-									"notebooks",
-								},
+								Enum:        enumSchemaValues(commentObjectTypes),
 							},
 							"id": {
 								Type:        "integer",
@@ -109,9 +109,23 @@ func CommentCreate(engine *twapi.Engine) toolsets.ToolWrapper {
 					"content_type": {
 						Type:        "string",
 						Description: "The content type of the comment. It can be either 'TEXT' or 'HTML'.",
-						Enum: []any{
-							"TEXT",
-							"HTML",
+						Enum:        enumSchemaValues(commentContentTypes),
+					},
+					"attachments": {
+						Type:        "object",
+						Description: "The files attached to the comment, like the attachments added in the web UI.",
+						Properties: map[string]*jsonschema.Schema{
+							"file_ids": {
+								Type:        "array",
+								Description: "The IDs of existing files of the project to attach.",
+								Items:       &jsonschema.Schema{Type: "integer"},
+							},
+							"pending_file_refs": {
+								Type: "array",
+								Description: "The references of files uploaded to Teamwork.com but not attached to any " +
+									"entity yet (pending file attachments).",
+								Items: &jsonschema.Schema{Type: "string"},
+							},
 						},
 					},
 				},
@@ -119,7 +133,7 @@ func CommentCreate(engine *twapi.Engine) toolsets.ToolWrapper {
 			},
 		},
 		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			var commentCreateRequest projects.CommentCreateRequest
+			var commentCreateRequest commentAttachmentsCreateRequest
 
 			var arguments map[string]any
 			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
@@ -127,7 +141,9 @@ func CommentCreate(engine *twapi.Engine) toolsets.ToolWrapper {
 			}
 			err := helpers.ParamGroup(arguments,
 				helpers.RequiredParam(&commentCreateRequest.Body, "body"),
-				helpers.OptionalPointerParam(&commentCreateRequest.ContentType, "content_type"),
+				helpers.OptionalPointerParam(&commentCreateRequest.ContentType, "content_type",
+					helpers.RestrictValues(commentContentTypes...),
+				),
 			)
 			if err != nil {
 				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
@@ -166,7 +182,31 @@ func CommentCreate(engine *twapi.Engine) toolsets.ToolWrapper {
 				return helpers.NewToolResultTextError(fmt.Sprintf("invalid object type: %s", objectType)), nil
 			}
 
-			comment, err := projects.CommentCreate(ctx, engine, commentCreateRequest)
+			if attachments, ok := arguments["attachments"]; ok && attachments != nil {
+				attachmentsMap, ok := attachments.(map[string]any)
+				if !ok {
+					return helpers.NewToolResultTextError(fmt.Sprintf("invalid attachments: expected an object, got %T",
+						attachments)), nil
+				}
+				var fileIDs []int64
+				var pendingFileRefs []string
+				err = helpers.ParamGroup(attachmentsMap,
+					helpers.OptionalNumericListParam(&fileIDs, "file_ids"),
+					helpers.OptionalListParam(&pendingFileRefs, "pending_file_refs"),
+				)
+				if err != nil {
+					return helpers.NewToolResultTextError(fmt.Sprintf("invalid attachments: %s", err.Error())), nil
+				}
+				ids := make([]string, len(fileIDs))
+				for i, id := range fileIDs {
+					ids[i] = strconv.FormatInt(id, 10)
+				}
+				commentCreateRequest.Attachments = strings.Join(ids, ",")
+				commentCreateRequest.PendingFileAttachments = strings.Join(pendingFileRefs, ",")
+			}
+
+			comment, err := twapi.Execute[commentAttachmentsCreateRequest, *projects.CommentCreateResponse](
+				ctx, engine, commentCreateRequest)
 			if err != nil {
 				return helpers.HandleAPIError(err, "failed to create comment")
 			}
@@ -198,10 +238,7 @@ func CommentUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 					"content_type": {
 						Type:        "string",
 						Description: "The content type of the comment. It can be either 'TEXT' or 'HTML'.",
-						Enum: []any{
-							"TEXT",
-							"HTML",
-						},
+						Enum:        enumSchemaValues(commentContentTypes),
 					},
 				},
 				Required: []string{"id", "body"},
@@ -217,7 +254,9 @@ func CommentUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 			err := helpers.ParamGroup(arguments,
 				helpers.RequiredNumericParam(&commentUpdateRequest.Path.ID, "id"),
 				helpers.RequiredParam(&commentUpdateRequest.Body, "body"),
-				helpers.OptionalPointerParam(&commentUpdateRequest.ContentType, "content_type"),
+				helpers.OptionalPointerParam(&commentUpdateRequest.ContentType, "content_type",
+					helpers.RestrictValues(commentContentTypes...),
+				),
 			)
 			if err != nil {
 				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
@@ -311,6 +350,10 @@ func CommentGet(engine *twapi.Engine) toolsets.ToolWrapper {
 				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
 			}
 
+			if passthroughEnabled(ctx) {
+				return passthrough(ctx, engine, commentGetRequest, nil, "failed to get comment")
+			}
+
 			comment, err := projects.CommentGet(ctx, engine, commentGetRequest)
 			if err != nil {
 				return helpers.HandleAPIError(err, "failed to get comment")
@@ -320,10 +363,12 @@ func CommentGet(engine *twapi.Engine) toolsets.ToolWrapper {
 			if err != nil {
 				return nil, err
 			}
+			linked := helpers.WebLinker(ctx, encoded, commentPathBuilder)
 			return &mcp.CallToolResult{
+				Meta: helpers.WebLinkedEntitiesMeta(linked),
 				Content: []mcp.Content{
 					&mcp.TextContent{
-						Text: string(helpers.WebLinker(ctx, encoded, commentPathBuilder)),
+						Text: string(linked),
 					},
 				},
 				StructuredContent: comment,
@@ -357,12 +402,18 @@ func CommentList(engine *twapi.Engine) toolsets.ToolWrapper {
 						Type:        "integer",
 						Description: "Number of results per page for pagination.",
 					},
+					"since":      commentSinceSchema(),
+					"order_mode": commentOrderModeSchema(),
+					"count_only": countOnlySchema(),
 				},
 			},
 			OutputSchema: commentListOutputSchema,
 		},
 		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			var commentListRequest projects.CommentListRequest
+			var since *time.Time
+			var orderMode string
+			var countOnly bool
 
 			var arguments map[string]any
 			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
@@ -372,12 +423,29 @@ func CommentList(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalParam(&commentListRequest.Filters.SearchTerm, "search_term"),
 				helpers.OptionalNumericParam(&commentListRequest.Filters.Page, "page"),
 				helpers.OptionalNumericParam(&commentListRequest.Filters.PageSize, "page_size"),
+				helpers.OptionalTimePointerParam(&since, "since"),
+				helpers.OptionalParam(&orderMode, "order_mode", helpers.RestrictValues(orderModes...)),
+				helpers.OptionalParam(&countOnly, "count_only"),
 			)
 			if err != nil {
 				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
 			}
 
-			commentList, err := projects.CommentList(ctx, engine, commentListRequest)
+			if countOnly {
+				return listCount(ctx, engine, queryRequest[projects.CommentListRequest]{
+					request: commentListRequest,
+					query:   commentListQuery(since, ""),
+				})
+			}
+
+			if passthroughEnabled(ctx) {
+				return passthrough(ctx, engine, commentListRequest, commentListQuery(since, orderMode),
+					"failed to list comments")
+			}
+
+			commentList, err := executeWithQuery[*projects.CommentListResponse](ctx, engine, commentListRequest,
+				commentListQuery(since, orderMode),
+			)
 			if err != nil {
 				return helpers.HandleAPIError(err, "failed to list comments")
 			}
@@ -386,10 +454,12 @@ func CommentList(engine *twapi.Engine) toolsets.ToolWrapper {
 			if err != nil {
 				return nil, err
 			}
+			linked := helpers.WebLinker(ctx, encoded, commentPathBuilder)
 			return &mcp.CallToolResult{
+				Meta: helpers.WebLinkedEntitiesMeta(linked),
 				Content: []mcp.Content{
 					&mcp.TextContent{
-						Text: string(helpers.WebLinker(ctx, encoded, commentPathBuilder)),
+						Text: string(linked),
 					},
 				},
 				StructuredContent: commentList,
@@ -428,6 +498,9 @@ func CommentListByFileVersion(engine *twapi.Engine) toolsets.ToolWrapper {
 						Type:        "integer",
 						Description: "Number of results per page for pagination.",
 					},
+					"since":      commentSinceSchema(),
+					"order_mode": commentOrderModeSchema(),
+					"count_only": countOnlySchema(),
 				},
 				Required: []string{"file_version_id"},
 			},
@@ -435,6 +508,9 @@ func CommentListByFileVersion(engine *twapi.Engine) toolsets.ToolWrapper {
 		},
 		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			var commentListRequest projects.CommentListRequest
+			var since *time.Time
+			var orderMode string
+			var countOnly bool
 
 			var arguments map[string]any
 			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
@@ -445,12 +521,29 @@ func CommentListByFileVersion(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalParam(&commentListRequest.Filters.SearchTerm, "search_term"),
 				helpers.OptionalNumericParam(&commentListRequest.Filters.Page, "page"),
 				helpers.OptionalNumericParam(&commentListRequest.Filters.PageSize, "page_size"),
+				helpers.OptionalTimePointerParam(&since, "since"),
+				helpers.OptionalParam(&orderMode, "order_mode", helpers.RestrictValues(orderModes...)),
+				helpers.OptionalParam(&countOnly, "count_only"),
 			)
 			if err != nil {
 				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
 			}
 
-			commentList, err := projects.CommentList(ctx, engine, commentListRequest)
+			if countOnly {
+				return listCount(ctx, engine, queryRequest[projects.CommentListRequest]{
+					request: commentListRequest,
+					query:   commentListQuery(since, ""),
+				})
+			}
+
+			if passthroughEnabled(ctx) {
+				return passthrough(ctx, engine, commentListRequest, commentListQuery(since, orderMode),
+					"failed to list comments")
+			}
+
+			commentList, err := executeWithQuery[*projects.CommentListResponse](ctx, engine, commentListRequest,
+				commentListQuery(since, orderMode),
+			)
 			if err != nil {
 				return helpers.HandleAPIError(err, "failed to list comments")
 			}
@@ -459,10 +552,12 @@ func CommentListByFileVersion(engine *twapi.Engine) toolsets.ToolWrapper {
 			if err != nil {
 				return nil, err
 			}
+			linked := helpers.WebLinker(ctx, encoded, commentPathBuilder)
 			return &mcp.CallToolResult{
+				Meta: helpers.WebLinkedEntitiesMeta(linked),
 				Content: []mcp.Content{
 					&mcp.TextContent{
-						Text: string(helpers.WebLinker(ctx, encoded, commentPathBuilder)),
+						Text: string(linked),
 					},
 				},
 				StructuredContent: commentList,
@@ -500,6 +595,9 @@ func CommentListByMilestone(engine *twapi.Engine) toolsets.ToolWrapper {
 						Type:        "integer",
 						Description: "Number of results per page for pagination.",
 					},
+					"since":      commentSinceSchema(),
+					"order_mode": commentOrderModeSchema(),
+					"count_only": countOnlySchema(),
 				},
 				Required: []string{"milestone_id"},
 			},
@@ -507,6 +605,9 @@ func CommentListByMilestone(engine *twapi.Engine) toolsets.ToolWrapper {
 		},
 		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			var commentListRequest projects.CommentListRequest
+			var since *time.Time
+			var orderMode string
+			var countOnly bool
 
 			var arguments map[string]any
 			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
@@ -517,12 +618,29 @@ func CommentListByMilestone(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalParam(&commentListRequest.Filters.SearchTerm, "search_term"),
 				helpers.OptionalNumericParam(&commentListRequest.Filters.Page, "page"),
 				helpers.OptionalNumericParam(&commentListRequest.Filters.PageSize, "page_size"),
+				helpers.OptionalTimePointerParam(&since, "since"),
+				helpers.OptionalParam(&orderMode, "order_mode", helpers.RestrictValues(orderModes...)),
+				helpers.OptionalParam(&countOnly, "count_only"),
 			)
 			if err != nil {
 				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
 			}
 
-			commentList, err := projects.CommentList(ctx, engine, commentListRequest)
+			if countOnly {
+				return listCount(ctx, engine, queryRequest[projects.CommentListRequest]{
+					request: commentListRequest,
+					query:   commentListQuery(since, ""),
+				})
+			}
+
+			if passthroughEnabled(ctx) {
+				return passthrough(ctx, engine, commentListRequest, commentListQuery(since, orderMode),
+					"failed to list comments")
+			}
+
+			commentList, err := executeWithQuery[*projects.CommentListResponse](ctx, engine, commentListRequest,
+				commentListQuery(since, orderMode),
+			)
 			if err != nil {
 				return helpers.HandleAPIError(err, "failed to list comments")
 			}
@@ -531,10 +649,12 @@ func CommentListByMilestone(engine *twapi.Engine) toolsets.ToolWrapper {
 			if err != nil {
 				return nil, err
 			}
+			linked := helpers.WebLinker(ctx, encoded, commentPathBuilder)
 			return &mcp.CallToolResult{
+				Meta: helpers.WebLinkedEntitiesMeta(linked),
 				Content: []mcp.Content{
 					&mcp.TextContent{
-						Text: string(helpers.WebLinker(ctx, encoded, commentPathBuilder)),
+						Text: string(linked),
 					},
 				},
 				StructuredContent: commentList,
@@ -572,6 +692,9 @@ func CommentListByNotebook(engine *twapi.Engine) toolsets.ToolWrapper {
 						Type:        "integer",
 						Description: "Number of results per page for pagination.",
 					},
+					"since":      commentSinceSchema(),
+					"order_mode": commentOrderModeSchema(),
+					"count_only": countOnlySchema(),
 				},
 				Required: []string{"notebook_id"},
 			},
@@ -579,6 +702,9 @@ func CommentListByNotebook(engine *twapi.Engine) toolsets.ToolWrapper {
 		},
 		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			var commentListRequest projects.CommentListRequest
+			var since *time.Time
+			var orderMode string
+			var countOnly bool
 
 			var arguments map[string]any
 			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
@@ -589,12 +715,29 @@ func CommentListByNotebook(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalParam(&commentListRequest.Filters.SearchTerm, "search_term"),
 				helpers.OptionalNumericParam(&commentListRequest.Filters.Page, "page"),
 				helpers.OptionalNumericParam(&commentListRequest.Filters.PageSize, "page_size"),
+				helpers.OptionalTimePointerParam(&since, "since"),
+				helpers.OptionalParam(&orderMode, "order_mode", helpers.RestrictValues(orderModes...)),
+				helpers.OptionalParam(&countOnly, "count_only"),
 			)
 			if err != nil {
 				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
 			}
 
-			commentList, err := projects.CommentList(ctx, engine, commentListRequest)
+			if countOnly {
+				return listCount(ctx, engine, queryRequest[projects.CommentListRequest]{
+					request: commentListRequest,
+					query:   commentListQuery(since, ""),
+				})
+			}
+
+			if passthroughEnabled(ctx) {
+				return passthrough(ctx, engine, commentListRequest, commentListQuery(since, orderMode),
+					"failed to list comments")
+			}
+
+			commentList, err := executeWithQuery[*projects.CommentListResponse](ctx, engine, commentListRequest,
+				commentListQuery(since, orderMode),
+			)
 			if err != nil {
 				return helpers.HandleAPIError(err, "failed to list comments")
 			}
@@ -603,10 +746,12 @@ func CommentListByNotebook(engine *twapi.Engine) toolsets.ToolWrapper {
 			if err != nil {
 				return nil, err
 			}
+			linked := helpers.WebLinker(ctx, encoded, commentPathBuilder)
 			return &mcp.CallToolResult{
+				Meta: helpers.WebLinkedEntitiesMeta(linked),
 				Content: []mcp.Content{
 					&mcp.TextContent{
-						Text: string(helpers.WebLinker(ctx, encoded, commentPathBuilder)),
+						Text: string(linked),
 					},
 				},
 				StructuredContent: commentList,
@@ -644,6 +789,9 @@ func CommentListByTask(engine *twapi.Engine) toolsets.ToolWrapper {
 						Type:        "integer",
 						Description: "Number of results per page for pagination.",
 					},
+					"since":      commentSinceSchema(),
+					"order_mode": commentOrderModeSchema(),
+					"count_only": countOnlySchema(),
 				},
 				Required: []string{"task_id"},
 			},
@@ -651,6 +799,9 @@ func CommentListByTask(engine *twapi.Engine) toolsets.ToolWrapper {
 		},
 		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			var commentListRequest projects.CommentListRequest
+			var since *time.Time
+			var orderMode string
+			var countOnly bool
 
 			var arguments map[string]any
 			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
@@ -661,12 +812,29 @@ func CommentListByTask(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalParam(&commentListRequest.Filters.SearchTerm, "search_term"),
 				helpers.OptionalNumericParam(&commentListRequest.Filters.Page, "page"),
 				helpers.OptionalNumericParam(&commentListRequest.Filters.PageSize, "page_size"),
+				helpers.OptionalTimePointerParam(&since, "since"),
+				helpers.OptionalParam(&orderMode, "order_mode", helpers.RestrictValues(orderModes...)),
+				helpers.OptionalParam(&countOnly, "count_only"),
 			)
 			if err != nil {
 				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
 			}
 
-			commentList, err := projects.CommentList(ctx, engine, commentListRequest)
+			if countOnly {
+				return listCount(ctx, engine, queryRequest[projects.CommentListRequest]{
+					request: commentListRequest,
+					query:   commentListQuery(since, ""),
+				})
+			}
+
+			if passthroughEnabled(ctx) {
+				return passthrough(ctx, engine, commentListRequest, commentListQuery(since, orderMode),
+					"failed to list comments")
+			}
+
+			commentList, err := executeWithQuery[*projects.CommentListResponse](ctx, engine, commentListRequest,
+				commentListQuery(since, orderMode),
+			)
 			if err != nil {
 				return helpers.HandleAPIError(err, "failed to list comments")
 			}
@@ -675,10 +843,12 @@ func CommentListByTask(engine *twapi.Engine) toolsets.ToolWrapper {
 			if err != nil {
 				return nil, err
 			}
+			linked := helpers.WebLinker(ctx, encoded, commentPathBuilder)
 			return &mcp.CallToolResult{
+				Meta: helpers.WebLinkedEntitiesMeta(linked),
 				Content: []mcp.Content{
 					&mcp.TextContent{
-						Text: string(helpers.WebLinker(ctx, encoded, commentPathBuilder)),
+						Text: string(linked),
 					},
 				},
 				StructuredContent: commentList,
@@ -716,3 +886,78 @@ func commentPathBuilder(object map[string]any) string {
 	}
 	return fmt.Sprintf("/#%v/%v?c=%v", relatedObjectType, relatedObjectID, id)
 }
+
+// commentAttachmentsCreateRequest extends the comment creation with the file
+// attachments of the API, not supported by the SDK.
+type commentAttachmentsCreateRequest struct {
+	projects.CommentCreateRequest
+
+	// Attachments is the comma-separated list of the IDs of the existing files
+	// attached to the comment.
+	Attachments string `json:"attachments,omitempty"`
+	// PendingFileAttachments is the comma-separated list of the references of
+	// the uploaded files attached to the comment.
+	PendingFileAttachments string `json:"pendingFileAttachments,omitempty"`
+}
+
+// HTTPRequest creates an HTTP request for the commentAttachmentsCreateRequest.
+func (c commentAttachmentsCreateRequest) HTTPRequest(ctx context.Context, server string) (*http.Request, error) {
+	// the SDK request resolves the path of the commented object
+	req, err := c.CommentCreateRequest.HTTPRequest(ctx, server)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := struct {
+		Comment commentAttachmentsCreateRequest `json:"comment"`
+	}{Comment: c}
+
+	var body bytes.Buffer
+	if err := json.NewEncoder(&body).Encode(payload); err != nil {
+		return nil, fmt.Errorf("failed to encode create comment request: %w", err)
+	}
+
+	req, err = http.NewRequestWithContext(ctx, http.MethodPost, req.URL.String(), &body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+// commentSinceSchema returns the schema of the since parameter of the comment
+// list tools.
+func commentSinceSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:   "string",
+		Format: "date-time",
+		Description: "Only return the comments posted or updated after this date and time. Use it when polling for " +
+			"new replies, passing the date of the last comment seen. The date format follows RFC3339 - " +
+			"YYYY-MM-DDTHH:MM:SSZ.",
+	}
+}
+
+// commentOrderModeSchema returns the schema of the order_mode parameter of the
+// comment list tools.
+func commentOrderModeSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type: "string",
+		Description: "The sort direction of the comments by posting date. Possible values are: asc, desc. Use asc " +
+			"to read a thread in the order it was written.",
+		Enum: enumSchemaValues(orderModes),
+	}
+}
+
+// commentListQuery returns the API query parameters to filter the comments
+// since the given date and sort them by posting date.
+func commentListQuery(since *time.Time, orderMode string) url.Values {
+	query := make(url.Values)
+	if since != nil {
+		query.Set("updatedAfter", since.UTC().Format(time.RFC3339))
+	}
+	if orderMode != "" {
+		query.Set("orderBy", "date")
+		query.Set("orderMode", orderMode)
+	}
+	return query
+}
@@ -1,7 +1,9 @@
 package twprojects_test
 
 import (
+	"encoding/json"
 	"net/http"
+	"net/url"
 	"testing"
 
 	"github.com/teamwork/mcp/internal/testutil"
@@ -20,6 +22,38 @@ func TestCommentCreate(t *testing.T) {
 	})
 }
 
+func TestCommentCreateAttachments(t *testing.T) {
+	var body map[string]map[string]any
+	mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(r *http.Request) (int, []byte) {
+		if r.Method != http.MethodPost {
+			return http.StatusOK, []byte(`{}`)
+		}
+		if r.URL.Path != "/tasks/123/comments.json" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("failed to decode body: %v", err)
+		}
+		return http.StatusCreated, []byte(`{"id":"456"}`)
+	})
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodCommentCreate.String(), map[string]any{
+		"object": map[string]any{
+			"type": "tasks",
+			"id":   float64(123),
+		},
+		"body": "See the attached mockups",
+		"attachments": map[string]any{
+			"file_ids":          []any{float64(10), float64(11)},
+			"pending_file_refs": []any{"tf_abc"},
+		},
+	})
+
+	comment := body["comment"]
+	if comment["attachments"] != "10,11" || comment["pendingFileAttachments"] != "tf_abc" {
+		t.Errorf("unexpected comment attachments: %v", comment)
+	}
+}
+
 func TestCommentUpdate(t *testing.T) {
 	mcpServer := mcpServerMock(t, http.StatusOK, []byte(`{}`))
 	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodCommentUpdate.String(), map[string]any{
@@ -52,6 +86,30 @@ func TestCommentList(t *testing.T) {
 	})
 }
 
+func TestCommentListByTaskSince(t *testing.T) {
+	var query url.Values
+	mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(r *http.Request) (int, []byte) {
+		query = r.URL.Query()
+		return http.StatusOK, []byte(`{}`)
+	})
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodCommentListByTask.String(), map[string]any{
+		"task_id":    float64(123),
+		"since":      "2025-01-01T10:00:00Z",
+		"order_mode": "asc",
+	})
+
+	expected := map[string]string{
+		"updatedAfter": "2025-01-01T10:00:00Z",
+		"orderBy":      "date",
+		"orderMode":    "asc",
+	}
+	for key, value := range expected {
+		if got := query.Get(key); got != value {
+			t.Errorf("expected %s=%q, got %q", key, value, got)
+		}
+	}
+}
+
 func TestCommentListByFileVersion(t *testing.T) {
 	mcpServer := mcpServerMock(t, http.StatusOK, []byte(`{}`))
 	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodCommentListByFileVersion.String(), map[string]any{
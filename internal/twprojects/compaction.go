@@ -0,0 +1,40 @@
+package twprojects
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/helpers"
+	"github.com/teamwork/mcp/internal/toolsets"
+)
+
+// compactionMiddleware removes the null and empty fields from the tool
+// results, which don't give any information to the model but take a large
+// part of the responses.
+func compactionMiddleware() toolsets.ToolMiddleware {
+	return rewriteTextMiddleware(helpers.CompactJSON)
+}
+
+// rewriteTextMiddleware rewrites the text content of the successful tool
+// results. Only the text content is changed, as the structured content must
+// follow the output schema of the tool.
+func rewriteTextMiddleware(rewrite func(data []byte) ([]byte, bool)) toolsets.ToolMiddleware {
+	return func(next mcp.ToolHandler) mcp.ToolHandler {
+		return func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := next(ctx, request)
+			if err != nil || result == nil || result.IsError {
+				return result, err
+			}
+			for _, content := range result.Content {
+				text, ok := content.(*mcp.TextContent)
+				if !ok {
+					continue
+				}
+				if rewritten, ok := rewrite([]byte(text.Text)); ok {
+					text.Text = string(rewritten)
+				}
+			}
+			return result, err
+		}
+	}
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"time"
 
 	"github.com/google/jsonschema-go/jsonschema"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -197,6 +200,7 @@ func CompanyUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 			InputSchema: &jsonschema.Schema{
 				Type: "object",
 				Properties: map[string]*jsonschema.Schema{
+					helpers.ExpectedUpdatedAtParam: helpers.ExpectedUpdatedAtSchema("company"),
 					"id": {
 						Type:        "integer",
 						Description: "The ID of the company to update.",
@@ -278,6 +282,7 @@ func CompanyUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 		},
 		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			var companyUpdateRequest projects.CompanyUpdateRequest
+			var expectedUpdatedAt *time.Time
 
 			var arguments map[string]any
 			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
@@ -285,6 +290,7 @@ func CompanyUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 			}
 			err := helpers.ParamGroup(arguments,
 				helpers.RequiredNumericParam(&companyUpdateRequest.Path.ID, "id"),
+				helpers.OptionalTimePointerParam(&expectedUpdatedAt, helpers.ExpectedUpdatedAtParam),
 				helpers.OptionalPointerParam(&companyUpdateRequest.Name, "name"),
 				helpers.OptionalPointerParam(&companyUpdateRequest.AddressOne, "address_one"),
 				helpers.OptionalPointerParam(&companyUpdateRequest.AddressTwo, "address_two"),
@@ -307,6 +313,18 @@ func CompanyUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
 			}
 
+			if expectedUpdatedAt != nil {
+				companyResponse, err := projects.CompanyGet(ctx, engine, projects.NewCompanyGetRequest(companyUpdateRequest.Path.ID))
+				if err != nil {
+					return helpers.HandleAPIError(err, "failed to get company")
+				}
+				conflict := helpers.CheckConflict("company", companyUpdateRequest.Path.ID, *expectedUpdatedAt,
+					companyResponse.Company.UpdatedAt)
+				if conflict != nil {
+					return conflict, nil
+				}
+			}
+
 			_, err = projects.CompanyUpdate(ctx, engine, companyUpdateRequest)
 			if err != nil {
 				return helpers.HandleAPIError(err, "failed to update company")
@@ -376,6 +394,10 @@ func CompanyGet(engine *twapi.Engine) toolsets.ToolWrapper {
 						Type:        "integer",
 						Description: "The ID of the company to get.",
 					},
+					"include_logo": {
+						Type:        "boolean",
+						Description: "If true, the company logo is returned as an image resource link.",
+					},
 				},
 				Required: []string{"id"},
 			},
@@ -383,6 +405,7 @@ func CompanyGet(engine *twapi.Engine) toolsets.ToolWrapper {
 		},
 		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			var companyGetRequest projects.CompanyGetRequest
+			var includeLogo bool
 
 			var arguments map[string]any
 			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
@@ -390,30 +413,37 @@ func CompanyGet(engine *twapi.Engine) toolsets.ToolWrapper {
 			}
 			err := helpers.ParamGroup(arguments,
 				helpers.RequiredNumericParam(&companyGetRequest.Path.ID, "id"),
+				helpers.OptionalParam(&includeLogo, "include_logo"),
 			)
 			if err != nil {
 				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
 			}
 
-			company, err := projects.CompanyGet(ctx, engine, companyGetRequest)
+			company, err := twapi.Execute[projects.CompanyGetRequest, *companyGetResponse](ctx, engine, companyGetRequest)
 			if err != nil {
 				return helpers.HandleAPIError(err, "failed to get company")
 			}
 
-			encoded, err := json.Marshal(company)
+			encoded, err := json.Marshal(company.CompanyGetResponse)
 			if err != nil {
 				return nil, err
 			}
-			return &mcp.CallToolResult{
+			linked := helpers.WebLinker(ctx, encoded,
+				helpers.WebLinkerWithIDPathBuilder("/app/clients"),
+			)
+			result := &mcp.CallToolResult{
+				Meta: helpers.WebLinkedEntitiesMeta(linked),
 				Content: []mcp.Content{
 					&mcp.TextContent{
-						Text: string(helpers.WebLinker(ctx, encoded,
-							helpers.WebLinkerWithIDPathBuilder("/app/clients"),
-						)),
+						Text: string(linked),
 					},
 				},
-				StructuredContent: company,
-			}, nil
+				StructuredContent: company.CompanyGetResponse,
+			}
+			if includeLogo && company.logoURL != "" {
+				result.Content = append(result.Content, helpers.NewImageResourceLink("logo", "Company Logo", company.logoURL))
+			}
+			return result, nil
 		},
 	}
 }
@@ -457,12 +487,14 @@ func CompanyList(engine *twapi.Engine) toolsets.ToolWrapper {
 						Type:        "integer",
 						Description: "Number of results per page for pagination.",
 					},
+					"count_only": countOnlySchema(),
 				},
 			},
 			OutputSchema: companyListOutputSchema,
 		},
 		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			var companyListRequest projects.CompanyListRequest
+			var countOnly bool
 
 			var arguments map[string]any
 			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
@@ -474,11 +506,16 @@ func CompanyList(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalPointerParam(&companyListRequest.Filters.MatchAllTags, "match_all_tags"),
 				helpers.OptionalNumericParam(&companyListRequest.Filters.Page, "page"),
 				helpers.OptionalNumericParam(&companyListRequest.Filters.PageSize, "page_size"),
+				helpers.OptionalParam(&countOnly, "count_only"),
 			)
 			if err != nil {
 				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
 			}
 
+			if countOnly {
+				return listCount(ctx, engine, companyListRequest)
+			}
+
 			companyList, err := projects.CompanyList(ctx, engine, companyListRequest)
 			if err != nil {
 				return helpers.HandleAPIError(err, "failed to list companies")
@@ -488,12 +525,14 @@ func CompanyList(engine *twapi.Engine) toolsets.ToolWrapper {
 			if err != nil {
 				return nil, err
 			}
+			linked := helpers.WebLinker(ctx, encoded,
+				helpers.WebLinkerWithIDPathBuilder("/app/clients"),
+			)
 			return &mcp.CallToolResult{
+				Meta: helpers.WebLinkedEntitiesMeta(linked),
 				Content: []mcp.Content{
 					&mcp.TextContent{
-						Text: string(helpers.WebLinker(ctx, encoded,
-							helpers.WebLinkerWithIDPathBuilder("/app/clients"),
-						)),
+						Text: string(linked),
 					},
 				},
 				StructuredContent: companyList,
@@ -501,3 +540,37 @@ func CompanyList(engine *twapi.Engine) toolsets.ToolWrapper {
 		},
 	}
 }
+
+// companyGetResponse extends the company response with the logo URL, which
+// isn't decoded by the SDK.
+type companyGetResponse struct {
+	projects.CompanyGetResponse
+
+	logoURL string
+}
+
+// HandleHTTPResponse handles the HTTP response for the companyGetResponse.
+func (c *companyGetResponse) HandleHTTPResponse(resp *http.Response) error {
+	if resp.StatusCode != http.StatusOK {
+		return twapi.NewHTTPError(resp, "failed to retrieve company")
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read retrieve company response: %w", err)
+	}
+	if err := json.Unmarshal(body, &c.CompanyGetResponse); err != nil {
+		return fmt.Errorf("failed to decode retrieve company response: %w", err)
+	}
+
+	var logo struct {
+		Company struct {
+			LogoURL string `json:"logoUrl"`
+		} `json:"company"`
+	}
+	if err := json.Unmarshal(body, &logo); err != nil {
+		return fmt.Errorf("failed to decode retrieve company response: %w", err)
+	}
+	c.logoURL = logo.Company.LogoURL
+	return nil
+}
@@ -69,6 +69,14 @@ func TestCompanyGet(t *testing.T) {
 	})
 }
 
+func TestCompanyGetWithLogo(t *testing.T) {
+	mcpServer := mcpServerMock(t, http.StatusOK, []byte(`{"company":{"id":123,"logoUrl":"https://example.com/logo.png"}}`))
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodCompanyGet.String(), map[string]any{
+		"id":           float64(123),
+		"include_logo": true,
+	})
+}
+
 func TestCompanyList(t *testing.T) {
 	mcpServer := mcpServerMock(t, http.StatusOK, []byte(`{}`))
 	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodCompanyList.String(), map[string]any{
@@ -0,0 +1,102 @@
+package twprojects
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/helpers"
+	"github.com/teamwork/twapi-go-sdk"
+	"github.com/teamwork/twapi-go-sdk/projects"
+)
+
+// compensationTimeout is the maximum time reverting the changes of a failed
+// composite tool call.
+const compensationTimeout = 30 * time.Second
+
+// compensation reverts a change made by a composite tool.
+type compensation struct {
+	// description is the manual cleanup instruction when the change can't be
+	// reverted (e.g. "delete task 123").
+	description string
+	revert      func(ctx context.Context) error
+}
+
+// compensationLog records how to revert the changes of a composite tool call
+// (e.g. creating a project and its content), so a failure part way through
+// doesn't leave half-made changes behind. The changes are reverted in the
+// reverse order they were made, and those that can't be reverted are returned
+// as manual cleanup instructions.
+type compensationLog struct {
+	engine        *twapi.Engine
+	compensations []compensation
+}
+
+func newCompensationLog(engine *twapi.Engine) *compensationLog {
+	return &compensationLog{engine: engine}
+}
+
+// record adds the compensation of a change just made.
+func (c *compensationLog) record(description string, revert func(ctx context.Context) error) {
+	c.compensations = append(c.compensations, compensation{description: description, revert: revert})
+}
+
+// recordProject records the creation of a project, reverted by deleting it
+// with all its content.
+func (c *compensationLog) recordProject(projectID int64) {
+	c.record(fmt.Sprintf("delete project %d", projectID), func(ctx context.Context) error {
+		_, err := projects.ProjectDelete(ctx, c.engine, projects.NewProjectDeleteRequest(projectID))
+		return err
+	})
+}
+
+// recordTask records the creation of a task, reverted by deleting it.
+func (c *compensationLog) recordTask(taskID int64) {
+	c.record(fmt.Sprintf("delete task %d", taskID), func(ctx context.Context) error {
+		_, err := projects.TaskDelete(ctx, c.engine, projects.NewTaskDeleteRequest(taskID))
+		return err
+	})
+}
+
+// recordTimelog records the creation of a timelog, reverted by deleting it.
+func (c *compensationLog) recordTimelog(timelogID int64) {
+	c.record(fmt.Sprintf("delete timelog %d", timelogID), func(ctx context.Context) error {
+		_, err := projects.TimelogDelete(ctx, c.engine, projects.NewTimelogDeleteRequest(timelogID))
+		return err
+	})
+}
+
+// rollback reverts the recorded changes, returning the manual cleanup
+// instructions of the changes that couldn't be reverted. The request context
+// may be cancelled, so the changes are reverted with their own deadline.
+func (c *compensationLog) rollback(ctx context.Context) []string {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), compensationTimeout)
+	defer cancel()
+
+	var manual []string
+	for i := len(c.compensations) - 1; i >= 0; i-- {
+		compensation := c.compensations[i]
+		if err := compensation.revert(ctx); err != nil {
+			manual = append(manual, fmt.Sprintf("%s (%s)", compensation.description, err))
+		}
+	}
+	c.compensations = nil
+	return manual
+}
+
+// fail reverts the recorded changes and returns the error result of the tool
+// call, describing the changes reverted or left to clean up manually.
+func (c *compensationLog) fail(ctx context.Context, err error, message string) (*mcp.CallToolResult, error) {
+	if len(c.compensations) == 0 {
+		return helpers.HandleAPIError(err, message)
+	}
+	count := len(c.compensations)
+	manual := c.rollback(ctx)
+	if len(manual) == 0 {
+		return helpers.HandleAPIError(err, message+", the changes made before the failure were reverted")
+	}
+	return helpers.HandleAPIError(err, fmt.Sprintf("%s, and %d of the %d changes made before the failure couldn't "+
+		"be reverted; clean them up manually: %s", message, len(manual), count, strings.Join(manual, "; ")))
+}
@@ -0,0 +1,202 @@
+package twprojects
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/helpers"
+	"github.com/teamwork/mcp/internal/toolsets"
+	twapi "github.com/teamwork/twapi-go-sdk"
+	"github.com/teamwork/twapi-go-sdk/projects"
+)
+
+// List of methods available in the Teamwork.com MCP service.
+//
+// The naming convention for methods follows a pattern described here:
+// https://github.com/github/github-mcp-server/issues/333
+const (
+	MethodCompletedTaskList toolsets.Method = "twprojects-list_completed_tasks"
+)
+
+// completedTasksMaxItems is the maximum number of tasks loaded to list the
+// completed tasks.
+const completedTasksMaxItems = 2000
+
+func init() {
+	// register the toolset methods
+	toolsets.RegisterMethod(MethodCompletedTaskList)
+}
+
+// completedTask is a task completed in the range.
+type completedTask struct {
+	ID          int64     `json:"id"`
+	Name        string    `json:"name"`
+	CompletedAt time.Time `json:"completedAt"`
+	CompletedBy *int64    `json:"completedBy,omitempty"`
+	TasklistID  int64     `json:"tasklistId,omitempty"`
+}
+
+// completedTaskList is the result of the completed task listing.
+type completedTaskList struct {
+	CompletedAfter  time.Time       `json:"completedAfter"`
+	CompletedBefore time.Time       `json:"completedBefore"`
+	Total           int             `json:"total"`
+	Tasks           []completedTask `json:"tasks"`
+	// Truncated is true when not all tasks were loaded.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// CompletedTaskList lists the tasks completed in a date range in Teamwork.com,
+// most recent first.
+func CompletedTaskList(engine *twapi.Engine) toolsets.ToolWrapper {
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name: string(MethodCompletedTaskList),
+			Description: "List the tasks completed in a date range in Teamwork.com, most recently completed first, " +
+				"with who completed them. Useful for weekly reviews and reports of what shipped, instead of scanning " +
+				"all tasks for the completed ones.",
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "List Completed Tasks",
+				ReadOnlyHint: true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"completed_after": {
+						Type:   "string",
+						Format: "date-time",
+						Description: "List the tasks completed after this date and time. The date format follows " +
+							"RFC3339 - YYYY-MM-DDTHH:MM:SSZ.",
+					},
+					"completed_before": {
+						Type:   "string",
+						Format: "date-time",
+						Description: "List the tasks completed before this date and time. The date format follows " +
+							"RFC3339 - YYYY-MM-DDTHH:MM:SSZ. Defaults to now.",
+					},
+					"project_ids": {
+						Type:        "array",
+						Description: "A list of project IDs to filter the tasks by project.",
+						Items:       &jsonschema.Schema{Type: "integer"},
+					},
+					"assignee_user_ids": {
+						Type:        "array",
+						Description: "A list of user IDs to filter the tasks by assigned users.",
+						Items:       &jsonschema.Schema{Type: "integer"},
+					},
+				},
+				Required: []string{"completed_after"},
+			},
+		},
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var completion taskCompletionFilters
+			var projectIDs, assigneeUserIDs []int64
+
+			var arguments map[string]any
+			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("failed to decode request: %s", err.Error())), nil
+			}
+			var completedAfter time.Time
+			err := helpers.ParamGroup(arguments,
+				helpers.RequiredTimeParam(&completedAfter, "completed_after"),
+				helpers.OptionalTimePointerParam(&completion.before, "completed_before"),
+				helpers.OptionalNumericListParam(&projectIDs, "project_ids"),
+				helpers.OptionalNumericListParam(&assigneeUserIDs, "assignee_user_ids"),
+			)
+			if err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
+			}
+			completion.after = &completedAfter
+			if completion.before == nil {
+				completion.before = twapi.Ptr(time.Now().UTC().Truncate(time.Second))
+			}
+			if err := completion.validate(); err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
+			}
+
+			query := make(url.Values)
+			completion.apply(query)
+			if len(projectIDs) > 0 {
+				ids := make([]string, len(projectIDs))
+				for i, id := range projectIDs {
+					ids[i] = strconv.FormatInt(id, 10)
+				}
+				query.Set("projectIds", strings.Join(ids, ","))
+			}
+
+			tasks, truncated, err := helpers.FetchAllPages(ctx, helpers.AutoPagination{
+				PageSize: 100,
+				MaxItems: completedTasksMaxItems,
+			}, func(ctx context.Context, page, pageSize int64) ([]completedTask, bool, error) {
+				taskListRequest := projects.NewTaskListRequest()
+				taskListRequest.Filters.AssigneeUserIDs = assigneeUserIDs
+				taskListRequest.Filters.Page = page
+				taskListRequest.Filters.PageSize = pageSize
+
+				response, err := executeWithQuery[*projects.TaskListResponse](ctx, engine, taskListRequest, query)
+				if err != nil {
+					return nil, false, err
+				}
+				var tasks []completedTask
+				for _, task := range response.Tasks {
+					// the range is checked again, as the tasks completed without a
+					// completion date can't be placed in it
+					if task.CompletedAt == nil || task.CompletedAt.Before(*completion.after) ||
+						!task.CompletedAt.Before(*completion.before) {
+						continue
+					}
+					tasks = append(tasks, completedTask{
+						ID:          task.ID,
+						Name:        task.Name,
+						CompletedAt: *task.CompletedAt,
+						CompletedBy: task.CompletedBy,
+						TasklistID:  task.Tasklist.ID,
+					})
+				}
+				return tasks, response.Meta.Page.HasMore, nil
+			})
+			if err != nil {
+				return helpers.HandleAPIError(err, "failed to list completed tasks")
+			}
+			slices.SortFunc(tasks, func(a, b completedTask) int {
+				return cmp.Or(b.CompletedAt.Compare(a.CompletedAt), cmp.Compare(a.ID, b.ID))
+			})
+
+			list := completedTaskList{
+				CompletedAfter:  completion.after.UTC(),
+				CompletedBefore: completion.before.UTC(),
+				Total:           len(tasks),
+				Tasks:           tasks,
+				Truncated:       truncated,
+			}
+			if list.Tasks == nil {
+				list.Tasks = []completedTask{}
+			}
+
+			encoded, err := json.Marshal(list)
+			if err != nil {
+				return nil, err
+			}
+			linked := helpers.WebLinker(ctx, encoded,
+				helpers.WebLinkerWithIDPathBuilder("/app/tasks"),
+			)
+			return &mcp.CallToolResult{
+				Meta: helpers.WebLinkedEntitiesMeta(linked),
+				Content: []mcp.Content{
+					&mcp.TextContent{
+						Text: string(linked),
+					},
+				},
+			}, nil
+		},
+	}
+}
@@ -0,0 +1,99 @@
+package twprojects_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/testutil"
+	"github.com/teamwork/mcp/internal/twprojects"
+)
+
+func TestCompletedTaskList(t *testing.T) {
+	mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(r *http.Request) (int, []byte) {
+		query := r.URL.Query()
+		if r.URL.Path != "/projects/api/v3/tasks.json" || query.Get("includeCompletedTasks") != "true" ||
+			query.Get("completedAfter") != "2025-03-01T00:00:00Z" || query.Get("completedBefore") != "2025-03-08T00:00:00Z" ||
+			query.Get("projectIds") != "123" {
+			t.Errorf("unexpected request %s %s", r.URL.Path, r.URL.RawQuery)
+		}
+		return http.StatusOK, []byte(`{"tasks":[` +
+			`{"id":1,"name":"First","completedAt":"2025-03-02T10:00:00Z","completedBy":7,"tasklist":{"id":5}},` +
+			`{"id":2,"name":"Open","status":"new","tasklist":{"id":5}},` +
+			`{"id":3,"name":"Last","completedAt":"2025-03-06T10:00:00Z","tasklist":{"id":5}},` +
+			`{"id":4,"name":"Later","completedAt":"2025-03-09T10:00:00Z","tasklist":{"id":5}}],` +
+			`"meta":{"page":{"hasMore":false}}}`)
+	})
+
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodCompletedTaskList.String(), map[string]any{
+		"completed_after":  "2025-03-01T00:00:00Z",
+		"completed_before": "2025-03-08T00:00:00Z",
+		"project_ids":      []any{float64(123)},
+	}, testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+		toolResult, ok := result.(*mcp.CallToolResult)
+		if !ok {
+			t.Fatalf("unexpected result type: %T", result)
+		}
+		if toolResult.IsError {
+			t.Fatalf("tool failed to execute: %v", toolResult.Content)
+		}
+		var list struct {
+			Total int `json:"total"`
+			Tasks []struct {
+				ID int64 `json:"id"`
+			} `json:"tasks"`
+		}
+		if err := json.Unmarshal([]byte(toolResult.Content[0].(*mcp.TextContent).Text), &list); err != nil {
+			t.Fatalf("failed to decode result: %v", err)
+		}
+		if list.Total != 2 || len(list.Tasks) != 2 || list.Tasks[0].ID != 3 || list.Tasks[1].ID != 1 {
+			t.Errorf("expected the tasks 3 and 1, got %+v", list)
+		}
+	}))
+}
+
+func TestTaskListCompletionFilters(t *testing.T) {
+	tests := []struct {
+		name      string
+		arguments map[string]any
+		query     string
+		isError   bool
+	}{{
+		name:      "completed range",
+		arguments: map[string]any{"completed_after": "2025-03-01T00:00:00Z"},
+		query:     "completedAfter=2025-03-01T00%3A00%3A00Z&includeCompletedTasks=true",
+	}, {
+		name: "empty range",
+		arguments: map[string]any{
+			"completed_after":  "2025-03-08T00:00:00Z",
+			"completed_before": "2025-03-01T00:00:00Z",
+		},
+		isError: true,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var query string
+			mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(r *http.Request) (int, []byte) {
+				query = r.URL.RawQuery
+				return http.StatusOK, []byte(`{"tasks":[],"meta":{"page":{"hasMore":false}}}`)
+			})
+
+			testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTaskList.String(), tt.arguments,
+				testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+					toolResult, ok := result.(*mcp.CallToolResult)
+					if !ok {
+						t.Fatalf("unexpected result type: %T", result)
+					}
+					if toolResult.IsError != tt.isError {
+						t.Errorf("unexpected error state %v: %v", toolResult.IsError, toolResult.Content)
+					}
+				}),
+			)
+			if tt.query != "" && query != tt.query {
+				t.Errorf("expected query %q, got %q", tt.query, query)
+			}
+		})
+	}
+}
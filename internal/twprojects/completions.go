@@ -0,0 +1,200 @@
+package twprojects
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/teamwork/mcp/internal/config"
+	"github.com/teamwork/mcp/internal/toolsets"
+	twapi "github.com/teamwork/twapi-go-sdk"
+	"github.com/teamwork/twapi-go-sdk/projects"
+)
+
+const (
+	// completionCacheTTL is the time the values of a completion search are
+	// reused, as clients usually request completions on every keystroke.
+	completionCacheTTL = time.Minute
+	// completionPageSize is the maximum number of values suggested for an
+	// argument.
+	completionPageSize = 20
+)
+
+// completionEntry is the cached result of a completion search.
+type completionEntry struct {
+	values    []string
+	expiresAt time.Time
+}
+
+// completions suggests values for the arguments of the tools and prompts,
+// searching the entities in Teamwork.com. The values are restricted to the
+// projects and people available in the sandbox.
+type completions struct {
+	engine  *twapi.Engine
+	sandbox *projectSandbox
+	// cache reuses the session store, as it already discards the least recently
+	// used searches when the limit is reached
+	cache *sessionStore[completionEntry]
+}
+
+func newCompletions(engine *twapi.Engine, sandbox *projectSandbox) *completions {
+	return &completions{
+		engine:  engine,
+		sandbox: sandbox,
+		cache:   newSessionStore[completionEntry](),
+	}
+}
+
+// register adds the argument completers to the toolset.
+func (c *completions) register(toolset *toolsets.Toolset) {
+	toolset.
+		AddCompleter("project_id", c.cached("project_id", c.projectIDs)).
+		AddCompleter("tasklist_id", c.cached("tasklist_id", c.tasklistIDs)).
+		AddCompleter("tag_id", c.cached("tag_id", c.tags(func(tag projects.Tag) string {
+			return strconv.FormatInt(tag.ID, 10)
+		}))).
+		AddCompleter("tag_name", c.cached("tag_name", c.tags(func(tag projects.Tag) string {
+			return tag.Name
+		}))).
+		AddCompleter("user_id", c.cached("user_id", c.users(func(user projects.User) string {
+			return strconv.FormatInt(user.ID, 10)
+		}))).
+		AddCompleter("user_name", c.cached("user_name", c.users(func(user projects.User) string {
+			return strings.TrimSpace(user.FirstName + " " + user.LastName)
+		})))
+}
+
+// cached reuses the values of recent searches. The installation and the user
+// are part of the key, as in HTTP mode the toolset is shared by all customers,
+// and each user can only see their own projects and people.
+func (c *completions) cached(argument string, completer toolsets.ArgumentCompleter) toolsets.ArgumentCompleter {
+	return func(ctx context.Context, value string, arguments map[string]string) ([]string, error) {
+		customerURL, _ := config.CustomerURLFromContext(ctx)
+		userID, _ := config.UserIDFromContext(ctx)
+		key := strings.Join([]string{customerURL, strconv.FormatInt(userID, 10), argument, arguments["project_id"],
+			strings.ToLower(value)}, "|")
+
+		var entry completionEntry
+		c.cache.update(key, func(cached *completionEntry) {
+			entry = *cached
+		})
+		if time.Now().Before(entry.expiresAt) {
+			return entry.values, nil
+		}
+
+		values, err := completer(ctx, value, arguments)
+		if err != nil {
+			return nil, err
+		}
+		c.cache.update(key, func(cached *completionEntry) {
+			*cached = completionEntry{
+				values:    values,
+				expiresAt: time.Now().Add(completionCacheTTL),
+			}
+		})
+		return values, nil
+	}
+}
+
+// projectIDs suggests the IDs of the projects matching the value.
+func (c *completions) projectIDs(ctx context.Context, value string, _ map[string]string) ([]string, error) {
+	request := projects.NewProjectListRequest()
+	request.Filters.SearchTerm = value
+	request.Filters.PageSize = completionPageSize
+
+	response, err := projects.ProjectList(ctx, c.engine, request)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]string, 0, len(response.Projects))
+	for _, project := range response.Projects {
+		allowed, err := c.sandbox.allows(ctx, sandboxReference{entity: "project", id: project.ID})
+		if err != nil {
+			return nil, err
+		}
+		if allowed {
+			values = append(values, strconv.FormatInt(project.ID, 10))
+		}
+	}
+	return values, nil
+}
+
+// tasklistIDs suggests the IDs of the tasklists matching the value, restricted
+// to the project when it was already resolved.
+func (c *completions) tasklistIDs(ctx context.Context, value string, arguments map[string]string) ([]string, error) {
+	request := projects.NewTasklistListRequest()
+	request.Filters.SearchTerm = value
+	request.Filters.PageSize = completionPageSize
+	if projectID, err := strconv.ParseInt(arguments["project_id"], 10, 64); err == nil {
+		if allowed, err := c.sandbox.allows(ctx, sandboxReference{entity: "project", id: projectID}); !allowed {
+			return nil, err
+		}
+		request.Path.ProjectID = projectID
+	}
+
+	response, err := projects.TasklistList(ctx, c.engine, request)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]string, 0, len(response.Tasklists))
+	for _, tasklist := range response.Tasklists {
+		allowed, err := c.sandbox.allows(ctx, sandboxReference{entity: "project", id: tasklist.Project.ID})
+		if err != nil {
+			return nil, err
+		}
+		if allowed {
+			values = append(values, strconv.FormatInt(tasklist.ID, 10))
+		}
+	}
+	return values, nil
+}
+
+// tags suggests the tags matching the value, converted by the given function.
+func (c *completions) tags(fn func(projects.Tag) string) toolsets.ArgumentCompleter {
+	return func(ctx context.Context, value string, _ map[string]string) ([]string, error) {
+		request := projects.NewTagListRequest()
+		request.Filters.SearchTerm = value
+		request.Filters.PageSize = completionPageSize
+
+		response, err := projects.TagList(ctx, c.engine, request)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]string, 0, len(response.Tags))
+		for _, tag := range response.Tags {
+			values = append(values, fn(tag))
+		}
+		return values, nil
+	}
+}
+
+// users suggests the users matching the value, converted by the given function.
+// The users are restricted to the project when it was already resolved, and to
+// the client company of the sandbox.
+func (c *completions) users(fn func(projects.User) string) toolsets.ArgumentCompleter {
+	return func(ctx context.Context, value string, arguments map[string]string) ([]string, error) {
+		request := projects.NewUserListRequest()
+		request.Filters.SearchTerm = value
+		request.Filters.PageSize = completionPageSize
+		if projectID, err := strconv.ParseInt(arguments["project_id"], 10, 64); err == nil {
+			if allowed, err := c.sandbox.allows(ctx, sandboxReference{entity: "project", id: projectID}); !allowed {
+				return nil, err
+			}
+			request.Path.ProjectID = projectID
+		}
+
+		response, err := projects.UserList(ctx, c.engine, request)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]string, 0, len(response.Users))
+		for _, user := range response.Users {
+			if c.sandbox.companyID > 0 && user.Company.ID != c.sandbox.companyID {
+				continue
+			}
+			values = append(values, fn(user))
+		}
+		return values, nil
+	}
+}
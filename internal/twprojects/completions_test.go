@@ -0,0 +1,79 @@
+package twprojects_test
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/teamwork/mcp/internal/config"
+	"github.com/teamwork/mcp/internal/testutil"
+	"github.com/teamwork/mcp/internal/toolsets"
+	"github.com/teamwork/mcp/internal/twprojects"
+)
+
+func TestCompleters(t *testing.T) {
+	engine := testutil.ProjectsEngineMock(http.StatusOK, []byte(`{"projects":[{"id":123,"name":"Website"}]}`))
+	group := twprojects.DefaultToolsetGroup(false, true, engine)
+	if err := group.EnableToolsets(toolsets.MethodAll); err != nil {
+		t.Fatalf("failed to enable toolsets: %v", err)
+	}
+
+	completer, ok := group.GetActiveCompleters()["project_id"]
+	if !ok {
+		t.Fatal("missing project_id completer")
+	}
+	values, err := completer(t.Context(), "web", nil)
+	if err != nil {
+		t.Fatalf("failed to complete: %v", err)
+	}
+	if !slices.Equal(values, []string{"123"}) {
+		t.Errorf("unexpected values: %v", values)
+	}
+}
+
+func TestCompletersSandbox(t *testing.T) {
+	engine := testutil.ProjectsEngineMock(http.StatusOK,
+		[]byte(`{"projects":[{"id":123,"name":"Website"},{"id":456,"name":"Website v2"}]}`))
+	group := twprojects.DefaultToolsetGroup(false, true, engine, twprojects.WithAllowedProjects([]int64{456}))
+	if err := group.EnableToolsets(toolsets.MethodAll); err != nil {
+		t.Fatalf("failed to enable toolsets: %v", err)
+	}
+
+	values, err := group.GetActiveCompleters()["project_id"](t.Context(), "web", nil)
+	if err != nil {
+		t.Fatalf("failed to complete: %v", err)
+	}
+	if !slices.Equal(values, []string{"456"}) {
+		t.Errorf("unexpected values: %v", values)
+	}
+
+	values, err = group.GetActiveCompleters()["tasklist_id"](t.Context(), "", map[string]string{"project_id": "123"})
+	if err != nil {
+		t.Fatalf("failed to complete: %v", err)
+	}
+	if len(values) > 0 {
+		t.Errorf("unexpected values for a project outside the sandbox: %v", values)
+	}
+}
+
+func TestCompletersCacheUsers(t *testing.T) {
+	var requests int
+	engine := testutil.ProjectsEngineMockFunc(func(*http.Request) (int, []byte) {
+		requests++
+		return http.StatusOK, []byte(`{"projects":[{"id":123,"name":"Website"}]}`)
+	})
+	group := twprojects.DefaultToolsetGroup(false, true, engine)
+	if err := group.EnableToolsets(toolsets.MethodAll); err != nil {
+		t.Fatalf("failed to enable toolsets: %v", err)
+	}
+
+	completer := group.GetActiveCompleters()["project_id"]
+	for _, userID := range []int64{1, 1, 2} {
+		if _, err := completer(config.WithUserID(t.Context(), userID), "web", nil); err != nil {
+			t.Fatalf("failed to complete: %v", err)
+		}
+	}
+	if requests != 2 {
+		t.Errorf("expected 2 requests, got %d", requests)
+	}
+}
@@ -0,0 +1,67 @@
+package twprojects
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/helpers"
+	twapi "github.com/teamwork/twapi-go-sdk"
+)
+
+// countOnlySchema returns the schema of the count_only parameter of the list
+// tools.
+func countOnlySchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type: "boolean",
+		Description: "If true, only the total number of matching records is returned, instead of the records " +
+			"themselves. Use it to answer questions like \"how many\" without listing every record. Pagination " +
+			"parameters are ignored.",
+	}
+}
+
+// countResponse contains the total number of records of a list request.
+type countResponse struct {
+	Meta struct {
+		Page struct {
+			Count int64 `json:"count"`
+		} `json:"page"`
+	} `json:"meta"`
+}
+
+// HandleHTTPResponse handles the HTTP response for the countResponse. If some
+// unexpected HTTP status code is returned by the API, a twapi.HTTPError is
+// returned.
+func (c *countResponse) HandleHTTPResponse(resp *http.Response) error {
+	if resp.StatusCode != http.StatusOK {
+		return twapi.NewHTTPError(resp, "failed to count records")
+	}
+	if err := json.NewDecoder(resp.Body).Decode(c); err != nil {
+		return fmt.Errorf("failed to decode count response: %w", err)
+	}
+	return nil
+}
+
+// listCount returns the total number of records matching the list request.
+func listCount[R twapi.HTTPRequester](
+	ctx context.Context,
+	engine *twapi.Engine,
+	request R,
+) (*mcp.CallToolResult, error) {
+	// only the first record is requested, as the total number of records is in
+	// the metadata of the response
+	response, err := executeWithQuery[*countResponse](ctx, engine, request, url.Values{
+		"page":     []string{"1"},
+		"pageSize": []string{"1"},
+	})
+	if err != nil {
+		return helpers.HandleAPIError(err, "failed to count records")
+	}
+	// the structured content isn't set, as it must follow the output schema of
+	// the list tool
+	return helpers.NewToolResultText(`{"count":%d}`, response.Meta.Page.Count), nil
+}
@@ -0,0 +1,270 @@
+package twprojects
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/helpers"
+	"github.com/teamwork/mcp/internal/toolsets"
+	twapi "github.com/teamwork/twapi-go-sdk"
+	"github.com/teamwork/twapi-go-sdk/projects"
+)
+
+// List of methods available in the Teamwork.com MCP service.
+//
+// The naming convention for methods follows a pattern described here:
+// https://github.com/github/github-mcp-server/issues/333
+const (
+	MethodCriticalPathGet toolsets.Method = "twprojects-get_critical_path"
+)
+
+const (
+	// criticalPathMaxTasks is the maximum number of tasks loaded to compute the
+	// critical path.
+	criticalPathMaxTasks = 5000
+	// criticalPathDayMinutes are the minutes of a working day, used to estimate
+	// the duration of the tasks without an estimate from their dates.
+	criticalPathDayMinutes = 8 * 60
+)
+
+// errCriticalPathCycle is returned when the task dependencies have a cycle.
+var errCriticalPathCycle = errors.New("the task dependencies have a cycle")
+
+func init() {
+	// register the toolset methods
+	toolsets.RegisterMethod(MethodCriticalPathGet)
+}
+
+// criticalPathTask is a task of the critical path computation. The times are
+// in minutes from the start of the project.
+type criticalPathTask struct {
+	ID              int64  `json:"id"`
+	Name            string `json:"name"`
+	DurationMinutes int64  `json:"durationMinutes"`
+	EarliestStart   int64  `json:"earliestStart"`
+	EarliestFinish  int64  `json:"earliestFinish"`
+	LatestStart     int64  `json:"latestStart"`
+	LatestFinish    int64  `json:"latestFinish"`
+	SlackMinutes    int64  `json:"slackMinutes"`
+	Critical        bool   `json:"critical"`
+
+	predecessors []int64
+}
+
+// criticalPath is the result of the critical path computation.
+type criticalPath struct {
+	ProjectID       int64              `json:"projectId"`
+	DurationMinutes int64              `json:"durationMinutes"`
+	CriticalPath    []int64            `json:"criticalPath"`
+	Tasks           []criticalPathTask `json:"tasks"`
+	// Truncated is true when the project has more tasks than the loaded ones.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// taskDurationMinutes returns the estimated duration of the task, falling back
+// to the working days between its start and due dates.
+func taskDurationMinutes(task projects.Task) int64 {
+	if task.EstimatedMinutes > 0 {
+		return task.EstimatedMinutes
+	}
+	if task.StartAt == nil || task.DueAt == nil || task.DueAt.Before(*task.StartAt) {
+		return 0
+	}
+	var days int64
+	for date := *task.StartAt; !date.After(*task.DueAt); date = date.AddDate(0, 0, 1) {
+		if date.Weekday() != time.Saturday && date.Weekday() != time.Sunday {
+			days++
+		}
+	}
+	return days * criticalPathDayMinutes
+}
+
+// computeCriticalPath computes the earliest and latest times of the tasks with
+// the critical path method, treating the predecessors as finish-to-start
+// dependencies. Predecessors outside the tasks (e.g. completed tasks) are
+// ignored. The tasks are returned sorted by earliest start.
+func computeCriticalPath(tasks []criticalPathTask) ([]criticalPathTask, []int64, int64, error) {
+	index := make(map[int64]int, len(tasks))
+	for i, task := range tasks {
+		index[task.ID] = i
+	}
+	successors := make([][]int, len(tasks))
+	pending := make([]int, len(tasks))
+	for i, task := range tasks {
+		for _, predecessor := range task.predecessors {
+			if j, ok := index[predecessor]; ok {
+				successors[j] = append(successors[j], i)
+				pending[i]++
+			}
+		}
+	}
+
+	// topological order, forward pass
+	order := make([]int, 0, len(tasks))
+	for i := range tasks {
+		if pending[i] == 0 {
+			order = append(order, i)
+		}
+	}
+	for k := 0; k < len(order); k++ {
+		i := order[k]
+		tasks[i].EarliestFinish = tasks[i].EarliestStart + tasks[i].DurationMinutes
+		for _, j := range successors[i] {
+			tasks[j].EarliestStart = max(tasks[j].EarliestStart, tasks[i].EarliestFinish)
+			if pending[j]--; pending[j] == 0 {
+				order = append(order, j)
+			}
+		}
+	}
+	if len(order) != len(tasks) {
+		return nil, nil, 0, errCriticalPathCycle
+	}
+
+	var duration int64
+	for _, task := range tasks {
+		duration = max(duration, task.EarliestFinish)
+	}
+
+	// backward pass
+	for k := len(order) - 1; k >= 0; k-- {
+		i := order[k]
+		tasks[i].LatestFinish = duration
+		for _, j := range successors[i] {
+			tasks[i].LatestFinish = min(tasks[i].LatestFinish, tasks[j].LatestStart)
+		}
+		tasks[i].LatestStart = tasks[i].LatestFinish - tasks[i].DurationMinutes
+		tasks[i].SlackMinutes = tasks[i].LatestStart - tasks[i].EarliestStart
+		tasks[i].Critical = tasks[i].SlackMinutes == 0
+	}
+
+	// the critical path is rebuilt backwards from the last critical task,
+	// through the critical predecessors finishing when the task starts
+	var path []int64
+	current := -1
+	for i, task := range tasks {
+		if task.Critical && task.EarliestFinish == duration && (current == -1 || task.ID < tasks[current].ID) {
+			current = i
+		}
+	}
+	for current != -1 {
+		path = append(path, tasks[current].ID)
+		next := -1
+		for _, predecessor := range tasks[current].predecessors {
+			j, ok := index[predecessor]
+			if !ok || !tasks[j].Critical || tasks[j].EarliestFinish != tasks[current].EarliestStart {
+				continue
+			}
+			if next == -1 || tasks[j].ID < tasks[next].ID {
+				next = j
+			}
+		}
+		current = next
+	}
+	slices.Reverse(path)
+
+	slices.SortFunc(tasks, func(a, b criticalPathTask) int {
+		return cmp.Or(cmp.Compare(a.EarliestStart, b.EarliestStart), cmp.Compare(a.ID, b.ID))
+	})
+	return tasks, path, duration, nil
+}
+
+// CriticalPathGet computes the critical path of a project in Teamwork.com from
+// the task dependencies.
+func CriticalPathGet(engine *twapi.Engine) toolsets.ToolWrapper {
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name: string(MethodCriticalPathGet),
+			Description: "Compute the critical path of a project in Teamwork.com from the dependencies " +
+				"(predecessors) of its incomplete tasks. It returns the sequence of task IDs that determines the " +
+				"project duration and, for each task, its earliest and latest start and finish and its slack, in " +
+				"minutes from the start of the project. Tasks with zero slack are critical: any delay on them delays " +
+				"the whole project. The duration of a task is its estimated time or, without an estimate, the " +
+				"working days between its start and due dates.",
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "Get Critical Path",
+				ReadOnlyHint: true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"project_id": {
+						Type:        "integer",
+						Description: "The ID of the project.",
+					},
+				},
+				Required: []string{"project_id"},
+			},
+		},
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var projectID int64
+
+			var arguments map[string]any
+			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("failed to decode request: %s", err.Error())), nil
+			}
+			err := helpers.ParamGroup(arguments,
+				helpers.RequiredNumericParam(&projectID, "project_id"),
+			)
+			if err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
+			}
+
+			tasks, truncated, err := helpers.FetchAllPages(ctx, helpers.AutoPagination{
+				PageSize: 100,
+				MaxItems: criticalPathMaxTasks,
+			}, func(ctx context.Context, page, pageSize int64) ([]criticalPathTask, bool, error) {
+				taskListRequest := projects.NewTaskListRequest()
+				taskListRequest.Path.ProjectID = projectID
+				taskListRequest.Filters.Page = page
+				taskListRequest.Filters.PageSize = pageSize
+
+				response, err := projects.TaskList(ctx, engine, taskListRequest)
+				if err != nil {
+					return nil, false, err
+				}
+				tasks := make([]criticalPathTask, 0, len(response.Tasks))
+				for _, task := range response.Tasks {
+					if task.CompletedAt != nil || task.Status == "completed" {
+						continue
+					}
+					criticalTask := criticalPathTask{
+						ID:              task.ID,
+						Name:            task.Name,
+						DurationMinutes: taskDurationMinutes(task),
+					}
+					for _, predecessor := range task.Predecessors {
+						criticalTask.predecessors = append(criticalTask.predecessors, predecessor.ID)
+					}
+					tasks = append(tasks, criticalTask)
+				}
+				return tasks, response.Meta.Page.HasMore, nil
+			})
+			if err != nil {
+				return helpers.HandleAPIError(err, "failed to list project tasks")
+			}
+
+			tasks, path, duration, err := computeCriticalPath(tasks)
+			if err != nil {
+				return helpers.NewToolResultTextError(err.Error()), nil
+			}
+			encoded, err := json.Marshal(criticalPath{
+				ProjectID:       projectID,
+				DurationMinutes: duration,
+				CriticalPath:    path,
+				Tasks:           tasks,
+				Truncated:       truncated,
+			})
+			if err != nil {
+				return nil, err
+			}
+			return helpers.NewToolResultText("%s", encoded), nil
+		},
+	}
+}
@@ -0,0 +1,68 @@
+package twprojects_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/testutil"
+	"github.com/teamwork/mcp/internal/twprojects"
+)
+
+func TestCriticalPathGet(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		expected string
+		isError  bool
+	}{{
+		name: "parallel branches",
+		response: `{"tasks":[` +
+			`{"id":1,"name":"Design","estimateMinutes":60},` +
+			`{"id":2,"name":"Backend","estimateMinutes":120,"predecessors":[{"id":1,"type":"tasks"}]},` +
+			`{"id":3,"name":"Copy","estimateMinutes":30,"predecessors":[{"id":1,"type":"tasks"}]},` +
+			`{"id":4,"name":"Launch","estimateMinutes":60,"predecessors":[{"id":2,"type":"tasks"},` +
+			`{"id":3,"type":"tasks"},{"id":5,"type":"tasks"}]},` +
+			`{"id":5,"name":"Kickoff","estimateMinutes":600,"status":"completed"}` +
+			`],"meta":{"page":{"hasMore":false}}}`,
+		expected: `{"projectId":123,"durationMinutes":240,"criticalPath":[1,2,4],"tasks":[` +
+			`{"id":1,"name":"Design","durationMinutes":60,"earliestStart":0,"earliestFinish":60,` +
+			`"latestStart":0,"latestFinish":60,"slackMinutes":0,"critical":true},` +
+			`{"id":2,"name":"Backend","durationMinutes":120,"earliestStart":60,"earliestFinish":180,` +
+			`"latestStart":60,"latestFinish":180,"slackMinutes":0,"critical":true},` +
+			`{"id":3,"name":"Copy","durationMinutes":30,"earliestStart":60,"earliestFinish":90,` +
+			`"latestStart":150,"latestFinish":180,"slackMinutes":90,"critical":false},` +
+			`{"id":4,"name":"Launch","durationMinutes":60,"earliestStart":180,"earliestFinish":240,` +
+			`"latestStart":180,"latestFinish":240,"slackMinutes":0,"critical":true}]}`,
+	}, {
+		name: "cycle",
+		response: `{"tasks":[` +
+			`{"id":1,"name":"A","estimateMinutes":60,"predecessors":[{"id":2,"type":"tasks"}]},` +
+			`{"id":2,"name":"B","estimateMinutes":60,"predecessors":[{"id":1,"type":"tasks"}]}` +
+			`],"meta":{"page":{"hasMore":false}}}`,
+		isError: true,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mcpServer := mcpServerMock(t, http.StatusOK, []byte(tt.response))
+			testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodCriticalPathGet.String(), map[string]any{
+				"project_id": float64(123),
+			}, testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+				toolResult, ok := result.(*mcp.CallToolResult)
+				if !ok {
+					t.Fatalf("unexpected result type: %T", result)
+				}
+				if toolResult.IsError != tt.isError {
+					t.Fatalf("expected error %v, got %v", tt.isError, toolResult.Content)
+				}
+				if tt.isError {
+					return
+				}
+				if text := toolResult.Content[0].(*mcp.TextContent).Text; text != tt.expected {
+					t.Errorf("expected %s, got %s", tt.expected, text)
+				}
+			}))
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package twprojects
+
+import (
+	"time"
+
+	"github.com/teamwork/mcp/internal/helpers"
+	"github.com/teamwork/mcp/internal/toolsets"
+)
+
+// normalizeDatesMiddleware rewrites the timestamps of the tool results in the
+// RFC3339 format in UTC, adding the timestamps in the display timezone when
+// set.
+func normalizeDatesMiddleware(display *time.Location) toolsets.ToolMiddleware {
+	return rewriteTextMiddleware(func(data []byte) ([]byte, bool) {
+		return helpers.NormalizeDates(data, display)
+	})
+}
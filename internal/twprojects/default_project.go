@@ -0,0 +1,219 @@
+package twprojects
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/helpers"
+	"github.com/teamwork/mcp/internal/toolsets"
+)
+
+// List of methods available in the Teamwork.com MCP service.
+//
+// The naming convention for methods follows a pattern described here:
+// https://github.com/github/github-mcp-server/issues/333
+const (
+	MethodDefaultProjectSet toolsets.Method = "twprojects-set_default_project"
+)
+
+const (
+	// defaultProjectMetaKey is the initialize "_meta" field where clients can
+	// set the default project of the session.
+	defaultProjectMetaKey = "com.teamwork/defaultProjectId"
+	// defaultProjectRootsTimeout is the maximum time waiting for the client to
+	// list its roots.
+	defaultProjectRootsTimeout = 2 * time.Second
+)
+
+// reRootProjectID extracts the project ID from a root URI (e.g.
+// https://example.teamwork.com/app/projects/123/tasks).
+var reRootProjectID = regexp.MustCompile(`/projects/(\d+)(?:/|$)`)
+
+func init() {
+	// register the toolset methods
+	toolsets.RegisterMethod(MethodDefaultProjectSet)
+}
+
+// defaultProject resolves the project used by the tools when the project_id
+// argument is omitted. In order of precedence, it is the project set with the
+// set_default_project tool, the project of the client roots, the project sent
+// in the initialize metadata and the project configured by the operator.
+type defaultProject struct {
+	configured int64
+	sessions   *sessionStore[int64]
+}
+
+func newDefaultProject(configured int64) *defaultProject {
+	return &defaultProject{
+		configured: configured,
+		sessions:   newSessionStore[int64](),
+	}
+}
+
+// resolve returns the default project of the session, if any.
+func (d *defaultProject) resolve(ctx context.Context, request *mcp.CallToolRequest) (int64, bool) {
+	if key, ok := sessionKey(ctx, request); ok {
+		var projectID int64
+		d.sessions.update(key, func(value *int64) {
+			projectID = *value
+		})
+		if projectID > 0 {
+			return projectID, true
+		}
+	}
+	if request.Session != nil {
+		if projectID, ok := rootsProject(ctx, request.Session); ok {
+			return projectID, true
+		}
+		if params := request.Session.InitializeParams(); params != nil {
+			if projectID, ok := metaProject(params.Meta); ok {
+				return projectID, true
+			}
+		}
+	}
+	return d.configured, d.configured > 0
+}
+
+// rootsProject returns the first project referenced by the client roots.
+func rootsProject(ctx context.Context, session *mcp.ServerSession) (int64, bool) {
+	if params := session.InitializeParams(); params == nil || params.Capabilities == nil {
+		return 0, false
+	}
+	ctx, cancel := context.WithTimeout(ctx, defaultProjectRootsTimeout)
+	defer cancel()
+
+	// clients without roots support answer with an error
+	roots, err := session.ListRoots(ctx, &mcp.ListRootsParams{})
+	if err != nil || roots == nil {
+		return 0, false
+	}
+	for _, root := range roots.Roots {
+		if root == nil {
+			continue
+		}
+		matches := reRootProjectID.FindStringSubmatch(root.URI)
+		if len(matches) < 2 {
+			continue
+		}
+		if projectID, err := strconv.ParseInt(matches[1], 10, 64); err == nil && projectID > 0 {
+			return projectID, true
+		}
+	}
+	return 0, false
+}
+
+// metaProject returns the project sent in the initialize metadata.
+func metaProject(meta mcp.Meta) (int64, bool) {
+	switch value := meta[defaultProjectMetaKey].(type) {
+	case float64:
+		return int64(value), value > 0
+	case string:
+		projectID, err := strconv.ParseInt(value, 10, 64)
+		return projectID, err == nil && projectID > 0
+	}
+	return 0, false
+}
+
+// apply wraps the tools that require a project_id argument, so it can be
+// omitted when the session has a default project.
+func (d *defaultProject) apply(tools []toolsets.ToolWrapper) []toolsets.ToolWrapper {
+	return toolsets.WrapToolList(tools, func(tool toolsets.ToolWrapper) toolsets.ToolWrapper {
+		schema, ok := tool.Tool.InputSchema.(*jsonschema.Schema)
+		if !ok || schema.Properties["project_id"] == nil || !slices.Contains(schema.Required, "project_id") ||
+			tool.Tool.Name == string(MethodDefaultProjectSet) {
+			return tool
+		}
+		tool.Tool, _ = withInputSchema(tool.Tool, func(schema *jsonschema.Schema) {
+			describeProperty(schema, "project_id", " Defaults to the project set with "+
+				string(MethodDefaultProjectSet)+", when omitted.")
+			makeOptional(schema, "project_id")
+		})
+		tool.Handler = d.middleware()(tool.Handler)
+		return tool
+	})
+}
+
+// middleware fills the omitted project_id argument with the default project of
+// the session.
+func (d *defaultProject) middleware() toolsets.ToolMiddleware {
+	return func(handler mcp.ToolHandler) mcp.ToolHandler {
+		return func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var arguments map[string]any
+			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+				return handler(ctx, request)
+			}
+			if arguments["project_id"] != nil {
+				return handler(ctx, request)
+			}
+			projectID, ok := d.resolve(ctx, request)
+			if !ok {
+				return handler(ctx, request)
+			}
+			if arguments == nil {
+				arguments = make(map[string]any)
+			}
+			arguments["project_id"] = float64(projectID)
+			return callWithArguments(ctx, handler, request, arguments)
+		}
+	}
+}
+
+// DefaultProjectSet sets the default project of the session.
+func DefaultProjectSet(defaults *defaultProject) toolsets.ToolWrapper {
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name: string(MethodDefaultProjectSet),
+			Description: "Set the default project of this session. The tools that require a project_id use it when " +
+				"the argument is omitted. Use it when the conversation is focused on a single project. Only the " +
+				"session is affected, nothing is changed in Teamwork.com.",
+			Annotations: &mcp.ToolAnnotations{
+				Title:          "Set Default Project",
+				ReadOnlyHint:   true,
+				IdempotentHint: true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"project_id": {
+						Type:        "integer",
+						Description: "The ID of the default project. Use 0 to clear the default project.",
+					},
+				},
+				Required: []string{"project_id"},
+			},
+		},
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var projectID int64
+
+			var arguments map[string]any
+			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("failed to decode request: %s", err.Error())), nil
+			}
+			err := helpers.ParamGroup(arguments,
+				helpers.RequiredNumericParam(&projectID, "project_id"),
+			)
+			if err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
+			}
+
+			key, ok := sessionKey(ctx, request)
+			if !ok {
+				return helpers.NewToolResultTextError("the default project is not available without a session"), nil
+			}
+			defaults.sessions.update(key, func(value *int64) {
+				*value = max(projectID, 0)
+			})
+			if projectID <= 0 {
+				return helpers.NewToolResultText("Default project cleared"), nil
+			}
+			return helpers.NewToolResultText("Default project set to %d", projectID), nil
+		},
+	}
+}
@@ -0,0 +1,100 @@
+package twprojects_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/config"
+	"github.com/teamwork/mcp/internal/testutil"
+	"github.com/teamwork/mcp/internal/twprojects"
+)
+
+func TestDefaultProjectSet(t *testing.T) {
+	runSessions(t, func(t *testing.T, withSession testutil.ExecuteToolRequestOption) {
+		var path string
+		mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(r *http.Request) (int, []byte) {
+			path = r.URL.Path
+			return http.StatusOK, []byte(`{}`)
+		})
+		testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodDefaultProjectSet.String(), map[string]any{
+			"project_id": float64(123),
+		}, withSession)
+
+		// the project ID is taken from the session
+		testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTasklistListByProject.String(), map[string]any{},
+			withSession)
+		if !strings.Contains(path, "/projects/123/") {
+			t.Errorf("expected the default project in the request, got %s", path)
+		}
+	})
+}
+
+func TestDefaultProjectFallback(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []twprojects.ToolsetGroupOption
+		isError bool
+	}{{
+		name: "configured default project",
+		opts: []twprojects.ToolsetGroupOption{twprojects.WithDefaultProject(123)},
+	}, {
+		name:    "without default project",
+		isError: true,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mcpServer := mcpServerMock(t, http.StatusOK, []byte(`{}`), tt.opts...)
+			testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTasklistListByProject.String(), map[string]any{},
+				testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+					toolResult, ok := result.(*mcp.CallToolResult)
+					if !ok {
+						t.Fatalf("unexpected result type: %T", result)
+					}
+					if toolResult.IsError != tt.isError {
+						t.Errorf("unexpected error state %v: %v", toolResult.IsError, toolResult.Content)
+					}
+				}),
+			)
+		})
+	}
+}
+
+func TestDefaultProjectStatelessUsers(t *testing.T) {
+	var path string
+	mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(r *http.Request) (int, []byte) {
+		path = r.URL.Path
+		return http.StatusOK, []byte(`{}`)
+	})
+	// the clients of the stateless transport can send the same session ID
+	ctx := config.WithStatelessTransport(config.WithCustomerURL(t.Context(), "https://example.teamwork.com"))
+	checkError := func(isError bool) testutil.ExecuteToolRequestOption {
+		return testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+			toolResult, ok := result.(*mcp.CallToolResult)
+			if !ok {
+				t.Fatalf("unexpected result type: %T", result)
+			}
+			if toolResult.IsError != isError {
+				t.Errorf("unexpected error state %v: %v", toolResult.IsError, toolResult.Content)
+			}
+		})
+	}
+
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodDefaultProjectSet.String(), map[string]any{
+		"project_id": float64(123),
+	}, testutil.ExecuteToolRequestWithContext(config.WithUserID(ctx, 1)), checkError(false))
+
+	// another user doesn't get the default project of the session
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTasklistListByProject.String(), map[string]any{},
+		testutil.ExecuteToolRequestWithContext(config.WithUserID(ctx, 2)), checkError(true))
+	if strings.Contains(path, "/projects/123/") {
+		t.Errorf("expected the default project of another user to be ignored, got %s", path)
+	}
+
+	// the sessions can't be identified without a user
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodDefaultProjectSet.String(), map[string]any{
+		"project_id": float64(123),
+	}, testutil.ExecuteToolRequestWithContext(ctx), checkError(true))
+}
@@ -0,0 +1,260 @@
+package twprojects
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/helpers"
+	"github.com/teamwork/mcp/internal/toolsets"
+	twapi "github.com/teamwork/twapi-go-sdk"
+	"github.com/teamwork/twapi-go-sdk/projects"
+)
+
+// List of methods available in the Teamwork.com MCP service.
+//
+// The naming convention for methods follows a pattern described here:
+// https://github.com/github/github-mcp-server/issues/333
+const (
+	MethodDueDateSuggest toolsets.Method = "twprojects-suggest_due_date"
+)
+
+const (
+	// dueDateHorizonDays is the number of days searched for a due date.
+	dueDateHorizonDays = 90
+	// dueDateDefaultDailyMinutes are the working minutes of the weekdays when
+	// the user has no working hours.
+	dueDateDefaultDailyMinutes = 8 * 60
+)
+
+// Reasons of the days skipped when suggesting a due date.
+const (
+	dueDateSkipUnavailable = "unavailable"
+	dueDateSkipFullyBooked = "fully booked"
+)
+
+func init() {
+	// register the toolset methods
+	toolsets.RegisterMethod(MethodDueDateSuggest)
+}
+
+// dueDateWorkloadResponse contains the workload of the assignee with their
+// working hours. The SDK response can't be used, as it fails to decode the
+// dates used as keys.
+type dueDateWorkloadResponse struct {
+	Workload struct {
+		Users []struct {
+			ID    int64                                `json:"userId"`
+			Dates map[string]projects.WorkloadUserDate `json:"dates"`
+		} `json:"users"`
+	} `json:"workload"`
+	Included struct {
+		WorkingHourEntries map[string]struct {
+			Weekday   string  `json:"weekday"`
+			TaskHours float64 `json:"taskHours"`
+		} `json:"workingHourEntries"`
+	} `json:"included"`
+}
+
+// HandleHTTPResponse handles the HTTP response for the dueDateWorkloadResponse.
+// If some unexpected HTTP status code is returned by the API, a twapi.HTTPError
+// is returned.
+func (d *dueDateWorkloadResponse) HandleHTTPResponse(resp *http.Response) error {
+	if resp.StatusCode != http.StatusOK {
+		return twapi.NewHTTPError(resp, "failed to retrieve workload")
+	}
+	if err := json.NewDecoder(resp.Body).Decode(d); err != nil {
+		return fmt.Errorf("failed to decode retrieve workload response: %w", err)
+	}
+	return nil
+}
+
+// dueDateSkippedDay is a working day without time available for the task.
+type dueDateSkippedDay struct {
+	Date   string `json:"date"`
+	Reason string `json:"reason"`
+}
+
+// dueDateSuggestion is the due date proposed for a task.
+type dueDateSuggestion struct {
+	UserID           int64               `json:"userId"`
+	EstimatedMinutes int64               `json:"estimatedMinutes"`
+	StartDate        string              `json:"startDate"`
+	SuggestedDueDate string              `json:"suggestedDueDate"`
+	WorkingDays      int                 `json:"workingDays"`
+	SkippedDays      []dueDateSkippedDay `json:"skippedDays"`
+}
+
+// workingMinutesByWeekday returns the task minutes of each weekday from the
+// working hours of the workload, falling back to 8 hours from Monday to Friday.
+func workingMinutesByWeekday(workload *dueDateWorkloadResponse) map[time.Weekday]int64 {
+	minutes := make(map[time.Weekday]int64)
+	for _, entry := range workload.Included.WorkingHourEntries {
+		for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+			if strings.EqualFold(entry.Weekday, weekday.String()) {
+				minutes[weekday] = int64(entry.TaskHours * 60)
+			}
+		}
+	}
+	if len(minutes) == 0 {
+		for weekday := time.Monday; weekday <= time.Friday; weekday++ {
+			minutes[weekday] = dueDateDefaultDailyMinutes
+		}
+	}
+	return minutes
+}
+
+// suggestDueDate walks the days from the start date, consuming the estimated
+// minutes with the time available each working day: the working minutes of the
+// weekday minus the minutes already allocated. Unavailable days (e.g. holidays
+// or time off) are skipped. It returns false when the effort doesn't fit in the
+// workload period.
+func suggestDueDate(
+	userID, estimatedMinutes int64,
+	startDate time.Time,
+	workload *dueDateWorkloadResponse,
+) (dueDateSuggestion, bool) {
+	suggestion := dueDateSuggestion{
+		UserID:           userID,
+		EstimatedMinutes: estimatedMinutes,
+		StartDate:        startDate.Format(time.DateOnly),
+		SkippedDays:      []dueDateSkippedDay{},
+	}
+
+	var dates map[string]projects.WorkloadUserDate
+	for _, user := range workload.Workload.Users {
+		if user.ID == userID {
+			dates = user.Dates
+		}
+	}
+	workingMinutes := workingMinutesByWeekday(workload)
+
+	remaining := estimatedMinutes
+	for day := range dueDateHorizonDays {
+		date := startDate.AddDate(0, 0, day)
+		key := date.Format(time.DateOnly)
+		available := workingMinutes[date.Weekday()]
+		if available <= 0 {
+			continue
+		}
+		entry := dates[key]
+		if entry.UnavailableDay {
+			suggestion.SkippedDays = append(suggestion.SkippedDays, dueDateSkippedDay{
+				Date:   key,
+				Reason: dueDateSkipUnavailable,
+			})
+			continue
+		}
+		available -= entry.CapacityMinutes
+		if available <= 0 {
+			suggestion.SkippedDays = append(suggestion.SkippedDays, dueDateSkippedDay{
+				Date:   key,
+				Reason: dueDateSkipFullyBooked,
+			})
+			continue
+		}
+
+		suggestion.WorkingDays++
+		remaining -= available
+		if remaining <= 0 {
+			suggestion.SuggestedDueDate = key
+			return suggestion, true
+		}
+	}
+	return suggestion, false
+}
+
+// DueDateSuggest proposes a due date for a task in Teamwork.com from its
+// estimated effort and the availability of the assignee.
+func DueDateSuggest(engine *twapi.Engine) toolsets.ToolWrapper {
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name: string(MethodDueDateSuggest),
+			Description: "Suggest a due date for a task in Teamwork.com from its estimated effort and the " +
+				"availability of the assignee. The working hours of the user, the time already allocated in the " +
+				"workload, weekends, holidays and time off are respected. The days skipped because the user is " +
+				"unavailable or fully booked are listed. " + workloadDescription,
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "Suggest Due Date",
+				ReadOnlyHint: true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"assignee_user_id": {
+						Type:        "integer",
+						Description: "The ID of the user who will work on the task.",
+					},
+					"estimated_minutes": {
+						Type:        "integer",
+						Description: "The estimated effort of the task, in minutes.",
+						Minimum:     twapi.Ptr(float64(1)),
+					},
+					"start_date": {
+						Type:   "string",
+						Format: "date",
+						Description: "The date the work can start. Defaults to today. The date must be in the format " +
+							"YYYY-MM-DD.",
+					},
+				},
+				Required: []string{"assignee_user_id", "estimated_minutes"},
+			},
+		},
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var userID, estimatedMinutes int64
+			var startDate twapi.Date
+
+			var arguments map[string]any
+			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("failed to decode request: %s", err.Error())), nil
+			}
+			err := helpers.ParamGroup(arguments,
+				helpers.RequiredNumericParam(&userID, "assignee_user_id"),
+				helpers.RequiredNumericParam(&estimatedMinutes, "estimated_minutes"),
+				helpers.OptionalDateParam(&startDate, "start_date"),
+			)
+			if err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
+			}
+			if estimatedMinutes <= 0 {
+				return helpers.NewToolResultTextError("invalid parameters: estimated_minutes must be positive"), nil
+			}
+
+			start := time.Time(startDate)
+			if start.IsZero() {
+				now := time.Now().UTC()
+				start = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+			}
+			end := start.AddDate(0, 0, dueDateHorizonDays-1)
+
+			workloadRequest := projects.NewWorkloadRequest(twapi.Date(start), twapi.Date(end))
+			workloadRequest.Filters.UserIDs = []int64{userID}
+			workloadRequest.Filters.Include = []projects.WorkloadGetRequestSideload{
+				projects.WorkloadGetRequestSideloadWorkingHours,
+				projects.WorkloadGetRequestSideloadWorkingHourEntries,
+			}
+			workload, err := twapi.Execute[projects.WorkloadRequest, *dueDateWorkloadResponse](ctx, engine,
+				workloadRequest,
+			)
+			if err != nil {
+				return helpers.HandleAPIError(err, "failed to get workload")
+			}
+
+			suggestion, ok := suggestDueDate(userID, estimatedMinutes, start, workload)
+			if !ok {
+				return helpers.NewToolResultTextError(fmt.Sprintf("the user doesn't have %d minutes available in the "+
+					"next %d days", estimatedMinutes, dueDateHorizonDays)), nil
+			}
+			encoded, err := json.Marshal(suggestion)
+			if err != nil {
+				return nil, err
+			}
+			return helpers.NewToolResultText("%s", encoded), nil
+		},
+	}
+}
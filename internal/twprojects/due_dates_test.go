@@ -0,0 +1,43 @@
+package twprojects_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/testutil"
+	"github.com/teamwork/mcp/internal/twprojects"
+)
+
+func TestDueDateSuggest(t *testing.T) {
+	mcpServer := mcpServerMock(t, http.StatusOK, []byte(`{"workload":{"users":[{"userId":1,"dates":{`+
+		`"2024-01-05":{"capacityMinutes":300},`+
+		`"2024-01-08":{"unavailableDay":true},`+
+		`"2024-01-09":{"capacityMinutes":360}}}]},`+
+		`"included":{"workingHourEntries":{`+
+		`"1":{"weekday":"monday","taskHours":6},"2":{"weekday":"tuesday","taskHours":6},`+
+		`"3":{"weekday":"wednesday","taskHours":6},"4":{"weekday":"thursday","taskHours":6},`+
+		`"5":{"weekday":"friday","taskHours":6},"6":{"weekday":"saturday","taskHours":0},`+
+		`"7":{"weekday":"sunday","taskHours":0}}}}`))
+
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodDueDateSuggest.String(), map[string]any{
+		"assignee_user_id":  float64(1),
+		"estimated_minutes": float64(400),
+		"start_date":        "2024-01-05",
+	}, testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+		toolResult, ok := result.(*mcp.CallToolResult)
+		if !ok {
+			t.Fatalf("unexpected result type: %T", result)
+		}
+		if toolResult.IsError {
+			t.Fatalf("tool failed to execute: %v", toolResult.Content)
+		}
+		text := toolResult.Content[0].(*mcp.TextContent).Text
+		expected := `{"userId":1,"estimatedMinutes":400,"startDate":"2024-01-05","suggestedDueDate":"2024-01-10",` +
+			`"workingDays":2,"skippedDays":[{"date":"2024-01-08","reason":"unavailable"},` +
+			`{"date":"2024-01-09","reason":"fully booked"}]}`
+		if text != expected {
+			t.Errorf("expected %s, got %s", expected, text)
+		}
+	}))
+}
@@ -0,0 +1,219 @@
+package twprojects
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/helpers"
+	"github.com/teamwork/mcp/internal/toolsets"
+	twapi "github.com/teamwork/twapi-go-sdk"
+)
+
+// List of methods available in the Teamwork.com MCP service.
+//
+// The naming convention for methods follows a pattern described here:
+// https://github.com/github/github-mcp-server/issues/333
+const (
+	MethodEntitiesGet toolsets.Method = "twprojects-get_entities"
+)
+
+const (
+	// entitiesGetMaxItems is the maximum number of entities retrieved in a
+	// single call.
+	entitiesGetMaxItems = 50
+	// entitiesGetConcurrency is the number of entities retrieved in parallel.
+	entitiesGetConcurrency = 5
+)
+
+func init() {
+	// register the toolset methods
+	toolsets.RegisterMethod(MethodEntitiesGet)
+}
+
+// entityReference identifies an entity to be retrieved.
+type entityReference struct {
+	Type string `json:"type"`
+	ID   int64  `json:"id"`
+}
+
+// hydratedEntity is an entity retrieved by the batch getter. Data contains the
+// same response as the get tool of the entity type.
+type hydratedEntity struct {
+	Type  string          `json:"type"`
+	ID    int64           `json:"id"`
+	Data  json.RawMessage `json:"data,omitempty"`
+	Error string          `json:"error,omitempty"`
+}
+
+// EntitiesGet retrieves multiple entities of different types in Teamwork.com.
+func EntitiesGet(engine *twapi.Engine) toolsets.ToolWrapper {
+	getTools := map[string]toolsets.ToolWrapper{
+		"project":   ProjectGet(engine),
+		"tasklist":  TasklistGet(engine),
+		"task":      TaskGet(engine),
+		"milestone": MilestoneGet(engine),
+		"company":   CompanyGet(engine),
+		"user":      UserGet(engine),
+		"tag":       TagGet(engine),
+		"team":      TeamGet(engine),
+		"comment":   CommentGet(engine),
+		"timelog":   TimelogGet(engine),
+		"timer":     TimerGet(engine),
+		"notebook":  NotebookGet(engine),
+	}
+	types := make([]string, 0, len(getTools))
+	for entityType := range getTools {
+		types = append(types, entityType)
+	}
+	slices.Sort(types)
+
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name: string(MethodEntitiesGet),
+			Description: "Get multiple entities of different types in Teamwork.com in a single call. Use it to hydrate " +
+				"the relationship references (type and ID pairs) returned by other tools, instead of calling the get " +
+				"tool of each entity. Plural types, as returned in the relationships (e.g. 'users'), are also accepted.",
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "Get Entities",
+				ReadOnlyHint: true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"entities": {
+						Type:        "array",
+						Description: "The entities to get.",
+						MinItems:    twapi.Ptr(1),
+						MaxItems:    twapi.Ptr(entitiesGetMaxItems),
+						Items: &jsonschema.Schema{
+							Type: "object",
+							Properties: map[string]*jsonschema.Schema{
+								"type": {
+									Type: "string",
+									Description: "The entity type. Possible values are: " +
+										strings.Join(types, ", ") + ".",
+								},
+								"id": {
+									Type:        "integer",
+									Description: "The ID of the entity.",
+								},
+							},
+							Required: []string{"type", "id"},
+						},
+					},
+				},
+				Required: []string{"entities"},
+			},
+		},
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var params struct {
+				Entities []entityReference `json:"entities"`
+			}
+			if err := json.Unmarshal(request.Params.Arguments, &params); err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("failed to decode request: %s", err.Error())), nil
+			}
+			if len(params.Entities) == 0 {
+				return helpers.NewToolResultTextError("invalid parameters: parameter entities is required"), nil
+			}
+			if len(params.Entities) > entitiesGetMaxItems {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: at most %d entities are allowed",
+					entitiesGetMaxItems)), nil
+			}
+
+			entities := make([]hydratedEntity, len(params.Entities))
+			metas := make([]mcp.Meta, len(params.Entities))
+
+			var wg sync.WaitGroup
+			semaphore := make(chan struct{}, entitiesGetConcurrency)
+			for i, reference := range params.Entities {
+				entities[i] = hydratedEntity{Type: normalizeEntityType(reference.Type), ID: reference.ID}
+
+				getTool, ok := getTools[entities[i].Type]
+				if !ok {
+					entities[i].Error = fmt.Sprintf("unsupported entity type %q", reference.Type)
+					continue
+				}
+
+				wg.Go(func() {
+					semaphore <- struct{}{}
+					defer func() { <-semaphore }()
+
+					data, meta, err := getEntity(ctx, getTool, reference.ID)
+					entities[i].Data, metas[i] = data, meta
+					if err != nil {
+						entities[i].Error = err.Error()
+					}
+				})
+			}
+			wg.Wait()
+
+			var linkedEntities []helpers.WebLinkedEntity
+			for _, meta := range metas {
+				if items, ok := meta[helpers.WebLinkedEntitiesMetaKey].([]helpers.WebLinkedEntity); ok {
+					linkedEntities = append(linkedEntities, items...)
+				}
+			}
+
+			result, err := helpers.NewToolResultJSON(map[string]any{"entities": entities})
+			if err != nil {
+				return nil, err
+			}
+			if len(linkedEntities) > 0 {
+				result.Meta = mcp.Meta{helpers.WebLinkedEntitiesMetaKey: linkedEntities}
+			}
+			return result, nil
+		},
+	}
+}
+
+// getEntity calls the get tool of the entity, returning the JSON content and
+// the metadata of the result.
+func getEntity(ctx context.Context, getTool toolsets.ToolWrapper, id int64) (json.RawMessage, mcp.Meta, error) {
+	arguments, err := json.Marshal(map[string]any{"id": id})
+	if err != nil {
+		return nil, nil, err
+	}
+	result, err := getTool.Handler(ctx, &mcp.CallToolRequest{
+		Params: &mcp.CallToolParamsRaw{
+			Name:      getTool.Tool.Name,
+			Arguments: arguments,
+		},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, content := range result.Content {
+		text, ok := content.(*mcp.TextContent)
+		if !ok {
+			continue
+		}
+		if result.IsError {
+			return nil, nil, errors.New(text.Text)
+		}
+		if json.Valid([]byte(text.Text)) {
+			return json.RawMessage(text.Text), result.Meta, nil
+		}
+	}
+	return nil, nil, errors.New("unexpected response")
+}
+
+// normalizeEntityType converts the entity type to the singular form, as
+// relationships use the plural form (e.g. "users" or "companies").
+func normalizeEntityType(entityType string) string {
+	entityType = strings.ToLower(strings.TrimSpace(entityType))
+	switch {
+	case entityType == "people" || entityType == "person":
+		return "user"
+	case strings.HasSuffix(entityType, "ies"):
+		return strings.TrimSuffix(entityType, "ies") + "y"
+	default:
+		return strings.TrimSuffix(entityType, "s")
+	}
+}
@@ -0,0 +1,54 @@
+package twprojects_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/testutil"
+	"github.com/teamwork/mcp/internal/twprojects"
+)
+
+func TestEntitiesGet(t *testing.T) {
+	mcpServer := mcpServerMock(t, http.StatusOK, []byte(`{"task":{"id":123},"project":{"id":456}}`))
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodEntitiesGet.String(), map[string]any{
+		"entities": []map[string]any{
+			{"type": "tasks", "id": float64(123)},
+			{"type": "project", "id": float64(456)},
+			{"type": "unknown", "id": float64(789)},
+		},
+	}, testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+		toolResult, ok := result.(*mcp.CallToolResult)
+		if !ok {
+			t.Fatalf("unexpected result type: %T", result)
+		}
+		if toolResult.IsError {
+			t.Fatalf("tool failed to execute: %v", toolResult.Content)
+		}
+
+		var response struct {
+			Entities []struct {
+				Type  string          `json:"type"`
+				ID    int64           `json:"id"`
+				Data  json.RawMessage `json:"data"`
+				Error string          `json:"error"`
+			} `json:"entities"`
+		}
+		if err := json.Unmarshal([]byte(toolResult.Content[0].(*mcp.TextContent).Text), &response); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if len(response.Entities) != 3 {
+			t.Fatalf("expected 3 entities, got %d", len(response.Entities))
+		}
+		if response.Entities[0].Type != "task" || len(response.Entities[0].Data) == 0 {
+			t.Errorf("unexpected task entity: %+v", response.Entities[0])
+		}
+		if response.Entities[1].Type != "project" || len(response.Entities[1].Data) == 0 {
+			t.Errorf("unexpected project entity: %+v", response.Entities[1])
+		}
+		if response.Entities[2].Error == "" {
+			t.Errorf("expected error for unsupported entity type")
+		}
+	}))
+}
@@ -0,0 +1,184 @@
+package twprojects
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/toolsets"
+)
+
+// includeDiffParam is the name of the optional parameter of the update tools
+// that adds the changed fields to the result.
+const includeDiffParam = "include_diff"
+
+// entityDiffIgnoredFields are the fields that change on every update, so they
+// are not reported in the diff.
+var entityDiffIgnoredFields = []string{"updatedAt", "updatedBy", "lastChangedOn", "meta"}
+
+// entityFieldChange is a field changed by an update tool.
+type entityFieldChange struct {
+	Field  string `json:"field"`
+	Before any    `json:"before"`
+	After  any    `json:"after"`
+}
+
+// entityDiff wraps the update tools that have a matching get tool (e.g.
+// update_task and get_task), so they can optionally return the fields changed
+// by the update. The entity is fetched before and after the update.
+func entityDiff(writeTools, readTools []toolsets.ToolWrapper) []toolsets.ToolWrapper {
+	getTools := make(map[string]toolsets.ToolWrapper)
+	for _, tool := range readTools {
+		if entityType, ok := strings.CutPrefix(tool.Tool.Name, "twprojects-get_"); ok {
+			getTools[entityType] = tool
+		}
+	}
+
+	return toolsets.WrapToolList(writeTools, func(tool toolsets.ToolWrapper) toolsets.ToolWrapper {
+		entityType, ok := strings.CutPrefix(tool.Tool.Name, "twprojects-update_")
+		schema, isSchema := tool.Tool.InputSchema.(*jsonschema.Schema)
+		getTool, hasGetTool := getTools[entityType]
+		if !ok || !isSchema || !hasGetTool || schema.Properties["id"] == nil {
+			return tool
+		}
+		tool.Tool, _ = withInputSchema(tool.Tool, func(schema *jsonschema.Schema) {
+			schema.Properties[includeDiffParam] = &jsonschema.Schema{
+				Type: "boolean",
+				Description: fmt.Sprintf("When true, the %s is retrieved before and after the update and the "+
+					"changed fields are returned, so the changes can be reviewed.", entityType),
+			}
+		})
+		tool.Handler = entityDiffMiddleware(getTool)(tool.Handler)
+		return tool
+	})
+}
+
+// entityDiffMiddleware adds the fields changed by the update to the result,
+// when asked with the include_diff argument.
+func entityDiffMiddleware(getTool toolsets.ToolWrapper) toolsets.ToolMiddleware {
+	return func(update mcp.ToolHandler) mcp.ToolHandler {
+		return func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var arguments map[string]any
+			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+				return update(ctx, request)
+			}
+			if includeDiff, _ := arguments[includeDiffParam].(bool); !includeDiff {
+				return update(ctx, request)
+			}
+
+			get := getTool.Handler
+			getRequest, err := entityGetRequest(request, getTool.Tool.Name, arguments["id"])
+			if err != nil {
+				return nil, err
+			}
+			before, beforeErr := fetchEntityFields(ctx, get, getRequest)
+
+			result, err := update(ctx, request)
+			if err != nil || result == nil || result.IsError {
+				return result, err
+			}
+			if beforeErr != nil {
+				result.Content = append(result.Content, &mcp.TextContent{
+					Text: fmt.Sprintf("The changes are not available: %s", beforeErr),
+				})
+				return result, nil
+			}
+			after, err := fetchEntityFields(ctx, get, getRequest)
+			if err != nil {
+				result.Content = append(result.Content, &mcp.TextContent{
+					Text: fmt.Sprintf("The changes are not available: %s", err),
+				})
+				return result, nil
+			}
+
+			encoded, err := json.Marshal(map[string]any{"changes": diffEntityFields(before, after)})
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode changes: %w", err)
+			}
+			result.Content = append(result.Content, &mcp.TextContent{Text: string(encoded)})
+			return result, nil
+		}
+	}
+}
+
+// entityGetRequest builds the request of the get tool for the entity ID.
+func entityGetRequest(request *mcp.CallToolRequest, name string, id any) (*mcp.CallToolRequest, error) {
+	encoded, err := json.Marshal(map[string]any{"id": id})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode arguments: %w", err)
+	}
+	return &mcp.CallToolRequest{
+		Session: request.Session,
+		Params:  &mcp.CallToolParamsRaw{Name: name, Arguments: encoded},
+		Extra:   request.Extra,
+	}, nil
+}
+
+// fetchEntityFields calls the get tool, returning the fields of the entity,
+// which is the single root field besides the metadata.
+func fetchEntityFields(
+	ctx context.Context,
+	get mcp.ToolHandler,
+	request *mcp.CallToolRequest,
+) (map[string]any, error) {
+	result, err := get(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	for _, content := range result.Content {
+		text, ok := content.(*mcp.TextContent)
+		if !ok {
+			continue
+		}
+		if result.IsError {
+			return nil, errors.New(text.Text)
+		}
+		var decoded map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(text.Text), &decoded); err != nil {
+			continue
+		}
+		for field, value := range decoded {
+			if field == "meta" || field == "included" {
+				continue
+			}
+			var fields map[string]any
+			if err := json.Unmarshal(value, &fields); err == nil && fields != nil {
+				return fields, nil
+			}
+		}
+	}
+	return nil, errors.New("unexpected response")
+}
+
+// diffEntityFields returns the fields with different values, sorted by name.
+func diffEntityFields(before, after map[string]any) []entityFieldChange {
+	fields := slices.Collect(maps.Keys(before))
+	for field := range after {
+		if _, ok := before[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+	slices.Sort(fields)
+
+	changes := []entityFieldChange{}
+	for _, field := range fields {
+		if slices.Contains(entityDiffIgnoredFields, field) {
+			continue
+		}
+		if !reflect.DeepEqual(before[field], after[field]) {
+			changes = append(changes, entityFieldChange{
+				Field:  field,
+				Before: before[field],
+				After:  after[field],
+			})
+		}
+	}
+	return changes
+}
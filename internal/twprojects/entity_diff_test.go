@@ -0,0 +1,37 @@
+package twprojects_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/testutil"
+	"github.com/teamwork/mcp/internal/twprojects"
+)
+
+func TestEntityDiff(t *testing.T) {
+	mcpServer := mcpServerMock(t, http.StatusOK, []byte(`{"task":{"id":123,"name":"Example"}}`))
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTaskUpdate.String(), map[string]any{
+		"id":           float64(123),
+		"name":         "Example",
+		"include_diff": true,
+	}, testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+		toolResult, ok := result.(*mcp.CallToolResult)
+		if !ok {
+			t.Fatalf("unexpected result type: %T", result)
+		}
+		if toolResult.IsError {
+			t.Fatalf("unexpected error: %v", toolResult.Content)
+		}
+		var found bool
+		for _, content := range toolResult.Content {
+			if text, ok := content.(*mcp.TextContent); ok && strings.Contains(text.Text, `"changes":[]`) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("expected the changes in the result, got %v", toolResult.Content)
+		}
+	}))
+}
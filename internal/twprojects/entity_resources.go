@@ -0,0 +1,181 @@
+package twprojects
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/config"
+	"github.com/teamwork/mcp/internal/helpers"
+	"github.com/teamwork/mcp/internal/toolsets"
+	twapi "github.com/teamwork/twapi-go-sdk"
+	"github.com/teamwork/twapi-go-sdk/projects"
+)
+
+// projectURIPrefix and taskURIPrefix precede the ID in the URI of the project
+// and task resources.
+const (
+	projectURIPrefix = "twprojects://projects/"
+	taskURIPrefix    = "twprojects://tasks/"
+)
+
+// entityResourceChange is the argument of a write tool with the ID of the
+// resource it changes.
+type entityResourceChange struct {
+	uriPrefix string
+	argument  string
+}
+
+// entityResourceChanges are the write tools changing the project and task
+// resources, so the subscribed clients are notified.
+var entityResourceChanges = map[toolsets.Method]entityResourceChange{
+	MethodProjectUpdate:           {uriPrefix: projectURIPrefix, argument: "id"},
+	MethodProjectDelete:           {uriPrefix: projectURIPrefix, argument: "id"},
+	MethodTaskUpdate:              {uriPrefix: taskURIPrefix, argument: "id"},
+	MethodTaskDelete:              {uriPrefix: taskURIPrefix, argument: "id"},
+	MethodTaskChecklistItemAdd:    {uriPrefix: taskURIPrefix, argument: "task_id"},
+	MethodTaskChecklistItemToggle: {uriPrefix: taskURIPrefix, argument: "task_id"},
+}
+
+// entityResourceID returns the ID of the entity of a project or task resource
+// URI with the given prefix.
+func entityResourceID(uri, prefix string) (int64, bool) {
+	id, ok := strings.CutPrefix(uri, prefix)
+	if !ok {
+		return 0, false
+	}
+	entityID, err := strconv.ParseInt(id, 10, 64)
+	return entityID, err == nil && entityID > 0
+}
+
+// entityResources exposes the projects and tasks as MCP resources, so the
+// clients can read them and subscribe to their changes. The resources are
+// restricted to the projects of the sandbox, and rewritten like the results of
+// the read tools (e.g. compacted, or without the financial data).
+type entityResources struct {
+	engine        *twapi.Engine
+	sandbox       *projectSandbox
+	rewrites      []func(data []byte) ([]byte, bool)
+	subscriptions *toolsets.ResourceSubscriptions
+}
+
+func newEntityResources(
+	engine *twapi.Engine,
+	sandbox *projectSandbox,
+	rewrites ...func(data []byte) ([]byte, bool),
+) *entityResources {
+	return &entityResources{
+		engine:   engine,
+		sandbox:  sandbox,
+		rewrites: rewrites,
+		subscriptions: toolsets.NewResourceSubscriptions(func(uri string) bool {
+			_, isProject := entityResourceID(uri, projectURIPrefix)
+			_, isTask := entityResourceID(uri, taskURIPrefix)
+			return isProject || isTask
+		}),
+	}
+}
+
+// middleware notifies the clients subscribed to the project and task resources
+// changed by the write tools. Only the clients of the same installation are
+// notified.
+func (r *entityResources) middleware() toolsets.ToolMiddleware {
+	return func(next mcp.ToolHandler) mcp.ToolHandler {
+		return func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := next(ctx, request)
+			change, ok := entityResourceChanges[toolsets.Method(request.Params.Name)]
+			if err != nil || !ok || result == nil || result.IsError {
+				return result, err
+			}
+
+			var arguments map[string]any
+			if json.Unmarshal(request.Params.Arguments, &arguments) != nil {
+				return result, nil
+			}
+			var id int64
+			if helpers.ParamGroup(arguments, helpers.RequiredNumericParam(&id, change.argument)) == nil {
+				r.subscriptions.Notify(ctx, config.ScopedResourceURI(ctx, change.uriPrefix+strconv.FormatInt(id, 10)))
+			}
+			return result, nil
+		}
+	}
+}
+
+// ProjectTemplate returns the resource template of the projects.
+func (r *entityResources) ProjectTemplate() toolsets.ServerResourceTemplate {
+	return toolsets.NewServerResourceTemplate(&mcp.ResourceTemplate{
+		Name:        "project",
+		Title:       "Project",
+		Description: "A project in Teamwork.com, in JSON format. Subscribe to it to be notified when it changes.",
+		URITemplate: projectURIPrefix + "{project_id}",
+		MIMEType:    "application/json",
+	}, func(ctx context.Context, request *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		uri := request.Params.URI
+		projectID, ok := entityResourceID(uri, projectURIPrefix)
+		if !ok {
+			return nil, mcp.ResourceNotFoundError(uri)
+		}
+		if err := r.sandbox.checkResource(ctx, uri, sandboxReference{entity: "project", id: projectID}); err != nil {
+			return nil, err
+		}
+		project, err := projects.ProjectGet(ctx, r.engine, projects.NewProjectGetRequest(projectID))
+		if err != nil {
+			if isNotFoundError(err) {
+				return nil, mcp.ResourceNotFoundError(uri)
+			}
+			return nil, fmt.Errorf("failed to get project: %w", err)
+		}
+		return r.result(uri, project)
+	})
+}
+
+// TaskTemplate returns the resource template of the tasks.
+func (r *entityResources) TaskTemplate() toolsets.ServerResourceTemplate {
+	return toolsets.NewServerResourceTemplate(&mcp.ResourceTemplate{
+		Name:        "task",
+		Title:       "Task",
+		Description: "A task in Teamwork.com, in JSON format. Subscribe to it to be notified when it changes.",
+		URITemplate: taskURIPrefix + "{task_id}",
+		MIMEType:    "application/json",
+	}, func(ctx context.Context, request *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		uri := request.Params.URI
+		taskID, ok := entityResourceID(uri, taskURIPrefix)
+		if !ok {
+			return nil, mcp.ResourceNotFoundError(uri)
+		}
+		if err := r.sandbox.checkResource(ctx, uri, sandboxReference{entity: "task", id: taskID}); err != nil {
+			return nil, err
+		}
+		task, err := projects.TaskGet(ctx, r.engine, projects.NewTaskGetRequest(taskID))
+		if err != nil {
+			if isNotFoundError(err) {
+				return nil, mcp.ResourceNotFoundError(uri)
+			}
+			return nil, fmt.Errorf("failed to get task: %w", err)
+		}
+		return r.result(uri, task)
+	})
+}
+
+// result encodes the entity as the contents of the resource.
+func (r *entityResources) result(uri string, entity any) (*mcp.ReadResourceResult, error) {
+	encoded, err := json.Marshal(entity)
+	if err != nil {
+		return nil, err
+	}
+	for _, rewrite := range r.rewrites {
+		if rewritten, ok := rewrite(encoded); ok {
+			encoded = rewritten
+		}
+	}
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{{
+			URI:      uri,
+			MIMEType: "application/json",
+			Text:     string(encoded),
+		}},
+	}, nil
+}
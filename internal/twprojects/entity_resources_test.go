@@ -0,0 +1,155 @@
+package twprojects_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/testutil"
+	"github.com/teamwork/mcp/internal/toolsets"
+	"github.com/teamwork/mcp/internal/twprojects"
+)
+
+func TestEntityResources(t *testing.T) {
+	tests := []struct {
+		name       string
+		uri        string
+		opts       []twprojects.ToolsetGroupOption
+		expected   string
+		unexpected string
+		notFound   bool
+	}{{
+		name:       "project",
+		uri:        "twprojects://projects/3",
+		expected:   `"name":"Website"`,
+		unexpected: `"description":null`,
+	}, {
+		name:     "project without compaction",
+		uri:      "twprojects://projects/3",
+		opts:     []twprojects.ToolsetGroupOption{twprojects.WithCompaction(false)},
+		expected: `"description":null`,
+	}, {
+		name:     "task",
+		uri:      "twprojects://tasks/5",
+		expected: `"name":"Homepage"`,
+	}, {
+		name:     "task outside the sandbox",
+		uri:      "twprojects://tasks/5",
+		opts:     []twprojects.ToolsetGroupOption{twprojects.WithAllowedProjects([]int64{4})},
+		notFound: true,
+	}, {
+		name:     "invalid ID",
+		uri:      "twprojects://tasks/abc",
+		notFound: true,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(r *http.Request) (int, []byte) {
+				switch r.URL.Path {
+				case "/projects/api/v3/projects/3.json":
+					return http.StatusOK, []byte(`{"project":{"id":3,"name":"Website"}}`)
+				case "/projects/api/v3/tasks/5.json":
+					return http.StatusOK, []byte(`{"task":{"id":5,"name":"Homepage","tasklist":{"id":7}}}`)
+				case "/projects/api/v3/tasklists/7.json":
+					return http.StatusOK, []byte(`{"tasklist":{"id":7,"project":{"id":3}}}`)
+				}
+				t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				return http.StatusNotFound, []byte(`{}`)
+			}, tt.opts...)
+
+			clientTransport, serverTransport := mcp.NewInMemoryTransports()
+			if _, err := mcpServer.Connect(t.Context(), serverTransport, nil); err != nil {
+				t.Fatalf("failed to connect to server: %v", err)
+			}
+			client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+			clientSession, err := client.Connect(t.Context(), clientTransport, nil)
+			if err != nil {
+				t.Fatalf("failed to connect to client: %v", err)
+			}
+			defer clientSession.Close() //nolint:errcheck
+
+			resource, err := clientSession.ReadResource(t.Context(), &mcp.ReadResourceParams{URI: tt.uri})
+			if tt.notFound {
+				if err == nil {
+					t.Fatalf("expected the resource not to be found, got %v", resource.Contents[0].Text)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to read resource: %v", err)
+			}
+			if text := resource.Contents[0].Text; !strings.Contains(text, tt.expected) {
+				t.Errorf("expected the resource to contain %s, got %s", tt.expected, text)
+			}
+			if text := resource.Contents[0].Text; tt.unexpected != "" && strings.Contains(text, tt.unexpected) {
+				t.Errorf("expected the resource not to contain %s, got %s", tt.unexpected, text)
+			}
+		})
+	}
+}
+
+func TestEntityResourceSubscriptions(t *testing.T) {
+	engine := testutil.ProjectsEngineMockFunc(func(r *http.Request) (int, []byte) {
+		return http.StatusOK, []byte(`{"task":{"id":5,"name":"Homepage"}}`)
+	})
+	group := twprojects.DefaultToolsetGroup(false, true, engine)
+	if err := group.EnableToolsets(toolsets.MethodAll); err != nil {
+		t.Fatalf("failed to enable toolsets: %v", err)
+	}
+	mcpServer := mcp.NewServer(&mcp.Implementation{Name: "test-server", Version: "1.0.0"}, &mcp.ServerOptions{
+		SubscribeHandler: func(_ context.Context, req *mcp.SubscribeRequest) error {
+			if !group.CanSubscribe(req.Params.URI) {
+				return mcp.ResourceNotFoundError(req.Params.URI)
+			}
+			return nil
+		},
+		UnsubscribeHandler: func(context.Context, *mcp.UnsubscribeRequest) error {
+			return nil
+		},
+	})
+	group.RegisterAll(mcpServer)
+
+	updated := make(chan string, 1)
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	if _, err := mcpServer.Connect(t.Context(), serverTransport, nil); err != nil {
+		t.Fatalf("failed to connect to server: %v", err)
+	}
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, &mcp.ClientOptions{
+		ResourceUpdatedHandler: func(_ context.Context, req *mcp.ResourceUpdatedNotificationRequest) {
+			updated <- req.Params.URI
+		},
+	})
+	clientSession, err := client.Connect(t.Context(), clientTransport, nil)
+	if err != nil {
+		t.Fatalf("failed to connect to client: %v", err)
+	}
+	defer clientSession.Close() //nolint:errcheck
+
+	if err := clientSession.Subscribe(t.Context(), &mcp.SubscribeParams{URI: "twprojects://exports/1"}); err == nil {
+		t.Errorf("expected the subscription to a resource without updates to fail")
+	}
+	if err := clientSession.Subscribe(t.Context(), &mcp.SubscribeParams{URI: "twprojects://tasks/5"}); err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+
+	result, err := clientSession.CallTool(t.Context(), &mcp.CallToolParams{
+		Name:      twprojects.MethodTaskUpdate.String(),
+		Arguments: map[string]any{"id": 5, "name": "New homepage"},
+	})
+	if err != nil || result.IsError {
+		t.Fatalf("failed to update task: %v %v", err, result)
+	}
+
+	select {
+	case uri := <-updated:
+		if uri != "twprojects://tasks/5" {
+			t.Errorf("expected an update of twprojects://tasks/5, got %s", uri)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the subscribed client to be notified")
+	}
+}
@@ -0,0 +1,101 @@
+package twprojects
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/toolsets"
+)
+
+// includeEntityParam is the name of the optional parameter of the create tools
+// that controls if the created entity is returned.
+const includeEntityParam = "include_entity"
+
+// entitySnapshot wraps the create tools, so their result includes the created
+// entity as returned by the matching get tool (e.g. get_task), with its web
+// link. This saves the follow-up call to retrieve the entity. When the entity
+// can't be retrieved, the result is returned unchanged.
+func entitySnapshot(writeTools, readTools []toolsets.ToolWrapper) []toolsets.ToolWrapper {
+	getTools := make(map[string]toolsets.ToolWrapper)
+	for _, tool := range readTools {
+		if entityType, ok := strings.CutPrefix(tool.Tool.Name, "twprojects-get_"); ok {
+			getTools[entityType] = tool
+		}
+	}
+
+	return toolsets.WrapToolList(writeTools, func(tool toolsets.ToolWrapper) toolsets.ToolWrapper {
+		if !isCreateTool(tool.Tool.Name) {
+			return tool
+		}
+		toolCopy, ok := withInputSchema(tool.Tool, func(schema *jsonschema.Schema) {
+			schema.Properties[includeEntityParam] = &jsonschema.Schema{
+				Type: "boolean",
+				Description: "When true, the created entity is returned with its web link, so it doesn't need to be " +
+					"retrieved. Defaults to true.",
+				Default: json.RawMessage("true"),
+			}
+		})
+		if !ok {
+			return tool
+		}
+		tool.Tool = toolCopy
+		tool.Handler = entitySnapshotMiddleware(getTools)(tool.Handler)
+		return tool
+	})
+}
+
+// isCreateTool reports if the tool creates entities, including the tools that
+// create them from templates.
+func isCreateTool(name string) bool {
+	return strings.HasPrefix(name, "twprojects-create_") || toolsets.Method(name) == MethodTasklistTemplateApply
+}
+
+// entitySnapshotMiddleware adds the entities created by the tool call to the
+// result, unless disabled with the include_entity argument.
+func entitySnapshotMiddleware(getTools map[string]toolsets.ToolWrapper) toolsets.ToolMiddleware {
+	return func(create mcp.ToolHandler) mcp.ToolHandler {
+		return func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := create(ctx, request)
+			if err != nil || result == nil || result.IsError {
+				return result, err
+			}
+
+			var arguments map[string]any
+			if err := json.Unmarshal(request.Params.Arguments, &arguments); err == nil {
+				if includeEntity, ok := arguments[includeEntityParam].(bool); ok && !includeEntity {
+					return result, nil
+				}
+			}
+
+			var snapshots []mcp.Content
+			for _, content := range result.Content {
+				text, ok := content.(*mcp.TextContent)
+				if !ok {
+					continue
+				}
+				entity, ok := createdEntity(text.Text)
+				if !ok {
+					continue
+				}
+				getTool, ok := getTools[entity.Type]
+				if !ok {
+					continue
+				}
+				getRequest, err := entityGetRequest(request, getTool.Tool.Name, entity.ID)
+				if err != nil {
+					continue
+				}
+				getResult, err := getTool.Handler(ctx, getRequest)
+				if err != nil || getResult == nil || getResult.IsError {
+					continue
+				}
+				snapshots = append(snapshots, getResult.Content...)
+			}
+			result.Content = append(result.Content, snapshots...)
+			return result, nil
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package twprojects_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/testutil"
+	"github.com/teamwork/mcp/internal/twprojects"
+)
+
+func TestEntitySnapshot(t *testing.T) {
+	tests := []struct {
+		name          string
+		includeEntity any
+		wantSnapshot  bool
+	}{{
+		name:         "default",
+		wantSnapshot: true,
+	}, {
+		name:          "disabled",
+		includeEntity: false,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(req *http.Request) (int, []byte) {
+				if req.Method == http.MethodPost {
+					return http.StatusCreated, []byte(`{"task":{"id":123}}`)
+				}
+				return http.StatusOK, []byte(`{"task":{"id":123,"name":"Snapshot"}}`)
+			})
+			arguments := map[string]any{
+				"name":        "Example",
+				"tasklist_id": float64(123),
+			}
+			if tt.includeEntity != nil {
+				arguments["include_entity"] = tt.includeEntity
+			}
+			testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTaskCreate.String(), arguments,
+				testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+					toolResult, ok := result.(*mcp.CallToolResult)
+					if !ok {
+						t.Fatalf("unexpected result type: %T", result)
+					}
+					if toolResult.IsError {
+						t.Fatalf("unexpected error: %v", toolResult.Content)
+					}
+					var found bool
+					for _, content := range toolResult.Content {
+						if text, ok := content.(*mcp.TextContent); ok && strings.HasPrefix(text.Text, `{"task"`) {
+							found = true
+						}
+					}
+					if found != tt.wantSnapshot {
+						t.Errorf("expected snapshot %t, got %v", tt.wantSnapshot, toolResult.Content)
+					}
+				}),
+			)
+		})
+	}
+}
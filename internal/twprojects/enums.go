@@ -0,0 +1,190 @@
+package twprojects
+
+import (
+	"context"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/helpers"
+	"github.com/teamwork/mcp/internal/toolsets"
+	"github.com/teamwork/twapi-go-sdk/projects"
+)
+
+// List of methods available in the Teamwork.com MCP service.
+//
+// The naming convention for methods follows a pattern described here:
+// https://github.com/github/github-mcp-server/issues/333
+const (
+	MethodEnumsGet toolsets.Method = "twprojects-get_enums"
+)
+
+func init() {
+	// register the toolset methods
+	toolsets.RegisterMethod(MethodEnumsGet)
+}
+
+// Valid values of the enumerated parameters. They are the single source for the
+// tool schemas, the parameter validation and the get_enums tool.
+var (
+	taskPriorities = []string{"low", "medium", "high"}
+	taskStatuses   = []string{"new", "reopened", "completed", "deleted"}
+	// taskPredecessorTypes are the dependency types between tasks.
+	taskPredecessorTypes = []projects.TaskPredecessorType{
+		projects.TaskPredecessorTypeStart,
+		projects.TaskPredecessorTypeFinish,
+	}
+	// taskLinkTypes are the types of the links between tasks of any project,
+	// from the point of view of the task owning the link.
+	taskLinkTypes = []string{"blocks", "blocked_by", "relates_to"}
+	// projectStatuses are the statuses accepted when listing projects. "all"
+	// includes the archived projects, which are hidden by default.
+	projectStatuses     = []string{"active", "archived", "completed", "all"}
+	taskReactionTypes   = []string{"like", "dislike", "joy", "frown", "heart"}
+	userTypes           = []string{"account", "collaborator", "contact"}
+	notebookTypes       = []projects.NotebookType{projects.NotebookTypeMarkdown, projects.NotebookTypeHTML}
+	commentObjectTypes  = []string{"tasks", "milestones", "files", "notebooks"}
+	commentContentTypes = []string{"TEXT", "HTML"}
+	tagItemTypes        = []string{
+		"project", "task", "tasklist", "milestone", "message", "timelog", "notebook", "file", "company", "link",
+	}
+	activityLogItemTypes = []string{
+		"message", "comment", "task", "tasklist", "taskgroup", "milestone", "file", "form", "notebook", "timelog",
+		"task_comment", "notebook_comment", "file_comment", "link_comment", "milestone_comment", "project", "link",
+		"billingInvoice", "risk", "projectUpdate", "reacted", "budget",
+	}
+	// projectPermissions are the permissions of a user in a project.
+	projectPermissions = []string{
+		"view-messages-and-files", "view-tasks-and-milestones", "view-time", "view-notebooks", "view-risk-register",
+		"view-invoices", "view-links", "add-tasks", "add-milestones", "add-taskLists", "add-messages", "add-files",
+		"add-time", "add-notebooks", "add-links", "set-privacy", "can-be-assigned-to-tasks-and-milestones",
+		"project-administrator", "add-people-to-project",
+	}
+	// trashItemTypes are the types of the deleted items that can be restored
+	// from the trash.
+	trashItemTypes = []string{"tasks", "projects"}
+	// printoutEntityTypes are the types of the entities that can be exported as
+	// a printout.
+	printoutEntityTypes = []string{"task", "milestone"}
+	// printoutFormats are the file formats of the printouts.
+	printoutFormats = []string{"pdf", "html"}
+)
+
+// enumDefinition describes the valid values of an enumerated parameter.
+type enumDefinition struct {
+	Description string   `json:"description"`
+	Values      []string `json:"values"`
+}
+
+// enumDefinitions returns the enumerated parameters indexed by name.
+func enumDefinitions() map[string]enumDefinition {
+	return map[string]enumDefinition{
+		"task_priority": {
+			Description: "Priority of a task.",
+			Values:      taskPriorities,
+		},
+		"task_status": {
+			Description: "Status of a task.",
+			Values:      taskStatuses,
+		},
+		"task_dependency_type": {
+			Description: "Type of a task dependency (predecessor). 'start' means the task can complete when the " +
+				"predecessor starts, 'complete' means the task can complete when the predecessor completes.",
+			Values: enumStrings(taskPredecessorTypes),
+		},
+		"task_link_type": {
+			Description: "Type of a link between tasks, possibly in different projects. 'blocks' means the task " +
+				"blocks the linked task, 'blocked_by' means the task is blocked by the linked task.",
+			Values: taskLinkTypes,
+		},
+		"project_status": {
+			Description: "Status of a project, used to filter the project list.",
+			Values:      projectStatuses,
+		},
+		"task_reaction_type": {
+			Description: "Type of a reaction to a task.",
+			Values:      taskReactionTypes,
+		},
+		"user_type": {
+			Description: "Type of a user.",
+			Values:      userTypes,
+		},
+		"notebook_type": {
+			Description: "Content type of a notebook.",
+			Values:      enumStrings(notebookTypes),
+		},
+		"comment_object_type": {
+			Description: "Type of the object a comment belongs to.",
+			Values:      commentObjectTypes,
+		},
+		"comment_content_type": {
+			Description: "Content type of a comment.",
+			Values:      commentContentTypes,
+		},
+		"tag_item_type": {
+			Description: "Type of the item a tag is applied to.",
+			Values:      tagItemTypes,
+		},
+		"activity_log_item_type": {
+			Description: "Type of the item an activity refers to.",
+			Values:      activityLogItemTypes,
+		},
+		"project_permission": {
+			Description: "Permission of a user in a project, used by the role templates.",
+			Values:      projectPermissions,
+		},
+		"trash_item_type": {
+			Description: "Type of a deleted item in the trash.",
+			Values:      trashItemTypes,
+		},
+		"printout_entity_type": {
+			Description: "Type of an entity exported as a printout.",
+			Values:      printoutEntityTypes,
+		},
+		"printout_format": {
+			Description: "File format of a printout.",
+			Values:      printoutFormats,
+		},
+	}
+}
+
+// EnumsGet returns the valid values of the enumerated parameters in
+// Teamwork.com.
+func EnumsGet() toolsets.ToolWrapper {
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name: string(MethodEnumsGet),
+			Description: "Get the valid values of the enumerated parameters in Teamwork.com, such as task priorities, " +
+				"task statuses, user types and task dependency types. Use it to check the accepted values instead of " +
+				"guessing them.",
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "Get Enums",
+				ReadOnlyHint: true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:       "object",
+				Properties: map[string]*jsonschema.Schema{},
+			},
+		},
+		Handler: func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return helpers.NewToolResultJSON(map[string]any{"enums": enumDefinitions()})
+		},
+	}
+}
+
+// enumStrings converts the typed enum values to strings.
+func enumStrings[T ~string](values []T) []string {
+	converted := make([]string, len(values))
+	for i, value := range values {
+		converted[i] = string(value)
+	}
+	return converted
+}
+
+// enumSchemaValues converts the enum values to the format of the JSON schema.
+func enumSchemaValues[T ~string](values []T) []any {
+	converted := make([]any, len(values))
+	for i, value := range values {
+		converted[i] = string(value)
+	}
+	return converted
+}
@@ -0,0 +1,42 @@
+package twprojects_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/testutil"
+	"github.com/teamwork/mcp/internal/twprojects"
+)
+
+func TestEnumsGet(t *testing.T) {
+	mcpServer := mcpServerMock(t, http.StatusOK, nil)
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodEnumsGet.String(), map[string]any{},
+		testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+			toolResult, ok := result.(*mcp.CallToolResult)
+			if !ok {
+				t.Fatalf("unexpected result type: %T", result)
+			}
+			if toolResult.IsError {
+				t.Fatalf("tool failed to execute: %v", toolResult.Content)
+			}
+
+			var response struct {
+				Enums map[string]struct {
+					Values []string `json:"values"`
+				} `json:"enums"`
+			}
+			if err := json.Unmarshal([]byte(toolResult.Content[0].(*mcp.TextContent).Text), &response); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if values := response.Enums["task_priority"].Values; !slices.Equal(values, []string{"low", "medium", "high"}) {
+				t.Errorf("unexpected task priorities: %v", values)
+			}
+			if values := response.Enums["task_dependency_type"].Values; !slices.Equal(values, []string{"start", "complete"}) {
+				t.Errorf("unexpected task dependency types: %v", values)
+			}
+		}),
+	)
+}
@@ -0,0 +1,280 @@
+package twprojects
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/helpers"
+	"github.com/teamwork/mcp/internal/textextract"
+	"github.com/teamwork/mcp/internal/toolsets"
+	twapi "github.com/teamwork/twapi-go-sdk"
+	"github.com/teamwork/twapi-go-sdk/projects"
+)
+
+// List of methods available in the Teamwork.com MCP service.
+//
+// The naming convention for methods follows a pattern described here:
+// https://github.com/github/github-mcp-server/issues/333
+const (
+	MethodFileTextExtract toolsets.Method = "twprojects-extract_file_text"
+)
+
+const (
+	// fileTextMaxFileSize is the maximum size, in bytes, of the files whose text
+	// is extracted.
+	fileTextMaxFileSize = 10 << 20
+	// fileTextDefaultChars is the default number of characters returned.
+	fileTextDefaultChars = 20000
+	// fileTextMaxChars is the maximum number of characters returned.
+	fileTextMaxChars = 200000
+)
+
+// errExternalDownload is returned when the file is downloaded from another
+// host, which must not receive the credentials of the installation.
+var errExternalDownload = errors.New("file stored in an external host")
+
+func init() {
+	// register the toolset methods
+	toolsets.RegisterMethod(MethodFileTextExtract)
+}
+
+// fileGetRequest represents the request to get a file.
+type fileGetRequest struct {
+	ID int64
+}
+
+// HTTPRequest creates an HTTP request for the fileGetRequest.
+func (f fileGetRequest) HTTPRequest(ctx context.Context, server string) (*http.Request, error) {
+	uri := server + "/files/" + strconv.FormatInt(f.ID, 10) + ".json"
+	return http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+}
+
+// fileGetResponse contains a file, with the URL to download its latest
+// version.
+type fileGetResponse struct {
+	File struct {
+		Name         string                `json:"name"`
+		OriginalName string                `json:"originalName"`
+		Size         projects.LegacyNumber `json:"size"`
+		DownloadURL  string                `json:"download-URL"`
+	} `json:"file"`
+}
+
+// HandleHTTPResponse handles the HTTP response for the fileGetResponse. If some
+// unexpected HTTP status code is returned by the API, a twapi.HTTPError is
+// returned.
+func (f *fileGetResponse) HandleHTTPResponse(resp *http.Response) error {
+	if resp.StatusCode != http.StatusOK {
+		return twapi.NewHTTPError(resp, "failed to get file")
+	}
+	if err := json.NewDecoder(resp.Body).Decode(f); err != nil {
+		return fmt.Errorf("failed to decode get file response: %w", err)
+	}
+	return nil
+}
+
+// fileDownloadRequest represents the request to download a file from the
+// installation.
+type fileDownloadRequest struct {
+	URL string
+}
+
+// HTTPRequest creates an HTTP request for the fileDownloadRequest. Files stored
+// in other hosts are rejected with errExternalDownload, so the request isn't
+// authenticated.
+func (f fileDownloadRequest) HTTPRequest(ctx context.Context, server string) (*http.Request, error) {
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, fmt.Errorf("invalid server URL: %w", err)
+	}
+	downloadURL, err := serverURL.Parse(f.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid download URL: %w", err)
+	}
+	if !strings.EqualFold(downloadURL.Host, serverURL.Host) {
+		return nil, errExternalDownload
+	}
+	return http.NewRequestWithContext(ctx, http.MethodGet, downloadURL.String(), nil)
+}
+
+// fileDownloadResponse contains the content of a downloaded file, limited to
+// fileTextMaxFileSize bytes.
+type fileDownloadResponse struct {
+	Content []byte
+}
+
+// HandleHTTPResponse handles the HTTP response for the fileDownloadResponse. If
+// some unexpected HTTP status code is returned by the API, a twapi.HTTPError is
+// returned.
+func (f *fileDownloadResponse) HandleHTTPResponse(resp *http.Response) error {
+	if resp.StatusCode != http.StatusOK {
+		return twapi.NewHTTPError(resp, "failed to download file")
+	}
+	content, err := readFileContent(resp.Body)
+	if err != nil {
+		return err
+	}
+	f.Content = content
+	return nil
+}
+
+// readFileContent reads the content of a file, failing when it's larger than
+// fileTextMaxFileSize bytes.
+func readFileContent(body io.Reader) ([]byte, error) {
+	content, err := io.ReadAll(io.LimitReader(body, fileTextMaxFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	if len(content) > fileTextMaxFileSize {
+		return nil, fmt.Errorf("the file is larger than %d bytes", fileTextMaxFileSize)
+	}
+	return content, nil
+}
+
+// downloadFile downloads a file. Files of the installation are downloaded with
+// its credentials, while files stored in other hosts (e.g. signed storage
+// URLs) are downloaded without them.
+func downloadFile(ctx context.Context, engine *twapi.Engine, downloadURL string) ([]byte, error) {
+	response, err := twapi.Execute[fileDownloadRequest, *fileDownloadResponse](ctx, engine,
+		fileDownloadRequest{URL: downloadURL},
+	)
+	if err == nil {
+		return response.Content, nil
+	}
+	if !errors.Is(err, errExternalDownload) {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, downloadURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid download URL: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download file: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download file: unexpected status %d", resp.StatusCode)
+	}
+	return readFileContent(resp.Body)
+}
+
+// FileTextExtract extracts the plain text of a file attached in Teamwork.com.
+func FileTextExtract(engine *twapi.Engine, extractors textextract.Registry) toolsets.ToolWrapper {
+	extensions := make([]string, 0, len(extractors))
+	for extension := range extractors {
+		extensions = append(extensions, extension)
+	}
+	slices.Sort(extensions)
+
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name: string(MethodFileTextExtract),
+			Description: fmt.Sprintf("Extract the plain text of a file attached in Teamwork.com, such as a project "+
+				"brief, so questions about it can be answered without uploading it again. Files up to %d MiB with "+
+				"the extensions %s are supported.", fileTextMaxFileSize>>20, strings.Join(extensions, ", ")),
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "Extract File Text",
+				ReadOnlyHint: true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"file_id": {
+						Type:        "integer",
+						Description: "The ID of the file.",
+					},
+					"max_chars": {
+						Type:        "integer",
+						Description: fmt.Sprintf("The maximum number of characters returned. Defaults to %d.", fileTextDefaultChars),
+						Minimum:     twapi.Ptr(float64(1)),
+						Maximum:     twapi.Ptr(float64(fileTextMaxChars)),
+					},
+				},
+				Required: []string{"file_id"},
+			},
+		},
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var fileID int64
+			maxChars := int64(fileTextDefaultChars)
+
+			var arguments map[string]any
+			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("failed to decode request: %s", err.Error())), nil
+			}
+			err := helpers.ParamGroup(arguments,
+				helpers.RequiredNumericParam(&fileID, "file_id"),
+				helpers.OptionalNumericParam(&maxChars, "max_chars"),
+			)
+			if err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
+			}
+			if maxChars < 1 || maxChars > fileTextMaxChars {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: max_chars must be between 1 "+
+					"and %d", fileTextMaxChars)), nil
+			}
+
+			file, err := twapi.Execute[fileGetRequest, *fileGetResponse](ctx, engine, fileGetRequest{ID: fileID})
+			if err != nil {
+				return helpers.HandleAPIError(err, "failed to get file")
+			}
+			name := file.File.OriginalName
+			if name == "" {
+				name = file.File.Name
+			}
+			if file.File.Size > fileTextMaxFileSize {
+				return helpers.NewToolResultTextError(fmt.Sprintf("the file has %d bytes, above the limit of %d bytes",
+					file.File.Size, fileTextMaxFileSize)), nil
+			}
+			if file.File.DownloadURL == "" {
+				return helpers.NewToolResultTextError("the file can't be downloaded"), nil
+			}
+
+			content, err := downloadFile(ctx, engine, file.File.DownloadURL)
+			if err != nil {
+				return helpers.HandleAPIError(err, "failed to download file")
+			}
+			text, err := extractors.Extract(ctx, name, content)
+			if err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("failed to extract the text of %q: %s", name,
+					err.Error())), nil
+			}
+
+			runes := []rune(strings.TrimSpace(text))
+			truncated := int64(len(runes)) > maxChars
+			if truncated {
+				runes = runes[:maxChars]
+			}
+			encoded, err := json.Marshal(struct {
+				FileID     int64  `json:"fileId"`
+				Name       string `json:"name"`
+				Size       int    `json:"size"`
+				Characters int    `json:"characters"`
+				Truncated  bool   `json:"truncated"`
+				Text       string `json:"text"`
+			}{
+				FileID:     fileID,
+				Name:       name,
+				Size:       len(content),
+				Characters: len(runes),
+				Truncated:  truncated,
+				Text:       string(runes),
+			})
+			if err != nil {
+				return nil, err
+			}
+			return helpers.NewToolResultText("%s", encoded), nil
+		},
+	}
+}
@@ -0,0 +1,42 @@
+package twprojects_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/testutil"
+	"github.com/teamwork/mcp/internal/twprojects"
+)
+
+func TestFileTextExtract(t *testing.T) {
+	mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(r *http.Request) (int, []byte) {
+		switch r.URL.Path {
+		case "/files/123.json":
+			return http.StatusOK, []byte(`{"file":{"name":"brief.txt","originalName":"Brief.TXT","size":"25",` +
+				`"download-URL":"/files/download/123"}}`)
+		case "/files/download/123":
+			return http.StatusOK, []byte("Launch the new website.\n")
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			return http.StatusNotFound, nil
+		}
+	})
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodFileTextExtract.String(), map[string]any{
+		"file_id":   float64(123),
+		"max_chars": float64(10),
+	}, testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+		toolResult, ok := result.(*mcp.CallToolResult)
+		if !ok {
+			t.Fatalf("unexpected result type: %T", result)
+		}
+		if toolResult.IsError {
+			t.Fatalf("tool failed to execute: %v", toolResult.Content)
+		}
+		text := toolResult.Content[0].(*mcp.TextContent).Text
+		expected := `{"fileId":123,"name":"Brief.TXT","size":24,"characters":10,"truncated":true,"text":"Launch the"}`
+		if text != expected {
+			t.Errorf("expected %s, got %s", expected, text)
+		}
+	}))
+}
@@ -0,0 +1,71 @@
+package twprojects
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/config"
+	"github.com/teamwork/mcp/internal/helpers"
+	"github.com/teamwork/mcp/internal/toolsets"
+)
+
+// financeMethods are the tools whose main purpose is exposing or changing
+// financial data (e.g. rates, costs, budgets and invoices).
+var financeMethods = []toolsets.Method{
+	MethodProjectTimeBudgetGet,
+}
+
+// withoutFinance removes the finance tools and redacts the financial fields
+// (e.g. "userRate" or the "billable" flag of timelogs) of the results of the
+// remaining tools, so the model doesn't see billing data even when it can
+// automate other work. The financial parameters of the remaining tools (e.g.
+// the billing of a project) are removed as well.
+func withoutFinance(tools []toolsets.ToolWrapper) []toolsets.ToolWrapper {
+	tools = slices.DeleteFunc(slices.Clone(tools), func(tool toolsets.ToolWrapper) bool {
+		return slices.Contains(financeMethods, toolsets.Method(tool.Tool.Name))
+	})
+	tools = toolsets.WrapToolList(tools, withoutFinanceParams)
+	return toolsets.UseToolMiddlewares(tools, config.ToolRedactionMiddleware(config.Redaction{Rates: true}))
+}
+
+// withoutFinanceParams removes the financial parameters from the schema of the
+// tool, rejecting the calls still sending them.
+func withoutFinanceParams(tool toolsets.ToolWrapper) toolsets.ToolWrapper {
+	schema, ok := tool.Tool.InputSchema.(*jsonschema.Schema)
+	if !ok || !slices.ContainsFunc(projectBillingParams, func(name string) bool {
+		return schema.Properties[name] != nil
+	}) {
+		return tool
+	}
+
+	tool.Tool, _ = withInputSchema(tool.Tool, func(schema *jsonschema.Schema) {
+		for _, name := range projectBillingParams {
+			delete(schema.Properties, name)
+		}
+	})
+	tool.Handler = withoutFinanceParamsMiddleware()(tool.Handler)
+	return tool
+}
+
+// withoutFinanceParamsMiddleware rejects the calls sending the financial
+// parameters.
+func withoutFinanceParamsMiddleware() toolsets.ToolMiddleware {
+	return func(handler mcp.ToolHandler) mcp.ToolHandler {
+		return func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var arguments map[string]any
+			if err := json.Unmarshal(request.Params.Arguments, &arguments); err == nil {
+				for _, name := range projectBillingParams {
+					if arguments[name] != nil {
+						return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s isn't available, "+
+							"as the financial data is disabled", name)), nil
+					}
+				}
+			}
+			return handler(ctx, request)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package twprojects_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/testutil"
+	"github.com/teamwork/mcp/internal/toolsets"
+	"github.com/teamwork/mcp/internal/twprojects"
+)
+
+func TestWithoutFinance(t *testing.T) {
+	engine := testutil.ProjectsEngineMock(http.StatusOK, []byte(`{}`))
+	enabled := twprojects.NewToolsetProvider(true, engine).Methods()
+	disabled := twprojects.NewToolsetProvider(true, engine, twprojects.WithFinance(false)).Methods()
+
+	var removed []toolsets.Method
+	for _, method := range enabled {
+		if !slices.Contains(disabled, method) {
+			removed = append(removed, method)
+		}
+	}
+	if expected := []toolsets.Method{twprojects.MethodProjectTimeBudgetGet}; !slices.Equal(removed, expected) {
+		t.Errorf("expected the finance tools %v to be removed, got %v", expected, removed)
+	}
+
+	provider := twprojects.NewToolsetProvider(false, engine, twprojects.WithFinance(false))
+	for _, tool := range provider.WriteTools() {
+		if tool.Tool.Name != twprojects.MethodProjectCreate.String() {
+			continue
+		}
+		schema, ok := tool.Tool.InputSchema.(*jsonschema.Schema)
+		if !ok || schema.Properties["budget"] != nil || schema.Properties["name"] == nil {
+			t.Errorf("expected the billing parameters to be removed from %s", tool.Tool.Name)
+		}
+	}
+}
+
+func TestWithoutFinanceFields(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   toolsets.Method
+		response string
+		entity   string
+		redacted []string
+		kept     string
+	}{{
+		name:     "user rates",
+		method:   twprojects.MethodUserGet,
+		response: `{"person":{"id":1,"firstName":"Jane","userRate":5000,"userCost":3000}}`,
+		entity:   "person",
+		redacted: []string{"userRate", "userCost"},
+		kept:     "firstName",
+	}, {
+		name:     "billable timelog",
+		method:   twprojects.MethodTimelogGet,
+		response: `{"timelog":{"id":1,"description":"Code review","minutes":60,"billable":true}}`,
+		entity:   "timelog",
+		redacted: []string{"billable"},
+		kept:     "description",
+	}, {
+		name:     "billable timer",
+		method:   twprojects.MethodTimerGet,
+		response: `{"timer":{"id":1,"description":"Code review","billable":true}}`,
+		entity:   "timer",
+		redacted: []string{"billable"},
+		kept:     "description",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mcpServer := mcpServerMock(t, http.StatusOK, []byte(tt.response), twprojects.WithFinance(false))
+			testutil.ExecuteToolRequest(t, mcpServer, tt.method.String(), map[string]any{
+				"id": float64(1),
+			}, testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+				toolResult, ok := result.(*mcp.CallToolResult)
+				if !ok {
+					t.Fatalf("unexpected result type: %T", result)
+				}
+				text, ok := toolResult.Content[0].(*mcp.TextContent)
+				if !ok {
+					t.Fatalf("unexpected content type: %T", toolResult.Content[0])
+				}
+				var decoded map[string]map[string]any
+				if err := json.Unmarshal([]byte(text.Text), &decoded); err != nil {
+					t.Fatalf("failed to decode result: %v", err)
+				}
+				entity := decoded[tt.entity]
+				for _, field := range tt.redacted {
+					if entity[field] != "[redacted]" {
+						t.Errorf("expected %s to be redacted, got %v", field, entity[field])
+					}
+				}
+				if value, ok := entity[tt.kept]; !ok || value == "[redacted]" {
+					t.Errorf("expected %s to be kept, got %v", tt.kept, value)
+				}
+			}))
+		})
+	}
+}
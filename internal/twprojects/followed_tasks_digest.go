@@ -0,0 +1,313 @@
+package twprojects
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/helpers"
+	"github.com/teamwork/mcp/internal/toolsets"
+	twapi "github.com/teamwork/twapi-go-sdk"
+	"github.com/teamwork/twapi-go-sdk/projects"
+)
+
+// List of methods available in the Teamwork.com MCP service.
+//
+// The naming convention for methods follows a pattern described here:
+// https://github.com/github/github-mcp-server/issues/333
+const (
+	MethodFollowedTasksDigest toolsets.Method = "twprojects-get_followed_tasks_digest"
+)
+
+const (
+	// followedTasksDefaultHours is the default period of the digest.
+	followedTasksDefaultHours = 24
+	// followedTasksMaxTasks is the maximum number of followed tasks loaded.
+	followedTasksMaxTasks = 500
+	// followedTasksMaxActivities is the maximum number of activities loaded.
+	followedTasksMaxActivities = 2000
+	// followedTasksMaxEvents is the maximum number of changes listed per task.
+	// All changes are counted in the summary.
+	followedTasksMaxEvents = 5
+)
+
+// reActivityTaskLink extracts the task ID from the link of the task comment
+// activities, whose item is the comment.
+var reActivityTaskLink = regexp.MustCompile(`tasks/(\d+)`)
+
+func init() {
+	// register the toolset methods
+	toolsets.RegisterMethod(MethodFollowedTasksDigest)
+}
+
+// followedTaskEvent is a change of a followed task.
+type followedTaskEvent struct {
+	At          time.Time `json:"at"`
+	Action      string    `json:"action"`
+	UserID      int64     `json:"userId,omitempty"`
+	Description string    `json:"description,omitempty"`
+}
+
+// followedTask is a followed task with a summary of its changes in the period.
+type followedTask struct {
+	TaskID    int64               `json:"taskId"`
+	Name      string              `json:"name"`
+	Status    string              `json:"status"`
+	UpdatedAt time.Time           `json:"updatedAt"`
+	Changes   int                 `json:"changes"`
+	Summary   string              `json:"summary"`
+	Events    []followedTaskEvent `json:"events,omitempty"`
+}
+
+// followedTasksDigest lists the followed tasks changed in the period, most
+// recently changed first.
+type followedTasksDigest struct {
+	UserID int64          `json:"userId"`
+	Since  time.Time      `json:"since"`
+	Tasks  []followedTask `json:"tasks"`
+	// Truncated is true when not all tasks or activities were loaded.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// FollowedTasksDigest summarizes the changes of the tasks a user follows in
+// Teamwork.com.
+func FollowedTasksDigest(engine *twapi.Engine) toolsets.ToolWrapper {
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name: string(MethodFollowedTasksDigest),
+			Description: "Get a digest of the tasks a user follows in Teamwork.com that changed in the last hours, with a " +
+				"compact summary of the changes of each task (e.g. \"completed, 2 edits, 1 comment\") and the most " +
+				"recent ones. The changes made by the user are ignored by default. Use it for personal digests instead " +
+				"of the email notifications.",
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "Get Followed Tasks Digest",
+				ReadOnlyHint: true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"user_id": {
+						Type:        "integer",
+						Description: "The ID of the user following the tasks. Defaults to the authenticated user.",
+					},
+					"hours": {
+						Type: "integer",
+						Description: fmt.Sprintf("The number of past hours covered by the digest. Defaults to %d.",
+							followedTasksDefaultHours),
+					},
+					"include_own_changes": {
+						Type:        "boolean",
+						Description: "If true, the changes made by the user are included.",
+					},
+				},
+			},
+		},
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var userID int64
+			hours := int64(followedTasksDefaultHours)
+			var includeOwnChanges bool
+
+			var arguments map[string]any
+			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("failed to decode request: %s", err.Error())), nil
+			}
+			err := helpers.ParamGroup(arguments,
+				helpers.OptionalNumericParam(&userID, "user_id"),
+				helpers.OptionalNumericParam(&hours, "hours"),
+				helpers.OptionalParam(&includeOwnChanges, "include_own_changes"),
+			)
+			if err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
+			}
+			if hours <= 0 {
+				return helpers.NewToolResultTextError("invalid parameters: hours must be positive"), nil
+			}
+
+			if userID == 0 {
+				me, err := projects.UserGetMe(ctx, engine, projects.NewUserGetMeRequest())
+				if err != nil {
+					return helpers.HandleAPIError(err, "failed to get the authenticated user")
+				}
+				userID = me.User.ID
+			}
+			digest := followedTasksDigest{
+				UserID: userID,
+				Since:  time.Now().UTC().Add(-time.Duration(hours) * time.Hour).Truncate(time.Second),
+			}
+
+			query := url.Values{
+				"followerIds":           []string{strconv.FormatInt(userID, 10)},
+				"updatedAfter":          []string{digest.Since.Format(time.RFC3339)},
+				"includeCompletedTasks": []string{"true"},
+			}
+			tasks, tasksTruncated, err := helpers.FetchAllPages(ctx, helpers.AutoPagination{
+				PageSize: 100,
+				MaxItems: followedTasksMaxTasks,
+			}, func(ctx context.Context, page, pageSize int64) ([]projects.Task, bool, error) {
+				taskListRequest := projects.NewTaskListRequest()
+				taskListRequest.Filters.Page = page
+				taskListRequest.Filters.PageSize = pageSize
+
+				response, err := executeWithQuery[*projects.TaskListResponse](ctx, engine, taskListRequest, query)
+				if err != nil {
+					return nil, false, err
+				}
+				return response.Tasks, response.Meta.Page.HasMore, nil
+			})
+			if err != nil {
+				return helpers.HandleAPIError(err, "failed to list the followed tasks")
+			}
+			digest.Tasks = make([]followedTask, 0, len(tasks))
+			if len(tasks) == 0 {
+				return followedTasksDigestResult(digest)
+			}
+
+			activities, activitiesTruncated, err := helpers.FetchAllPages(ctx, helpers.AutoPagination{
+				PageSize: 100,
+				MaxItems: followedTasksMaxActivities,
+			}, func(ctx context.Context, page, pageSize int64) ([]projects.Activity, bool, error) {
+				activityListRequest := projects.NewActivityListRequest()
+				activityListRequest.Filters.StartDate = digest.Since
+				activityListRequest.Filters.LogItemTypes = []projects.LogItemType{
+					projects.LogItemTypeTask,
+					projects.LogItemTypeTaskComment,
+				}
+				activityListRequest.Filters.Page = page
+				activityListRequest.Filters.PageSize = pageSize
+
+				response, err := projects.ActivityList(ctx, engine, activityListRequest)
+				if err != nil {
+					return nil, false, err
+				}
+				return response.Activities, response.Meta.Page.HasMore, nil
+			})
+			if err != nil {
+				return helpers.HandleAPIError(err, "failed to list the activities")
+			}
+			digest.Truncated = tasksTruncated || activitiesTruncated
+
+			events := make(map[int64][]followedTaskEvent)
+			for _, activity := range activities {
+				if !includeOwnChanges && activity.User.ID == userID {
+					continue
+				}
+				taskID, action := activityTask(activity)
+				if taskID == 0 {
+					continue
+				}
+				event := followedTaskEvent{
+					At:     activity.At,
+					Action: action,
+					UserID: activity.User.ID,
+				}
+				if activity.Description != nil {
+					event.Description = *activity.Description
+				}
+				events[taskID] = append(events[taskID], event)
+			}
+
+			for _, task := range tasks {
+				taskEvents := events[task.ID]
+				if len(taskEvents) == 0 && !includeOwnChanges && task.UpdatedBy != nil && *task.UpdatedBy == userID {
+					// only changed by the user
+					continue
+				}
+				slices.SortFunc(taskEvents, func(a, b followedTaskEvent) int {
+					return b.At.Compare(a.At)
+				})
+				digest.Tasks = append(digest.Tasks, followedTask{
+					TaskID:    task.ID,
+					Name:      task.Name,
+					Status:    task.Status,
+					UpdatedAt: task.UpdatedAt,
+					Changes:   len(taskEvents),
+					Summary:   summarizeTaskEvents(taskEvents),
+					Events:    taskEvents[:min(len(taskEvents), followedTasksMaxEvents)],
+				})
+			}
+			slices.SortFunc(digest.Tasks, func(a, b followedTask) int {
+				return b.UpdatedAt.Compare(a.UpdatedAt)
+			})
+			return followedTasksDigestResult(digest)
+		},
+	}
+}
+
+func followedTasksDigestResult(digest followedTasksDigest) (*mcp.CallToolResult, error) {
+	encoded, err := json.Marshal(digest)
+	if err != nil {
+		return nil, err
+	}
+	return helpers.NewToolResultText("%s", encoded), nil
+}
+
+// activityTask returns the task changed by the activity and the kind of the
+// change. The task comment activities reference the comment, so the task is
+// resolved from the activity link.
+func activityTask(activity projects.Activity) (int64, string) {
+	if strings.Contains(activity.Item.Type, "comment") {
+		for _, link := range []*string{activity.ItemLink, activity.Link} {
+			if link == nil {
+				continue
+			}
+			if matches := reActivityTaskLink.FindStringSubmatch(*link); matches != nil {
+				taskID, _ := strconv.ParseInt(matches[1], 10, 64)
+				return taskID, "comment"
+			}
+		}
+		return 0, ""
+	}
+	return activity.Item.ID, string(activity.Action)
+}
+
+// summarizeTaskEvents describes the changes of a task in a few words, e.g.
+// "completed, 2 edits, 1 comment".
+func summarizeTaskEvents(events []followedTaskEvent) string {
+	if len(events) == 0 {
+		return "updated"
+	}
+	counts := make(map[string]int)
+	for _, event := range events {
+		counts[event.Action]++
+	}
+	nouns := map[string][2]string{
+		string(projects.LogTypeEdited): {"edit", "edits"},
+		"comment":                      {"comment", "comments"},
+	}
+
+	var parts []string
+	for _, action := range []string{
+		string(projects.LogTypeNew),
+		string(projects.LogTypeCompleted),
+		string(projects.LogTypeReopened),
+	} {
+		if counts[action] > 0 {
+			if action == string(projects.LogTypeNew) {
+				parts = append(parts, "created")
+			} else {
+				parts = append(parts, action)
+			}
+			delete(counts, action)
+		}
+	}
+	for _, action := range []string{string(projects.LogTypeEdited), "comment"} {
+		if count := counts[action]; count > 0 {
+			noun := nouns[action][0]
+			if count > 1 {
+				noun = nouns[action][1]
+			}
+			parts = append(parts, fmt.Sprintf("%d %s", count, noun))
+			delete(counts, action)
+		}
+	}
+	return strings.Join(append(parts, slices.Sorted(maps.Keys(counts))...), ", ")
+}
@@ -0,0 +1,68 @@
+package twprojects_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/testutil"
+	"github.com/teamwork/mcp/internal/twprojects"
+)
+
+func TestFollowedTasksDigest(t *testing.T) {
+	mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(r *http.Request) (int, []byte) {
+		query := r.URL.Query()
+		switch r.URL.Path {
+		case "/projects/api/v3/tasks.json":
+			if query.Get("followerIds") != "7" || query.Get("updatedAfter") == "" {
+				t.Errorf("unexpected task query %s", r.URL.RawQuery)
+			}
+			return http.StatusOK, []byte(`{"tasks":[` +
+				`{"id":1,"name":"Design","status":"completed","updatedAt":"2025-03-02T10:00:00Z","updatedBy":8},` +
+				`{"id":2,"name":"Copy","status":"new","updatedAt":"2025-03-02T12:00:00Z","updatedBy":7}],` +
+				`"meta":{"page":{"hasMore":false}}}`)
+		case "/projects/api/v3/latestactivity.json":
+			return http.StatusOK, []byte(`{"activities":[` +
+				`{"id":10,"activityType":"edited","dateTime":"2025-03-02T08:00:00Z","user":{"id":8},"item":{"id":1,"type":"task"}},` +
+				`{"id":11,"activityType":"edited","dateTime":"2025-03-02T09:00:00Z","user":{"id":8},"item":{"id":1,"type":"task"}},` +
+				`{"id":12,"activityType":"completed","dateTime":"2025-03-02T10:00:00Z","user":{"id":8},"item":{"id":1,"type":"task"}},` +
+				`{"id":13,"activityType":"new","dateTime":"2025-03-02T09:30:00Z","user":{"id":9},` +
+				`"item":{"id":55,"type":"task_comment"},"link":"tasks/1?c=55"},` +
+				`{"id":14,"activityType":"edited","dateTime":"2025-03-02T12:00:00Z","user":{"id":7},"item":{"id":2,"type":"task"}}],` +
+				`"meta":{"page":{"hasMore":false}}}`)
+		}
+		t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		return http.StatusNotFound, []byte(`{}`)
+	})
+
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodFollowedTasksDigest.String(), map[string]any{
+		"user_id": float64(7),
+		"hours":   float64(48),
+	}, testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+		toolResult, ok := result.(*mcp.CallToolResult)
+		if !ok {
+			t.Fatalf("unexpected result type: %T", result)
+		}
+		if toolResult.IsError {
+			t.Fatalf("tool failed to execute: %v", toolResult.Content)
+		}
+		var digest struct {
+			Tasks []struct {
+				TaskID  int64  `json:"taskId"`
+				Changes int    `json:"changes"`
+				Summary string `json:"summary"`
+			} `json:"tasks"`
+		}
+		if err := json.Unmarshal([]byte(toolResult.Content[0].(*mcp.TextContent).Text), &digest); err != nil {
+			t.Fatalf("failed to decode result: %v", err)
+		}
+		// the task only changed by the user is skipped
+		if len(digest.Tasks) != 1 || digest.Tasks[0].TaskID != 1 {
+			t.Fatalf("expected only task 1, got %+v", digest.Tasks)
+		}
+		if task := digest.Tasks[0]; task.Changes != 4 || task.Summary != "completed, 2 edits, 1 comment" {
+			t.Errorf("unexpected summary %+v", task)
+		}
+	}))
+}
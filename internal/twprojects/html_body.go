@@ -0,0 +1,304 @@
+package twprojects
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/config"
+	"github.com/teamwork/mcp/internal/helpers"
+	"github.com/teamwork/mcp/internal/toolsets"
+	"github.com/teamwork/twapi-go-sdk"
+)
+
+// List of methods available in the Teamwork.com MCP service.
+//
+// The naming convention for methods follows a pattern described here:
+// https://github.com/github/github-mcp-server/issues/333
+const (
+	MethodHTMLBodyBuild toolsets.Method = "twprojects-build_html_body"
+)
+
+func init() {
+	// register the toolset methods
+	toolsets.RegisterMethod(MethodHTMLBodyBuild)
+}
+
+var (
+	// reHTMLBodyHeading matches a Markdown heading line.
+	reHTMLBodyHeading = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	// reHTMLBodyUnorderedItem matches an unordered list item.
+	reHTMLBodyUnorderedItem = regexp.MustCompile(`^[-*+]\s+(.*)$`)
+	// reHTMLBodyOrderedItem matches an ordered list item.
+	reHTMLBodyOrderedItem = regexp.MustCompile(`^\d+[.)]\s+(.*)$`)
+	// reHTMLBodyRule matches a horizontal rule.
+	reHTMLBodyRule = regexp.MustCompile(`^(?:-{3,}|\*{3,}|_{3,})$`)
+	// reHTMLBodyCode matches an inline code span.
+	reHTMLBodyCode = regexp.MustCompile("`([^`]+)`")
+	// reHTMLBodyLink matches a Markdown link, after the text was escaped.
+	reHTMLBodyLink = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	// reHTMLBodyMention matches "@name" mentions not preceded by a word
+	// character, so email addresses are left alone.
+	reHTMLBodyMention = regexp.MustCompile(`(^|[^\p{L}\p{N}_@.])@([\p{L}][\p{L}\p{N}_'.-]*[\p{L}\p{N}])`)
+	// reHTMLBodyTaskLink matches "#123" task references.
+	reHTMLBodyTaskLink = regexp.MustCompile(`(^|[\s(])#(\d+)\b`)
+	// reHTMLBodyStrong matches bold text.
+	reHTMLBodyStrong = regexp.MustCompile(`\*\*(\S(?:.*?\S)?)\*\*|__(\S(?:.*?\S)?)__`)
+	// reHTMLBodyEmphasis matches italic text.
+	reHTMLBodyEmphasis = regexp.MustCompile(`\*(\S(?:[^*]*?\S)?)\*|(^|[^\p{L}\p{N}_])_(\S(?:[^_]*?\S)?)_`)
+	// reHTMLBodyStrikethrough matches strikethrough text.
+	reHTMLBodyStrikethrough = regexp.MustCompile(`~~(\S(?:.*?\S)?)~~`)
+	// reHTMLBodyPlaceholder matches the placeholders of the inline elements
+	// protected from the other rules.
+	reHTMLBodyPlaceholder = regexp.MustCompile("\x00(\\d+)\x00")
+)
+
+// htmlBodyMention is a "@name" mention found in the Markdown.
+type htmlBodyMention struct {
+	Name   string `json:"name"`
+	UserID int64  `json:"userId,omitempty"`
+}
+
+// htmlBody is the HTML built from the Markdown.
+type htmlBody struct {
+	HTML               string            `json:"html"`
+	Mentions           []htmlBodyMention `json:"mentions,omitempty"`
+	UnresolvedMentions []string          `json:"unresolvedMentions,omitempty"`
+	TaskIDs            []int64           `json:"taskIds,omitempty"`
+}
+
+// HTMLBodyBuild converts Markdown into the HTML accepted by Teamwork.com rich
+// text fields, keeping the mentions and task references intact.
+func HTMLBodyBuild(engine *twapi.Engine) toolsets.ToolWrapper {
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name: string(MethodHTMLBodyBuild),
+			Description: "Convert Markdown into HTML accepted by Teamwork.com rich text fields, such as comment bodies " +
+				"with the 'HTML' content type. It supports headings, paragraphs, lists, quotes, code, links, bold, " +
+				"italic and strikethrough text. '@name' mentions are kept as written, so they are not mangled by the " +
+				"formatting, and resolved to users; '#123' references become links to the tasks. Use it instead of " +
+				"writing the HTML by hand. Nothing is created in Teamwork.com.",
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "Build HTML Body",
+				ReadOnlyHint: true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"markdown": {
+						Type:        "string",
+						Description: "The Markdown to convert.",
+					},
+					"resolve_mentions": {
+						Type: "boolean",
+						Description: "If true, the '@name' mentions are searched in the users, returning the ID of the " +
+							"single user matching each one. Defaults to true.",
+					},
+				},
+				Required: []string{"markdown"},
+			},
+		},
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var markdown string
+			resolveMentions := true
+
+			var arguments map[string]any
+			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("failed to decode request: %s", err.Error())), nil
+			}
+			err := helpers.ParamGroup(arguments,
+				helpers.RequiredParam(&markdown, "markdown"),
+				helpers.OptionalParam(&resolveMentions, "resolve_mentions"),
+			)
+			if err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
+			}
+
+			customerURL, _ := config.CustomerURLFromContext(ctx)
+			converter := htmlBodyConverter{customerURL: strings.TrimSuffix(customerURL, "/")}
+			body := htmlBody{HTML: converter.convert(markdown)}
+
+			for _, name := range converter.mentions {
+				mention := htmlBodyMention{Name: name}
+				if resolveMentions {
+					if mention.UserID, err = resolveTaskDraftAssignee(ctx, engine, name); err != nil {
+						return helpers.HandleAPIError(err, "failed to resolve mentions")
+					}
+					if mention.UserID == 0 {
+						body.UnresolvedMentions = append(body.UnresolvedMentions, name)
+					}
+				}
+				body.Mentions = append(body.Mentions, mention)
+			}
+			body.TaskIDs = converter.taskIDs
+
+			return helpers.NewToolResultJSON(body)
+		},
+	}
+}
+
+// htmlBodyConverter converts Markdown into HTML, collecting the mentions and
+// task references found.
+type htmlBodyConverter struct {
+	customerURL string
+
+	mentions []string
+	taskIDs  []int64
+}
+
+// convert converts the Markdown blocks into HTML.
+func (c *htmlBodyConverter) convert(markdown string) string {
+	lines := strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n")
+
+	var out strings.Builder
+	var paragraph, quote []string
+	var listTag string
+	flush := func() {
+		if len(paragraph) > 0 {
+			out.WriteString("<p>" + c.inlines(paragraph) + "</p>")
+			paragraph = nil
+		}
+		if len(quote) > 0 {
+			out.WriteString("<blockquote><p>" + c.inlines(quote) + "</p></blockquote>")
+			quote = nil
+		}
+		if listTag != "" {
+			out.WriteString("</" + listTag + ">")
+			listTag = ""
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+
+		if strings.HasPrefix(line, "```") {
+			flush()
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```"); i++ {
+				code = append(code, lines[i])
+			}
+			out.WriteString("<pre><code>" + html.EscapeString(strings.Join(code, "\n")) + "</code></pre>")
+			continue
+		}
+
+		switch {
+		case line == "":
+			flush()
+		case reHTMLBodyRule.MatchString(line):
+			flush()
+			out.WriteString("<hr />")
+		case reHTMLBodyHeading.MatchString(line):
+			flush()
+			matches := reHTMLBodyHeading.FindStringSubmatch(line)
+			tag := "h" + strconv.Itoa(len(matches[1]))
+			out.WriteString("<" + tag + ">" + c.inline(matches[2]) + "</" + tag + ">")
+		case strings.HasPrefix(line, ">"):
+			if len(paragraph) > 0 || listTag != "" {
+				flush()
+			}
+			quote = append(quote, strings.TrimSpace(strings.TrimPrefix(line, ">")))
+		case reHTMLBodyUnorderedItem.MatchString(line), reHTMLBodyOrderedItem.MatchString(line):
+			tag, item := "ul", reHTMLBodyUnorderedItem.FindStringSubmatch(line)
+			if item == nil {
+				tag, item = "ol", reHTMLBodyOrderedItem.FindStringSubmatch(line)
+			}
+			if listTag != tag {
+				flush()
+				out.WriteString("<" + tag + ">")
+				listTag = tag
+			}
+			out.WriteString("<li>" + c.inline(item[1]) + "</li>")
+		default:
+			if len(quote) > 0 || listTag != "" {
+				flush()
+			}
+			paragraph = append(paragraph, line)
+		}
+	}
+	flush()
+	return out.String()
+}
+
+// inlines converts the lines of a block, keeping the line breaks.
+func (c *htmlBodyConverter) inlines(lines []string) string {
+	converted := make([]string, len(lines))
+	for i, line := range lines {
+		converted[i] = c.inline(line)
+	}
+	return strings.Join(converted, "<br />")
+}
+
+// inline converts the inline Markdown of the text. Code spans, links, mentions
+// and task references are replaced by placeholders first, so the emphasis rules
+// don't change their content (e.g. the underscores of "@jane_doe").
+func (c *htmlBodyConverter) inline(text string) string {
+	var protected []string
+	protect := func(s string) string {
+		protected = append(protected, s)
+		return "\x00" + strconv.Itoa(len(protected)-1) + "\x00"
+	}
+
+	text = reHTMLBodyCode.ReplaceAllStringFunc(text, func(match string) string {
+		return protect("<code>" + html.EscapeString(match[1:len(match)-1]) + "</code>")
+	})
+	text = html.EscapeString(text)
+	text = reHTMLBodyLink.ReplaceAllStringFunc(text, func(match string) string {
+		matches := reHTMLBodyLink.FindStringSubmatch(match)
+		if !htmlBodySafeURL(html.UnescapeString(matches[2])) {
+			return match
+		}
+		return protect(`<a href="` + matches[2] + `">` + matches[1] + `</a>`)
+	})
+	text = reHTMLBodyMention.ReplaceAllStringFunc(text, func(match string) string {
+		matches := reHTMLBodyMention.FindStringSubmatch(match)
+		name := html.UnescapeString(matches[2])
+		c.addMention(name)
+		return matches[1] + protect("@"+html.EscapeString(name))
+	})
+	text = reHTMLBodyTaskLink.ReplaceAllStringFunc(text, func(match string) string {
+		matches := reHTMLBodyTaskLink.FindStringSubmatch(match)
+		id, err := strconv.ParseInt(matches[2], 10, 64)
+		if err != nil {
+			return match
+		}
+		c.taskIDs = append(c.taskIDs, id)
+		return matches[1] + protect(`<a href="`+c.customerURL+"/app/tasks/"+matches[2]+`">#`+matches[2]+`</a>`)
+	})
+
+	text = reHTMLBodyStrong.ReplaceAllString(text, "<strong>$1$2</strong>")
+	text = reHTMLBodyEmphasis.ReplaceAllString(text, "$2<em>$1$3</em>")
+	text = reHTMLBodyStrikethrough.ReplaceAllString(text, "<del>$1</del>")
+
+	return reHTMLBodyPlaceholder.ReplaceAllStringFunc(text, func(match string) string {
+		index, _ := strconv.Atoi(strings.Trim(match, "\x00"))
+		return protected[index]
+	})
+}
+
+// addMention records the mentioned name, once.
+func (c *htmlBodyConverter) addMention(name string) {
+	for _, mention := range c.mentions {
+		if strings.EqualFold(mention, name) {
+			return
+		}
+	}
+	c.mentions = append(c.mentions, name)
+}
+
+// htmlBodySafeURL reports whether the link URL can be used, rejecting schemes
+// like "javascript:".
+func htmlBodySafeURL(uri string) bool {
+	lower := strings.ToLower(uri)
+	for _, prefix := range []string{"http://", "https://", "mailto:", "/", "#"} {
+		if strings.HasPrefix(lower, prefix) {
+			return true
+		}
+	}
+	return false
+}
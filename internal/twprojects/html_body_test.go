@@ -0,0 +1,98 @@
+package twprojects_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/testutil"
+	"github.com/teamwork/mcp/internal/twprojects"
+)
+
+func TestHTMLBodyBuild(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		html     string
+	}{{
+		name:     "paragraphs",
+		markdown: "Hello **team**, this is *urgent*.\nSee `main.go`.\n\n~~Old~~ plan",
+		html:     "<p>Hello <strong>team</strong>, this is <em>urgent</em>.<br />See <code>main.go</code>.</p><p><del>Old</del> plan</p>",
+	}, {
+		name:     "blocks",
+		markdown: "## Status\n- one\n- two\n1. first\n> quoted\n```\n<b>x</b>\n```",
+		html: "<h2>Status</h2><ul><li>one</li><li>two</li></ul><ol><li>first</li></ol>" +
+			"<blockquote><p>quoted</p></blockquote><pre><code>&lt;b&gt;x&lt;/b&gt;</code></pre>",
+	}, {
+		name:     "mentions and links",
+		markdown: "@jane_doe check #123 and [docs](https://example.com/a_b_c), mail jane@example.com <script>",
+		html: `<p>@jane_doe check <a href="/app/tasks/123">#123</a> and <a href="https://example.com/a_b_c">docs</a>, ` +
+			`mail jane@example.com &lt;script&gt;</p>`,
+	}, {
+		name:     "unsafe link",
+		markdown: "[click](javascript:alert(1))",
+		html:     "<p>[click](javascript:alert(1))</p>",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mcpServer := mcpServerMock(t, http.StatusOK, []byte(`{}`))
+			testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodHTMLBodyBuild.String(), map[string]any{
+				"markdown":         tt.markdown,
+				"resolve_mentions": false,
+			}, testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+				toolResult, ok := result.(*mcp.CallToolResult)
+				if !ok {
+					t.Fatalf("unexpected result type: %T", result)
+				}
+				if toolResult.IsError {
+					t.Fatalf("tool failed to execute: %v", toolResult.Content)
+				}
+				var body struct {
+					HTML string `json:"html"`
+				}
+				if err := json.Unmarshal([]byte(toolResult.Content[0].(*mcp.TextContent).Text), &body); err != nil {
+					t.Fatalf("failed to decode result: %v", err)
+				}
+				if body.HTML != tt.html {
+					t.Errorf("expected html %q, got %q", tt.html, body.HTML)
+				}
+			}))
+		})
+	}
+}
+
+func TestHTMLBodyBuildMentions(t *testing.T) {
+	mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(r *http.Request) (int, []byte) {
+		if r.URL.Query().Get("searchTerm") == "jane" {
+			return http.StatusOK, []byte(`{"people":[{"id":7,"firstName":"Jane"}]}`)
+		}
+		return http.StatusOK, []byte(`{"people":[]}`)
+	})
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodHTMLBodyBuild.String(), map[string]any{
+		"markdown": "@jane and @nobody, please review. Thanks @Jane!",
+	}, testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+		toolResult, ok := result.(*mcp.CallToolResult)
+		if !ok {
+			t.Fatalf("unexpected result type: %T", result)
+		}
+		if toolResult.IsError {
+			t.Fatalf("tool failed to execute: %v", toolResult.Content)
+		}
+		var body struct {
+			Mentions []struct {
+				Name   string `json:"name"`
+				UserID int64  `json:"userId"`
+			} `json:"mentions"`
+			UnresolvedMentions []string `json:"unresolvedMentions"`
+		}
+		if err := json.Unmarshal([]byte(toolResult.Content[0].(*mcp.TextContent).Text), &body); err != nil {
+			t.Fatalf("failed to decode result: %v", err)
+		}
+		if len(body.Mentions) != 2 || body.Mentions[0].UserID != 7 || len(body.UnresolvedMentions) != 1 ||
+			body.UnresolvedMentions[0] != "nobody" {
+			t.Errorf("unexpected mentions: %+v", body)
+		}
+	}))
+}
@@ -0,0 +1,126 @@
+package twprojects
+
+import (
+	"context"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	twapi "github.com/teamwork/twapi-go-sdk"
+)
+
+// listOrder maps the sort fields accepted by a list tool to the values of the
+// API "orderBy" query parameter.
+type listOrder map[string]string
+
+// Sort fields of the list tools.
+var (
+	taskListOrder = listOrder{
+		"due_date":   "duedate",
+		"start_date": "startdate",
+		"priority":   "priority",
+		"created":    "createdat",
+		"name":       "name",
+	}
+	projectListOrder = listOrder{
+		"due_date": "duedate",
+		"created":  "datecreated",
+		"name":     "name",
+		"activity": "lastactivity",
+	}
+	timelogListOrder = listOrder{
+		"date":    "date",
+		"updated": "dateupdated",
+		"hours":   "hours",
+		"user":    "user",
+		"project": "project",
+		"task":    "task",
+	}
+)
+
+// orderModes are the sort directions of the list tools.
+var orderModes = []string{"asc", "desc"}
+
+// fields returns the sort fields sorted alphabetically.
+func (o listOrder) fields() []string {
+	return slices.Sorted(maps.Keys(o))
+}
+
+// schema returns the schema of the order_by parameter.
+func (o listOrder) schema(entity string) *jsonschema.Schema {
+	fields := o.fields()
+	return &jsonschema.Schema{
+		Type: "string",
+		Description: "The field to sort the " + entity + " by. Possible values are: " +
+			strings.Join(fields, ", ") + ".",
+		Enum: enumSchemaValues(fields),
+	}
+}
+
+// orderModeSchema returns the schema of the order_mode parameter.
+func orderModeSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:        "string",
+		Description: "The sort direction. Possible values are: asc, desc. Only used with order_by.",
+		Enum:        enumSchemaValues(orderModes),
+	}
+}
+
+// query returns the API query parameters to sort the results.
+func (o listOrder) query(orderBy, orderMode string) url.Values {
+	query := make(url.Values)
+	if field, ok := o[orderBy]; ok {
+		query.Set("orderBy", field)
+		if orderMode != "" {
+			query.Set("orderMode", orderMode)
+		}
+	}
+	return query
+}
+
+// joinIDs returns the IDs separated by commas, as expected by the API query
+// parameters.
+func joinIDs(ids []int64) string {
+	values := make([]string, len(ids))
+	for i, id := range ids {
+		values[i] = strconv.FormatInt(id, 10)
+	}
+	return strings.Join(values, ",")
+}
+
+// queryRequest wraps a request, setting additional query parameters not
+// supported by the SDK.
+type queryRequest[R twapi.HTTPRequester] struct {
+	request R
+	query   url.Values
+}
+
+// HTTPRequest creates the HTTP request of the wrapped request with the
+// additional query parameters.
+func (q queryRequest[R]) HTTPRequest(ctx context.Context, server string) (*http.Request, error) {
+	req, err := q.request.HTTPRequest(ctx, server)
+	if err != nil {
+		return nil, err
+	}
+	query := req.URL.Query()
+	for key, values := range q.query {
+		query[key] = values
+	}
+	req.URL.RawQuery = query.Encode()
+	return req, nil
+}
+
+// executeWithQuery executes the request with the additional query parameters,
+// decoding the response into T.
+func executeWithQuery[T twapi.HTTPResponser, R twapi.HTTPRequester](
+	ctx context.Context,
+	engine *twapi.Engine,
+	request R,
+	query url.Values,
+) (T, error) {
+	return twapi.Execute[queryRequest[R], T](ctx, engine, queryRequest[R]{request: request, query: query})
+}
@@ -0,0 +1,109 @@
+package twprojects
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/config"
+	"github.com/teamwork/mcp/internal/helpers"
+	"github.com/teamwork/mcp/internal/toolsets"
+)
+
+// macroToolPrefix is the prefix of the tools generated for each macro.
+const macroToolPrefix = "twprojects-macro_"
+
+// macroTools creates a tool for each macro referencing one of the given tools.
+// The macro tool calls the referenced tool with the fixed arguments of the
+// macro, merged with the arguments provided at call time. Macros referencing
+// other tools are skipped, so the macros of write tools are only created with
+// the write tools.
+func macroTools(macros []config.ToolMacro, tools []toolsets.ToolWrapper) []toolsets.ToolWrapper {
+	var created []toolsets.ToolWrapper
+	for _, macro := range macros {
+		index := slices.IndexFunc(tools, func(tool toolsets.ToolWrapper) bool {
+			return tool.Tool.Name == macro.Tool
+		})
+		if index == -1 {
+			continue
+		}
+		created = append(created, macroTool(macro, tools[index]))
+	}
+	return created
+}
+
+// macroTool creates the tool of a macro calling the given tool.
+func macroTool(macro config.ToolMacro, target toolsets.ToolWrapper) toolsets.ToolWrapper {
+	method := toolsets.Method(macroToolPrefix + macro.Name)
+	toolsets.RegisterMethod(method)
+
+	description := macro.Description
+	if description == "" {
+		description = target.Tool.Description
+	}
+	if len(macro.Arguments) > 0 {
+		fixed := slices.Sorted(maps.Keys(macro.Arguments))
+		description += fmt.Sprintf(" Shortcut for %s with fixed %v.", macro.Tool, fixed)
+	}
+
+	var annotations *mcp.ToolAnnotations
+	if target.Tool.Annotations != nil {
+		copied := *target.Tool.Annotations
+		annotations = &copied
+	} else {
+		annotations = &mcp.ToolAnnotations{}
+	}
+	annotations.Title = macro.Name
+
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name:         string(method),
+			Description:  description,
+			Annotations:  annotations,
+			InputSchema:  macroInputSchema(target.Tool.InputSchema, macro.Arguments),
+			OutputSchema: target.Tool.OutputSchema,
+		},
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			arguments := make(map[string]any)
+			if len(request.Params.Arguments) > 0 {
+				if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+					return helpers.NewToolResultTextError(fmt.Sprintf("failed to decode request: %s", err.Error())), nil
+				}
+			}
+			// the fixed arguments can't be overridden by the caller
+			maps.Copy(arguments, macro.Arguments)
+
+			targetRequest, err := withArguments(request, arguments)
+			if err != nil {
+				return nil, err
+			}
+			targetRequest.Params.Name = macro.Tool
+			return target.Handler(ctx, targetRequest)
+		},
+	}
+}
+
+// macroInputSchema returns the input schema of the tool without the fixed
+// arguments, which can't be provided by the caller.
+func macroInputSchema(schema any, fixed map[string]any) any {
+	original, ok := schema.(*jsonschema.Schema)
+	if !ok || len(fixed) == 0 {
+		return schema
+	}
+	copied := *original
+	copied.Properties = make(map[string]*jsonschema.Schema, len(original.Properties))
+	for name, property := range original.Properties {
+		if _, ok := fixed[name]; !ok {
+			copied.Properties[name] = property
+		}
+	}
+	copied.Required = slices.DeleteFunc(slices.Clone(original.Required), func(name string) bool {
+		_, ok := fixed[name]
+		return ok
+	})
+	return &copied
+}
@@ -0,0 +1,59 @@
+package twprojects_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/teamwork/mcp/internal/config"
+	"github.com/teamwork/mcp/internal/testutil"
+	"github.com/teamwork/mcp/internal/twprojects"
+)
+
+func TestMacro(t *testing.T) {
+	var path string
+	var timelog struct {
+		Timelog struct {
+			Description string `json:"description"`
+			Minutes     int64  `json:"minutes"`
+		} `json:"timelog"`
+	}
+	mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(r *http.Request) (int, []byte) {
+		if r.Method != http.MethodPost {
+			return http.StatusOK, []byte(`{}`)
+		}
+		path = r.URL.Path
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("failed to read request body: %v", err)
+		}
+		if err := json.Unmarshal(body, &timelog); err != nil {
+			t.Fatalf("failed to decode request body: %v", err)
+		}
+		return http.StatusCreated, []byte(`{"timelog":{"id":1}}`)
+	}, twprojects.WithMacros([]config.ToolMacro{{
+		Name: "log_standup_time",
+		Tool: twprojects.MethodTimelogCreate.String(),
+		Arguments: map[string]any{
+			"project_id": float64(123),
+			"hours":      float64(0),
+			"minutes":    float64(15),
+		},
+	}}))
+
+	// the fixed arguments take precedence over the provided ones
+	testutil.ExecuteToolRequest(t, mcpServer, "twprojects-macro_log_standup_time", map[string]any{
+		"description": "Standup",
+		"date":        "2023-12-31",
+		"time":        "09:00:00",
+		"minutes":     float64(60),
+	})
+
+	if path != "/projects/api/v3/projects/123/time.json" {
+		t.Errorf("unexpected path %q", path)
+	}
+	if timelog.Timelog.Description != "Standup" || timelog.Timelog.Minutes != 15 {
+		t.Errorf("unexpected timelog %+v", timelog.Timelog)
+	}
+}
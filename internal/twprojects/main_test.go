@@ -1,6 +1,9 @@
 package twprojects_test
 
 import (
+	"testing"
+
+	"github.com/teamwork/mcp/internal/config"
 	"github.com/teamwork/mcp/internal/testutil"
 )
 
@@ -8,3 +11,27 @@ import (
 var (
 	mcpServerMock = testutil.ProjectsMCPServerMock
 )
+
+// runSessions runs the test of a tool keeping state for each kind of session:
+// the in-memory sessions, and the STDIO sessions, which have no ID and are
+// bound to an installation. The option sets the context of the requests.
+func runSessions(t *testing.T, f func(t *testing.T, withSession testutil.ExecuteToolRequestOption)) {
+	sessions := []struct {
+		name        string
+		customerURL string
+	}{{
+		name: "in-memory",
+	}, {
+		name:        "stdio",
+		customerURL: "https://example.teamwork.com",
+	}}
+	for _, session := range sessions {
+		t.Run(session.name, func(t *testing.T) {
+			ctx := t.Context()
+			if session.customerURL != "" {
+				ctx = config.WithCustomerURL(ctx, session.customerURL)
+			}
+			f(t, testutil.ExecuteToolRequestWithContext(ctx))
+		})
+	}
+}
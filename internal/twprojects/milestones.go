@@ -1,9 +1,13 @@
 package twprojects
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/google/jsonschema-go/jsonschema"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -21,6 +25,7 @@ const (
 	MethodMilestoneCreate        toolsets.Method = "twprojects-create_milestone"
 	MethodMilestoneUpdate        toolsets.Method = "twprojects-update_milestone"
 	MethodMilestoneDelete        toolsets.Method = "twprojects-delete_milestone"
+	MethodMilestoneComplete      toolsets.Method = "twprojects-complete_milestone"
 	MethodMilestoneGet           toolsets.Method = "twprojects-get_milestone"
 	MethodMilestoneList          toolsets.Method = "twprojects-list_milestones"
 	MethodMilestoneListByProject toolsets.Method = "twprojects-list_milestones_by_project"
@@ -43,6 +48,7 @@ func init() {
 	toolsets.RegisterMethod(MethodMilestoneCreate)
 	toolsets.RegisterMethod(MethodMilestoneUpdate)
 	toolsets.RegisterMethod(MethodMilestoneDelete)
+	toolsets.RegisterMethod(MethodMilestoneComplete)
 	toolsets.RegisterMethod(MethodMilestoneGet)
 	toolsets.RegisterMethod(MethodMilestoneList)
 	toolsets.RegisterMethod(MethodMilestoneListByProject)
@@ -91,7 +97,7 @@ func MilestoneCreate(engine *twapi.Engine) toolsets.ToolWrapper {
 					},
 					"assignees": {
 						Type: "object",
-						Description: "An object containing assignees for the milestone. " +
+						Description: "An object containing assignees for the milestone, who are its responsible parties. " +
 							"MUST contain at least one of: user_ids, company_ids or team_ids with non-empty arrays.",
 						Properties: map[string]*jsonschema.Schema{
 							"user_ids": {
@@ -141,12 +147,21 @@ func MilestoneCreate(engine *twapi.Engine) toolsets.ToolWrapper {
 							Type: "integer",
 						},
 					},
+					"notify": {
+						Type:        "boolean",
+						Description: "Whether the responsible parties (assignees) are notified about the milestone change.",
+					},
+					"reminder": {
+						Type: "boolean",
+						Description: "Whether the responsible parties (assignees) receive reminders before the milestone due " +
+							"date.",
+					},
 				},
 				Required: []string{"name", "project_id", "due_date", "assignees"},
 			},
 		},
 		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			var milestoneCreateRequest projects.MilestoneCreateRequest
+			var milestoneCreateRequest legacyMilestoneCreateRequest
 
 			var arguments map[string]any
 			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
@@ -159,6 +174,8 @@ func MilestoneCreate(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.RequiredLegacyDateParam(&milestoneCreateRequest.DueAt, "due_date"),
 				helpers.OptionalNumericListParam(&milestoneCreateRequest.TasklistIDs, "tasklist_ids"),
 				helpers.OptionalNumericListParam(&milestoneCreateRequest.TagIDs, "tag_ids"),
+				helpers.OptionalPointerParam(&milestoneCreateRequest.Notify, "notify"),
+				helpers.OptionalPointerParam(&milestoneCreateRequest.Reminder, "reminder"),
 			)
 			if err != nil {
 				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
@@ -186,7 +203,8 @@ func MilestoneCreate(engine *twapi.Engine) toolsets.ToolWrapper {
 				return helpers.NewToolResultTextError("at least one assignee must be provided"), nil
 			}
 
-			milestone, err := projects.MilestoneCreate(ctx, engine, milestoneCreateRequest)
+			milestone, err := twapi.Execute[legacyMilestoneCreateRequest, *projects.MilestoneCreateResponse](
+				ctx, engine, milestoneCreateRequest)
 			if err != nil {
 				return helpers.HandleAPIError(err, "failed to create milestone")
 			}
@@ -207,6 +225,7 @@ func MilestoneUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 			InputSchema: &jsonschema.Schema{
 				Type: "object",
 				Properties: map[string]*jsonschema.Schema{
+					helpers.ExpectedUpdatedAtParam: helpers.ExpectedUpdatedAtSchema("milestone"),
 					"id": {
 						Type:        "integer",
 						Description: "The ID of the milestone to update.",
@@ -226,7 +245,7 @@ func MilestoneUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 					},
 					"assignees": {
 						Type:        "object",
-						Description: "An object containing assignees for the milestone.",
+						Description: "An object containing assignees for the milestone, who are its responsible parties.",
 						Properties: map[string]*jsonschema.Schema{
 							"user_ids": {
 								Type:        "array",
@@ -275,12 +294,22 @@ func MilestoneUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 							Type: "integer",
 						},
 					},
+					"notify": {
+						Type:        "boolean",
+						Description: "Whether the responsible parties (assignees) are notified about the milestone change.",
+					},
+					"reminder": {
+						Type: "boolean",
+						Description: "Whether the responsible parties (assignees) receive reminders before the milestone due " +
+							"date.",
+					},
 				},
 				Required: []string{"id"},
 			},
 		},
 		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			var milestoneUpdateRequest projects.MilestoneUpdateRequest
+			var milestoneUpdateRequest legacyMilestoneUpdateRequest
+			var expectedUpdatedAt *time.Time
 
 			var arguments map[string]any
 			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
@@ -288,11 +317,14 @@ func MilestoneUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 			}
 			err := helpers.ParamGroup(arguments,
 				helpers.RequiredNumericParam(&milestoneUpdateRequest.Path.ID, "id"),
+				helpers.OptionalTimePointerParam(&expectedUpdatedAt, helpers.ExpectedUpdatedAtParam),
 				helpers.OptionalPointerParam(&milestoneUpdateRequest.Name, "name"),
 				helpers.OptionalPointerParam(&milestoneUpdateRequest.Description, "description"),
 				helpers.OptionalLegacyDatePointerParam(&milestoneUpdateRequest.DueAt, "due_date"),
 				helpers.OptionalNumericListParam(&milestoneUpdateRequest.TasklistIDs, "tasklist_ids"),
 				helpers.OptionalNumericListParam(&milestoneUpdateRequest.TagIDs, "tag_ids"),
+				helpers.OptionalPointerParam(&milestoneUpdateRequest.Notify, "notify"),
+				helpers.OptionalPointerParam(&milestoneUpdateRequest.Reminder, "reminder"),
 			)
 			if err != nil {
 				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
@@ -315,7 +347,20 @@ func MilestoneUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 				}
 			}
 
-			_, err = projects.MilestoneUpdate(ctx, engine, milestoneUpdateRequest)
+			if expectedUpdatedAt != nil {
+				milestoneResponse, err := projects.MilestoneGet(ctx, engine, projects.NewMilestoneGetRequest(milestoneUpdateRequest.Path.ID))
+				if err != nil {
+					return helpers.HandleAPIError(err, "failed to get milestone")
+				}
+				conflict := helpers.CheckConflict("milestone", milestoneUpdateRequest.Path.ID, *expectedUpdatedAt,
+					milestoneResponse.Milestone.UpdatedAt)
+				if conflict != nil {
+					return conflict, nil
+				}
+			}
+
+			_, err = twapi.Execute[legacyMilestoneUpdateRequest, *projects.MilestoneUpdateResponse](
+				ctx, engine, milestoneUpdateRequest)
 			if err != nil {
 				return helpers.HandleAPIError(err, "failed to update milestone")
 			}
@@ -367,6 +412,60 @@ func MilestoneDelete(engine *twapi.Engine) toolsets.ToolWrapper {
 	}
 }
 
+// MilestoneComplete marks a milestone as complete or incomplete in
+// Teamwork.com.
+func MilestoneComplete(engine *twapi.Engine) toolsets.ToolWrapper {
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name:        string(MethodMilestoneComplete),
+			Description: "Mark an existing milestone as complete, or reopen it, in Teamwork.com. " + milestoneDescription,
+			Annotations: &mcp.ToolAnnotations{
+				Title: "Complete Milestone",
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"id": {
+						Type:        "integer",
+						Description: "The ID of the milestone to complete.",
+					},
+					"completed": {
+						Type:        "boolean",
+						Description: "Whether the milestone is complete. Set to false to reopen it. Defaults to true.",
+					},
+				},
+				Required: []string{"id"},
+			},
+		},
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			completeRequest := milestoneCompleteRequest{
+				Completed: true,
+			}
+
+			var arguments map[string]any
+			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("failed to decode request: %s", err.Error())), nil
+			}
+			err := helpers.ParamGroup(arguments,
+				helpers.RequiredNumericParam(&completeRequest.ID, "id"),
+				helpers.OptionalParam(&completeRequest.Completed, "completed"),
+			)
+			if err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
+			}
+
+			_, err = twapi.Execute[milestoneCompleteRequest, *milestoneCompleteResponse](ctx, engine, completeRequest)
+			if err != nil {
+				return helpers.HandleAPIError(err, "failed to complete milestone")
+			}
+			if !completeRequest.Completed {
+				return helpers.NewToolResultText("Milestone reopened successfully"), nil
+			}
+			return helpers.NewToolResultText("Milestone completed successfully"), nil
+		},
+	}
+}
+
 // MilestoneGet retrieves a milestone in Teamwork.com.
 func MilestoneGet(engine *twapi.Engine) toolsets.ToolWrapper {
 	return toolsets.ToolWrapper{
@@ -403,6 +502,10 @@ func MilestoneGet(engine *twapi.Engine) toolsets.ToolWrapper {
 				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
 			}
 
+			if passthroughEnabled(ctx) {
+				return passthrough(ctx, engine, milestoneGetRequest, nil, "failed to get milestone")
+			}
+
 			milestone, err := projects.MilestoneGet(ctx, engine, milestoneGetRequest)
 			if err != nil {
 				return helpers.HandleAPIError(err, "failed to get milestone")
@@ -412,12 +515,14 @@ func MilestoneGet(engine *twapi.Engine) toolsets.ToolWrapper {
 			if err != nil {
 				return nil, err
 			}
+			linked := helpers.WebLinker(ctx, encoded,
+				helpers.WebLinkerWithIDPathBuilder("/app/milestones"),
+			)
 			return &mcp.CallToolResult{
+				Meta: helpers.WebLinkedEntitiesMeta(linked),
 				Content: []mcp.Content{
 					&mcp.TextContent{
-						Text: string(helpers.WebLinker(ctx, encoded,
-							helpers.WebLinkerWithIDPathBuilder("/app/milestones"),
-						)),
+						Text: string(linked),
 					},
 				},
 				StructuredContent: milestone,
@@ -467,12 +572,14 @@ func MilestoneList(engine *twapi.Engine) toolsets.ToolWrapper {
 						Type:        "integer",
 						Description: "Number of results per page for pagination.",
 					},
+					"count_only": countOnlySchema(),
 				},
 			},
 			OutputSchema: milestoneListOutputSchema,
 		},
 		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			var milestoneListRequest projects.MilestoneListRequest
+			var countOnly bool
 
 			var arguments map[string]any
 			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
@@ -484,11 +591,20 @@ func MilestoneList(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalPointerParam(&milestoneListRequest.Filters.MatchAllTags, "match_all_tags"),
 				helpers.OptionalNumericParam(&milestoneListRequest.Filters.Page, "page"),
 				helpers.OptionalNumericParam(&milestoneListRequest.Filters.PageSize, "page_size"),
+				helpers.OptionalParam(&countOnly, "count_only"),
 			)
 			if err != nil {
 				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
 			}
 
+			if countOnly {
+				return listCount(ctx, engine, milestoneListRequest)
+			}
+
+			if passthroughEnabled(ctx) {
+				return passthrough(ctx, engine, milestoneListRequest, nil, "failed to list milestones")
+			}
+
 			milestoneList, err := projects.MilestoneList(ctx, engine, milestoneListRequest)
 			if err != nil {
 				return helpers.HandleAPIError(err, "failed to list milestones")
@@ -498,12 +614,14 @@ func MilestoneList(engine *twapi.Engine) toolsets.ToolWrapper {
 			if err != nil {
 				return nil, err
 			}
+			linked := helpers.WebLinker(ctx, encoded,
+				helpers.WebLinkerWithIDPathBuilder("/app/milestones"),
+			)
 			return &mcp.CallToolResult{
+				Meta: helpers.WebLinkedEntitiesMeta(linked),
 				Content: []mcp.Content{
 					&mcp.TextContent{
-						Text: string(helpers.WebLinker(ctx, encoded,
-							helpers.WebLinkerWithIDPathBuilder("/app/milestones"),
-						)),
+						Text: string(linked),
 					},
 				},
 				StructuredContent: milestoneList,
@@ -557,6 +675,7 @@ func MilestoneListByProject(engine *twapi.Engine) toolsets.ToolWrapper {
 						Type:        "integer",
 						Description: "Number of results per page for pagination.",
 					},
+					"count_only": countOnlySchema(),
 				},
 				Required: []string{"project_id"},
 			},
@@ -564,6 +683,7 @@ func MilestoneListByProject(engine *twapi.Engine) toolsets.ToolWrapper {
 		},
 		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			var milestoneListRequest projects.MilestoneListRequest
+			var countOnly bool
 
 			var arguments map[string]any
 			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
@@ -576,11 +696,20 @@ func MilestoneListByProject(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalPointerParam(&milestoneListRequest.Filters.MatchAllTags, "match_all_tags"),
 				helpers.OptionalNumericParam(&milestoneListRequest.Filters.Page, "page"),
 				helpers.OptionalNumericParam(&milestoneListRequest.Filters.PageSize, "page_size"),
+				helpers.OptionalParam(&countOnly, "count_only"),
 			)
 			if err != nil {
 				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
 			}
 
+			if countOnly {
+				return listCount(ctx, engine, milestoneListRequest)
+			}
+
+			if passthroughEnabled(ctx) {
+				return passthrough(ctx, engine, milestoneListRequest, nil, "failed to list milestones")
+			}
+
 			milestoneList, err := projects.MilestoneList(ctx, engine, milestoneListRequest)
 			if err != nil {
 				return helpers.HandleAPIError(err, "failed to list milestones")
@@ -590,12 +719,14 @@ func MilestoneListByProject(engine *twapi.Engine) toolsets.ToolWrapper {
 			if err != nil {
 				return nil, err
 			}
+			linked := helpers.WebLinker(ctx, encoded,
+				helpers.WebLinkerWithIDPathBuilder("/app/milestones"),
+			)
 			return &mcp.CallToolResult{
+				Meta: helpers.WebLinkedEntitiesMeta(linked),
 				Content: []mcp.Content{
 					&mcp.TextContent{
-						Text: string(helpers.WebLinker(ctx, encoded,
-							helpers.WebLinkerWithIDPathBuilder("/app/milestones"),
-						)),
+						Text: string(linked),
 					},
 				},
 				StructuredContent: milestoneList,
@@ -603,3 +734,90 @@ func MilestoneListByProject(engine *twapi.Engine) toolsets.ToolWrapper {
 		},
 	}
 }
+
+// legacyMilestoneCreateRequest extends the milestone creation with the
+// notification and reminder fields of the API, not supported by the SDK.
+type legacyMilestoneCreateRequest struct {
+	projects.MilestoneCreateRequest
+
+	// Notify indicates whether the responsible parties are notified.
+	Notify *bool `json:"notify,omitempty"`
+	// Reminder indicates whether the responsible parties receive reminders
+	// before the due date.
+	Reminder *bool `json:"reminder,omitempty"`
+}
+
+// HTTPRequest creates an HTTP request for the legacyMilestoneCreateRequest.
+func (m legacyMilestoneCreateRequest) HTTPRequest(ctx context.Context, server string) (*http.Request, error) {
+	uri := fmt.Sprintf("%s/projects/%d/milestones.json", server, m.Path.ProjectID)
+	return legacyMilestoneHTTPRequest(ctx, http.MethodPost, uri, m)
+}
+
+// legacyMilestoneUpdateRequest extends the milestone update with the
+// notification and reminder fields of the API, not supported by the SDK.
+type legacyMilestoneUpdateRequest struct {
+	projects.MilestoneUpdateRequest
+
+	// Notify indicates whether the responsible parties are notified.
+	Notify *bool `json:"notify,omitempty"`
+	// Reminder indicates whether the responsible parties receive reminders
+	// before the due date.
+	Reminder *bool `json:"reminder,omitempty"`
+}
+
+// HTTPRequest creates an HTTP request for the legacyMilestoneUpdateRequest.
+func (m legacyMilestoneUpdateRequest) HTTPRequest(ctx context.Context, server string) (*http.Request, error) {
+	uri := server + "/milestones/" + strconv.FormatInt(m.Path.ID, 10) + ".json"
+	return legacyMilestoneHTTPRequest(ctx, http.MethodPut, uri, m)
+}
+
+// legacyMilestoneHTTPRequest creates an HTTP request with the milestone as the
+// JSON body, as expected by the v1 API.
+func legacyMilestoneHTTPRequest(ctx context.Context, method, uri string, milestone any) (*http.Request, error) {
+	payload := struct {
+		Milestone any `json:"milestone"`
+	}{Milestone: milestone}
+
+	var body bytes.Buffer
+	if err := json.NewEncoder(&body).Encode(payload); err != nil {
+		return nil, fmt.Errorf("failed to encode milestone request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, uri, &body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+// milestoneCompleteRequest represents the request to mark a milestone as
+// complete or incomplete.
+//
+// https://apidocs.teamwork.com/docs/teamwork/v1/milestones/put-milestones-id-complete-json
+type milestoneCompleteRequest struct {
+	ID        int64
+	Completed bool
+}
+
+// HTTPRequest creates an HTTP request for the milestoneCompleteRequest.
+func (m milestoneCompleteRequest) HTTPRequest(ctx context.Context, server string) (*http.Request, error) {
+	action := "uncomplete"
+	if m.Completed {
+		action = "complete"
+	}
+	uri := server + "/milestones/" + strconv.FormatInt(m.ID, 10) + "/" + action + ".json"
+	return http.NewRequestWithContext(ctx, http.MethodPut, uri, nil)
+}
+
+// milestoneCompleteResponse handles the response of the milestoneCompleteRequest.
+type milestoneCompleteResponse struct{}
+
+// HandleHTTPResponse handles the HTTP response for the
+// milestoneCompleteResponse.
+func (m *milestoneCompleteResponse) HandleHTTPResponse(resp *http.Response) error {
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return twapi.NewHTTPError(resp, "failed to complete milestone")
+	}
+	return nil
+}
@@ -1,7 +1,9 @@
 package twprojects_test
 
 import (
+	"io"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/teamwork/mcp/internal/testutil"
@@ -22,6 +24,8 @@ func TestMilestoneCreate(t *testing.T) {
 		},
 		"tasklist_ids": []float64{8, 9},
 		"tag_ids":      []float64{10, 11, 12},
+		"notify":       true,
+		"reminder":     true,
 	})
 }
 
@@ -39,9 +43,57 @@ func TestMilestoneUpdate(t *testing.T) {
 		},
 		"tasklist_ids": []float64{8, 9},
 		"tag_ids":      []float64{10, 11, 12},
+		"notify":       true,
+		"reminder":     true,
 	})
 }
 
+func TestMilestoneUpdateReminder(t *testing.T) {
+	var body string
+	mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(r *http.Request) (int, []byte) {
+		content, _ := io.ReadAll(r.Body)
+		body = string(content)
+		return http.StatusOK, []byte(`{}`)
+	})
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodMilestoneUpdate.String(), map[string]any{
+		"id":       float64(123),
+		"reminder": true,
+	})
+	if !strings.Contains(body, `"reminder":true`) {
+		t.Errorf("expected reminder in the request body, got %s", body)
+	}
+}
+
+func TestMilestoneComplete(t *testing.T) {
+	tests := []struct {
+		name      string
+		arguments map[string]any
+		path      string
+	}{{
+		name:      "complete",
+		arguments: map[string]any{"id": float64(123)},
+		path:      "/milestones/123/complete.json",
+	}, {
+		name:      "reopen",
+		arguments: map[string]any{"id": float64(123), "completed": false},
+		path:      "/milestones/123/uncomplete.json",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var path string
+			mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(r *http.Request) (int, []byte) {
+				path = r.URL.Path
+				return http.StatusOK, []byte(`{"STATUS":"OK"}`)
+			})
+			testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodMilestoneComplete.String(), tt.arguments)
+			if !strings.HasSuffix(path, tt.path) {
+				t.Errorf("expected request to %s, got %s", tt.path, path)
+			}
+		})
+	}
+}
+
 func TestMilestoneDelete(t *testing.T) {
 	mcpServer := mcpServerMock(t, http.StatusOK, []byte(`{}`))
 	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodMilestoneDelete.String(), map[string]any{
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/google/jsonschema-go/jsonschema"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -89,7 +90,7 @@ func NotebookCreate(engine *twapi.Engine) toolsets.ToolWrapper {
 					"type": {
 						Type:        "string",
 						Description: "The type of the notebook. Valid values are 'MARKDOWN' and 'HTML'.",
-						Enum:        []any{"MARKDOWN", "HTML"},
+						Enum:        enumSchemaValues(notebookTypes),
 					},
 					"tag_ids": {
 						Type:        "array",
@@ -115,10 +116,7 @@ func NotebookCreate(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalPointerParam(&notebookCreateRequest.Description, "description"),
 				helpers.RequiredParam(&notebookCreateRequest.Contents, "contents"),
 				helpers.RequiredParam(&notebookCreateRequest.Type, "type",
-					helpers.RestrictValues(
-						projects.NotebookTypeMarkdown,
-						projects.NotebookTypeHTML,
-					),
+					helpers.RestrictValues(notebookTypes...),
 				),
 				helpers.OptionalNumericListParam(&notebookCreateRequest.TagIDs, "tag_ids"),
 			)
@@ -147,6 +145,7 @@ func NotebookUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 			InputSchema: &jsonschema.Schema{
 				Type: "object",
 				Properties: map[string]*jsonschema.Schema{
+					helpers.ExpectedUpdatedAtParam: helpers.ExpectedUpdatedAtSchema("notebook"),
 					"id": {
 						Type:        "integer",
 						Description: "The ID of the notebook to update.",
@@ -166,7 +165,7 @@ func NotebookUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 					"type": {
 						Type:        "string",
 						Description: "The type of the notebook. Valid values are 'MARKDOWN' and 'HTML'.",
-						Enum:        []any{"MARKDOWN", "HTML"},
+						Enum:        enumSchemaValues(notebookTypes),
 					},
 					"tag_ids": {
 						Type:        "array",
@@ -181,6 +180,7 @@ func NotebookUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 		},
 		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			var notebookUpdateRequest projects.NotebookUpdateRequest
+			var expectedUpdatedAt *time.Time
 
 			var arguments map[string]any
 			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
@@ -188,14 +188,12 @@ func NotebookUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 			}
 			err := helpers.ParamGroup(arguments,
 				helpers.RequiredNumericParam(&notebookUpdateRequest.Path.ID, "id"),
+				helpers.OptionalTimePointerParam(&expectedUpdatedAt, helpers.ExpectedUpdatedAtParam),
 				helpers.OptionalPointerParam(&notebookUpdateRequest.Name, "name"),
 				helpers.OptionalPointerParam(&notebookUpdateRequest.Description, "description"),
 				helpers.OptionalPointerParam(&notebookUpdateRequest.Contents, "contents"),
 				helpers.OptionalPointerParam(&notebookUpdateRequest.Type, "type",
-					helpers.RestrictValues(
-						projects.NotebookTypeMarkdown,
-						projects.NotebookTypeHTML,
-					),
+					helpers.RestrictValues(notebookTypes...),
 				),
 				helpers.OptionalNumericListParam(&notebookUpdateRequest.TagIDs, "tag_ids"),
 			)
@@ -203,6 +201,18 @@ func NotebookUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
 			}
 
+			if expectedUpdatedAt != nil {
+				notebookResponse, err := projects.NotebookGet(ctx, engine, projects.NewNotebookGetRequest(notebookUpdateRequest.Path.ID))
+				if err != nil {
+					return helpers.HandleAPIError(err, "failed to get notebook")
+				}
+				conflict := helpers.CheckConflict("notebook", notebookUpdateRequest.Path.ID, *expectedUpdatedAt,
+					notebookResponse.Notebook.UpdatedAt)
+				if conflict != nil {
+					return conflict, nil
+				}
+			}
+
 			_, err = projects.NotebookUpdate(ctx, engine, notebookUpdateRequest)
 			if err != nil {
 				return helpers.HandleAPIError(err, "failed to update notebook")
@@ -300,12 +310,14 @@ func NotebookGet(engine *twapi.Engine) toolsets.ToolWrapper {
 			if err != nil {
 				return nil, err
 			}
+			linked := helpers.WebLinker(ctx, encoded,
+				helpers.WebLinkerWithIDPathBuilder("/app/notebooks"),
+			)
 			return &mcp.CallToolResult{
+				Meta: helpers.WebLinkedEntitiesMeta(linked),
 				Content: []mcp.Content{
 					&mcp.TextContent{
-						Text: string(helpers.WebLinker(ctx, encoded,
-							helpers.WebLinkerWithIDPathBuilder("/app/notebooks"),
-						)),
+						Text: string(linked),
 					},
 				},
 				StructuredContent: notebook,
@@ -367,12 +379,14 @@ func NotebookList(engine *twapi.Engine) toolsets.ToolWrapper {
 						Type:        "integer",
 						Description: "Number of results per page for pagination.",
 					},
+					"count_only": countOnlySchema(),
 				},
 			},
 			OutputSchema: notebookListOutputSchema,
 		},
 		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			var notebookListRequest projects.NotebookListRequest
+			var countOnly bool
 
 			var arguments map[string]any
 			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
@@ -386,11 +400,16 @@ func NotebookList(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalPointerParam(&notebookListRequest.Filters.IncludeContents, "include_contents"),
 				helpers.OptionalNumericParam(&notebookListRequest.Filters.Page, "page"),
 				helpers.OptionalNumericParam(&notebookListRequest.Filters.PageSize, "page_size"),
+				helpers.OptionalParam(&countOnly, "count_only"),
 			)
 			if err != nil {
 				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
 			}
 
+			if countOnly {
+				return listCount(ctx, engine, notebookListRequest)
+			}
+
 			notebookList, err := projects.NotebookList(ctx, engine, notebookListRequest)
 			if err != nil {
 				return helpers.HandleAPIError(err, "failed to list notebooks")
@@ -400,12 +419,14 @@ func NotebookList(engine *twapi.Engine) toolsets.ToolWrapper {
 			if err != nil {
 				return nil, err
 			}
+			linked := helpers.WebLinker(ctx, encoded,
+				helpers.WebLinkerWithIDPathBuilder("/app/notebooks"),
+			)
 			return &mcp.CallToolResult{
+				Meta: helpers.WebLinkedEntitiesMeta(linked),
 				Content: []mcp.Content{
 					&mcp.TextContent{
-						Text: string(helpers.WebLinker(ctx, encoded,
-							helpers.WebLinkerWithIDPathBuilder("/app/notebooks"),
-						)),
+						Text: string(linked),
 					},
 				},
 				StructuredContent: notebookList,
@@ -0,0 +1,55 @@
+package twprojects
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/helpers"
+	"github.com/teamwork/mcp/internal/toolsets"
+	twapi "github.com/teamwork/twapi-go-sdk"
+)
+
+type passthroughKey struct{}
+
+// passthroughMiddleware enables the passthrough mode for the tool calls. The
+// get and list tools supporting it return the API response body as is,
+// skipping the decoding, the re-encoding and the web links, which are costly
+// for large responses.
+func passthroughMiddleware() toolsets.ToolMiddleware {
+	return func(next mcp.ToolHandler) mcp.ToolHandler {
+		return func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return next(context.WithValue(ctx, passthroughKey{}, true), request)
+		}
+	}
+}
+
+// passthroughEnabled checks if the tool call is in passthrough mode.
+func passthroughEnabled(ctx context.Context) bool {
+	enabled, _ := ctx.Value(passthroughKey{}).(bool)
+	return enabled
+}
+
+// passthrough executes the request with the additional query parameters,
+// returning the response body as the tool result. The message describes the
+// failure when the request fails.
+func passthrough[R twapi.HTTPRequester](
+	ctx context.Context,
+	engine *twapi.Engine,
+	request R,
+	query url.Values,
+	message string,
+) (*mcp.CallToolResult, error) {
+	response, err := executeWithQuery[*rawResponse](ctx, engine, request, query)
+	if err != nil {
+		return helpers.HandleAPIError(err, message)
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: string(response.body),
+			},
+		},
+		StructuredContent: response.body,
+	}, nil
+}
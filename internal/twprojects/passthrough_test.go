@@ -0,0 +1,35 @@
+package twprojects_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/testutil"
+	"github.com/teamwork/mcp/internal/twprojects"
+)
+
+func TestTaskListPassthrough(t *testing.T) {
+	response := `{"tasks":[{"id":1,"name":"Example"}],"meta":{"page":{"hasMore":false}},"unknown":true}`
+	mcpServer := testutil.ProjectsMCPServerMock(t, http.StatusOK, []byte(response),
+		twprojects.WithPassthrough(true),
+	)
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTaskList.String(), map[string]any{},
+		testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+			toolResult, ok := result.(*mcp.CallToolResult)
+			if !ok {
+				t.Fatalf("unexpected result type: %T", result)
+			}
+			if toolResult.IsError {
+				t.Fatalf("tool failed to execute: %v", toolResult.Content)
+			}
+			// the response keeps the fields unknown to the SDK, only adding the
+			// pagination metadata
+			expected := `{"meta":{"page":{"hasMore":false}},"pagination":{"page":1,"page_size":50,"has_more":false},` +
+				`"tasks":[{"id":1,"name":"Example"}],"unknown":true}`
+			if text := toolResult.Content[0].(*mcp.TextContent).Text; text != expected {
+				t.Errorf("expected %s, got %s", expected, text)
+			}
+		}),
+	)
+}
@@ -0,0 +1,330 @@
+package twprojects
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/helpers"
+	"github.com/teamwork/mcp/internal/toolsets"
+	twapi "github.com/teamwork/twapi-go-sdk"
+	"github.com/teamwork/twapi-go-sdk/projects"
+)
+
+// List of methods available in the Teamwork.com MCP service.
+//
+// The naming convention for methods follows a pattern described here:
+// https://github.com/github/github-mcp-server/issues/333
+const (
+	MethodPersonFind toolsets.Method = "twprojects-find_person"
+)
+
+const (
+	// personFindDefaultResults is the default number of candidates returned.
+	personFindDefaultResults = 5
+	// personFindMaxUsers is the maximum number of people of the installation
+	// compared with the query.
+	personFindMaxUsers = 5000
+	// personFindMinScore is the minimum score of the candidates returned.
+	personFindMinScore = 0.5
+)
+
+// personNicknames are groups of names commonly used for the same person, so a
+// query for "Bob" finds "Robert".
+var personNicknames = [][]string{
+	{"alexander", "alex", "alexandra", "sandy", "sasha"},
+	{"andrew", "andy", "drew"},
+	{"anthony", "tony"},
+	{"benjamin", "ben", "benny"},
+	{"catherine", "katherine", "kathryn", "cathy", "kathy", "kate", "katie", "kat"},
+	{"charles", "charlie", "chuck"},
+	{"christopher", "chris", "kit"},
+	{"daniel", "dan", "danny"},
+	{"david", "dave", "davy"},
+	{"edward", "ed", "eddie", "ted", "ned"},
+	{"elizabeth", "liz", "lizzie", "beth", "betty", "eliza"},
+	{"francis", "frances", "frank", "fran"},
+	{"gregory", "greg"},
+	{"james", "jim", "jimmy", "jamie"},
+	{"jennifer", "jen", "jenny"},
+	{"jonathan", "jon", "jonny"},
+	{"joseph", "joe", "joey"},
+	{"margaret", "maggie", "meg", "peggy"},
+	{"matthew", "matt"},
+	{"michael", "mike", "mick", "mikey"},
+	{"nicholas", "nick", "nicky"},
+	{"patrick", "pat", "paddy"},
+	{"patricia", "pat", "patty", "trish"},
+	{"peter", "pete"},
+	{"philip", "phillip", "phil"},
+	{"rebecca", "becky", "becca"},
+	{"richard", "rick", "rich", "dick"},
+	{"robert", "rob", "bob", "bobby", "robbie"},
+	{"samuel", "samantha", "sam", "sammy"},
+	{"stephen", "steven", "steve"},
+	{"susan", "sue", "susie"},
+	{"thomas", "tom", "tommy"},
+	{"timothy", "tim", "timmy"},
+	{"victoria", "vicky", "tori"},
+	{"william", "will", "bill", "billy", "liam"},
+}
+
+// personNicknameIndex indexes the nickname groups by name.
+var personNicknameIndex = func() map[string][]int {
+	index := make(map[string][]int)
+	for i, group := range personNicknames {
+		for _, name := range group {
+			index[name] = append(index[name], i)
+		}
+	}
+	return index
+}()
+
+func init() {
+	// register the toolset methods
+	toolsets.RegisterMethod(MethodPersonFind)
+}
+
+// personCandidate is a person matching the query, with how well it matches.
+type personCandidate struct {
+	ID        int64   `json:"id"`
+	Name      string  `json:"name"`
+	Email     string  `json:"email"`
+	Title     string  `json:"title,omitempty"`
+	CompanyID int64   `json:"companyId,omitempty"`
+	Type      string  `json:"type,omitempty"`
+	Score     float64 `json:"score"`
+}
+
+// PersonFind searches the people of the installation with fuzzy matching in
+// Teamwork.com.
+func PersonFind(engine *twapi.Engine) toolsets.ToolWrapper {
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name: string(MethodPersonFind),
+			Description: "Find a person in Teamwork.com by a name or email that may be partial, misspelled or a " +
+				"nickname (e.g. \"Bob\" for \"Robert\", \"jon smth\" or \"j.smith\"), searching all the people of the " +
+				"installation. The candidates are ranked by a score from 0 to 1, the best first. Use it to resolve " +
+				"the user ID of the person mentioned in a request when the user list search doesn't find them.",
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "Find Person",
+				ReadOnlyHint: true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"query": {
+						Type:        "string",
+						Description: "The name, part of the name, nickname or email of the person.",
+					},
+					"max_results": {
+						Type: "integer",
+						Description: fmt.Sprintf("The maximum number of candidates. Defaults to %d.",
+							personFindDefaultResults),
+					},
+				},
+				Required: []string{"query"},
+			},
+		},
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var query string
+			maxResults := int64(personFindDefaultResults)
+
+			var arguments map[string]any
+			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("failed to decode request: %s", err.Error())), nil
+			}
+			err := helpers.ParamGroup(arguments,
+				helpers.RequiredParam(&query, "query"),
+				helpers.OptionalNumericParam(&maxResults, "max_results"),
+			)
+			if err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
+			}
+			if len(personTokens(query)) == 0 {
+				return helpers.NewToolResultTextError("invalid parameters: query must contain a name or email"), nil
+			}
+			if maxResults <= 0 {
+				return helpers.NewToolResultTextError("invalid parameters: max_results must be positive"), nil
+			}
+
+			users, truncated, err := helpers.FetchAllPages(ctx, helpers.AutoPagination{
+				PageSize: 100,
+				MaxItems: personFindMaxUsers,
+			}, func(ctx context.Context, page, pageSize int64) ([]projects.User, bool, error) {
+				userListRequest := projects.NewUserListRequest()
+				userListRequest.Filters.Page = page
+				userListRequest.Filters.PageSize = pageSize
+
+				response, err := projects.UserList(ctx, engine, userListRequest)
+				if err != nil {
+					return nil, false, err
+				}
+				return response.Users, response.Meta.Page.HasMore, nil
+			})
+			if err != nil {
+				return helpers.HandleAPIError(err, "failed to list users")
+			}
+
+			candidates := make([]personCandidate, 0)
+			for _, user := range users {
+				if user.Deleted {
+					continue
+				}
+				score := personScore(query, user)
+				if score < personFindMinScore {
+					continue
+				}
+				candidate := personCandidate{
+					ID:        user.ID,
+					Name:      strings.TrimSpace(user.FirstName + " " + user.LastName),
+					Email:     user.Email,
+					CompanyID: user.Company.ID,
+					Type:      user.Type,
+					Score:     math.Round(score*100) / 100,
+				}
+				if user.Title != nil {
+					candidate.Title = *user.Title
+				}
+				candidates = append(candidates, candidate)
+			}
+			slices.SortStableFunc(candidates, func(a, b personCandidate) int {
+				return cmp.Or(cmp.Compare(b.Score, a.Score), cmp.Compare(a.Name, b.Name))
+			})
+
+			encoded, err := json.Marshal(struct {
+				Candidates []personCandidate `json:"candidates"`
+				// Truncated is true when not all people of the installation were
+				// compared.
+				Truncated bool `json:"truncated,omitempty"`
+			}{
+				Candidates: candidates[:min(len(candidates), int(maxResults))],
+				Truncated:  truncated,
+			})
+			if err != nil {
+				return nil, err
+			}
+			return helpers.NewToolResultText("%s", encoded), nil
+		},
+	}
+}
+
+// personScore returns how well the person matches the query, from 0 to 1. An
+// exact email is a perfect match. Otherwise, each word of the query is matched
+// with the closest word of the name or the email username, and the score is the
+// average of the words.
+func personScore(query string, user projects.User) float64 {
+	query = strings.TrimSpace(query)
+	if strings.Contains(query, "@") && strings.EqualFold(query, user.Email) {
+		return 1
+	}
+	if strings.Contains(query, "@") {
+		// the domain is shared by many people, so only the username is compared
+		query, _, _ = strings.Cut(query, "@")
+	}
+
+	username, _, _ := strings.Cut(user.Email, "@")
+	names := slices.Concat(personTokens(user.FirstName), personTokens(user.LastName))
+	words := slices.Concat(names, personTokens(username))
+	if len(words) == 0 {
+		return 0
+	}
+
+	tokens := personTokens(query)
+	if len(tokens) == 0 {
+		return 0
+	}
+	var total float64
+	for _, token := range tokens {
+		var best float64
+		for _, word := range words {
+			best = max(best, personWordScore(token, word))
+		}
+		total += best
+	}
+	score := total / float64(len(tokens))
+
+	// the full name typed in a single word (e.g. "johnsmith")
+	if len(tokens) == 1 && len(names) > 1 && tokens[0] == strings.Join(names, "") {
+		score = 1
+	}
+	return score
+}
+
+// personWordScore returns how well a word of the query matches a word of the
+// person: exact, nickname, prefix (e.g. initials or partial names), substring
+// or with typos.
+func personWordScore(token, word string) float64 {
+	switch {
+	case token == word:
+		return 1
+	case personNicknamesMatch(token, word):
+		return 0.9
+	case strings.HasPrefix(word, token):
+		if len(token) == 1 {
+			// initials are weak evidence
+			return 0.6
+		}
+		return 0.85
+	case len(token) >= 3 && strings.Contains(word, token):
+		return 0.7
+	}
+	if len(token) < 3 {
+		return 0
+	}
+	distance := levenshtein(token, word)
+	similarity := 1 - float64(distance)/float64(max(len([]rune(token)), len([]rune(word))))
+	if similarity < 0.6 {
+		return 0
+	}
+	return similarity * 0.85
+}
+
+// personNicknamesMatch reports whether both names belong to the same nickname
+// group.
+func personNicknamesMatch(a, b string) bool {
+	for _, group := range personNicknameIndex[a] {
+		if slices.Contains(personNicknameIndex[b], group) {
+			return true
+		}
+	}
+	return false
+}
+
+// personTokens splits a name or email username in lowercase words, so
+// "Mary-Jane" and "mary.jane" both match "mary jane".
+func personTokens(value string) []string {
+	return strings.FieldsFunc(strings.ToLower(value), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// levenshtein returns the number of single character edits needed to change a
+// into b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
@@ -0,0 +1,85 @@
+package twprojects_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/testutil"
+	"github.com/teamwork/mcp/internal/twprojects"
+)
+
+func TestPersonFind(t *testing.T) {
+	mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(r *http.Request) (int, []byte) {
+		if r.URL.Path != "/projects/api/v3/people.json" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			return http.StatusNotFound, []byte(`{}`)
+		}
+		return http.StatusOK, []byte(`{"people":[` +
+			`{"id":1,"firstName":"Robert","lastName":"Smith","email":"robert.smith@example.com"},` +
+			`{"id":2,"firstName":"Roberta","lastName":"Jones","email":"rjones@example.com"},` +
+			`{"id":3,"firstName":"Alice","lastName":"Smithson","email":"alice@example.com"},` +
+			`{"id":4,"firstName":"Bob","lastName":"Smith","email":"bob@example.com","deleted":true}],` +
+			`"meta":{"page":{"hasMore":false}}}`)
+	})
+
+	tests := []struct {
+		name  string
+		query string
+		want  []int64
+	}{{
+		name:  "nickname",
+		query: "Bob Smith",
+		want:  []int64{1},
+	}, {
+		name:  "partial name",
+		query: "rob",
+		want:  []int64{1, 2},
+	}, {
+		name:  "typo",
+		query: "Alcie Smithsen",
+		want:  []int64{3},
+	}, {
+		name:  "email",
+		query: "rjones@example.com",
+		want:  []int64{2},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodPersonFind.String(), map[string]any{
+				"query": tt.query,
+			}, testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+				toolResult, ok := result.(*mcp.CallToolResult)
+				if !ok {
+					t.Fatalf("unexpected result type: %T", result)
+				}
+				if toolResult.IsError {
+					t.Fatalf("tool failed to execute: %v", toolResult.Content)
+				}
+				var found struct {
+					Candidates []struct {
+						ID    int64   `json:"id"`
+						Score float64 `json:"score"`
+					} `json:"candidates"`
+				}
+				if err := json.Unmarshal([]byte(toolResult.Content[0].(*mcp.TextContent).Text), &found); err != nil {
+					t.Fatalf("failed to decode result: %v", err)
+				}
+				if len(found.Candidates) < len(tt.want) {
+					t.Fatalf("expected candidates %v, got %+v", tt.want, found.Candidates)
+				}
+				for i, id := range tt.want {
+					if found.Candidates[i].ID != id {
+						t.Errorf("expected candidate %d to be %d, got %+v", i, id, found.Candidates)
+					}
+				}
+				for _, candidate := range found.Candidates {
+					if candidate.ID == 4 {
+						t.Errorf("deleted person returned: %+v", found.Candidates)
+					}
+				}
+			}))
+		})
+	}
+}
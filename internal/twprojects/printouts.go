@@ -0,0 +1,296 @@
+package twprojects
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/helpers"
+	"github.com/teamwork/mcp/internal/printout"
+	"github.com/teamwork/mcp/internal/toolsets"
+	twapi "github.com/teamwork/twapi-go-sdk"
+	"github.com/teamwork/twapi-go-sdk/projects"
+)
+
+// List of methods available in the Teamwork.com MCP service.
+//
+// The naming convention for methods follows a pattern described here:
+// https://github.com/github/github-mcp-server/issues/333
+const (
+	MethodPrintoutExport toolsets.Method = "twprojects-export_printout"
+)
+
+// printoutMaxTasks is the maximum number of tasks listed in a milestone report.
+const printoutMaxTasks = 500
+
+// printoutDateFormat is the format of the dates in the printouts.
+const printoutDateFormat = "2006-01-02"
+
+func init() {
+	// register the toolset methods
+	toolsets.RegisterMethod(MethodPrintoutExport)
+}
+
+// PrintoutExport renders a task or a milestone report as a PDF or standalone
+// HTML document, returned as a link to its resource in the exports store.
+func PrintoutExport(engine *twapi.Engine, exports *helpers.SpillStore) toolsets.ToolWrapper {
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name: string(MethodPrintoutExport),
+			Description: "Export a task, or a milestone report with its tasks, from Teamwork.com as a PDF or standalone " +
+				"HTML document, e.g. to share it with a client without a Teamwork.com account. The document is " +
+				"returned as a link to a temporary resource.",
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "Export Printout",
+				ReadOnlyHint: true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"entity_type": {
+						Type:        "string",
+						Description: "The type of the entity to export.",
+						Enum:        enumSchemaValues(printoutEntityTypes),
+					},
+					"id": {
+						Type:        "integer",
+						Description: "The ID of the task or milestone.",
+					},
+					"format": {
+						Type:        "string",
+						Description: "The file format of the document. Defaults to pdf.",
+						Enum:        enumSchemaValues(printoutFormats),
+					},
+				},
+				Required: []string{"entity_type", "id"},
+			},
+		},
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var entityType string
+			var id int64
+			format := "pdf"
+
+			var arguments map[string]any
+			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("failed to decode request: %s", err.Error())), nil
+			}
+			err := helpers.ParamGroup(arguments,
+				helpers.RequiredParam(&entityType, "entity_type", helpers.RestrictValues(printoutEntityTypes...)),
+				helpers.RequiredNumericParam(&id, "id"),
+				helpers.OptionalParam(&format, "format", helpers.RestrictValues(printoutFormats...)),
+			)
+			if err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
+			}
+
+			var document printout.Document
+			switch entityType {
+			case "task":
+				document, err = taskPrintout(ctx, engine, id)
+			case "milestone":
+				document, err = milestonePrintout(ctx, engine, id)
+			}
+			if err != nil {
+				return helpers.HandleAPIError(err, "failed to load "+entityType)
+			}
+
+			writer := exports.NewWriter(ctx)
+			name := entityType + "-" + strconv.FormatInt(id, 10) + "." + format
+			if format == "html" {
+				if err := printout.HTML(writer, document); err != nil {
+					return nil, err
+				}
+				return writer.Link(name, "text/html")
+			}
+			if err := printout.PDF(writer, document); err != nil {
+				return nil, err
+			}
+			return writer.Link(name, "application/pdf")
+		},
+	}
+}
+
+// taskPrintout loads a task, with its tasklist and project, into a printout
+// document.
+func taskPrintout(ctx context.Context, engine *twapi.Engine, taskID int64) (printout.Document, error) {
+	taskResponse, err := projects.TaskGet(ctx, engine, projects.NewTaskGetRequest(taskID))
+	if err != nil {
+		return printout.Document{}, err
+	}
+	task := taskResponse.Task
+
+	subtitle, err := printoutTasklistPath(ctx, engine, task.Tasklist.ID)
+	if err != nil {
+		return printout.Document{}, err
+	}
+	assignees, err := printoutAssignees(ctx, engine, task.Assignees)
+	if err != nil {
+		return printout.Document{}, err
+	}
+
+	document := printout.Document{
+		Title:    task.Name,
+		Subtitle: subtitle,
+		Fields: []printout.Field{
+			{Label: "Status", Value: task.Status},
+			{Label: "Progress", Value: fmt.Sprintf("%d%%", task.Progress)},
+		},
+	}
+	if task.Priority != nil && *task.Priority != "" {
+		document.Fields = append(document.Fields, printout.Field{Label: "Priority", Value: *task.Priority})
+	}
+	if task.StartAt != nil {
+		document.Fields = append(document.Fields, printout.Field{
+			Label: "Start date",
+			Value: task.StartAt.Format(printoutDateFormat),
+		})
+	}
+	if task.DueAt != nil {
+		document.Fields = append(document.Fields, printout.Field{
+			Label: "Due date",
+			Value: task.DueAt.Format(printoutDateFormat),
+		})
+	}
+	if task.EstimatedMinutes > 0 {
+		document.Fields = append(document.Fields, printout.Field{
+			Label: "Estimate",
+			Value: (time.Duration(task.EstimatedMinutes) * time.Minute).String(),
+		})
+	}
+	if assignees != "" {
+		document.Fields = append(document.Fields, printout.Field{Label: "Assignees", Value: assignees})
+	}
+	if task.Description != nil {
+		if paragraphs := printout.PlainText(*task.Description); len(paragraphs) > 0 {
+			document.Sections = append(document.Sections, printout.Section{
+				Title:      "Description",
+				Paragraphs: paragraphs,
+			})
+		}
+	}
+	return document, nil
+}
+
+// milestonePrintout loads a milestone, with the tasks of its tasklists, into a
+// printout document.
+func milestonePrintout(ctx context.Context, engine *twapi.Engine, milestoneID int64) (printout.Document, error) {
+	milestoneResponse, err := projects.MilestoneGet(ctx, engine, projects.NewMilestoneGetRequest(milestoneID))
+	if err != nil {
+		return printout.Document{}, err
+	}
+	milestone := milestoneResponse.Milestone
+
+	projectResponse, err := projects.ProjectGet(ctx, engine, projects.NewProjectGetRequest(milestone.Project.ID))
+	if err != nil {
+		return printout.Document{}, err
+	}
+	responsible, err := printoutAssignees(ctx, engine, milestone.ResponsibleParties)
+	if err != nil {
+		return printout.Document{}, err
+	}
+
+	document := printout.Document{
+		Title:    milestone.Name,
+		Subtitle: projectResponse.Project.Name,
+		Fields: []printout.Field{
+			{Label: "Status", Value: milestone.Status},
+			{Label: "Due date", Value: milestone.DueAt.Format(printoutDateFormat)},
+		},
+	}
+	if responsible != "" {
+		document.Fields = append(document.Fields, printout.Field{Label: "Responsible", Value: responsible})
+	}
+	if paragraphs := printout.PlainText(milestone.Description); len(paragraphs) > 0 {
+		document.Sections = append(document.Sections, printout.Section{
+			Title:      "Description",
+			Paragraphs: paragraphs,
+		})
+	}
+
+	var total, completed int
+	for _, tasklist := range milestone.Tasklists {
+		tasklistResponse, err := projects.TasklistGet(ctx, engine, projects.NewTasklistGetRequest(tasklist.ID))
+		if err != nil {
+			return printout.Document{}, err
+		}
+		tasks, _, err := helpers.FetchAllPages(ctx, helpers.AutoPagination{PageSize: 100, MaxItems: printoutMaxTasks},
+			func(ctx context.Context, page, pageSize int64) ([]projects.Task, bool, error) {
+				taskListRequest := projects.NewTaskListRequest()
+				taskListRequest.Path.TasklistID = tasklist.ID
+				taskListRequest.Filters.Page = page
+				taskListRequest.Filters.PageSize = pageSize
+
+				response, err := executeWithQuery[*projects.TaskListResponse](ctx, engine, taskListRequest, url.Values{
+					"includeCompletedTasks": []string{"true"},
+				})
+				if err != nil {
+					return nil, false, err
+				}
+				return response.Tasks, response.Meta.Page.HasMore, nil
+			})
+		if err != nil {
+			return printout.Document{}, err
+		}
+
+		section := printout.Section{Title: tasklistResponse.Tasklist.Name}
+		for _, task := range tasks {
+			item := "[" + task.Status + "] " + task.Name
+			if task.DueAt != nil {
+				item += " (due " + task.DueAt.Format(printoutDateFormat) + ")"
+			}
+			section.Items = append(section.Items, item)
+			if task.Status == "completed" {
+				completed++
+			}
+		}
+		if len(section.Items) == 0 {
+			section.Paragraphs = []string{"No tasks."}
+		}
+		total += len(tasks)
+		document.Sections = append(document.Sections, section)
+	}
+	document.Fields = append(document.Fields, printout.Field{
+		Label: "Tasks",
+		Value: fmt.Sprintf("%d of %d completed", completed, total),
+	})
+	return document, nil
+}
+
+// printoutTasklistPath returns the project and tasklist names of a task.
+func printoutTasklistPath(ctx context.Context, engine *twapi.Engine, tasklistID int64) (string, error) {
+	tasklistResponse, err := projects.TasklistGet(ctx, engine, projects.NewTasklistGetRequest(tasklistID))
+	if err != nil {
+		return "", err
+	}
+	projectResponse, err := projects.ProjectGet(ctx, engine,
+		projects.NewProjectGetRequest(tasklistResponse.Tasklist.Project.ID))
+	if err != nil {
+		return "", err
+	}
+	return projectResponse.Project.Name + " / " + tasklistResponse.Tasklist.Name, nil
+}
+
+// printoutAssignees returns the names of the assigned users, as the reader of
+// the printout may not know them by ID. Teams and companies are listed by
+// their type, as they rarely matter outside the installation.
+func printoutAssignees(ctx context.Context, engine *twapi.Engine, assignees []twapi.Relationship) (string, error) {
+	names := make([]string, 0, len(assignees))
+	for _, assignee := range assignees {
+		if assignee.Type != "users" {
+			names = append(names, fmt.Sprintf("%s %d", strings.TrimSuffix(assignee.Type, "s"), assignee.ID))
+			continue
+		}
+		userResponse, err := projects.UserGet(ctx, engine, projects.NewUserGetRequest(assignee.ID))
+		if err != nil {
+			return "", err
+		}
+		names = append(names, strings.TrimSpace(userResponse.User.FirstName+" "+userResponse.User.LastName))
+	}
+	return strings.Join(names, ", "), nil
+}
@@ -0,0 +1,53 @@
+package twprojects_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/testutil"
+	"github.com/teamwork/mcp/internal/twprojects"
+)
+
+func TestPrintoutExport(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(r *http.Request) (int, []byte) {
+		switch r.URL.Path {
+		case "/projects/api/v3/tasks/123.json":
+			return http.StatusOK, []byte(`{"task":{"id":123,"name":"Launch website","status":"new","progress":50,` +
+				`"description":"<p>Publish the site.</p>","dueDate":"2025-03-01T00:00:00Z","tasklist":{"id":5},` +
+				`"assignees":[{"id":7,"type":"users"}]}}`)
+		case "/projects/api/v3/tasklists/5.json":
+			return http.StatusOK, []byte(`{"tasklist":{"id":5,"name":"Release","project":{"id":9}}}`)
+		case "/projects/api/v3/projects/9.json":
+			return http.StatusOK, []byte(`{"project":{"id":9,"name":"Marketing"}}`)
+		case "/projects/api/v3/people/7.json":
+			return http.StatusOK, []byte(`{"person":{"id":7,"firstName":"Ana","lastName":"Silva"}}`)
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			return http.StatusNotFound, nil
+		}
+	})
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodPrintoutExport.String(), map[string]any{
+		"entity_type": "task",
+		"id":          float64(123),
+		"format":      "html",
+	}, testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+		toolResult, ok := result.(*mcp.CallToolResult)
+		if !ok {
+			t.Fatalf("unexpected result type: %T", result)
+		}
+		if toolResult.IsError || len(toolResult.Content) != 2 {
+			t.Fatalf("unexpected result: %v", toolResult.Content)
+		}
+		link, ok := toolResult.Content[1].(*mcp.ResourceLink)
+		if !ok {
+			t.Fatalf("unexpected content type %T", toolResult.Content[1])
+		}
+		if !strings.HasPrefix(link.URI, "twprojects://exports/") || link.Name != "task-123.html" ||
+			link.MIMEType != "text/html" {
+			t.Errorf("unexpected resource link %+v", link)
+		}
+	}))
+}
@@ -0,0 +1,359 @@
+package twprojects
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/helpers"
+	"github.com/teamwork/mcp/internal/toolsets"
+	twapi "github.com/teamwork/twapi-go-sdk"
+	"github.com/teamwork/twapi-go-sdk/projects"
+)
+
+// List of methods available in the Teamwork.com MCP service.
+//
+// The naming convention for methods follows a pattern described here:
+// https://github.com/github/github-mcp-server/issues/333
+const (
+	MethodPriorityInbox toolsets.Method = "twprojects-priority_inbox"
+)
+
+const (
+	// priorityInboxDefaultDueHours is the default horizon of the tasks due soon.
+	priorityInboxDefaultDueHours = 48
+	// priorityInboxDefaultMaxItems is the default number of items returned.
+	priorityInboxDefaultMaxItems = 50
+	// priorityInboxMaxNotifications is the maximum number of unread
+	// notifications loaded.
+	priorityInboxMaxNotifications = 500
+	// priorityInboxMaxTasks is the maximum number of tasks with due dates
+	// loaded.
+	priorityInboxMaxTasks = 1000
+)
+
+// Categories of the priority inbox items, from the most to the least urgent.
+const (
+	priorityInboxOverdue      = "overdue"
+	priorityInboxMention      = "mention"
+	priorityInboxDueSoon      = "due_soon"
+	priorityInboxNotification = "notification"
+)
+
+// priorityInboxCategories are the categories of the items, by urgency.
+var priorityInboxCategories = []string{
+	priorityInboxOverdue,
+	priorityInboxMention,
+	priorityInboxDueSoon,
+	priorityInboxNotification,
+}
+
+func init() {
+	// register the toolset methods
+	toolsets.RegisterMethod(MethodPriorityInbox)
+}
+
+// notificationListRequest represents the request to list the unread
+// notifications of the authenticated user.
+type notificationListRequest struct {
+	Page     int64
+	PageSize int64
+}
+
+// HTTPRequest creates an HTTP request for the notificationListRequest.
+func (n notificationListRequest) HTTPRequest(ctx context.Context, server string) (*http.Request, error) {
+	query := url.Values{}
+	query.Set("unreadOnly", "true")
+	query.Set("page", strconv.FormatInt(n.Page, 10))
+	query.Set("pageSize", strconv.FormatInt(n.PageSize, 10))
+	uri := server + "/projects/api/v3/notifications.json?" + query.Encode()
+	return http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+}
+
+// notification is an unread notification of the authenticated user.
+type notification struct {
+	ID          int64     `json:"id"`
+	Type        string    `json:"type"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"createdAt"`
+	Entity      struct {
+		ID   int64  `json:"id"`
+		Type string `json:"type"`
+	} `json:"entity"`
+}
+
+// notificationListResponse contains the unread notifications.
+type notificationListResponse struct {
+	Meta struct {
+		Page struct {
+			HasMore bool `json:"hasMore"`
+		} `json:"page"`
+	} `json:"meta"`
+	Notifications []notification `json:"notifications"`
+}
+
+// HandleHTTPResponse handles the HTTP response for the
+// notificationListResponse. If some unexpected HTTP status code is returned by
+// the API, a twapi.HTTPError is returned.
+func (n *notificationListResponse) HandleHTTPResponse(resp *http.Response) error {
+	if resp.StatusCode != http.StatusOK {
+		return twapi.NewHTTPError(resp, "failed to list notifications")
+	}
+	if err := json.NewDecoder(resp.Body).Decode(n); err != nil {
+		return fmt.Errorf("failed to decode list notifications response: %w", err)
+	}
+	return nil
+}
+
+// priorityInboxItem is an entity needing the attention of the user, with the
+// reasons it is in the inbox.
+type priorityInboxItem struct {
+	Rank            int        `json:"rank"`
+	Category        string     `json:"category"`
+	EntityType      string     `json:"entityType"`
+	EntityID        int64      `json:"entityId"`
+	Title           string     `json:"title"`
+	Reasons         []string   `json:"reasons"`
+	DueAt           *time.Time `json:"dueAt,omitempty"`
+	NotifiedAt      *time.Time `json:"notifiedAt,omitempty"`
+	NotificationIDs []int64    `json:"notificationIds,omitempty"`
+}
+
+// priorityInbox is the ranked list of items needing the attention of the user.
+type priorityInbox struct {
+	UserID      int64               `json:"userId"`
+	GeneratedAt time.Time           `json:"generatedAt"`
+	Counts      map[string]int      `json:"counts"`
+	Total       int                 `json:"total"`
+	Items       []priorityInboxItem `json:"items"`
+	// Unavailable are the sources not supported by the installation.
+	Unavailable []string `json:"unavailable,omitempty"`
+	// Truncated is true when not all notifications or tasks were loaded.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// PriorityInbox merges the unread notifications, mentions, and the overdue and
+// soon due tasks of the user in a single ranked list in Teamwork.com.
+func PriorityInbox(engine *twapi.Engine) toolsets.ToolWrapper {
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name: string(MethodPriorityInbox),
+			Description: "Get the priority inbox of the authenticated user in Teamwork.com: the unread notifications, " +
+				"'@mentions', tasks due soon and overdue tasks merged in a single list, ranked by urgency (overdue, " +
+				"mentions, due soon, other notifications). An entity appearing in many sources is listed once with all " +
+				"the reasons. Use it for daily briefings instead of calling the notification and task tools " +
+				"separately.",
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "Priority Inbox",
+				ReadOnlyHint: true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"due_within_hours": {
+						Type: "integer",
+						Description: fmt.Sprintf("The hours ahead the tasks due soon are searched. Defaults to %d.",
+							priorityInboxDefaultDueHours),
+					},
+					"max_items": {
+						Type:        "integer",
+						Description: fmt.Sprintf("The maximum number of items. Defaults to %d.", priorityInboxDefaultMaxItems),
+					},
+				},
+			},
+		},
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			dueWithinHours := int64(priorityInboxDefaultDueHours)
+			maxItems := int64(priorityInboxDefaultMaxItems)
+
+			var arguments map[string]any
+			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("failed to decode request: %s", err.Error())), nil
+			}
+			err := helpers.ParamGroup(arguments,
+				helpers.OptionalNumericParam(&dueWithinHours, "due_within_hours"),
+				helpers.OptionalNumericParam(&maxItems, "max_items"),
+			)
+			if err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
+			}
+			if dueWithinHours <= 0 || maxItems <= 0 {
+				return helpers.NewToolResultTextError("invalid parameters: due_within_hours and max_items must be " +
+					"positive"), nil
+			}
+
+			me, err := projects.UserGetMe(ctx, engine, projects.NewUserGetMeRequest())
+			if err != nil {
+				return helpers.HandleAPIError(err, "failed to get the authenticated user")
+			}
+			inbox := priorityInbox{
+				UserID:      me.User.ID,
+				GeneratedAt: time.Now().UTC().Truncate(time.Second),
+			}
+
+			notifications, notificationsTruncated, err := helpers.FetchAllPages(ctx, helpers.AutoPagination{
+				PageSize: 100,
+				MaxItems: priorityInboxMaxNotifications,
+			}, func(ctx context.Context, page, pageSize int64) ([]notification, bool, error) {
+				response, err := twapi.Execute[notificationListRequest, *notificationListResponse](ctx, engine,
+					notificationListRequest{Page: page, PageSize: pageSize},
+				)
+				if err != nil {
+					return nil, false, err
+				}
+				return response.Notifications, response.Meta.Page.HasMore, nil
+			})
+			if err != nil {
+				if !isNotFoundError(err) {
+					return helpers.HandleAPIError(err, "failed to list notifications")
+				}
+				inbox.Unavailable = append(inbox.Unavailable, "notifications")
+			}
+
+			tasks, tasksTruncated, err := helpers.FetchAllPages(ctx, helpers.AutoPagination{
+				PageSize: 100,
+				MaxItems: priorityInboxMaxTasks,
+			}, func(ctx context.Context, page, pageSize int64) ([]projects.Task, bool, error) {
+				taskListRequest := projects.NewTaskListRequest()
+				taskListRequest.Filters.AssigneeUserIDs = []int64{me.User.ID}
+				taskListRequest.Filters.Page = page
+				taskListRequest.Filters.PageSize = pageSize
+
+				response, err := executeWithQuery[*projects.TaskListResponse](ctx, engine, taskListRequest, url.Values{
+					"hasDueDate": []string{"true"},
+				})
+				if err != nil {
+					return nil, false, err
+				}
+				return response.Tasks, response.Meta.Page.HasMore, nil
+			})
+			if err != nil {
+				return helpers.HandleAPIError(err, "failed to list the tasks of the user")
+			}
+
+			deadline := inbox.GeneratedAt.Add(time.Duration(dueWithinHours) * time.Hour)
+			items := buildPriorityInbox(notifications, tasks, inbox.GeneratedAt, deadline)
+			inbox.Counts = make(map[string]int, len(priorityInboxCategories))
+			for _, item := range items {
+				inbox.Counts[item.Category]++
+			}
+			inbox.Total = len(items)
+			inbox.Items = items[:min(len(items), int(maxItems))]
+			inbox.Truncated = notificationsTruncated || tasksTruncated
+
+			encoded, err := json.Marshal(inbox)
+			if err != nil {
+				return nil, err
+			}
+			return helpers.NewToolResultText("%s", encoded), nil
+		},
+	}
+}
+
+// buildPriorityInbox merges the notifications and the open tasks overdue or
+// due before the deadline by entity, ranking them by category and, within a
+// category, by due date or most recent notification.
+func buildPriorityInbox(
+	notifications []notification,
+	tasks []projects.Task,
+	now, deadline time.Time,
+) []priorityInboxItem {
+	type entityKey struct {
+		entityType string
+		id         int64
+	}
+	items := make(map[entityKey]*priorityInboxItem)
+	item := func(entityType string, id int64, title string) *priorityInboxItem {
+		key := entityKey{entityType: entityType, id: id}
+		if items[key] == nil {
+			items[key] = &priorityInboxItem{
+				Category:   priorityInboxNotification,
+				EntityType: entityType,
+				EntityID:   id,
+				Title:      title,
+			}
+		}
+		return items[key]
+	}
+	// promote keeps the most urgent category of the item
+	promote := func(item *priorityInboxItem, category string) {
+		if slices.Index(priorityInboxCategories, category) < slices.Index(priorityInboxCategories, item.Category) {
+			item.Category = category
+		}
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	for _, task := range tasks {
+		if task.DueAt == nil || task.Status == "completed" || task.CompletedAt != nil {
+			continue
+		}
+		var category, reason string
+		switch {
+		case task.DueAt.Before(today):
+			category = priorityInboxOverdue
+			reason = fmt.Sprintf("overdue since %s", task.DueAt.Format(time.DateOnly))
+		case !task.DueAt.After(deadline):
+			category = priorityInboxDueSoon
+			reason = fmt.Sprintf("due %s", task.DueAt.Format(time.DateOnly))
+		default:
+			continue
+		}
+		entry := item("task", task.ID, task.Name)
+		entry.DueAt = task.DueAt
+		entry.Reasons = append(entry.Reasons, reason)
+		promote(entry, category)
+	}
+
+	for _, notification := range notifications {
+		entityType, entityID := strings.ToLower(notification.Entity.Type), notification.Entity.ID
+		if entityType == "" || entityID == 0 {
+			entityType, entityID = "notification", notification.ID
+		}
+		entry := item(entityType, entityID, notification.Description)
+		entry.NotificationIDs = append(entry.NotificationIDs, notification.ID)
+		if entry.NotifiedAt == nil || notification.CreatedAt.After(*entry.NotifiedAt) {
+			entry.NotifiedAt = &notification.CreatedAt
+		}
+		if strings.Contains(strings.ToLower(notification.Type), "mention") {
+			entry.Reasons = append(entry.Reasons, "mentioned: "+notification.Description)
+			promote(entry, priorityInboxMention)
+		} else {
+			entry.Reasons = append(entry.Reasons, "unread notification: "+notification.Description)
+		}
+	}
+
+	ranked := make([]priorityInboxItem, 0, len(items))
+	for _, entry := range items {
+		ranked = append(ranked, *entry)
+	}
+	slices.SortFunc(ranked, func(a, b priorityInboxItem) int {
+		if c := cmp.Compare(slices.Index(priorityInboxCategories, a.Category),
+			slices.Index(priorityInboxCategories, b.Category)); c != 0 {
+			return c
+		}
+		if a.DueAt != nil && b.DueAt != nil {
+			if c := a.DueAt.Compare(*b.DueAt); c != 0 {
+				return c
+			}
+		}
+		if a.NotifiedAt != nil && b.NotifiedAt != nil {
+			if c := b.NotifiedAt.Compare(*a.NotifiedAt); c != 0 {
+				return c
+			}
+		}
+		return cmp.Or(cmp.Compare(a.EntityType, b.EntityType), cmp.Compare(a.EntityID, b.EntityID))
+	})
+	for i := range ranked {
+		ranked[i].Rank = i + 1
+	}
+	return ranked
+}
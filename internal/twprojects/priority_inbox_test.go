@@ -0,0 +1,101 @@
+package twprojects_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/testutil"
+	"github.com/teamwork/mcp/internal/twprojects"
+)
+
+func TestPriorityInbox(t *testing.T) {
+	dueSoon := time.Now().UTC().Add(24 * time.Hour).Format(time.RFC3339)
+	dueLater := time.Now().UTC().Add(30 * 24 * time.Hour).Format(time.RFC3339)
+
+	tests := []struct {
+		name               string
+		notificationStatus int
+		expected           []string
+		unavailable        []string
+	}{{
+		name:               "merges and ranks the sources",
+		notificationStatus: http.StatusOK,
+		expected:           []string{"overdue task 1", "mention task 2", "notification comment 9"},
+	}, {
+		name:               "notifications unavailable",
+		notificationStatus: http.StatusNotFound,
+		expected:           []string{"overdue task 1", "due_soon task 2"},
+		unavailable:        []string{"notifications"},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(r *http.Request) (int, []byte) {
+				switch r.URL.Path {
+				case "/projects/api/v3/me.json":
+					return http.StatusOK, []byte(`{"person":{"id":7}}`)
+				case "/projects/api/v3/notifications.json":
+					if r.URL.Query().Get("unreadOnly") != "true" {
+						t.Errorf("expected only unread notifications, got %s", r.URL.RawQuery)
+					}
+					return tt.notificationStatus, []byte(`{"notifications":[` +
+						`{"id":100,"type":"comment.mention","description":"Jane mentioned you",` +
+						`"createdAt":"2025-03-01T10:00:00Z","entity":{"type":"Task","id":2}},` +
+						`{"id":101,"type":"comment.new","description":"New comment",` +
+						`"createdAt":"2025-03-02T10:00:00Z","entity":{"type":"comment","id":9}}],` +
+						`"meta":{"page":{"hasMore":false}}}`)
+				case "/projects/api/v3/tasks.json":
+					query := r.URL.Query()
+					if query.Get("responsiblePartyIds") != "7" || query.Get("hasDueDate") != "true" {
+						t.Errorf("unexpected task query %s", r.URL.RawQuery)
+					}
+					return http.StatusOK, []byte(`{"tasks":[` +
+						`{"id":1,"name":"Overdue","status":"new","dueDate":"2000-01-01T00:00:00Z"},` +
+						`{"id":2,"name":"Soon","status":"new","dueDate":"` + dueSoon + `"},` +
+						`{"id":3,"name":"Later","status":"new","dueDate":"` + dueLater + `"},` +
+						`{"id":4,"name":"Done","status":"completed","dueDate":"2000-01-01T00:00:00Z"}],` +
+						`"meta":{"page":{"hasMore":false}}}`)
+				}
+				t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				return http.StatusNotFound, []byte(`{}`)
+			})
+
+			testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodPriorityInbox.String(), map[string]any{},
+				testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+					toolResult, ok := result.(*mcp.CallToolResult)
+					if !ok {
+						t.Fatalf("unexpected result type: %T", result)
+					}
+					if toolResult.IsError {
+						t.Fatalf("tool failed to execute: %v", toolResult.Content)
+					}
+					var inbox struct {
+						Items []struct {
+							Category   string `json:"category"`
+							EntityType string `json:"entityType"`
+							EntityID   int64  `json:"entityId"`
+						} `json:"items"`
+						Unavailable []string `json:"unavailable"`
+					}
+					if err := json.Unmarshal([]byte(toolResult.Content[0].(*mcp.TextContent).Text), &inbox); err != nil {
+						t.Fatalf("failed to decode result: %v", err)
+					}
+					var items []string
+					for _, item := range inbox.Items {
+						items = append(items, fmt.Sprintf("%s %s %d", item.Category, item.EntityType, item.EntityID))
+					}
+					if !slices.Equal(items, tt.expected) {
+						t.Errorf("expected items %v, got %v", tt.expected, items)
+					}
+					if !slices.Equal(inbox.Unavailable, tt.unavailable) {
+						t.Errorf("expected unavailable sources %v, got %v", tt.unavailable, inbox.Unavailable)
+					}
+				}))
+		})
+	}
+}
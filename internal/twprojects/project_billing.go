@@ -0,0 +1,245 @@
+package twprojects
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/teamwork/mcp/internal/helpers"
+	twapi "github.com/teamwork/twapi-go-sdk"
+)
+
+// projectBillingTypes are the ways the work of a project is billed.
+var projectBillingTypes = []string{"time_and_materials", "fixed_fee", "non_billable"}
+
+// projectBudgetTypes are the types of the budgets of a project. Financial
+// budgets cap the billable amount, and time budgets the minutes logged.
+var projectBudgetTypes = []string{"financial", "time"}
+
+// projectBillingParams are the parameters of the project create and update
+// tools setting the billing of the project. They are financial data, removed
+// from the tools when the finance tools are disabled.
+var projectBillingParams = []string{"billing_type", "rate_card_id", "budget"}
+
+// withProjectBillingProperties adds the billing parameters to the properties of
+// the project create and update tools.
+func withProjectBillingProperties(properties map[string]*jsonschema.Schema) map[string]*jsonschema.Schema {
+	maps.Copy(properties, map[string]*jsonschema.Schema{
+		"billing_type": {
+			Type: "string",
+			Description: "How the work of the project is billed: by the time logged at the rates of the project " +
+				"(time_and_materials), at a fixed price (fixed_fee), or not at all (non_billable).",
+			Enum: enumSchemaValues(projectBillingTypes),
+		},
+		"rate_card_id": {
+			Type:        "integer",
+			Description: "The ID of the rate card setting the hourly rates of the people working in the project.",
+		},
+		"budget": {
+			Type: "object",
+			Description: "A budget added to the project, so the consumption is tracked from the start. The " +
+				"capacity of financial budgets is in cents of the project currency, and of time budgets in minutes.",
+			Properties: map[string]*jsonschema.Schema{
+				"type": {
+					Type:        "string",
+					Description: "The type of the budget.",
+					Enum:        enumSchemaValues(projectBudgetTypes),
+				},
+				"capacity": {
+					Type:        "integer",
+					Description: "The capacity of the budget, in cents for financial budgets and minutes for time budgets.",
+				},
+				"start_date": {
+					Type:        "string",
+					Format:      "date",
+					Description: "The start date of the budget in the format YYYY-MM-DD. Defaults to today.",
+				},
+				"end_date": {
+					Type:        "string",
+					Format:      "date",
+					Description: "The end date of the budget in the format YYYY-MM-DD. Defaults to an open-ended budget.",
+				},
+			},
+			Required: []string{"type", "capacity"},
+		},
+	})
+	return properties
+}
+
+// projectBilling is the billing set when creating or updating a project.
+type projectBilling struct {
+	billingType *string
+	rateCardID  *int64
+	budget      *projectBudgetCreateRequest
+}
+
+// parseProjectBilling parses the billing parameters of the project create and
+// update tools.
+func parseProjectBilling(arguments map[string]any) (projectBilling, error) {
+	var billing projectBilling
+	var budget map[string]any
+	err := helpers.ParamGroup(arguments,
+		helpers.OptionalPointerParam(&billing.billingType, "billing_type",
+			helpers.RestrictValues(projectBillingTypes...),
+		),
+		helpers.OptionalNumericPointerParam(&billing.rateCardID, "rate_card_id"),
+		helpers.OptionalParam(&budget, "budget"),
+	)
+	if err != nil || budget == nil {
+		return billing, err
+	}
+
+	budgetCreateRequest := projectBudgetCreateRequest{
+		StartDate: twapi.Date(time.Now().UTC().Truncate(24 * time.Hour)),
+	}
+	err = helpers.ParamGroup(budget,
+		helpers.RequiredParam(&budgetCreateRequest.Type, "type",
+			helpers.RestrictValues(projectBudgetTypes...),
+		),
+		helpers.RequiredNumericParam(&budgetCreateRequest.Capacity, "capacity"),
+		helpers.OptionalDateParam(&budgetCreateRequest.StartDate, "start_date"),
+		helpers.OptionalDatePointerParam(&budgetCreateRequest.EndDate, "end_date"),
+	)
+	if err != nil {
+		return billing, fmt.Errorf("budget: %w", err)
+	}
+	if budgetCreateRequest.Capacity <= 0 {
+		return billing, errors.New("budget: capacity must be a positive integer")
+	}
+	if budgetCreateRequest.EndDate != nil &&
+		time.Time(*budgetCreateRequest.EndDate).Before(time.Time(budgetCreateRequest.StartDate)) {
+		return billing, errors.New("budget: end_date must not be before start_date")
+	}
+	billing.budget = &budgetCreateRequest
+	return billing, nil
+}
+
+// empty reports whether no billing parameter was provided.
+func (p projectBilling) empty() bool {
+	return p.billingType == nil && p.rateCardID == nil && p.budget == nil
+}
+
+// apply sets the billing type and the rate card of the project, and adds the
+// budget.
+func (p projectBilling) apply(ctx context.Context, engine *twapi.Engine, projectID int64) error {
+	if p.billingType != nil || p.rateCardID != nil {
+		_, err := twapi.Execute[projectBillingRequest, *projectBillingResponse](ctx, engine, projectBillingRequest{
+			ProjectID:   projectID,
+			BillingType: p.billingType,
+			RateCardID:  p.rateCardID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if p.budget != nil {
+		budget := *p.budget
+		budget.ProjectID = projectID
+		if _, err := twapi.Execute[projectBudgetCreateRequest, *projectBillingResponse](ctx, engine, budget); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// projectBillingRequest represents the request to set the billing type and
+// the rate card of a project.
+type projectBillingRequest struct {
+	ProjectID   int64
+	BillingType *string
+	RateCardID  *int64
+}
+
+// HTTPRequest creates an HTTP request for the projectBillingRequest.
+func (p projectBillingRequest) HTTPRequest(ctx context.Context, server string) (*http.Request, error) {
+	uri := fmt.Sprintf("%s/projects/api/v3/projects/%d.json", server, p.ProjectID)
+
+	payload := struct {
+		Project struct {
+			BillingType *string `json:"billingType,omitempty"`
+			RateCardID  *int64  `json:"rateCardId,omitempty"`
+		} `json:"project"`
+	}{}
+	payload.Project.BillingType = p.BillingType
+	payload.Project.RateCardID = p.RateCardID
+
+	var body bytes.Buffer
+	if err := json.NewEncoder(&body).Encode(payload); err != nil {
+		return nil, fmt.Errorf("failed to encode project billing request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, uri, &body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+// projectBudgetCreateRequest represents the request to add a budget to a
+// project.
+type projectBudgetCreateRequest struct {
+	ProjectID int64
+	Type      string
+	Capacity  int64
+	StartDate twapi.Date
+	EndDate   *twapi.Date
+}
+
+// HTTPRequest creates an HTTP request for the projectBudgetCreateRequest.
+func (p projectBudgetCreateRequest) HTTPRequest(ctx context.Context, server string) (*http.Request, error) {
+	uri := server + "/projects/api/v3/projects/budgets.json"
+
+	type budget struct {
+		ProjectID     int64      `json:"projectId"`
+		Type          string     `json:"type"`
+		Capacity      int64      `json:"capacity"`
+		StartDateTime time.Time  `json:"startDateTime"`
+		EndDateTime   *time.Time `json:"endDateTime,omitempty"`
+	}
+	payload := struct {
+		Budget budget `json:"budget"`
+	}{
+		Budget: budget{
+			ProjectID:     p.ProjectID,
+			Type:          strings.ToUpper(p.Type),
+			Capacity:      p.Capacity,
+			StartDateTime: time.Time(p.StartDate),
+		},
+	}
+	if p.EndDate != nil {
+		payload.Budget.EndDateTime = twapi.Ptr(time.Time(*p.EndDate))
+	}
+
+	var body bytes.Buffer
+	if err := json.NewEncoder(&body).Encode(payload); err != nil {
+		return nil, fmt.Errorf("failed to encode create project budget request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, uri, &body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+// projectBillingResponse handles the response of the project billing and
+// budget requests.
+type projectBillingResponse struct{}
+
+// HandleHTTPResponse handles the HTTP response for the projectBillingResponse.
+func (p *projectBillingResponse) HandleHTTPResponse(resp *http.Response) error {
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusNoContent:
+		return nil
+	}
+	return twapi.NewHTTPError(resp, "failed to update project billing")
+}
@@ -0,0 +1,419 @@
+package twprojects
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/helpers"
+	"github.com/teamwork/mcp/internal/toolsets"
+	twapi "github.com/teamwork/twapi-go-sdk"
+	"github.com/teamwork/twapi-go-sdk/projects"
+)
+
+// List of methods available in the Teamwork.com MCP service.
+//
+// The naming convention for methods follows a pattern described here:
+// https://github.com/github/github-mcp-server/issues/333
+const (
+	MethodProjectBootstrap toolsets.Method = "twprojects-bootstrap_project"
+)
+
+// projectBootstrapMaxTasks is the maximum number of tasks created by a single
+// bootstrap, keeping the call within a reasonable duration.
+const projectBootstrapMaxTasks = 500
+
+func init() {
+	// register the toolset methods
+	toolsets.RegisterMethod(MethodProjectBootstrap)
+}
+
+// projectBootstrapPlan is the structure of the project to create, validated
+// before any change is made.
+type projectBootstrapPlan struct {
+	project       projects.ProjectCreateRequest
+	memberUserIDs []int64
+	milestones    []projects.MilestoneCreateRequest
+	tasklists     []projectBootstrapTasklist
+}
+
+// projectBootstrapTasklist is a tasklist of the plan with its tasks.
+type projectBootstrapTasklist struct {
+	request projects.TasklistCreateRequest
+	// milestone is the index of the linked milestone in the plan, or -1.
+	milestone int
+	tasks     []projects.TaskCreateRequest
+}
+
+// projectBootstrapResult is the result of the bootstrap, with the IDs of the
+// created entities.
+type projectBootstrapResult struct {
+	ProjectID  int64                          `json:"projectId"`
+	Milestones []projectBootstrapEntity       `json:"milestones,omitempty"`
+	Tasklists  []projectBootstrapTasklistInfo `json:"tasklists,omitempty"`
+}
+
+// projectBootstrapEntity is an entity created by the bootstrap.
+type projectBootstrapEntity struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// projectBootstrapTasklistInfo is a tasklist created by the bootstrap, with its
+// tasks.
+type projectBootstrapTasklistInfo struct {
+	projectBootstrapEntity
+
+	Tasks []projectBootstrapEntity `json:"tasks,omitempty"`
+}
+
+// ProjectBootstrap creates a project with its milestones, tasklists and tasks
+// in a single call, deleting the project when any step fails.
+func ProjectBootstrap(engine *twapi.Engine) toolsets.ToolWrapper {
+	idsSchema := func(description string) *jsonschema.Schema {
+		return &jsonschema.Schema{
+			Type:        "array",
+			Description: description,
+			Items:       &jsonschema.Schema{Type: "integer"},
+		}
+	}
+	dateSchema := func(description string) *jsonschema.Schema {
+		return &jsonschema.Schema{
+			Type:        "string",
+			Format:      "date",
+			Description: description + " The date must be in the format YYYY-MM-DD.",
+		}
+	}
+
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name: string(MethodProjectBootstrap),
+			Description: "Create a project in Teamwork.com with its people, milestones, tasklists and initial tasks " +
+				"from a single structured payload, instead of a long sequence of create calls. The whole payload is " +
+				"validated before anything is created, and when any step fails the project is deleted with everything " +
+				"created so far, so there is no half-built project left behind. It returns the IDs of the created " +
+				"entities. " + projectDescription,
+			Annotations: &mcp.ToolAnnotations{
+				Title: "Bootstrap Project",
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"name": {
+						Type:        "string",
+						Description: "The name of the project.",
+					},
+					"description": {
+						Type:        "string",
+						Description: "The description of the project.",
+					},
+					"start_date": dateSchema("The start date of the project."),
+					"end_date":   dateSchema("The end date of the project."),
+					"company_id": {
+						Type:        "integer",
+						Description: "The ID of the company associated with the project.",
+					},
+					"owner_id": {
+						Type:        "integer",
+						Description: "The ID of the user who owns the project.",
+					},
+					"tag_ids": idsSchema("A list of tag IDs to associate with the project."),
+					"member_user_ids": idsSchema("The IDs of the users added to the project. The assignees of " +
+						"the tasks and milestones must be members of the project."),
+					"milestones": {
+						Type:        "array",
+						Description: "The milestones of the project.",
+						Items: &jsonschema.Schema{
+							Type: "object",
+							Properties: map[string]*jsonschema.Schema{
+								"name": {
+									Type:        "string",
+									Description: "The name of the milestone, also used to link the tasklists to it.",
+								},
+								"description": {
+									Type:        "string",
+									Description: "The description of the milestone.",
+								},
+								"due_date":          dateSchema("The due date of the milestone."),
+								"assignee_user_ids": idsSchema("The IDs of the users responsible for the milestone."),
+							},
+							Required: []string{"name", "due_date", "assignee_user_ids"},
+						},
+					},
+					"tasklists": {
+						Type:        "array",
+						Description: "The tasklists of the project, with their tasks.",
+						Items: &jsonschema.Schema{
+							Type: "object",
+							Properties: map[string]*jsonschema.Schema{
+								"name": {
+									Type:        "string",
+									Description: "The name of the tasklist.",
+								},
+								"description": {
+									Type:        "string",
+									Description: "The description of the tasklist.",
+								},
+								"milestone": {
+									Type:        "string",
+									Description: "The name of a milestone of the payload to link the tasklist to.",
+								},
+								"tasks": {
+									Type:        "array",
+									Description: "The tasks of the tasklist.",
+									Items: &jsonschema.Schema{
+										Type: "object",
+										Properties: map[string]*jsonschema.Schema{
+											"name": {
+												Type:        "string",
+												Description: "The name of the task.",
+											},
+											"description": {
+												Type:        "string",
+												Description: "The description of the task.",
+											},
+											"priority": {
+												Type:        "string",
+												Description: "The priority of the task. Possible values are: low, medium, high.",
+												Enum:        enumSchemaValues(taskPriorities),
+											},
+											"start_date": dateSchema("The start date of the task."),
+											"due_date":   dateSchema("The due date of the task."),
+											"estimated_minutes": {
+												Type:        "integer",
+												Description: "The estimated time to complete the task in minutes.",
+											},
+											"assignee_user_ids": idsSchema("The IDs of the users assigned to the task."),
+											"tag_ids":           idsSchema("A list of tag IDs to assign to the task."),
+										},
+										Required: []string{"name"},
+									},
+								},
+							},
+							Required: []string{"name"},
+						},
+					},
+				},
+				Required: []string{"name"},
+			},
+		},
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var arguments map[string]any
+			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("failed to decode request: %s", err.Error())), nil
+			}
+			plan, err := parseProjectBootstrapPlan(arguments)
+			if err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
+			}
+
+			projectResponse, err := projects.ProjectCreate(ctx, engine, plan.project)
+			if err != nil {
+				return helpers.HandleAPIError(err, "failed to create project")
+			}
+			projectID := int64(projectResponse.ID)
+
+			// the project is deleted with all the content created so far when any
+			// step fails
+			compensations := newCompensationLog(engine)
+			compensations.recordProject(projectID)
+			result, err := bootstrapProject(ctx, engine, projectID, plan)
+			if err != nil {
+				return compensations.fail(ctx, err, "failed to bootstrap project")
+			}
+
+			encoded, err := json.Marshal(result)
+			if err != nil {
+				return nil, err
+			}
+			return helpers.NewToolResultText("Project created successfully with ID %d. Created entities: %s",
+				projectID, encoded), nil
+		},
+	}
+}
+
+// bootstrapProject creates the content of the plan in the project, stopping at
+// the first failure.
+func bootstrapProject(
+	ctx context.Context,
+	engine *twapi.Engine,
+	projectID int64,
+	plan projectBootstrapPlan,
+) (projectBootstrapResult, error) {
+	result := projectBootstrapResult{ProjectID: projectID}
+
+	if len(plan.memberUserIDs) > 0 {
+		_, err := projects.ProjectMemberAdd(ctx, engine, projects.NewProjectMemberAddRequest(projectID,
+			plan.memberUserIDs...))
+		if err != nil {
+			return result, fmt.Errorf("failed to add project members: %w", err)
+		}
+	}
+
+	milestoneIDs := make([]int64, len(plan.milestones))
+	for i, milestoneCreateRequest := range plan.milestones {
+		milestoneCreateRequest.Path.ProjectID = projectID
+		response, err := projects.MilestoneCreate(ctx, engine, milestoneCreateRequest)
+		if err != nil {
+			return result, fmt.Errorf("failed to create milestone %q: %w", milestoneCreateRequest.Name, err)
+		}
+		milestoneIDs[i] = int64(response.ID)
+		result.Milestones = append(result.Milestones, projectBootstrapEntity{
+			ID:   milestoneIDs[i],
+			Name: milestoneCreateRequest.Name,
+		})
+	}
+
+	for _, tasklist := range plan.tasklists {
+		tasklistCreateRequest := tasklist.request
+		tasklistCreateRequest.Path.ProjectID = projectID
+		if tasklist.milestone >= 0 {
+			tasklistCreateRequest.MilestoneID = &milestoneIDs[tasklist.milestone]
+		}
+		response, err := projects.TasklistCreate(ctx, engine, tasklistCreateRequest)
+		if err != nil {
+			return result, fmt.Errorf("failed to create tasklist %q: %w", tasklistCreateRequest.Name, err)
+		}
+		info := projectBootstrapTasklistInfo{
+			projectBootstrapEntity: projectBootstrapEntity{ID: int64(response.ID), Name: tasklistCreateRequest.Name},
+		}
+
+		for _, taskCreateRequest := range tasklist.tasks {
+			taskCreateRequest.Path.TasklistID = info.ID
+			taskResponse, err := projects.TaskCreate(ctx, engine, taskCreateRequest)
+			if err != nil {
+				return result, fmt.Errorf("failed to create task %q: %w", taskCreateRequest.Name, err)
+			}
+			info.Tasks = append(info.Tasks, projectBootstrapEntity{
+				ID:   taskResponse.Task.ID,
+				Name: taskCreateRequest.Name,
+			})
+		}
+		result.Tasklists = append(result.Tasklists, info)
+	}
+	return result, nil
+}
+
+// parseProjectBootstrapPlan validates the whole payload, so nothing is created
+// when part of it is invalid.
+func parseProjectBootstrapPlan(arguments map[string]any) (projectBootstrapPlan, error) {
+	var plan projectBootstrapPlan
+	var startDate, endDate *twapi.Date
+	var milestones, tasklists []map[string]any
+
+	err := helpers.ParamGroup(arguments,
+		helpers.RequiredParam(&plan.project.Name, "name"),
+		helpers.OptionalPointerParam(&plan.project.Description, "description"),
+		helpers.OptionalDatePointerParam(&startDate, "start_date"),
+		helpers.OptionalDatePointerParam(&endDate, "end_date"),
+		helpers.OptionalNumericParam(&plan.project.CompanyID, "company_id"),
+		helpers.OptionalNumericPointerParam(&plan.project.OwnerID, "owner_id"),
+		helpers.OptionalNumericListParam(&plan.project.TagIDs, "tag_ids"),
+		helpers.OptionalNumericListParam(&plan.memberUserIDs, "member_user_ids"),
+		helpers.OptionalListParam(&milestones, "milestones"),
+		helpers.OptionalListParam(&tasklists, "tasklists"),
+	)
+	if err != nil {
+		return plan, err
+	}
+	if startDate != nil {
+		plan.project.StartAt = twapi.Ptr(projects.NewLegacyDate(time.Time(*startDate)))
+	}
+	if endDate != nil {
+		plan.project.EndAt = twapi.Ptr(projects.NewLegacyDate(time.Time(*endDate)))
+	}
+
+	milestoneIndexes := make(map[string]int, len(milestones))
+	for i, milestone := range milestones {
+		var milestoneCreateRequest projects.MilestoneCreateRequest
+		var dueDate twapi.Date
+		err := helpers.ParamGroup(milestone,
+			helpers.RequiredParam(&milestoneCreateRequest.Name, "name"),
+			helpers.OptionalPointerParam(&milestoneCreateRequest.Description, "description"),
+			helpers.RequiredDateParam(&dueDate, "due_date"),
+			helpers.OptionalNumericListParam(&milestoneCreateRequest.Assignees.UserIDs, "assignee_user_ids"),
+		)
+		if err != nil {
+			return plan, fmt.Errorf("milestone %d: %w", i+1, err)
+		}
+		if len(milestoneCreateRequest.Assignees.UserIDs) == 0 {
+			return plan, fmt.Errorf("milestone %q: assignee_user_ids is required", milestoneCreateRequest.Name)
+		}
+		if _, ok := milestoneIndexes[milestoneCreateRequest.Name]; ok {
+			return plan, fmt.Errorf("duplicated milestone %q", milestoneCreateRequest.Name)
+		}
+		milestoneIndexes[milestoneCreateRequest.Name] = i
+		milestoneCreateRequest.DueAt = projects.NewLegacyDate(time.Time(dueDate))
+		plan.milestones = append(plan.milestones, milestoneCreateRequest)
+	}
+
+	var taskCount int
+	for i, tasklist := range tasklists {
+		planTasklist := projectBootstrapTasklist{milestone: -1}
+		var milestoneName string
+		var tasks []map[string]any
+		err := helpers.ParamGroup(tasklist,
+			helpers.RequiredParam(&planTasklist.request.Name, "name"),
+			helpers.OptionalPointerParam(&planTasklist.request.Description, "description"),
+			helpers.OptionalParam(&milestoneName, "milestone"),
+			helpers.OptionalListParam(&tasks, "tasks"),
+		)
+		if err != nil {
+			return plan, fmt.Errorf("tasklist %d: %w", i+1, err)
+		}
+		if milestoneName != "" {
+			index, ok := milestoneIndexes[milestoneName]
+			if !ok {
+				return plan, fmt.Errorf("tasklist %q: unknown milestone %q", planTasklist.request.Name, milestoneName)
+			}
+			planTasklist.milestone = index
+		}
+
+		for j, task := range tasks {
+			taskCreateRequest, err := parseProjectBootstrapTask(task)
+			if err != nil {
+				return plan, fmt.Errorf("tasklist %q, task %d: %w", planTasklist.request.Name, j+1, err)
+			}
+			planTasklist.tasks = append(planTasklist.tasks, taskCreateRequest)
+		}
+		taskCount += len(tasks)
+		plan.tasklists = append(plan.tasklists, planTasklist)
+	}
+	if taskCount > projectBootstrapMaxTasks {
+		return plan, fmt.Errorf("too many tasks (%d), the maximum is %d", taskCount, projectBootstrapMaxTasks)
+	}
+	return plan, nil
+}
+
+// parseProjectBootstrapTask validates a task of the payload.
+func parseProjectBootstrapTask(task map[string]any) (projects.TaskCreateRequest, error) {
+	var taskCreateRequest projects.TaskCreateRequest
+	var assigneeUserIDs []int64
+	err := helpers.ParamGroup(task,
+		helpers.RequiredParam(&taskCreateRequest.Name, "name"),
+		helpers.OptionalPointerParam(&taskCreateRequest.Description, "description"),
+		helpers.OptionalPointerParam(&taskCreateRequest.Priority, "priority",
+			helpers.RestrictValues(taskPriorities...),
+		),
+		helpers.OptionalDatePointerParam(&taskCreateRequest.StartAt, "start_date"),
+		helpers.OptionalDatePointerParam(&taskCreateRequest.DueAt, "due_date"),
+		helpers.OptionalNumericPointerParam(&taskCreateRequest.EstimatedMinutes, "estimated_minutes"),
+		helpers.OptionalNumericListParam(&assigneeUserIDs, "assignee_user_ids"),
+		helpers.OptionalNumericListParam(&taskCreateRequest.TagIDs, "tag_ids"),
+	)
+	if err != nil {
+		return taskCreateRequest, err
+	}
+	if taskCreateRequest.StartAt != nil && taskCreateRequest.DueAt != nil &&
+		time.Time(*taskCreateRequest.DueAt).Before(time.Time(*taskCreateRequest.StartAt)) {
+		return taskCreateRequest, errors.New("due_date is before start_date")
+	}
+	if len(assigneeUserIDs) > 0 {
+		taskCreateRequest.Assignees = &projects.UserGroups{UserIDs: assigneeUserIDs}
+	}
+	return taskCreateRequest, nil
+}
@@ -0,0 +1,112 @@
+package twprojects_test
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/testutil"
+	"github.com/teamwork/mcp/internal/twprojects"
+)
+
+func TestProjectBootstrap(t *testing.T) {
+	arguments := map[string]any{
+		"name":            "Website relaunch",
+		"start_date":      "2025-11-03",
+		"member_user_ids": []any{float64(7)},
+		"milestones": []any{
+			map[string]any{"name": "Launch", "due_date": "2025-12-01", "assignee_user_ids": []any{float64(7)}},
+		},
+		"tasklists": []any{
+			map[string]any{
+				"name":      "Design",
+				"milestone": "Launch",
+				"tasks": []any{
+					map[string]any{"name": "Wireframes", "assignee_user_ids": []any{float64(7)}},
+					map[string]any{"name": "Visual design", "priority": "high"},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		arguments map[string]any
+		failTask  bool
+		requests  []string
+		isError   bool
+	}{{
+		name:      "all created",
+		arguments: arguments,
+		requests: []string{
+			"POST /projects.json",
+			"PUT /projects/api/v3/projects/10/people.json",
+			"POST /projects/10/milestones.json",
+			"POST /projects/10/tasklists.json",
+			"POST /projects/api/v3/tasklists/30/tasks.json",
+			"POST /projects/api/v3/tasklists/30/tasks.json",
+		},
+	}, {
+		name:      "rollback",
+		arguments: arguments,
+		failTask:  true,
+		requests: []string{
+			"POST /projects.json",
+			"PUT /projects/api/v3/projects/10/people.json",
+			"POST /projects/10/milestones.json",
+			"POST /projects/10/tasklists.json",
+			"POST /projects/api/v3/tasklists/30/tasks.json",
+			"DELETE /projects/10.json",
+		},
+		isError: true,
+	}, {
+		name: "unknown milestone",
+		arguments: map[string]any{
+			"name":      "Website relaunch",
+			"tasklists": []any{map[string]any{"name": "Design", "milestone": "Launch"}},
+		},
+		isError: true,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []string
+			mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(r *http.Request) (int, []byte) {
+				if r.Method == http.MethodGet {
+					return http.StatusOK, []byte(`{}`)
+				}
+				requests = append(requests, r.Method+" "+r.URL.Path)
+				switch r.Method + " " + r.URL.Path {
+				case "POST /projects.json":
+					return http.StatusCreated, []byte(`{"id":"10"}`)
+				case "POST /projects/10/milestones.json":
+					return http.StatusCreated, []byte(`{"milestoneId":"20"}`)
+				case "POST /projects/10/tasklists.json":
+					return http.StatusCreated, []byte(`{"tasklistId":"30"}`)
+				case "POST /projects/api/v3/tasklists/30/tasks.json":
+					if tt.failTask {
+						return http.StatusBadRequest, []byte(`{"errors":[{"detail":"invalid assignee"}]}`)
+					}
+					return http.StatusCreated, []byte(`{"task":{"id":40}}`)
+				}
+				return http.StatusOK, []byte(`{}`)
+			})
+
+			testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodProjectBootstrap.String(), tt.arguments,
+				testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+					toolResult, ok := result.(*mcp.CallToolResult)
+					if !ok {
+						t.Fatalf("unexpected result type: %T", result)
+					}
+					if toolResult.IsError != tt.isError {
+						t.Errorf("unexpected error state %v: %v", toolResult.IsError, toolResult.Content)
+					}
+				}),
+			)
+			if !slices.Equal(requests, tt.requests) {
+				t.Errorf("expected requests %v, got %v", tt.requests, requests)
+			}
+		})
+	}
+}
@@ -0,0 +1,537 @@
+package twprojects
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/helpers"
+	"github.com/teamwork/mcp/internal/toolsets"
+	twapi "github.com/teamwork/twapi-go-sdk"
+	"github.com/teamwork/twapi-go-sdk/projects"
+)
+
+// List of methods available in the Teamwork.com MCP service.
+//
+// The naming convention for methods follows a pattern described here:
+// https://github.com/github/github-mcp-server/issues/333
+const (
+	MethodProjectClone toolsets.Method = "twprojects-clone_project"
+)
+
+// projectCloneMaxItems is the maximum number of entities of each type loaded
+// from the source project.
+const projectCloneMaxItems = 5000
+
+// Content types copied when cloning a project.
+const (
+	projectCloneTasks      = "tasks"
+	projectCloneMilestones = "milestones"
+	projectCloneFiles      = "files"
+	projectClonePeople     = "people"
+	projectCloneSettings   = "settings"
+	// projectCloneTasklists identifies the copied tasklists in the ID mapping,
+	// as they are copied with the tasks.
+	projectCloneTasklists = "tasklists"
+)
+
+// projectCloneContents are the content types, in the order they are copied:
+// the milestones are copied before the tasklists linked to them.
+var projectCloneContents = []string{
+	projectCloneSettings, projectClonePeople, projectCloneMilestones, projectCloneTasks, projectCloneFiles,
+}
+
+func init() {
+	// register the toolset methods
+	toolsets.RegisterMethod(MethodProjectClone)
+}
+
+// projectCloneSkipped is a content type that couldn't be copied.
+type projectCloneSkipped struct {
+	Content string `json:"content"`
+	Reason  string `json:"reason"`
+}
+
+// projectCloneFailure is an entity of the source project that couldn't be
+// copied.
+type projectCloneFailure struct {
+	Content string `json:"content"`
+	ID      int64  `json:"id"`
+	Error   string `json:"error"`
+}
+
+// projectCloneResult is the result of the project clone, mapping the IDs of
+// the source entities to the IDs of the copies.
+type projectCloneResult struct {
+	ProjectID       int64                       `json:"projectId"`
+	SourceProjectID int64                       `json:"sourceProjectId"`
+	DayOffset       int                         `json:"dayOffset"`
+	Copied          []string                    `json:"copied"`
+	IDMap           map[string]map[string]int64 `json:"idMap"`
+	People          []int64                     `json:"people,omitempty"`
+	Skipped         []projectCloneSkipped       `json:"skipped"`
+	Failures        []projectCloneFailure       `json:"failures"`
+}
+
+// projectCloner copies the content of a project into a new project, shifting
+// the dates by the offset.
+type projectCloner struct {
+	engine    *twapi.Engine
+	projectID int64
+	offset    int
+	result    projectCloneResult
+}
+
+// mapID records the ID of the copy of a source entity.
+func (c *projectCloner) mapID(content string, sourceID, id int64) {
+	if c.result.IDMap[content] == nil {
+		c.result.IDMap[content] = make(map[string]int64)
+	}
+	c.result.IDMap[content][strconv.FormatInt(sourceID, 10)] = id
+}
+
+// mappedID returns the ID of the copy of a source entity.
+func (c *projectCloner) mappedID(content string, sourceID int64) (int64, bool) {
+	id, ok := c.result.IDMap[content][strconv.FormatInt(sourceID, 10)]
+	return id, ok
+}
+
+// fail records an entity that couldn't be copied.
+func (c *projectCloner) fail(content string, id int64, err error) {
+	c.result.Failures = append(c.result.Failures, projectCloneFailure{Content: content, ID: id, Error: err.Error()})
+}
+
+// shift moves the date by the day offset.
+func (c *projectCloner) shift(date time.Time) time.Time {
+	return date.AddDate(0, 0, c.offset)
+}
+
+// cloneMilestones copies the milestones, with their assignees and tags.
+func (c *projectCloner) cloneMilestones(ctx context.Context, milestones []projects.Milestone) {
+	for _, milestone := range milestones {
+		var assignees projects.LegacyUserGroups
+		for _, party := range milestone.ResponsibleParties {
+			switch party.Type {
+			case "companies":
+				assignees.CompanyIDs = append(assignees.CompanyIDs, party.ID)
+			case "teams":
+				assignees.TeamIDs = append(assignees.TeamIDs, party.ID)
+			default:
+				assignees.UserIDs = append(assignees.UserIDs, party.ID)
+			}
+		}
+		milestoneCreateRequest := projects.NewMilestoneCreateRequest(c.projectID, milestone.Name,
+			projects.NewLegacyDate(c.shift(milestone.DueAt)), assignees)
+		if milestone.Description != "" {
+			milestoneCreateRequest.Description = &milestone.Description
+		}
+		for _, tag := range milestone.Tags {
+			milestoneCreateRequest.TagIDs = append(milestoneCreateRequest.TagIDs, tag.ID)
+		}
+
+		response, err := projects.MilestoneCreate(ctx, c.engine, milestoneCreateRequest)
+		if err != nil {
+			c.fail(projectCloneMilestones, milestone.ID, err)
+			continue
+		}
+		c.mapID(projectCloneMilestones, milestone.ID, int64(response.ID))
+	}
+}
+
+// cloneTasklists copies the tasklists, linked to the copies of their
+// milestones.
+func (c *projectCloner) cloneTasklists(ctx context.Context, tasklists []projects.Tasklist) {
+	for _, tasklist := range tasklists {
+		tasklistCreateRequest := projects.NewTasklistCreateRequest(c.projectID, tasklist.Name)
+		if tasklist.Description != "" {
+			tasklistCreateRequest.Description = &tasklist.Description
+		}
+		if tasklist.Milestone != nil {
+			if milestoneID, ok := c.mappedID(projectCloneMilestones, tasklist.Milestone.ID); ok {
+				tasklistCreateRequest.MilestoneID = &milestoneID
+			}
+		}
+
+		response, err := projects.TasklistCreate(ctx, c.engine, tasklistCreateRequest)
+		if err != nil {
+			c.fail(projectCloneTasklists, tasklist.ID, err)
+			continue
+		}
+		c.mapID(projectCloneTasklists, tasklist.ID, int64(response.ID))
+	}
+}
+
+// cloneTasks copies the tasks into the copies of their tasklists. The parent
+// tasks are copied before their subtasks, and the predecessors are set once
+// all tasks are copied.
+func (c *projectCloner) cloneTasks(ctx context.Context, tasks []projects.Task) {
+	pending := slices.Clone(tasks)
+	for len(pending) > 0 {
+		var deferred []projects.Task
+		for _, task := range pending {
+			var parentTaskID *int64
+			if task.ParentTask != nil && task.ParentTask.ID > 0 {
+				id, ok := c.mappedID(projectCloneTasks, task.ParentTask.ID)
+				if !ok && slices.ContainsFunc(pending, func(t projects.Task) bool { return t.ID == task.ParentTask.ID }) {
+					deferred = append(deferred, task)
+					continue
+				}
+				if ok {
+					parentTaskID = &id
+				}
+			}
+			tasklistID, ok := c.mappedID(projectCloneTasklists, task.Tasklist.ID)
+			if !ok {
+				c.fail(projectCloneTasks, task.ID, fmt.Errorf("tasklist %d wasn't copied", task.Tasklist.ID))
+				continue
+			}
+
+			taskCreateRequest := projects.NewTaskCreateRequest(tasklistID, task.Name)
+			taskCreateRequest.Description = task.Description
+			taskCreateRequest.Priority = task.Priority
+			taskCreateRequest.ParentTaskID = parentTaskID
+			if task.EstimatedMinutes > 0 {
+				taskCreateRequest.EstimatedMinutes = &task.EstimatedMinutes
+			}
+			if task.StartAt != nil {
+				taskCreateRequest.StartAt = twapi.Ptr(twapi.Date(c.shift(*task.StartAt)))
+			}
+			if task.DueAt != nil {
+				taskCreateRequest.DueAt = twapi.Ptr(twapi.Date(c.shift(*task.DueAt)))
+			}
+			if len(task.Assignees) > 0 {
+				var assignees projects.UserGroups
+				for _, assignee := range task.Assignees {
+					switch assignee.Type {
+					case "companies":
+						assignees.CompanyIDs = append(assignees.CompanyIDs, assignee.ID)
+					case "teams":
+						assignees.TeamIDs = append(assignees.TeamIDs, assignee.ID)
+					default:
+						assignees.UserIDs = append(assignees.UserIDs, assignee.ID)
+					}
+				}
+				taskCreateRequest.Assignees = &assignees
+			}
+			for _, tag := range task.Tags {
+				taskCreateRequest.TagIDs = append(taskCreateRequest.TagIDs, tag.ID)
+			}
+
+			response, err := projects.TaskCreate(ctx, c.engine, taskCreateRequest)
+			if err != nil {
+				c.fail(projectCloneTasks, task.ID, err)
+				continue
+			}
+			c.mapID(projectCloneTasks, task.ID, response.Task.ID)
+		}
+		if len(deferred) == len(pending) {
+			// the parents of the remaining tasks failed to be copied
+			for _, task := range deferred {
+				c.fail(projectCloneTasks, task.ID, fmt.Errorf("parent task %d wasn't copied", task.ParentTask.ID))
+			}
+			break
+		}
+		pending = deferred
+	}
+
+	for _, task := range tasks {
+		id, ok := c.mappedID(projectCloneTasks, task.ID)
+		if !ok || len(task.Predecessors) == 0 {
+			continue
+		}
+		taskUpdateRequest := projects.NewTaskUpdateRequest(id)
+		for _, predecessor := range task.Predecessors {
+			predecessorID, ok := c.mappedID(projectCloneTasks, predecessor.ID)
+			if !ok {
+				continue
+			}
+			predecessorType := projects.TaskPredecessorTypeFinish
+			if value, _ := predecessor.Meta["type"].(string); value == string(projects.TaskPredecessorTypeStart) {
+				predecessorType = projects.TaskPredecessorTypeStart
+			}
+			taskUpdateRequest.Predecessors = append(taskUpdateRequest.Predecessors, projects.TaskPredecessor{
+				ID:   predecessorID,
+				Type: predecessorType,
+			})
+		}
+		if len(taskUpdateRequest.Predecessors) == 0 {
+			continue
+		}
+		if _, err := projects.TaskUpdate(ctx, c.engine, taskUpdateRequest); err != nil {
+			c.fail(projectCloneTasks, task.ID, fmt.Errorf("failed to set predecessors: %w", err))
+		}
+	}
+}
+
+// projectCloneAnchor returns the date the source project starts: its start
+// date or, without one, the earliest date of the copied tasks and milestones.
+func projectCloneAnchor(
+	project projects.Project,
+	milestones []projects.Milestone,
+	tasks []projects.Task,
+) (time.Time, bool) {
+	if project.StartAt != nil {
+		return *project.StartAt, true
+	}
+	var dates []time.Time
+	for _, milestone := range milestones {
+		dates = append(dates, milestone.DueAt)
+	}
+	for _, task := range tasks {
+		for _, date := range []*time.Time{task.StartAt, task.DueAt} {
+			if date != nil {
+				dates = append(dates, *date)
+			}
+		}
+	}
+	if len(dates) == 0 {
+		return time.Time{}, false
+	}
+	return slices.MinFunc(dates, func(a, b time.Time) int { return a.Compare(b) }), true
+}
+
+// daysBetween returns the number of calendar days from one date to another.
+func daysBetween(from, to time.Time) int {
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	to = time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(to.Sub(from).Hours() / 24)
+}
+
+// ProjectClone copies a project in Teamwork.com with the selected content,
+// moving all dates relative to a new start date.
+func ProjectClone(engine *twapi.Engine) toolsets.ToolWrapper {
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name: string(MethodProjectClone),
+			Description: "Copy a project in Teamwork.com into a new project, choosing the content to copy: tasks (with " +
+				"their tasklists, subtasks, assignees and dependencies), milestones, people and settings (description, " +
+				"company, owner and tags). All dates are moved relative to the new start date, keeping the same " +
+				"schedule. It returns the mapping of the source IDs to the new IDs for follow-up automation, and the " +
+				"entities that couldn't be copied. Completed tasks aren't copied. " + projectDescription,
+			Annotations: &mcp.ToolAnnotations{
+				Title: "Clone Project",
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"project_id": {
+						Type:        "integer",
+						Description: "The ID of the project to copy.",
+					},
+					"name": {
+						Type:        "string",
+						Description: "The name of the new project.",
+					},
+					"start_date": {
+						Type:   "string",
+						Format: "date",
+						Description: "The start date of the new project. The dates of the copied content are moved by " +
+							"the days between the source project start (or its earliest date) and this date. The date " +
+							"must be in the format YYYY-MM-DD.",
+					},
+					"include": {
+						Type: "array",
+						Description: "The content to copy. Defaults to all content. Files can't be copied and are " +
+							"reported as skipped.",
+						Items: &jsonschema.Schema{
+							Type: "string",
+							Enum: enumSchemaValues(projectCloneContents),
+						},
+					},
+				},
+				Required: []string{"project_id", "name", "start_date"},
+			},
+		},
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var sourceProjectID int64
+			var name string
+			var startDate twapi.Date
+			include := projectCloneContents
+
+			var arguments map[string]any
+			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("failed to decode request: %s", err.Error())), nil
+			}
+			err := helpers.ParamGroup(arguments,
+				helpers.RequiredNumericParam(&sourceProjectID, "project_id"),
+				helpers.RequiredParam(&name, "name"),
+				helpers.RequiredDateParam(&startDate, "start_date"),
+				helpers.OptionalListParam(&include, "include"),
+			)
+			if err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
+			}
+			for _, content := range include {
+				if !slices.Contains(projectCloneContents, content) {
+					return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: unknown content %q", content)), nil
+				}
+			}
+
+			projectResponse, err := projects.ProjectGet(ctx, engine, projects.NewProjectGetRequest(sourceProjectID))
+			if err != nil {
+				return helpers.HandleAPIError(err, "failed to get project")
+			}
+			source := projectResponse.Project
+
+			pagination := helpers.AutoPagination{PageSize: 100, MaxItems: projectCloneMaxItems}
+			var milestones []projects.Milestone
+			if slices.Contains(include, projectCloneMilestones) {
+				milestones, _, err = helpers.FetchAllPages(ctx, pagination,
+					func(ctx context.Context, page, pageSize int64) ([]projects.Milestone, bool, error) {
+						milestoneListRequest := projects.NewMilestoneListRequest()
+						milestoneListRequest.Path.ProjectID = sourceProjectID
+						milestoneListRequest.Filters.Page = page
+						milestoneListRequest.Filters.PageSize = pageSize
+						response, err := projects.MilestoneList(ctx, engine, milestoneListRequest)
+						if err != nil {
+							return nil, false, err
+						}
+						return response.Milestones, response.Meta.Page.HasMore, nil
+					})
+				if err != nil {
+					return helpers.HandleAPIError(err, "failed to list milestones")
+				}
+			}
+			var tasklists []projects.Tasklist
+			var tasks []projects.Task
+			if slices.Contains(include, projectCloneTasks) {
+				tasklists, _, err = helpers.FetchAllPages(ctx, pagination,
+					func(ctx context.Context, page, pageSize int64) ([]projects.Tasklist, bool, error) {
+						tasklistListRequest := projects.NewTasklistListRequest()
+						tasklistListRequest.Path.ProjectID = sourceProjectID
+						tasklistListRequest.Filters.Page = page
+						tasklistListRequest.Filters.PageSize = pageSize
+						response, err := projects.TasklistList(ctx, engine, tasklistListRequest)
+						if err != nil {
+							return nil, false, err
+						}
+						return response.Tasklists, response.Meta.Page.HasMore, nil
+					})
+				if err != nil {
+					return helpers.HandleAPIError(err, "failed to list tasklists")
+				}
+				tasks, _, err = helpers.FetchAllPages(ctx, pagination,
+					func(ctx context.Context, page, pageSize int64) ([]projects.Task, bool, error) {
+						taskListRequest := projects.NewTaskListRequest()
+						taskListRequest.Path.ProjectID = sourceProjectID
+						taskListRequest.Filters.Page = page
+						taskListRequest.Filters.PageSize = pageSize
+						response, err := projects.TaskList(ctx, engine, taskListRequest)
+						if err != nil {
+							return nil, false, err
+						}
+						return slices.DeleteFunc(response.Tasks, func(task projects.Task) bool {
+							return task.CompletedAt != nil || task.Status == "completed"
+						}), response.Meta.Page.HasMore, nil
+					})
+				if err != nil {
+					return helpers.HandleAPIError(err, "failed to list tasks")
+				}
+			}
+			var people []int64
+			if slices.Contains(include, projectClonePeople) {
+				people, _, err = helpers.FetchAllPages(ctx, pagination,
+					func(ctx context.Context, page, pageSize int64) ([]int64, bool, error) {
+						userListRequest := projects.NewUserListRequest()
+						userListRequest.Path.ProjectID = sourceProjectID
+						userListRequest.Filters.Page = page
+						userListRequest.Filters.PageSize = pageSize
+						response, err := projects.UserList(ctx, engine, userListRequest)
+						if err != nil {
+							return nil, false, err
+						}
+						userIDs := make([]int64, len(response.Users))
+						for i, user := range response.Users {
+							userIDs[i] = user.ID
+						}
+						return userIDs, response.Meta.Page.HasMore, nil
+					})
+				if err != nil {
+					return helpers.HandleAPIError(err, "failed to list project people")
+				}
+			}
+
+			var offset int
+			if anchor, ok := projectCloneAnchor(source, milestones, tasks); ok {
+				offset = daysBetween(anchor, time.Time(startDate))
+			}
+			cloner := projectCloner{
+				engine: engine,
+				offset: offset,
+				result: projectCloneResult{
+					SourceProjectID: sourceProjectID,
+					DayOffset:       offset,
+					Copied:          []string{},
+					IDMap:           make(map[string]map[string]int64),
+					Skipped:         []projectCloneSkipped{},
+					Failures:        []projectCloneFailure{},
+				},
+			}
+
+			projectCreateRequest := projects.NewProjectCreateRequest(name)
+			projectCreateRequest.StartAt = twapi.Ptr(projects.NewLegacyDate(time.Time(startDate)))
+			if source.EndAt != nil {
+				projectCreateRequest.EndAt = twapi.Ptr(projects.NewLegacyDate(cloner.shift(*source.EndAt)))
+			}
+			if slices.Contains(include, projectCloneSettings) {
+				projectCreateRequest.Description = source.Description
+				projectCreateRequest.CompanyID = source.Company.ID
+				if source.Owner != nil {
+					projectCreateRequest.OwnerID = &source.Owner.ID
+				}
+				for _, tag := range source.Tags {
+					projectCreateRequest.TagIDs = append(projectCreateRequest.TagIDs, tag.ID)
+				}
+			}
+			projectCreateResponse, err := projects.ProjectCreate(ctx, engine, projectCreateRequest)
+			if err != nil {
+				return helpers.HandleAPIError(err, "failed to create project")
+			}
+			cloner.projectID = int64(projectCreateResponse.ID)
+			cloner.result.ProjectID = cloner.projectID
+
+			for _, content := range projectCloneContents {
+				if !slices.Contains(include, content) {
+					continue
+				}
+				switch content {
+				case projectCloneSettings:
+				case projectClonePeople:
+					if len(people) > 0 {
+						_, err := projects.ProjectMemberAdd(ctx, engine,
+							projects.NewProjectMemberAddRequest(cloner.projectID, people...))
+						if err != nil {
+							cloner.fail(projectClonePeople, sourceProjectID, err)
+							continue
+						}
+					}
+					cloner.result.People = people
+				case projectCloneMilestones:
+					cloner.cloneMilestones(ctx, milestones)
+				case projectCloneTasks:
+					cloner.cloneTasklists(ctx, tasklists)
+					cloner.cloneTasks(ctx, tasks)
+				case projectCloneFiles:
+					cloner.result.Skipped = append(cloner.result.Skipped, projectCloneSkipped{
+						Content: projectCloneFiles,
+						Reason:  "files can't be copied through the API, they must be uploaded again",
+					})
+					continue
+				}
+				cloner.result.Copied = append(cloner.result.Copied, content)
+			}
+
+			encoded, err := json.Marshal(cloner.result)
+			if err != nil {
+				return nil, err
+			}
+			return helpers.NewToolResultText("Project created successfully with ID %d. Result of the copy: %s",
+				cloner.projectID, encoded), nil
+		},
+	}
+}
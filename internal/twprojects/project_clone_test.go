@@ -0,0 +1,109 @@
+package twprojects_test
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/testutil"
+	"github.com/teamwork/mcp/internal/twprojects"
+)
+
+func TestProjectClone(t *testing.T) {
+	bodies := make(map[string]string)
+	nextTaskID := 500
+	mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(r *http.Request) (int, []byte) {
+		request := r.Method + " " + r.URL.Path
+		if r.Body != nil {
+			content, err := io.ReadAll(r.Body)
+			if err != nil {
+				t.Fatalf("failed to read request body: %v", err)
+			}
+			bodies[request] += strings.TrimSpace(string(content))
+		}
+
+		switch request {
+		case "GET /projects/api/v3/projects/100.json":
+			return http.StatusOK, []byte(`{"project":{"id":100,"name":"Source","description":"Template",` +
+				`"startAt":"2024-01-01T00:00:00Z","endAt":"2024-01-31T00:00:00Z","company":{"id":3},` +
+				`"tags":[{"id":8}]}}`)
+		case "GET /projects/api/v3/projects/100/milestones.json":
+			return http.StatusOK, []byte(`{"milestones":[{"id":20,"name":"Launch",` +
+				`"deadline":"2024-01-31T00:00:00Z","responsibleParties":[{"id":7,"type":"users"}]}],` +
+				`"meta":{"page":{"hasMore":false}}}`)
+		case "GET /projects/api/v3/projects/100/tasklists.json":
+			return http.StatusOK, []byte(`{"tasklists":[{"id":10,"name":"Build","milestone":{"id":20}}],` +
+				`"meta":{"page":{"hasMore":false}}}`)
+		case "GET /projects/api/v3/projects/100/tasks.json":
+			return http.StatusOK, []byte(`{"tasks":[` +
+				`{"id":2,"name":"Subtask","tasklist":{"id":10},"parentTask":{"id":1},` +
+				`"predecessors":[{"id":1,"type":"tasks"}]},` +
+				`{"id":1,"name":"Parent","tasklist":{"id":10},"dueDate":"2024-01-10T00:00:00Z",` +
+				`"assignees":[{"id":7,"type":"users"}]},` +
+				`{"id":3,"name":"Done","tasklist":{"id":10},"status":"completed"}],` +
+				`"meta":{"page":{"hasMore":false}}}`)
+		case "GET /projects/api/v3/projects/100/people.json":
+			return http.StatusOK, []byte(`{"people":[{"id":7},{"id":9}],"meta":{"page":{"hasMore":false}}}`)
+		case "POST /projects.json":
+			return http.StatusCreated, []byte(`{"id":"200"}`)
+		case "PUT /projects/api/v3/projects/200/people.json":
+			return http.StatusOK, []byte(`{}`)
+		case "POST /projects/200/milestones.json":
+			return http.StatusCreated, []byte(`{"milestoneId":"300"}`)
+		case "POST /projects/200/tasklists.json":
+			return http.StatusCreated, []byte(`{"tasklistId":"400"}`)
+		case "POST /projects/api/v3/tasklists/400/tasks.json":
+			nextTaskID++
+			return http.StatusCreated, []byte(`{"task":{"id":` + strconv.Itoa(nextTaskID-1) + `}}`)
+		case "PUT /projects/api/v3/tasks/501.json":
+			return http.StatusOK, []byte(`{"task":{"id":501}}`)
+		}
+		t.Errorf("unexpected request %s", request)
+		return http.StatusNotFound, nil
+	})
+
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodProjectClone.String(), map[string]any{
+		"project_id": float64(100),
+		"name":       "Copy",
+		"start_date": "2024-03-01",
+	}, testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+		toolResult, ok := result.(*mcp.CallToolResult)
+		if !ok {
+			t.Fatalf("unexpected result type: %T", result)
+		}
+		if toolResult.IsError {
+			t.Fatalf("tool failed to execute: %v", toolResult.Content)
+		}
+		text := toolResult.Content[0].(*mcp.TextContent).Text
+		expected := `Project created successfully with ID 200. Result of the copy: {"projectId":200,` +
+			`"sourceProjectId":100,"dayOffset":60,"copied":["settings","people","milestones","tasks"],` +
+			`"idMap":{"milestones":{"20":300},"tasklists":{"10":400},"tasks":{"1":500,"2":501}},"people":[7,9],` +
+			`"skipped":[{"content":"files","reason":"files can't be copied through the API, they must be uploaded ` +
+			`again"}],"failures":[]}`
+		if text != expected {
+			t.Errorf("expected %s, got %s", expected, text)
+		}
+	}))
+
+	expectedBodies := map[string][]string{
+		"POST /projects.json": {
+			`"start-date":"20240301"`, `"end-date":"20240331"`, `"description":"Template"`, `"companyId":3`,
+			`"tagIds":[8]`,
+		},
+		"PUT /projects/api/v3/projects/200/people.json":  {`"userIds":[7,9]`},
+		"POST /projects/200/milestones.json":             {`"deadline":"20240331"`, `"responsible-party-ids":"7"`},
+		"POST /projects/200/tasklists.json":              {`"milestone-Id":300`},
+		"POST /projects/api/v3/tasklists/400/tasks.json": {`"dueAt":"2024-03-10"`, `"parentTaskId":500`},
+		"PUT /projects/api/v3/tasks/501.json":            {`"predecessors":[{"id":500,"type":"complete"}]`},
+	}
+	for request, fragments := range expectedBodies {
+		for _, fragment := range fragments {
+			if !strings.Contains(bodies[request], fragment) {
+				t.Errorf("expected %s body to contain %s, got %s", request, fragment, bodies[request])
+			}
+		}
+	}
+}
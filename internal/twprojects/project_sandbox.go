@@ -0,0 +1,478 @@
+package twprojects
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/config"
+	"github.com/teamwork/mcp/internal/helpers"
+	"github.com/teamwork/mcp/internal/toolsets"
+	"github.com/teamwork/twapi-go-sdk"
+	"github.com/teamwork/twapi-go-sdk/projects"
+)
+
+// sandboxReferenceParams are the parameters referencing entities, by the type
+// of the referenced entity.
+var sandboxReferenceParams = map[string]string{
+	"project_id":        "project",
+	"project_ids":       "project",
+	"tasklist_id":       "tasklist",
+	"tasklist_ids":      "tasklist",
+	"stale_tasklist_id": "tasklist",
+	"task_id":           "task",
+	"parent_task_id":    "task",
+	"related_task_id":   "task",
+	"milestone_id":      "milestone",
+	"notebook_id":       "notebook",
+}
+
+// sandboxUnscopedEntities are the entities that don't belong to a project. They
+// can be read, but not changed, in the sandbox.
+var sandboxUnscopedEntities = []string{"user", "company", "tag", "team", "industry"}
+
+// sandboxUnscopedMethods are the tools without project references that are
+// available in the sandbox, as they don't expose or change project data.
+var sandboxUnscopedMethods = []toolsets.Method{
+	MethodUserGetMe,
+	MethodIndustryList,
+	MethodEnumsGet,
+	MethodTasklistTemplateList,
+	MethodDefaultProjectSet,
+	MethodRecentEntities,
+	// only the writes performed in the sandbox can be reverted
+	MethodUndoLast,
+}
+
+// sandboxDirectoryMethods are the tools listing the people and companies of
+// the installation. They are only available when the sandbox isn't bound to a
+// client company.
+var sandboxDirectoryMethods = []toolsets.Method{
+	MethodUserList,
+	MethodPersonFind,
+	MethodCompanyList,
+	MethodTeamList,
+}
+
+// sandboxCompanyProjectsTTL is the time the projects of the client company are
+// cached, so new projects become available without restarting the server.
+const sandboxCompanyProjectsTTL = 5 * time.Minute
+
+// sandboxEntityMethods are the tools whose id parameter references an entity
+// not named after the tool.
+var sandboxEntityMethods = map[toolsets.Method]string{
+	MethodTimerHeartbeat: "timer",
+}
+
+// projectSandbox restricts the tools to the entities of an allowlist of
+// projects, so the server can be piloted on a few projects without risking the
+// rest of the installation. The references to other entities (e.g. tasks) are
+// resolved to their project before calling the tool.
+//
+// When bound to a client company, the tools are also restricted to the
+// projects and people of that company, so a client-facing agent can't leak
+// the data of other clients.
+type projectSandbox struct {
+	engine    *twapi.Engine
+	allowed   []int64
+	companyID int64
+
+	mutex sync.Mutex
+	// companyProjects are the projects of the client company, by installation,
+	// as the tools are shared by all the installations in HTTP mode
+	companyProjects map[string]sandboxCompanyProjects
+}
+
+// sandboxCompanyProjects are the cached projects of the client company in an
+// installation.
+type sandboxCompanyProjects struct {
+	projectIDs []int64
+	fetchedAt  time.Time
+}
+
+func newProjectSandbox(engine *twapi.Engine, allowed []int64, companyID int64) *projectSandbox {
+	return &projectSandbox{
+		engine:          engine,
+		allowed:         allowed,
+		companyID:       companyID,
+		companyProjects: make(map[string]sandboxCompanyProjects),
+	}
+}
+
+// sandboxReference is an entity referenced by the arguments of a tool call.
+type sandboxReference struct {
+	entity string
+	id     int64
+}
+
+// apply wraps the tools, rejecting the calls referencing entities outside the
+// allowed projects. The write tools can only change entities of the allowed
+// projects, while the read tools can also read the entities that don't belong
+// to any project (e.g. users). The tools filtering by project without a project
+// reference are restricted to the allowed projects.
+func (s *projectSandbox) apply(tools []toolsets.ToolWrapper, readOnly bool) []toolsets.ToolWrapper {
+	if len(s.allowed) == 0 && s.companyID == 0 {
+		return tools
+	}
+	return toolsets.WrapToolList(tools, func(tool toolsets.ToolWrapper) toolsets.ToolWrapper {
+		var properties map[string]*jsonschema.Schema
+		if schema, ok := tool.Tool.InputSchema.(*jsonschema.Schema); ok {
+			properties = schema.Properties
+		}
+		tool.Handler = s.middleware(toolsets.Method(tool.Tool.Name), properties, readOnly)(tool.Handler)
+		return tool
+	})
+}
+
+// middleware checks the references of the tool call against the allowed
+// projects, restricting the tools filtering by project to them.
+func (s *projectSandbox) middleware(
+	method toolsets.Method,
+	properties map[string]*jsonschema.Schema,
+	readOnly bool,
+) toolsets.ToolMiddleware {
+	return func(handler mcp.ToolHandler) mcp.ToolHandler {
+		return func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var arguments map[string]any
+			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+				// the references can't be checked, so the call is denied
+				return helpers.NewToolResultTextError(fmt.Sprintf("failed to decode request: %s", err.Error())), nil
+			}
+
+			allowed, err := s.allowedProjects(ctx)
+			if err != nil {
+				return helpers.HandleAPIError(err, "failed to list the projects of the company")
+			}
+			restriction := s.describe(allowed)
+			if len(allowed) == 0 {
+				return helpers.NewToolResultTextError(restriction + " and none is available"), nil
+			}
+
+			var scoped bool
+			references := sandboxReferences(method, arguments, readOnly)
+			for _, reference := range references {
+				if slices.Contains(sandboxUnscopedEntities, reference.entity) {
+					if !readOnly {
+						return helpers.NewToolResultTextError(fmt.Sprintf("%s and can't change a %s, as it doesn't "+
+							"belong to a project", restriction, reference.entity)), nil
+					}
+					ok, err := s.companyAllows(ctx, reference)
+					if err != nil {
+						return helpers.HandleAPIError(err, fmt.Sprintf("failed to check the company of %s %d",
+							reference.entity, reference.id))
+					}
+					if !ok {
+						return helpers.NewToolResultTextError(fmt.Sprintf("%s and %s %d doesn't belong to company %d",
+							restriction, reference.entity, reference.id, s.companyID)), nil
+					}
+					continue
+				}
+				projectID, err := s.projectOf(ctx, reference)
+				if err != nil {
+					return helpers.HandleAPIError(err, fmt.Sprintf("failed to check the project of %s %d",
+						reference.entity, reference.id))
+				}
+				if projectID == 0 {
+					return helpers.NewToolResultTextError(fmt.Sprintf("%s and can't check the project of %s %d",
+						restriction, reference.entity, reference.id)), nil
+				}
+				if !slices.Contains(allowed, projectID) {
+					return helpers.NewToolResultTextError(fmt.Sprintf("%s and %s %d belongs to project %d",
+						restriction, reference.entity, reference.id, projectID)), nil
+				}
+				scoped = true
+			}
+			if scoped || slices.Contains(sandboxUnscopedMethods, method) ||
+				(s.companyID == 0 && slices.Contains(sandboxDirectoryMethods, method)) {
+				return handler(ctx, request)
+			}
+
+			// tools filtering by project are restricted to the allowed projects
+			if arguments == nil {
+				arguments = make(map[string]any)
+			}
+			switch {
+			case properties["project_ids"] != nil:
+				arguments["project_ids"] = allowed
+			case properties["project_id"] != nil && len(allowed) == 1:
+				arguments["project_id"] = float64(allowed[0])
+			case properties["project_id"] != nil:
+				return helpers.NewToolResultTextError(fmt.Sprintf("%s, set the project_id parameter to one of them",
+					restriction)), nil
+			case len(references) > 0:
+				// only entities that don't belong to a project are referenced
+				return handler(ctx, request)
+			default:
+				return helpers.NewToolResultTextError(fmt.Sprintf("%s and %s doesn't reference any of them, use the "+
+					"tools scoped to a project instead", restriction, method)), nil
+			}
+			return callWithArguments(ctx, handler, request, arguments)
+		}
+	}
+}
+
+// allows reports whether the referenced entity belongs to the allowed projects,
+// for the accesses that don't go through the tools (e.g. resources).
+func (s *projectSandbox) allows(ctx context.Context, reference sandboxReference) (bool, error) {
+	if len(s.allowed) == 0 && s.companyID == 0 {
+		return true, nil
+	}
+	allowed, err := s.allowedProjects(ctx)
+	if err != nil {
+		return false, err
+	}
+	projectID, err := s.projectOf(ctx, reference)
+	if err != nil {
+		return false, err
+	}
+	return projectID != 0 && slices.Contains(allowed, projectID), nil
+}
+
+// checkResource hides the resources outside the allowed projects, reporting
+// them as not found.
+func (s *projectSandbox) checkResource(ctx context.Context, uri string, reference sandboxReference) error {
+	allowed, err := s.allows(ctx, reference)
+	if err != nil {
+		if isNotFoundError(err) {
+			return mcp.ResourceNotFoundError(uri)
+		}
+		return fmt.Errorf("failed to check the project of %s %d: %w", reference.entity, reference.id, err)
+	}
+	if !allowed {
+		return mcp.ResourceNotFoundError(uri)
+	}
+	return nil
+}
+
+// describe returns the restriction, for the error messages.
+func (s *projectSandbox) describe(allowed []int64) string {
+	ids := make([]string, len(allowed))
+	for i, id := range allowed {
+		ids[i] = strconv.FormatInt(id, 10)
+	}
+	if s.companyID > 0 {
+		return fmt.Sprintf("this session is restricted to the projects of company %d (%s)", s.companyID,
+			strings.Join(ids, ", "))
+	}
+	return "this server is restricted to the projects " + strings.Join(ids, ", ")
+}
+
+// allowedProjects returns the projects available in the sandbox. When bound to
+// a client company, they are the projects of the company, also restricted to
+// the allowlist when there is one.
+func (s *projectSandbox) allowedProjects(ctx context.Context) ([]int64, error) {
+	if s.companyID == 0 {
+		return s.allowed, nil
+	}
+
+	installation, _ := config.CustomerURLFromContext(ctx)
+	key := installation + "|" + strconv.FormatInt(s.companyID, 10)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	cached, ok := s.companyProjects[key]
+	if !ok || time.Since(cached.fetchedAt) > sandboxCompanyProjectsTTL {
+		companyProjects, _, err := helpers.FetchAllPages(ctx, helpers.AutoPagination{PageSize: 100},
+			func(ctx context.Context, page, pageSize int64) ([]int64, bool, error) {
+				projectListRequest := projects.NewProjectListRequest()
+				projectListRequest.Filters.Page = page
+				projectListRequest.Filters.PageSize = pageSize
+
+				response, err := executeWithQuery[*projects.ProjectListResponse](ctx, s.engine, projectListRequest,
+					url.Values{
+						"projectCompanyIds":       []string{strconv.FormatInt(s.companyID, 10)},
+						"includeArchivedProjects": []string{"true"},
+					})
+				if err != nil {
+					return nil, false, err
+				}
+				projectIDs := make([]int64, len(response.Projects))
+				for i, project := range response.Projects {
+					projectIDs[i] = project.ID
+				}
+				return projectIDs, response.Meta.Page.HasMore, nil
+			})
+		if err != nil {
+			return nil, err
+		}
+		// the expired projects of other installations are discarded, so the
+		// cache doesn't grow with the installations served
+		maps.DeleteFunc(s.companyProjects, func(_ string, cached sandboxCompanyProjects) bool {
+			return time.Since(cached.fetchedAt) > sandboxCompanyProjectsTTL
+		})
+		cached = sandboxCompanyProjects{projectIDs: companyProjects, fetchedAt: time.Now()}
+		s.companyProjects[key] = cached
+	}
+
+	if len(s.allowed) == 0 {
+		return cached.projectIDs, nil
+	}
+	return slices.DeleteFunc(slices.Clone(cached.projectIDs), func(projectID int64) bool {
+		return !slices.Contains(s.allowed, projectID)
+	}), nil
+}
+
+// companyAllows reports whether an entity that doesn't belong to a project can
+// be read in the sandbox. When bound to a client company, only the company
+// itself and its people can be read.
+func (s *projectSandbox) companyAllows(ctx context.Context, reference sandboxReference) (bool, error) {
+	if s.companyID == 0 {
+		return true, nil
+	}
+	switch reference.entity {
+	case "company":
+		return reference.id == s.companyID, nil
+	case "user":
+		response, err := projects.UserGet(ctx, s.engine, projects.NewUserGetRequest(reference.id))
+		if err != nil {
+			return false, err
+		}
+		return response.User.Company.ID == s.companyID, nil
+	case "team":
+		// teams mix people of different companies
+		return false, nil
+	}
+	return true, nil
+}
+
+// projectOf returns the project of the referenced entity. It returns zero when
+// the entity can't be resolved to a project.
+func (s *projectSandbox) projectOf(ctx context.Context, reference sandboxReference) (int64, error) {
+	switch reference.entity {
+	case "project":
+		return reference.id, nil
+	case "tasklist":
+		response, err := projects.TasklistGet(ctx, s.engine, projects.NewTasklistGetRequest(reference.id))
+		if err != nil {
+			return 0, err
+		}
+		return response.Tasklist.Project.ID, nil
+	case "task":
+		response, err := projects.TaskGet(ctx, s.engine, projects.NewTaskGetRequest(reference.id))
+		if err != nil {
+			return 0, err
+		}
+		return s.projectOf(ctx, sandboxReference{entity: "tasklist", id: response.Task.Tasklist.ID})
+	case "milestone":
+		response, err := projects.MilestoneGet(ctx, s.engine, projects.NewMilestoneGetRequest(reference.id))
+		if err != nil {
+			return 0, err
+		}
+		return response.Milestone.Project.ID, nil
+	case "notebook":
+		response, err := projects.NotebookGet(ctx, s.engine, projects.NewNotebookGetRequest(reference.id))
+		if err != nil {
+			return 0, err
+		}
+		return response.Notebook.Project.ID, nil
+	case "comment":
+		response, err := projects.CommentGet(ctx, s.engine, projects.NewCommentGetRequest(reference.id))
+		if err != nil {
+			return 0, err
+		}
+		return response.Comment.Project.ID, nil
+	case "timelog":
+		response, err := projects.TimelogGet(ctx, s.engine, projects.NewTimelogGetRequest(reference.id))
+		if err != nil {
+			return 0, err
+		}
+		return response.Timelog.Project.ID, nil
+	case "timer":
+		response, err := projects.TimerGet(ctx, s.engine, projects.NewTimerGetRequest(reference.id))
+		if err != nil {
+			return 0, err
+		}
+		return response.Timer.Project.ID, nil
+	}
+	return 0, nil
+}
+
+// sandboxReferences returns the entities referenced by the arguments of a tool
+// call: the ID parameters, the id parameter of the tools named after an entity
+// (e.g. get_task) and the typed references (e.g. the object of a comment).
+func sandboxReferences(method toolsets.Method, arguments map[string]any, readOnly bool) []sandboxReference {
+	var references []sandboxReference
+	add := func(entity string, value any) {
+		// the typed references can use the plural types (e.g. "companies")
+		entity = strings.ToLower(entity)
+		if singular, ok := strings.CutSuffix(entity, "ies"); ok {
+			entity = singular + "y"
+		} else {
+			entity = strings.TrimSuffix(entity, "s")
+		}
+		if id, ok := sandboxID(value); ok {
+			references = append(references, sandboxReference{entity: entity, id: id})
+		}
+	}
+
+	for param, entity := range sandboxReferenceParams {
+		if list, ok := arguments[param].([]any); ok {
+			for _, value := range list {
+				add(entity, value)
+			}
+		} else {
+			add(entity, arguments[param])
+		}
+	}
+
+	// the company of the write tools is an attribute of the changed entity
+	if readOnly {
+		add("company", arguments["company_id"])
+	}
+
+	if id, ok := arguments["id"]; ok {
+		switch {
+		case arguments["entity_type"] != nil:
+			entityType, _ := arguments["entity_type"].(string)
+			add(entityType, id)
+		case arguments["item_type"] != nil:
+			itemType, _ := arguments["item_type"].(string)
+			add(itemType, id)
+		case sandboxEntityMethods[method] != "":
+			add(sandboxEntityMethods[method], id)
+		default:
+			name := strings.TrimPrefix(string(method), "twprojects-")
+			if _, entity, ok := strings.Cut(name, "_"); ok {
+				add(entity, id)
+			}
+		}
+	}
+
+	var objects []any
+	if object, ok := arguments["object"].(map[string]any); ok {
+		objects = append(objects, object)
+	}
+	if entities, ok := arguments["entities"].([]any); ok {
+		objects = append(objects, entities...)
+	}
+	for _, object := range objects {
+		if object, ok := object.(map[string]any); ok {
+			entityType, _ := object["type"].(string)
+			add(entityType, object["id"])
+		}
+	}
+	return references
+}
+
+// sandboxID returns the ID of a reference, which can be a number or a numeric
+// string. Zero and negative IDs (e.g. clearing a field) are ignored.
+func sandboxID(value any) (int64, bool) {
+	var id int64
+	switch value := value.(type) {
+	case float64:
+		id = int64(value)
+	case string:
+		id, _ = strconv.ParseInt(value, 10, 64)
+	}
+	return id, id > 0
+}
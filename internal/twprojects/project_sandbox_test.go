@@ -0,0 +1,252 @@
+package twprojects_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/config"
+	"github.com/teamwork/mcp/internal/testutil"
+	"github.com/teamwork/mcp/internal/toolsets"
+	"github.com/teamwork/mcp/internal/twprojects"
+)
+
+func TestProjectSandbox(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    toolsets.Method
+		arguments map[string]any
+		isError   bool
+		query     string
+	}{{
+		name:      "allowed project",
+		method:    twprojects.MethodTasklistListByProject,
+		arguments: map[string]any{"project_id": float64(123)},
+	}, {
+		name:      "other project",
+		method:    twprojects.MethodTasklistListByProject,
+		arguments: map[string]any{"project_id": float64(456)},
+		isError:   true,
+	}, {
+		name:      "task of an allowed project",
+		method:    twprojects.MethodTaskGet,
+		arguments: map[string]any{"id": float64(10)},
+	}, {
+		name:      "task of another project",
+		method:    twprojects.MethodTaskUpdate,
+		arguments: map[string]any{"id": float64(20), "name": "Renamed"},
+		isError:   true,
+	}, {
+		name:      "project filter",
+		method:    twprojects.MethodProjectList,
+		arguments: map[string]any{},
+		query:     "projectIds=123",
+	}, {
+		name:      "read entity without project",
+		method:    twprojects.MethodUserGet,
+		arguments: map[string]any{"id": float64(1)},
+	}, {
+		name:      "change entity without project",
+		method:    twprojects.MethodUserUpdate,
+		arguments: map[string]any{"id": float64(1), "first_name": "Jane"},
+		isError:   true,
+	}, {
+		name:      "tool without project references",
+		method:    twprojects.MethodTaskList,
+		arguments: map[string]any{},
+		isError:   true,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var query string
+			mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(r *http.Request) (int, []byte) {
+				switch {
+				case strings.HasSuffix(r.URL.Path, "/tasks/10.json"):
+					return http.StatusOK, []byte(`{"task":{"id":10,"tasklist":{"id":1,"type":"tasklists"}}}`)
+				case strings.HasSuffix(r.URL.Path, "/tasks/20.json"):
+					return http.StatusOK, []byte(`{"task":{"id":20,"tasklist":{"id":2,"type":"tasklists"}}}`)
+				case strings.HasSuffix(r.URL.Path, "/tasklists/1.json"):
+					return http.StatusOK, []byte(`{"tasklist":{"id":1,"project":{"id":123,"type":"projects"}}}`)
+				case strings.HasSuffix(r.URL.Path, "/tasklists/2.json"):
+					return http.StatusOK, []byte(`{"tasklist":{"id":2,"project":{"id":456,"type":"projects"}}}`)
+				}
+				query = r.URL.RawQuery
+				return http.StatusOK, []byte(`{}`)
+			}, twprojects.WithAllowedProjects([]int64{123}))
+
+			testutil.ExecuteToolRequest(t, mcpServer, tt.method.String(), tt.arguments,
+				testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+					toolResult, ok := result.(*mcp.CallToolResult)
+					if !ok {
+						t.Fatalf("unexpected result type: %T", result)
+					}
+					if toolResult.IsError != tt.isError {
+						t.Errorf("unexpected error state %v: %v", toolResult.IsError, toolResult.Content)
+					}
+				}),
+			)
+			if tt.query != "" && !strings.Contains(query, tt.query) {
+				t.Errorf("expected query to contain %q, got %q", tt.query, query)
+			}
+		})
+	}
+}
+
+func TestProjectSandboxInvalidArguments(t *testing.T) {
+	var requested bool
+	mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(*http.Request) (int, []byte) {
+		requested = true
+		return http.StatusOK, []byte(`{"industries":[]}`)
+	}, twprojects.WithAllowedProjects([]int64{123}))
+
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	if _, err := mcpServer.Connect(t.Context(), serverTransport, nil); err != nil {
+		t.Fatalf("failed to connect to server: %v", err)
+	}
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	clientSession, err := client.Connect(t.Context(), clientTransport, nil)
+	if err != nil {
+		t.Fatalf("failed to connect to client: %v", err)
+	}
+	defer clientSession.Close() //nolint:errcheck
+
+	// the tool ignores the arguments, so only the sandbox can deny the call
+	result, err := clientSession.CallTool(t.Context(), &mcp.CallToolParams{
+		Name:      twprojects.MethodIndustryList.String(),
+		Arguments: []any{float64(456)},
+	})
+	if err != nil {
+		t.Fatalf("failed to call tool: %v", err)
+	}
+	if !result.IsError {
+		t.Errorf("expected an error for arguments that can't be decoded")
+	}
+	if requested {
+		t.Error("unexpected API request")
+	}
+}
+
+func TestProjectSandboxCompany(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    toolsets.Method
+		arguments map[string]any
+		isError   bool
+	}{{
+		name:      "company project",
+		method:    twprojects.MethodProjectGet,
+		arguments: map[string]any{"id": float64(123)},
+	}, {
+		name:      "other company project",
+		method:    twprojects.MethodProjectGet,
+		arguments: map[string]any{"id": float64(456)},
+		isError:   true,
+	}, {
+		name:      "company user",
+		method:    twprojects.MethodUserGet,
+		arguments: map[string]any{"id": float64(1)},
+	}, {
+		name:      "other company user",
+		method:    twprojects.MethodUserGet,
+		arguments: map[string]any{"id": float64(2)},
+		isError:   true,
+	}, {
+		name:      "company users",
+		method:    twprojects.MethodCompanyUserList,
+		arguments: map[string]any{"company_id": float64(7)},
+	}, {
+		name:      "other company users",
+		method:    twprojects.MethodCompanyUserList,
+		arguments: map[string]any{"company_id": float64(8)},
+		isError:   true,
+	}, {
+		name:      "all users",
+		method:    twprojects.MethodUserList,
+		arguments: map[string]any{},
+		isError:   true,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(r *http.Request) (int, []byte) {
+				switch {
+				case strings.HasSuffix(r.URL.Path, "/projects.json") && r.URL.Query().Get("projectCompanyIds") == "7":
+					return http.StatusOK, []byte(`{"projects":[{"id":123}],"meta":{"page":{"hasMore":false}}}`)
+				case strings.HasSuffix(r.URL.Path, "/people/1.json"):
+					return http.StatusOK, []byte(`{"person":{"id":1,"company":{"id":7,"type":"companies"}}}`)
+				case strings.HasSuffix(r.URL.Path, "/people/2.json"):
+					return http.StatusOK, []byte(`{"person":{"id":2,"company":{"id":8,"type":"companies"}}}`)
+				}
+				return http.StatusOK, []byte(`{}`)
+			}, twprojects.WithCompany(7))
+
+			testutil.ExecuteToolRequest(t, mcpServer, tt.method.String(), tt.arguments,
+				testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+					toolResult, ok := result.(*mcp.CallToolResult)
+					if !ok {
+						t.Fatalf("unexpected result type: %T", result)
+					}
+					if toolResult.IsError != tt.isError {
+						t.Errorf("unexpected error state %v: %v", toolResult.IsError, toolResult.Content)
+					}
+				}),
+			)
+		})
+	}
+}
+
+func TestProjectSandboxCompanyInstallations(t *testing.T) {
+	// the same company ID has different projects in each installation
+	companyProjects := map[string]string{
+		"https://a.example.com": `{"projects":[{"id":123}],"meta":{"page":{"hasMore":false}}}`,
+		"https://b.example.com": `{"projects":[{"id":456}],"meta":{"page":{"hasMore":false}}}`,
+	}
+	mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(r *http.Request) (int, []byte) {
+		if strings.HasSuffix(r.URL.Path, "/projects.json") {
+			installation, _ := config.CustomerURLFromContext(r.Context())
+			return http.StatusOK, []byte(companyProjects[installation])
+		}
+		return http.StatusOK, []byte(`{}`)
+	}, twprojects.WithCompany(7))
+
+	tests := []struct {
+		name         string
+		installation string
+		projectID    int64
+		isError      bool
+	}{{
+		name:         "first installation",
+		installation: "https://a.example.com",
+		projectID:    123,
+	}, {
+		name:         "project of the first installation",
+		installation: "https://b.example.com",
+		projectID:    123,
+		isError:      true,
+	}, {
+		name:         "second installation",
+		installation: "https://b.example.com",
+		projectID:    456,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodProjectGet.String(), map[string]any{
+				"id": float64(tt.projectID),
+			},
+				testutil.ExecuteToolRequestWithContext(config.WithCustomerURL(t.Context(), tt.installation)),
+				testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+					toolResult, ok := result.(*mcp.CallToolResult)
+					if !ok {
+						t.Fatalf("unexpected result type: %T", result)
+					}
+					if toolResult.IsError != tt.isError {
+						t.Errorf("unexpected error state %v: %v", toolResult.IsError, toolResult.Content)
+					}
+				}),
+			)
+		})
+	}
+}
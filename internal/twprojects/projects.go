@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/url"
+	"time"
 
 	"github.com/google/jsonschema-go/jsonschema"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -63,14 +65,15 @@ func init() {
 func ProjectCreate(engine *twapi.Engine) toolsets.ToolWrapper {
 	return toolsets.ToolWrapper{
 		Tool: &mcp.Tool{
-			Name:        string(MethodProjectCreate),
-			Description: "Create a new project in Teamwork.com. " + projectDescription,
+			Name: string(MethodProjectCreate),
+			Description: "Create a new project in Teamwork.com. The billing type, rate card and budget can be set in " +
+				"the same call, so the project is ready for billing. " + projectDescription,
 			Annotations: &mcp.ToolAnnotations{
 				Title: "Create Project",
 			},
 			InputSchema: &jsonschema.Schema{
 				Type: "object",
-				Properties: map[string]*jsonschema.Schema{
+				Properties: withProjectBillingProperties(map[string]*jsonschema.Schema{
 					"name": {
 						Type:        "string",
 						Description: "The name of the project.",
@@ -91,7 +94,7 @@ func ProjectCreate(engine *twapi.Engine) toolsets.ToolWrapper {
 						Type:        "integer",
 						Description: "The ID of the company associated with the project.",
 					},
-					"owned_id": {
+					"owner_id": {
 						Type:        "integer",
 						Description: "The ID of the user who owns the project.",
 					},
@@ -102,7 +105,7 @@ func ProjectCreate(engine *twapi.Engine) toolsets.ToolWrapper {
 							Type: "integer",
 						},
 					},
-				},
+				}),
 				Required: []string{"name"},
 			},
 		},
@@ -119,17 +122,31 @@ func ProjectCreate(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalLegacyDatePointerParam(&projectCreateRequest.StartAt, "start_at"),
 				helpers.OptionalLegacyDatePointerParam(&projectCreateRequest.EndAt, "end_at"),
 				helpers.OptionalNumericParam(&projectCreateRequest.CompanyID, "company_id"),
+				// "owned_id" is the former name of the parameter, still accepted for
+				// backward compatibility
 				helpers.OptionalNumericPointerParam(&projectCreateRequest.OwnerID, "owned_id"),
+				helpers.OptionalNumericPointerParam(&projectCreateRequest.OwnerID, "owner_id"),
 				helpers.OptionalNumericListParam(&projectCreateRequest.TagIDs, "tag_ids"),
 			)
 			if err != nil {
 				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
 			}
+			billing, err := parseProjectBilling(arguments)
+			if err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
+			}
 
 			project, err := projects.ProjectCreate(ctx, engine, projectCreateRequest)
 			if err != nil {
 				return helpers.HandleAPIError(err, "failed to create project")
 			}
+			if !billing.empty() {
+				compensations := newCompensationLog(engine)
+				compensations.recordProject(int64(project.ID))
+				if err := billing.apply(ctx, engine, int64(project.ID)); err != nil {
+					return compensations.fail(ctx, err, "failed to set project billing")
+				}
+			}
 			return helpers.NewToolResultText("Project created successfully with ID %d", project.ID), nil
 		},
 	}
@@ -139,14 +156,16 @@ func ProjectCreate(engine *twapi.Engine) toolsets.ToolWrapper {
 func ProjectUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 	return toolsets.ToolWrapper{
 		Tool: &mcp.Tool{
-			Name:        string(MethodProjectUpdate),
-			Description: "Update an existing project in Teamwork.com. " + projectDescription,
+			Name: string(MethodProjectUpdate),
+			Description: "Update an existing project in Teamwork.com. The owner, start and end dates, client/company, " +
+				"tags, billing type and rate card can be set, and a budget added, in the same call. " + projectDescription,
 			Annotations: &mcp.ToolAnnotations{
 				Title: "Update Project",
 			},
 			InputSchema: &jsonschema.Schema{
 				Type: "object",
-				Properties: map[string]*jsonschema.Schema{
+				Properties: withProjectBillingProperties(map[string]*jsonschema.Schema{
+					helpers.ExpectedUpdatedAtParam: helpers.ExpectedUpdatedAtSchema("project"),
 					"id": {
 						Type:        "integer",
 						Description: "The ID of the project to update.",
@@ -171,7 +190,7 @@ func ProjectUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 						Type:        "integer",
 						Description: "The ID of the company associated with the project.",
 					},
-					"owned_id": {
+					"owner_id": {
 						Type:        "integer",
 						Description: "The ID of the user who owns the project.",
 					},
@@ -182,12 +201,13 @@ func ProjectUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 							Type: "integer",
 						},
 					},
-				},
+				}),
 				Required: []string{"id"},
 			},
 		},
 		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			var projectUpdateRequest projects.ProjectUpdateRequest
+			var expectedUpdatedAt *time.Time
 
 			var arguments map[string]any
 			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
@@ -195,22 +215,45 @@ func ProjectUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 			}
 			err := helpers.ParamGroup(arguments,
 				helpers.RequiredNumericParam(&projectUpdateRequest.Path.ID, "id"),
+				helpers.OptionalTimePointerParam(&expectedUpdatedAt, helpers.ExpectedUpdatedAtParam),
 				helpers.OptionalPointerParam(&projectUpdateRequest.Name, "name"),
 				helpers.OptionalPointerParam(&projectUpdateRequest.Description, "description"),
 				helpers.OptionalLegacyDatePointerParam(&projectUpdateRequest.StartAt, "start_at"),
 				helpers.OptionalLegacyDatePointerParam(&projectUpdateRequest.EndAt, "end_at"),
 				helpers.OptionalNumericPointerParam(&projectUpdateRequest.CompanyID, "company_id"),
+				// "owned_id" is the former name of the parameter, still accepted for
+				// backward compatibility
 				helpers.OptionalNumericPointerParam(&projectUpdateRequest.OwnerID, "owned_id"),
+				helpers.OptionalNumericPointerParam(&projectUpdateRequest.OwnerID, "owner_id"),
 				helpers.OptionalNumericListParam(&projectUpdateRequest.TagIDs, "tag_ids"),
 			)
 			if err != nil {
 				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
 			}
+			billing, err := parseProjectBilling(arguments)
+			if err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
+			}
+
+			if expectedUpdatedAt != nil {
+				projectResponse, err := projects.ProjectGet(ctx, engine, projects.NewProjectGetRequest(projectUpdateRequest.Path.ID))
+				if err != nil {
+					return helpers.HandleAPIError(err, "failed to get project")
+				}
+				conflict := helpers.CheckConflict("project", projectUpdateRequest.Path.ID, *expectedUpdatedAt,
+					projectResponse.Project.UpdatedAt)
+				if conflict != nil {
+					return conflict, nil
+				}
+			}
 
 			_, err = projects.ProjectUpdate(ctx, engine, projectUpdateRequest)
 			if err != nil {
 				return helpers.HandleAPIError(err, "failed to update project")
 			}
+			if err := billing.apply(ctx, engine, projectUpdateRequest.Path.ID); err != nil {
+				return helpers.HandleAPIError(err, "failed to update project billing")
+			}
 			return helpers.NewToolResultText("Project updated successfully"), nil
 		},
 	}
@@ -254,7 +297,9 @@ func ProjectDelete(engine *twapi.Engine) toolsets.ToolWrapper {
 			if err != nil {
 				return helpers.HandleAPIError(err, "failed to delete project")
 			}
-			return helpers.NewToolResultText("Project deleted successfully"), nil
+			result := helpers.NewToolResultText("Project deleted successfully")
+			result.Content = append(result.Content, trashRestoreHint("projects", projectDeleteRequest.Path.ID))
+			return result, nil
 		},
 	}
 }
@@ -295,6 +340,10 @@ func ProjectGet(engine *twapi.Engine) toolsets.ToolWrapper {
 				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
 			}
 
+			if passthroughEnabled(ctx) {
+				return passthrough(ctx, engine, projectGetRequest, nil, "failed to get project")
+			}
+
 			project, err := projects.ProjectGet(ctx, engine, projectGetRequest)
 			if err != nil {
 				return helpers.HandleAPIError(err, "failed to get project")
@@ -304,12 +353,14 @@ func ProjectGet(engine *twapi.Engine) toolsets.ToolWrapper {
 			if err != nil {
 				return nil, err
 			}
+			linked := helpers.WebLinker(ctx, encoded,
+				helpers.WebLinkerWithIDPathBuilder("/app/projects"),
+			)
 			return &mcp.CallToolResult{
+				Meta: helpers.WebLinkedEntitiesMeta(linked),
 				Content: []mcp.Content{
 					&mcp.TextContent{
-						Text: string(helpers.WebLinker(ctx, encoded,
-							helpers.WebLinkerWithIDPathBuilder("/app/projects"),
-						)),
+						Text: string(linked),
 					},
 				},
 				StructuredContent: project,
@@ -347,6 +398,39 @@ func ProjectList(engine *twapi.Engine) toolsets.ToolWrapper {
 						Description: "If true, the search will match projects that have all the specified tags. If false, the " +
 							"search will match projects that have any of the specified tags. Defaults to false.",
 					},
+					"status": {
+						Type: "string",
+						Description: "Filter projects by status. Archived projects are only returned with the 'archived' or " +
+							"'all' statuses. Defaults to the active and completed projects.",
+						Enum: enumSchemaValues(projectStatuses),
+					},
+					"category_ids": {
+						Type:        "array",
+						Description: "A list of project category IDs to filter projects by category.",
+						Items: &jsonschema.Schema{
+							Type: "integer",
+						},
+					},
+					"company_ids": {
+						Type:        "array",
+						Description: "A list of company IDs to filter projects by client company.",
+						Items: &jsonschema.Schema{
+							Type: "integer",
+						},
+					},
+					"project_ids": {
+						Type:        "array",
+						Description: "A list of project IDs to filter projects by ID.",
+						Items: &jsonschema.Schema{
+							Type: "integer",
+						},
+					},
+					"updated_after": {
+						Type:   "string",
+						Format: "date-time",
+						Description: "Filter projects updated after this date and time. The date format follows RFC3339 - " +
+							"YYYY-MM-DDTHH:MM:SSZ.",
+					},
 					"page": {
 						Type:        "integer",
 						Description: "Page number for pagination of results.",
@@ -355,12 +439,18 @@ func ProjectList(engine *twapi.Engine) toolsets.ToolWrapper {
 						Type:        "integer",
 						Description: "Number of results per page for pagination.",
 					},
+					"count_only": countOnlySchema(),
+					"order_by":   projectListOrder.schema("projects"),
+					"order_mode": orderModeSchema(),
 				},
 			},
 			OutputSchema: projectListOutputSchema,
 		},
 		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			var projectListRequest projects.ProjectListRequest
+			var filters projectListFilters
+			var countOnly bool
+			var orderBy, orderMode string
 
 			var arguments map[string]any
 			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
@@ -370,14 +460,37 @@ func ProjectList(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalParam(&projectListRequest.Filters.SearchTerm, "search_term"),
 				helpers.OptionalNumericListParam(&projectListRequest.Filters.TagIDs, "tag_ids"),
 				helpers.OptionalPointerParam(&projectListRequest.Filters.MatchAllTags, "match_all_tags"),
+				helpers.OptionalParam(&filters.status, "status", helpers.RestrictValues(projectStatuses...)),
+				helpers.OptionalNumericListParam(&filters.categoryIDs, "category_ids"),
+				helpers.OptionalNumericListParam(&filters.companyIDs, "company_ids"),
+				helpers.OptionalNumericListParam(&filters.projectIDs, "project_ids"),
+				helpers.OptionalTimePointerParam(&filters.updatedAfter, "updated_after"),
 				helpers.OptionalNumericParam(&projectListRequest.Filters.Page, "page"),
 				helpers.OptionalNumericParam(&projectListRequest.Filters.PageSize, "page_size"),
+				helpers.OptionalParam(&countOnly, "count_only"),
+				helpers.OptionalParam(&orderBy, "order_by", helpers.RestrictValues(projectListOrder.fields()...)),
+				helpers.OptionalParam(&orderMode, "order_mode", helpers.RestrictValues(orderModes...)),
 			)
 			if err != nil {
 				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
 			}
 
-			projectList, err := projects.ProjectList(ctx, engine, projectListRequest)
+			filteredRequest := queryRequest[projects.ProjectListRequest]{
+				request: projectListRequest,
+				query:   filters.query(),
+			}
+			if countOnly {
+				return listCount(ctx, engine, filteredRequest)
+			}
+
+			if passthroughEnabled(ctx) {
+				return passthrough(ctx, engine, filteredRequest, projectListOrder.query(orderBy, orderMode),
+					"failed to list projects")
+			}
+
+			projectList, err := executeWithQuery[*projects.ProjectListResponse](ctx, engine, filteredRequest,
+				projectListOrder.query(orderBy, orderMode),
+			)
 			if err != nil {
 				return helpers.HandleAPIError(err, "failed to list projects")
 			}
@@ -386,12 +499,14 @@ func ProjectList(engine *twapi.Engine) toolsets.ToolWrapper {
 			if err != nil {
 				return nil, err
 			}
+			linked := helpers.WebLinker(ctx, encoded,
+				helpers.WebLinkerWithIDPathBuilder("/app/projects"),
+			)
 			return &mcp.CallToolResult{
+				Meta: helpers.WebLinkedEntitiesMeta(linked),
 				Content: []mcp.Content{
 					&mcp.TextContent{
-						Text: string(helpers.WebLinker(ctx, encoded,
-							helpers.WebLinkerWithIDPathBuilder("/app/projects"),
-						)),
+						Text: string(linked),
 					},
 				},
 				StructuredContent: projectList,
@@ -399,3 +514,39 @@ func ProjectList(engine *twapi.Engine) toolsets.ToolWrapper {
 		},
 	}
 }
+
+// projectListFilters are the project list filters not supported by the SDK.
+type projectListFilters struct {
+	status       string
+	categoryIDs  []int64
+	companyIDs   []int64
+	projectIDs   []int64
+	updatedAfter *time.Time
+}
+
+// query returns the API query parameters of the filters.
+func (f projectListFilters) query() url.Values {
+	query := make(url.Values)
+	switch f.status {
+	case "active", "completed":
+		query.Set("projectStatuses", f.status)
+	case "archived":
+		query.Set("includeArchivedProjects", "true")
+		query.Set("projectStatuses", "archived")
+	case "all":
+		query.Set("includeArchivedProjects", "true")
+	}
+	if len(f.categoryIDs) > 0 {
+		query.Set("projectCategoryIds", joinIDs(f.categoryIDs))
+	}
+	if len(f.companyIDs) > 0 {
+		query.Set("projectCompanyIds", joinIDs(f.companyIDs))
+	}
+	if len(f.projectIDs) > 0 {
+		query.Set("projectIds", joinIDs(f.projectIDs))
+	}
+	if f.updatedAfter != nil {
+		query.Set("updatedAfter", f.updatedAfter.UTC().Format(time.RFC3339))
+	}
+	return query
+}
@@ -1,9 +1,13 @@
 package twprojects_test
 
 import (
+	"encoding/json"
 	"net/http"
+	"net/url"
+	"slices"
 	"testing"
 
+	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/teamwork/mcp/internal/testutil"
 	"github.com/teamwork/mcp/internal/twprojects"
 )
@@ -21,6 +25,86 @@ func TestProjectCreate(t *testing.T) {
 	})
 }
 
+func TestProjectCreateBilling(t *testing.T) {
+	tests := []struct {
+		name         string
+		budgetStatus int
+		requests     []string
+		isError      bool
+	}{{
+		name:         "billing set",
+		budgetStatus: http.StatusCreated,
+		requests: []string{
+			"POST /projects.json",
+			"PATCH /projects/api/v3/projects/123.json",
+			"POST /projects/api/v3/projects/budgets.json",
+		},
+	}, {
+		name:         "failed budget deletes the project",
+		budgetStatus: http.StatusBadRequest,
+		requests: []string{
+			"POST /projects.json",
+			"PATCH /projects/api/v3/projects/123.json",
+			"POST /projects/api/v3/projects/budgets.json",
+			"DELETE /projects/123.json",
+		},
+		isError: true,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []string
+			mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(r *http.Request) (int, []byte) {
+				if r.Method == http.MethodGet {
+					return http.StatusOK, []byte(`{}`)
+				}
+				requests = append(requests, r.Method+" "+r.URL.Path)
+				switch r.URL.Path {
+				case "/projects.json":
+					return http.StatusCreated, []byte(`{"id":"123"}`)
+				case "/projects/api/v3/projects/budgets.json":
+					var body struct {
+						Budget struct {
+							ProjectID int64  `json:"projectId"`
+							Type      string `json:"type"`
+							Capacity  int64  `json:"capacity"`
+						} `json:"budget"`
+					}
+					if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+						t.Errorf("failed to decode budget: %v", err)
+					}
+					if body.Budget.ProjectID != 123 || body.Budget.Type != "FINANCIAL" || body.Budget.Capacity != 500000 {
+						t.Errorf("unexpected budget %+v", body.Budget)
+					}
+					return tt.budgetStatus, []byte(`{}`)
+				}
+				return http.StatusOK, []byte(`{}`)
+			})
+
+			testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodProjectCreate.String(), map[string]any{
+				"name":         "Example",
+				"billing_type": "fixed_fee",
+				"rate_card_id": float64(5),
+				"budget": map[string]any{
+					"type":     "financial",
+					"capacity": float64(500000),
+				},
+			}, testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+				toolResult, ok := result.(*mcp.CallToolResult)
+				if !ok {
+					t.Fatalf("unexpected result type: %T", result)
+				}
+				if toolResult.IsError != tt.isError {
+					t.Errorf("unexpected error state %v: %v", toolResult.IsError, toolResult.Content)
+				}
+			}))
+			if !slices.Equal(requests, tt.requests) {
+				t.Errorf("expected requests %v, got %v", tt.requests, requests)
+			}
+		})
+	}
+}
+
 func TestProjectUpdate(t *testing.T) {
 	mcpServer := mcpServerMock(t, http.StatusOK, []byte(`{}`))
 	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodProjectUpdate.String(), map[string]any{
@@ -57,5 +141,34 @@ func TestProjectList(t *testing.T) {
 		"match_all_tags": true,
 		"page":           float64(1),
 		"page_size":      float64(10),
+		"order_by":       "due_date",
+		"order_mode":     "asc",
+	})
+}
+
+func TestProjectListFilters(t *testing.T) {
+	var query url.Values
+	mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(r *http.Request) (int, []byte) {
+		query = r.URL.Query()
+		return http.StatusOK, []byte(`{}`)
 	})
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodProjectList.String(), map[string]any{
+		"status":        "archived",
+		"category_ids":  []float64{1, 2},
+		"company_ids":   []float64{3},
+		"updated_after": "2025-01-01T00:00:00Z",
+	})
+
+	expected := map[string]string{
+		"projectStatuses":         "archived",
+		"includeArchivedProjects": "true",
+		"projectCategoryIds":      "1,2",
+		"projectCompanyIds":       "3",
+		"updatedAfter":            "2025-01-01T00:00:00Z",
+	}
+	for key, value := range expected {
+		if got := query.Get(key); got != value {
+			t.Errorf("expected %s=%q, got %q", key, value, got)
+		}
+	}
 }
@@ -0,0 +1,315 @@
+package twprojects
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/helpers"
+	"github.com/teamwork/mcp/internal/toolsets"
+)
+
+// List of methods available in the Teamwork.com MCP service.
+//
+// The naming convention for methods follows a pattern described here:
+// https://github.com/github/github-mcp-server/issues/333
+const (
+	MethodRecentEntities toolsets.Method = "twprojects-recent_entities"
+)
+
+// recentEntitiesMaxEntries is the maximum number of entities remembered per
+// session. Older entities are forgotten.
+const recentEntitiesMaxEntries = 20
+
+var (
+	// reCreatedEntity extracts the entity type and ID from the result of the
+	// create tools.
+	reCreatedEntity = regexp.MustCompile(`^(\w+) created successfully with ID (\d+)`)
+	// reRecentReference matches the references to recent entities accepted in
+	// the ID parameters (e.g. "last_created_task" or "last_project").
+	reRecentReference = regexp.MustCompile(`^last_(?:(created|fetched)_)?([a-z]+)$`)
+)
+
+func init() {
+	// register the toolset methods
+	toolsets.RegisterMethod(MethodRecentEntities)
+}
+
+// recentEntity is an entity created or fetched in the session.
+type recentEntity struct {
+	Type      string    `json:"type"`
+	ID        int64     `json:"id"`
+	Name      string    `json:"name,omitempty"`
+	Action    string    `json:"action"`
+	Reference string    `json:"reference"`
+	At        time.Time `json:"at"`
+}
+
+// recentEntities remembers the entities created or fetched in each session, so
+// they can be referenced in the ID parameters of the following tool calls
+// (e.g. "last_created_task") instead of copying the IDs.
+type recentEntities struct {
+	sessions *sessionStore[[]recentEntity]
+}
+
+func newRecentEntities() *recentEntities {
+	return &recentEntities{
+		sessions: newSessionStore[[]recentEntity](),
+	}
+}
+
+// push adds the entity as the most recent one, replacing any previous entry of
+// the same entity and action.
+func (r *recentEntities) push(key string, entity recentEntity) {
+	r.sessions.update(key, func(entities *[]recentEntity) {
+		*entities = slices.DeleteFunc(*entities, func(e recentEntity) bool {
+			return e.Type == entity.Type && e.ID == entity.ID && e.Action == entity.Action
+		})
+		*entities = append(*entities, entity)
+		if len(*entities) > recentEntitiesMaxEntries {
+			*entities = slices.Delete(*entities, 0, len(*entities)-recentEntitiesMaxEntries)
+		}
+	})
+}
+
+// list returns the entities of the session, the most recent first.
+func (r *recentEntities) list(key string) []recentEntity {
+	var entities []recentEntity
+	r.sessions.update(key, func(values *[]recentEntity) {
+		entities = slices.Clone(*values)
+	})
+	slices.Reverse(entities)
+	return entities
+}
+
+// lookup returns the ID of the most recent entity matching the reference.
+func (r *recentEntities) lookup(key, reference string) (int64, bool) {
+	matches := reRecentReference.FindStringSubmatch(reference)
+	if len(matches) < 3 {
+		return 0, false
+	}
+	action, entityType := matches[1], matches[2]
+	for _, entity := range r.list(key) {
+		if entity.Type == entityType && (action == "" || entity.Action == action) {
+			return entity.ID, true
+		}
+	}
+	return 0, false
+}
+
+// middleware remembers the created and fetched entities and replaces the
+// references in the ID parameters by the IDs.
+func (r *recentEntities) middleware() toolsets.ToolMiddleware {
+	return func(next mcp.ToolHandler) mcp.ToolHandler {
+		return func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			key, ok := sessionKey(ctx, request)
+			if !ok {
+				return next(ctx, request)
+			}
+			request, err := r.resolve(key, request)
+			if err != nil {
+				return helpers.NewToolResultTextError(err.Error()), nil
+			}
+			result, err := next(ctx, request)
+			if err != nil || result == nil || result.IsError {
+				return result, err
+			}
+			fetchedType := fetchedEntityType(toolsets.Method(request.Params.Name))
+			for _, content := range result.Content {
+				text, ok := content.(*mcp.TextContent)
+				if !ok {
+					continue
+				}
+				if entity, ok := createdEntity(text.Text); ok {
+					r.push(key, entity)
+				} else if entity, ok := fetchedEntity(fetchedType, text.Text); ok {
+					r.push(key, entity)
+				}
+			}
+			return result, err
+		}
+	}
+}
+
+// resolve replaces the references to recent entities in the ID parameters. The
+// request is returned unchanged when there are no references.
+func (r *recentEntities) resolve(key string, request *mcp.CallToolRequest) (*mcp.CallToolRequest, error) {
+	var arguments map[string]any
+	if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+		return request, nil
+	}
+
+	var replaced bool
+	resolveValue := func(name string, value any) (any, error) {
+		reference, ok := value.(string)
+		if !ok || !reRecentReference.MatchString(reference) {
+			return value, nil
+		}
+		id, ok := r.lookup(key, reference)
+		if !ok {
+			return nil, fmt.Errorf("invalid parameters: no recent entity matches %q in %s", reference, name)
+		}
+		replaced = true
+		return float64(id), nil
+	}
+	for name, value := range arguments {
+		switch {
+		case name == "id" || strings.HasSuffix(name, "_id"):
+			resolved, err := resolveValue(name, value)
+			if err != nil {
+				return nil, err
+			}
+			arguments[name] = resolved
+		case strings.HasSuffix(name, "_ids"):
+			values, ok := value.([]any)
+			if !ok {
+				continue
+			}
+			for i, value := range values {
+				resolved, err := resolveValue(name, value)
+				if err != nil {
+					return nil, err
+				}
+				values[i] = resolved
+			}
+		}
+	}
+	if !replaced {
+		return request, nil
+	}
+	return withArguments(request, arguments)
+}
+
+// fetchedEntityType returns the entity type retrieved by a get tool, or an
+// empty string for other tools.
+func fetchedEntityType(method toolsets.Method) string {
+	switch method {
+	case MethodUserGetMe:
+		return "user"
+	case MethodEnumsGet, MethodEntitiesGet:
+		return ""
+	}
+	entityType, ok := strings.CutPrefix(string(method), "twprojects-get_")
+	if !ok {
+		return ""
+	}
+	return entityType
+}
+
+// createdEntity extracts the entity from the result of a create tool.
+func createdEntity(text string) (recentEntity, bool) {
+	matches := reCreatedEntity.FindStringSubmatch(text)
+	if len(matches) < 3 {
+		return recentEntity{}, false
+	}
+	id, err := strconv.ParseInt(matches[2], 10, 64)
+	if err != nil {
+		return recentEntity{}, false
+	}
+	return newRecentEntity(strings.ToLower(matches[1]), id, "", "created"), true
+}
+
+// fetchedEntity extracts the entity from the result of a get tool, which has
+// the entity as the single root field besides the metadata.
+func fetchedEntity(entityType, text string) (recentEntity, bool) {
+	if entityType == "" {
+		return recentEntity{}, false
+	}
+	var decoded map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(text), &decoded); err != nil {
+		return recentEntity{}, false
+	}
+	for field, value := range decoded {
+		if field == "meta" || field == "included" {
+			continue
+		}
+		var entity struct {
+			ID        int64  `json:"id"`
+			Name      string `json:"name"`
+			FirstName string `json:"firstName"`
+			LastName  string `json:"lastName"`
+		}
+		if err := json.Unmarshal(value, &entity); err != nil || entity.ID == 0 {
+			continue
+		}
+		name := entity.Name
+		if name == "" {
+			name = strings.TrimSpace(entity.FirstName + " " + entity.LastName)
+		}
+		return newRecentEntity(entityType, entity.ID, name, "fetched"), true
+	}
+	return recentEntity{}, false
+}
+
+func newRecentEntity(entityType string, id int64, name, action string) recentEntity {
+	return recentEntity{
+		Type:      entityType,
+		ID:        id,
+		Name:      name,
+		Action:    action,
+		Reference: "last_" + action + "_" + entityType,
+		At:        time.Now().UTC(),
+	}
+}
+
+// RecentEntities lists the entities created or fetched in the session.
+func RecentEntities(recent *recentEntities) toolsets.ToolWrapper {
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name: string(MethodRecentEntities),
+			Description: "List the entities recently created or fetched in this session, the most recent first. " +
+				"Instead of copying IDs, any ID parameter accepts a reference to a recent entity: " +
+				"\"last_created_<type>\", \"last_fetched_<type>\" or \"last_<type>\" (e.g. \"last_created_task\" or " +
+				"\"last_project\").",
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "Recent Entities",
+				ReadOnlyHint: true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"type": {
+						Type:        "string",
+						Description: "Only list the entities of this type (e.g. task, project or user).",
+					},
+				},
+			},
+		},
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var entityType string
+
+			var arguments map[string]any
+			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("failed to decode request: %s", err.Error())), nil
+			}
+			err := helpers.ParamGroup(arguments,
+				helpers.OptionalParam(&entityType, "type"),
+			)
+			if err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
+			}
+
+			key, ok := sessionKey(ctx, request)
+			if !ok {
+				return helpers.NewToolResultTextError("recent entities are not available without a session"), nil
+			}
+			entities := recent.list(key)
+			if entityType != "" {
+				entities = slices.DeleteFunc(entities, func(entity recentEntity) bool {
+					return entity.Type != strings.ToLower(entityType)
+				})
+			}
+			if entities == nil {
+				entities = []recentEntity{}
+			}
+			return helpers.NewToolResultJSON(map[string]any{"entities": entities})
+		},
+	}
+}
@@ -0,0 +1,47 @@
+package twprojects_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/testutil"
+	"github.com/teamwork/mcp/internal/twprojects"
+)
+
+func TestRecentEntities(t *testing.T) {
+	runSessions(t, func(t *testing.T, withSession testutil.ExecuteToolRequestOption) {
+		mcpServer := mcpServerMock(t, http.StatusOK, []byte(`{"task":{"id":123,"name":"Write docs"}}`))
+		testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTaskGet.String(), map[string]any{
+			"id": float64(123),
+		}, withSession)
+
+		testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodRecentEntities.String(), map[string]any{
+			"type": "task",
+		}, withSession, testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+			toolResult, ok := result.(*mcp.CallToolResult)
+			if !ok || toolResult.IsError || len(toolResult.Content) == 0 {
+				t.Fatalf("unexpected result: %v", result)
+			}
+			text, ok := toolResult.Content[0].(*mcp.TextContent)
+			if !ok || !strings.Contains(text.Text, `"reference":"last_fetched_task"`) {
+				t.Errorf("unexpected entities: %v", toolResult.Content[0])
+			}
+		}))
+
+		// the reference is replaced by the ID of the fetched task
+		testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTaskGet.String(), map[string]any{
+			"id": "last_task",
+		}, withSession)
+
+		testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTaskGet.String(), map[string]any{
+			"id": "last_created_task",
+		}, withSession, testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+			toolResult, ok := result.(*mcp.CallToolResult)
+			if !ok || !toolResult.IsError {
+				t.Errorf("expected an error for an unknown reference: %v", result)
+			}
+		}))
+	})
+}
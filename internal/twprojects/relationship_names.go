@@ -0,0 +1,208 @@
+package twprojects
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/toolsets"
+)
+
+// relationshipNamesMaxEntries is the maximum number of names cached per
+// session. New names are ignored once the limit is reached.
+const relationshipNamesMaxEntries = 5000
+
+// relationshipNameTypes are the entity types whose names are cached, in the
+// singular form.
+var relationshipNameTypes = []string{"user", "project", "company", "tag"}
+
+// relationshipNames caches the names of the entities seen in the tool results
+// of each session, annotating the bare relationships (only type and ID) of the
+// following results with the name. This avoids follow-up calls only to resolve
+// who or what a relationship refers to.
+type relationshipNames struct {
+	sessions *sessionStore[map[string]string]
+}
+
+func newRelationshipNames() *relationshipNames {
+	return &relationshipNames{
+		sessions: newSessionStore[map[string]string](),
+	}
+}
+
+// middleware populates the cache with the tool results and annotates their
+// relationships. Only the text content is changed, as the structured content
+// must follow the output schema of the tool.
+func (r *relationshipNames) middleware() toolsets.ToolMiddleware {
+	return func(next mcp.ToolHandler) mcp.ToolHandler {
+		return func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := next(ctx, request)
+			if err != nil || result == nil || result.IsError {
+				return result, err
+			}
+			if key, ok := sessionKey(ctx, request); ok {
+				r.process(key, result)
+			}
+			return result, err
+		}
+	}
+}
+
+func (r *relationshipNames) process(key string, result *mcp.CallToolResult) {
+	for _, content := range result.Content {
+		text, ok := content.(*mcp.TextContent)
+		if !ok {
+			continue
+		}
+		decoder := json.NewDecoder(strings.NewReader(text.Text))
+		decoder.UseNumber()
+		var decoded map[string]any
+		if err := decoder.Decode(&decoded); err != nil {
+			continue
+		}
+
+		var annotated bool
+		r.sessions.update(key, func(names *map[string]string) {
+			if *names == nil {
+				*names = make(map[string]string)
+			}
+			collectRelationshipNames(*names, decoded)
+			annotated = annotateRelationships(*names, decoded)
+		})
+		if !annotated {
+			continue
+		}
+
+		var encoded bytes.Buffer
+		encoder := json.NewEncoder(&encoded)
+		encoder.SetEscapeHTML(false)
+		if err := encoder.Encode(decoded); err == nil {
+			text.Text = strings.TrimSuffix(encoded.String(), "\n")
+		}
+	}
+}
+
+// collectRelationshipNames stores the names of the entities in the root fields
+// and in the included section of the response.
+func collectRelationshipNames(names map[string]string, decoded map[string]any) {
+	collect := func(key string, value any) {
+		entityType := normalizeEntityType(key)
+		if !slices.Contains(relationshipNameTypes, entityType) {
+			return
+		}
+		switch v := value.(type) {
+		case map[string]any:
+			if _, ok := v["id"]; ok {
+				storeRelationshipName(names, entityType, "", v)
+				return
+			}
+			// included entities are indexed by ID
+			for id, item := range v {
+				if object, ok := item.(map[string]any); ok {
+					storeRelationshipName(names, entityType, id, object)
+				}
+			}
+		case []any:
+			for _, item := range v {
+				if object, ok := item.(map[string]any); ok {
+					storeRelationshipName(names, entityType, "", object)
+				}
+			}
+		}
+	}
+
+	for key, value := range decoded {
+		if key != "included" {
+			collect(key, value)
+			continue
+		}
+		if included, ok := value.(map[string]any); ok {
+			for includedKey, includedValue := range included {
+				collect(includedKey, includedValue)
+			}
+		}
+	}
+}
+
+func storeRelationshipName(names map[string]string, entityType, id string, object map[string]any) {
+	if len(names) >= relationshipNamesMaxEntries {
+		return
+	}
+	if id == "" {
+		id = relationshipID(object["id"])
+	}
+	if id == "" {
+		return
+	}
+
+	name, _ := object["name"].(string)
+	if name == "" {
+		firstName, _ := object["firstName"].(string)
+		lastName, _ := object["lastName"].(string)
+		name = strings.TrimSpace(firstName + " " + lastName)
+	}
+	if name != "" {
+		names[entityType+"/"+id] = name
+	}
+}
+
+// annotateRelationships adds the cached name to the bare relationships found
+// anywhere in the response. It reports whether any relationship was annotated.
+func annotateRelationships(names map[string]string, value any) bool {
+	var annotated bool
+	switch v := value.(type) {
+	case map[string]any:
+		if isBareRelationship(v) {
+			entityType, _ := v["type"].(string)
+			if name, ok := names[normalizeEntityType(entityType)+"/"+relationshipID(v["id"])]; ok {
+				v["name"] = name
+				return true
+			}
+		}
+		for _, item := range v {
+			annotated = annotateRelationships(names, item) || annotated
+		}
+	case []any:
+		for _, item := range v {
+			annotated = annotateRelationships(names, item) || annotated
+		}
+	}
+	return annotated
+}
+
+// isBareRelationship checks if the object is a relationship reference, which
+// only contains the type and ID, optionally with metadata.
+func isBareRelationship(object map[string]any) bool {
+	if _, ok := object["name"]; ok {
+		return false
+	}
+	if _, ok := object["type"].(string); !ok {
+		return false
+	}
+	if _, ok := object["id"]; !ok {
+		return false
+	}
+	for key := range object {
+		if key != "id" && key != "type" && key != "meta" {
+			return false
+		}
+	}
+	return true
+}
+
+func relationshipID(id any) string {
+	switch v := id.(type) {
+	case json.Number:
+		return v.String()
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return ""
+	}
+}
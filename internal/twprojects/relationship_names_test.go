@@ -0,0 +1,41 @@
+package twprojects_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/testutil"
+	"github.com/teamwork/mcp/internal/twprojects"
+)
+
+func TestRelationshipNames(t *testing.T) {
+	runSessions(t, func(t *testing.T, withSession testutil.ExecuteToolRequestOption) {
+		mcpServer := mcpServerMock(t, http.StatusOK, []byte(`{"people":[{"id":5,"firstName":"Ana","lastName":"Lopez"}],`+
+			`"tasks":[{"id":1,"name":"Example","assignees":[{"id":5,"type":"users"},{"id":6,"type":"users"}]}]}`))
+
+		// the names of the users are cached from a previous response
+		testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodUserList.String(), map[string]any{}, withSession)
+
+		testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTaskList.String(), map[string]any{},
+			withSession,
+			testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+				toolResult, ok := result.(*mcp.CallToolResult)
+				if !ok {
+					t.Fatalf("unexpected result type: %T", result)
+				}
+				if toolResult.IsError {
+					t.Fatalf("tool failed to execute: %v", toolResult.Content)
+				}
+				text := toolResult.Content[0].(*mcp.TextContent).Text
+				if !strings.Contains(text, `{"id":5,"name":"Ana Lopez","type":"users"}`) {
+					t.Errorf("expected assignee annotated with the name, got %s", text)
+				}
+				if !strings.Contains(text, `{"id":6,"type":"users"}`) {
+					t.Errorf("expected unknown assignee without name, got %s", text)
+				}
+			}),
+		)
+	})
+}
@@ -0,0 +1,317 @@
+package twprojects
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/config"
+	"github.com/teamwork/mcp/internal/helpers"
+	"github.com/teamwork/mcp/internal/toolsets"
+)
+
+// List of methods available in the Teamwork.com MCP service.
+//
+// The naming convention for methods follows a pattern described here:
+// https://github.com/github/github-mcp-server/issues/333
+const (
+	MethodReportRun toolsets.Method = "twprojects-run_report"
+)
+
+// reReportPlaceholder matches a value that is a single argument reference.
+var reReportPlaceholder = regexp.MustCompile(`^\{\{[^{}]+\}\}$`)
+
+// reportPromptPrefix is the prefix of the prompts generated for each report
+// template.
+const reportPromptPrefix = "twprojects-report_"
+
+func init() {
+	// register the toolset methods
+	toolsets.RegisterMethod(MethodReportRun)
+}
+
+// ReportRun runs an operator-defined report template, calling the tools of
+// each section and assembling the results into a single Markdown document.
+// Only the provided tools can be referenced by the sections. Large documents
+// are spilled to the exports store, returning a link to their resource.
+func ReportRun(
+	templates []config.ReportTemplate,
+	tools []toolsets.ToolWrapper,
+	exports *helpers.SpillStore,
+) toolsets.ToolWrapper {
+	names := make([]any, len(templates))
+	var descriptions []string
+	for i, template := range templates {
+		names[i] = template.Name
+		description := fmt.Sprintf("'%s'", template.Name)
+		if template.Description != "" {
+			description += ": " + template.Description
+		}
+		if len(template.Arguments) > 0 {
+			var arguments []string
+			for _, argument := range template.Arguments {
+				arguments = append(arguments, reportArgumentDescription(argument))
+			}
+			description += " (arguments: " + strings.Join(arguments, "; ") + ")"
+		}
+		descriptions = append(descriptions, description)
+	}
+
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name: string(MethodReportRun),
+			Description: "Run a report defined by the operator, assembling data from multiple Teamwork.com sources into " +
+				"a single Markdown document. Available reports: " + strings.Join(descriptions, ". ") + ".",
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "Run Report",
+				ReadOnlyHint: true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"name": {
+						Type:        "string",
+						Description: "The name of the report to run.",
+						Enum:        names,
+					},
+					"arguments": {
+						Type:        "object",
+						Description: "The arguments of the report, as described in the report list.",
+					},
+				},
+				Required: []string{"name"},
+			},
+		},
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var name string
+			var reportArguments map[string]any
+
+			var arguments map[string]any
+			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("failed to decode request: %s", err.Error())), nil
+			}
+			err := helpers.ParamGroup(arguments,
+				helpers.RequiredParam(&name, "name"),
+				helpers.OptionalParam(&reportArguments, "arguments"),
+			)
+			if err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
+			}
+
+			index := slices.IndexFunc(templates, func(template config.ReportTemplate) bool {
+				return template.Name == name
+			})
+			if index == -1 {
+				return helpers.NewToolResultTextError(fmt.Sprintf("report %q not found", name)), nil
+			}
+
+			document := exports.NewWriter(ctx)
+			if err := runReport(ctx, document, templates[index], tools, reportArguments); err != nil {
+				return helpers.NewToolResultTextError(err.Error()), nil
+			}
+			return document.Result(name+".md", "text/markdown")
+		},
+	}
+}
+
+// ReportPrompts creates a prompt for each report template. The prompt runs the
+// report server-side and returns the document for the LLM to analyze.
+func ReportPrompts(templates []config.ReportTemplate, tools []toolsets.ToolWrapper) []toolsets.ServerPrompt {
+	prompts := make([]toolsets.ServerPrompt, 0, len(templates))
+	for _, template := range templates {
+		prompt := &mcp.Prompt{
+			Name:        reportPromptPrefix + template.Name,
+			Title:       template.Title,
+			Description: template.Description,
+		}
+		for _, argument := range template.Arguments {
+			prompt.Arguments = append(prompt.Arguments, &mcp.PromptArgument{
+				Name:        argument.Name,
+				Description: argument.Description,
+				Required:    argument.Required,
+			})
+		}
+
+		prompts = append(prompts, toolsets.NewServerPrompt(prompt,
+			func(ctx context.Context, request *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+				reportArguments := make(map[string]any, len(request.Params.Arguments))
+				for key, value := range request.Params.Arguments {
+					// prompt arguments are always strings, but most tools expect numeric IDs
+					if number, err := strconv.ParseFloat(value, 64); err == nil {
+						reportArguments[key] = number
+					} else {
+						reportArguments[key] = value
+					}
+				}
+
+				var document strings.Builder
+				if err := runReport(ctx, &document, template, tools, reportArguments); err != nil {
+					return nil, err
+				}
+
+				instructions := "Analyze the following report and summarize the key findings."
+				if template.Description != "" {
+					instructions = "Analyze the following report (" + template.Description + ") and summarize the key " +
+						"findings."
+				}
+				return &mcp.GetPromptResult{
+					Description: template.Description,
+					Messages: []*mcp.PromptMessage{
+						{
+							Role: "user",
+							Content: &mcp.TextContent{
+								Text: instructions + "\n\n" + document.String(),
+							},
+						},
+					},
+				}, nil
+			},
+		))
+	}
+	return prompts
+}
+
+// runReport calls the tools of each report section and writes the results to
+// the document in Markdown. Failed sections are reported inline, so a single
+// failure doesn't discard the whole report.
+func runReport(
+	ctx context.Context,
+	document io.Writer,
+	template config.ReportTemplate,
+	tools []toolsets.ToolWrapper,
+	arguments map[string]any,
+) error {
+	for _, argument := range template.Arguments {
+		if _, ok := arguments[argument.Name]; argument.Required && !ok {
+			return fmt.Errorf("missing required argument %q for report %q", argument.Name, template.Name)
+		}
+	}
+
+	title := template.Title
+	if title == "" {
+		title = template.Name
+	}
+	fmt.Fprintf(document, "# %s\n", title)
+	if template.Description != "" {
+		fmt.Fprintf(document, "\n%s\n", template.Description)
+	}
+
+	for _, section := range template.Sections {
+		sectionTitle := section.Title
+		if sectionTitle == "" {
+			sectionTitle = section.Tool
+		}
+		fmt.Fprintf(document, "\n## %s\n\n", sectionTitle)
+
+		content, err := runReportSection(ctx, section, tools, arguments)
+		if err != nil {
+			fmt.Fprintf(document, "_Failed to load section: %s_\n", err)
+			continue
+		}
+		if _, err := io.WriteString(document, content); err != nil {
+			return fmt.Errorf("failed to write report: %w", err)
+		}
+	}
+	return nil
+}
+
+func runReportSection(
+	ctx context.Context,
+	section config.ReportSection,
+	tools []toolsets.ToolWrapper,
+	arguments map[string]any,
+) (string, error) {
+	index := slices.IndexFunc(tools, func(tool toolsets.ToolWrapper) bool {
+		return tool.Tool.Name == section.Tool
+	})
+	if index == -1 {
+		return "", fmt.Errorf("tool %q is not available for reports", section.Tool)
+	}
+
+	encodedArguments, err := json.Marshal(replaceReportArguments(section.Arguments, arguments))
+	if err != nil {
+		return "", fmt.Errorf("failed to encode arguments: %w", err)
+	}
+
+	result, err := tools[index].Handler(ctx, &mcp.CallToolRequest{
+		Params: &mcp.CallToolParamsRaw{
+			Name:      section.Tool,
+			Arguments: encodedArguments,
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var content strings.Builder
+	for _, item := range result.Content {
+		text, ok := item.(*mcp.TextContent)
+		if !ok {
+			continue
+		}
+		if result.IsError {
+			return "", errors.New(text.Text)
+		}
+		if json.Valid([]byte(text.Text)) {
+			fmt.Fprintf(&content, "```json\n%s\n```\n", text.Text)
+		} else {
+			fmt.Fprintf(&content, "%s\n", text.Text)
+		}
+	}
+	return content.String(), nil
+}
+
+// replaceReportArguments replaces the "{{name}}" references in the section
+// arguments with the report arguments. When a value is a single reference, the
+// argument value is used as is, preserving its type.
+func replaceReportArguments(value any, arguments map[string]any) any {
+	switch v := value.(type) {
+	case string:
+		for name, argument := range arguments {
+			placeholder := "{{" + name + "}}"
+			if v == placeholder {
+				return argument
+			}
+			v = strings.ReplaceAll(v, placeholder, fmt.Sprint(argument))
+		}
+		return v
+	case map[string]any:
+		replaced := make(map[string]any, len(v))
+		for key, item := range v {
+			item = replaceReportArguments(item, arguments)
+			if text, ok := item.(string); ok && reReportPlaceholder.MatchString(text) {
+				// optional arguments not provided are omitted
+				continue
+			}
+			replaced[key] = item
+		}
+		return replaced
+	case []any:
+		replaced := make([]any, len(v))
+		for i, item := range v {
+			replaced[i] = replaceReportArguments(item, arguments)
+		}
+		return replaced
+	default:
+		return value
+	}
+}
+
+func reportArgumentDescription(argument config.ReportArgument) string {
+	description := argument.Name
+	if argument.Required {
+		description += " (required)"
+	}
+	if argument.Description != "" {
+		description += ": " + argument.Description
+	}
+	return description
+}
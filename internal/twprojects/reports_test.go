@@ -0,0 +1,75 @@
+package twprojects_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/config"
+	"github.com/teamwork/mcp/internal/testutil"
+	"github.com/teamwork/mcp/internal/twprojects"
+)
+
+var reportTemplates = []config.ReportTemplate{{
+	Name:  "project-status",
+	Title: "Project Status",
+	Arguments: []config.ReportArgument{
+		{Name: "project_id", Required: true},
+	},
+	Sections: []config.ReportSection{{
+		Title:     "Project",
+		Tool:      twprojects.MethodProjectGet.String(),
+		Arguments: map[string]any{"id": "{{project_id}}"},
+	}, {
+		Title:     "Tasks",
+		Tool:      twprojects.MethodTaskListByProject.String(),
+		Arguments: map[string]any{"project_id": "{{project_id}}", "search_term": "{{search_term}}"},
+	}},
+}}
+
+func TestReportRun(t *testing.T) {
+	mcpServer := mcpServerMock(t, http.StatusOK, []byte(`{}`), twprojects.WithReportTemplates(reportTemplates))
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodReportRun.String(), map[string]any{
+		"name": "project-status",
+		"arguments": map[string]any{
+			"project_id": float64(123),
+		},
+	}, testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+		t.Helper()
+
+		toolResult, ok := result.(*mcp.CallToolResult)
+		if !ok {
+			t.Fatalf("unexpected result type: %T", result)
+		}
+		if toolResult.IsError {
+			t.Fatalf("tool failed to execute: %v", toolResult.Content)
+		}
+		document := toolResult.Content[0].(*mcp.TextContent).Text
+		for _, expected := range []string{"# Project Status", "## Project", "## Tasks"} {
+			if !strings.Contains(document, expected) {
+				t.Errorf("expected report to contain %q, got:\n%s", expected, document)
+			}
+		}
+		if strings.Contains(document, "Failed to load section") {
+			t.Errorf("expected all sections to load, got:\n%s", document)
+		}
+	}))
+}
+
+func TestReportRunMissingArgument(t *testing.T) {
+	mcpServer := mcpServerMock(t, http.StatusOK, []byte(`{}`), twprojects.WithReportTemplates(reportTemplates))
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodReportRun.String(), map[string]any{
+		"name": "project-status",
+	}, testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+		t.Helper()
+
+		toolResult, ok := result.(*mcp.CallToolResult)
+		if !ok {
+			t.Fatalf("unexpected result type: %T", result)
+		}
+		if !toolResult.IsError {
+			t.Errorf("expected tool to fail without the required argument")
+		}
+	}))
+}
@@ -0,0 +1,344 @@
+package twprojects
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/config"
+	"github.com/teamwork/mcp/internal/helpers"
+	"github.com/teamwork/mcp/internal/toolsets"
+	twapi "github.com/teamwork/twapi-go-sdk"
+)
+
+// List of methods available in the Teamwork.com MCP service.
+//
+// The naming convention for methods follows a pattern described here:
+// https://github.com/github/github-mcp-server/issues/333
+const (
+	MethodRoleTemplateApply toolsets.Method = "twprojects-apply_role_template"
+)
+
+func init() {
+	// register the toolset methods
+	toolsets.RegisterMethod(MethodRoleTemplateApply)
+}
+
+// projectPermissionsGetRequest represents the request to get the permissions
+// of a user in a project.
+type projectPermissionsGetRequest struct {
+	ProjectID int64
+	UserID    int64
+}
+
+// HTTPRequest creates an HTTP request for the projectPermissionsGetRequest.
+func (p projectPermissionsGetRequest) HTTPRequest(ctx context.Context, server string) (*http.Request, error) {
+	uri := server + "/projects/" + strconv.FormatInt(p.ProjectID, 10) + "/people/" +
+		strconv.FormatInt(p.UserID, 10) + ".json"
+	return http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+}
+
+// projectPermissionsGetResponse contains the permissions of a user in a
+// project. The API returns the flags as booleans, numbers or "0"/"1" strings.
+type projectPermissionsGetResponse struct {
+	Person struct {
+		Permissions map[string]any `json:"permissions"`
+	} `json:"person"`
+}
+
+// HandleHTTPResponse handles the HTTP response for the
+// projectPermissionsGetResponse. If some unexpected HTTP status code is
+// returned by the API, a twapi.HTTPError is returned.
+func (p *projectPermissionsGetResponse) HandleHTTPResponse(resp *http.Response) error {
+	if resp.StatusCode != http.StatusOK {
+		return twapi.NewHTTPError(resp, "failed to get project permissions")
+	}
+	if err := json.NewDecoder(resp.Body).Decode(p); err != nil {
+		return fmt.Errorf("failed to decode get project permissions response: %w", err)
+	}
+	return nil
+}
+
+// permission returns the flag of a permission, false when it isn't set.
+func (p *projectPermissionsGetResponse) permission(name string) bool {
+	switch value := p.Person.Permissions[name].(type) {
+	case bool:
+		return value
+	case float64:
+		return value != 0
+	case string:
+		enabled, _ := strconv.ParseBool(value)
+		return enabled
+	}
+	return false
+}
+
+// projectPermissionsUpdateRequest represents the request to update the
+// permissions of a user in a project.
+type projectPermissionsUpdateRequest struct {
+	ProjectID   int64           `json:"-"`
+	UserID      int64           `json:"-"`
+	Permissions map[string]bool `json:"permissions"`
+}
+
+// HTTPRequest creates an HTTP request for the projectPermissionsUpdateRequest.
+func (p projectPermissionsUpdateRequest) HTTPRequest(ctx context.Context, server string) (*http.Request, error) {
+	uri := server + "/projects/" + strconv.FormatInt(p.ProjectID, 10) + "/people/" +
+		strconv.FormatInt(p.UserID, 10) + ".json"
+
+	var body bytes.Buffer
+	if err := json.NewEncoder(&body).Encode(p); err != nil {
+		return nil, fmt.Errorf("failed to encode update project permissions request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, uri, &body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+// projectPermissionsUpdateResponse represents the response of updating the
+// permissions of a user in a project.
+type projectPermissionsUpdateResponse struct{}
+
+// HandleHTTPResponse handles the HTTP response for the
+// projectPermissionsUpdateResponse. If some unexpected HTTP status code is
+// returned by the API, a twapi.HTTPError is returned.
+func (p *projectPermissionsUpdateResponse) HandleHTTPResponse(resp *http.Response) error {
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return twapi.NewHTTPError(resp, "failed to update project permissions")
+	}
+	return nil
+}
+
+// permissionDifference is a permission of a user differing from the role
+// template.
+type permissionDifference struct {
+	Permission string `json:"permission"`
+	Current    bool   `json:"current"`
+	Expected   bool   `json:"expected"`
+}
+
+// projectPermissionsDiff contains the differences of the user permissions in a
+// project against the role template.
+type projectPermissionsDiff struct {
+	ProjectID   int64                  `json:"projectId"`
+	Differences []permissionDifference `json:"differences"`
+	Applied     bool                   `json:"applied"`
+	Error       string                 `json:"error,omitempty"`
+}
+
+// roleTemplateResult is the result of comparing the user permissions against a
+// role template.
+type roleTemplateResult struct {
+	UserID       int64                    `json:"userId"`
+	Template     string                   `json:"template"`
+	Apply        bool                     `json:"apply"`
+	Consistent   int                      `json:"consistent"`
+	Inconsistent int                      `json:"inconsistent"`
+	Projects     []projectPermissionsDiff `json:"projects"`
+}
+
+// diffPermissions returns the permissions differing from the expected ones,
+// sorted by name.
+func diffPermissions(expected map[string]bool, current *projectPermissionsGetResponse) []permissionDifference {
+	differences := []permissionDifference{}
+	for name, value := range expected {
+		if current.permission(name) != value {
+			differences = append(differences, permissionDifference{
+				Permission: name,
+				Current:    !value,
+				Expected:   value,
+			})
+		}
+	}
+	sort.Slice(differences, func(i, j int) bool {
+		return differences[i].Permission < differences[j].Permission
+	})
+	return differences
+}
+
+// RoleTemplateApply compares the permissions of a user in many projects
+// against a role template in Teamwork.com, optionally applying the template.
+func RoleTemplateApply(engine *twapi.Engine, templates []config.RoleTemplate) toolsets.ToolWrapper {
+	templateNames := make([]string, len(templates))
+	for i, template := range templates {
+		templateNames[i] = template.Name
+	}
+
+	templateSchema := &jsonschema.Schema{
+		Type: "string",
+		Description: "The name of the role template configured by the operator. Either template or permissions is " +
+			"required.",
+	}
+	if len(templateNames) > 0 {
+		templateSchema.Enum = enumSchemaValues(templateNames)
+		for _, template := range templates {
+			if template.Description != "" {
+				templateSchema.Description += fmt.Sprintf(" '%s': %s.", template.Name, template.Description)
+			}
+		}
+	}
+
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name: string(MethodRoleTemplateApply),
+			Description: "Compare the permissions of a user in one or more projects in Teamwork.com against a role " +
+				"template, a named set of project permissions, listing the differences by project. With apply=true " +
+				"the differing permissions are changed to match the template, keeping the permissions consistent " +
+				"across many projects. Use apply=false first to review the changes.",
+			Annotations: &mcp.ToolAnnotations{
+				Title: "Apply Role Template",
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"user_id": {
+						Type:        "integer",
+						Description: "The ID of the user.",
+					},
+					"project_ids": {
+						Type:        "array",
+						Description: "The IDs of the projects where the permissions of the user are compared.",
+						MinItems:    twapi.Ptr(1),
+						Items: &jsonschema.Schema{
+							Type: "integer",
+						},
+					},
+					"template": templateSchema,
+					"permissions": {
+						Type: "object",
+						Description: "An ad-hoc role template, mapping permission names to whether they are granted. " +
+							"Used when the template parameter isn't provided.",
+						PropertyNames: &jsonschema.Schema{
+							Enum: enumSchemaValues(projectPermissions),
+						},
+						AdditionalProperties: &jsonschema.Schema{
+							Type: "boolean",
+						},
+					},
+					"apply": {
+						Type:        "boolean",
+						Description: "Whether the differing permissions are changed to match the template. Defaults to false.",
+					},
+				},
+				Required: []string{"user_id", "project_ids"},
+			},
+		},
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var userID int64
+			var projectIDs []int64
+			var templateName string
+			var apply bool
+
+			var arguments map[string]any
+			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("failed to decode request: %s", err.Error())), nil
+			}
+			err := helpers.ParamGroup(arguments,
+				helpers.RequiredNumericParam(&userID, "user_id"),
+				helpers.OptionalNumericListParam(&projectIDs, "project_ids"),
+				helpers.OptionalParam(&templateName, "template"),
+				helpers.OptionalParam(&apply, "apply"),
+			)
+			if err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
+			}
+			if len(projectIDs) == 0 {
+				return helpers.NewToolResultTextError("invalid parameters: parameter project_ids is required"), nil
+			}
+
+			var permissions map[string]bool
+			switch {
+			case templateName != "":
+				index := slices.IndexFunc(templates, func(template config.RoleTemplate) bool {
+					return template.Name == templateName
+				})
+				if index == -1 {
+					return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: unknown role template %q, "+
+						"available templates: %s", templateName, strings.Join(templateNames, ", "))), nil
+				}
+				permissions = templates[index].Permissions
+			case arguments["permissions"] != nil:
+				templateName = "custom"
+				var params struct {
+					Permissions map[string]bool `json:"permissions"`
+				}
+				if err := json.Unmarshal(request.Params.Arguments, &params); err != nil {
+					return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: invalid permissions: %s",
+						err.Error())), nil
+				}
+				permissions = params.Permissions
+			}
+			if len(permissions) == 0 {
+				return helpers.NewToolResultTextError("invalid parameters: template or permissions is required"), nil
+			}
+			for name := range permissions {
+				if !slices.Contains(projectPermissions, name) {
+					return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: unknown permission %q",
+						name)), nil
+				}
+			}
+
+			result := roleTemplateResult{
+				UserID:   userID,
+				Template: templateName,
+				Apply:    apply,
+				Projects: make([]projectPermissionsDiff, 0, len(projectIDs)),
+			}
+			for _, projectID := range projectIDs {
+				diff := projectPermissionsDiff{ProjectID: projectID, Differences: []permissionDifference{}}
+
+				current, err := twapi.Execute[projectPermissionsGetRequest, *projectPermissionsGetResponse](ctx, engine,
+					projectPermissionsGetRequest{ProjectID: projectID, UserID: userID},
+				)
+				if err != nil {
+					diff.Error = fmt.Sprintf("failed to get permissions: %s", err.Error())
+					result.Projects = append(result.Projects, diff)
+					continue
+				}
+				diff.Differences = diffPermissions(permissions, current)
+				if len(diff.Differences) == 0 {
+					result.Consistent++
+				} else {
+					result.Inconsistent++
+				}
+
+				if apply && len(diff.Differences) > 0 {
+					updateRequest := projectPermissionsUpdateRequest{
+						ProjectID:   projectID,
+						UserID:      userID,
+						Permissions: make(map[string]bool, len(diff.Differences)),
+					}
+					for _, difference := range diff.Differences {
+						updateRequest.Permissions[difference.Permission] = difference.Expected
+					}
+					_, err := twapi.Execute[projectPermissionsUpdateRequest, *projectPermissionsUpdateResponse](ctx,
+						engine, updateRequest)
+					if err != nil {
+						diff.Error = fmt.Sprintf("failed to update permissions: %s", err.Error())
+					} else {
+						diff.Applied = true
+					}
+				}
+				result.Projects = append(result.Projects, diff)
+			}
+
+			encoded, err := json.Marshal(result)
+			if err != nil {
+				return nil, err
+			}
+			return helpers.NewToolResultText("%s", encoded), nil
+		},
+	}
+}
@@ -0,0 +1,70 @@
+package twprojects_test
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/config"
+	"github.com/teamwork/mcp/internal/testutil"
+	"github.com/teamwork/mcp/internal/twprojects"
+)
+
+func TestRoleTemplateApply(t *testing.T) {
+	var updates []string
+	mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(r *http.Request) (int, []byte) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /projects/1/people/7.json":
+			return http.StatusOK, []byte(`{"person":{"permissions":{"add-tasks":"1","view-time":"1"}}}`)
+		case "GET /projects/2/people/7.json":
+			return http.StatusOK, []byte(`{"person":{"permissions":{"add-tasks":false,"view-time":true,` +
+				`"project-administrator":true}}}`)
+		case "PUT /projects/2/people/7.json":
+			content, err := io.ReadAll(r.Body)
+			if err != nil {
+				t.Fatalf("failed to read request body: %v", err)
+			}
+			updates = append(updates, strings.TrimSpace(string(content)))
+			return http.StatusOK, []byte(`{}`)
+		}
+		t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		return http.StatusNotFound, nil
+	}, twprojects.WithRoleTemplates([]config.RoleTemplate{{
+		Name: "developer",
+		Permissions: map[string]bool{
+			"add-tasks":             true,
+			"view-time":             true,
+			"project-administrator": false,
+		},
+	}}))
+
+	executePreviewedToolRequest(t, mcpServer, twprojects.MethodRoleTemplateApply.String(), map[string]any{
+		"user_id":     float64(7),
+		"project_ids": []any{float64(1), float64(2)},
+		"template":    "developer",
+		"apply":       true,
+	}, testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+		toolResult, ok := result.(*mcp.CallToolResult)
+		if !ok {
+			t.Fatalf("unexpected result type: %T", result)
+		}
+		if toolResult.IsError {
+			t.Fatalf("tool failed to execute: %v", toolResult.Content)
+		}
+		text := toolResult.Content[0].(*mcp.TextContent).Text
+		expected := `{"userId":7,"template":"developer","apply":true,"consistent":1,"inconsistent":1,"projects":[` +
+			`{"projectId":1,"differences":[],"applied":false},` +
+			`{"projectId":2,"differences":[{"permission":"add-tasks","current":false,"expected":true},` +
+			`{"permission":"project-administrator","current":true,"expected":false}],"applied":true}]}`
+		if text != expected {
+			t.Errorf("expected %s, got %s", expected, text)
+		}
+	}))
+
+	expected := `{"permissions":{"add-tasks":true,"project-administrator":false}}`
+	if len(updates) != 1 || updates[0] != expected {
+		t.Errorf("expected update %s, got %v", expected, updates)
+	}
+}
@@ -0,0 +1,84 @@
+package twprojects
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/config"
+)
+
+// sessionMaxCount is the maximum number of sessions tracked by a
+// sessionStore. The least recently used session is discarded when the limit is
+// reached.
+const sessionMaxCount = 1000
+
+// sessionKey identifies the session of the request. In HTTP mode the tools are
+// shared by all customers, and the stateless transport doesn't validate the
+// session IDs chosen by the clients, so the session is combined with the
+// installation and the authenticated user. The STDIO sessions have no ID, as
+// there is a single session, so they are identified by the installation alone.
+// The calls without a session (e.g. scheduled jobs), or without a user in a
+// stateless transport, can't keep any state.
+func sessionKey(ctx context.Context, request *mcp.CallToolRequest) (string, bool) {
+	if request.Session == nil {
+		return "", false
+	}
+	userID, ok := config.UserIDFromContext(ctx)
+	if !ok && config.IsStatelessTransport(ctx) {
+		return "", false
+	}
+	customerURL, _ := config.CustomerURLFromContext(ctx)
+	return customerURL + "|" + strconv.FormatInt(userID, 10) + "|" + request.Session.ID(), true
+}
+
+// sessionStore keeps a value for each session, discarding the least recently
+// used sessions when the limit is reached.
+type sessionStore[T any] struct {
+	sessions map[string]*sessionValue[T]
+	mutex    sync.Mutex
+}
+
+type sessionValue[T any] struct {
+	value     T
+	updatedAt time.Time
+}
+
+func newSessionStore[T any]() *sessionStore[T] {
+	return &sessionStore[T]{
+		sessions: make(map[string]*sessionValue[T]),
+	}
+}
+
+// update calls fn with the value of the session, creating it when needed. The
+// store is locked while fn runs, so the value can be safely modified.
+func (s *sessionStore[T]) update(key string, fn func(value *T)) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	session, ok := s.sessions[key]
+	if !ok {
+		if len(s.sessions) >= sessionMaxCount {
+			s.evict()
+		}
+		session = new(sessionValue[T])
+		s.sessions[key] = session
+	}
+	fn(&session.value)
+	session.updatedAt = time.Now()
+}
+
+// evict removes the least recently used session. It must be called with the
+// mutex locked.
+func (s *sessionStore[T]) evict() {
+	var oldestKey string
+	var oldest time.Time
+	for key, session := range s.sessions {
+		if oldestKey == "" || session.updatedAt.Before(oldest) {
+			oldestKey, oldest = key, session.updatedAt
+		}
+	}
+	delete(s.sessions, oldestKey)
+}
@@ -0,0 +1,242 @@
+package twprojects
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/config"
+	"github.com/teamwork/mcp/internal/helpers"
+	"github.com/teamwork/mcp/internal/toolsets"
+	twapi "github.com/teamwork/twapi-go-sdk"
+	"github.com/teamwork/twapi-go-sdk/projects"
+)
+
+// List of methods available in the Teamwork.com MCP service.
+//
+// The naming convention for methods follows a pattern described here:
+// https://github.com/github/github-mcp-server/issues/333
+const (
+	MethodSLABreachList toolsets.Method = "twprojects-list_sla_breaches"
+)
+
+// slaBreachesMaxTasks is the maximum number of tasks evaluated against the SLA
+// rules.
+const slaBreachesMaxTasks = 5000
+
+func init() {
+	// register the toolset methods
+	toolsets.RegisterMethod(MethodSLABreachList)
+}
+
+// slaBreach is an incomplete task open for longer than allowed by a SLA rule.
+type slaBreach struct {
+	TaskID         int64     `json:"taskId"`
+	TaskName       string    `json:"taskName"`
+	TasklistID     int64     `json:"tasklistId,omitempty"`
+	Priority       string    `json:"priority,omitempty"`
+	AssigneeIDs    []int64   `json:"assigneeIds,omitempty"`
+	Rule           string    `json:"rule"`
+	CreatedAt      time.Time `json:"createdAt"`
+	Deadline       time.Time `json:"deadline"`
+	OverdueMinutes int64     `json:"overdueMinutes"`
+}
+
+// slaBreaches is the result of the SLA evaluation.
+type slaBreaches struct {
+	ProjectID      int64       `json:"projectId,omitempty"`
+	EvaluatedAt    time.Time   `json:"evaluatedAt"`
+	Rules          []string    `json:"rules"`
+	EvaluatedTasks int         `json:"evaluatedTasks"`
+	Breaches       []slaBreach `json:"breaches"`
+	// Truncated is true when not all tasks were evaluated.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// slaRuleMatches checks if the task is covered by the SLA rule.
+func slaRuleMatches(rule config.SLARule, task projects.Task) bool {
+	if rule.Priority != "" && (task.Priority == nil || !strings.EqualFold(*task.Priority, rule.Priority)) {
+		return false
+	}
+	if len(rule.TagIDs) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(task.Tags, func(tag twapi.Relationship) bool {
+		return slices.Contains(rule.TagIDs, tag.ID)
+	})
+}
+
+// evaluateSLARules returns the breaches of the incomplete tasks at the given
+// time, sorted by the overdue time, longest first. A task breaching many rules
+// is reported once for each rule.
+func evaluateSLARules(rules []config.SLARule, tasks []projects.Task, now time.Time) []slaBreach {
+	breaches := []slaBreach{}
+	for _, task := range tasks {
+		if task.CreatedAt == nil || task.CompletedAt != nil || task.Status == "completed" {
+			continue
+		}
+		for _, rule := range rules {
+			if !slaRuleMatches(rule, task) {
+				continue
+			}
+			deadline := task.CreatedAt.Add(time.Duration(rule.CompleteWithin))
+			if !now.After(deadline) {
+				continue
+			}
+			breach := slaBreach{
+				TaskID:         task.ID,
+				TaskName:       task.Name,
+				TasklistID:     task.Tasklist.ID,
+				Rule:           rule.Name,
+				CreatedAt:      *task.CreatedAt,
+				Deadline:       deadline,
+				OverdueMinutes: int64(now.Sub(deadline) / time.Minute),
+			}
+			if task.Priority != nil {
+				breach.Priority = *task.Priority
+			}
+			for _, assignee := range task.Assignees {
+				breach.AssigneeIDs = append(breach.AssigneeIDs, assignee.ID)
+			}
+			breaches = append(breaches, breach)
+		}
+	}
+	slices.SortFunc(breaches, func(a, b slaBreach) int {
+		return cmp.Or(cmp.Compare(b.OverdueMinutes, a.OverdueMinutes), cmp.Compare(a.TaskID, b.TaskID))
+	})
+	return breaches
+}
+
+// SLABreachList evaluates the tasks in Teamwork.com against SLA rules, listing
+// the tasks open for longer than allowed. The rules provided at call time take
+// precedence over the configured ones.
+func SLABreachList(engine *twapi.Engine, configuredRules []config.SLARule) toolsets.ToolWrapper {
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name: string(MethodSLABreachList),
+			Description: "Evaluate the incomplete tasks in Teamwork.com against SLA rules, such as high priority tasks " +
+				"must be completed within 3 days of their creation, and list the breaches, longest overdue first. " +
+				"Rules match tasks by priority and tags. When no rules are provided, the rules configured in the " +
+				"server are used. Useful for scheduled escalations.",
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "List SLA Breaches",
+				ReadOnlyHint: true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"project_id": {
+						Type:        "integer",
+						Description: "The ID of the project to evaluate. When omitted, the tasks of all projects are evaluated.",
+					},
+					"rules": {
+						Type:        "array",
+						Description: "The SLA rules. When omitted, the rules configured in the server are used.",
+						Items: &jsonschema.Schema{
+							Type: "object",
+							Properties: map[string]*jsonschema.Schema{
+								"name": {
+									Type:        "string",
+									Description: "The name of the rule, reported with the breaches.",
+								},
+								"priority": {
+									Type:        "string",
+									Description: "The task priority matched by the rule. When omitted, any priority is matched.",
+									Enum:        enumSchemaValues(taskPriorities),
+								},
+								"tag_ids": {
+									Type:        "array",
+									Description: "The tags matched by the rule, where a task matches when it has any of them.",
+									Items: &jsonschema.Schema{
+										Type: "integer",
+									},
+								},
+								"complete_within": {
+									Type: "string",
+									Description: "The maximum time between the creation and the completion of a task, as a " +
+										"duration such as '72h' or '30m'.",
+								},
+							},
+							Required: []string{"name", "complete_within"},
+						},
+					},
+				},
+			},
+		},
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var projectID int64
+			var input struct {
+				Rules []config.SLARule `json:"rules"`
+			}
+
+			var arguments map[string]any
+			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("failed to decode request: %s", err.Error())), nil
+			}
+			if err := json.Unmarshal(request.Params.Arguments, &input); err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: invalid rules: %s", err.Error())), nil
+			}
+			err := helpers.ParamGroup(arguments,
+				helpers.OptionalNumericParam(&projectID, "project_id"),
+			)
+			if err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
+			}
+
+			rules := input.Rules
+			if len(rules) == 0 {
+				rules = configuredRules
+			}
+			if len(rules) == 0 {
+				return helpers.NewToolResultTextError("no SLA rules were provided or configured"), nil
+			}
+			ruleNames := make([]string, len(rules))
+			for i, rule := range rules {
+				if rule.Name == "" || rule.CompleteWithin <= 0 {
+					return helpers.NewToolResultTextError("invalid parameters: each rule requires a name and a positive " +
+						"complete_within"), nil
+				}
+				ruleNames[i] = rule.Name
+			}
+
+			tasks, truncated, err := helpers.FetchAllPages(ctx, helpers.AutoPagination{
+				PageSize: 100,
+				MaxItems: slaBreachesMaxTasks,
+			}, func(ctx context.Context, page, pageSize int64) ([]projects.Task, bool, error) {
+				taskListRequest := projects.NewTaskListRequest()
+				taskListRequest.Path.ProjectID = projectID
+				taskListRequest.Filters.Page = page
+				taskListRequest.Filters.PageSize = pageSize
+
+				response, err := projects.TaskList(ctx, engine, taskListRequest)
+				if err != nil {
+					return nil, false, err
+				}
+				return response.Tasks, response.Meta.Page.HasMore, nil
+			})
+			if err != nil {
+				return helpers.HandleAPIError(err, "failed to list tasks")
+			}
+
+			now := time.Now().UTC().Truncate(time.Second)
+			encoded, err := json.Marshal(slaBreaches{
+				ProjectID:      projectID,
+				EvaluatedAt:    now,
+				Rules:          ruleNames,
+				EvaluatedTasks: len(tasks),
+				Breaches:       evaluateSLARules(rules, tasks, now),
+				Truncated:      truncated,
+			})
+			if err != nil {
+				return nil, err
+			}
+			return helpers.NewToolResultText("%s", encoded), nil
+		},
+	}
+}
@@ -0,0 +1,111 @@
+package twprojects_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/config"
+	"github.com/teamwork/mcp/internal/testutil"
+	"github.com/teamwork/mcp/internal/twprojects"
+)
+
+const slaTasksResponse = `{"tasks":[` +
+	`{"id":1,"name":"Old high","priority":"high","createdAt":"2020-01-01T00:00:00Z","tasklist":{"id":5},` +
+	`"assignees":[{"id":7,"type":"users"}]},` +
+	`{"id":2,"name":"Old low","priority":"low","createdAt":"2020-01-02T00:00:00Z","tasklist":{"id":5}},` +
+	`{"id":3,"name":"New high","priority":"high","createdAt":"2099-01-01T00:00:00Z","tasklist":{"id":5}},` +
+	`{"id":4,"name":"Done high","priority":"high","createdAt":"2020-01-01T00:00:00Z","status":"completed"},` +
+	`{"id":5,"name":"Old tagged","createdAt":"2020-01-03T00:00:00Z","tags":[{"id":9,"type":"tags"}]}],` +
+	`"meta":{"page":{"hasMore":false}}}`
+
+func TestSLABreachList(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      []twprojects.ToolsetGroupOption
+		arguments map[string]any
+		expected  [][2]any
+		isError   bool
+	}{{
+		name: "rules provided at call time",
+		arguments: map[string]any{
+			"project_id": float64(123),
+			"rules": []any{
+				map[string]any{"name": "high", "priority": "high", "complete_within": "72h"},
+				map[string]any{"name": "escalated", "tag_ids": []any{float64(9)}, "complete_within": "24h"},
+			},
+		},
+		expected: [][2]any{{float64(1), "high"}, {float64(5), "escalated"}},
+	}, {
+		name: "configured rules",
+		opts: []twprojects.ToolsetGroupOption{
+			twprojects.WithSLARules([]config.SLARule{{
+				Name:           "any",
+				CompleteWithin: config.Duration(30 * 24 * time.Hour),
+			}}),
+		},
+		arguments: map[string]any{
+			"project_id": float64(123),
+		},
+		expected: [][2]any{{float64(1), "any"}, {float64(2), "any"}, {float64(5), "any"}},
+	}, {
+		name: "without rules",
+		arguments: map[string]any{
+			"project_id": float64(123),
+		},
+		isError: true,
+	}, {
+		name: "invalid rule",
+		arguments: map[string]any{
+			"rules": []any{map[string]any{"name": "high", "complete_within": "soon"}},
+		},
+		isError: true,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(r *http.Request) (int, []byte) {
+				if r.URL.Path != "/projects/api/v3/projects/123/tasks.json" {
+					t.Errorf("unexpected request %s", r.URL.Path)
+				}
+				return http.StatusOK, []byte(slaTasksResponse)
+			}, tt.opts...)
+
+			testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodSLABreachList.String(), tt.arguments,
+				testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+					toolResult, ok := result.(*mcp.CallToolResult)
+					if !ok {
+						t.Fatalf("unexpected result type: %T", result)
+					}
+					if toolResult.IsError != tt.isError {
+						t.Fatalf("expected error %t, got %v", tt.isError, toolResult.Content)
+					}
+					if tt.isError {
+						return
+					}
+
+					var breaches struct {
+						Breaches []struct {
+							TaskID float64 `json:"taskId"`
+							Rule   string  `json:"rule"`
+						} `json:"breaches"`
+					}
+					text := toolResult.Content[0].(*mcp.TextContent).Text
+					if err := json.Unmarshal([]byte(text), &breaches); err != nil {
+						t.Fatalf("failed to decode result: %v", err)
+					}
+					var got [][2]any
+					for _, breach := range breaches.Breaches {
+						got = append(got, [2]any{breach.TaskID, breach.Rule})
+					}
+					if !slices.Equal(got, tt.expected) {
+						t.Errorf("expected breaches %v, got %v", tt.expected, got)
+					}
+				}),
+			)
+		})
+	}
+}
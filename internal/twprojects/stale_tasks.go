@@ -0,0 +1,291 @@
+package twprojects
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/helpers"
+	"github.com/teamwork/mcp/internal/toolsets"
+	twapi "github.com/teamwork/twapi-go-sdk"
+	"github.com/teamwork/twapi-go-sdk/projects"
+)
+
+// List of methods available in the Teamwork.com MCP service.
+//
+// The naming convention for methods follows a pattern described here:
+// https://github.com/github/github-mcp-server/issues/333
+const (
+	MethodStaleTaskSweep toolsets.Method = "twprojects-sweep_stale_tasks"
+)
+
+const (
+	// staleTasksMaxItems is the maximum number of tasks, timelogs and comments
+	// loaded to find the stale tasks.
+	staleTasksMaxItems = 5000
+	// staleTasksDefaultNudge is the comment posted on the stale tasks when no
+	// comment body is provided.
+	staleTasksDefaultNudge = "This task has had no activity for %d days. Is it still relevant?"
+)
+
+// Actions applied to the stale tasks.
+const (
+	staleTaskActionNone    = "none"
+	staleTaskActionComment = "comment"
+	staleTaskActionMove    = "move"
+)
+
+var staleTaskActions = []string{staleTaskActionNone, staleTaskActionComment, staleTaskActionMove}
+
+func init() {
+	// register the toolset methods
+	toolsets.RegisterMethod(MethodStaleTaskSweep)
+}
+
+// staleTask is a task without activity since the cutoff.
+type staleTask struct {
+	ID         int64     `json:"id"`
+	Name       string    `json:"name"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+	TasklistID int64     `json:"tasklistId,omitempty"`
+}
+
+// staleTaskFailure is a stale task where the action failed.
+type staleTaskFailure struct {
+	ID    int64  `json:"id"`
+	Error string `json:"error"`
+}
+
+// staleTaskSweep is the result of the stale task sweep.
+type staleTaskSweep struct {
+	ProjectID  int64              `json:"projectId"`
+	Days       int64              `json:"days"`
+	Cutoff     time.Time          `json:"cutoff"`
+	StaleTasks []staleTask        `json:"staleTasks"`
+	Action     string             `json:"action"`
+	Actioned   []int64            `json:"actioned"`
+	Failures   []staleTaskFailure `json:"failures"`
+	// Truncated is true when not all tasks, timelogs or comments were loaded.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// StaleTaskSweep lists the tasks of a project in Teamwork.com without activity
+// for a number of days, optionally nudging them with a comment or moving them
+// to another tasklist.
+func StaleTaskSweep(engine *twapi.Engine) toolsets.ToolWrapper {
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name: string(MethodStaleTaskSweep),
+			Description: "List the incomplete tasks of a project in Teamwork.com without any activity (updates, " +
+				"comments or logged time) in the last number of days, oldest first. In the same call, the stale tasks " +
+				"can be nudged with a comment or moved to another tasklist (e.g. a \"Stale\" tasklist), which is " +
+				"useful for project hygiene automations. The tasks where the action failed are reported without " +
+				"stopping the sweep.",
+			Annotations: &mcp.ToolAnnotations{
+				Title: "Sweep Stale Tasks",
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"project_id": {
+						Type:        "integer",
+						Description: "The ID of the project.",
+					},
+					"days": {
+						Type:        "integer",
+						Description: "The number of days without activity for a task to be stale.",
+						Minimum:     twapi.Ptr(float64(1)),
+					},
+					"action": {
+						Type: "string",
+						Description: "The action applied to the stale tasks: 'none' only lists them, 'comment' posts a " +
+							"nudge comment and 'move' moves them to the stale tasklist. Defaults to 'none'.",
+						Enum: []any{staleTaskActionNone, staleTaskActionComment, staleTaskActionMove},
+					},
+					"comment_body": {
+						Type: "string",
+						Description: "The body of the nudge comment, for the 'comment' action. Defaults to a message " +
+							"asking if the task is still relevant.",
+					},
+					"stale_tasklist_id": {
+						Type:        "integer",
+						Description: "The ID of the tasklist the stale tasks are moved to, required for the 'move' action.",
+					},
+				},
+				Required: []string{"project_id", "days"},
+			},
+		},
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var projectID, days, staleTasklistID int64
+			action := staleTaskActionNone
+			var commentBody string
+
+			var arguments map[string]any
+			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("failed to decode request: %s", err.Error())), nil
+			}
+			err := helpers.ParamGroup(arguments,
+				helpers.RequiredNumericParam(&projectID, "project_id"),
+				helpers.RequiredNumericParam(&days, "days"),
+				helpers.OptionalParam(&action, "action", helpers.RestrictValues(staleTaskActions...)),
+				helpers.OptionalParam(&commentBody, "comment_body"),
+				helpers.OptionalNumericParam(&staleTasklistID, "stale_tasklist_id"),
+			)
+			if err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
+			}
+			if days <= 0 {
+				return helpers.NewToolResultTextError("invalid parameters: days must be positive"), nil
+			}
+			if action == staleTaskActionMove && staleTasklistID == 0 {
+				return helpers.NewToolResultTextError("invalid parameters: stale_tasklist_id is required for the " +
+					"move action"), nil
+			}
+			if commentBody == "" {
+				commentBody = fmt.Sprintf(staleTasksDefaultNudge, days)
+			}
+
+			cutoff := time.Now().UTC().AddDate(0, 0, -int(days)).Truncate(time.Second)
+			pagination := helpers.AutoPagination{
+				PageSize: 100,
+				MaxItems: staleTasksMaxItems,
+			}
+
+			candidates, tasksTruncated, err := helpers.FetchAllPages(ctx, pagination,
+				func(ctx context.Context, page, pageSize int64) ([]staleTask, bool, error) {
+					taskListRequest := projects.NewTaskListRequest()
+					taskListRequest.Path.ProjectID = projectID
+					taskListRequest.Filters.Page = page
+					taskListRequest.Filters.PageSize = pageSize
+
+					response, err := projects.TaskList(ctx, engine, taskListRequest)
+					if err != nil {
+						return nil, false, err
+					}
+					var tasks []staleTask
+					for _, task := range response.Tasks {
+						if task.CompletedAt != nil || task.Status == "completed" || !task.UpdatedAt.Before(cutoff) {
+							continue
+						}
+						tasks = append(tasks, staleTask{
+							ID:         task.ID,
+							Name:       task.Name,
+							UpdatedAt:  task.UpdatedAt,
+							TasklistID: task.Tasklist.ID,
+						})
+					}
+					return tasks, response.Meta.Page.HasMore, nil
+				})
+			if err != nil {
+				return helpers.HandleAPIError(err, "failed to list project tasks")
+			}
+
+			active := make(map[int64]struct{})
+			timelogTaskIDs, timelogsTruncated, err := helpers.FetchAllPages(ctx, pagination,
+				func(ctx context.Context, page, pageSize int64) ([]int64, bool, error) {
+					timelogListRequest := projects.NewTimelogListRequest()
+					timelogListRequest.Path.ProjectID = projectID
+					timelogListRequest.Filters.StartDate = &cutoff
+					timelogListRequest.Filters.Page = page
+					timelogListRequest.Filters.PageSize = pageSize
+
+					response, err := projects.TimelogList(ctx, engine, timelogListRequest)
+					if err != nil {
+						return nil, false, err
+					}
+					var taskIDs []int64
+					for _, timelog := range response.Timelogs {
+						if timelog.Task != nil {
+							taskIDs = append(taskIDs, timelog.Task.ID)
+						}
+					}
+					return taskIDs, response.Meta.Page.HasMore, nil
+				})
+			if err != nil {
+				return helpers.HandleAPIError(err, "failed to list project timelogs")
+			}
+
+			commentQuery := commentListQuery(&cutoff, "")
+			commentQuery.Set("projectIds", strconv.FormatInt(projectID, 10))
+			commentTaskIDs, commentsTruncated, err := helpers.FetchAllPages(ctx, pagination,
+				func(ctx context.Context, page, pageSize int64) ([]int64, bool, error) {
+					commentListRequest := projects.NewCommentListRequest()
+					commentListRequest.Filters.Page = page
+					commentListRequest.Filters.PageSize = pageSize
+
+					response, err := executeWithQuery[*projects.CommentListResponse](ctx, engine, commentListRequest,
+						commentQuery,
+					)
+					if err != nil {
+						return nil, false, err
+					}
+					var taskIDs []int64
+					for _, comment := range response.Comments {
+						if comment.Object != nil && comment.Object.Type == "tasks" && comment.Project.ID == projectID {
+							taskIDs = append(taskIDs, comment.Object.ID)
+						}
+					}
+					return taskIDs, response.Meta.Page.HasMore, nil
+				})
+			if err != nil {
+				return helpers.HandleAPIError(err, "failed to list project comments")
+			}
+			for _, taskID := range slices.Concat(timelogTaskIDs, commentTaskIDs) {
+				active[taskID] = struct{}{}
+			}
+
+			sweep := staleTaskSweep{
+				ProjectID:  projectID,
+				Days:       days,
+				Cutoff:     cutoff,
+				StaleTasks: []staleTask{},
+				Action:     action,
+				Actioned:   []int64{},
+				Failures:   []staleTaskFailure{},
+				Truncated:  tasksTruncated || timelogsTruncated || commentsTruncated,
+			}
+			for _, task := range candidates {
+				if _, ok := active[task.ID]; !ok {
+					sweep.StaleTasks = append(sweep.StaleTasks, task)
+				}
+			}
+			slices.SortFunc(sweep.StaleTasks, func(a, b staleTask) int {
+				return cmp.Or(a.UpdatedAt.Compare(b.UpdatedAt), cmp.Compare(a.ID, b.ID))
+			})
+
+			for _, task := range sweep.StaleTasks {
+				var err error
+				switch action {
+				case staleTaskActionComment:
+					_, err = projects.CommentCreate(ctx, engine, projects.NewCommentCreateRequestInTask(task.ID, commentBody))
+				case staleTaskActionMove:
+					if task.TasklistID == staleTasklistID {
+						continue
+					}
+					taskUpdateRequest := projects.NewTaskUpdateRequest(task.ID)
+					taskUpdateRequest.TasklistID = &staleTasklistID
+					_, err = projects.TaskUpdate(ctx, engine, taskUpdateRequest)
+				default:
+					continue
+				}
+				if err != nil {
+					sweep.Failures = append(sweep.Failures, staleTaskFailure{ID: task.ID, Error: err.Error()})
+					continue
+				}
+				sweep.Actioned = append(sweep.Actioned, task.ID)
+			}
+
+			encoded, err := json.Marshal(sweep)
+			if err != nil {
+				return nil, err
+			}
+			return helpers.NewToolResultText("%s", encoded), nil
+		},
+	}
+}
@@ -0,0 +1,102 @@
+package twprojects_test
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/testutil"
+	"github.com/teamwork/mcp/internal/twprojects"
+)
+
+func TestStaleTaskSweep(t *testing.T) {
+	var updates []string
+	mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(r *http.Request) (int, []byte) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/projects/api/v3/projects/123/tasks.json":
+			return http.StatusOK, []byte(`{"tasks":[` +
+				`{"id":1,"name":"Stale","updatedAt":"2020-01-02T00:00:00Z","tasklist":{"id":5}},` +
+				`{"id":2,"name":"Logged","updatedAt":"2020-01-01T00:00:00Z","tasklist":{"id":5}},` +
+				`{"id":3,"name":"Commented","updatedAt":"2020-01-01T00:00:00Z","tasklist":{"id":5}},` +
+				`{"id":4,"name":"Updated","updatedAt":"2099-01-01T00:00:00Z","tasklist":{"id":5}},` +
+				`{"id":5,"name":"Done","updatedAt":"2020-01-01T00:00:00Z","status":"completed","tasklist":{"id":5}},` +
+				`{"id":6,"name":"Already moved","updatedAt":"2020-01-01T00:00:00Z","tasklist":{"id":99}}],` +
+				`"meta":{"page":{"hasMore":false}}}`)
+		case r.Method == http.MethodGet && r.URL.Path == "/projects/api/v3/projects/123/time.json":
+			if r.URL.Query().Get("startDate") == "" {
+				t.Errorf("expected the timelogs to be filtered by start date")
+			}
+			return http.StatusOK, []byte(`{"timelogs":[{"id":1,"minutes":30,"task":{"id":2}}],` +
+				`"meta":{"page":{"hasMore":false}}}`)
+		case r.Method == http.MethodGet && r.URL.Path == "/projects/api/v3/comments.json":
+			if r.URL.Query().Get("updatedAfter") == "" || r.URL.Query().Get("projectIds") != "123" {
+				t.Errorf("unexpected comment query %q", r.URL.RawQuery)
+			}
+			return http.StatusOK, []byte(`{"comments":[` +
+				`{"id":1,"object":{"id":3,"type":"tasks"},"project":{"id":123}},` +
+				`{"id":2,"object":{"id":1,"type":"milestones"},"project":{"id":123}}],` +
+				`"meta":{"page":{"hasMore":false}}}`)
+		case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, "/projects/api/v3/tasks/"):
+			body, _ := io.ReadAll(r.Body)
+			updates = append(updates, r.URL.Path+" "+string(body))
+			return http.StatusOK, []byte(`{"task":{}}`)
+		}
+		t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		return http.StatusNotFound, nil
+	})
+
+	executePreviewedToolRequest(t, mcpServer, twprojects.MethodStaleTaskSweep.String(), map[string]any{
+		"project_id":        float64(123),
+		"days":              float64(30),
+		"action":            "move",
+		"stale_tasklist_id": float64(99),
+	}, testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+		toolResult, ok := result.(*mcp.CallToolResult)
+		if !ok {
+			t.Fatalf("unexpected result type: %T", result)
+		}
+		if toolResult.IsError {
+			t.Fatalf("tool failed to execute: %v", toolResult.Content)
+		}
+		text := toolResult.Content[0].(*mcp.TextContent).Text
+		expected := `"staleTasks":[` +
+			`{"id":6,"name":"Already moved","updatedAt":"2020-01-01T00:00:00Z","tasklistId":99},` +
+			`{"id":1,"name":"Stale","updatedAt":"2020-01-02T00:00:00Z","tasklistId":5}],` +
+			`"action":"move","actioned":[1],"failures":[]}`
+		if !strings.HasSuffix(text, expected) {
+			t.Errorf("expected suffix %s, got %s", expected, text)
+		}
+	}))
+
+	if len(updates) != 1 || !strings.HasPrefix(updates[0], "/projects/api/v3/tasks/1.json ") ||
+		!strings.Contains(updates[0], `"tasklistId":99`) {
+		t.Errorf("unexpected task updates %v", updates)
+	}
+}
+
+func TestStaleTaskSweepMoveWithoutTasklist(t *testing.T) {
+	mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(r *http.Request) (int, []byte) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		return http.StatusNotFound, nil
+	})
+
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodStaleTaskSweep.String(), map[string]any{
+		"project_id": float64(123),
+		"days":       float64(30),
+		"action":     "move",
+	}, testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+		toolResult, ok := result.(*mcp.CallToolResult)
+		if !ok {
+			t.Fatalf("unexpected result type: %T", result)
+		}
+		if !toolResult.IsError {
+			t.Fatalf("expected an error result")
+		}
+		text := toolResult.Content[0].(*mcp.TextContent).Text
+		if !strings.Contains(text, "stale_tasklist_id") {
+			t.Errorf("unexpected error %s", text)
+		}
+	}))
+}
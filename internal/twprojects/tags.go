@@ -268,18 +268,7 @@ func TagList(engine *twapi.Engine) toolsets.ToolWrapper {
 						Type: "string",
 						Description: "The type of item to filter tags by. Valid values are 'project', 'task', 'tasklist', " +
 							"'milestone', 'message', 'timelog', 'notebook', 'file', 'company' and 'link'.",
-						Enum: []any{
-							"project",
-							"task",
-							"tasklist",
-							"milestone",
-							"message",
-							"timelog",
-							"notebook",
-							"file",
-							"company",
-							"link",
-						},
+						Enum: enumSchemaValues(tagItemTypes),
 					},
 					"project_ids": {
 						Type:        "array",
@@ -296,12 +285,14 @@ func TagList(engine *twapi.Engine) toolsets.ToolWrapper {
 						Type:        "integer",
 						Description: "Number of results per page for pagination.",
 					},
+					"count_only": countOnlySchema(),
 				},
 			},
 			OutputSchema: tagListOutputSchema,
 		},
 		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			var tagListRequest projects.TagListRequest
+			var countOnly bool
 
 			var arguments map[string]any
 			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
@@ -310,17 +301,21 @@ func TagList(engine *twapi.Engine) toolsets.ToolWrapper {
 			err := helpers.ParamGroup(arguments,
 				helpers.OptionalParam(&tagListRequest.Filters.SearchTerm, "search_term"),
 				helpers.OptionalParam(&tagListRequest.Filters.ItemType, "item_type",
-					helpers.RestrictValues("project", "task", "tasklist", "milestone", "message", "timelog", "notebook",
-						"file", "company", "link"),
+					helpers.RestrictValues(tagItemTypes...),
 				),
 				helpers.OptionalNumericListParam(&tagListRequest.Filters.ProjectIDs, "project_ids"),
 				helpers.OptionalNumericParam(&tagListRequest.Filters.Page, "page"),
 				helpers.OptionalNumericParam(&tagListRequest.Filters.PageSize, "page_size"),
+				helpers.OptionalParam(&countOnly, "count_only"),
 			)
 			if err != nil {
 				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
 			}
 
+			if countOnly {
+				return listCount(ctx, engine, tagListRequest)
+			}
+
 			tagList, err := projects.TagList(ctx, engine, tagListRequest)
 			if err != nil {
 				return helpers.HandleAPIError(err, "failed to list tags")
@@ -0,0 +1,320 @@
+package twprojects
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/helpers"
+	"github.com/teamwork/mcp/internal/toolsets"
+	"github.com/teamwork/twapi-go-sdk"
+	"github.com/teamwork/twapi-go-sdk/projects"
+)
+
+// List of methods available in the Teamwork.com MCP service.
+//
+// The naming convention for methods follows a pattern described here:
+// https://github.com/github/github-mcp-server/issues/333
+const (
+	MethodTaskChecklistList       toolsets.Method = "twprojects-list_task_checklist"
+	MethodTaskChecklistItemAdd    toolsets.Method = "twprojects-add_task_checklist_item"
+	MethodTaskChecklistItemToggle toolsets.Method = "twprojects-toggle_task_checklist_item"
+)
+
+const taskChecklistDescription = "The checklist of a task is a list of steps kept in the task description as " +
+	"Markdown checkboxes (e.g. \"- [ ] Write tests\" and \"- [x] Update docs\"). It tracks granular progress without " +
+	"creating subtasks."
+
+// reChecklistItem matches a checklist item line of the task description,
+// capturing the prefix, the state and the text.
+var reChecklistItem = regexp.MustCompile(`^(\s*[-*+]\s+\[)([ xX])\]\s+(.*\S)\s*$`)
+
+func init() {
+	// register the toolset methods
+	toolsets.RegisterMethod(MethodTaskChecklistList)
+	toolsets.RegisterMethod(MethodTaskChecklistItemAdd)
+	toolsets.RegisterMethod(MethodTaskChecklistItemToggle)
+}
+
+// taskChecklistItem is a checklist item of a task.
+type taskChecklistItem struct {
+	Index int    `json:"index"`
+	Text  string `json:"text"`
+	Done  bool   `json:"done"`
+	// line is the index of the line in the description.
+	line int
+}
+
+// taskChecklist is the checklist of a task.
+type taskChecklist struct {
+	TaskID    int64               `json:"taskId"`
+	Items     []taskChecklistItem `json:"items"`
+	Completed int                 `json:"completed"`
+	Total     int                 `json:"total"`
+}
+
+// parseTaskChecklist extracts the checklist items of the task description.
+func parseTaskChecklist(taskID int64, description string) taskChecklist {
+	checklist := taskChecklist{
+		TaskID: taskID,
+		Items:  []taskChecklistItem{},
+	}
+	for i, line := range strings.Split(description, "\n") {
+		matches := reChecklistItem.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+		item := taskChecklistItem{
+			Index: len(checklist.Items) + 1,
+			Text:  matches[3],
+			Done:  matches[2] != " ",
+			line:  i,
+		}
+		if item.Done {
+			checklist.Completed++
+		}
+		checklist.Items = append(checklist.Items, item)
+	}
+	checklist.Total = len(checklist.Items)
+	return checklist
+}
+
+// taskDescriptionText returns the description of the task, or an empty string
+// when it has none.
+func taskDescriptionText(task projects.Task) string {
+	if task.Description == nil {
+		return ""
+	}
+	return *task.Description
+}
+
+// setTaskChecklistItem returns the description with the state of the checklist
+// item changed.
+func setTaskChecklistItem(description string, item taskChecklistItem, done bool) string {
+	state := " "
+	if done {
+		state = "x"
+	}
+	lines := strings.Split(description, "\n")
+	lines[item.line] = reChecklistItem.ReplaceAllString(lines[item.line], "${1}"+state+"] ${3}")
+	return strings.Join(lines, "\n")
+}
+
+// TaskChecklistList lists the checklist items of a task in Teamwork.com.
+func TaskChecklistList(engine *twapi.Engine) toolsets.ToolWrapper {
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name: string(MethodTaskChecklistList),
+			Description: "List the checklist items of a task in Teamwork.com, with their completion. " +
+				taskChecklistDescription,
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "List Task Checklist",
+				ReadOnlyHint: true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"task_id": {
+						Type:        "integer",
+						Description: "The ID of the task.",
+					},
+				},
+				Required: []string{"task_id"},
+			},
+		},
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var taskID int64
+
+			var arguments map[string]any
+			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("failed to decode request: %s", err.Error())), nil
+			}
+			err := helpers.ParamGroup(arguments,
+				helpers.RequiredNumericParam(&taskID, "task_id"),
+			)
+			if err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
+			}
+
+			taskResponse, err := projects.TaskGet(ctx, engine, projects.NewTaskGetRequest(taskID))
+			if err != nil {
+				return helpers.HandleAPIError(err, "failed to get task")
+			}
+
+			encoded, err := json.Marshal(parseTaskChecklist(taskID, taskDescriptionText(taskResponse.Task)))
+			if err != nil {
+				return nil, err
+			}
+			return helpers.NewToolResultText("%s", encoded), nil
+		},
+	}
+}
+
+// TaskChecklistItemAdd adds a checklist item to a task in Teamwork.com.
+func TaskChecklistItemAdd(engine *twapi.Engine) toolsets.ToolWrapper {
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name: string(MethodTaskChecklistItemAdd),
+			Description: "Add a checklist item at the end of the checklist of a task in Teamwork.com. " +
+				taskChecklistDescription,
+			Annotations: &mcp.ToolAnnotations{
+				Title: "Add Task Checklist Item",
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"task_id": {
+						Type:        "integer",
+						Description: "The ID of the task.",
+					},
+					"text": {
+						Type:        "string",
+						Description: "The text of the checklist item. It must be a single line.",
+					},
+					"done": {
+						Type:        "boolean",
+						Description: "Whether the checklist item is already done. Defaults to false.",
+					},
+				},
+				Required: []string{"task_id", "text"},
+			},
+		},
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var taskID int64
+			var text string
+			var done bool
+
+			var arguments map[string]any
+			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("failed to decode request: %s", err.Error())), nil
+			}
+			err := helpers.ParamGroup(arguments,
+				helpers.RequiredNumericParam(&taskID, "task_id"),
+				helpers.RequiredParam(&text, "text"),
+				helpers.OptionalParam(&done, "done"),
+			)
+			if err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
+			}
+			text = strings.TrimSpace(text)
+			if text == "" || strings.Contains(text, "\n") {
+				return helpers.NewToolResultTextError("invalid parameters: text must be a non-empty single line"), nil
+			}
+
+			taskResponse, err := projects.TaskGet(ctx, engine, projects.NewTaskGetRequest(taskID))
+			if err != nil {
+				return helpers.HandleAPIError(err, "failed to get task")
+			}
+
+			state := " "
+			if done {
+				state = "x"
+			}
+			description := strings.TrimRight(taskDescriptionText(taskResponse.Task), "\n")
+			if description != "" {
+				description += "\n"
+			}
+			description += fmt.Sprintf("- [%s] %s", state, text)
+
+			taskUpdateRequest := projects.NewTaskUpdateRequest(taskID)
+			taskUpdateRequest.Description = &description
+			if _, err := projects.TaskUpdate(ctx, engine, taskUpdateRequest); err != nil {
+				return helpers.HandleAPIError(err, "failed to update task")
+			}
+
+			checklist := parseTaskChecklist(taskID, description)
+			return helpers.NewToolResultText("Checklist item %d added successfully (%d of %d done)",
+				checklist.Total, checklist.Completed, checklist.Total), nil
+		},
+	}
+}
+
+// TaskChecklistItemToggle changes the completion of a checklist item of a task
+// in Teamwork.com.
+func TaskChecklistItemToggle(engine *twapi.Engine) toolsets.ToolWrapper {
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name: string(MethodTaskChecklistItemToggle),
+			Description: "Toggle the completion of a checklist item of a task in Teamwork.com, or set it with the done " +
+				"parameter. " + taskChecklistDescription,
+			Annotations: &mcp.ToolAnnotations{
+				Title: "Toggle Task Checklist Item",
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"task_id": {
+						Type:        "integer",
+						Description: "The ID of the task.",
+					},
+					"index": {
+						Type:        "integer",
+						Description: "The position of the checklist item, starting at 1, as returned when listing the checklist.",
+						Minimum:     twapi.Ptr(float64(1)),
+					},
+					"done": {
+						Type:        "boolean",
+						Description: "Whether the checklist item is done. When omitted, the current state is toggled.",
+					},
+				},
+				Required: []string{"task_id", "index"},
+			},
+		},
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var taskID int64
+			var index int64
+			var done *bool
+
+			var arguments map[string]any
+			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("failed to decode request: %s", err.Error())), nil
+			}
+			err := helpers.ParamGroup(arguments,
+				helpers.RequiredNumericParam(&taskID, "task_id"),
+				helpers.RequiredNumericParam(&index, "index"),
+				helpers.OptionalPointerParam(&done, "done"),
+			)
+			if err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
+			}
+
+			taskResponse, err := projects.TaskGet(ctx, engine, projects.NewTaskGetRequest(taskID))
+			if err != nil {
+				return helpers.HandleAPIError(err, "failed to get task")
+			}
+
+			description := taskDescriptionText(taskResponse.Task)
+			checklist := parseTaskChecklist(taskID, description)
+			if index < 1 || index > int64(checklist.Total) {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: the task checklist has %d item(s)",
+					checklist.Total)), nil
+			}
+			item := checklist.Items[index-1]
+			newDone := !item.Done
+			if done != nil {
+				newDone = *done
+			}
+
+			if newDone != item.Done {
+				description = setTaskChecklistItem(description, item, newDone)
+				taskUpdateRequest := projects.NewTaskUpdateRequest(taskID)
+				taskUpdateRequest.Description = &description
+				if _, err := projects.TaskUpdate(ctx, engine, taskUpdateRequest); err != nil {
+					return helpers.HandleAPIError(err, "failed to update task")
+				}
+				checklist = parseTaskChecklist(taskID, description)
+			}
+
+			state := "not done"
+			if newDone {
+				state = "done"
+			}
+			return helpers.NewToolResultText("Checklist item %d %q marked as %s (%d of %d done)",
+				index, item.Text, state, checklist.Completed, checklist.Total), nil
+		},
+	}
+}
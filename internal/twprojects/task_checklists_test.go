@@ -0,0 +1,88 @@
+package twprojects_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/testutil"
+	"github.com/teamwork/mcp/internal/twprojects"
+)
+
+const taskChecklistDescription = "Release notes\n- [ ] Write tests\n- [x] Update docs"
+
+func TestTaskChecklistList(t *testing.T) {
+	mcpServer := mcpServerMock(t, http.StatusOK, []byte(`{"task":{"id":123,"description":`+
+		`"Release notes\n- [ ] Write tests\n- [x] Update docs"}}`))
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTaskChecklistList.String(), map[string]any{
+		"task_id": float64(123),
+	}, testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+		toolResult, ok := result.(*mcp.CallToolResult)
+		if !ok {
+			t.Fatalf("unexpected result type: %T", result)
+		}
+		if toolResult.IsError {
+			t.Fatalf("tool failed to execute: %v", toolResult.Content)
+		}
+		text := toolResult.Content[0].(*mcp.TextContent).Text
+		expected := `{"taskId":123,"items":[{"index":1,"text":"Write tests","done":false},` +
+			`{"index":2,"text":"Update docs","done":true}],"completed":1,"total":2}`
+		if text != expected {
+			t.Errorf("expected %s, got %s", expected, text)
+		}
+	}))
+}
+
+func TestTaskChecklistItemAdd(t *testing.T) {
+	var description string
+	mcpServer := testutil.ProjectsMCPServerMockFunc(t, taskChecklistAPIMock(t, &description))
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTaskChecklistItemAdd.String(), map[string]any{
+		"task_id": float64(123),
+		"text":    "Deploy",
+	})
+
+	if expected := taskChecklistDescription + "\n- [ ] Deploy"; description != expected {
+		t.Errorf("expected description %q, got %q", expected, description)
+	}
+}
+
+func TestTaskChecklistItemToggle(t *testing.T) {
+	var description string
+	mcpServer := testutil.ProjectsMCPServerMockFunc(t, taskChecklistAPIMock(t, &description))
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTaskChecklistItemToggle.String(), map[string]any{
+		"task_id": float64(123),
+		"index":   float64(1),
+	})
+
+	if expected := strings.Replace(taskChecklistDescription, "[ ]", "[x]", 1); description != expected {
+		t.Errorf("expected description %q, got %q", expected, description)
+	}
+}
+
+// taskChecklistAPIMock answers the task requests, storing the description sent
+// when updating the task.
+func taskChecklistAPIMock(t *testing.T, description *string) func(*http.Request) (int, []byte) {
+	return func(r *http.Request) (int, []byte) {
+		if r.Method == http.MethodGet {
+			encoded, _ := json.Marshal(taskChecklistDescription)
+			return http.StatusOK, []byte(`{"task":{"id":123,"description":` + string(encoded) + `}}`)
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("failed to read request body: %v", err)
+		}
+		var update struct {
+			Task struct {
+				Description string `json:"description"`
+			} `json:"task"`
+		}
+		if err := json.Unmarshal(body, &update); err != nil {
+			t.Fatalf("failed to decode request body: %v", err)
+		}
+		*description = update.Task.Description
+		return http.StatusOK, []byte(`{}`)
+	}
+}
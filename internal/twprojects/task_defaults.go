@@ -0,0 +1,156 @@
+package twprojects
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/config"
+	"github.com/teamwork/mcp/internal/helpers"
+	"github.com/teamwork/mcp/internal/toolsets"
+	"github.com/teamwork/twapi-go-sdk"
+	"github.com/teamwork/twapi-go-sdk/projects"
+)
+
+// taskDefaults fills the tasklist and the assignees omitted when creating a
+// task with the operator-defined defaults. The project of the task is the
+// project_id argument or, when omitted, the default project of the session.
+type taskDefaults struct {
+	engine   *twapi.Engine
+	config   config.TaskDefaults
+	projects *defaultProject
+}
+
+func newTaskDefaults(engine *twapi.Engine, defaults config.TaskDefaults, projects *defaultProject) *taskDefaults {
+	return &taskDefaults{
+		engine:   engine,
+		config:   defaults,
+		projects: projects,
+	}
+}
+
+// apply wraps the task creation tool, so the tasklist_id can be omitted when
+// the project has a default tasklist. The tools are returned unchanged when no
+// defaults are defined.
+func (t *taskDefaults) apply(tools []toolsets.ToolWrapper) []toolsets.ToolWrapper {
+	if !t.config.Enabled() {
+		return tools
+	}
+
+	return toolsets.WrapToolList(tools, func(tool toolsets.ToolWrapper) toolsets.ToolWrapper {
+		if tool.Tool.Name != string(MethodTaskCreate) {
+			return tool
+		}
+		toolCopy, ok := withInputSchema(tool.Tool, func(schema *jsonschema.Schema) {
+			describeProperty(schema, "tasklist_id", " Defaults to the tasklist configured for the project, when omitted.")
+			describeProperty(schema, "assignees", " Defaults to the assignees configured for the project, when omitted.")
+			schema.Properties["project_id"] = &jsonschema.Schema{
+				Type: "integer",
+				Description: "The ID of the project, used to choose the configured default tasklist and assignees. " +
+					"Defaults to the project set with " + string(MethodDefaultProjectSet) + ", when omitted.",
+			}
+			makeOptional(schema, "tasklist_id")
+		})
+		if !ok {
+			return tool
+		}
+		tool.Tool = toolCopy
+		tool.Handler = t.middleware()(tool.Handler)
+		return tool
+	})
+}
+
+// middleware fills the omitted tasklist and assignees of the task with the
+// defaults of its project.
+func (t *taskDefaults) middleware() toolsets.ToolMiddleware {
+	return func(handler mcp.ToolHandler) mcp.ToolHandler {
+		return func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var arguments map[string]any
+			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+				return handler(ctx, request)
+			}
+			if arguments == nil {
+				arguments = make(map[string]any)
+			}
+
+			var projectID int64
+			if err := helpers.ParamGroup(arguments, helpers.OptionalNumericParam(&projectID, "project_id")); err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
+			}
+			delete(arguments, "project_id")
+
+			if projectID == 0 && arguments["tasklist_id"] != nil {
+				var err error
+				if projectID, err = t.tasklistProject(ctx, arguments["tasklist_id"]); err != nil {
+					return helpers.HandleAPIError(err, "failed to load the tasklist project")
+				}
+			}
+			if projectID == 0 {
+				projectID, _ = t.projects.resolve(ctx, request)
+			}
+			projectDefaults, _ := t.config.Project(projectID)
+
+			if arguments["tasklist_id"] == nil {
+				if projectDefaults.TasklistID == 0 {
+					return helpers.NewToolResultTextError("invalid parameters: tasklist_id is required, as no default " +
+						"tasklist is configured for the project"), nil
+				}
+				arguments["tasklist_id"] = float64(projectDefaults.TasklistID)
+			}
+
+			if arguments["assignees"] == nil {
+				userIDs, err := t.assignees(ctx, projectDefaults)
+				if err != nil {
+					return helpers.HandleAPIError(err, "failed to load the default assignee")
+				}
+				if len(userIDs) > 0 {
+					arguments["assignees"] = map[string]any{"user_ids": userIDs}
+				}
+			}
+
+			return callWithArguments(ctx, handler, request, arguments)
+		}
+	}
+}
+
+// tasklistProject returns the project of the tasklist, only loaded when some
+// project defines default assignees.
+func (t *taskDefaults) tasklistProject(ctx context.Context, tasklistID any) (int64, error) {
+	if !slices.ContainsFunc(t.config.Projects, func(project config.ProjectTaskDefaults) bool {
+		return len(project.AssigneeUserIDs) > 0
+	}) {
+		return 0, nil
+	}
+	id, ok := sandboxID(tasklistID)
+	if !ok {
+		// invalid tasklists are reported by the tool
+		return 0, nil
+	}
+	response, err := projects.TasklistGet(ctx, t.engine, projects.NewTasklistGetRequest(id))
+	if err != nil {
+		return 0, err
+	}
+	return response.Tasklist.Project.ID, nil
+}
+
+// assignees returns the users assigned to a task created without assignees, in
+// order of precedence the assignees of the project, the default assignees and
+// the user calling the tool.
+func (t *taskDefaults) assignees(ctx context.Context, projectDefaults config.ProjectTaskDefaults) ([]int64, error) {
+	switch {
+	case len(projectDefaults.AssigneeUserIDs) > 0:
+		return projectDefaults.AssigneeUserIDs, nil
+	case len(t.config.AssigneeUserIDs) > 0:
+		return t.config.AssigneeUserIDs, nil
+	case t.config.AssignToMe:
+		response, err := projects.UserGetMe(ctx, t.engine, projects.NewUserGetMeRequest())
+		if err != nil {
+			return nil, err
+		}
+		return []int64{response.User.ID}, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,97 @@
+package twprojects_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/config"
+	"github.com/teamwork/mcp/internal/testutil"
+	"github.com/teamwork/mcp/internal/twprojects"
+)
+
+func TestTaskDefaults(t *testing.T) {
+	defaults := config.TaskDefaults{
+		AssignToMe: true,
+		Projects: []config.ProjectTaskDefaults{
+			{ProjectID: 123, TasklistID: 456, AssigneeUserIDs: []int64{12}},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		arguments map[string]any
+		path      string
+		assignees []any
+		isError   bool
+	}{{
+		name:      "default project",
+		arguments: map[string]any{"name": "Follow up with ACME"},
+		path:      "/projects/api/v3/tasklists/456/tasks.json",
+		assignees: []any{float64(12)},
+	}, {
+		name:      "tasklist of another project",
+		arguments: map[string]any{"name": "Follow up with ACME", "tasklist_id": float64(789)},
+		path:      "/projects/api/v3/tasklists/789/tasks.json",
+		assignees: []any{float64(7)},
+	}, {
+		name:      "explicit assignees",
+		arguments: map[string]any{"name": "Follow up", "assignees": map[string]any{"user_ids": []any{float64(3)}}},
+		path:      "/projects/api/v3/tasklists/456/tasks.json",
+		assignees: []any{float64(3)},
+	}, {
+		name:      "project without default tasklist",
+		arguments: map[string]any{"name": "Follow up with ACME", "project_id": float64(999)},
+		isError:   true,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var path string
+			var assignees []any
+			mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(r *http.Request) (int, []byte) {
+				switch {
+				case r.Method == http.MethodGet && r.URL.Path == "/projects/api/v3/tasklists/789.json":
+					return http.StatusOK, []byte(`{"tasklist":{"id":789,"project":{"id":321}}}`)
+				case r.Method == http.MethodGet && r.URL.Path == "/projects/api/v3/me.json":
+					return http.StatusOK, []byte(`{"person":{"id":7}}`)
+				case r.Method == http.MethodPost:
+					path = r.URL.Path
+					var body struct {
+						Task struct {
+							Assignees struct {
+								UserIDs []any `json:"userIds"`
+							} `json:"assignees"`
+						} `json:"task"`
+					}
+					if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+						t.Errorf("failed to decode body: %v", err)
+					}
+					assignees = body.Task.Assignees.UserIDs
+					return http.StatusCreated, []byte(`{"task":{"id":1}}`)
+				}
+				return http.StatusOK, []byte(`{}`)
+			}, twprojects.WithDefaultProject(123), twprojects.WithTaskDefaults(defaults))
+
+			testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTaskCreate.String(), tt.arguments,
+				testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+					toolResult, ok := result.(*mcp.CallToolResult)
+					if !ok {
+						t.Fatalf("unexpected result type: %T", result)
+					}
+					if toolResult.IsError != tt.isError {
+						t.Errorf("unexpected error state %v: %v", toolResult.IsError, toolResult.Content)
+					}
+				}),
+			)
+			if path != tt.path {
+				t.Errorf("expected path %q, got %q", tt.path, path)
+			}
+			if !reflect.DeepEqual(assignees, tt.assignees) {
+				t.Errorf("expected assignees %v, got %v", tt.assignees, assignees)
+			}
+		})
+	}
+}
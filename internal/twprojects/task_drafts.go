@@ -0,0 +1,314 @@
+package twprojects
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/helpers"
+	"github.com/teamwork/mcp/internal/toolsets"
+	"github.com/teamwork/twapi-go-sdk"
+	"github.com/teamwork/twapi-go-sdk/projects"
+)
+
+// List of methods available in the Teamwork.com MCP service.
+//
+// The naming convention for methods follows a pattern described here:
+// https://github.com/github/github-mcp-server/issues/333
+const (
+	MethodTaskDraftFromText toolsets.Method = "twprojects-draft_tasks_from_text"
+)
+
+func init() {
+	// register the toolset methods
+	toolsets.RegisterMethod(MethodTaskDraftFromText)
+}
+
+var (
+	// reTaskDraftBullet matches list markers, checkboxes and action item
+	// prefixes at the start of a line.
+	reTaskDraftBullet = regexp.MustCompile(`(?i)^(?:[-*•+]\s+|\d+[.)]\s+)?(?:\[[ x]?\]\s*)?` +
+		`(?:(?:todo|action(?: item)?|ai)\s*:\s*)?`)
+	// reTaskDraftMention matches "@name" mentions.
+	reTaskDraftMention = regexp.MustCompile(`@([\p{L}][\p{L}.'-]*)`)
+	// reTaskDraftOwnerPrefix matches a leading "Name:" or "Name Surname:".
+	reTaskDraftOwnerPrefix = regexp.MustCompile(`^(\p{Lu}[\p{L}'-]*(?:\s\p{Lu}[\p{L}'-]*)?)\s*:\s+`)
+	// reTaskDraftOwnerSuffix matches a trailing "(Name)" or "(Name Surname)".
+	reTaskDraftOwnerSuffix = regexp.MustCompile(`\s*\((\p{Lu}[\p{L}'-]*(?:\s\p{Lu}[\p{L}'-]*)?)\)\s*$`)
+	// reTaskDraftDueDate matches due date expressions like "by 2025-10-01",
+	// "due tomorrow" or "by next friday".
+	reTaskDraftDueDate = regexp.MustCompile(`(?i)\s*\b(?:by|due(?: on| by)?|before|until)\s+(` +
+		`\d{4}-\d{2}-\d{2}|today|tomorrow|end of (?:the )?(?:week|month)|eo[wm]|next week|` +
+		`(?:next |this )?(?:monday|tuesday|wednesday|thursday|friday|saturday|sunday))\b[.,;]?`)
+)
+
+// taskDraft is a task proposed from a block of text.
+type taskDraft struct {
+	Name           string `json:"name"`
+	AssigneeGuess  string `json:"assigneeGuess,omitempty"`
+	AssigneeUserID int64  `json:"assigneeUserId,omitempty"`
+	DueDate        string `json:"dueDate,omitempty"`
+	TaskID         int64  `json:"taskId,omitempty"`
+}
+
+// TaskDraftFromText parses a block of text (e.g. meeting notes) into proposed
+// tasks, and creates them when confirmed.
+func TaskDraftFromText(engine *twapi.Engine) toolsets.ToolWrapper {
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name: string(MethodTaskDraftFromText),
+			Description: "Parse a pasted block of text, such as meeting notes, into a list of proposed tasks in " +
+				"Teamwork.com. Each line with a list marker, checkbox or action item prefix (e.g. 'TODO:') becomes a task; " +
+				"when there are none, every non-empty line is used. The assignee is guessed from '@name' mentions, a leading " +
+				"'Name:' or a trailing '(Name)', and the due date from expressions like 'by 2025-10-01', 'due tomorrow' or " +
+				"'by friday'. By default only a preview is returned; review it with the user and call the tool again with " +
+				"confirm=true to create the tasks in the tasklist; when a task fails to be created, the tasks already created " +
+				"are deleted, so the call can be retried. " + taskDescription,
+			Annotations: &mcp.ToolAnnotations{
+				Title: "Draft Tasks From Text",
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"text": {
+						Type:        "string",
+						Description: "The block of text to parse into tasks.",
+					},
+					"tasklist_id": {
+						Type: "integer",
+						Description: "The ID of the tasklist where the tasks will be created. Required when confirm is true. " +
+							"Use the " + string(MethodTasklistList) + " method to find the tasklist ID.",
+					},
+					"confirm": {
+						Type:        "boolean",
+						Description: "If true, the proposed tasks are created. Defaults to false, returning only the preview.",
+					},
+					"reference_date": {
+						Type:   "string",
+						Format: "date",
+						Description: "The date used to resolve relative due dates (e.g. 'tomorrow') in ISO 8601 format " +
+							"(YYYY-MM-DD). Defaults to today.",
+					},
+				},
+				Required: []string{"text"},
+			},
+		},
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var text string
+			var tasklistID int64
+			var confirm bool
+			referenceDate := twapi.Date(time.Now())
+
+			var arguments map[string]any
+			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("failed to decode request: %s", err.Error())), nil
+			}
+			err := helpers.ParamGroup(arguments,
+				helpers.RequiredParam(&text, "text"),
+				helpers.OptionalNumericParam(&tasklistID, "tasklist_id"),
+				helpers.OptionalParam(&confirm, "confirm"),
+				helpers.OptionalDateParam(&referenceDate, "reference_date"),
+			)
+			if err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
+			}
+			if confirm && tasklistID == 0 {
+				return helpers.NewToolResultTextError("invalid parameters: tasklist_id is required when confirm is true"), nil
+			}
+
+			drafts := parseTaskDrafts(text, time.Time(referenceDate))
+			if len(drafts) == 0 {
+				return helpers.NewToolResultTextError("no tasks found in the text"), nil
+			}
+
+			if !confirm {
+				return helpers.NewToolResultJSON(map[string]any{
+					"confirmed": false,
+					"tasks":     drafts,
+				})
+			}
+
+			// the tasks are created all or nothing, so a retry doesn't duplicate them
+			compensations := newCompensationLog(engine)
+			for i, draft := range drafts {
+				taskCreateRequest := projects.NewTaskCreateRequest(tasklistID, draft.Name)
+				if draft.DueDate != "" {
+					dueDate, err := time.Parse("2006-01-02", draft.DueDate)
+					if err == nil {
+						taskCreateRequest.DueAt = twapi.Ptr(twapi.Date(dueDate))
+					}
+				}
+				if draft.AssigneeGuess != "" {
+					userID, err := resolveTaskDraftAssignee(ctx, engine, draft.AssigneeGuess)
+					if err != nil {
+						return compensations.fail(ctx, err, "failed to resolve assignee")
+					}
+					if userID > 0 {
+						drafts[i].AssigneeUserID = userID
+						taskCreateRequest.Assignees = &projects.UserGroups{UserIDs: []int64{userID}}
+					}
+				}
+
+				taskResponse, err := projects.TaskCreate(ctx, engine, taskCreateRequest)
+				if err != nil {
+					return compensations.fail(ctx, err, fmt.Sprintf("failed to create task %q", draft.Name))
+				}
+				drafts[i].TaskID = taskResponse.Task.ID
+				compensations.recordTask(taskResponse.Task.ID)
+			}
+
+			return helpers.NewToolResultJSON(map[string]any{
+				"confirmed": true,
+				"tasks":     drafts,
+			})
+		},
+	}
+}
+
+// parseTaskDrafts extracts the proposed tasks from a block of text. Relative
+// due dates are resolved from the reference date.
+func parseTaskDrafts(text string, reference time.Time) []taskDraft {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+
+	// when the text contains list markers, only those lines are considered
+	// tasks; the remaining lines are usually headings or context
+	var hasMarkers bool
+	for _, line := range lines {
+		if line = strings.TrimSpace(line); line != "" && reTaskDraftBullet.FindString(line) != "" {
+			hasMarkers = true
+			break
+		}
+	}
+
+	var drafts []taskDraft
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		marker := reTaskDraftBullet.FindString(line)
+		if hasMarkers && marker == "" {
+			continue
+		}
+		line = strings.TrimSpace(strings.TrimPrefix(line, marker))
+		if line == "" || strings.HasSuffix(line, ":") {
+			continue
+		}
+
+		var draft taskDraft
+		if matches := reTaskDraftDueDate.FindStringSubmatch(line); matches != nil {
+			if dueDate, ok := resolveTaskDraftDate(matches[1], reference); ok {
+				draft.DueDate = dueDate.Format("2006-01-02")
+				line = strings.Replace(line, matches[0], "", 1)
+			}
+		}
+		if matches := reTaskDraftMention.FindStringSubmatch(line); matches != nil {
+			draft.AssigneeGuess = matches[1]
+			line = strings.Replace(line, matches[0], "", 1)
+		} else if matches := reTaskDraftOwnerPrefix.FindStringSubmatch(line); matches != nil {
+			draft.AssigneeGuess = matches[1]
+			line = strings.TrimPrefix(line, matches[0])
+		} else if matches := reTaskDraftOwnerSuffix.FindStringSubmatch(line); matches != nil {
+			draft.AssigneeGuess = matches[1]
+			line = strings.TrimSuffix(line, matches[0])
+		}
+
+		draft.Name = cleanTaskDraftName(line)
+		if draft.Name == "" {
+			continue
+		}
+		drafts = append(drafts, draft)
+	}
+	return drafts
+}
+
+// resolveTaskDraftDate converts a due date expression into a date.
+func resolveTaskDraftDate(expression string, reference time.Time) (time.Time, bool) {
+	reference = time.Date(reference.Year(), reference.Month(), reference.Day(), 0, 0, 0, 0, time.UTC)
+	expression = strings.ToLower(expression)
+
+	switch expression {
+	case "today":
+		return reference, true
+	case "tomorrow":
+		return reference.AddDate(0, 0, 1), true
+	case "end of week", "end of the week", "eow":
+		return weekdayOnOrAfter(reference, time.Friday), true
+	case "end of month", "end of the month", "eom":
+		return time.Date(reference.Year(), reference.Month()+1, 0, 0, 0, 0, 0, time.UTC), true
+	case "next week":
+		return weekdayOfNextWeek(reference, time.Monday), true
+	}
+
+	if date, err := time.Parse("2006-01-02", expression); err == nil {
+		return date, true
+	}
+
+	next := strings.HasPrefix(expression, "next ")
+	expression = strings.TrimPrefix(strings.TrimPrefix(expression, "next "), "this ")
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		if !strings.EqualFold(weekday.String(), expression) {
+			continue
+		}
+		if next {
+			return weekdayOfNextWeek(reference, weekday), true
+		}
+		return weekdayOnOrAfter(reference, weekday), true
+	}
+	return time.Time{}, false
+}
+
+// weekdayOnOrAfter returns the first date on or after the reference that falls
+// on the weekday.
+func weekdayOnOrAfter(reference time.Time, weekday time.Weekday) time.Time {
+	return reference.AddDate(0, 0, (int(weekday)-int(reference.Weekday())+7)%7)
+}
+
+// weekdayOfNextWeek returns the date of the weekday in the week after the
+// reference, considering weeks start on Monday.
+func weekdayOfNextWeek(reference time.Time, weekday time.Weekday) time.Time {
+	isoWeekday := func(weekday time.Weekday) int {
+		if weekday == time.Sunday {
+			return 7
+		}
+		return int(weekday)
+	}
+	nextMonday := reference.AddDate(0, 0, 8-isoWeekday(reference.Weekday()))
+	return nextMonday.AddDate(0, 0, isoWeekday(weekday)-1)
+}
+
+// cleanTaskDraftName removes leftover punctuation and spacing, capitalizing the
+// first letter.
+func cleanTaskDraftName(name string) string {
+	name = strings.Join(strings.Fields(name), " ")
+	name = strings.Trim(name, " -–—:;,.")
+	if name == "" {
+		return ""
+	}
+	first, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToUpper(first)) + name[size:]
+}
+
+// resolveTaskDraftAssignee searches for a user matching the assignee guess. It
+// returns zero when there isn't a single match, leaving the task unassigned.
+func resolveTaskDraftAssignee(ctx context.Context, engine *twapi.Engine, guess string) (int64, error) {
+	userListRequest := projects.NewUserListRequest()
+	userListRequest.Filters.SearchTerm = guess
+	userListRequest.Filters.PageSize = 2
+
+	userList, err := projects.UserList(ctx, engine, userListRequest)
+	if err != nil {
+		return 0, err
+	}
+	if len(userList.Users) != 1 {
+		return 0, nil
+	}
+	return userList.Users[0].ID, nil
+}
@@ -0,0 +1,99 @@
+package twprojects_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/testutil"
+	"github.com/teamwork/mcp/internal/twprojects"
+)
+
+func TestTaskDraftFromText(t *testing.T) {
+	mcpServer := mcpServerMock(t, http.StatusOK, []byte(`{}`))
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTaskDraftFromText.String(), map[string]any{
+		"text": "Weekly sync\n" +
+			"- @john send the proposal by tomorrow\n" +
+			"- Mary: review the budget due 2025-10-20\n" +
+			"* [ ] update the roadmap by next friday (Alice Smith)\n" +
+			"Thanks everyone!",
+		"reference_date": "2025-10-15",
+	}, testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+		t.Helper()
+
+		toolResult, ok := result.(*mcp.CallToolResult)
+		if !ok {
+			t.Fatalf("unexpected result type: %T", result)
+		}
+		if toolResult.IsError {
+			t.Fatalf("tool failed to execute: %v", toolResult.Content)
+		}
+
+		var preview struct {
+			Confirmed bool             `json:"confirmed"`
+			Tasks     []map[string]any `json:"tasks"`
+		}
+		if err := json.Unmarshal([]byte(toolResult.Content[0].(*mcp.TextContent).Text), &preview); err != nil {
+			t.Fatalf("failed to decode preview: %v", err)
+		}
+		expected := []map[string]any{
+			{"name": "Send the proposal", "assigneeGuess": "john", "dueDate": "2025-10-16"},
+			{"name": "Review the budget", "assigneeGuess": "Mary", "dueDate": "2025-10-20"},
+			{"name": "Update the roadmap", "assigneeGuess": "Alice Smith", "dueDate": "2025-10-24"},
+		}
+		if preview.Confirmed {
+			t.Errorf("expected preview not to be confirmed")
+		}
+		if !reflect.DeepEqual(preview.Tasks, expected) {
+			t.Errorf("expected tasks %v, got %v", expected, preview.Tasks)
+		}
+	}))
+}
+
+func TestTaskDraftFromTextConfirm(t *testing.T) {
+	mcpServer := mcpServerMock(t, http.StatusCreated, []byte(`{"task":{"id":123}}`))
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTaskDraftFromText.String(), map[string]any{
+		"text":        "- send the proposal by 2025-10-16\n- review the budget",
+		"tasklist_id": float64(456),
+		"confirm":     true,
+	})
+}
+
+func TestTaskDraftFromTextConfirmRollback(t *testing.T) {
+	var created int
+	var deleted []string
+	mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(r *http.Request) (int, []byte) {
+		switch r.Method {
+		case http.MethodPost:
+			created++
+			if created == 3 {
+				return http.StatusBadRequest, []byte(`{"errors":[{"detail":"invalid task"}]}`)
+			}
+			return http.StatusCreated, []byte(fmt.Sprintf(`{"task":{"id":%d}}`, 100+created))
+		case http.MethodDelete:
+			deleted = append(deleted, r.URL.Path)
+		}
+		return http.StatusOK, []byte(`{}`)
+	})
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTaskDraftFromText.String(), map[string]any{
+		"text":        "- send the proposal\n- review the budget\n- update the roadmap",
+		"tasklist_id": float64(456),
+		"confirm":     true,
+	}, testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+		toolResult, ok := result.(*mcp.CallToolResult)
+		if !ok {
+			t.Fatalf("unexpected result type: %T", result)
+		}
+		if !toolResult.IsError {
+			t.Errorf("expected an error, got %v", toolResult.Content)
+		}
+	}))
+
+	expected := []string{"/projects/api/v3/tasks/102.json", "/projects/api/v3/tasks/101.json"}
+	if !reflect.DeepEqual(deleted, expected) {
+		t.Errorf("expected deleted tasks %v, got %v", expected, deleted)
+	}
+}
@@ -0,0 +1,302 @@
+package twprojects
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/helpers"
+	"github.com/teamwork/mcp/internal/toolsets"
+	twapi "github.com/teamwork/twapi-go-sdk"
+)
+
+// List of methods available in the Teamwork.com MCP service.
+//
+// The naming convention for methods follows a pattern described here:
+// https://github.com/github/github-mcp-server/issues/333
+const (
+	MethodTaskLinkCreate toolsets.Method = "twprojects-create_task_link"
+	MethodTaskLinkList   toolsets.Method = "twprojects-list_task_links"
+)
+
+const taskLinkDescription = "Task links are related-task relationships between tasks of any project. Unlike the " +
+	"predecessors, which schedule tasks of the same project, they express cross-project blockers and references, " +
+	"e.g. a task of the mobile app project blocked by an API task of the backend project."
+
+func init() {
+	// register the toolset methods
+	toolsets.RegisterMethod(MethodTaskLinkCreate)
+	toolsets.RegisterMethod(MethodTaskLinkList)
+}
+
+// taskLinkCreateRequest represents the request to link a task to another task.
+type taskLinkCreateRequest struct {
+	TaskID        int64
+	RelatedTaskID int64
+	Type          string
+}
+
+// HTTPRequest creates an HTTP request for the taskLinkCreateRequest.
+func (t taskLinkCreateRequest) HTTPRequest(ctx context.Context, server string) (*http.Request, error) {
+	uri := server + "/projects/api/v3/tasks/" + strconv.FormatInt(t.TaskID, 10) + "/relationships.json"
+
+	payload := struct {
+		Relationship struct {
+			RelatedTaskID int64  `json:"relatedTaskId"`
+			Type          string `json:"type"`
+		} `json:"relationship"`
+	}{}
+	payload.Relationship.RelatedTaskID = t.RelatedTaskID
+	payload.Relationship.Type = t.Type
+
+	var body bytes.Buffer
+	if err := json.NewEncoder(&body).Encode(payload); err != nil {
+		return nil, fmt.Errorf("failed to encode create task link request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, uri, &body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+// taskLinkCreateResponse represents the response of linking a task to another
+// task.
+type taskLinkCreateResponse struct {
+	Relationship struct {
+		ID int64 `json:"id"`
+	} `json:"relationship"`
+}
+
+// HandleHTTPResponse handles the HTTP response for the taskLinkCreateResponse.
+// If some unexpected HTTP status code is returned by the API, a twapi.HTTPError
+// is returned.
+func (t *taskLinkCreateResponse) HandleHTTPResponse(resp *http.Response) error {
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return twapi.NewHTTPError(resp, "failed to create task link")
+	}
+	if err := json.NewDecoder(resp.Body).Decode(t); err != nil {
+		return fmt.Errorf("failed to decode create task link response: %w", err)
+	}
+	return nil
+}
+
+// taskLinkListRequest represents the request to list the links of a task.
+type taskLinkListRequest struct {
+	TaskID int64
+}
+
+// HTTPRequest creates an HTTP request for the taskLinkListRequest.
+func (t taskLinkListRequest) HTTPRequest(ctx context.Context, server string) (*http.Request, error) {
+	uri := server + "/projects/api/v3/tasks/" + strconv.FormatInt(t.TaskID, 10) + "/relationships.json"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	query := req.URL.Query()
+	query.Set("include", "tasks,projects")
+	req.URL.RawQuery = query.Encode()
+	return req, nil
+}
+
+// taskLinkListResponse contains the links of a task, with the linked tasks and
+// their projects.
+type taskLinkListResponse struct {
+	Relationships []struct {
+		ID          int64              `json:"id"`
+		Type        string             `json:"type"`
+		RelatedTask twapi.Relationship `json:"relatedTask"`
+	} `json:"relationships"`
+	Included struct {
+		Tasks map[string]struct {
+			Name      string `json:"name"`
+			Status    string `json:"status"`
+			ProjectID int64  `json:"projectId"`
+		} `json:"tasks"`
+		Projects map[string]struct {
+			Name string `json:"name"`
+		} `json:"projects"`
+	} `json:"included"`
+}
+
+// HandleHTTPResponse handles the HTTP response for the taskLinkListResponse. If
+// some unexpected HTTP status code is returned by the API, a twapi.HTTPError is
+// returned.
+func (t *taskLinkListResponse) HandleHTTPResponse(resp *http.Response) error {
+	if resp.StatusCode != http.StatusOK {
+		return twapi.NewHTTPError(resp, "failed to list task links")
+	}
+	if err := json.NewDecoder(resp.Body).Decode(t); err != nil {
+		return fmt.Errorf("failed to decode list task links response: %w", err)
+	}
+	return nil
+}
+
+// taskLink is a link of a task to a task of any project.
+type taskLink struct {
+	ID          int64  `json:"id"`
+	Type        string `json:"type"`
+	TaskID      int64  `json:"taskId"`
+	TaskName    string `json:"taskName,omitempty"`
+	TaskStatus  string `json:"taskStatus,omitempty"`
+	ProjectID   int64  `json:"projectId,omitempty"`
+	ProjectName string `json:"projectName,omitempty"`
+}
+
+// taskLinks contains the links of a task.
+type taskLinks struct {
+	TaskID int64      `json:"taskId"`
+	Links  []taskLink `json:"links"`
+	// OpenBlockers are the IDs of the incomplete tasks blocking the task.
+	OpenBlockers []int64 `json:"openBlockers"`
+}
+
+// summarizeTaskLinks resolves the linked tasks and their projects from the
+// included entities, listing the blockers that aren't completed yet.
+func summarizeTaskLinks(taskID int64, response *taskLinkListResponse) taskLinks {
+	links := taskLinks{
+		TaskID:       taskID,
+		Links:        []taskLink{},
+		OpenBlockers: []int64{},
+	}
+	for _, relationship := range response.Relationships {
+		link := taskLink{
+			ID:     relationship.ID,
+			Type:   relationship.Type,
+			TaskID: relationship.RelatedTask.ID,
+		}
+		if task, ok := response.Included.Tasks[strconv.FormatInt(link.TaskID, 10)]; ok {
+			link.TaskName = task.Name
+			link.TaskStatus = task.Status
+			link.ProjectID = task.ProjectID
+			link.ProjectName = response.Included.Projects[strconv.FormatInt(task.ProjectID, 10)].Name
+		}
+		links.Links = append(links.Links, link)
+		if link.Type == "blocked_by" && link.TaskStatus != "completed" {
+			links.OpenBlockers = append(links.OpenBlockers, link.TaskID)
+		}
+	}
+	return links
+}
+
+// TaskLinkCreate links a task to another task of any project in Teamwork.com.
+func TaskLinkCreate(engine *twapi.Engine) toolsets.ToolWrapper {
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name: string(MethodTaskLinkCreate),
+			Description: "Link a task to another task in Teamwork.com, which can be in a different project. " +
+				taskLinkDescription,
+			Annotations: &mcp.ToolAnnotations{
+				Title: "Create Task Link",
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"task_id": {
+						Type:        "integer",
+						Description: "The ID of the task owning the link.",
+					},
+					"related_task_id": {
+						Type:        "integer",
+						Description: "The ID of the linked task, from any project.",
+					},
+					"type": {
+						Type: "string",
+						Description: "The type of the link from the point of view of the task owning it: 'blocks' when " +
+							"the task blocks the linked task, 'blocked_by' when the task is blocked by the linked task and " +
+							"'relates_to' for a plain reference. Defaults to 'relates_to'.",
+						Enum: enumSchemaValues(taskLinkTypes),
+					},
+				},
+				Required: []string{"task_id", "related_task_id"},
+			},
+		},
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			linkCreateRequest := taskLinkCreateRequest{Type: "relates_to"}
+
+			var arguments map[string]any
+			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("failed to decode request: %s", err.Error())), nil
+			}
+			err := helpers.ParamGroup(arguments,
+				helpers.RequiredNumericParam(&linkCreateRequest.TaskID, "task_id"),
+				helpers.RequiredNumericParam(&linkCreateRequest.RelatedTaskID, "related_task_id"),
+				helpers.OptionalParam(&linkCreateRequest.Type, "type", helpers.RestrictValues(taskLinkTypes...)),
+			)
+			if err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
+			}
+			if linkCreateRequest.TaskID == linkCreateRequest.RelatedTaskID {
+				return helpers.NewToolResultTextError("invalid parameters: a task can't be linked to itself"), nil
+			}
+
+			response, err := twapi.Execute[taskLinkCreateRequest, *taskLinkCreateResponse](ctx, engine,
+				linkCreateRequest,
+			)
+			if err != nil {
+				return helpers.HandleAPIError(err, "failed to create task link")
+			}
+			return helpers.NewToolResultText("Task link created successfully with ID %d", response.Relationship.ID), nil
+		},
+	}
+}
+
+// TaskLinkList lists the links of a task in Teamwork.com to tasks of any
+// project.
+func TaskLinkList(engine *twapi.Engine) toolsets.ToolWrapper {
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name: string(MethodTaskLinkList),
+			Description: "List the links of a task in Teamwork.com to tasks of any project, with the project of each " +
+				"linked task and the incomplete tasks blocking it. " + taskLinkDescription,
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "List Task Links",
+				ReadOnlyHint: true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"task_id": {
+						Type:        "integer",
+						Description: "The ID of the task.",
+					},
+				},
+				Required: []string{"task_id"},
+			},
+		},
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var taskID int64
+
+			var arguments map[string]any
+			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("failed to decode request: %s", err.Error())), nil
+			}
+			err := helpers.ParamGroup(arguments,
+				helpers.RequiredNumericParam(&taskID, "task_id"),
+			)
+			if err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
+			}
+
+			response, err := twapi.Execute[taskLinkListRequest, *taskLinkListResponse](ctx, engine,
+				taskLinkListRequest{TaskID: taskID},
+			)
+			if err != nil {
+				return helpers.HandleAPIError(err, "failed to list task links")
+			}
+
+			encoded, err := json.Marshal(summarizeTaskLinks(taskID, response))
+			if err != nil {
+				return nil, err
+			}
+			return helpers.NewToolResultText("%s", encoded), nil
+		},
+	}
+}
@@ -0,0 +1,75 @@
+package twprojects_test
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/testutil"
+	"github.com/teamwork/mcp/internal/twprojects"
+)
+
+func TestTaskLinkList(t *testing.T) {
+	mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(r *http.Request) (int, []byte) {
+		if r.URL.Path != "/projects/api/v3/tasks/123/relationships.json" {
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+		return http.StatusOK, []byte(`{"relationships":[` +
+			`{"id":1,"type":"blocked_by","relatedTask":{"id":10,"type":"tasks"}},` +
+			`{"id":2,"type":"blocked_by","relatedTask":{"id":11,"type":"tasks"}},` +
+			`{"id":3,"type":"relates_to","relatedTask":{"id":12,"type":"tasks"}}],` +
+			`"included":{"tasks":{` +
+			`"10":{"name":"Build API","status":"new","projectId":5},` +
+			`"11":{"name":"Design","status":"completed","projectId":6},` +
+			`"12":{"name":"Docs","status":"new","projectId":5}},` +
+			`"projects":{"5":{"name":"Backend"},"6":{"name":"Design"}}}}`)
+	})
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTaskLinkList.String(), map[string]any{
+		"task_id": float64(123),
+	}, testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+		toolResult, ok := result.(*mcp.CallToolResult)
+		if !ok {
+			t.Fatalf("unexpected result type: %T", result)
+		}
+		if toolResult.IsError {
+			t.Fatalf("tool failed to execute: %v", toolResult.Content)
+		}
+		text := toolResult.Content[0].(*mcp.TextContent).Text
+		expected := `{"taskId":123,"links":[` +
+			`{"id":1,"type":"blocked_by","taskId":10,"taskName":"Build API","taskStatus":"new","projectId":5,` +
+			`"projectName":"Backend"},` +
+			`{"id":2,"type":"blocked_by","taskId":11,"taskName":"Design","taskStatus":"completed","projectId":6,` +
+			`"projectName":"Design"},` +
+			`{"id":3,"type":"relates_to","taskId":12,"taskName":"Docs","taskStatus":"new","projectId":5,` +
+			`"projectName":"Backend"}],"openBlockers":[10]}`
+		if text != expected {
+			t.Errorf("expected %s, got %s", expected, text)
+		}
+	}))
+}
+
+func TestTaskLinkCreate(t *testing.T) {
+	var body string
+	mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(r *http.Request) (int, []byte) {
+		if r.Method != http.MethodPost || r.URL.Path != "/projects/api/v3/tasks/123/relationships.json" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		content, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("failed to read request body: %v", err)
+		}
+		body = string(content)
+		return http.StatusCreated, []byte(`{"relationship":{"id":7}}`)
+	})
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTaskLinkCreate.String(), map[string]any{
+		"task_id":         float64(123),
+		"related_task_id": float64(456),
+		"type":            "blocked_by",
+	})
+
+	if expected := `{"relationship":{"relatedTaskId":456,"type":"blocked_by"}}`; strings.TrimSpace(body) != expected {
+		t.Errorf("expected body %s, got %s", expected, body)
+	}
+}
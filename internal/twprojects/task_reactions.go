@@ -0,0 +1,263 @@
+package twprojects
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/helpers"
+	"github.com/teamwork/mcp/internal/toolsets"
+	"github.com/teamwork/twapi-go-sdk"
+)
+
+// List of methods available in the Teamwork.com MCP service.
+//
+// The naming convention for methods follows a pattern described here:
+// https://github.com/github/github-mcp-server/issues/333
+const (
+	MethodTaskReactionList toolsets.Method = "twprojects-list_task_reactions"
+	MethodTaskReactionAdd  toolsets.Method = "twprojects-add_task_reaction"
+)
+
+const taskReactionDescription = "Reactions are emoji responses (like, dislike, joy, frown or heart) that users add " +
+	"to a task. Teams often use them as a lightweight acknowledgement that a task was seen or agreed on, without " +
+	"writing a comment."
+
+func init() {
+	// register the toolset methods
+	toolsets.RegisterMethod(MethodTaskReactionList)
+	toolsets.RegisterMethod(MethodTaskReactionAdd)
+}
+
+// taskReactionListRequest represents the request to list the reactions of a
+// task.
+type taskReactionListRequest struct {
+	TaskID int64
+}
+
+// HTTPRequest creates an HTTP request for the taskReactionListRequest.
+func (t taskReactionListRequest) HTTPRequest(ctx context.Context, server string) (*http.Request, error) {
+	uri := server + "/projects/api/v3/tasks/" + strconv.FormatInt(t.TaskID, 10) + "/reactions.json"
+	return http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+}
+
+// taskReactionListResponse contains the reactions of a task.
+type taskReactionListResponse struct {
+	Reactions []struct {
+		Type   string `json:"type"`
+		UserID int64  `json:"userId"`
+	} `json:"reactions"`
+}
+
+// HandleHTTPResponse handles the HTTP response for the taskReactionListResponse.
+// If some unexpected HTTP status code is returned by the API, a twapi.HTTPError
+// is returned.
+func (t *taskReactionListResponse) HandleHTTPResponse(resp *http.Response) error {
+	if resp.StatusCode != http.StatusOK {
+		return twapi.NewHTTPError(resp, "failed to list task reactions")
+	}
+	if err := json.NewDecoder(resp.Body).Decode(t); err != nil {
+		return fmt.Errorf("failed to decode list task reactions response: %w", err)
+	}
+	return nil
+}
+
+// taskReactionAddRequest represents the request to add a reaction to a task.
+type taskReactionAddRequest struct {
+	TaskID int64
+	Type   string
+}
+
+// HTTPRequest creates an HTTP request for the taskReactionAddRequest.
+func (t taskReactionAddRequest) HTTPRequest(ctx context.Context, server string) (*http.Request, error) {
+	uri := server + "/projects/api/v3/tasks/" + strconv.FormatInt(t.TaskID, 10) + "/reactions.json"
+
+	payload := struct {
+		Reaction struct {
+			Type string `json:"type"`
+		} `json:"reaction"`
+	}{}
+	payload.Reaction.Type = t.Type
+
+	var body bytes.Buffer
+	if err := json.NewEncoder(&body).Encode(payload); err != nil {
+		return nil, fmt.Errorf("failed to encode add task reaction request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, uri, &body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+// taskReactionAddResponse represents the response of adding a reaction to a
+// task.
+type taskReactionAddResponse struct{}
+
+// HandleHTTPResponse handles the HTTP response for the taskReactionAddResponse.
+// If some unexpected HTTP status code is returned by the API, a twapi.HTTPError
+// is returned.
+func (t *taskReactionAddResponse) HandleHTTPResponse(resp *http.Response) error {
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK &&
+		resp.StatusCode != http.StatusNoContent {
+		return twapi.NewHTTPError(resp, "failed to add task reaction")
+	}
+	return nil
+}
+
+// taskReactionCount is the number of reactions of a type on a task.
+type taskReactionCount struct {
+	Type    string  `json:"type"`
+	Count   int     `json:"count"`
+	UserIDs []int64 `json:"userIds"`
+}
+
+// taskReactionSummary contains the reaction counts of a task.
+type taskReactionSummary struct {
+	TaskID    int64               `json:"taskId"`
+	Reactions []taskReactionCount `json:"reactions"`
+	Total     int                 `json:"total"`
+}
+
+// summarizeTaskReactions groups the reactions of the task by type, keeping the
+// order of the known reaction types.
+func summarizeTaskReactions(taskID int64, response *taskReactionListResponse) taskReactionSummary {
+	summary := taskReactionSummary{
+		TaskID:    taskID,
+		Reactions: []taskReactionCount{},
+	}
+	counts := make(map[string]*taskReactionCount)
+	for _, reaction := range response.Reactions {
+		count, ok := counts[reaction.Type]
+		if !ok {
+			count = &taskReactionCount{Type: reaction.Type, UserIDs: []int64{}}
+			counts[reaction.Type] = count
+		}
+		count.Count++
+		count.UserIDs = append(count.UserIDs, reaction.UserID)
+		summary.Total++
+	}
+	for _, reactionType := range taskReactionTypes {
+		if count, ok := counts[reactionType]; ok {
+			summary.Reactions = append(summary.Reactions, *count)
+			delete(counts, reactionType)
+		}
+	}
+	// unknown reaction types are kept at the end, sorted by type
+	for _, reactionType := range slices.Sorted(maps.Keys(counts)) {
+		summary.Reactions = append(summary.Reactions, *counts[reactionType])
+	}
+	return summary
+}
+
+// TaskReactionList lists the reaction counts of a task in Teamwork.com.
+func TaskReactionList(engine *twapi.Engine) toolsets.ToolWrapper {
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name: string(MethodTaskReactionList),
+			Description: "List the reactions of a task in Teamwork.com, counted by type with the users that reacted. " +
+				taskReactionDescription,
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "List Task Reactions",
+				ReadOnlyHint: true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"task_id": {
+						Type:        "integer",
+						Description: "The ID of the task.",
+					},
+				},
+				Required: []string{"task_id"},
+			},
+		},
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var taskID int64
+
+			var arguments map[string]any
+			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("failed to decode request: %s", err.Error())), nil
+			}
+			err := helpers.ParamGroup(arguments,
+				helpers.RequiredNumericParam(&taskID, "task_id"),
+			)
+			if err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
+			}
+
+			response, err := twapi.Execute[taskReactionListRequest, *taskReactionListResponse](ctx, engine,
+				taskReactionListRequest{TaskID: taskID},
+			)
+			if err != nil {
+				return helpers.HandleAPIError(err, "failed to list task reactions")
+			}
+
+			encoded, err := json.Marshal(summarizeTaskReactions(taskID, response))
+			if err != nil {
+				return nil, err
+			}
+			return helpers.NewToolResultText("%s", encoded), nil
+		},
+	}
+}
+
+// TaskReactionAdd adds a reaction to a task in Teamwork.com.
+func TaskReactionAdd(engine *twapi.Engine) toolsets.ToolWrapper {
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name: string(MethodTaskReactionAdd),
+			Description: "Add a reaction of the logged user to a task in Teamwork.com, e.g. to acknowledge it. " +
+				taskReactionDescription,
+			Annotations: &mcp.ToolAnnotations{
+				Title: "Add Task Reaction",
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"task_id": {
+						Type:        "integer",
+						Description: "The ID of the task.",
+					},
+					"type": {
+						Type:        "string",
+						Description: "The type of the reaction.",
+						Enum:        enumSchemaValues(taskReactionTypes),
+					},
+				},
+				Required: []string{"task_id", "type"},
+			},
+		},
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var reactionAddRequest taskReactionAddRequest
+
+			var arguments map[string]any
+			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("failed to decode request: %s", err.Error())), nil
+			}
+			err := helpers.ParamGroup(arguments,
+				helpers.RequiredNumericParam(&reactionAddRequest.TaskID, "task_id"),
+				helpers.RequiredParam(&reactionAddRequest.Type, "type", helpers.RestrictValues(taskReactionTypes...)),
+			)
+			if err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
+			}
+
+			_, err = twapi.Execute[taskReactionAddRequest, *taskReactionAddResponse](ctx, engine, reactionAddRequest)
+			if err != nil {
+				return helpers.HandleAPIError(err, "failed to add task reaction")
+			}
+			return helpers.NewToolResultText("Reaction %q added to task %d successfully",
+				reactionAddRequest.Type, reactionAddRequest.TaskID), nil
+		},
+	}
+}
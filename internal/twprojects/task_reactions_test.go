@@ -0,0 +1,57 @@
+package twprojects_test
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/testutil"
+	"github.com/teamwork/mcp/internal/twprojects"
+)
+
+func TestTaskReactionList(t *testing.T) {
+	mcpServer := mcpServerMock(t, http.StatusOK, []byte(`{"reactions":[{"type":"heart","userId":2},`+
+		`{"type":"like","userId":1},{"type":"like","userId":3}]}`))
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTaskReactionList.String(), map[string]any{
+		"task_id": float64(123),
+	}, testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+		toolResult, ok := result.(*mcp.CallToolResult)
+		if !ok {
+			t.Fatalf("unexpected result type: %T", result)
+		}
+		if toolResult.IsError {
+			t.Fatalf("tool failed to execute: %v", toolResult.Content)
+		}
+		text := toolResult.Content[0].(*mcp.TextContent).Text
+		expected := `{"taskId":123,"reactions":[{"type":"like","count":2,"userIds":[1,3]},` +
+			`{"type":"heart","count":1,"userIds":[2]}],"total":3}`
+		if text != expected {
+			t.Errorf("expected %s, got %s", expected, text)
+		}
+	}))
+}
+
+func TestTaskReactionAdd(t *testing.T) {
+	var body string
+	mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(r *http.Request) (int, []byte) {
+		if r.Method != http.MethodPost || r.URL.Path != "/projects/api/v3/tasks/123/reactions.json" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		content, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("failed to read request body: %v", err)
+		}
+		body = string(content)
+		return http.StatusCreated, []byte(`{}`)
+	})
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTaskReactionAdd.String(), map[string]any{
+		"task_id": float64(123),
+		"type":    "like",
+	})
+
+	if expected := `{"reaction":{"type":"like"}}`; strings.TrimSpace(body) != expected {
+		t.Errorf("expected body %s, got %s", expected, body)
+	}
+}
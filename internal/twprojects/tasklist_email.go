@@ -0,0 +1,161 @@
+package twprojects
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/helpers"
+	"github.com/teamwork/mcp/internal/toolsets"
+	twapi "github.com/teamwork/twapi-go-sdk"
+)
+
+// List of methods available in the Teamwork.com MCP service.
+//
+// The naming convention for methods follows a pattern described here:
+// https://github.com/github/github-mcp-server/issues/333
+const (
+	MethodTasklistEmailAddressGet        toolsets.Method = "twprojects-get_tasklist_email_address"
+	MethodTasklistEmailAddressRegenerate toolsets.Method = "twprojects-regenerate_tasklist_email_address"
+)
+
+const tasklistEmailAddressDescription = "Each tasklist in Teamwork.com has an email-in address: every email " +
+	"forwarded to it creates a task in the tasklist, with the subject as the task name and the body as its " +
+	"description."
+
+func init() {
+	// register the toolset methods
+	toolsets.RegisterMethod(MethodTasklistEmailAddressGet)
+	toolsets.RegisterMethod(MethodTasklistEmailAddressRegenerate)
+}
+
+// tasklistEmailAddressRequest represents the request to get or regenerate the
+// email-in address of a tasklist.
+type tasklistEmailAddressRequest struct {
+	TasklistID int64
+	Regenerate bool
+}
+
+// HTTPRequest creates an HTTP request for the tasklistEmailAddressRequest.
+func (t tasklistEmailAddressRequest) HTTPRequest(ctx context.Context, server string) (*http.Request, error) {
+	uri := server + "/tasklists/" + strconv.FormatInt(t.TasklistID, 10) + "/emailaddress.json"
+	method := http.MethodGet
+	if t.Regenerate {
+		uri = server + "/tasklists/" + strconv.FormatInt(t.TasklistID, 10) + "/emailaddress/reset.json"
+		method = http.MethodPut
+	}
+	return http.NewRequestWithContext(ctx, method, uri, nil)
+}
+
+// tasklistEmailAddressResponse contains the email-in address of a tasklist.
+type tasklistEmailAddressResponse struct {
+	EmailAddress struct {
+		Address string `json:"address"`
+	} `json:"emailaddress"`
+}
+
+// HandleHTTPResponse handles the HTTP response for the
+// tasklistEmailAddressResponse. If some unexpected HTTP status code is returned
+// by the API, a twapi.HTTPError is returned.
+func (t *tasklistEmailAddressResponse) HandleHTTPResponse(resp *http.Response) error {
+	if resp.StatusCode != http.StatusOK {
+		return twapi.NewHTTPError(resp, "failed to retrieve tasklist email address")
+	}
+	if err := json.NewDecoder(resp.Body).Decode(t); err != nil {
+		return fmt.Errorf("failed to decode tasklist email address response: %w", err)
+	}
+	return nil
+}
+
+// tasklistEmailAddressSchema is the input schema shared by the tasklist email
+// address tools.
+func tasklistEmailAddressSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"tasklist_id": {
+				Type:        "integer",
+				Description: "The ID of the tasklist.",
+			},
+		},
+		Required: []string{"tasklist_id"},
+	}
+}
+
+// tasklistEmailAddressHandler retrieves or regenerates the email-in address of
+// a tasklist.
+func tasklistEmailAddressHandler(engine *twapi.Engine, regenerate bool) mcp.ToolHandler {
+	return func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		emailAddressRequest := tasklistEmailAddressRequest{Regenerate: regenerate}
+
+		var arguments map[string]any
+		if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+			return helpers.NewToolResultTextError(fmt.Sprintf("failed to decode request: %s", err.Error())), nil
+		}
+		err := helpers.ParamGroup(arguments,
+			helpers.RequiredNumericParam(&emailAddressRequest.TasklistID, "tasklist_id"),
+		)
+		if err != nil {
+			return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
+		}
+
+		response, err := twapi.Execute[tasklistEmailAddressRequest, *tasklistEmailAddressResponse](ctx, engine,
+			emailAddressRequest)
+		if err != nil {
+			return helpers.HandleAPIError(err, "failed to retrieve tasklist email address")
+		}
+
+		encoded, err := json.Marshal(struct {
+			TasklistID   int64  `json:"tasklistId"`
+			EmailAddress string `json:"emailAddress"`
+			Regenerated  bool   `json:"regenerated"`
+		}{
+			TasklistID:   emailAddressRequest.TasklistID,
+			EmailAddress: response.EmailAddress.Address,
+			Regenerated:  regenerate,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return helpers.NewToolResultText("%s", encoded), nil
+	}
+}
+
+// TasklistEmailAddressGet retrieves the email-in address of a tasklist in
+// Teamwork.com.
+func TasklistEmailAddressGet(engine *twapi.Engine) toolsets.ToolWrapper {
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name: string(MethodTasklistEmailAddressGet),
+			Description: "Retrieve the email-in address of a tasklist in Teamwork.com, e.g. to tell a user where to " +
+				"forward emails so they become tasks. " + tasklistEmailAddressDescription,
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "Get Tasklist Email Address",
+				ReadOnlyHint: true,
+			},
+			InputSchema: tasklistEmailAddressSchema(),
+		},
+		Handler: tasklistEmailAddressHandler(engine, false),
+	}
+}
+
+// TasklistEmailAddressRegenerate regenerates the email-in address of a
+// tasklist in Teamwork.com.
+func TasklistEmailAddressRegenerate(engine *twapi.Engine) toolsets.ToolWrapper {
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name: string(MethodTasklistEmailAddressRegenerate),
+			Description: "Regenerate the email-in address of a tasklist in Teamwork.com, e.g. when it's receiving " +
+				"spam. The previous address stops working immediately. " + tasklistEmailAddressDescription,
+			Annotations: &mcp.ToolAnnotations{
+				Title: "Regenerate Tasklist Email Address",
+			},
+			InputSchema: tasklistEmailAddressSchema(),
+		},
+		Handler: tasklistEmailAddressHandler(engine, true),
+	}
+}
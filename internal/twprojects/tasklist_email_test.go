@@ -0,0 +1,47 @@
+package twprojects_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/testutil"
+	"github.com/teamwork/mcp/internal/twprojects"
+)
+
+func TestTasklistEmailAddressGet(t *testing.T) {
+	mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(r *http.Request) (int, []byte) {
+		if r.Method != http.MethodGet || r.URL.Path != "/tasklists/123/emailaddress.json" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		return http.StatusOK, []byte(`{"emailaddress":{"address":"tasks+abc123@example.teamwork.com"}}`)
+	})
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTasklistEmailAddressGet.String(), map[string]any{
+		"tasklist_id": float64(123),
+	}, testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+		toolResult, ok := result.(*mcp.CallToolResult)
+		if !ok {
+			t.Fatalf("unexpected result type: %T", result)
+		}
+		if toolResult.IsError {
+			t.Fatalf("tool failed to execute: %v", toolResult.Content)
+		}
+		text := toolResult.Content[0].(*mcp.TextContent).Text
+		expected := `{"tasklistId":123,"emailAddress":"tasks+abc123@example.teamwork.com","regenerated":false}`
+		if text != expected {
+			t.Errorf("expected %s, got %s", expected, text)
+		}
+	}))
+}
+
+func TestTasklistEmailAddressRegenerate(t *testing.T) {
+	mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(r *http.Request) (int, []byte) {
+		if r.Method != http.MethodPut || r.URL.Path != "/tasklists/123/emailaddress/reset.json" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		return http.StatusOK, []byte(`{"emailaddress":{"address":"tasks+def456@example.teamwork.com"}}`)
+	})
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTasklistEmailAddressRegenerate.String(), map[string]any{
+		"tasklist_id": float64(123),
+	})
+}
@@ -0,0 +1,115 @@
+package twprojects
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/teamwork/mcp/internal/helpers"
+	twapi "github.com/teamwork/twapi-go-sdk"
+	"github.com/teamwork/twapi-go-sdk/projects"
+)
+
+// tasklistProgressMaxTasks is the maximum number of tasks loaded to compute
+// the progress of the listed tasklists.
+const tasklistProgressMaxTasks = 5000
+
+// includeProgressSchema returns the schema of the include_progress parameter
+// of the tasklist list tools.
+func includeProgressSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type: "boolean",
+		Description: "If true, the open, completed and overdue task counts of each tasklist are included, keyed by " +
+			"tasklist ID. Use it for status overviews instead of listing every task.",
+	}
+}
+
+// tasklistProgress is the number of tasks of a tasklist by state. Overdue
+// tasks are open tasks due before today.
+type tasklistProgress struct {
+	Total           int `json:"total"`
+	Open            int `json:"open"`
+	Completed       int `json:"completed"`
+	Overdue         int `json:"overdue"`
+	PercentComplete int `json:"percentComplete"`
+}
+
+// tasklistListWithProgress is a page of tasklists with the progress of each
+// one.
+type tasklistListWithProgress struct {
+	*projects.TasklistListResponse
+
+	Progress map[string]tasklistProgress `json:"progress"`
+	// ProgressTruncated is true when not all tasks were loaded, so the counts
+	// are lower than the actual ones.
+	ProgressTruncated bool `json:"progressTruncated,omitempty"`
+}
+
+// withTasklistProgress counts the tasks of the listed tasklists by state.
+func withTasklistProgress(
+	ctx context.Context,
+	engine *twapi.Engine,
+	tasklistList *projects.TasklistListResponse,
+) (*tasklistListWithProgress, error) {
+	list := &tasklistListWithProgress{
+		TasklistListResponse: tasklistList,
+		Progress:             make(map[string]tasklistProgress, len(tasklistList.Tasklists)),
+	}
+	if len(tasklistList.Tasklists) == 0 {
+		return list, nil
+	}
+
+	ids := make([]string, len(tasklistList.Tasklists))
+	for i, tasklist := range tasklistList.Tasklists {
+		ids[i] = strconv.FormatInt(tasklist.ID, 10)
+		list.Progress[ids[i]] = tasklistProgress{}
+	}
+	query := url.Values{
+		"tasklistIds":           []string{strings.Join(ids, ",")},
+		"includeCompletedTasks": []string{"true"},
+	}
+
+	tasks, truncated, err := helpers.FetchAllPages(ctx, helpers.AutoPagination{
+		PageSize: 100,
+		MaxItems: tasklistProgressMaxTasks,
+	}, func(ctx context.Context, page, pageSize int64) ([]projects.Task, bool, error) {
+		taskListRequest := projects.NewTaskListRequest()
+		taskListRequest.Filters.Page = page
+		taskListRequest.Filters.PageSize = pageSize
+
+		response, err := executeWithQuery[*projects.TaskListResponse](ctx, engine, taskListRequest, query)
+		if err != nil {
+			return nil, false, err
+		}
+		return response.Tasks, response.Meta.Page.HasMore, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	for _, task := range tasks {
+		key := strconv.FormatInt(task.Tasklist.ID, 10)
+		progress, ok := list.Progress[key]
+		if !ok {
+			continue
+		}
+		progress.Total++
+		switch {
+		case task.Status == "completed" || task.CompletedAt != nil:
+			progress.Completed++
+		case task.DueAt != nil && task.DueAt.Before(today):
+			progress.Open++
+			progress.Overdue++
+		default:
+			progress.Open++
+		}
+		progress.PercentComplete = progress.Completed * 100 / progress.Total
+		list.Progress[key] = progress
+	}
+	list.ProgressTruncated = truncated
+	return list, nil
+}
@@ -0,0 +1,244 @@
+package twprojects
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/helpers"
+	"github.com/teamwork/mcp/internal/toolsets"
+	twapi "github.com/teamwork/twapi-go-sdk"
+	"github.com/teamwork/twapi-go-sdk/projects"
+)
+
+// List of methods available in the Teamwork.com MCP service.
+//
+// The naming convention for methods follows a pattern described here:
+// https://github.com/github/github-mcp-server/issues/333
+const (
+	MethodTasklistTemplateList  toolsets.Method = "twprojects-list_tasklist_templates"
+	MethodTasklistTemplateApply toolsets.Method = "twprojects-apply_tasklist_template"
+)
+
+const tasklistTemplateDescription = "A task list template is a reusable task list, with its tasks and their " +
+	"relative dates, that can be added to any project. Templates standardize recurring work, such as QA checklists " +
+	"or onboarding steps."
+
+func init() {
+	// register the toolset methods
+	toolsets.RegisterMethod(MethodTasklistTemplateList)
+	toolsets.RegisterMethod(MethodTasklistTemplateApply)
+}
+
+// TasklistTemplateList lists the tasklist templates in Teamwork.com.
+func TasklistTemplateList(engine *twapi.Engine) toolsets.ToolWrapper {
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name:        string(MethodTasklistTemplateList),
+			Description: "List tasklist templates in Teamwork.com. " + tasklistTemplateDescription,
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "List Tasklist Templates",
+				ReadOnlyHint: true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:       "object",
+				Properties: map[string]*jsonschema.Schema{},
+			},
+		},
+		Handler: func(ctx context.Context, _ *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			templateList, err := twapi.Execute[tasklistTemplateListRequest, *tasklistTemplateListResponse](
+				ctx, engine, tasklistTemplateListRequest{})
+			if err != nil {
+				return helpers.HandleAPIError(err, "failed to list tasklist templates")
+			}
+			return helpers.NewToolResultJSON(templateList)
+		},
+	}
+}
+
+// TasklistTemplateApply creates a tasklist in a project from a tasklist
+// template in Teamwork.com.
+func TasklistTemplateApply(engine *twapi.Engine) toolsets.ToolWrapper {
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name: string(MethodTasklistTemplateApply),
+			Description: "Create a tasklist in a project from a tasklist template in Teamwork.com, copying all the " +
+				"tasks of the template. " + tasklistTemplateDescription,
+			Annotations: &mcp.ToolAnnotations{
+				Title: "Apply Tasklist Template",
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"template_id": {
+						Type:        "integer",
+						Description: "The ID of the tasklist template.",
+					},
+					"project_id": {
+						Type:        "integer",
+						Description: "The ID of the project where the tasklist will be created.",
+					},
+					"name": {
+						Type:        "string",
+						Description: "The name of the new tasklist. Defaults to the template name.",
+					},
+					"start_date": {
+						Type: "string",
+						Description: "The date the template tasks are anchored to, in the format YYYYMMDD. The task dates " +
+							"are calculated relative to it. Defaults to today.",
+					},
+					"keep_off_weekends": {
+						Type:        "boolean",
+						Description: "If true, the task dates falling on weekends are moved to the next working day.",
+					},
+				},
+				Required: []string{"template_id", "project_id"},
+			},
+		},
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var templateApplyRequest tasklistTemplateApplyRequest
+
+			var arguments map[string]any
+			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("failed to decode request: %s", err.Error())), nil
+			}
+			err := helpers.ParamGroup(arguments,
+				helpers.RequiredNumericParam(&templateApplyRequest.TemplateID, "template_id"),
+				helpers.RequiredNumericParam(&templateApplyRequest.ProjectID, "project_id"),
+				helpers.OptionalParam(&templateApplyRequest.Name, "name"),
+				helpers.OptionalLegacyDatePointerParam(&templateApplyRequest.StartDate, "start_date"),
+				helpers.OptionalParam(&templateApplyRequest.KeepOffWeekends, "keep_off_weekends"),
+			)
+			if err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
+			}
+
+			templateApply, err := twapi.Execute[tasklistTemplateApplyRequest, *tasklistTemplateApplyResponse](
+				ctx, engine, templateApplyRequest)
+			if err != nil {
+				return helpers.HandleAPIError(err, "failed to apply tasklist template")
+			}
+			return helpers.NewToolResultText("Tasklist created successfully with ID %d from template %d",
+				templateApply.TasklistID, templateApplyRequest.TemplateID), nil
+		},
+	}
+}
+
+// tasklistTemplateListRequest represents the request to load the tasklist
+// templates.
+type tasklistTemplateListRequest struct{}
+
+// HTTPRequest creates an HTTP request for the tasklistTemplateListRequest.
+func (t tasklistTemplateListRequest) HTTPRequest(ctx context.Context, server string) (*http.Request, error) {
+	return http.NewRequestWithContext(ctx, http.MethodGet, server+"/tasklists/templates.json", nil)
+}
+
+// tasklistTemplate is a tasklist template.
+type tasklistTemplate struct {
+	ID          projects.LegacyNumber `json:"id"`
+	Name        string                `json:"name"`
+	Description string                `json:"description"`
+}
+
+// MarshalJSON encodes the template with a numeric ID, as returned by the other
+// tools.
+func (t tasklistTemplate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		ID          int64  `json:"id"`
+		Name        string `json:"name"`
+		Description string `json:"description,omitempty"`
+	}{
+		ID:          int64(t.ID),
+		Name:        t.Name,
+		Description: t.Description,
+	})
+}
+
+// tasklistTemplateListResponse contains the tasklist templates.
+type tasklistTemplateListResponse struct {
+	Templates []tasklistTemplate `json:"todo-lists"`
+}
+
+// MarshalJSON encodes the response with a clearer root field than the API.
+func (t tasklistTemplateListResponse) MarshalJSON() ([]byte, error) {
+	templates := t.Templates
+	if templates == nil {
+		templates = []tasklistTemplate{}
+	}
+	return json.Marshal(map[string]any{"templates": templates})
+}
+
+// HandleHTTPResponse handles the HTTP response for the
+// tasklistTemplateListResponse.
+func (t *tasklistTemplateListResponse) HandleHTTPResponse(resp *http.Response) error {
+	if resp.StatusCode != http.StatusOK {
+		return twapi.NewHTTPError(resp, "failed to list tasklist templates")
+	}
+	if err := json.NewDecoder(resp.Body).Decode(t); err != nil {
+		return fmt.Errorf("failed to decode list tasklist templates response: %w", err)
+	}
+	return nil
+}
+
+// tasklistTemplateApplyRequest represents the request to create a tasklist from
+// a template.
+type tasklistTemplateApplyRequest struct {
+	TemplateID      int64
+	ProjectID       int64
+	Name            string
+	StartDate       *projects.LegacyDate
+	KeepOffWeekends bool
+}
+
+// HTTPRequest creates an HTTP request for the tasklistTemplateApplyRequest.
+func (t tasklistTemplateApplyRequest) HTTPRequest(ctx context.Context, server string) (*http.Request, error) {
+	uri := server + "/projects/" + strconv.FormatInt(t.ProjectID, 10) + "/tasklists.json"
+
+	payload := struct {
+		Tasklist struct {
+			Name            string               `json:"name,omitempty"`
+			TemplateID      string               `json:"todo-list-template-id"`
+			StartDate       *projects.LegacyDate `json:"todo-list-template-start-date,omitempty"`
+			KeepOffWeekends bool                 `json:"todo-list-template-keep-off-weekends"`
+		} `json:"todo-list"`
+	}{}
+	payload.Tasklist.Name = t.Name
+	payload.Tasklist.TemplateID = strconv.FormatInt(t.TemplateID, 10)
+	payload.Tasklist.StartDate = t.StartDate
+	payload.Tasklist.KeepOffWeekends = t.KeepOffWeekends
+
+	var body bytes.Buffer
+	if err := json.NewEncoder(&body).Encode(payload); err != nil {
+		return nil, fmt.Errorf("failed to encode apply tasklist template request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, uri, &body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+// tasklistTemplateApplyResponse contains the ID of the tasklist created from a
+// template.
+type tasklistTemplateApplyResponse struct {
+	TasklistID projects.LegacyNumber `json:"TASKLISTID"`
+}
+
+// HandleHTTPResponse handles the HTTP response for the
+// tasklistTemplateApplyResponse.
+func (t *tasklistTemplateApplyResponse) HandleHTTPResponse(resp *http.Response) error {
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return twapi.NewHTTPError(resp, "failed to apply tasklist template")
+	}
+	if err := json.NewDecoder(resp.Body).Decode(t); err != nil {
+		return fmt.Errorf("failed to decode apply tasklist template response: %w", err)
+	}
+	return nil
+}
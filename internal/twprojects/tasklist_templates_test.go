@@ -0,0 +1,25 @@
+package twprojects_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/teamwork/mcp/internal/testutil"
+	"github.com/teamwork/mcp/internal/twprojects"
+)
+
+func TestTasklistTemplateList(t *testing.T) {
+	mcpServer := mcpServerMock(t, http.StatusOK, []byte(`{"todo-lists":[{"id":"123","name":"QA checklist"}]}`))
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTasklistTemplateList.String(), map[string]any{})
+}
+
+func TestTasklistTemplateApply(t *testing.T) {
+	mcpServer := mcpServerMock(t, http.StatusCreated, []byte(`{"TASKLISTID":"456","STATUS":"OK"}`))
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTasklistTemplateApply.String(), map[string]any{
+		"template_id":       float64(123),
+		"project_id":        float64(789),
+		"name":              "QA",
+		"start_date":        "20240101",
+		"keep_off_weekends": true,
+	})
+}
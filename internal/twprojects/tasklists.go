@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/google/jsonschema-go/jsonschema"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -130,6 +131,7 @@ func TasklistUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 			InputSchema: &jsonschema.Schema{
 				Type: "object",
 				Properties: map[string]*jsonschema.Schema{
+					helpers.ExpectedUpdatedAtParam: helpers.ExpectedUpdatedAtSchema("tasklist"),
 					"id": {
 						Type:        "integer",
 						Description: "The ID of the tasklist to update.",
@@ -152,6 +154,7 @@ func TasklistUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 		},
 		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			var tasklistUpdateRequest projects.TasklistUpdateRequest
+			var expectedUpdatedAt *time.Time
 
 			var arguments map[string]any
 			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
@@ -159,6 +162,7 @@ func TasklistUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 			}
 			err := helpers.ParamGroup(arguments,
 				helpers.RequiredNumericParam(&tasklistUpdateRequest.Path.ID, "id"),
+				helpers.OptionalTimePointerParam(&expectedUpdatedAt, helpers.ExpectedUpdatedAtParam),
 				helpers.OptionalPointerParam(&tasklistUpdateRequest.Name, "name"),
 				helpers.OptionalPointerParam(&tasklistUpdateRequest.Description, "description"),
 				helpers.OptionalNumericPointerParam(&tasklistUpdateRequest.MilestoneID, "milestone_id"),
@@ -167,6 +171,18 @@ func TasklistUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
 			}
 
+			if expectedUpdatedAt != nil {
+				tasklistResponse, err := projects.TasklistGet(ctx, engine, projects.NewTasklistGetRequest(tasklistUpdateRequest.Path.ID))
+				if err != nil {
+					return helpers.HandleAPIError(err, "failed to get tasklist")
+				}
+				conflict := helpers.CheckConflict("tasklist", tasklistUpdateRequest.Path.ID, *expectedUpdatedAt,
+					tasklistResponse.Tasklist.UpdatedAt)
+				if conflict != nil {
+					return conflict, nil
+				}
+			}
+
 			_, err = projects.TasklistUpdate(ctx, engine, tasklistUpdateRequest)
 			if err != nil {
 				return helpers.HandleAPIError(err, "failed to update tasklist")
@@ -255,6 +271,10 @@ func TasklistGet(engine *twapi.Engine) toolsets.ToolWrapper {
 				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
 			}
 
+			if passthroughEnabled(ctx) {
+				return passthrough(ctx, engine, tasklistGetRequest, nil, "failed to get tasklist")
+			}
+
 			tasklist, err := projects.TasklistGet(ctx, engine, tasklistGetRequest)
 			if err != nil {
 				return helpers.HandleAPIError(err, "failed to get tasklist")
@@ -264,12 +284,14 @@ func TasklistGet(engine *twapi.Engine) toolsets.ToolWrapper {
 			if err != nil {
 				return nil, err
 			}
+			linked := helpers.WebLinker(ctx, encoded,
+				helpers.WebLinkerWithIDPathBuilder("/app/tasklists"),
+			)
 			return &mcp.CallToolResult{
+				Meta: helpers.WebLinkedEntitiesMeta(linked),
 				Content: []mcp.Content{
 					&mcp.TextContent{
-						Text: string(helpers.WebLinker(ctx, encoded,
-							helpers.WebLinkerWithIDPathBuilder("/app/tasklists"),
-						)),
+						Text: string(linked),
 					},
 				},
 				StructuredContent: tasklist,
@@ -303,12 +325,15 @@ func TasklistList(engine *twapi.Engine) toolsets.ToolWrapper {
 						Type:        "integer",
 						Description: "Number of results per page for pagination.",
 					},
+					"count_only":       countOnlySchema(),
+					"include_progress": includeProgressSchema(),
 				},
 			},
 			OutputSchema: tasklistListOutputSchema,
 		},
 		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			var tasklistListRequest projects.TasklistListRequest
+			var countOnly, includeProgress bool
 
 			var arguments map[string]any
 			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
@@ -318,29 +343,47 @@ func TasklistList(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalParam(&tasklistListRequest.Filters.SearchTerm, "search_term"),
 				helpers.OptionalNumericParam(&tasklistListRequest.Filters.Page, "page"),
 				helpers.OptionalNumericParam(&tasklistListRequest.Filters.PageSize, "page_size"),
+				helpers.OptionalParam(&countOnly, "count_only"),
+				helpers.OptionalParam(&includeProgress, "include_progress"),
 			)
 			if err != nil {
 				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
 			}
 
+			if countOnly {
+				return listCount(ctx, engine, tasklistListRequest)
+			}
+
+			if passthroughEnabled(ctx) {
+				return passthrough(ctx, engine, tasklistListRequest, nil, "failed to list tasklists")
+			}
+
 			tasklistList, err := projects.TasklistList(ctx, engine, tasklistListRequest)
 			if err != nil {
 				return helpers.HandleAPIError(err, "failed to list tasklists")
 			}
+			var result any = tasklistList
+			if includeProgress {
+				if result, err = withTasklistProgress(ctx, engine, tasklistList); err != nil {
+					return helpers.HandleAPIError(err, "failed to count the tasks of the tasklists")
+				}
+			}
 
-			encoded, err := json.Marshal(tasklistList)
+			encoded, err := json.Marshal(result)
 			if err != nil {
 				return nil, err
 			}
+			linked := helpers.WebLinker(ctx, encoded,
+				helpers.WebLinkerWithIDPathBuilder("/app/tasklists"),
+			)
 			return &mcp.CallToolResult{
+				Meta: helpers.WebLinkedEntitiesMeta(linked),
 				Content: []mcp.Content{
 					&mcp.TextContent{
-						Text: string(helpers.WebLinker(ctx, encoded,
-							helpers.WebLinkerWithIDPathBuilder("/app/tasklists"),
-						)),
+						Text: string(linked),
 					},
 				},
-				StructuredContent: tasklistList,
+				StructuredContent: result,
 			}, nil
 		},
 	}
@@ -375,6 +418,8 @@ func TasklistListByProject(engine *twapi.Engine) toolsets.ToolWrapper {
 						Type:        "integer",
 						Description: "Number of results per page for pagination.",
 					},
+					"count_only":       countOnlySchema(),
+					"include_progress": includeProgressSchema(),
 				},
 				Required: []string{"project_id"},
 			},
@@ -382,6 +427,7 @@ func TasklistListByProject(engine *twapi.Engine) toolsets.ToolWrapper {
 		},
 		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			var tasklistListRequest projects.TasklistListRequest
+			var countOnly, includeProgress bool
 
 			var arguments map[string]any
 			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
@@ -392,29 +438,47 @@ func TasklistListByProject(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalParam(&tasklistListRequest.Filters.SearchTerm, "search_term"),
 				helpers.OptionalNumericParam(&tasklistListRequest.Filters.Page, "page"),
 				helpers.OptionalNumericParam(&tasklistListRequest.Filters.PageSize, "page_size"),
+				helpers.OptionalParam(&countOnly, "count_only"),
+				helpers.OptionalParam(&includeProgress, "include_progress"),
 			)
 			if err != nil {
 				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
 			}
 
+			if countOnly {
+				return listCount(ctx, engine, tasklistListRequest)
+			}
+
+			if passthroughEnabled(ctx) {
+				return passthrough(ctx, engine, tasklistListRequest, nil, "failed to list tasklists")
+			}
+
 			tasklistList, err := projects.TasklistList(ctx, engine, tasklistListRequest)
 			if err != nil {
 				return helpers.HandleAPIError(err, "failed to list tasklists")
 			}
+			var result any = tasklistList
+			if includeProgress {
+				if result, err = withTasklistProgress(ctx, engine, tasklistList); err != nil {
+					return helpers.HandleAPIError(err, "failed to count the tasks of the tasklists")
+				}
+			}
 
-			encoded, err := json.Marshal(tasklistList)
+			encoded, err := json.Marshal(result)
 			if err != nil {
 				return nil, err
 			}
+			linked := helpers.WebLinker(ctx, encoded,
+				helpers.WebLinkerWithIDPathBuilder("/app/tasklists"),
+			)
 			return &mcp.CallToolResult{
+				Meta: helpers.WebLinkedEntitiesMeta(linked),
 				Content: []mcp.Content{
 					&mcp.TextContent{
-						Text: string(helpers.WebLinker(ctx, encoded,
-							helpers.WebLinkerWithIDPathBuilder("/app/tasklists"),
-						)),
+						Text: string(linked),
 					},
 				},
-				StructuredContent: tasklistList,
+				StructuredContent: result,
 			}, nil
 		},
 	}
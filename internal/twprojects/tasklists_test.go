@@ -1,9 +1,11 @@
 package twprojects_test
 
 import (
+	"encoding/json"
 	"net/http"
 	"testing"
 
+	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/teamwork/mcp/internal/testutil"
 	"github.com/teamwork/mcp/internal/twprojects"
 )
@@ -61,3 +63,65 @@ func TestTasklistListByProject(t *testing.T) {
 		"page_size":   float64(10),
 	})
 }
+
+func TestTasklistListProgress(t *testing.T) {
+	mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(r *http.Request) (int, []byte) {
+		switch r.URL.Path {
+		case "/projects/api/v3/projects/5/tasklists.json":
+			return http.StatusOK, []byte(`{"tasklists":[{"id":1,"name":"Design"},{"id":2,"name":"Build"}],` +
+				`"meta":{"page":{"hasMore":false}}}`)
+		case "/projects/api/v3/tasks.json":
+			query := r.URL.Query()
+			if query.Get("tasklistIds") != "1,2" || query.Get("includeCompletedTasks") != "true" {
+				t.Errorf("unexpected query %s", r.URL.RawQuery)
+			}
+			return http.StatusOK, []byte(`{"tasks":[` +
+				`{"id":10,"status":"completed","tasklist":{"id":1}},` +
+				`{"id":11,"status":"new","dueDate":"2020-01-01T00:00:00Z","tasklist":{"id":1}},` +
+				`{"id":12,"status":"new","tasklist":{"id":1}},` +
+				`{"id":13,"status":"completed","tasklist":{"id":1}}],` +
+				`"meta":{"page":{"hasMore":false}}}`)
+		}
+		t.Errorf("unexpected request %s", r.URL.Path)
+		return http.StatusNotFound, nil
+	})
+
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTasklistListByProject.String(), map[string]any{
+		"project_id":       float64(5),
+		"include_progress": true,
+	}, testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+		toolResult, ok := result.(*mcp.CallToolResult)
+		if !ok {
+			t.Fatalf("unexpected result type: %T", result)
+		}
+		if toolResult.IsError {
+			t.Fatalf("tool failed to execute: %v", toolResult.Content)
+		}
+		var list struct {
+			Tasklists []struct {
+				ID int64 `json:"id"`
+			} `json:"tasklists"`
+			Progress map[string]struct {
+				Total           int `json:"total"`
+				Open            int `json:"open"`
+				Completed       int `json:"completed"`
+				Overdue         int `json:"overdue"`
+				PercentComplete int `json:"percentComplete"`
+			} `json:"progress"`
+		}
+		if err := json.Unmarshal([]byte(toolResult.Content[0].(*mcp.TextContent).Text), &list); err != nil {
+			t.Fatalf("failed to decode result: %v", err)
+		}
+		if len(list.Tasklists) != 2 {
+			t.Errorf("expected 2 tasklists, got %d", len(list.Tasklists))
+		}
+		design := list.Progress["1"]
+		if design.Total != 4 || design.Open != 2 || design.Completed != 2 || design.Overdue != 1 ||
+			design.PercentComplete != 50 {
+			t.Errorf("unexpected progress of tasklist 1: %+v", design)
+		}
+		if build, ok := list.Progress["2"]; !ok || build.Total != 0 {
+			t.Errorf("unexpected progress of tasklist 2: %+v", build)
+		}
+	}))
+}
@@ -4,9 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/url"
+	"slices"
+	"time"
 
 	"github.com/google/jsonschema-go/jsonschema"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/config"
 	"github.com/teamwork/mcp/internal/helpers"
 	"github.com/teamwork/mcp/internal/toolsets"
 	"github.com/teamwork/twapi-go-sdk"
@@ -93,7 +97,7 @@ func TaskCreate(engine *twapi.Engine) toolsets.ToolWrapper {
 					"priority": {
 						Type:        "string",
 						Description: "The priority of the task. Possible values are: low, medium, high.",
-						Enum:        []any{"low", "medium", "high"},
+						Enum:        enumSchemaValues(taskPriorities),
 					},
 					"progress": {
 						Type:        "integer",
@@ -172,7 +176,7 @@ func TaskCreate(engine *twapi.Engine) toolsets.ToolWrapper {
 									Description: "The type of dependency. Possible values are: start or complete. 'start' means this " +
 										"task can complete when the predecessor starts, 'complete' means this task can complete when " +
 										"the predecessor completes.",
-									Enum: []any{"start", "complete"},
+									Enum: enumSchemaValues(taskPredecessorTypes),
 								},
 							},
 						},
@@ -193,7 +197,7 @@ func TaskCreate(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.RequiredNumericParam(&taskCreateRequest.Path.TasklistID, "tasklist_id"),
 				helpers.OptionalPointerParam(&taskCreateRequest.Description, "description"),
 				helpers.OptionalPointerParam(&taskCreateRequest.Priority, "priority",
-					helpers.RestrictValues("low", "medium", "high"),
+					helpers.RestrictValues(taskPriorities...),
 				),
 				helpers.OptionalNumericPointerParam(&taskCreateRequest.Progress, "progress"),
 				helpers.OptionalDatePointerParam(&taskCreateRequest.StartAt, "start_date"),
@@ -240,10 +244,7 @@ func TaskCreate(engine *twapi.Engine) toolsets.ToolWrapper {
 					err = helpers.ParamGroup(predecessorMap,
 						helpers.RequiredNumericParam(&p.ID, "task_id"),
 						helpers.RequiredParam(&p.Type, "type",
-							helpers.RestrictValues(
-								projects.TaskPredecessorTypeStart,
-								projects.TaskPredecessorTypeFinish,
-							),
+							helpers.RestrictValues(taskPredecessorTypes...),
 						),
 					)
 					if err != nil {
@@ -275,6 +276,7 @@ func TaskUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 			InputSchema: &jsonschema.Schema{
 				Type: "object",
 				Properties: map[string]*jsonschema.Schema{
+					helpers.ExpectedUpdatedAtParam: helpers.ExpectedUpdatedAtSchema("task"),
 					"id": {
 						Type:        "integer",
 						Description: "The ID of the task to update.",
@@ -290,7 +292,7 @@ func TaskUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 					"priority": {
 						Type:        "string",
 						Description: "The priority of the task. Possible values are: low, medium, high.",
-						Enum:        []any{"low", "medium", "high"},
+						Enum:        enumSchemaValues(taskPriorities),
 					},
 					"progress": {
 						Type:        "integer",
@@ -369,7 +371,7 @@ func TaskUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 									Description: "The type of dependency. Possible values are: start or complete. 'start' means this " +
 										"task can complete when the predecessor starts, 'complete' means this task can complete when the " +
 										"predecessor completes.",
-									Enum: []any{"start", "complete"},
+									Enum: enumSchemaValues(taskPredecessorTypes),
 								},
 							},
 						},
@@ -380,6 +382,7 @@ func TaskUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 		},
 		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			var taskUpdateRequest projects.TaskUpdateRequest
+			var expectedUpdatedAt *time.Time
 
 			var arguments map[string]any
 			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
@@ -387,11 +390,12 @@ func TaskUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 			}
 			err := helpers.ParamGroup(arguments,
 				helpers.RequiredNumericParam(&taskUpdateRequest.Path.ID, "id"),
+				helpers.OptionalTimePointerParam(&expectedUpdatedAt, helpers.ExpectedUpdatedAtParam),
 				helpers.OptionalNumericPointerParam(&taskUpdateRequest.TasklistID, "tasklist_id"),
 				helpers.OptionalPointerParam(&taskUpdateRequest.Name, "name"),
 				helpers.OptionalPointerParam(&taskUpdateRequest.Description, "description"),
 				helpers.OptionalPointerParam(&taskUpdateRequest.Priority, "priority",
-					helpers.RestrictValues("low", "medium", "high"),
+					helpers.RestrictValues(taskPriorities...),
 				),
 				helpers.OptionalNumericPointerParam(&taskUpdateRequest.Progress, "progress"),
 				helpers.OptionalDatePointerParam(&taskUpdateRequest.StartAt, "start_date"),
@@ -438,10 +442,7 @@ func TaskUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 					err = helpers.ParamGroup(predecessorMap,
 						helpers.RequiredNumericParam(&p.ID, "task_id"),
 						helpers.RequiredParam(&p.Type, "type",
-							helpers.RestrictValues(
-								projects.TaskPredecessorTypeStart,
-								projects.TaskPredecessorTypeFinish,
-							),
+							helpers.RestrictValues(taskPredecessorTypes...),
 						),
 					)
 					if err != nil {
@@ -452,6 +453,18 @@ func TaskUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 				}
 			}
 
+			if expectedUpdatedAt != nil {
+				taskResponse, err := projects.TaskGet(ctx, engine, projects.NewTaskGetRequest(taskUpdateRequest.Path.ID))
+				if err != nil {
+					return helpers.HandleAPIError(err, "failed to get task")
+				}
+				conflict := helpers.CheckConflict("task", taskUpdateRequest.Path.ID, *expectedUpdatedAt,
+					&taskResponse.Task.UpdatedAt)
+				if conflict != nil {
+					return conflict, nil
+				}
+			}
+
 			_, err = projects.TaskUpdate(ctx, engine, taskUpdateRequest)
 			if err != nil {
 				return helpers.HandleAPIError(err, "failed to update task")
@@ -499,7 +512,9 @@ func TaskDelete(engine *twapi.Engine) toolsets.ToolWrapper {
 			if err != nil {
 				return helpers.HandleAPIError(err, "failed to delete task")
 			}
-			return helpers.NewToolResultText("Task deleted successfully"), nil
+			result := helpers.NewToolResultText("Task deleted successfully")
+			result.Content = append(result.Content, trashRestoreHint("tasks", taskDeleteRequest.Path.ID))
+			return result, nil
 		},
 	}
 }
@@ -540,6 +555,10 @@ func TaskGet(engine *twapi.Engine) toolsets.ToolWrapper {
 				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
 			}
 
+			if passthroughEnabled(ctx) {
+				return passthrough(ctx, engine, taskGetRequest, nil, "failed to get task")
+			}
+
 			task, err := projects.TaskGet(ctx, engine, taskGetRequest)
 			if err != nil {
 				return helpers.HandleAPIError(err, "failed to get task")
@@ -549,12 +568,14 @@ func TaskGet(engine *twapi.Engine) toolsets.ToolWrapper {
 			if err != nil {
 				return nil, err
 			}
+			linked := helpers.WebLinker(ctx, encoded,
+				helpers.WebLinkerWithIDPathBuilder("/app/tasks"),
+			)
 			return &mcp.CallToolResult{
+				Meta: helpers.WebLinkedEntitiesMeta(linked),
 				Content: []mcp.Content{
 					&mcp.TextContent{
-						Text: string(helpers.WebLinker(ctx, encoded,
-							helpers.WebLinkerWithIDPathBuilder("/app/tasks"),
-						)),
+						Text: string(linked),
 					},
 				},
 				StructuredContent: task,
@@ -590,6 +611,9 @@ func TaskList(engine *twapi.Engine) toolsets.ToolWrapper {
 						Description: "A list of user IDs to filter tasks by assigned users",
 						Items:       &jsonschema.Schema{Type: "integer"},
 					},
+					"assignee":         taskAssigneeSchema(),
+					"completed_after":  taskCompletedAfterSchema(),
+					"completed_before": taskCompletedBeforeSchema(),
 					"match_all_tags": {
 						Type: "boolean",
 						Description: "If true, the search will match tasks that have all the specified tags. If false, the " +
@@ -603,12 +627,19 @@ func TaskList(engine *twapi.Engine) toolsets.ToolWrapper {
 						Type:        "integer",
 						Description: "Number of results per page for pagination.",
 					},
+					"count_only": countOnlySchema(),
+					"order_by":   taskListOrder.schema("tasks"),
+					"order_mode": orderModeSchema(),
 				},
 			},
 			OutputSchema: taskListOutputSchema,
 		},
 		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			var taskListRequest projects.TaskListRequest
+			var assignee string
+			var completion taskCompletionFilters
+			var countOnly bool
+			var orderBy, orderMode string
 
 			var arguments map[string]any
 			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
@@ -618,15 +649,49 @@ func TaskList(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalParam(&taskListRequest.Filters.SearchTerm, "search_term"),
 				helpers.OptionalNumericListParam(&taskListRequest.Filters.TagIDs, "tag_ids"),
 				helpers.OptionalNumericListParam(&taskListRequest.Filters.AssigneeUserIDs, "assignee_user_ids"),
+				helpers.OptionalParam(&assignee, "assignee", helpers.RestrictValues(taskAssignees...)),
+				helpers.OptionalTimePointerParam(&completion.after, "completed_after"),
+				helpers.OptionalTimePointerParam(&completion.before, "completed_before"),
 				helpers.OptionalPointerParam(&taskListRequest.Filters.MatchAllTags, "match_all_tags"),
 				helpers.OptionalNumericParam(&taskListRequest.Filters.Page, "page"),
 				helpers.OptionalNumericParam(&taskListRequest.Filters.PageSize, "page_size"),
+				helpers.OptionalParam(&countOnly, "count_only"),
+				helpers.OptionalParam(&orderBy, "order_by", helpers.RestrictValues(taskListOrder.fields()...)),
+				helpers.OptionalParam(&orderMode, "order_mode", helpers.RestrictValues(orderModes...)),
 			)
 			if err != nil {
 				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
 			}
 
-			taskList, err := projects.TaskList(ctx, engine, taskListRequest)
+			if assignee == taskAssigneeUnassigned && len(taskListRequest.Filters.AssigneeUserIDs) > 0 {
+				return helpers.NewToolResultTextError("invalid parameters: the unassigned assignee can't be combined " +
+					"with assignee_user_ids"), nil
+			}
+			assigneeQuery, err := taskAssigneeFilter(ctx, engine, &taskListRequest.Filters, assignee)
+			if err != nil {
+				return helpers.HandleAPIError(err, "failed to filter tasks by assignee")
+			}
+			if err := completion.validate(); err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
+			}
+			completion.apply(assigneeQuery)
+			filteredRequest := queryRequest[projects.TaskListRequest]{
+				request: taskListRequest,
+				query:   assigneeQuery,
+			}
+
+			if countOnly {
+				return listCount(ctx, engine, filteredRequest)
+			}
+
+			if passthroughEnabled(ctx) {
+				return passthrough(ctx, engine, filteredRequest, taskListOrder.query(orderBy, orderMode),
+					"failed to list tasks")
+			}
+
+			taskList, err := executeWithQuery[*projects.TaskListResponse](ctx, engine, filteredRequest,
+				taskListOrder.query(orderBy, orderMode),
+			)
 			if err != nil {
 				return helpers.HandleAPIError(err, "failed to list tasks")
 			}
@@ -635,12 +700,14 @@ func TaskList(engine *twapi.Engine) toolsets.ToolWrapper {
 			if err != nil {
 				return nil, err
 			}
+			linked := helpers.WebLinker(ctx, encoded,
+				helpers.WebLinkerWithIDPathBuilder("/app/tasks"),
+			)
 			return &mcp.CallToolResult{
+				Meta: helpers.WebLinkedEntitiesMeta(linked),
 				Content: []mcp.Content{
 					&mcp.TextContent{
-						Text: string(helpers.WebLinker(ctx, encoded,
-							helpers.WebLinkerWithIDPathBuilder("/app/tasks"),
-						)),
+						Text: string(linked),
 					},
 				},
 				StructuredContent: taskList,
@@ -680,6 +747,9 @@ func TaskListByTasklist(engine *twapi.Engine) toolsets.ToolWrapper {
 						Description: "A list of user IDs to filter tasks by assigned users",
 						Items:       &jsonschema.Schema{Type: "integer"},
 					},
+					"assignee":         taskAssigneeSchema(),
+					"completed_after":  taskCompletedAfterSchema(),
+					"completed_before": taskCompletedBeforeSchema(),
 					"match_all_tags": {
 						Type: "boolean",
 						Description: "If true, the search will match tasks that have all the specified tags. If false, the " +
@@ -693,6 +763,9 @@ func TaskListByTasklist(engine *twapi.Engine) toolsets.ToolWrapper {
 						Type:        "integer",
 						Description: "Number of results per page for pagination.",
 					},
+					"count_only": countOnlySchema(),
+					"order_by":   taskListOrder.schema("tasks"),
+					"order_mode": orderModeSchema(),
 				},
 				Required: []string{"tasklist_id"},
 			},
@@ -700,6 +773,10 @@ func TaskListByTasklist(engine *twapi.Engine) toolsets.ToolWrapper {
 		},
 		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			var taskListRequest projects.TaskListRequest
+			var assignee string
+			var completion taskCompletionFilters
+			var countOnly bool
+			var orderBy, orderMode string
 
 			var arguments map[string]any
 			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
@@ -710,15 +787,49 @@ func TaskListByTasklist(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalParam(&taskListRequest.Filters.SearchTerm, "search_term"),
 				helpers.OptionalNumericListParam(&taskListRequest.Filters.TagIDs, "tag_ids"),
 				helpers.OptionalNumericListParam(&taskListRequest.Filters.AssigneeUserIDs, "assignee_user_ids"),
+				helpers.OptionalParam(&assignee, "assignee", helpers.RestrictValues(taskAssignees...)),
+				helpers.OptionalTimePointerParam(&completion.after, "completed_after"),
+				helpers.OptionalTimePointerParam(&completion.before, "completed_before"),
 				helpers.OptionalPointerParam(&taskListRequest.Filters.MatchAllTags, "match_all_tags"),
 				helpers.OptionalNumericParam(&taskListRequest.Filters.Page, "page"),
 				helpers.OptionalNumericParam(&taskListRequest.Filters.PageSize, "page_size"),
+				helpers.OptionalParam(&countOnly, "count_only"),
+				helpers.OptionalParam(&orderBy, "order_by", helpers.RestrictValues(taskListOrder.fields()...)),
+				helpers.OptionalParam(&orderMode, "order_mode", helpers.RestrictValues(orderModes...)),
 			)
 			if err != nil {
 				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
 			}
 
-			taskList, err := projects.TaskList(ctx, engine, taskListRequest)
+			if assignee == taskAssigneeUnassigned && len(taskListRequest.Filters.AssigneeUserIDs) > 0 {
+				return helpers.NewToolResultTextError("invalid parameters: the unassigned assignee can't be combined " +
+					"with assignee_user_ids"), nil
+			}
+			assigneeQuery, err := taskAssigneeFilter(ctx, engine, &taskListRequest.Filters, assignee)
+			if err != nil {
+				return helpers.HandleAPIError(err, "failed to filter tasks by assignee")
+			}
+			if err := completion.validate(); err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
+			}
+			completion.apply(assigneeQuery)
+			filteredRequest := queryRequest[projects.TaskListRequest]{
+				request: taskListRequest,
+				query:   assigneeQuery,
+			}
+
+			if countOnly {
+				return listCount(ctx, engine, filteredRequest)
+			}
+
+			if passthroughEnabled(ctx) {
+				return passthrough(ctx, engine, filteredRequest, taskListOrder.query(orderBy, orderMode),
+					"failed to list tasks")
+			}
+
+			taskList, err := executeWithQuery[*projects.TaskListResponse](ctx, engine, filteredRequest,
+				taskListOrder.query(orderBy, orderMode),
+			)
 			if err != nil {
 				return helpers.HandleAPIError(err, "failed to list tasks")
 			}
@@ -727,12 +838,14 @@ func TaskListByTasklist(engine *twapi.Engine) toolsets.ToolWrapper {
 			if err != nil {
 				return nil, err
 			}
+			linked := helpers.WebLinker(ctx, encoded,
+				helpers.WebLinkerWithIDPathBuilder("/app/tasks"),
+			)
 			return &mcp.CallToolResult{
+				Meta: helpers.WebLinkedEntitiesMeta(linked),
 				Content: []mcp.Content{
 					&mcp.TextContent{
-						Text: string(helpers.WebLinker(ctx, encoded,
-							helpers.WebLinkerWithIDPathBuilder("/app/tasks"),
-						)),
+						Text: string(linked),
 					},
 				},
 				StructuredContent: taskList,
@@ -772,6 +885,9 @@ func TaskListByProject(engine *twapi.Engine) toolsets.ToolWrapper {
 						Description: "A list of user IDs to filter tasks by assigned users",
 						Items:       &jsonschema.Schema{Type: "integer"},
 					},
+					"assignee":         taskAssigneeSchema(),
+					"completed_after":  taskCompletedAfterSchema(),
+					"completed_before": taskCompletedBeforeSchema(),
 					"match_all_tags": {
 						Type: "boolean",
 						Description: "If true, the search will match tasks that have all the specified tags. If false, the " +
@@ -785,6 +901,9 @@ func TaskListByProject(engine *twapi.Engine) toolsets.ToolWrapper {
 						Type:        "integer",
 						Description: "Number of results per page for pagination.",
 					},
+					"count_only": countOnlySchema(),
+					"order_by":   taskListOrder.schema("tasks"),
+					"order_mode": orderModeSchema(),
 				},
 				Required: []string{"project_id"},
 			},
@@ -792,6 +911,10 @@ func TaskListByProject(engine *twapi.Engine) toolsets.ToolWrapper {
 		},
 		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			var taskListRequest projects.TaskListRequest
+			var assignee string
+			var completion taskCompletionFilters
+			var countOnly bool
+			var orderBy, orderMode string
 
 			var arguments map[string]any
 			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
@@ -802,15 +925,49 @@ func TaskListByProject(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalParam(&taskListRequest.Filters.SearchTerm, "search_term"),
 				helpers.OptionalNumericListParam(&taskListRequest.Filters.TagIDs, "tag_ids"),
 				helpers.OptionalNumericListParam(&taskListRequest.Filters.AssigneeUserIDs, "assignee_user_ids"),
+				helpers.OptionalParam(&assignee, "assignee", helpers.RestrictValues(taskAssignees...)),
+				helpers.OptionalTimePointerParam(&completion.after, "completed_after"),
+				helpers.OptionalTimePointerParam(&completion.before, "completed_before"),
 				helpers.OptionalPointerParam(&taskListRequest.Filters.MatchAllTags, "match_all_tags"),
 				helpers.OptionalNumericParam(&taskListRequest.Filters.Page, "page"),
 				helpers.OptionalNumericParam(&taskListRequest.Filters.PageSize, "page_size"),
+				helpers.OptionalParam(&countOnly, "count_only"),
+				helpers.OptionalParam(&orderBy, "order_by", helpers.RestrictValues(taskListOrder.fields()...)),
+				helpers.OptionalParam(&orderMode, "order_mode", helpers.RestrictValues(orderModes...)),
 			)
 			if err != nil {
 				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
 			}
 
-			taskList, err := projects.TaskList(ctx, engine, taskListRequest)
+			if assignee == taskAssigneeUnassigned && len(taskListRequest.Filters.AssigneeUserIDs) > 0 {
+				return helpers.NewToolResultTextError("invalid parameters: the unassigned assignee can't be combined " +
+					"with assignee_user_ids"), nil
+			}
+			assigneeQuery, err := taskAssigneeFilter(ctx, engine, &taskListRequest.Filters, assignee)
+			if err != nil {
+				return helpers.HandleAPIError(err, "failed to filter tasks by assignee")
+			}
+			if err := completion.validate(); err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
+			}
+			completion.apply(assigneeQuery)
+			filteredRequest := queryRequest[projects.TaskListRequest]{
+				request: taskListRequest,
+				query:   assigneeQuery,
+			}
+
+			if countOnly {
+				return listCount(ctx, engine, filteredRequest)
+			}
+
+			if passthroughEnabled(ctx) {
+				return passthrough(ctx, engine, filteredRequest, taskListOrder.query(orderBy, orderMode),
+					"failed to list tasks")
+			}
+
+			taskList, err := executeWithQuery[*projects.TaskListResponse](ctx, engine, filteredRequest,
+				taskListOrder.query(orderBy, orderMode),
+			)
 			if err != nil {
 				return helpers.HandleAPIError(err, "failed to list tasks")
 			}
@@ -819,12 +976,14 @@ func TaskListByProject(engine *twapi.Engine) toolsets.ToolWrapper {
 			if err != nil {
 				return nil, err
 			}
+			linked := helpers.WebLinker(ctx, encoded,
+				helpers.WebLinkerWithIDPathBuilder("/app/tasks"),
+			)
 			return &mcp.CallToolResult{
+				Meta: helpers.WebLinkedEntitiesMeta(linked),
 				Content: []mcp.Content{
 					&mcp.TextContent{
-						Text: string(helpers.WebLinker(ctx, encoded,
-							helpers.WebLinkerWithIDPathBuilder("/app/tasks"),
-						)),
+						Text: string(linked),
 					},
 				},
 				StructuredContent: taskList,
@@ -832,3 +991,108 @@ func TaskListByProject(engine *twapi.Engine) toolsets.ToolWrapper {
 		},
 	}
 }
+
+// Special values of the assignee filter of the task list tools.
+const (
+	taskAssigneeAnyone     = "anyone"
+	taskAssigneeUnassigned = "unassigned"
+	taskAssigneeMe         = "me"
+)
+
+// taskAssignees are the special values of the assignee filter.
+var taskAssignees = []string{taskAssigneeAnyone, taskAssigneeUnassigned, taskAssigneeMe}
+
+// taskAssigneeSchema returns the schema of the assignee parameter of the task
+// list tools.
+func taskAssigneeSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type: "string",
+		Description: "Filter tasks by a special assignee. 'anyone' returns the tasks assigned to at least one user, " +
+			"'unassigned' returns the tasks without assignees (e.g. the backlog to triage) and 'me' returns the tasks " +
+			"assigned to the logged user. It can be combined with assignee_user_ids, except for 'unassigned'.",
+		Enum: enumSchemaValues(taskAssignees),
+	}
+}
+
+// taskCompletedAfterSchema returns the schema of the completed_after parameter
+// of the task list tools.
+func taskCompletedAfterSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:   "string",
+		Format: "date-time",
+		Description: "Filter tasks completed after this date and time, including the completed tasks in the " +
+			"results. The date format follows RFC3339 - YYYY-MM-DDTHH:MM:SSZ.",
+	}
+}
+
+// taskCompletedBeforeSchema returns the schema of the completed_before
+// parameter of the task list tools.
+func taskCompletedBeforeSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:   "string",
+		Format: "date-time",
+		Description: "Filter tasks completed before this date and time, including the completed tasks in the " +
+			"results. The date format follows RFC3339 - YYYY-MM-DDTHH:MM:SSZ.",
+	}
+}
+
+// taskCompletionFilters are the completion date filters of the task list
+// tools.
+type taskCompletionFilters struct {
+	after  *time.Time
+	before *time.Time
+}
+
+// validate checks the completion date range isn't empty.
+func (f taskCompletionFilters) validate() error {
+	if f.after != nil && f.before != nil && !f.after.Before(*f.before) {
+		return fmt.Errorf("completed_after must be before completed_before")
+	}
+	return nil
+}
+
+// apply sets the API query parameters of the filters. The API excludes the
+// completed tasks unless they are explicitly included.
+func (f taskCompletionFilters) apply(query url.Values) {
+	if f.after == nil && f.before == nil {
+		return
+	}
+	query.Set("includeCompletedTasks", "true")
+	if f.after != nil {
+		query.Set("completedAfter", f.after.UTC().Format(time.RFC3339))
+	}
+	if f.before != nil {
+		query.Set("completedBefore", f.before.UTC().Format(time.RFC3339))
+	}
+}
+
+// taskAssigneeFilter applies the assignee special value to the task list
+// filters, returning the additional API query parameters.
+func taskAssigneeFilter(
+	ctx context.Context,
+	engine *twapi.Engine,
+	filters *projects.TaskListRequestFilters,
+	assignee string,
+) (url.Values, error) {
+	query := make(url.Values)
+	switch assignee {
+	case taskAssigneeAnyone:
+		query.Set("onlyAssignedTasks", "true")
+	case taskAssigneeUnassigned:
+		// the API represents the unassigned tasks with the responsible party 0
+		filters.AssigneeUserIDs = []int64{0}
+	case taskAssigneeMe:
+		userID, ok := config.UserIDFromContext(ctx)
+		if !ok {
+			response, err := projects.UserGetMe(ctx, engine, projects.NewUserGetMeRequest())
+			if err != nil {
+				return nil, err
+			}
+			userID = response.User.ID
+		}
+		if !slices.Contains(filters.AssigneeUserIDs, userID) {
+			filters.AssigneeUserIDs = append(filters.AssigneeUserIDs, userID)
+		}
+	}
+	return query, nil
+}
@@ -1,9 +1,12 @@
 package twprojects_test
 
 import (
+	"encoding/json"
 	"net/http"
 	"testing"
 
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/helpers"
 	"github.com/teamwork/mcp/internal/testutil"
 	"github.com/teamwork/mcp/internal/twprojects"
 )
@@ -71,6 +74,41 @@ func TestTaskUpdate(t *testing.T) {
 	})
 }
 
+func TestTaskUpdateConflict(t *testing.T) {
+	mcpServer := mcpServerMock(t, http.StatusOK, []byte(`{"task":{"id":123,"updatedAt":"2023-10-02T10:00:00Z"}}`))
+
+	tests := []struct {
+		name              string
+		expectedUpdatedAt string
+		wantConflict      bool
+	}{{
+		name:              "unchanged",
+		expectedUpdatedAt: "2023-10-02T10:00:00Z",
+	}, {
+		name:              "changed",
+		expectedUpdatedAt: "2023-10-01T10:00:00Z",
+		wantConflict:      true,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTaskUpdate.String(), map[string]any{
+				"id":                  float64(123),
+				"name":                "Example",
+				"expected_updated_at": tt.expectedUpdatedAt,
+			}, testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+				toolResult, ok := result.(*mcp.CallToolResult)
+				if !ok {
+					t.Fatalf("unexpected result type: %T", result)
+				}
+				if toolResult.IsError != tt.wantConflict {
+					t.Errorf("expected conflict %t, got result %v", tt.wantConflict, toolResult.Content)
+				}
+			}))
+		})
+	}
+}
+
 func TestTaskDelete(t *testing.T) {
 	mcpServer := mcpServerMock(t, http.StatusOK, []byte(`{}`))
 	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTaskDelete.String(), map[string]any{
@@ -94,9 +132,108 @@ func TestTaskList(t *testing.T) {
 		"page":              float64(1),
 		"page_size":         float64(10),
 		"assignee_user_ids": []float64{4, 5, 6},
+		"order_by":          "priority",
+		"order_mode":        "desc",
 	})
 }
 
+func TestTaskListAssignee(t *testing.T) {
+	tests := []struct {
+		name     string
+		assignee string
+		key      string
+		expected string
+	}{{
+		name:     "anyone",
+		assignee: "anyone",
+		key:      "onlyAssignedTasks",
+		expected: "true",
+	}, {
+		name:     "unassigned",
+		assignee: "unassigned",
+		key:      "responsiblePartyIds",
+		expected: "0",
+	}, {
+		name:     "me",
+		assignee: "me",
+		key:      "responsiblePartyIds",
+		expected: "4,42",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var value string
+			mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(r *http.Request) (int, []byte) {
+				if r.URL.Path == "/projects/api/v3/me.json" {
+					return http.StatusOK, []byte(`{"person":{"id":42}}`)
+				}
+				value = r.URL.Query().Get(tt.key)
+				return http.StatusOK, []byte(`{}`)
+			})
+			arguments := map[string]any{
+				"assignee": tt.assignee,
+			}
+			if tt.assignee != "unassigned" {
+				arguments["assignee_user_ids"] = []float64{4}
+			}
+			testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTaskList.String(), arguments)
+
+			if value != tt.expected {
+				t.Errorf("expected %s=%q, got %q", tt.key, tt.expected, value)
+			}
+		})
+	}
+}
+
+func TestTaskListPagination(t *testing.T) {
+	mcpServer := mcpServerMock(t, http.StatusOK, []byte(`{"meta":{"page":{"hasMore":true}},"tasks":[]}`))
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTaskList.String(), map[string]any{
+		"search_term": "test",
+		"page":        float64(2),
+	}, testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+		toolResult, ok := result.(*mcp.CallToolResult)
+		if !ok {
+			t.Fatalf("unexpected result type: %T", result)
+		}
+		if toolResult.IsError {
+			t.Fatalf("tool failed to execute: %v", toolResult.Content)
+		}
+
+		var response struct {
+			Pagination helpers.Pagination `json:"pagination"`
+		}
+		if err := json.Unmarshal([]byte(toolResult.Content[0].(*mcp.TextContent).Text), &response); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		pagination := response.Pagination
+		if pagination.Page != 2 || pagination.PageSize != 50 || !pagination.HasMore {
+			t.Errorf("unexpected pagination: %+v", pagination)
+		}
+		if pagination.NextPageArguments["page"] != float64(3) || pagination.NextPageArguments["search_term"] != "test" {
+			t.Errorf("unexpected next page arguments: %v", pagination.NextPageArguments)
+		}
+	}))
+}
+
+func TestTaskListCountOnly(t *testing.T) {
+	mcpServer := mcpServerMock(t, http.StatusOK, []byte(`{"meta":{"page":{"count":42,"hasMore":true}},"tasks":[]}`))
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTaskList.String(), map[string]any{
+		"search_term": "test",
+		"count_only":  true,
+	}, testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+		toolResult, ok := result.(*mcp.CallToolResult)
+		if !ok {
+			t.Fatalf("unexpected result type: %T", result)
+		}
+		if toolResult.IsError {
+			t.Fatalf("tool failed to execute: %v", toolResult.Content)
+		}
+		if text := toolResult.Content[0].(*mcp.TextContent).Text; text != `{"count":42}` {
+			t.Errorf("unexpected response: %s", text)
+		}
+	}))
+}
+
 func TestTaskListByTasklist(t *testing.T) {
 	mcpServer := mcpServerMock(t, http.StatusOK, []byte(`{}`))
 	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTaskListByTasklist.String(), map[string]any{
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/google/jsonschema-go/jsonschema"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -150,6 +151,7 @@ func TeamUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 			InputSchema: &jsonschema.Schema{
 				Type: "object",
 				Properties: map[string]*jsonschema.Schema{
+					helpers.ExpectedUpdatedAtParam: helpers.ExpectedUpdatedAtSchema("team"),
 					"id": {
 						Type:        "integer",
 						Description: "The ID of the team to update.",
@@ -192,6 +194,7 @@ func TeamUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 		},
 		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			var teamUpdateRequest projects.TeamUpdateRequest
+			var expectedUpdatedAt *time.Time
 
 			var arguments map[string]any
 			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
@@ -199,6 +202,7 @@ func TeamUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 			}
 			err := helpers.ParamGroup(arguments,
 				helpers.RequiredNumericParam(&teamUpdateRequest.Path.ID, "id"),
+				helpers.OptionalTimePointerParam(&expectedUpdatedAt, helpers.ExpectedUpdatedAtParam),
 				helpers.OptionalPointerParam(&teamUpdateRequest.Name, "name"),
 				helpers.OptionalPointerParam(&teamUpdateRequest.Handle, "handle"),
 				helpers.OptionalPointerParam(&teamUpdateRequest.Description, "description"),
@@ -210,6 +214,18 @@ func TeamUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
 			}
 
+			if expectedUpdatedAt != nil {
+				teamResponse, err := projects.TeamGet(ctx, engine, projects.NewTeamGetRequest(teamUpdateRequest.Path.ID))
+				if err != nil {
+					return helpers.HandleAPIError(err, "failed to get team")
+				}
+				conflict := helpers.CheckConflict("team", teamUpdateRequest.Path.ID, *expectedUpdatedAt,
+					&teamResponse.Team.UpdatedAt)
+				if conflict != nil {
+					return conflict, nil
+				}
+			}
+
 			_, err = projects.TeamUpdate(ctx, engine, teamUpdateRequest)
 			if err != nil {
 				return helpers.HandleAPIError(err, "failed to update team")
@@ -307,12 +323,14 @@ func TeamGet(engine *twapi.Engine) toolsets.ToolWrapper {
 			if err != nil {
 				return nil, err
 			}
+			linked := helpers.WebLinker(ctx, encoded,
+				helpers.WebLinkerWithIDPathBuilder("/app/teams"),
+			)
 			return &mcp.CallToolResult{
+				Meta: helpers.WebLinkedEntitiesMeta(linked),
 				Content: []mcp.Content{
 					&mcp.TextContent{
-						Text: string(helpers.WebLinker(ctx, encoded,
-							helpers.WebLinkerWithIDPathBuilder("/app/teams"),
-						)),
+						Text: string(linked),
 					},
 				},
 				StructuredContent: team,
@@ -346,12 +364,14 @@ func TeamList(engine *twapi.Engine) toolsets.ToolWrapper {
 						Type:        "integer",
 						Description: "Number of results per page for pagination.",
 					},
+					"count_only": countOnlySchema(),
 				},
 			},
 			OutputSchema: teamListOutputSchema,
 		},
 		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			var teamListRequest projects.TeamListRequest
+			var countOnly bool
 
 			// to simplify the teams logic for the LLM, always return all team types
 			teamListRequest.Filters.IncludeCompanyTeams = true
@@ -366,11 +386,16 @@ func TeamList(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalParam(&teamListRequest.Filters.SearchTerm, "search_term"),
 				helpers.OptionalNumericParam(&teamListRequest.Filters.Page, "page"),
 				helpers.OptionalNumericParam(&teamListRequest.Filters.PageSize, "page_size"),
+				helpers.OptionalParam(&countOnly, "count_only"),
 			)
 			if err != nil {
 				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
 			}
 
+			if countOnly {
+				return listCount(ctx, engine, teamListRequest)
+			}
+
 			teamList, err := projects.TeamList(ctx, engine, teamListRequest)
 			if err != nil {
 				return helpers.HandleAPIError(err, "failed to list teams")
@@ -380,12 +405,14 @@ func TeamList(engine *twapi.Engine) toolsets.ToolWrapper {
 			if err != nil {
 				return nil, err
 			}
+			linked := helpers.WebLinker(ctx, encoded,
+				helpers.WebLinkerWithIDPathBuilder("/app/teams"),
+			)
 			return &mcp.CallToolResult{
+				Meta: helpers.WebLinkedEntitiesMeta(linked),
 				Content: []mcp.Content{
 					&mcp.TextContent{
-						Text: string(helpers.WebLinker(ctx, encoded,
-							helpers.WebLinkerWithIDPathBuilder("/app/teams"),
-						)),
+						Text: string(linked),
 					},
 				},
 				StructuredContent: teamList,
@@ -423,6 +450,7 @@ func TeamListByCompany(engine *twapi.Engine) toolsets.ToolWrapper {
 						Type:        "integer",
 						Description: "Number of results per page for pagination.",
 					},
+					"count_only": countOnlySchema(),
 				},
 				Required: []string{"company_id"},
 			},
@@ -430,6 +458,7 @@ func TeamListByCompany(engine *twapi.Engine) toolsets.ToolWrapper {
 		},
 		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			var teamListRequest projects.TeamListRequest
+			var countOnly bool
 
 			var arguments map[string]any
 			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
@@ -440,11 +469,16 @@ func TeamListByCompany(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalParam(&teamListRequest.Filters.SearchTerm, "search_term"),
 				helpers.OptionalNumericParam(&teamListRequest.Filters.Page, "page"),
 				helpers.OptionalNumericParam(&teamListRequest.Filters.PageSize, "page_size"),
+				helpers.OptionalParam(&countOnly, "count_only"),
 			)
 			if err != nil {
 				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
 			}
 
+			if countOnly {
+				return listCount(ctx, engine, teamListRequest)
+			}
+
 			teamList, err := projects.TeamList(ctx, engine, teamListRequest)
 			if err != nil {
 				return helpers.HandleAPIError(err, "failed to list teams")
@@ -454,12 +488,14 @@ func TeamListByCompany(engine *twapi.Engine) toolsets.ToolWrapper {
 			if err != nil {
 				return nil, err
 			}
+			linked := helpers.WebLinker(ctx, encoded,
+				helpers.WebLinkerWithIDPathBuilder("/app/teams"),
+			)
 			return &mcp.CallToolResult{
+				Meta: helpers.WebLinkedEntitiesMeta(linked),
 				Content: []mcp.Content{
 					&mcp.TextContent{
-						Text: string(helpers.WebLinker(ctx, encoded,
-							helpers.WebLinkerWithIDPathBuilder("/app/teams"),
-						)),
+						Text: string(linked),
 					},
 				},
 				StructuredContent: teamList,
@@ -497,6 +533,7 @@ func TeamListByProject(engine *twapi.Engine) toolsets.ToolWrapper {
 						Type:        "integer",
 						Description: "Number of results per page for pagination.",
 					},
+					"count_only": countOnlySchema(),
 				},
 				Required: []string{"project_id"},
 			},
@@ -504,6 +541,7 @@ func TeamListByProject(engine *twapi.Engine) toolsets.ToolWrapper {
 		},
 		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			var teamListRequest projects.TeamListRequest
+			var countOnly bool
 
 			var arguments map[string]any
 			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
@@ -514,11 +552,16 @@ func TeamListByProject(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalParam(&teamListRequest.Filters.SearchTerm, "search_term"),
 				helpers.OptionalNumericParam(&teamListRequest.Filters.Page, "page"),
 				helpers.OptionalNumericParam(&teamListRequest.Filters.PageSize, "page_size"),
+				helpers.OptionalParam(&countOnly, "count_only"),
 			)
 			if err != nil {
 				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
 			}
 
+			if countOnly {
+				return listCount(ctx, engine, teamListRequest)
+			}
+
 			teamList, err := projects.TeamList(ctx, engine, teamListRequest)
 			if err != nil {
 				return helpers.HandleAPIError(err, "failed to list teams")
@@ -528,12 +571,14 @@ func TeamListByProject(engine *twapi.Engine) toolsets.ToolWrapper {
 			if err != nil {
 				return nil, err
 			}
+			linked := helpers.WebLinker(ctx, encoded,
+				helpers.WebLinkerWithIDPathBuilder("/app/teams"),
+			)
 			return &mcp.CallToolResult{
+				Meta: helpers.WebLinkedEntitiesMeta(linked),
 				Content: []mcp.Content{
 					&mcp.TextContent{
-						Text: string(helpers.WebLinker(ctx, encoded,
-							helpers.WebLinkerWithIDPathBuilder("/app/teams"),
-						)),
+						Text: string(linked),
 					},
 				},
 				StructuredContent: teamList,
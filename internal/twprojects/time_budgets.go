@@ -0,0 +1,376 @@
+package twprojects
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/helpers"
+	"github.com/teamwork/mcp/internal/toolsets"
+	twapi "github.com/teamwork/twapi-go-sdk"
+	"github.com/teamwork/twapi-go-sdk/projects"
+)
+
+// List of methods available in the Teamwork.com MCP service.
+//
+// The naming convention for methods follows a pattern described here:
+// https://github.com/github/github-mcp-server/issues/333
+const (
+	MethodProjectTimeBudgetGet toolsets.Method = "twprojects-get_project_time_budget"
+)
+
+const timeBudgetDescription = "A time budget caps the minutes that can be logged in a project during a period. " +
+	"Project managers follow its consumption to detect the tasklists and users using more time than planned."
+
+// timeBudgetMaxTimelogs is the maximum number of timelogs aggregated in the
+// time budget drill-down.
+const timeBudgetMaxTimelogs = 10000
+
+func init() {
+	// register the toolset methods
+	toolsets.RegisterMethod(MethodProjectTimeBudgetGet)
+}
+
+// projectBudgetListRequest represents the request to load the active time
+// budgets of a project.
+type projectBudgetListRequest struct {
+	ProjectID int64
+}
+
+// HTTPRequest creates an HTTP request for the projectBudgetListRequest.
+func (p projectBudgetListRequest) HTTPRequest(ctx context.Context, server string) (*http.Request, error) {
+	query := url.Values{}
+	query.Set("projectIds", strconv.FormatInt(p.ProjectID, 10))
+	query.Set("type", "TIME")
+	query.Set("status", "ACTIVE")
+	uri := server + "/projects/api/v3/projects/budgets.json?" + query.Encode()
+	return http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+}
+
+// projectBudget is a project budget. The capacity of time budgets is in
+// minutes.
+type projectBudget struct {
+	ID            int64      `json:"id"`
+	Type          string     `json:"type"`
+	Capacity      int64      `json:"capacity"`
+	CapacityUsed  int64      `json:"capacityUsed"`
+	StartDateTime *time.Time `json:"startDateTime"`
+	EndDateTime   *time.Time `json:"endDateTime"`
+}
+
+// projectBudgetListResponse contains the budgets of a project.
+type projectBudgetListResponse struct {
+	Budgets []projectBudget `json:"budgets"`
+}
+
+// HandleHTTPResponse handles the HTTP response for the
+// projectBudgetListResponse. If some unexpected HTTP status code is returned by
+// the API, a twapi.HTTPError is returned.
+func (p *projectBudgetListResponse) HandleHTTPResponse(resp *http.Response) error {
+	if resp.StatusCode != http.StatusOK {
+		return twapi.NewHTTPError(resp, "failed to list project budgets")
+	}
+	if err := json.NewDecoder(resp.Body).Decode(p); err != nil {
+		return fmt.Errorf("failed to decode list project budgets response: %w", err)
+	}
+	return nil
+}
+
+// timeBudgetTimelogListResponse contains the timelogs of a project with the
+// tasks, tasklists and users they reference.
+type timeBudgetTimelogListResponse struct {
+	Meta struct {
+		Page struct {
+			HasMore bool `json:"hasMore"`
+		} `json:"page"`
+	} `json:"meta"`
+	Timelogs []projects.Timelog `json:"timelogs"`
+	Included struct {
+		Tasks map[string]struct {
+			Tasklist twapi.Relationship `json:"tasklist"`
+		} `json:"tasks"`
+		Tasklists map[string]struct {
+			Name string `json:"name"`
+		} `json:"tasklists"`
+		Users map[string]struct {
+			FirstName string `json:"firstName"`
+			LastName  string `json:"lastName"`
+		} `json:"users"`
+	} `json:"included"`
+}
+
+// HandleHTTPResponse handles the HTTP response for the
+// timeBudgetTimelogListResponse. If some unexpected HTTP status code is
+// returned by the API, a twapi.HTTPError is returned.
+func (t *timeBudgetTimelogListResponse) HandleHTTPResponse(resp *http.Response) error {
+	if resp.StatusCode != http.StatusOK {
+		return twapi.NewHTTPError(resp, "failed to list timelogs")
+	}
+	if err := json.NewDecoder(resp.Body).Decode(t); err != nil {
+		return fmt.Errorf("failed to decode list timelogs response: %w", err)
+	}
+	return nil
+}
+
+// timeBudgetEntry is a timelog with the names of its tasklist and user.
+type timeBudgetEntry struct {
+	minutes      int64
+	tasklistID   int64
+	tasklistName string
+	userID       int64
+	userName     string
+}
+
+// timeBudgetUsage is the time logged in a tasklist or by a user.
+type timeBudgetUsage struct {
+	ID      int64  `json:"id"`
+	Name    string `json:"name,omitempty"`
+	Minutes int64  `json:"minutes"`
+	// BudgetPercentage is the percentage of the budget capacity used. It is
+	// only set when the project has an active time budget.
+	BudgetPercentage *float64 `json:"budgetPercentage,omitempty"`
+}
+
+// timeBudgetTasklist is the time logged in a tasklist, broken down by user.
+type timeBudgetTasklist struct {
+	timeBudgetUsage
+	Users []timeBudgetUsage `json:"users"`
+}
+
+// timeBudget is the capacity and consumption of a time budget, in minutes.
+type timeBudget struct {
+	ID               int64      `json:"id"`
+	CapacityMinutes  int64      `json:"capacityMinutes"`
+	UsedMinutes      int64      `json:"usedMinutes"`
+	RemainingMinutes int64      `json:"remainingMinutes"`
+	StartDate        *time.Time `json:"startDate,omitempty"`
+	EndDate          *time.Time `json:"endDate,omitempty"`
+}
+
+// timeBudgetSummary is the consumption of the time budget of a project.
+type timeBudgetSummary struct {
+	ProjectID int64 `json:"projectId"`
+	// Budget is the active time budget of the project, if any.
+	Budget        *timeBudget          `json:"budget"`
+	LoggedMinutes int64                `json:"loggedMinutes"`
+	Tasklists     []timeBudgetTasklist `json:"tasklists"`
+	Users         []timeBudgetUsage    `json:"users"`
+	// Truncated is true when the project has more timelogs than the aggregated
+	// ones.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// summarizeTimeBudget aggregates the time logged by tasklist and user, sorted
+// by the minutes logged. Timelogs without a task are grouped in a tasklist with
+// ID zero.
+func summarizeTimeBudget(projectID int64, budget *projectBudget, entries []timeBudgetEntry) timeBudgetSummary {
+	summary := timeBudgetSummary{
+		ProjectID: projectID,
+		Tasklists: []timeBudgetTasklist{},
+		Users:     []timeBudgetUsage{},
+	}
+	percentage := func(minutes int64) *float64 {
+		if budget == nil || budget.Capacity <= 0 {
+			return nil
+		}
+		value := math.Round(float64(minutes)*10000/float64(budget.Capacity)) / 100
+		return &value
+	}
+	if budget != nil {
+		summary.Budget = &timeBudget{
+			ID:               budget.ID,
+			CapacityMinutes:  budget.Capacity,
+			UsedMinutes:      budget.CapacityUsed,
+			RemainingMinutes: budget.Capacity - budget.CapacityUsed,
+			StartDate:        budget.StartDateTime,
+			EndDate:          budget.EndDateTime,
+		}
+	}
+
+	tasklists := make(map[int64]*timeBudgetTasklist)
+	tasklistUsers := make(map[int64]map[int64]*timeBudgetUsage)
+	users := make(map[int64]*timeBudgetUsage)
+	for _, entry := range entries {
+		summary.LoggedMinutes += entry.minutes
+
+		tasklist, ok := tasklists[entry.tasklistID]
+		if !ok {
+			tasklist = &timeBudgetTasklist{
+				timeBudgetUsage: timeBudgetUsage{ID: entry.tasklistID, Name: entry.tasklistName},
+			}
+			tasklists[entry.tasklistID] = tasklist
+			tasklistUsers[entry.tasklistID] = make(map[int64]*timeBudgetUsage)
+		}
+		tasklist.Minutes += entry.minutes
+
+		tasklistUser, ok := tasklistUsers[entry.tasklistID][entry.userID]
+		if !ok {
+			tasklistUser = &timeBudgetUsage{ID: entry.userID, Name: entry.userName}
+			tasklistUsers[entry.tasklistID][entry.userID] = tasklistUser
+		}
+		tasklistUser.Minutes += entry.minutes
+
+		user, ok := users[entry.userID]
+		if !ok {
+			user = &timeBudgetUsage{ID: entry.userID, Name: entry.userName}
+			users[entry.userID] = user
+		}
+		user.Minutes += entry.minutes
+	}
+
+	sortUsage := func(a, b timeBudgetUsage) int {
+		return cmp.Or(cmp.Compare(b.Minutes, a.Minutes), cmp.Compare(a.ID, b.ID))
+	}
+	for id, tasklist := range tasklists {
+		tasklist.BudgetPercentage = percentage(tasklist.Minutes)
+		tasklist.Users = []timeBudgetUsage{}
+		for _, user := range tasklistUsers[id] {
+			user.BudgetPercentage = percentage(user.Minutes)
+			tasklist.Users = append(tasklist.Users, *user)
+		}
+		slices.SortFunc(tasklist.Users, sortUsage)
+		summary.Tasklists = append(summary.Tasklists, *tasklist)
+	}
+	slices.SortFunc(summary.Tasklists, func(a, b timeBudgetTasklist) int {
+		return sortUsage(a.timeBudgetUsage, b.timeBudgetUsage)
+	})
+	for _, user := range users {
+		user.BudgetPercentage = percentage(user.Minutes)
+		summary.Users = append(summary.Users, *user)
+	}
+	slices.SortFunc(summary.Users, sortUsage)
+	return summary
+}
+
+// ProjectTimeBudgetGet returns the consumption of the time budget of a project
+// in Teamwork.com, broken down by tasklist and user.
+func ProjectTimeBudgetGet(engine *twapi.Engine) toolsets.ToolWrapper {
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name: string(MethodProjectTimeBudgetGet),
+			Description: "Get the consumption of the active time budget of a project in Teamwork.com, broken down by " +
+				"tasklist and by user, with the minutes logged and the percentage of the budget they used. Without an " +
+				"active time budget, only the logged time is broken down. " + timeBudgetDescription,
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "Get Project Time Budget",
+				ReadOnlyHint: true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"project_id": {
+						Type:        "integer",
+						Description: "The ID of the project.",
+					},
+					"start_date": {
+						Type:   "string",
+						Format: "date-time",
+						Description: "Start date of the logged time to break down. Defaults to the start of the active " +
+							"time budget. The date format follows RFC3339 - YYYY-MM-DDTHH:MM:SSZ.",
+					},
+					"end_date": {
+						Type:   "string",
+						Format: "date-time",
+						Description: "End date of the logged time to break down. Defaults to the end of the active time " +
+							"budget. The date format follows RFC3339 - YYYY-MM-DDTHH:MM:SSZ.",
+					},
+				},
+				Required: []string{"project_id"},
+			},
+		},
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var projectID int64
+			var startDate, endDate *time.Time
+
+			var arguments map[string]any
+			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("failed to decode request: %s", err.Error())), nil
+			}
+			err := helpers.ParamGroup(arguments,
+				helpers.RequiredNumericParam(&projectID, "project_id"),
+				helpers.OptionalTimePointerParam(&startDate, "start_date"),
+				helpers.OptionalTimePointerParam(&endDate, "end_date"),
+			)
+			if err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
+			}
+
+			budgets, err := twapi.Execute[projectBudgetListRequest, *projectBudgetListResponse](ctx, engine,
+				projectBudgetListRequest{ProjectID: projectID},
+			)
+			if err != nil {
+				return helpers.HandleAPIError(err, "failed to get project time budget")
+			}
+			var budget *projectBudget
+			if len(budgets.Budgets) > 0 {
+				budget = &budgets.Budgets[0]
+				startDate = cmp.Or(startDate, budget.StartDateTime)
+				endDate = cmp.Or(endDate, budget.EndDateTime)
+			}
+
+			entries, truncated, err := helpers.FetchAllPages(ctx, helpers.AutoPagination{
+				PageSize: 100,
+				MaxItems: timeBudgetMaxTimelogs,
+			}, func(ctx context.Context, page, pageSize int64) ([]timeBudgetEntry, bool, error) {
+				timelogListRequest := projects.NewTimelogListRequest()
+				timelogListRequest.Path.ProjectID = projectID
+				timelogListRequest.Filters.StartDate = startDate
+				timelogListRequest.Filters.EndDate = endDate
+				timelogListRequest.Filters.Page = page
+				timelogListRequest.Filters.PageSize = pageSize
+
+				response, err := executeWithQuery[*timeBudgetTimelogListResponse](ctx, engine, timelogListRequest,
+					url.Values{"include": []string{"tasks,tasks.tasklists,users"}},
+				)
+				if err != nil {
+					return nil, false, err
+				}
+				return timeBudgetEntries(response), response.Meta.Page.HasMore, nil
+			})
+			if err != nil {
+				return helpers.HandleAPIError(err, "failed to list project timelogs")
+			}
+
+			summary := summarizeTimeBudget(projectID, budget, entries)
+			summary.Truncated = truncated
+			encoded, err := json.Marshal(summary)
+			if err != nil {
+				return nil, err
+			}
+			return helpers.NewToolResultText("%s", encoded), nil
+		},
+	}
+}
+
+// timeBudgetEntries resolves the tasklist and user of each timelog from the
+// included entities.
+func timeBudgetEntries(response *timeBudgetTimelogListResponse) []timeBudgetEntry {
+	entries := make([]timeBudgetEntry, 0, len(response.Timelogs))
+	for _, timelog := range response.Timelogs {
+		entry := timeBudgetEntry{
+			minutes: timelog.Minutes,
+			userID:  timelog.User.ID,
+		}
+		if user, ok := response.Included.Users[strconv.FormatInt(timelog.User.ID, 10)]; ok {
+			entry.userName = strings.TrimSpace(user.FirstName + " " + user.LastName)
+		}
+		if timelog.Task != nil {
+			if task, ok := response.Included.Tasks[strconv.FormatInt(timelog.Task.ID, 10)]; ok {
+				entry.tasklistID = task.Tasklist.ID
+				entry.tasklistName = response.Included.Tasklists[strconv.FormatInt(task.Tasklist.ID, 10)].Name
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
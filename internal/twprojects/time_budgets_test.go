@@ -0,0 +1,72 @@
+package twprojects_test
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/testutil"
+	"github.com/teamwork/mcp/internal/twprojects"
+)
+
+func TestProjectTimeBudgetGet(t *testing.T) {
+	var timelogQuery string
+	mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(r *http.Request) (int, []byte) {
+		switch r.URL.Path {
+		case "/projects/api/v3/projects/budgets.json":
+			return http.StatusOK, []byte(`{"budgets":[{"id":7,"type":"TIME","capacity":1000,"capacityUsed":300,` +
+				`"startDateTime":"2024-01-01T00:00:00Z","endDateTime":"2024-01-31T23:59:59Z"}]}`)
+		case "/projects/api/v3/projects/123/time.json":
+			timelogQuery = r.URL.RawQuery
+			return http.StatusOK, []byte(`{"timelogs":[` +
+				`{"id":1,"minutes":120,"user":{"id":1},"task":{"id":10}},` +
+				`{"id":2,"minutes":60,"user":{"id":2},"task":{"id":10}},` +
+				`{"id":3,"minutes":100,"user":{"id":2},"task":{"id":20}},` +
+				`{"id":4,"minutes":20,"user":{"id":1}}],` +
+				`"included":{"tasks":{"10":{"tasklist":{"id":5}},"20":{"tasklist":{"id":6}}},` +
+				`"tasklists":{"5":{"name":"Design"},"6":{"name":"Build"}},` +
+				`"users":{"1":{"firstName":"Ann","lastName":"Lee"},"2":{"firstName":"Bob","lastName":"Ray"}}},` +
+				`"meta":{"page":{"hasMore":false}}}`)
+		}
+		t.Errorf("unexpected request %s", r.URL.Path)
+		return http.StatusNotFound, nil
+	})
+
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodProjectTimeBudgetGet.String(), map[string]any{
+		"project_id": float64(123),
+	}, testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+		toolResult, ok := result.(*mcp.CallToolResult)
+		if !ok {
+			t.Fatalf("unexpected result type: %T", result)
+		}
+		if toolResult.IsError {
+			t.Fatalf("tool failed to execute: %v", toolResult.Content)
+		}
+		text := toolResult.Content[0].(*mcp.TextContent).Text
+		expected := `{"projectId":123,"budget":{"id":7,"capacityMinutes":1000,"usedMinutes":300,` +
+			`"remainingMinutes":700,"startDate":"2024-01-01T00:00:00Z","endDate":"2024-01-31T23:59:59Z"},` +
+			`"loggedMinutes":300,"tasklists":[` +
+			`{"id":5,"name":"Design","minutes":180,"budgetPercentage":18,"users":[` +
+			`{"id":1,"name":"Ann Lee","minutes":120,"budgetPercentage":12},` +
+			`{"id":2,"name":"Bob Ray","minutes":60,"budgetPercentage":6}]},` +
+			`{"id":6,"name":"Build","minutes":100,"budgetPercentage":10,"users":[` +
+			`{"id":2,"name":"Bob Ray","minutes":100,"budgetPercentage":10}]},` +
+			`{"id":0,"minutes":20,"budgetPercentage":2,"users":[` +
+			`{"id":1,"name":"Ann Lee","minutes":20,"budgetPercentage":2}]}],` +
+			`"users":[{"id":2,"name":"Bob Ray","minutes":160,"budgetPercentage":16},` +
+			`{"id":1,"name":"Ann Lee","minutes":140,"budgetPercentage":14}]}`
+		if text != expected {
+			t.Errorf("expected %s, got %s", expected, text)
+		}
+	}))
+
+	// the timelogs are limited to the budget period
+	query, err := url.ParseQuery(timelogQuery)
+	if err != nil {
+		t.Fatalf("failed to parse query: %v", err)
+	}
+	if query.Get("startDate") != "2024-01-01T00:00:00Z" || query.Get("endDate") != "2024-01-31T23:59:59Z" {
+		t.Errorf("unexpected timelog query %q", timelogQuery)
+	}
+}
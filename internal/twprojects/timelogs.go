@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/google/jsonschema-go/jsonschema"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -176,6 +177,7 @@ func TimelogUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 			InputSchema: &jsonschema.Schema{
 				Type: "object",
 				Properties: map[string]*jsonschema.Schema{
+					helpers.ExpectedUpdatedAtParam: helpers.ExpectedUpdatedAtSchema("timelog"),
 					"id": {
 						Type:        "integer",
 						Description: "The ID of the timelog to update.",
@@ -239,6 +241,7 @@ func TimelogUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 		},
 		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			var timelogUpdateRequest projects.TimelogUpdateRequest
+			var expectedUpdatedAt *time.Time
 
 			var arguments map[string]any
 			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
@@ -246,6 +249,7 @@ func TimelogUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 			}
 			err := helpers.ParamGroup(arguments,
 				helpers.RequiredNumericParam(&timelogUpdateRequest.Path.ID, "id"),
+				helpers.OptionalTimePointerParam(&expectedUpdatedAt, helpers.ExpectedUpdatedAtParam),
 				helpers.OptionalPointerParam(&timelogUpdateRequest.Description, "description"),
 				helpers.OptionalDatePointerParam(&timelogUpdateRequest.Date, "date"),
 				helpers.OptionalTimeOnlyPointerParam(&timelogUpdateRequest.Time, "time"),
@@ -260,6 +264,18 @@ func TimelogUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
 			}
 
+			if expectedUpdatedAt != nil {
+				timelogResponse, err := projects.TimelogGet(ctx, engine, projects.NewTimelogGetRequest(timelogUpdateRequest.Path.ID))
+				if err != nil {
+					return helpers.HandleAPIError(err, "failed to get timelog")
+				}
+				conflict := helpers.CheckConflict("timelog", timelogUpdateRequest.Path.ID, *expectedUpdatedAt,
+					timelogResponse.Timelog.UpdatedAt)
+				if conflict != nil {
+					return conflict, nil
+				}
+			}
+
 			_, err = projects.TimelogUpdate(ctx, engine, timelogUpdateRequest)
 			if err != nil {
 				return helpers.HandleAPIError(err, "failed to update timelog")
@@ -348,6 +364,10 @@ func TimelogGet(engine *twapi.Engine) toolsets.ToolWrapper {
 				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
 			}
 
+			if passthroughEnabled(ctx) {
+				return passthrough(ctx, engine, timelogGetRequest, nil, "failed to get timelog")
+			}
+
 			timelog, err := projects.TimelogGet(ctx, engine, timelogGetRequest)
 			if err != nil {
 				return helpers.HandleAPIError(err, "failed to get timelog")
@@ -421,12 +441,17 @@ func TimelogList(engine *twapi.Engine) toolsets.ToolWrapper {
 						Type:        "integer",
 						Description: "Number of results per page for pagination.",
 					},
+					"count_only": countOnlySchema(),
+					"order_by":   timelogListOrder.schema("timelogs"),
+					"order_mode": orderModeSchema(),
 				},
 			},
 			OutputSchema: timelogListOutputSchema,
 		},
 		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			var timelogListRequest projects.TimelogListRequest
+			var countOnly bool
+			var orderBy, orderMode string
 
 			var arguments map[string]any
 			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
@@ -442,12 +467,26 @@ func TimelogList(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalNumericListParam(&timelogListRequest.Filters.AssignedToTeamIDs, "assigned_team_ids"),
 				helpers.OptionalNumericParam(&timelogListRequest.Filters.Page, "page"),
 				helpers.OptionalNumericParam(&timelogListRequest.Filters.PageSize, "page_size"),
+				helpers.OptionalParam(&countOnly, "count_only"),
+				helpers.OptionalParam(&orderBy, "order_by", helpers.RestrictValues(timelogListOrder.fields()...)),
+				helpers.OptionalParam(&orderMode, "order_mode", helpers.RestrictValues(orderModes...)),
 			)
 			if err != nil {
 				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
 			}
 
-			timelogList, err := projects.TimelogList(ctx, engine, timelogListRequest)
+			if countOnly {
+				return listCount(ctx, engine, timelogListRequest)
+			}
+
+			if passthroughEnabled(ctx) {
+				return passthrough(ctx, engine, timelogListRequest, timelogListOrder.query(orderBy, orderMode),
+					"failed to list timelogs")
+			}
+
+			timelogList, err := executeWithQuery[*projects.TimelogListResponse](ctx, engine, timelogListRequest,
+				timelogListOrder.query(orderBy, orderMode),
+			)
 			if err != nil {
 				return helpers.HandleAPIError(err, "failed to list timelogs")
 			}
@@ -524,6 +563,9 @@ func TimelogListByProject(engine *twapi.Engine) toolsets.ToolWrapper {
 						Type:        "integer",
 						Description: "Number of results per page for pagination.",
 					},
+					"count_only": countOnlySchema(),
+					"order_by":   timelogListOrder.schema("timelogs"),
+					"order_mode": orderModeSchema(),
 				},
 				Required: []string{"project_id"},
 			},
@@ -531,6 +573,8 @@ func TimelogListByProject(engine *twapi.Engine) toolsets.ToolWrapper {
 		},
 		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			var timelogListRequest projects.TimelogListRequest
+			var countOnly bool
+			var orderBy, orderMode string
 
 			var arguments map[string]any
 			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
@@ -547,12 +591,26 @@ func TimelogListByProject(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalNumericListParam(&timelogListRequest.Filters.AssignedToTeamIDs, "assigned_team_ids"),
 				helpers.OptionalNumericParam(&timelogListRequest.Filters.Page, "page"),
 				helpers.OptionalNumericParam(&timelogListRequest.Filters.PageSize, "page_size"),
+				helpers.OptionalParam(&countOnly, "count_only"),
+				helpers.OptionalParam(&orderBy, "order_by", helpers.RestrictValues(timelogListOrder.fields()...)),
+				helpers.OptionalParam(&orderMode, "order_mode", helpers.RestrictValues(orderModes...)),
 			)
 			if err != nil {
 				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
 			}
 
-			timelogList, err := projects.TimelogList(ctx, engine, timelogListRequest)
+			if countOnly {
+				return listCount(ctx, engine, timelogListRequest)
+			}
+
+			if passthroughEnabled(ctx) {
+				return passthrough(ctx, engine, timelogListRequest, timelogListOrder.query(orderBy, orderMode),
+					"failed to list timelogs")
+			}
+
+			timelogList, err := executeWithQuery[*projects.TimelogListResponse](ctx, engine, timelogListRequest,
+				timelogListOrder.query(orderBy, orderMode),
+			)
 			if err != nil {
 				return helpers.HandleAPIError(err, "failed to list timelogs")
 			}
@@ -629,6 +687,9 @@ func TimelogListByTask(engine *twapi.Engine) toolsets.ToolWrapper {
 						Type:        "integer",
 						Description: "Number of results per page for pagination.",
 					},
+					"count_only": countOnlySchema(),
+					"order_by":   timelogListOrder.schema("timelogs"),
+					"order_mode": orderModeSchema(),
 				},
 				Required: []string{"task_id"},
 			},
@@ -636,6 +697,8 @@ func TimelogListByTask(engine *twapi.Engine) toolsets.ToolWrapper {
 		},
 		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			var timelogListRequest projects.TimelogListRequest
+			var countOnly bool
+			var orderBy, orderMode string
 
 			var arguments map[string]any
 			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
@@ -652,12 +715,26 @@ func TimelogListByTask(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalNumericListParam(&timelogListRequest.Filters.AssignedToTeamIDs, "assigned_team_ids"),
 				helpers.OptionalNumericParam(&timelogListRequest.Filters.Page, "page"),
 				helpers.OptionalNumericParam(&timelogListRequest.Filters.PageSize, "page_size"),
+				helpers.OptionalParam(&countOnly, "count_only"),
+				helpers.OptionalParam(&orderBy, "order_by", helpers.RestrictValues(timelogListOrder.fields()...)),
+				helpers.OptionalParam(&orderMode, "order_mode", helpers.RestrictValues(orderModes...)),
 			)
 			if err != nil {
 				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
 			}
 
-			timelogList, err := projects.TimelogList(ctx, engine, timelogListRequest)
+			if countOnly {
+				return listCount(ctx, engine, timelogListRequest)
+			}
+
+			if passthroughEnabled(ctx) {
+				return passthrough(ctx, engine, timelogListRequest, timelogListOrder.query(orderBy, orderMode),
+					"failed to list timelogs")
+			}
+
+			timelogList, err := executeWithQuery[*projects.TimelogListResponse](ctx, engine, timelogListRequest,
+				timelogListOrder.query(orderBy, orderMode),
+			)
 			if err != nil {
 				return helpers.HandleAPIError(err, "failed to list timelogs")
 			}
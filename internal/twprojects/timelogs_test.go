@@ -69,6 +69,8 @@ func TestTimelogList(t *testing.T) {
 		"assigned_team_ids":    []float64{7, 8, 9},
 		"page":                 float64(1),
 		"page_size":            float64(10),
+		"order_by":             "date",
+		"order_mode":           "desc",
 	})
 }
 
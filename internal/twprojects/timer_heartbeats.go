@@ -0,0 +1,176 @@
+package twprojects
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/helpers"
+	"github.com/teamwork/mcp/internal/toolsets"
+	"github.com/teamwork/twapi-go-sdk"
+	"github.com/teamwork/twapi-go-sdk/projects"
+)
+
+// List of methods available in the Teamwork.com MCP service.
+//
+// The naming convention for methods follows a pattern described here:
+// https://github.com/github/github-mcp-server/issues/333
+const (
+	MethodTimerHeartbeat toolsets.Method = "twprojects-timer_heartbeat"
+)
+
+// timerIdleThreshold is the default time without heartbeats after which the
+// user is considered idle, the same as in the Teamwork.com desktop app.
+const timerIdleThreshold = 10 * time.Minute
+
+func init() {
+	// register the toolset methods
+	toolsets.RegisterMethod(MethodTimerHeartbeat)
+}
+
+// timerHeartbeat is the result of a heartbeat of a running timer.
+type timerHeartbeat struct {
+	TimerID     int64     `json:"timerId"`
+	Running     bool      `json:"running"`
+	Duration    int64     `json:"duration"`
+	LastBeatAt  time.Time `json:"lastHeartbeatAt,omitzero"`
+	Idle        bool      `json:"idle"`
+	IdleMinutes int64     `json:"idleMinutes"`
+}
+
+// timerHeartbeats remembers the last heartbeat of the running timers in each
+// session, so the gaps between heartbeats can be reported as idle time.
+type timerHeartbeats struct {
+	sessions *sessionStore[map[int64]time.Time]
+}
+
+func newTimerHeartbeats() *timerHeartbeats {
+	return &timerHeartbeats{
+		sessions: newSessionStore[map[int64]time.Time](),
+	}
+}
+
+// beat records a heartbeat of the timer at the given time, returning the
+// previous one.
+func (t *timerHeartbeats) beat(key string, timerID int64, at time.Time) (time.Time, bool) {
+	var last time.Time
+	var ok bool
+	t.sessions.update(key, func(beats *map[int64]time.Time) {
+		if *beats == nil {
+			*beats = make(map[int64]time.Time)
+		}
+		last, ok = (*beats)[timerID]
+		(*beats)[timerID] = at
+	})
+	return last, ok
+}
+
+// forget removes the heartbeats of the timer, when it's no longer running.
+func (t *timerHeartbeats) forget(key string, timerID int64) {
+	t.sessions.update(key, func(beats *map[int64]time.Time) {
+		delete(*beats, timerID)
+	})
+}
+
+// TimerHeartbeat records that the user is still active while a timer runs,
+// reporting the idle time since the previous heartbeat.
+func TimerHeartbeat(engine *twapi.Engine, heartbeats *timerHeartbeats) toolsets.ToolWrapper {
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name: string(MethodTimerHeartbeat),
+			Description: "Send a heartbeat for a running timer in Teamwork.com, reporting that the user is still " +
+				"active. Time-tracking clients should send heartbeats periodically (e.g. every minute) while the user " +
+				"is active. When the time since the previous heartbeat exceeds the idle threshold, the user is " +
+				"reported as idle with the idle minutes, which can be discarded with the discard_idle_minutes " +
+				"parameter when completing the timer. " + timerDescription,
+			Annotations: &mcp.ToolAnnotations{
+				Title: "Timer Heartbeat",
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"id": {
+						Type:        "integer",
+						Description: "The ID of the running timer.",
+					},
+					"idle_threshold_minutes": {
+						Type: "integer",
+						Description: fmt.Sprintf("The number of minutes without heartbeats after which the user is "+
+							"considered idle. Defaults to %d.", int64(timerIdleThreshold.Minutes())),
+						Minimum: twapi.Ptr(float64(1)),
+					},
+				},
+				Required: []string{"id"},
+			},
+		},
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var timerGetRequest projects.TimerGetRequest
+			idleThresholdMinutes := int64(timerIdleThreshold.Minutes())
+
+			var arguments map[string]any
+			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("failed to decode request: %s", err.Error())), nil
+			}
+			err := helpers.ParamGroup(arguments,
+				helpers.RequiredNumericParam(&timerGetRequest.Path.ID, "id"),
+				helpers.OptionalNumericParam(&idleThresholdMinutes, "idle_threshold_minutes"),
+			)
+			if err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
+			}
+			if idleThresholdMinutes < 1 {
+				return helpers.NewToolResultTextError("invalid parameters: idle_threshold_minutes must be at least 1"), nil
+			}
+
+			key, ok := sessionKey(ctx, request)
+			if !ok {
+				return helpers.NewToolResultTextError("timer heartbeats require a session"), nil
+			}
+
+			timerResponse, err := projects.TimerGet(ctx, engine, timerGetRequest)
+			if err != nil {
+				return helpers.HandleAPIError(err, "failed to get timer")
+			}
+			timer := timerResponse.Timer
+
+			heartbeat := timerHeartbeat{
+				TimerID:  timer.ID,
+				Running:  timer.Running,
+				Duration: timer.Duration,
+			}
+			if !timer.Running {
+				heartbeats.forget(key, timer.ID)
+			} else {
+				now := time.Now()
+				last, ok := heartbeats.beat(key, timer.ID, now)
+				// time while the timer was paused is not idle time
+				if ok && last.Before(timer.LastStartedAt) {
+					last = timer.LastStartedAt
+				}
+				if ok {
+					heartbeat.LastBeatAt = last
+					if gap := now.Sub(last); gap > time.Duration(idleThresholdMinutes)*time.Minute {
+						heartbeat.Idle = true
+						heartbeat.IdleMinutes = int64(gap.Minutes())
+					}
+				}
+			}
+
+			encoded, err := json.Marshal(heartbeat)
+			if err != nil {
+				return nil, err
+			}
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					&mcp.TextContent{
+						Text: string(encoded),
+					},
+				},
+				StructuredContent: heartbeat,
+			}, nil
+		},
+	}
+}
@@ -307,12 +307,19 @@ func TimerComplete(engine *twapi.Engine) toolsets.ToolWrapper {
 						Type:        "integer",
 						Description: "The ID of the timer to complete.",
 					},
+					"discard_idle_minutes": {
+						Type: "integer",
+						Description: "The number of idle minutes to discard from the time logged by the timer, as " +
+							"reported by the timer heartbeats.",
+						Minimum: twapi.Ptr(float64(0)),
+					},
 				},
 				Required: []string{"id"},
 			},
 		},
 		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			var timerCompleteRequest projects.TimerCompleteRequest
+			var discardIdleMinutes int64
 
 			var arguments map[string]any
 			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
@@ -320,16 +327,38 @@ func TimerComplete(engine *twapi.Engine) toolsets.ToolWrapper {
 			}
 			err := helpers.ParamGroup(arguments,
 				helpers.RequiredNumericParam(&timerCompleteRequest.Path.ID, "id"),
+				helpers.OptionalNumericParam(&discardIdleMinutes, "discard_idle_minutes"),
 			)
 			if err != nil {
 				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
 			}
+			if discardIdleMinutes < 0 {
+				return helpers.NewToolResultTextError("invalid parameters: discard_idle_minutes can't be negative"), nil
+			}
 
-			_, err = projects.TimerComplete(ctx, engine, timerCompleteRequest)
+			timerResponse, err := projects.TimerComplete(ctx, engine, timerCompleteRequest)
 			if err != nil {
 				return helpers.HandleAPIError(err, "failed to complete timer")
 			}
-			return helpers.NewToolResultText("Timer completed successfully"), nil
+			if discardIdleMinutes == 0 || timerResponse.Timer.Timelog == nil {
+				return helpers.NewToolResultText("Timer completed successfully"), nil
+			}
+
+			// the idle time is discarded from the timelog created by the timer
+			timelogID := timerResponse.Timer.Timelog.ID
+			timelogResponse, err := projects.TimelogGet(ctx, engine, projects.NewTimelogGetRequest(timelogID))
+			if err != nil {
+				return helpers.HandleAPIError(err, "timer completed, but failed to get its timelog to discard idle time")
+			}
+			minutes := max(timelogResponse.Timelog.Minutes-discardIdleMinutes, 0)
+			timelogUpdateRequest := projects.NewTimelogUpdateRequest(timelogID)
+			timelogUpdateRequest.Hours = twapi.Ptr(int64(0))
+			timelogUpdateRequest.Minutes = &minutes
+			if _, err := projects.TimelogUpdate(ctx, engine, timelogUpdateRequest); err != nil {
+				return helpers.HandleAPIError(err, "timer completed, but failed to discard idle time from its timelog")
+			}
+			return helpers.NewToolResultText("Timer completed successfully, discarding %d idle minutes from timelog %d",
+				timelogResponse.Timelog.Minutes-minutes, timelogID), nil
 		},
 	}
 }
@@ -422,12 +451,14 @@ func TimerGet(engine *twapi.Engine) toolsets.ToolWrapper {
 			if err != nil {
 				return nil, err
 			}
+			linked := helpers.WebLinker(ctx, encoded,
+				helpers.WebLinkerWithIDPathBuilder("/app/timers"),
+			)
 			return &mcp.CallToolResult{
+				Meta: helpers.WebLinkedEntitiesMeta(linked),
 				Content: []mcp.Content{
 					&mcp.TextContent{
-						Text: string(helpers.WebLinker(ctx, encoded,
-							helpers.WebLinkerWithIDPathBuilder("/app/timers"),
-						)),
+						Text: string(linked),
 					},
 				},
 				StructuredContent: timer,
@@ -477,12 +508,14 @@ func TimerList(engine *twapi.Engine) toolsets.ToolWrapper {
 						Type:        "integer",
 						Description: "Number of results per page for pagination.",
 					},
+					"count_only": countOnlySchema(),
 				},
 			},
 			OutputSchema: timerListOutputSchema,
 		},
 		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			var timerListRequest projects.TimerListRequest
+			var countOnly bool
 
 			var arguments map[string]any
 			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
@@ -495,11 +528,16 @@ func TimerList(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalParam(&timerListRequest.Filters.RunningTimersOnly, "running_timers_only"),
 				helpers.OptionalNumericParam(&timerListRequest.Filters.Page, "page"),
 				helpers.OptionalNumericParam(&timerListRequest.Filters.PageSize, "page_size"),
+				helpers.OptionalParam(&countOnly, "count_only"),
 			)
 			if err != nil {
 				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
 			}
 
+			if countOnly {
+				return listCount(ctx, engine, timerListRequest)
+			}
+
 			timerList, err := projects.TimerList(ctx, engine, timerListRequest)
 			if err != nil {
 				return helpers.HandleAPIError(err, "failed to list timers")
@@ -509,12 +547,14 @@ func TimerList(engine *twapi.Engine) toolsets.ToolWrapper {
 			if err != nil {
 				return nil, err
 			}
+			linked := helpers.WebLinker(ctx, encoded,
+				helpers.WebLinkerWithIDPathBuilder("/app/timers"),
+			)
 			return &mcp.CallToolResult{
+				Meta: helpers.WebLinkedEntitiesMeta(linked),
 				Content: []mcp.Content{
 					&mcp.TextContent{
-						Text: string(helpers.WebLinker(ctx, encoded,
-							helpers.WebLinkerWithIDPathBuilder("/app/timers"),
-						)),
+						Text: string(linked),
 					},
 				},
 				StructuredContent: timerList,
@@ -1,9 +1,12 @@
 package twprojects_test
 
 import (
+	"io"
 	"net/http"
+	"strings"
 	"testing"
 
+	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/teamwork/mcp/internal/testutil"
 	"github.com/teamwork/mcp/internal/twprojects"
 )
@@ -79,3 +82,48 @@ func TestTimerList(t *testing.T) {
 		"page_size":           float64(10),
 	})
 }
+
+func TestTimerCompleteDiscardIdleMinutes(t *testing.T) {
+	mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(r *http.Request) (int, []byte) {
+		switch r.Method + " " + r.URL.Path {
+		case "PUT /projects/api/v3/me/timers/123/complete.json":
+			return http.StatusOK, []byte(`{"timer":{"id":123,"timelog":{"id":456,"type":"timelogs"}}}`)
+		case "GET /projects/api/v3/time/456.json":
+			return http.StatusOK, []byte(`{"timelog":{"id":456,"minutes":90}}`)
+		case "PATCH /projects/api/v3/time/456.json":
+			body, err := io.ReadAll(r.Body)
+			if err != nil || !strings.Contains(string(body), `"minutes":60`) {
+				t.Errorf("unexpected timelog update %s", body)
+			}
+			return http.StatusOK, []byte(`{"timelog":{"id":456}}`)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			return http.StatusNotFound, nil
+		}
+	})
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTimerComplete.String(), map[string]any{
+		"id":                   float64(123),
+		"discard_idle_minutes": float64(30),
+	})
+}
+
+func TestTimerHeartbeat(t *testing.T) {
+	runSessions(t, func(t *testing.T, withSession testutil.ExecuteToolRequestOption) {
+		mcpServer := mcpServerMock(t, http.StatusOK, []byte(`{"timer":{"id":123,"running":true,"duration":60}}`))
+		for range 2 {
+			testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTimerHeartbeat.String(), map[string]any{
+				"id":                     float64(123),
+				"idle_threshold_minutes": float64(5),
+			}, withSession, testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+				toolResult, ok := result.(*mcp.CallToolResult)
+				if !ok || toolResult.IsError || len(toolResult.Content) == 0 {
+					t.Fatalf("unexpected result: %v", result)
+				}
+				text, ok := toolResult.Content[0].(*mcp.TextContent)
+				if !ok || !strings.Contains(text.Text, `"idle":false`) {
+					t.Errorf("unexpected heartbeat: %v", toolResult.Content[0])
+				}
+			}))
+		}
+	})
+}
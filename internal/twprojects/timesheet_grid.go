@@ -0,0 +1,451 @@
+package twprojects
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/helpers"
+	"github.com/teamwork/mcp/internal/toolsets"
+	twapi "github.com/teamwork/twapi-go-sdk"
+	"github.com/teamwork/twapi-go-sdk/projects"
+)
+
+// List of methods available in the Teamwork.com MCP service.
+//
+// The naming convention for methods follows a pattern described here:
+// https://github.com/github/github-mcp-server/issues/333
+const (
+	MethodTimesheetGridGet  toolsets.Method = "twprojects-get_timesheet_grid"
+	MethodTimesheetGridFill toolsets.Method = "twprojects-fill_timesheet_grid"
+)
+
+const timesheetGridDescription = "The timesheet grid is the weekly view of the time logged by a user, with a row " +
+	"for each task or project and a column for each day from Monday to Sunday, as in the \"My Timesheet\" page of " +
+	"Teamwork.com."
+
+const (
+	// timesheetGridMaxTimelogs is the maximum number of timelogs loaded to build
+	// the grid of a week.
+	timesheetGridMaxTimelogs = 1000
+	// timesheetGridMaxCells is the maximum number of cells filled in a single
+	// call.
+	timesheetGridMaxCells = 100
+)
+
+// timesheetGridDefaultTime is the time of the day of the timelogs created
+// without one.
+var timesheetGridDefaultTime = twapi.Time(time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC))
+
+func init() {
+	// register the toolset methods
+	toolsets.RegisterMethod(MethodTimesheetGridGet)
+	toolsets.RegisterMethod(MethodTimesheetGridFill)
+}
+
+// timesheetGrid is the time logged by a user in a week, by task or project and
+// day.
+type timesheetGrid struct {
+	UserID      int64              `json:"userId"`
+	WeekStart   string             `json:"weekStart"`
+	Days        []string           `json:"days"`
+	Rows        []timesheetGridRow `json:"rows"`
+	DailyTotals []int64            `json:"dailyMinutes"`
+	Total       int64              `json:"totalMinutes"`
+	Truncated   bool               `json:"truncated,omitempty"`
+}
+
+// timesheetGridRow is the time logged in a task, or in a project without a
+// task, on each day of the week.
+type timesheetGridRow struct {
+	ProjectID   int64   `json:"projectId"`
+	ProjectName string  `json:"projectName,omitempty"`
+	TaskID      int64   `json:"taskId,omitempty"`
+	TaskName    string  `json:"taskName,omitempty"`
+	Minutes     []int64 `json:"minutes"`
+	Total       int64   `json:"totalMinutes"`
+}
+
+// timesheetGridTimelogListResponse contains the timelogs of the week, with the
+// names of their tasks and projects.
+type timesheetGridTimelogListResponse struct {
+	Meta struct {
+		Page struct {
+			HasMore bool `json:"hasMore"`
+		} `json:"page"`
+	} `json:"meta"`
+	Timelogs []projects.Timelog `json:"timelogs"`
+	Included struct {
+		Tasks map[string]struct {
+			Name string `json:"name"`
+		} `json:"tasks"`
+		Projects map[string]struct {
+			Name string `json:"name"`
+		} `json:"projects"`
+	} `json:"included"`
+}
+
+// HandleHTTPResponse handles the HTTP response for the
+// timesheetGridTimelogListResponse. If some unexpected HTTP status code is
+// returned by the API, a twapi.HTTPError is returned.
+func (t *timesheetGridTimelogListResponse) HandleHTTPResponse(resp *http.Response) error {
+	if resp.StatusCode != http.StatusOK {
+		return twapi.NewHTTPError(resp, "failed to list timelogs")
+	}
+	if err := json.NewDecoder(resp.Body).Decode(t); err != nil {
+		return fmt.Errorf("failed to decode list timelogs response: %w", err)
+	}
+	return nil
+}
+
+// timesheetGridEntry is a timelog with the names of its task and project.
+type timesheetGridEntry struct {
+	timelog     projects.Timelog
+	taskName    string
+	projectName string
+}
+
+// TimesheetGridGet retrieves the weekly timesheet grid of a user in
+// Teamwork.com.
+func TimesheetGridGet(engine *twapi.Engine) toolsets.ToolWrapper {
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name: string(MethodTimesheetGridGet),
+			Description: "Get the weekly timesheet grid of a user in Teamwork.com, with the minutes logged in each " +
+				"task or project per day and the daily and weekly totals. " + timesheetGridDescription,
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "Get Timesheet Grid",
+				ReadOnlyHint: true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"user_id": {
+						Type:        "integer",
+						Description: "The ID of the user. Defaults to the authenticated user.",
+					},
+					"week_start": {
+						Type:   "string",
+						Format: "date",
+						Description: "Any day of the week of the grid, in the format YYYY-MM-DD. The week is identified by " +
+							"its Monday. Defaults to the current week.",
+					},
+					"project_ids": {
+						Type:        "array",
+						Description: "The IDs of the projects to include in the grid. Defaults to all projects.",
+						Items: &jsonschema.Schema{
+							Type: "integer",
+						},
+					},
+				},
+			},
+		},
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var userID int64
+			var week *twapi.Date
+			var projectIDs []int64
+
+			var arguments map[string]any
+			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("failed to decode request: %s", err.Error())), nil
+			}
+			err := helpers.ParamGroup(arguments,
+				helpers.OptionalNumericParam(&userID, "user_id"),
+				helpers.OptionalDatePointerParam(&week, "week_start"),
+				helpers.OptionalNumericListParam(&projectIDs, "project_ids"),
+			)
+			if err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
+			}
+
+			if userID == 0 {
+				response, err := projects.UserGetMe(ctx, engine, projects.NewUserGetMeRequest())
+				if err != nil {
+					return helpers.HandleAPIError(err, "failed to get the authenticated user")
+				}
+				userID = response.User.ID
+			}
+			start := time.Now().UTC()
+			if week != nil {
+				start = time.Time(*week)
+			}
+			start = weekStart(time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC))
+			end := start.AddDate(0, 0, 7).Add(-time.Second)
+
+			entries, truncated, err := helpers.FetchAllPages(ctx, helpers.AutoPagination{
+				PageSize: 100,
+				MaxItems: timesheetGridMaxTimelogs,
+			}, func(ctx context.Context, page, pageSize int64) ([]timesheetGridEntry, bool, error) {
+				timelogListRequest := projects.NewTimelogListRequest()
+				timelogListRequest.Filters.AssignedToUserIDs = []int64{userID}
+				timelogListRequest.Filters.StartDate = &start
+				timelogListRequest.Filters.EndDate = &end
+				timelogListRequest.Filters.Page = page
+				timelogListRequest.Filters.PageSize = pageSize
+
+				response, err := executeWithQuery[*timesheetGridTimelogListResponse](ctx, engine, timelogListRequest,
+					url.Values{"include": []string{"tasks,projects"}},
+				)
+				if err != nil {
+					return nil, false, err
+				}
+				entries := make([]timesheetGridEntry, 0, len(response.Timelogs))
+				for _, timelog := range response.Timelogs {
+					entry := timesheetGridEntry{
+						timelog:     timelog,
+						projectName: response.Included.Projects[strconv.FormatInt(timelog.Project.ID, 10)].Name,
+					}
+					if timelog.Task != nil {
+						entry.taskName = response.Included.Tasks[strconv.FormatInt(timelog.Task.ID, 10)].Name
+					}
+					entries = append(entries, entry)
+				}
+				return entries, response.Meta.Page.HasMore, nil
+			})
+			if err != nil {
+				return helpers.HandleAPIError(err, "failed to list the timelogs of the week")
+			}
+
+			grid := buildTimesheetGrid(userID, start, entries, projectIDs)
+			grid.Truncated = truncated
+			encoded, err := json.Marshal(grid)
+			if err != nil {
+				return nil, err
+			}
+			return helpers.NewToolResultText("%s", encoded), nil
+		},
+	}
+}
+
+// buildTimesheetGrid sums the minutes of the timelogs by task, or project when
+// logged without a task, and day of the week. The timelogs of other users, out
+// of the week or of projects not included are ignored.
+func buildTimesheetGrid(
+	userID int64,
+	start time.Time,
+	entries []timesheetGridEntry,
+	projectIDs []int64,
+) timesheetGrid {
+	grid := timesheetGrid{
+		UserID:      userID,
+		WeekStart:   start.Format(time.DateOnly),
+		Days:        make([]string, 7),
+		Rows:        []timesheetGridRow{},
+		DailyTotals: make([]int64, 7),
+	}
+	for day := range grid.Days {
+		grid.Days[day] = start.AddDate(0, 0, day).Format(time.DateOnly)
+	}
+
+	type rowKey struct{ projectID, taskID int64 }
+	rows := make(map[rowKey]*timesheetGridRow)
+	var order []rowKey
+	for _, entry := range entries {
+		timelog := entry.timelog
+		if timelog.User.ID != userID || (len(projectIDs) > 0 && !slices.Contains(projectIDs, timelog.Project.ID)) {
+			continue
+		}
+		day := slices.Index(grid.Days, timelog.LoggedAt.UTC().Format(time.DateOnly))
+		if day < 0 {
+			continue
+		}
+
+		key := rowKey{projectID: timelog.Project.ID}
+		if timelog.Task != nil {
+			key.taskID = timelog.Task.ID
+		}
+		row, ok := rows[key]
+		if !ok {
+			row = &timesheetGridRow{
+				ProjectID:   key.projectID,
+				ProjectName: entry.projectName,
+				TaskID:      key.taskID,
+				TaskName:    entry.taskName,
+				Minutes:     make([]int64, 7),
+			}
+			rows[key] = row
+			order = append(order, key)
+		}
+		row.Minutes[day] += timelog.Minutes
+		row.Total += timelog.Minutes
+		grid.DailyTotals[day] += timelog.Minutes
+		grid.Total += timelog.Minutes
+	}
+
+	for _, key := range order {
+		grid.Rows = append(grid.Rows, *rows[key])
+	}
+	slices.SortStableFunc(grid.Rows, func(a, b timesheetGridRow) int {
+		return cmp.Or(
+			cmp.Compare(a.ProjectName, b.ProjectName),
+			cmp.Compare(a.ProjectID, b.ProjectID),
+			cmp.Compare(a.TaskName, b.TaskName),
+			cmp.Compare(a.TaskID, b.TaskID),
+		)
+	})
+	return grid
+}
+
+// TimesheetGridFill fills cells of the weekly timesheet grid of a user in
+// Teamwork.com, creating a timelog for each cell.
+func TimesheetGridFill(engine *twapi.Engine) toolsets.ToolWrapper {
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name: string(MethodTimesheetGridFill),
+			Description: "Fill multiple cells of the weekly timesheet grid of a user in Teamwork.com in a single call, " +
+				"creating a timelog for each task or project and day. The existing timelogs are kept, so the minutes " +
+				"of a cell are added to the time already logged. When a timelog can't be created, the timelogs " +
+				"created before the failure are deleted. " + timesheetGridDescription,
+			Annotations: &mcp.ToolAnnotations{
+				Title: "Fill Timesheet Grid",
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"user_id": {
+						Type:        "integer",
+						Description: "The ID of the user logging the time. Defaults to the authenticated user.",
+					},
+					"cells": {
+						Type:        "array",
+						Description: fmt.Sprintf("The cells to fill, up to %d.", timesheetGridMaxCells),
+						Items: &jsonschema.Schema{
+							Type: "object",
+							Properties: map[string]*jsonschema.Schema{
+								"task_id": {
+									Type: "integer",
+									Description: "The ID of the task of the cell. Either task_id or project_id must be " +
+										"provided, but not both.",
+								},
+								"project_id": {
+									Type: "integer",
+									Description: "The ID of the project of the cell, for time not logged in a task. Either " +
+										"task_id or project_id must be provided, but not both.",
+								},
+								"date": {
+									Type:        "string",
+									Format:      "date",
+									Description: "The day of the cell in the format YYYY-MM-DD.",
+								},
+								"minutes": {
+									Type:        "integer",
+									Description: "The minutes to log, e.g. 90 for 1h30m. Must be a positive integer.",
+								},
+								"time": {
+									Type:    "string",
+									Pattern: `^(?:[01]\d|2[0-3]):[0-5]\d:[0-5]\d$`,
+									Description: "The time the work started in the format HH:MM:SS. Defaults to " +
+										"09:00:00.",
+								},
+								"description": {
+									Type:        "string",
+									Description: "A description of the work done.",
+								},
+								"billable": {
+									Type:        "boolean",
+									Description: "If true, the time is billable. Defaults to false.",
+								},
+								"tag_ids": {
+									Type:        "array",
+									Description: "A list of tag IDs to associate with the timelog.",
+									Items: &jsonschema.Schema{
+										Type: "integer",
+									},
+								},
+							},
+							Required: []string{"date", "minutes"},
+						},
+					},
+				},
+				Required: []string{"cells"},
+			},
+		},
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var arguments map[string]any
+			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("failed to decode request: %s", err.Error())), nil
+			}
+			timelogCreateRequests, err := parseTimesheetGridCells(arguments)
+			if err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
+			}
+
+			compensations := newCompensationLog(engine)
+			timelogIDs := make([]string, 0, len(timelogCreateRequests))
+			var total int64
+			for i, timelogCreateRequest := range timelogCreateRequests {
+				response, err := projects.TimelogCreate(ctx, engine, timelogCreateRequest)
+				if err != nil {
+					return compensations.fail(ctx, err, fmt.Sprintf("failed to fill cell %d", i+1))
+				}
+				compensations.recordTimelog(response.Timelog.ID)
+				timelogIDs = append(timelogIDs, strconv.FormatInt(response.Timelog.ID, 10))
+				total += timelogCreateRequest.Hours*60 + timelogCreateRequest.Minutes
+			}
+			return helpers.NewToolResultText("Timesheet filled successfully with %d minutes in %d timelogs with IDs %s",
+				total, len(timelogIDs), strings.Join(timelogIDs, ", ")), nil
+		},
+	}
+}
+
+// parseTimesheetGridCells validates the cells to fill before logging any time,
+// returning the timelogs to create.
+func parseTimesheetGridCells(arguments map[string]any) ([]projects.TimelogCreateRequest, error) {
+	var userID *int64
+	var cells []map[string]any
+	err := helpers.ParamGroup(arguments,
+		helpers.OptionalNumericPointerParam(&userID, "user_id"),
+		helpers.OptionalListParam(&cells, "cells"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case len(cells) == 0:
+		return nil, errors.New("cells is required")
+	case len(cells) > timesheetGridMaxCells:
+		return nil, fmt.Errorf("at most %d cells can be filled in a single call", timesheetGridMaxCells)
+	}
+
+	timelogCreateRequests := make([]projects.TimelogCreateRequest, 0, len(cells))
+	for i, cell := range cells {
+		timelogCreateRequest := projects.TimelogCreateRequest{
+			Time:   timesheetGridDefaultTime,
+			UserID: userID,
+		}
+		var minutes int64
+		err := helpers.ParamGroup(cell,
+			helpers.OptionalNumericParam(&timelogCreateRequest.Path.TaskID, "task_id"),
+			helpers.OptionalNumericParam(&timelogCreateRequest.Path.ProjectID, "project_id"),
+			helpers.RequiredDateParam(&timelogCreateRequest.Date, "date"),
+			helpers.RequiredNumericParam(&minutes, "minutes"),
+			helpers.OptionalTimeOnlyParam(&timelogCreateRequest.Time, "time"),
+			helpers.OptionalPointerParam(&timelogCreateRequest.Description, "description"),
+			helpers.OptionalParam(&timelogCreateRequest.Billable, "billable"),
+			helpers.OptionalNumericListParam(&timelogCreateRequest.TagIDs, "tag_ids"),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("cell %d: %w", i+1, err)
+		}
+		if (timelogCreateRequest.Path.TaskID == 0) == (timelogCreateRequest.Path.ProjectID == 0) {
+			return nil, fmt.Errorf("cell %d: either task_id or project_id must be provided", i+1)
+		}
+		if minutes <= 0 {
+			return nil, fmt.Errorf("cell %d: minutes must be a positive integer", i+1)
+		}
+		timelogCreateRequest.Hours = minutes / 60
+		timelogCreateRequest.Minutes = minutes % 60
+		timelogCreateRequests = append(timelogCreateRequests, timelogCreateRequest)
+	}
+	return timelogCreateRequests, nil
+}
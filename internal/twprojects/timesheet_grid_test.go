@@ -0,0 +1,155 @@
+package twprojects_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/testutil"
+	"github.com/teamwork/mcp/internal/twprojects"
+)
+
+func TestTimesheetGridGet(t *testing.T) {
+	mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(r *http.Request) (int, []byte) {
+		query := r.URL.Query()
+		if r.URL.Path != "/projects/api/v3/time.json" || query.Get("assignedToUserIds") != "7" ||
+			query.Get("startDate") != "2025-03-03T00:00:00Z" || query.Get("endDate") != "2025-03-09T23:59:59Z" {
+			t.Errorf("unexpected request %s %s", r.URL.Path, r.URL.RawQuery)
+		}
+		return http.StatusOK, []byte(`{"timelogs":[` +
+			`{"id":1,"minutes":60,"timeLogged":"2025-03-03T09:00:00Z","user":{"id":7},"task":{"id":10},"project":{"id":5}},` +
+			`{"id":2,"minutes":30,"timeLogged":"2025-03-05T09:00:00Z","user":{"id":7},"task":{"id":10},"project":{"id":5}},` +
+			`{"id":3,"minutes":45,"timeLogged":"2025-03-05T14:00:00Z","user":{"id":7},"project":{"id":5}},` +
+			`{"id":4,"minutes":15,"timeLogged":"2025-03-09T09:00:00Z","user":{"id":7},"task":{"id":11},"project":{"id":6}}],` +
+			`"included":{"tasks":{"10":{"name":"Design"},"11":{"name":"Review"}},` +
+			`"projects":{"5":{"name":"Website"},"6":{"name":"Audit"}}},` +
+			`"meta":{"page":{"hasMore":false}}}`)
+	})
+
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTimesheetGridGet.String(), map[string]any{
+		"user_id":    float64(7),
+		"week_start": "2025-03-06",
+	}, testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+		toolResult, ok := result.(*mcp.CallToolResult)
+		if !ok {
+			t.Fatalf("unexpected result type: %T", result)
+		}
+		if toolResult.IsError {
+			t.Fatalf("tool failed to execute: %v", toolResult.Content)
+		}
+		var grid struct {
+			WeekStart string `json:"weekStart"`
+			Rows      []struct {
+				ProjectID int64   `json:"projectId"`
+				TaskID    int64   `json:"taskId"`
+				Minutes   []int64 `json:"minutes"`
+			} `json:"rows"`
+			DailyMinutes []int64 `json:"dailyMinutes"`
+			TotalMinutes int64   `json:"totalMinutes"`
+		}
+		if err := json.Unmarshal([]byte(toolResult.Content[0].(*mcp.TextContent).Text), &grid); err != nil {
+			t.Fatalf("failed to decode result: %v", err)
+		}
+		if grid.WeekStart != "2025-03-03" || grid.TotalMinutes != 150 || len(grid.Rows) != 3 {
+			t.Fatalf("unexpected grid %+v", grid)
+		}
+		// rows are sorted by project name, Audit first
+		if grid.Rows[0].TaskID != 11 || grid.Rows[0].Minutes[6] != 15 {
+			t.Errorf("unexpected first row %+v", grid.Rows[0])
+		}
+		if grid.Rows[2].TaskID != 10 || grid.Rows[2].Minutes[0] != 60 || grid.Rows[2].Minutes[2] != 30 {
+			t.Errorf("unexpected last row %+v", grid.Rows[2])
+		}
+		if !slices.Equal(grid.DailyMinutes, []int64{60, 0, 75, 0, 0, 0, 15}) {
+			t.Errorf("unexpected daily minutes %v", grid.DailyMinutes)
+		}
+	}))
+}
+
+func TestTimesheetGridFill(t *testing.T) {
+	tests := []struct {
+		name      string
+		cells     []any
+		failAfter int
+		created   int
+		deleted   int
+		isError   bool
+	}{{
+		name: "all cells filled",
+		cells: []any{
+			map[string]any{"task_id": float64(10), "date": "2025-03-03", "minutes": float64(90)},
+			map[string]any{"project_id": float64(5), "date": "2025-03-04", "minutes": float64(30)},
+		},
+		failAfter: -1,
+		created:   2,
+	}, {
+		name: "failure reverts the filled cells",
+		cells: []any{
+			map[string]any{"task_id": float64(10), "date": "2025-03-03", "minutes": float64(90)},
+			map[string]any{"task_id": float64(11), "date": "2025-03-04", "minutes": float64(30)},
+		},
+		failAfter: 1,
+		created:   1,
+		deleted:   1,
+		isError:   true,
+	}, {
+		name: "cell without task or project",
+		cells: []any{
+			map[string]any{"date": "2025-03-03", "minutes": float64(90)},
+		},
+		failAfter: -1,
+		isError:   true,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created, deleted int
+			mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(r *http.Request) (int, []byte) {
+				switch r.Method {
+				case http.MethodPost:
+					if created == tt.failAfter {
+						return http.StatusInternalServerError, nil
+					}
+					var body struct {
+						Timelog struct {
+							Hours   int64 `json:"hours"`
+							Minutes int64 `json:"minutes"`
+						} `json:"timelog"`
+					}
+					if data, err := io.ReadAll(r.Body); err == nil {
+						_ = json.Unmarshal(data, &body)
+					}
+					if created == 0 && (body.Timelog.Hours != 1 || body.Timelog.Minutes != 30) {
+						t.Errorf("unexpected duration %+v", body.Timelog)
+					}
+					created++
+					return http.StatusCreated, []byte(fmt.Sprintf(`{"timelog":{"id":%d}}`, 100+created))
+				case http.MethodDelete:
+					deleted++
+					return http.StatusNoContent, nil
+				}
+				return http.StatusOK, []byte(`{}`)
+			})
+
+			testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTimesheetGridFill.String(), map[string]any{
+				"cells": tt.cells,
+			}, testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+				toolResult, ok := result.(*mcp.CallToolResult)
+				if !ok {
+					t.Fatalf("unexpected result type: %T", result)
+				}
+				if toolResult.IsError != tt.isError {
+					t.Errorf("unexpected error state %v: %v", toolResult.IsError, toolResult.Content)
+				}
+			}))
+			if created != tt.created || deleted != tt.deleted {
+				t.Errorf("expected %d created and %d deleted timelogs, got %d and %d",
+					tt.created, tt.deleted, created, deleted)
+			}
+		})
+	}
+}
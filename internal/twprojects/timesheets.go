@@ -0,0 +1,440 @@
+package twprojects
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/helpers"
+	"github.com/teamwork/mcp/internal/toolsets"
+	twapi "github.com/teamwork/twapi-go-sdk"
+)
+
+// List of methods available in the Teamwork.com MCP service.
+//
+// The naming convention for methods follows a pattern described here:
+// https://github.com/github/github-mcp-server/issues/333
+const (
+	MethodTimesheetLock         toolsets.Method = "twprojects-lock_timesheets"
+	MethodTimesheetUnlock       toolsets.Method = "twprojects-unlock_timesheets"
+	MethodTimesheetSubmit       toolsets.Method = "twprojects-submit_timesheet"
+	MethodTimesheetApprove      toolsets.Method = "twprojects-approve_timesheet"
+	MethodTimesheetReject       toolsets.Method = "twprojects-reject_timesheet"
+	MethodTimesheetApprovalList toolsets.Method = "twprojects-list_timesheet_approvals"
+)
+
+const timesheetDescription = "A timesheet is the set of timelogs of a user in a period. Locking the timesheets of " +
+	"a period prevents adding, changing or deleting its timelogs, freezing the period before generating invoices or " +
+	"reports. Timesheet locking is only available in some installations."
+
+const timesheetApprovalDescription = "In installations with timesheet approvals, users submit their weekly " +
+	"timesheet for approval, and managers approve or reject it. Rejected timesheets can be changed and submitted " +
+	"again."
+
+// timesheetApprovalStatuses are the statuses of a weekly timesheet in the
+// approval workflow.
+var timesheetApprovalStatuses = []string{"draft", "submitted", "approved", "rejected"}
+
+func init() {
+	// register the toolset methods
+	toolsets.RegisterMethod(MethodTimesheetLock)
+	toolsets.RegisterMethod(MethodTimesheetUnlock)
+	toolsets.RegisterMethod(MethodTimesheetSubmit)
+	toolsets.RegisterMethod(MethodTimesheetApprove)
+	toolsets.RegisterMethod(MethodTimesheetReject)
+	toolsets.RegisterMethod(MethodTimesheetApprovalList)
+}
+
+// TimesheetLock locks the timesheets of a date range in Teamwork.com.
+func TimesheetLock(engine *twapi.Engine) toolsets.ToolWrapper {
+	return timesheetLockTool(engine, true)
+}
+
+// TimesheetUnlock unlocks the timesheets of a date range in Teamwork.com.
+func TimesheetUnlock(engine *twapi.Engine) toolsets.ToolWrapper {
+	return timesheetLockTool(engine, false)
+}
+
+func timesheetLockTool(engine *twapi.Engine, lock bool) toolsets.ToolWrapper {
+	method, action, title := MethodTimesheetUnlock, "unlock", "Unlock Timesheets"
+	if lock {
+		method, action, title = MethodTimesheetLock, "lock", "Lock Timesheets"
+	}
+
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name: string(method),
+			Description: fmt.Sprintf("%s the timesheets of a date range in Teamwork.com. ", title) +
+				timesheetDescription,
+			Annotations: &mcp.ToolAnnotations{
+				Title: title,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"start_date": {
+						Type:        "string",
+						Format:      "date",
+						Description: fmt.Sprintf("The first day of the period to %s, in the format YYYY-MM-DD.", action),
+					},
+					"end_date": {
+						Type:        "string",
+						Format:      "date",
+						Description: fmt.Sprintf("The last day of the period to %s, in the format YYYY-MM-DD.", action),
+					},
+					"user_ids": {
+						Type: "array",
+						Description: fmt.Sprintf("The IDs of the users whose timesheets are %sed. Defaults to all "+
+							"users.", action),
+						Items: &jsonschema.Schema{
+							Type: "integer",
+						},
+					},
+				},
+				Required: []string{"start_date", "end_date"},
+			},
+		},
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			lockRequest := timesheetLockRequest{Lock: lock}
+
+			var arguments map[string]any
+			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("failed to decode request: %s", err.Error())), nil
+			}
+			err := helpers.ParamGroup(arguments,
+				helpers.RequiredDateParam(&lockRequest.StartDate, "start_date"),
+				helpers.RequiredDateParam(&lockRequest.EndDate, "end_date"),
+				helpers.OptionalNumericListParam(&lockRequest.UserIDs, "user_ids"),
+			)
+			if err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
+			}
+			if time.Time(lockRequest.EndDate).Before(time.Time(lockRequest.StartDate)) {
+				return helpers.NewToolResultTextError("invalid parameters: end_date must not be before start_date"), nil
+			}
+
+			_, err = twapi.Execute[timesheetLockRequest, *timesheetUpdateResponse](ctx, engine, lockRequest)
+			if err != nil {
+				if isNotFoundError(err) {
+					return helpers.NewToolResultTextError("timesheet locking isn't supported by this installation"), nil
+				}
+				return helpers.HandleAPIError(err, fmt.Sprintf("failed to %s timesheets", action))
+			}
+			return helpers.NewToolResultText("Timesheets from %s to %s %sed successfully",
+				time.Time(lockRequest.StartDate).Format(time.DateOnly),
+				time.Time(lockRequest.EndDate).Format(time.DateOnly), action), nil
+		},
+	}
+}
+
+// TimesheetSubmit submits the weekly timesheet of a user for approval in
+// Teamwork.com.
+func TimesheetSubmit(engine *twapi.Engine) toolsets.ToolWrapper {
+	return timesheetApprovalTool(engine, MethodTimesheetSubmit, "submit", "submitted", "Submit Timesheet")
+}
+
+// TimesheetApprove approves the weekly timesheet of a user in Teamwork.com.
+func TimesheetApprove(engine *twapi.Engine) toolsets.ToolWrapper {
+	return timesheetApprovalTool(engine, MethodTimesheetApprove, "approve", "approved", "Approve Timesheet")
+}
+
+// TimesheetReject rejects the weekly timesheet of a user in Teamwork.com.
+func TimesheetReject(engine *twapi.Engine) toolsets.ToolWrapper {
+	return timesheetApprovalTool(engine, MethodTimesheetReject, "reject", "rejected", "Reject Timesheet")
+}
+
+func timesheetApprovalTool(
+	engine *twapi.Engine,
+	method toolsets.Method,
+	action, pastAction, title string,
+) toolsets.ToolWrapper {
+	properties := map[string]*jsonschema.Schema{
+		"user_id": {
+			Type:        "integer",
+			Description: fmt.Sprintf("The ID of the user whose timesheet is %s.", pastAction),
+		},
+		"week_start": {
+			Type:   "string",
+			Format: "date",
+			Description: "Any day of the week of the timesheet, in the format YYYY-MM-DD. The week is identified " +
+				"by its Monday.",
+		},
+	}
+	if action == "reject" {
+		properties["reason"] = &jsonschema.Schema{
+			Type:        "string",
+			Description: "The reason of the rejection, shown to the user.",
+		}
+	}
+
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name: string(method),
+			Description: fmt.Sprintf("%s the weekly timesheet of a user in Teamwork.com. ", title) +
+				timesheetApprovalDescription,
+			Annotations: &mcp.ToolAnnotations{
+				Title: title,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:       "object",
+				Properties: properties,
+				Required:   []string{"user_id", "week_start"},
+			},
+		},
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			approvalRequest := timesheetApprovalRequest{Action: action}
+
+			var arguments map[string]any
+			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("failed to decode request: %s", err.Error())), nil
+			}
+			err := helpers.ParamGroup(arguments,
+				helpers.RequiredNumericParam(&approvalRequest.UserID, "user_id"),
+				helpers.RequiredDateParam(&approvalRequest.WeekStart, "week_start"),
+				helpers.OptionalParam(&approvalRequest.Reason, "reason"),
+			)
+			if err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
+			}
+			approvalRequest.WeekStart = twapi.Date(weekStart(time.Time(approvalRequest.WeekStart)))
+
+			_, err = twapi.Execute[timesheetApprovalRequest, *timesheetUpdateResponse](ctx, engine, approvalRequest)
+			if err != nil {
+				if isNotFoundError(err) {
+					return helpers.NewToolResultTextError("timesheet approvals aren't supported by this installation"), nil
+				}
+				return helpers.HandleAPIError(err, fmt.Sprintf("failed to %s timesheet", action))
+			}
+			return helpers.NewToolResultText("Timesheet of user %d for the week of %s %s successfully",
+				approvalRequest.UserID, time.Time(approvalRequest.WeekStart).Format(time.DateOnly), pastAction), nil
+		},
+	}
+}
+
+// TimesheetApprovalList lists the weekly timesheets in the approval workflow in
+// Teamwork.com.
+func TimesheetApprovalList(engine *twapi.Engine) toolsets.ToolWrapper {
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name: string(MethodTimesheetApprovalList),
+			Description: "List the weekly timesheets in the approval workflow in Teamwork.com. Use it with the " +
+				"'submitted' status to get the approval queue. " + timesheetApprovalDescription,
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "List Timesheet Approvals",
+				ReadOnlyHint: true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"status": {
+						Type:        "string",
+						Description: "The approval status to filter by. Defaults to submitted.",
+						Enum:        enumSchemaValues(timesheetApprovalStatuses),
+					},
+					"user_ids": {
+						Type:        "array",
+						Description: "The IDs of the users to filter by.",
+						Items: &jsonschema.Schema{
+							Type: "integer",
+						},
+					},
+					"page": {
+						Type:        "integer",
+						Description: "Page number for pagination of results.",
+					},
+					"page_size": {
+						Type:        "integer",
+						Description: "Number of results per page for pagination.",
+					},
+				},
+			},
+		},
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			listRequest := timesheetApprovalListRequest{Status: "submitted"}
+
+			var arguments map[string]any
+			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("failed to decode request: %s", err.Error())), nil
+			}
+			err := helpers.ParamGroup(arguments,
+				helpers.OptionalParam(&listRequest.Status, "status",
+					helpers.RestrictValues(timesheetApprovalStatuses...),
+				),
+				helpers.OptionalNumericListParam(&listRequest.UserIDs, "user_ids"),
+				helpers.OptionalNumericParam(&listRequest.Page, "page"),
+				helpers.OptionalNumericParam(&listRequest.PageSize, "page_size"),
+			)
+			if err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
+			}
+
+			approvalList, err := twapi.Execute[timesheetApprovalListRequest, *rawResponse](ctx, engine, listRequest)
+			if err != nil {
+				if isNotFoundError(err) {
+					return helpers.NewToolResultTextError("timesheet approvals aren't supported by this installation"), nil
+				}
+				return helpers.HandleAPIError(err, "failed to list timesheet approvals")
+			}
+			return helpers.NewToolResultText("%s", approvalList.body), nil
+		},
+	}
+}
+
+// weekStart returns the Monday of the week of the date.
+func weekStart(date time.Time) time.Time {
+	offset := (int(date.Weekday()) + 6) % 7
+	return date.AddDate(0, 0, -offset)
+}
+
+// isNotFoundError checks if the API returned a not found status, which is the
+// case of endpoints not available in the installation.
+func isNotFoundError(err error) bool {
+	var httpErr *twapi.HTTPError
+	return errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusNotFound
+}
+
+// timesheetLockRequest represents the request to lock or unlock the timesheets
+// of a date range.
+type timesheetLockRequest struct {
+	Lock      bool
+	StartDate twapi.Date
+	EndDate   twapi.Date
+	UserIDs   []int64
+}
+
+// HTTPRequest creates an HTTP request for the timesheetLockRequest.
+func (t timesheetLockRequest) HTTPRequest(ctx context.Context, server string) (*http.Request, error) {
+	uri := server + "/projects/api/v3/timesheets/unlock.json"
+	if t.Lock {
+		uri = server + "/projects/api/v3/timesheets/lock.json"
+	}
+
+	payload := struct {
+		StartDate twapi.Date `json:"startDate"`
+		EndDate   twapi.Date `json:"endDate"`
+		UserIDs   []int64    `json:"userIds,omitempty"`
+	}{
+		StartDate: t.StartDate,
+		EndDate:   t.EndDate,
+		UserIDs:   t.UserIDs,
+	}
+
+	var body bytes.Buffer
+	if err := json.NewEncoder(&body).Encode(payload); err != nil {
+		return nil, fmt.Errorf("failed to encode timesheet lock request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, uri, &body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+// timesheetUpdateResponse handles the response of the timesheet lock and
+// approval requests.
+type timesheetUpdateResponse struct{}
+
+// HandleHTTPResponse handles the HTTP response for the timesheetUpdateResponse.
+func (t *timesheetUpdateResponse) HandleHTTPResponse(resp *http.Response) error {
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return twapi.NewHTTPError(resp, "failed to update timesheets")
+	}
+	return nil
+}
+
+// timesheetApprovalRequest represents the request to change the approval
+// status of a weekly timesheet.
+type timesheetApprovalRequest struct {
+	Action    string
+	UserID    int64
+	WeekStart twapi.Date
+	Reason    string
+}
+
+// HTTPRequest creates an HTTP request for the timesheetApprovalRequest.
+func (t timesheetApprovalRequest) HTTPRequest(ctx context.Context, server string) (*http.Request, error) {
+	uri := server + "/projects/api/v3/timesheets/" + t.Action + ".json"
+
+	payload := struct {
+		UserID    int64      `json:"userId"`
+		WeekStart twapi.Date `json:"weekStart"`
+		Reason    string     `json:"reason,omitempty"`
+	}{
+		UserID:    t.UserID,
+		WeekStart: t.WeekStart,
+		Reason:    t.Reason,
+	}
+
+	var body bytes.Buffer
+	if err := json.NewEncoder(&body).Encode(payload); err != nil {
+		return nil, fmt.Errorf("failed to encode timesheet approval request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, uri, &body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+// timesheetApprovalListRequest represents the request to list the weekly
+// timesheets in the approval workflow.
+type timesheetApprovalListRequest struct {
+	Status   string
+	UserIDs  []int64
+	Page     int64
+	PageSize int64
+}
+
+// HTTPRequest creates an HTTP request for the timesheetApprovalListRequest.
+func (t timesheetApprovalListRequest) HTTPRequest(ctx context.Context, server string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server+"/projects/api/v3/timesheets/approvals.json", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	query := req.URL.Query()
+	query.Set("status", t.Status)
+	if len(t.UserIDs) > 0 {
+		userIDs := make([]string, len(t.UserIDs))
+		for i, id := range t.UserIDs {
+			userIDs[i] = strconv.FormatInt(id, 10)
+		}
+		query.Set("userIds", strings.Join(userIDs, ","))
+	}
+	if t.Page > 0 {
+		query.Set("page", strconv.FormatInt(t.Page, 10))
+	}
+	if t.PageSize > 0 {
+		query.Set("pageSize", strconv.FormatInt(t.PageSize, 10))
+	}
+	req.URL.RawQuery = query.Encode()
+	return req, nil
+}
+
+// rawResponse keeps the JSON body of a response not modeled by the SDK.
+type rawResponse struct {
+	body json.RawMessage
+}
+
+// HandleHTTPResponse handles the HTTP response for the rawResponse.
+func (r *rawResponse) HandleHTTPResponse(resp *http.Response) error {
+	if resp.StatusCode != http.StatusOK {
+		return twapi.NewHTTPError(resp, "unexpected response")
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&r.body); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package twprojects_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/teamwork/mcp/internal/testutil"
+	"github.com/teamwork/mcp/internal/twprojects"
+)
+
+func TestTimesheetLock(t *testing.T) {
+	mcpServer := mcpServerMock(t, http.StatusOK, []byte(`{}`))
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTimesheetLock.String(), map[string]any{
+		"start_date": "2024-01-01",
+		"end_date":   "2024-01-31",
+		"user_ids":   []float64{1, 2},
+	})
+}
+
+func TestTimesheetUnlock(t *testing.T) {
+	mcpServer := mcpServerMock(t, http.StatusOK, []byte(`{}`))
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTimesheetUnlock.String(), map[string]any{
+		"start_date": "2024-01-01",
+		"end_date":   "2024-01-31",
+	})
+}
+
+func TestTimesheetSubmit(t *testing.T) {
+	mcpServer := mcpServerMock(t, http.StatusOK, []byte(`{}`))
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTimesheetSubmit.String(), map[string]any{
+		"user_id":    float64(123),
+		"week_start": "2024-01-03",
+	})
+}
+
+func TestTimesheetApprove(t *testing.T) {
+	mcpServer := mcpServerMock(t, http.StatusOK, []byte(`{}`))
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTimesheetApprove.String(), map[string]any{
+		"user_id":    float64(123),
+		"week_start": "2024-01-01",
+	})
+}
+
+func TestTimesheetReject(t *testing.T) {
+	mcpServer := mcpServerMock(t, http.StatusOK, []byte(`{}`))
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTimesheetReject.String(), map[string]any{
+		"user_id":    float64(123),
+		"week_start": "2024-01-01",
+		"reason":     "Missing hours on Friday",
+	})
+}
+
+func TestTimesheetApprovalList(t *testing.T) {
+	mcpServer := mcpServerMock(t, http.StatusOK, []byte(`{"timesheets":[]}`))
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTimesheetApprovalList.String(), map[string]any{
+		"status":    "submitted",
+		"user_ids":  []float64{1, 2},
+		"page":      float64(1),
+		"page_size": float64(10),
+	})
+}
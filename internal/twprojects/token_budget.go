@@ -0,0 +1,185 @@
+package twprojects
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/toolsets"
+)
+
+const (
+	// tokenBudgetCharsPerToken is the approximate number of characters of a
+	// token, used to estimate the tokens of the results without a tokenizer.
+	tokenBudgetCharsPerToken = 4
+	// tokenBudgetPageSize is the maximum page size of the list tools once the
+	// session exceeded its token budget.
+	tokenBudgetPageSize = 10
+)
+
+// tokenBudgetSummaryFields are the fields kept in the items of the list results
+// once the session exceeded its token budget.
+var tokenBudgetSummaryFields = []string{"id", "name", "title", "status", "meta"}
+
+// tokenBudget tracks the approximate tokens of the results returned in each
+// session. Once the budget is exceeded, the list tools switch to a summary mode
+// returning fewer items with only their main fields, protecting clients with
+// small context windows.
+type tokenBudget struct {
+	limit    int
+	sessions *sessionStore[int]
+}
+
+func newTokenBudget(limit int) *tokenBudget {
+	return &tokenBudget{
+		limit:    limit,
+		sessions: newSessionStore[int](),
+	}
+}
+
+// apply wraps the tools, so their results count towards the session budget.
+// When the budget is disabled, the tools are returned unchanged.
+func (b *tokenBudget) apply(tools []toolsets.ToolWrapper) []toolsets.ToolWrapper {
+	if b.limit <= 0 {
+		return tools
+	}
+	return toolsets.WrapToolList(tools, func(tool toolsets.ToolWrapper) toolsets.ToolWrapper {
+		schema, ok := tool.Tool.InputSchema.(*jsonschema.Schema)
+		listTool := ok && schema.Properties[paginationParams.PageSize] != nil
+		tool.Handler = b.middleware(listTool)(tool.Handler)
+		return tool
+	})
+}
+
+// middleware counts the result towards the session budget, summarizing the
+// results of the list tools once the budget is exceeded.
+func (b *tokenBudget) middleware(listTool bool) toolsets.ToolMiddleware {
+	return func(next mcp.ToolHandler) mcp.ToolHandler {
+		return func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			key, ok := sessionKey(ctx, request)
+			if !ok {
+				return next(ctx, request)
+			}
+			var used int
+			b.sessions.update(key, func(value *int) {
+				used = *value
+			})
+
+			summary := listTool && used >= b.limit
+			if summary {
+				var err error
+				if request, err = limitPageSize(request, tokenBudgetPageSize); err != nil {
+					return nil, err
+				}
+			}
+
+			result, err := next(ctx, request)
+			if err != nil || result == nil {
+				return result, err
+			}
+			if summary && !result.IsError {
+				summarizeListResult(result)
+			}
+
+			var exceeded bool
+			tokens := resultTokens(result)
+			b.sessions.update(key, func(value *int) {
+				exceeded = *value < b.limit && *value+tokens >= b.limit
+				*value += tokens
+			})
+			if exceeded && request.Session != nil {
+				// clients that didn't set a logging level don't receive the warning,
+				// but still get the notice in the list results
+				_ = request.Session.Log(ctx, &mcp.LoggingMessageParams{
+					Level:  "warning",
+					Logger: "teamwork-mcp",
+					Data: fmt.Sprintf("The session exceeded its budget of ~%d response tokens. List tools now return "+
+						"up to %d items with only their main fields.", b.limit, tokenBudgetPageSize),
+				})
+			}
+			if summary && !result.IsError {
+				result.Content = append(result.Content, &mcp.TextContent{
+					Text: fmt.Sprintf("The session exceeded its response token budget, so only the main fields of up "+
+						"to %d items are returned. Use the get tools for the details of specific items.",
+						tokenBudgetPageSize),
+				})
+			}
+			return result, nil
+		}
+	}
+}
+
+// limitPageSize returns a copy of the request with the page size limited to the
+// given size.
+func limitPageSize(request *mcp.CallToolRequest, size int) (*mcp.CallToolRequest, error) {
+	var arguments map[string]any
+	if len(request.Params.Arguments) > 0 {
+		if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+			// invalid arguments are reported by the tool
+			return request, nil
+		}
+	}
+	if pageSize, ok := arguments[paginationParams.PageSize].(float64); ok && pageSize > 0 && pageSize <= float64(size) {
+		return request, nil
+	}
+	if arguments == nil {
+		arguments = make(map[string]any)
+	}
+	arguments[paginationParams.PageSize] = float64(size)
+	return withArguments(request, arguments)
+}
+
+// summarizeListResult keeps only the summary fields of the items in the root
+// lists of the JSON results, dropping the included entities.
+func summarizeListResult(result *mcp.CallToolResult) {
+	for _, content := range result.Content {
+		text, ok := content.(*mcp.TextContent)
+		if !ok {
+			continue
+		}
+		decoder := json.NewDecoder(strings.NewReader(text.Text))
+		decoder.UseNumber()
+		var decoded map[string]any
+		if err := decoder.Decode(&decoded); err != nil {
+			continue
+		}
+		delete(decoded, "included")
+		for _, value := range decoded {
+			items, ok := value.([]any)
+			if !ok {
+				continue
+			}
+			for i, item := range items {
+				object, ok := item.(map[string]any)
+				if !ok {
+					continue
+				}
+				summary := make(map[string]any, len(tokenBudgetSummaryFields))
+				for field, value := range object {
+					if slices.Contains(tokenBudgetSummaryFields, field) {
+						summary[field] = value
+					}
+				}
+				items[i] = summary
+			}
+		}
+		if encoded, err := json.Marshal(decoded); err == nil {
+			text.Text = string(encoded)
+		}
+	}
+}
+
+// resultTokens estimates the tokens of the text contents of the result.
+func resultTokens(result *mcp.CallToolResult) int {
+	var chars int
+	for _, content := range result.Content {
+		if text, ok := content.(*mcp.TextContent); ok {
+			chars += len(text.Text)
+		}
+	}
+	return chars / tokenBudgetCharsPerToken
+}
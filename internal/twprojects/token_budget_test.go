@@ -0,0 +1,57 @@
+package twprojects_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/testutil"
+	"github.com/teamwork/mcp/internal/twprojects"
+)
+
+func TestTokenBudget(t *testing.T) {
+	runSessions(t, func(t *testing.T, withSession testutil.ExecuteToolRequestOption) {
+		var pageSizes []string
+		mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(r *http.Request) (int, []byte) {
+			pageSizes = append(pageSizes, r.URL.Query().Get("pageSize"))
+			return http.StatusOK, []byte(`{"tasks":[{"id":1,"name":"Example","description":"` +
+				strings.Repeat("a", 200) + `"}],"included":{"users":{}}}`)
+		}, twprojects.WithTokenBudget(50))
+
+		// the first call exceeds the budget, so the following list calls are
+		// summarized
+		for range 2 {
+			testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTaskList.String(), map[string]any{
+				"page_size": float64(50),
+			}, withSession)
+		}
+
+		testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTaskList.String(), map[string]any{
+			"page_size": float64(50),
+		}, withSession, testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+			toolResult, ok := result.(*mcp.CallToolResult)
+			if !ok {
+				t.Fatalf("unexpected result type: %T", result)
+			}
+			if toolResult.IsError {
+				t.Fatalf("tool failed to execute: %v", toolResult.Content)
+			}
+			text := toolResult.Content[0].(*mcp.TextContent).Text
+			if strings.Contains(text, "description") || strings.Contains(text, "included") {
+				t.Errorf("expected summarized items, got %s", text)
+			}
+			if !strings.Contains(text, `"name":"Example"`) {
+				t.Errorf("expected item name, got %s", text)
+			}
+			notice := toolResult.Content[len(toolResult.Content)-1].(*mcp.TextContent).Text
+			if !strings.Contains(notice, "token budget") {
+				t.Errorf("expected budget notice, got %s", notice)
+			}
+		}))
+
+		if pageSizes[0] != "50" || pageSizes[len(pageSizes)-1] != "10" {
+			t.Errorf("expected page size limited after the budget, got %v", pageSizes)
+		}
+	})
+}
@@ -1,24 +1,292 @@
 package twprojects
 
 import (
+	"slices"
+	"time"
+
+	"github.com/teamwork/mcp/internal/config"
+	"github.com/teamwork/mcp/internal/helpers"
+	"github.com/teamwork/mcp/internal/textextract"
 	"github.com/teamwork/mcp/internal/toolsets"
 	twapi "github.com/teamwork/twapi-go-sdk"
 )
 
+// paginationParams are the pagination parameters of the list tools.
+var paginationParams = helpers.PaginationParams{
+	Page:            "page",
+	PageSize:        "page_size",
+	DefaultPageSize: 50,
+}
+
+// exportsURIPrefix is the URI prefix of the resources with the large results
+// spilled to disk and the exported documents.
+const exportsURIPrefix = "twprojects://exports/"
+
+// ToolsetGroupOptions holds optional features of the default ToolsetGroup.
+type ToolsetGroupOptions struct {
+	// reportTemplates are the operator-defined reports exposed as prompts and
+	// through the report tool.
+	reportTemplates []config.ReportTemplate
+	// defaultProjectID is the project used when the project_id argument is
+	// omitted and the session has no default project.
+	defaultProjectID int64
+	// tokenBudget is the approximate number of response tokens of a session
+	// before the list tools switch to the summary mode. Zero disables it.
+	tokenBudget int
+	// macros are the operator-defined shortcuts exposed as additional tools.
+	macros []config.ToolMacro
+	// passthrough makes the get and list tools return the API responses as is,
+	// without web links.
+	passthrough bool
+	// exportMemoryLimit is the size, in bytes, above which the large results
+	// (e.g. reports) are spilled to disk. Zero uses the default.
+	exportMemoryLimit int
+	// slaRules are the operator-defined SLA rules evaluated by the SLA breaches
+	// tool when no rules are provided at call time.
+	slaRules []config.SLARule
+	// roleTemplates are the operator-defined project permissions compared and
+	// applied by the role template tool.
+	roleTemplates []config.RoleTemplate
+	// textExtractors are the extractors of the attached files text, added to
+	// the built-in ones.
+	textExtractors map[string]textextract.Extractor
+	// displayTimezone is the timezone of the human-readable timestamps added to
+	// the tool results. Nil doesn't add them.
+	displayTimezone *time.Location
+	// noCompaction keeps the null and empty fields in the tool results.
+	noCompaction bool
+	// allowedProjectIDs restricts the tools to the entities of these projects.
+	// Empty allows all projects.
+	allowedProjectIDs []int64
+	// noFinance removes the tools and result fields with financial data.
+	noFinance bool
+	// companyID binds the tools to the projects and people of a client
+	// company. Zero doesn't bind them.
+	companyID int64
+	// taskDefaults are the tasklist and assignees of the tasks created without
+	// them.
+	taskDefaults config.TaskDefaults
+	// writeLog is the log of the write requests listed by the audit tool. Nil
+	// doesn't add the tool.
+	writeLog *config.WriteLog
+}
+
+// ToolsetGroupOption is a function that configures the ToolsetGroupOptions.
+type ToolsetGroupOption func(*ToolsetGroupOptions)
+
+// WithReportTemplates enables the report tool and prompts for the given report
+// templates.
+func WithReportTemplates(templates []config.ReportTemplate) ToolsetGroupOption {
+	return func(opts *ToolsetGroupOptions) {
+		opts.reportTemplates = templates
+	}
+}
+
+// WithDefaultProject sets the project used by the tools when the project_id
+// argument is omitted, unless the session has its own default project.
+func WithDefaultProject(projectID int64) ToolsetGroupOption {
+	return func(opts *ToolsetGroupOptions) {
+		opts.defaultProjectID = projectID
+	}
+}
+
+// WithTokenBudget sets the approximate number of response tokens of a session
+// before the list tools switch to a summary mode, returning fewer items with
+// only their main fields. Zero or negative disables the budget.
+func WithTokenBudget(tokens int) ToolsetGroupOption {
+	return func(opts *ToolsetGroupOptions) {
+		opts.tokenBudget = tokens
+	}
+}
+
+// WithMacros exposes the given macros as additional tools, calling another
+// tool of the toolset with fixed arguments.
+func WithMacros(macros []config.ToolMacro) ToolsetGroupOption {
+	return func(opts *ToolsetGroupOptions) {
+		opts.macros = macros
+	}
+}
+
+// WithPassthrough makes the get and list tools return the API responses as is,
+// skipping the decoding and re-encoding of large responses. The results don't
+// have web links.
+func WithPassthrough(enabled bool) ToolsetGroupOption {
+	return func(opts *ToolsetGroupOptions) {
+		opts.passthrough = enabled
+	}
+}
+
+// WithExportMemoryLimit sets the size, in bytes, above which the large results
+// (e.g. reports) are written to a temporary file and returned as a link to an
+// MCP resource, instead of being kept in memory. Zero or negative uses
+// helpers.DefaultSpillMemoryLimit.
+func WithExportMemoryLimit(bytes int) ToolsetGroupOption {
+	return func(opts *ToolsetGroupOptions) {
+		opts.exportMemoryLimit = bytes
+	}
+}
+
+// WithSLARules sets the SLA rules evaluated by the SLA breaches tool when the
+// rules aren't provided at call time.
+func WithSLARules(rules []config.SLARule) ToolsetGroupOption {
+	return func(opts *ToolsetGroupOptions) {
+		opts.slaRules = rules
+	}
+}
+
+// WithRoleTemplates sets the named project permissions available to the role
+// template tool.
+func WithRoleTemplates(templates []config.RoleTemplate) ToolsetGroupOption {
+	return func(opts *ToolsetGroupOptions) {
+		opts.roleTemplates = templates
+	}
+}
+
+// WithTextExtractors adds extractors of the attached files text, by file
+// extension (e.g. ".pdf"), replacing the built-in ones of the same extensions.
+func WithTextExtractors(extractors map[string]textextract.Extractor) ToolsetGroupOption {
+	return func(opts *ToolsetGroupOptions) {
+		opts.textExtractors = extractors
+	}
+}
+
+// WithDisplayTimezone sets the timezone of the installation, used to add a
+// human-readable version of the timestamps in the tool results. The timestamps
+// themselves are always in UTC.
+func WithDisplayTimezone(location *time.Location) ToolsetGroupOption {
+	return func(opts *ToolsetGroupOptions) {
+		opts.displayTimezone = location
+	}
+}
+
+// WithCompaction sets whether the null and empty fields are removed from the
+// results of the get and list tools, which is enabled by default.
+func WithCompaction(enabled bool) ToolsetGroupOption {
+	return func(opts *ToolsetGroupOptions) {
+		opts.noCompaction = !enabled
+	}
+}
+
+// WithAllowedProjects restricts the tools to the entities of the given
+// projects, rejecting the calls referencing other projects or changing entities
+// that don't belong to a project (e.g. users). An empty list allows all
+// projects.
+func WithAllowedProjects(projectIDs []int64) ToolsetGroupOption {
+	return func(opts *ToolsetGroupOptions) {
+		opts.allowedProjectIDs = projectIDs
+	}
+}
+
+// WithFinance sets whether the tools and result fields with financial data
+// (e.g. rates, costs and budgets) are available, which is enabled by default.
+// It is independent of the read-only mode, so the model can automate the work
+// without seeing the billing data.
+func WithFinance(enabled bool) ToolsetGroupOption {
+	return func(opts *ToolsetGroupOptions) {
+		opts.noFinance = !enabled
+	}
+}
+
+// WithCompany binds the tools to the projects and people of a client company,
+// so a client-facing agent can't read the data of other clients. Zero or
+// negative doesn't bind them.
+func WithCompany(companyID int64) ToolsetGroupOption {
+	return func(opts *ToolsetGroupOptions) {
+		opts.companyID = max(companyID, 0)
+	}
+}
+
+// WithTaskDefaults sets the tasklist and assignees used when a task is created
+// without them, making the tasklist_id argument of the task creation optional.
+func WithTaskDefaults(defaults config.TaskDefaults) ToolsetGroupOption {
+	return func(opts *ToolsetGroupOptions) {
+		opts.taskDefaults = defaults
+	}
+}
+
 // DefaultToolsetGroup creates a default ToolsetGroup for Teamwork Projects.
-func DefaultToolsetGroup(readOnly, allowDelete bool, engine *twapi.Engine) *toolsets.ToolsetGroup {
+func DefaultToolsetGroup(
+	readOnly, allowDelete bool,
+	engine *twapi.Engine,
+	opts ...ToolsetGroupOption,
+) *toolsets.ToolsetGroup {
+	provider := NewToolsetProvider(allowDelete, engine, opts...)
+
+	group := toolsets.NewToolsetGroup(readOnly)
+	toolset := group.AddProvider(provider)
+	if len(provider.options.reportTemplates) > 0 {
+		toolset.AddPrompts(ReportPrompts(provider.options.reportTemplates, provider.reportTools)...)
+	}
+	toolset.AddResourceTemplates(provider.exports.ResourceTemplate())
+	toolset.AddResourceTemplates(provider.previews.ResourceTemplate())
+	toolset.AddResourceTemplates(ProjectCalendarResourceTemplate(engine, provider.sandbox))
+	toolset.AddResourceTemplates(provider.resources.ProjectTemplate(), provider.resources.TaskTemplate())
+	toolset.SetResourceSubscriptions(provider.resources.subscriptions)
+	newCompletions(engine, provider.sandbox).register(toolset)
+	return group
+}
+
+// ToolsetProvider provides the tools of the Teamwork Projects toolset.
+type ToolsetProvider struct {
+	options    ToolsetGroupOptions
+	readTools  []toolsets.ToolWrapper
+	writeTools []toolsets.ToolWrapper
+	// reportTools are the tools that can be referenced by the reports
+	reportTools []toolsets.ToolWrapper
+	// exports keeps the large results spilled to disk and the exported documents
+	exports *helpers.SpillStore
+	// previews keeps the intended changes of the bulk tools
+	previews *bulkPreviews
+	// sandbox restricts the tools and resources to the allowed projects
+	sandbox *projectSandbox
+	// resources exposes the projects and tasks as resources
+	resources *entityResources
+}
+
+// NewToolsetProvider creates the tools of the Teamwork Projects toolset. The
+// delete tools are only provided when allowDelete is true.
+func NewToolsetProvider(
+	allowDelete bool,
+	engine *twapi.Engine,
+	opts ...ToolsetGroupOption,
+) *ToolsetProvider {
+	var options ToolsetGroupOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	heartbeats := newTimerHeartbeats()
+
 	writeTools := []toolsets.ToolWrapper{
 		ProjectCreate(engine),
 		ProjectUpdate(engine),
+		ProjectClone(engine),
+		ProjectBootstrap(engine),
 		ProjectMemberAdd(engine),
+		RoleTemplateApply(engine, options.roleTemplates),
 		TasklistCreate(engine),
+		TasklistTemplateApply(engine),
 		TasklistUpdate(engine),
+		TasklistEmailAddressRegenerate(engine),
 		TaskCreate(engine),
 		TaskUpdate(engine),
+		TaskDraftFromText(engine),
+		TaskChecklistItemAdd(engine),
+		TaskChecklistItemToggle(engine),
+		TaskReactionAdd(engine),
+		TaskLinkCreate(engine),
+		StaleTaskSweep(engine),
 		UserCreate(engine),
+		UserImport(engine),
+		ClientInvite(engine),
+		ClientInvitationResend(engine),
+		ClientInvitationCancel(engine, allowDelete),
 		UserUpdate(engine),
+		UserDeactivate(engine),
+		UserReactivate(engine),
 		MilestoneCreate(engine),
 		MilestoneUpdate(engine),
+		MilestoneComplete(engine),
 		CompanyCreate(engine),
 		CompanyUpdate(engine),
 		TagCreate(engine),
@@ -29,13 +297,21 @@ func DefaultToolsetGroup(readOnly, allowDelete bool, engine *twapi.Engine) *tool
 		CommentUpdate(engine),
 		TimelogCreate(engine),
 		TimelogUpdate(engine),
+		TimesheetGridFill(engine),
+		TimesheetLock(engine),
+		TimesheetUnlock(engine),
+		TimesheetSubmit(engine),
+		TimesheetApprove(engine),
+		TimesheetReject(engine),
 		TimerCreate(engine),
 		TimerUpdate(engine),
 		TimerPause(engine),
 		TimerResume(engine),
 		TimerComplete(engine),
+		TimerHeartbeat(engine, heartbeats),
 		NotebookCreate(engine),
 		NotebookUpdate(engine),
+		TrashRestore(engine),
 	}
 	if allowDelete {
 		writeTools = append(writeTools, []toolsets.ToolWrapper{
@@ -53,53 +329,208 @@ func DefaultToolsetGroup(readOnly, allowDelete bool, engine *twapi.Engine) *tool
 			NotebookDelete(engine),
 		}...)
 	}
+	// bulk tools only make changes referencing a reviewed preview
+	previews := newBulkPreviews()
+	writeTools = previews.apply(writeTools)
 
-	group := toolsets.NewToolsetGroup(readOnly)
-	group.AddToolset(toolsets.NewToolset("projects", projectDescription).
-		AddWriteTools(writeTools...).
-		AddReadTools(
-			ProjectGet(engine),
-			ProjectList(engine),
-			TasklistGet(engine),
-			TasklistList(engine),
-			TasklistListByProject(engine),
-			TaskGet(engine),
-			TaskList(engine),
-			TaskListByTasklist(engine),
-			TaskListByProject(engine),
-			UserGet(engine),
-			UserGetMe(engine),
-			UserList(engine),
-			UserListByProject(engine),
-			UsersWorkload(engine),
-			MilestoneGet(engine),
-			MilestoneList(engine),
-			MilestoneListByProject(engine),
-			CompanyGet(engine),
-			CompanyList(engine),
-			TagGet(engine),
-			TagList(engine),
-			TeamGet(engine),
-			TeamList(engine),
-			TeamListByCompany(engine),
-			TeamListByProject(engine),
-			CommentGet(engine),
-			CommentList(engine),
-			CommentListByFileVersion(engine),
-			CommentListByMilestone(engine),
-			CommentListByNotebook(engine),
-			CommentListByTask(engine),
-			TimelogGet(engine),
-			TimelogList(engine),
-			TimelogListByProject(engine),
-			TimelogListByTask(engine),
-			TimerGet(engine),
-			TimerList(engine),
-			ActivityList(engine),
-			ActivityListByProject(engine),
-			NotebookGet(engine),
-			NotebookList(engine),
-			IndustryList(engine),
-		))
-	return group
+	// writes performed in the session are recorded, so the undo tool can revert
+	// them
+	journal := newUndoJournal(engine, allowDelete)
+	writeTools = journal.record(writeTools)
+
+	defaults := newDefaultProject(options.defaultProjectID)
+	recent := newRecentEntities()
+	exports := helpers.NewSpillStore(exportsURIPrefix, options.exportMemoryLimit, 0)
+
+	readTools := []toolsets.ToolWrapper{
+		ProjectGet(engine),
+		ProjectList(engine),
+		ProjectTimeBudgetGet(engine),
+		TasklistGet(engine),
+		TasklistList(engine),
+		TasklistListByProject(engine),
+		TasklistTemplateList(engine),
+		TasklistEmailAddressGet(engine),
+		TaskGet(engine),
+		TaskList(engine),
+		TaskListByTasklist(engine),
+		TaskListByProject(engine),
+		CompletedTaskList(engine),
+		PriorityInbox(engine),
+		FollowedTasksDigest(engine),
+		CriticalPathGet(engine),
+		SLABreachList(engine, options.slaRules),
+		TaskChecklistList(engine),
+		TaskReactionList(engine),
+		TaskLinkList(engine),
+		UserGet(engine),
+		UserGetMe(engine),
+		UserList(engine),
+		UserListByProject(engine),
+		CompanyUserList(engine),
+		PersonFind(engine),
+		UsersWorkload(engine),
+		DueDateSuggest(engine),
+		MilestoneGet(engine),
+		MilestoneList(engine),
+		MilestoneListByProject(engine),
+		CompanyGet(engine),
+		CompanyList(engine),
+		TagGet(engine),
+		TagList(engine),
+		TeamGet(engine),
+		TeamList(engine),
+		TeamListByCompany(engine),
+		TeamListByProject(engine),
+		CommentGet(engine),
+		CommentList(engine),
+		CommentListByFileVersion(engine),
+		CommentListByMilestone(engine),
+		CommentListByNotebook(engine),
+		CommentListByTask(engine),
+		HTMLBodyBuild(engine),
+		TimelogGet(engine),
+		TimelogList(engine),
+		TimelogListByProject(engine),
+		TimelogListByTask(engine),
+		TimerGet(engine),
+		TimerList(engine),
+		TimesheetApprovalList(engine),
+		TimesheetGridGet(engine),
+		ActivityList(engine),
+		ActivityListByProject(engine),
+		TrashList(engine),
+		FileTextExtract(engine, textextract.Default().With(options.textExtractors)),
+		PrintoutExport(engine, exports),
+		ProjectCalendarGet(engine),
+		NotebookGet(engine),
+		NotebookList(engine),
+		IndustryList(engine),
+		EntitiesGet(engine),
+		EnumsGet(),
+		DefaultProjectSet(defaults),
+		RecentEntities(recent),
+	}
+	if options.writeLog != nil {
+		readTools = append(readTools, WriteRequestList(engine, options.writeLog))
+	}
+
+	if options.noFinance {
+		writeTools = withoutFinance(writeTools)
+		readTools = withoutFinance(readTools)
+	}
+
+	readTools = helpers.Paginate(readTools, paginationParams)
+	if options.passthrough {
+		readTools = toolsets.UseToolMiddlewares(readTools, passthroughMiddleware())
+	} else {
+		// the API versions return the timestamps in different formats
+		readTools = toolsets.UseToolMiddlewares(readTools, normalizeDatesMiddleware(options.displayTimezone))
+		if !options.noCompaction {
+			readTools = toolsets.UseToolMiddlewares(readTools, compactionMiddleware())
+		}
+	}
+
+	// create tools return the created entity and update tools can return the
+	// fields they changed
+	writeTools = entitySnapshot(writeTools, readTools)
+	writeTools = entityDiff(writeTools, readTools)
+
+	// clients subscribed to the changed projects and tasks are notified, and
+	// the resources hide the same data as the read tools
+	var resourceRewrites []func(data []byte) ([]byte, bool)
+	if options.noFinance {
+		resourceRewrites = append(resourceRewrites, config.Redactor(config.Redaction{Rates: true}))
+	}
+	if !options.passthrough && !options.noCompaction {
+		resourceRewrites = append(resourceRewrites, helpers.CompactJSON)
+	}
+	sandbox := newProjectSandbox(engine, options.allowedProjectIDs, options.companyID)
+	resources := newEntityResources(engine, sandbox, resourceRewrites...)
+	writeTools = toolsets.UseToolMiddlewares(writeTools, resources.middleware())
+
+	// names seen in the session annotate the relationships of later results
+	names := newRelationshipNames()
+	writeTools = toolsets.UseToolMiddlewares(writeTools, names.middleware())
+	readTools = toolsets.UseToolMiddlewares(readTools, names.middleware())
+
+	// tasks created without a tasklist or assignees use the configured ones
+	writeTools = newTaskDefaults(engine, options.taskDefaults, defaults).apply(writeTools)
+
+	// the references are checked against the allowed projects and the client
+	// company once the default project and the recent entities are resolved
+	writeTools = sandbox.apply(writeTools, false)
+	readTools = sandbox.apply(readTools, true)
+
+	// tools requiring a project fall back to the default project of the session
+	writeTools = defaults.apply(writeTools)
+	readTools = defaults.apply(readTools)
+
+	// entities created or fetched in the session can be referenced in the ID
+	// parameters, so the references are resolved before any other wrapper
+	writeTools = toolsets.UseToolMiddlewares(writeTools, recent.middleware())
+	readTools = toolsets.UseToolMiddlewares(readTools, recent.middleware())
+
+	// the results of all tools count towards the token budget of the session
+	budget := newTokenBudget(options.tokenBudget)
+	writeTools = budget.apply(writeTools)
+	readTools = budget.apply(readTools)
+
+	// the undo tool reverts the writes through the fully wrapped tools, so they
+	// are checked like the writes of the model
+	writeTools = append(slices.Clip(writeTools), UndoLast(journal, writeTools))
+
+	// macros call the fully wrapped tools, so they behave like the tool they
+	// reference
+	if len(options.macros) > 0 {
+		writeTools = append(slices.Clip(writeTools), macroTools(options.macros, writeTools)...)
+		readTools = append(slices.Clip(readTools), macroTools(options.macros, readTools)...)
+	}
+
+	provider := &ToolsetProvider{
+		options:     options,
+		readTools:   readTools,
+		writeTools:  writeTools,
+		reportTools: readTools,
+		exports:     exports,
+		previews:    previews,
+		sandbox:     sandbox,
+		resources:   resources,
+	}
+	if len(options.reportTemplates) > 0 {
+		// reports can only reference read tools, so they are safe in read-only
+		// sessions
+		provider.readTools = append(slices.Clip(readTools), ReportRun(options.reportTemplates, readTools,
+			provider.exports))
+	}
+	return provider
+}
+
+// Method returns the method of the Teamwork Projects toolset.
+func (p *ToolsetProvider) Method() toolsets.Method {
+	return "projects"
+}
+
+// Description returns the description of the Teamwork Projects toolset.
+func (p *ToolsetProvider) Description() string {
+	return projectDescription
+}
+
+// Methods returns the methods of the read and write tools.
+func (p *ToolsetProvider) Methods() []toolsets.Method {
+	methods := make([]toolsets.Method, 0, len(p.readTools)+len(p.writeTools))
+	for _, tool := range slices.Concat(p.readTools, p.writeTools) {
+		methods = append(methods, toolsets.Method(tool.Tool.Name))
+	}
+	return methods
+}
+
+// ReadTools returns the tools that don't change any data.
+func (p *ToolsetProvider) ReadTools() []toolsets.ToolWrapper {
+	return p.readTools
+}
+
+// WriteTools returns the tools that change data.
+func (p *ToolsetProvider) WriteTools() []toolsets.ToolWrapper {
+	return p.writeTools
 }
@@ -0,0 +1,272 @@
+package twprojects
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/helpers"
+	"github.com/teamwork/mcp/internal/toolsets"
+	twapi "github.com/teamwork/twapi-go-sdk"
+	"github.com/teamwork/twapi-go-sdk/projects"
+)
+
+// List of methods available in the Teamwork.com MCP service.
+//
+// The naming convention for methods follows a pattern described here:
+// https://github.com/github/github-mcp-server/issues/333
+const (
+	MethodTrashList    toolsets.Method = "twprojects-list_trash"
+	MethodTrashRestore toolsets.Method = "twprojects-restore_from_trash"
+)
+
+const trashDescription = "Deleted tasks and projects are kept in the trash of Teamwork.com for a while, where they " +
+	"can be restored with all their content, e.g. to recover from an accidental deletion."
+
+// trashDefaultDays is the number of days listed by default in the trash.
+const trashDefaultDays = 30
+
+// trashMaxItems is the maximum number of deleted items listed in the trash.
+const trashMaxItems = 500
+
+func init() {
+	// register the toolset methods
+	toolsets.RegisterMethod(MethodTrashList)
+	toolsets.RegisterMethod(MethodTrashRestore)
+}
+
+// trashRestoreRequest represents the request to restore a deleted item.
+type trashRestoreRequest struct {
+	ItemType string
+	ID       int64
+}
+
+// HTTPRequest creates an HTTP request for the trashRestoreRequest.
+func (t trashRestoreRequest) HTTPRequest(ctx context.Context, server string) (*http.Request, error) {
+	uri := server + "/projects/api/v3/" + t.ItemType + "/" + strconv.FormatInt(t.ID, 10) + "/restore.json"
+	return http.NewRequestWithContext(ctx, http.MethodPut, uri, nil)
+}
+
+// trashRestoreResponse represents the response of restoring a deleted item.
+type trashRestoreResponse struct{}
+
+// HandleHTTPResponse handles the HTTP response for the trashRestoreResponse. If
+// some unexpected HTTP status code is returned by the API, a twapi.HTTPError is
+// returned.
+func (t *trashRestoreResponse) HandleHTTPResponse(resp *http.Response) error {
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return twapi.NewHTTPError(resp, "failed to restore item")
+	}
+	return nil
+}
+
+// trashItem is a deleted item in the trash.
+type trashItem struct {
+	ID         int64      `json:"id"`
+	Name       string     `json:"name"`
+	DeletedAt  *time.Time `json:"deletedAt,omitempty"`
+	DeletedBy  *int64     `json:"deletedBy,omitempty"`
+	TasklistID int64      `json:"tasklistId,omitempty"`
+}
+
+// trashRestoreHint returns the instructions to restore a deleted item, added
+// to the results of the delete tools.
+func trashRestoreHint(itemType string, id int64) mcp.Content {
+	return &mcp.TextContent{
+		Text: fmt.Sprintf("The deletion can be reverted for a while with the %s tool, using item_type %q and id %d.",
+			MethodTrashRestore, itemType, id),
+	}
+}
+
+// TrashList lists the recently deleted tasks or projects in Teamwork.com.
+func TrashList(engine *twapi.Engine) toolsets.ToolWrapper {
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name: string(MethodTrashList),
+			Description: "List the recently deleted tasks or projects in Teamwork.com, with when and by whom they " +
+				"were deleted. " + trashDescription,
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "List Trash",
+				ReadOnlyHint: true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"item_type": {
+						Type:        "string",
+						Description: "The type of the deleted items.",
+						Enum:        enumSchemaValues(trashItemTypes),
+					},
+					"project_id": {
+						Type:        "integer",
+						Description: "The ID of the project of the deleted tasks. Ignored for projects.",
+					},
+					"deleted_after": {
+						Type:   "string",
+						Format: "date-time",
+						Description: fmt.Sprintf("List only the items deleted after this date and time. Defaults to %d "+
+							"days ago.", trashDefaultDays),
+					},
+				},
+				Required: []string{"item_type"},
+			},
+		},
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var itemType string
+			var projectID int64
+			var deletedAfter *time.Time
+
+			var arguments map[string]any
+			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("failed to decode request: %s", err.Error())), nil
+			}
+			err := helpers.ParamGroup(arguments,
+				helpers.RequiredParam(&itemType, "item_type", helpers.RestrictValues(trashItemTypes...)),
+				helpers.OptionalNumericParam(&projectID, "project_id"),
+				helpers.OptionalTimePointerParam(&deletedAfter, "deleted_after"),
+			)
+			if err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
+			}
+			if deletedAfter == nil {
+				deletedAfter = twapi.Ptr(time.Now().AddDate(0, 0, -trashDefaultDays))
+			}
+
+			query := url.Values{
+				"updatedAfter": []string{deletedAfter.UTC().Format(time.RFC3339)},
+			}
+			pagination := helpers.AutoPagination{PageSize: 100, MaxItems: trashMaxItems}
+
+			var items []trashItem
+			var truncated bool
+			switch itemType {
+			case "tasks":
+				query.Set("showDeleted", "true")
+				items, truncated, err = helpers.FetchAllPages(ctx, pagination,
+					func(ctx context.Context, page, pageSize int64) ([]trashItem, bool, error) {
+						taskListRequest := projects.NewTaskListRequest()
+						taskListRequest.Path.ProjectID = projectID
+						taskListRequest.Filters.Page = page
+						taskListRequest.Filters.PageSize = pageSize
+
+						response, err := executeWithQuery[*projects.TaskListResponse](ctx, engine, taskListRequest, query)
+						if err != nil {
+							return nil, false, err
+						}
+						var deleted []trashItem
+						for _, task := range response.Tasks {
+							if task.Status != "deleted" {
+								continue
+							}
+							deleted = append(deleted, trashItem{
+								ID:         task.ID,
+								Name:       task.Name,
+								DeletedAt:  &task.UpdatedAt,
+								DeletedBy:  task.UpdatedBy,
+								TasklistID: task.Tasklist.ID,
+							})
+						}
+						return deleted, response.Meta.Page.HasMore, nil
+					})
+			case "projects":
+				query.Set("projectStatuses", "deleted")
+				items, truncated, err = helpers.FetchAllPages(ctx, pagination,
+					func(ctx context.Context, page, pageSize int64) ([]trashItem, bool, error) {
+						projectListRequest := projects.NewProjectListRequest()
+						projectListRequest.Filters.Page = page
+						projectListRequest.Filters.PageSize = pageSize
+
+						response, err := executeWithQuery[*projects.ProjectListResponse](ctx, engine, projectListRequest,
+							query)
+						if err != nil {
+							return nil, false, err
+						}
+						deleted := make([]trashItem, 0, len(response.Projects))
+						for _, project := range response.Projects {
+							deleted = append(deleted, trashItem{
+								ID:        project.ID,
+								Name:      project.Name,
+								DeletedAt: project.UpdatedAt,
+								DeletedBy: project.UpdatedBy,
+							})
+						}
+						return deleted, response.Meta.Page.HasMore, nil
+					})
+			}
+			if err != nil {
+				return helpers.HandleAPIError(err, "failed to list trash")
+			}
+
+			encoded, err := json.Marshal(struct {
+				ItemType     string      `json:"itemType"`
+				DeletedAfter time.Time   `json:"deletedAfter"`
+				Items        []trashItem `json:"items"`
+				Truncated    bool        `json:"truncated"`
+			}{
+				ItemType:     itemType,
+				DeletedAfter: deletedAfter.UTC(),
+				Items:        append([]trashItem{}, items...),
+				Truncated:    truncated,
+			})
+			if err != nil {
+				return nil, err
+			}
+			return helpers.NewToolResultText("%s", encoded), nil
+		},
+	}
+}
+
+// TrashRestore restores a deleted task or project in Teamwork.com.
+func TrashRestore(engine *twapi.Engine) toolsets.ToolWrapper {
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name:        string(MethodTrashRestore),
+			Description: "Restore a deleted task or project in Teamwork.com from the trash. " + trashDescription,
+			Annotations: &mcp.ToolAnnotations{
+				Title: "Restore From Trash",
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"item_type": {
+						Type:        "string",
+						Description: "The type of the deleted item.",
+						Enum:        enumSchemaValues(trashItemTypes),
+					},
+					"id": {
+						Type:        "integer",
+						Description: "The ID of the deleted item.",
+					},
+				},
+				Required: []string{"item_type", "id"},
+			},
+		},
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var restoreRequest trashRestoreRequest
+
+			var arguments map[string]any
+			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("failed to decode request: %s", err.Error())), nil
+			}
+			err := helpers.ParamGroup(arguments,
+				helpers.RequiredParam(&restoreRequest.ItemType, "item_type", helpers.RestrictValues(trashItemTypes...)),
+				helpers.RequiredNumericParam(&restoreRequest.ID, "id"),
+			)
+			if err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
+			}
+
+			_, err = twapi.Execute[trashRestoreRequest, *trashRestoreResponse](ctx, engine, restoreRequest)
+			if err != nil {
+				return helpers.HandleAPIError(err, "failed to restore item")
+			}
+			return helpers.NewToolResultText("Item restored successfully"), nil
+		},
+	}
+}
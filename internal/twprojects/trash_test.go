@@ -0,0 +1,79 @@
+package twprojects_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/testutil"
+	"github.com/teamwork/mcp/internal/twprojects"
+)
+
+func TestTrashList(t *testing.T) {
+	mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(r *http.Request) (int, []byte) {
+		if r.URL.Path != "/projects/api/v3/projects/123/tasks.json" {
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+		query := r.URL.Query()
+		if query.Get("showDeleted") != "true" || query.Get("updatedAfter") != "2025-01-01T00:00:00Z" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		return http.StatusOK, []byte(`{"tasks":[` +
+			`{"id":1,"name":"Deleted","status":"deleted","updatedAt":"2025-01-02T00:00:00Z","updatedBy":7,` +
+			`"tasklist":{"id":5}},` +
+			`{"id":2,"name":"Open","status":"new","updatedAt":"2025-01-03T00:00:00Z","tasklist":{"id":5}}],` +
+			`"meta":{"page":{"hasMore":false}}}`)
+	})
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTrashList.String(), map[string]any{
+		"item_type":     "tasks",
+		"project_id":    float64(123),
+		"deleted_after": "2025-01-01T00:00:00Z",
+	}, testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+		toolResult, ok := result.(*mcp.CallToolResult)
+		if !ok {
+			t.Fatalf("unexpected result type: %T", result)
+		}
+		if toolResult.IsError {
+			t.Fatalf("tool failed to execute: %v", toolResult.Content)
+		}
+		text := toolResult.Content[0].(*mcp.TextContent).Text
+		expected := `{"itemType":"tasks","deletedAfter":"2025-01-01T00:00:00Z","items":[{"id":1,"name":"Deleted",` +
+			`"deletedAt":"2025-01-02T00:00:00Z","deletedBy":7,"tasklistId":5}],"truncated":false}`
+		if text != expected {
+			t.Errorf("expected %s, got %s", expected, text)
+		}
+	}))
+}
+
+func TestTrashRestore(t *testing.T) {
+	mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(r *http.Request) (int, []byte) {
+		if r.Method != http.MethodPut || r.URL.Path != "/projects/api/v3/projects/123/restore.json" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		return http.StatusNoContent, nil
+	})
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTrashRestore.String(), map[string]any{
+		"item_type": "projects",
+		"id":        float64(123),
+	})
+}
+
+func TestTaskDeleteRestoreHint(t *testing.T) {
+	mcpServer := mcpServerMock(t, http.StatusOK, []byte(`{}`))
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTaskDelete.String(), map[string]any{
+		"id": float64(123),
+	}, testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+		toolResult, ok := result.(*mcp.CallToolResult)
+		if !ok {
+			t.Fatalf("unexpected result type: %T", result)
+		}
+		if toolResult.IsError || len(toolResult.Content) != 2 {
+			t.Fatalf("unexpected result: %v", toolResult.Content)
+		}
+		text := toolResult.Content[1].(*mcp.TextContent).Text
+		if !strings.Contains(text, twprojects.MethodTrashRestore.String()) || !strings.Contains(text, "id 123") {
+			t.Errorf("unexpected restore instructions %s", text)
+		}
+	}))
+}
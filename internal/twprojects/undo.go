@@ -0,0 +1,352 @@
+package twprojects
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/helpers"
+	"github.com/teamwork/mcp/internal/toolsets"
+	twapi "github.com/teamwork/twapi-go-sdk"
+	"github.com/teamwork/twapi-go-sdk/projects"
+)
+
+// List of methods available in the Teamwork.com MCP service.
+//
+// The naming convention for methods follows a pattern described here:
+// https://github.com/github/github-mcp-server/issues/333
+const (
+	MethodUndoLast toolsets.Method = "twprojects-undo_last"
+)
+
+// undoMaxEntries is the maximum number of reversible writes kept per session.
+// Older writes can't be reverted anymore.
+const undoMaxEntries = 50
+
+// reCreatedID extracts the ID from the result of the create tools.
+var reCreatedID = regexp.MustCompile(`created successfully with ID (\d+)`)
+
+func init() {
+	// register the toolset methods
+	toolsets.RegisterMethod(MethodUndoLast)
+}
+
+// undoEntry is the inverse of a write operation.
+type undoEntry struct {
+	description string
+	method      toolsets.Method
+	arguments   map[string]any
+}
+
+type undoingKey struct{}
+
+// isUndoing checks if the write is the inverse of a previous one, which isn't
+// recorded in the journal.
+func isUndoing(ctx context.Context) bool {
+	undoing, _ := ctx.Value(undoingKey{}).(bool)
+	return undoing
+}
+
+// undoJournal records the inverse of the writes performed in each session, so
+// the most recent mutation can be reverted. The creations are only reverted
+// when the delete tools are allowed, as their inverse is a deletion.
+type undoJournal struct {
+	engine      *twapi.Engine
+	allowDelete bool
+	sessions    *sessionStore[[]undoEntry]
+}
+
+func newUndoJournal(engine *twapi.Engine, allowDelete bool) *undoJournal {
+	return &undoJournal{
+		engine:      engine,
+		allowDelete: allowDelete,
+		sessions:    newSessionStore[[]undoEntry](),
+	}
+}
+
+func (j *undoJournal) push(key string, entry undoEntry) {
+	j.sessions.update(key, func(entries *[]undoEntry) {
+		*entries = append(*entries, entry)
+		if len(*entries) > undoMaxEntries {
+			*entries = slices.Delete(*entries, 0, len(*entries)-undoMaxEntries)
+		}
+	})
+}
+
+func (j *undoJournal) pop(key string) (undoEntry, bool) {
+	var entry undoEntry
+	var ok bool
+	j.sessions.update(key, func(entries *[]undoEntry) {
+		if len(*entries) == 0 {
+			return
+		}
+		entry, ok = (*entries)[len(*entries)-1], true
+		*entries = (*entries)[:len(*entries)-1]
+	})
+	return entry, ok
+}
+
+// record wraps the write tools with an inverse operation, so their changes are
+// recorded in the journal. Tools without an inverse are returned unchanged.
+func (j *undoJournal) record(tools []toolsets.ToolWrapper) []toolsets.ToolWrapper {
+	deleteMethods := map[toolsets.Method]toolsets.Method{
+		MethodProjectCreate:  MethodProjectDelete,
+		MethodTasklistCreate: MethodTasklistDelete,
+		// tasklists created from templates are deleted with all their tasks
+		MethodTasklistTemplateApply: MethodTasklistDelete,
+		MethodTaskCreate:            MethodTaskDelete,
+		MethodMilestoneCreate:       MethodMilestoneDelete,
+		MethodCompanyCreate:         MethodCompanyDelete,
+		MethodTagCreate:             MethodTagDelete,
+		MethodTeamCreate:            MethodTeamDelete,
+		MethodCommentCreate:         MethodCommentDelete,
+		MethodTimelogCreate:         MethodTimelogDelete,
+		MethodTimerCreate:           MethodTimerDelete,
+		MethodNotebookCreate:        MethodNotebookDelete,
+		// cloned projects are deleted with all their copied content
+		MethodProjectClone: MethodProjectDelete,
+		// bootstrapped projects are deleted with all their created content
+		MethodProjectBootstrap: MethodProjectDelete,
+	}
+	if !j.allowDelete {
+		clear(deleteMethods)
+	}
+
+	return toolsets.WrapToolList(tools, func(tool toolsets.ToolWrapper) toolsets.ToolWrapper {
+		method := toolsets.Method(tool.Tool.Name)
+		switch {
+		case method == MethodTaskUpdate:
+			tool.Handler = j.recordTaskUpdate(tool.Handler)
+		case deleteMethods[method] != "":
+			tool.Handler = j.recordCreate(tool.Handler, deleteMethods[method])
+		}
+		return tool
+	})
+}
+
+// recordCreate records the deletion of the created entity as the inverse.
+func (j *undoJournal) recordCreate(handler mcp.ToolHandler, deleteMethod toolsets.Method) mcp.ToolHandler {
+	return func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := handler(ctx, request)
+		if err != nil || result == nil || result.IsError || isUndoing(ctx) {
+			return result, err
+		}
+		key, ok := sessionKey(ctx, request)
+		if !ok {
+			return result, err
+		}
+		for _, content := range result.Content {
+			text, ok := content.(*mcp.TextContent)
+			if !ok {
+				continue
+			}
+			matches := reCreatedID.FindStringSubmatch(text.Text)
+			if len(matches) < 2 {
+				continue
+			}
+			id, parseErr := strconv.ParseInt(matches[1], 10, 64)
+			if parseErr != nil {
+				continue
+			}
+			j.push(key, undoEntry{
+				description: fmt.Sprintf("%s (deleted entity %d)", request.Params.Name, id),
+				method:      deleteMethod,
+				arguments:   map[string]any{"id": float64(id)},
+			})
+		}
+		return result, err
+	}
+}
+
+// recordTaskUpdate loads the task before the update, recording the previous
+// values of the updated fields as the inverse.
+func (j *undoJournal) recordTaskUpdate(handler mcp.ToolHandler) mcp.ToolHandler {
+	return func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		key, ok := sessionKey(ctx, request)
+		if !ok || isUndoing(ctx) {
+			return handler(ctx, request)
+		}
+
+		var arguments map[string]any
+		if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+			return handler(ctx, request)
+		}
+		var taskID int64
+		if err := helpers.ParamGroup(arguments, helpers.RequiredNumericParam(&taskID, "id")); err != nil {
+			return handler(ctx, request)
+		}
+
+		var entry *undoEntry
+		if taskResponse, err := projects.TaskGet(ctx, j.engine, projects.NewTaskGetRequest(taskID)); err == nil {
+			previous, skipped := previousTaskValues(taskResponse.Task, arguments)
+			description := fmt.Sprintf("%s (restored task %d)", request.Params.Name, taskID)
+			if len(skipped) > 0 {
+				description += fmt.Sprintf("; fields that were empty were not cleared: %s", strings.Join(skipped, ", "))
+			}
+			entry = &undoEntry{
+				description: description,
+				method:      MethodTaskUpdate,
+				arguments:   previous,
+			}
+		}
+
+		result, err := handler(ctx, request)
+		if err != nil || result == nil || result.IsError || entry == nil {
+			return result, err
+		}
+		j.push(key, *entry)
+		return result, err
+	}
+}
+
+// previousTaskValues builds the task update arguments that restore the fields
+// present in the update arguments. Fields without a previous value can't be
+// cleared through the update tool, so they are returned as skipped.
+func previousTaskValues(task projects.Task, arguments map[string]any) (map[string]any, []string) {
+	previous := map[string]any{"id": float64(task.ID)}
+	var skipped []string
+
+	for field := range arguments {
+		switch field {
+		case "name":
+			previous[field] = task.Name
+		case "description":
+			if task.Description != nil {
+				previous[field] = *task.Description
+			} else {
+				previous[field] = ""
+			}
+		case "priority":
+			if task.Priority != nil && slices.Contains([]string{"low", "medium", "high"}, *task.Priority) {
+				previous[field] = *task.Priority
+			} else {
+				skipped = append(skipped, field)
+			}
+		case "progress":
+			previous[field] = float64(task.Progress)
+		case "start_date":
+			if task.StartAt != nil {
+				previous[field] = task.StartAt.Format(time.DateOnly)
+			} else {
+				skipped = append(skipped, field)
+			}
+		case "due_date":
+			if task.DueAt != nil {
+				previous[field] = task.DueAt.Format(time.DateOnly)
+			} else {
+				skipped = append(skipped, field)
+			}
+		case "estimated_minutes":
+			previous[field] = float64(task.EstimatedMinutes)
+		case "tasklist_id":
+			previous[field] = float64(task.Tasklist.ID)
+		case "parent_task_id":
+			if task.ParentTask != nil {
+				previous[field] = float64(task.ParentTask.ID)
+			} else {
+				skipped = append(skipped, field)
+			}
+		case "tag_ids":
+			if len(task.Tags) > 0 {
+				tagIDs := make([]any, len(task.Tags))
+				for i, tag := range task.Tags {
+					tagIDs[i] = float64(tag.ID)
+				}
+				previous[field] = tagIDs
+			} else {
+				skipped = append(skipped, field)
+			}
+		case "assignees":
+			assignees := make(map[string]any)
+			for _, assignee := range task.Assignees {
+				var group string
+				switch assignee.Type {
+				case "users":
+					group = "user_ids"
+				case "companies":
+					group = "company_ids"
+				case "teams":
+					group = "team_ids"
+				default:
+					continue
+				}
+				ids, _ := assignees[group].([]any)
+				assignees[group] = append(ids, float64(assignee.ID))
+			}
+			if len(assignees) > 0 {
+				previous[field] = assignees
+			} else {
+				skipped = append(skipped, field)
+			}
+		case "predecessors":
+			skipped = append(skipped, field)
+		}
+	}
+	slices.Sort(skipped)
+	return previous, skipped
+}
+
+// UndoLast reverts the most recent write performed in the session. The inverse
+// operations call the given tools, which must be the fully wrapped write tools,
+// so the reverts are checked like any other write (e.g. against the allowed
+// projects).
+func UndoLast(journal *undoJournal, tools []toolsets.ToolWrapper) toolsets.ToolWrapper {
+	handlers := make(map[toolsets.Method]mcp.ToolHandler, len(tools))
+	for _, tool := range tools {
+		handlers[toolsets.Method(tool.Tool.Name)] = tool.Handler
+	}
+
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name: string(MethodUndoLast),
+			Description: "Revert the most recent change performed in this session, such as restoring the previous values " +
+				"of an updated task or, when deleting is allowed, deleting a task that was just created. Each call " +
+				"reverts one more change. Use it when a previous action was a mistake.",
+			Annotations: &mcp.ToolAnnotations{
+				Title: "Undo Last Change",
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:       "object",
+				Properties: map[string]*jsonschema.Schema{},
+			},
+		},
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			key, ok := sessionKey(ctx, request)
+			if !ok {
+				return helpers.NewToolResultTextError("undo is not available without a session"), nil
+			}
+			entry, ok := journal.pop(key)
+			if !ok {
+				return helpers.NewToolResultTextError("there are no changes to undo in this session"), nil
+			}
+
+			handler, ok := handlers[entry.method]
+			if !ok {
+				return helpers.NewToolResultTextError(fmt.Sprintf("%s can't be reverted, as %s isn't available",
+					entry.description, entry.method)), nil
+			}
+			undoRequest, err := withArguments(&mcp.CallToolRequest{
+				Session: request.Session,
+				Params:  &mcp.CallToolParamsRaw{Name: entry.method.String()},
+				Extra:   request.Extra,
+			}, entry.arguments)
+			if err != nil {
+				return nil, err
+			}
+			result, err := handler(context.WithValue(ctx, undoingKey{}, true), undoRequest)
+			if err != nil || result == nil || result.IsError {
+				// keep the change in the journal, so it can be retried
+				journal.push(key, entry)
+				return result, err
+			}
+			return helpers.NewToolResultText("Reverted %s", entry.description), nil
+		},
+	}
+}
@@ -0,0 +1,164 @@
+package twprojects_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/config"
+	"github.com/teamwork/mcp/internal/testutil"
+	"github.com/teamwork/mcp/internal/toolsets"
+	"github.com/teamwork/mcp/internal/twprojects"
+)
+
+func TestUndoLast(t *testing.T) {
+	mcpServer := mcpServerMock(t, http.StatusOK, []byte(`{"task":{"id":123,"name":"Old name",`+
+		`"tasklist":{"id":456,"type":"tasklists"}}}`))
+
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTaskUpdate.String(), map[string]any{
+		"id":       float64(123),
+		"name":     "New name",
+		"due_date": "2023-10-15",
+	})
+
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodUndoLast.String(), map[string]any{},
+		testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+			toolResult, ok := result.(*mcp.CallToolResult)
+			if !ok {
+				t.Fatalf("unexpected result type: %T", result)
+			}
+			if toolResult.IsError {
+				t.Fatalf("tool failed to execute: %v", toolResult.Content)
+			}
+			text := toolResult.Content[0].(*mcp.TextContent).Text
+			if !strings.Contains(text, "restored task 123") || !strings.Contains(text, "due_date") {
+				t.Errorf("unexpected result: %s", text)
+			}
+		}),
+	)
+
+	// the journal is empty after reverting the only change
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodUndoLast.String(), map[string]any{},
+		testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+			toolResult, ok := result.(*mcp.CallToolResult)
+			if !ok {
+				t.Fatalf("unexpected result type: %T", result)
+			}
+			if !toolResult.IsError {
+				t.Errorf("expected error when there are no changes to undo")
+			}
+		}),
+	)
+}
+
+func TestUndoLastSTDIO(t *testing.T) {
+	mcpServer := mcpServerMock(t, http.StatusOK, []byte(`{"task":{"id":123,"name":"Old name",`+
+		`"tasklist":{"id":456,"type":"tasklists"}}}`))
+
+	// STDIO sessions have no ID and are identified by the installation
+	ctx := config.WithCustomerURL(t.Context(), "https://example.teamwork.com")
+
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTaskUpdate.String(), map[string]any{
+		"id":   float64(123),
+		"name": "New name",
+	}, testutil.ExecuteToolRequestWithContext(ctx))
+
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodUndoLast.String(), map[string]any{},
+		testutil.ExecuteToolRequestWithContext(ctx),
+		testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+			toolResult, ok := result.(*mcp.CallToolResult)
+			if !ok {
+				t.Fatalf("unexpected result type: %T", result)
+			}
+			if toolResult.IsError {
+				t.Fatalf("tool failed to execute: %v", toolResult.Content)
+			}
+			text := toolResult.Content[0].(*mcp.TextContent).Text
+			if !strings.Contains(text, "restored task 123") {
+				t.Errorf("unexpected result: %s", text)
+			}
+		}),
+	)
+}
+
+func TestUndoLastWithoutDelete(t *testing.T) {
+	var deleted bool
+	engine := testutil.ProjectsEngineMockFunc(func(r *http.Request) (int, []byte) {
+		if r.Method == http.MethodDelete {
+			deleted = true
+			return http.StatusNoContent, nil
+		}
+		return http.StatusCreated, []byte(`{"task":{"id":123}}`)
+	})
+	group := twprojects.DefaultToolsetGroup(false, false, engine)
+	if err := group.EnableToolsets(toolsets.MethodAll); err != nil {
+		t.Fatalf("failed to enable toolsets: %v", err)
+	}
+	mcpServer := mcp.NewServer(&mcp.Implementation{Name: "test-server", Version: "1.0.0"}, &mcp.ServerOptions{})
+	group.RegisterAll(mcpServer)
+
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTaskCreate.String(), map[string]any{
+		"name":        "Example",
+		"tasklist_id": float64(456),
+	})
+
+	// creations can't be reverted, as their inverse is a deletion
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodUndoLast.String(), map[string]any{},
+		testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+			toolResult, ok := result.(*mcp.CallToolResult)
+			if !ok {
+				t.Fatalf("unexpected result type: %T", result)
+			}
+			if !toolResult.IsError {
+				t.Errorf("expected error when there are no changes to undo")
+			}
+		}),
+	)
+	if deleted {
+		t.Error("unexpected delete request")
+	}
+}
+
+func TestUndoLastSandbox(t *testing.T) {
+	// the task is moved to a project outside the sandbox after the update
+	var moved, restored bool
+	mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(r *http.Request) (int, []byte) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/projects/api/v3/tasks/123.json":
+			if moved {
+				return http.StatusOK, []byte(`{"task":{"id":123,"name":"New name","tasklist":{"id":789}}}`)
+			}
+			return http.StatusOK, []byte(`{"task":{"id":123,"name":"Old name","tasklist":{"id":456}}}`)
+		case r.URL.Path == "/projects/api/v3/tasklists/456.json":
+			return http.StatusOK, []byte(`{"tasklist":{"id":456,"project":{"id":3}}}`)
+		case r.URL.Path == "/projects/api/v3/tasklists/789.json":
+			return http.StatusOK, []byte(`{"tasklist":{"id":789,"project":{"id":4}}}`)
+		case r.Method != http.MethodGet:
+			restored = moved
+			return http.StatusOK, []byte(`{"task":{"id":123}}`)
+		}
+		return http.StatusOK, []byte(`{}`)
+	}, twprojects.WithAllowedProjects([]int64{3}))
+
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTaskUpdate.String(), map[string]any{
+		"id":   float64(123),
+		"name": "New name",
+	})
+	moved = true
+
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodUndoLast.String(), map[string]any{},
+		testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+			toolResult, ok := result.(*mcp.CallToolResult)
+			if !ok {
+				t.Fatalf("unexpected result type: %T", result)
+			}
+			if !toolResult.IsError {
+				t.Errorf("expected the undo outside the sandbox to fail, got %v", toolResult.Content)
+			}
+		}),
+	)
+	if restored {
+		t.Error("unexpected update of a task outside the sandbox")
+	}
+}
@@ -0,0 +1,281 @@
+package twprojects
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/helpers"
+	"github.com/teamwork/mcp/internal/toolsets"
+	twapi "github.com/teamwork/twapi-go-sdk"
+	"github.com/teamwork/twapi-go-sdk/projects"
+)
+
+// List of methods available in the Teamwork.com MCP service.
+//
+// The naming convention for methods follows a pattern described here:
+// https://github.com/github/github-mcp-server/issues/333
+const (
+	MethodUserImport toolsets.Method = "twprojects-import_users"
+)
+
+// userImportMaxUsers is the maximum number of users imported in a single call.
+const userImportMaxUsers = 100
+
+func init() {
+	// register the toolset methods
+	toolsets.RegisterMethod(MethodUserImport)
+}
+
+// userImportRequest represents the request to create a user, choosing whether
+// the invitation email is sent.
+type userImportRequest struct {
+	projects.UserCreateRequest
+
+	SendInvite bool `json:"sendInvite"`
+}
+
+// HTTPRequest creates an HTTP request for the userImportRequest.
+func (u userImportRequest) HTTPRequest(ctx context.Context, server string) (*http.Request, error) {
+	uri := server + "/people.json"
+
+	payload := struct {
+		User userImportRequest `json:"person"`
+	}{User: u}
+
+	var body bytes.Buffer
+	if err := json.NewEncoder(&body).Encode(payload); err != nil {
+		return nil, fmt.Errorf("failed to encode create user request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, uri, &body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+// userImportRow is a user to import.
+type userImportRow struct {
+	FirstName   string  `json:"first_name"`
+	LastName    string  `json:"last_name"`
+	Email       string  `json:"email"`
+	Title       *string `json:"title"`
+	Type        *string `json:"type"`
+	CompanyID   *int64  `json:"company_id"`
+	CompanyName string  `json:"company_name"`
+}
+
+// userImportRowResult is the result of importing a user.
+type userImportRowResult struct {
+	Row    int    `json:"row"`
+	Email  string `json:"email"`
+	UserID int64  `json:"userId,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// userImportResult is the result of the user import.
+type userImportResult struct {
+	Imported int                   `json:"imported"`
+	Failed   int                   `json:"failed"`
+	Results  []userImportRowResult `json:"results"`
+}
+
+// userImportCompanies resolves the company names of the imported users,
+// caching the companies already found.
+type userImportCompanies struct {
+	engine *twapi.Engine
+	ids    map[string]int64
+}
+
+// resolve returns the ID of the company with the name, ignoring the case.
+func (c *userImportCompanies) resolve(ctx context.Context, name string) (int64, error) {
+	key := strings.ToLower(name)
+	if id, ok := c.ids[key]; ok {
+		return id, nil
+	}
+	companyListRequest := projects.NewCompanyListRequest()
+	companyListRequest.Filters.SearchTerm = name
+	response, err := projects.CompanyList(ctx, c.engine, companyListRequest)
+	if err != nil {
+		return 0, fmt.Errorf("failed to search company %q: %w", name, err)
+	}
+	index := slices.IndexFunc(response.Companies, func(company projects.Company) bool {
+		return strings.EqualFold(company.Name, name)
+	})
+	if index == -1 {
+		return 0, fmt.Errorf("company %q not found", name)
+	}
+	c.ids[key] = response.Companies[index].ID
+	return c.ids[key], nil
+}
+
+// UserImport creates many users in Teamwork.com in a single call, reporting
+// the result of each one.
+func UserImport(engine *twapi.Engine) toolsets.ToolWrapper {
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name: string(MethodUserImport),
+			Description: "Create many users in Teamwork.com in a single call, e.g. when onboarding the team of a new " +
+				"client. Each user is created independently and the result of each row is reported, so a failing row " +
+				"doesn't stop the import. The invitation emails can be suppressed. " + userDescription,
+			Annotations: &mcp.ToolAnnotations{
+				Title: "Import Users",
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"users": {
+						Type:        "array",
+						Description: "The users to create.",
+						MinItems:    twapi.Ptr(1),
+						MaxItems:    twapi.Ptr(userImportMaxUsers),
+						Items: &jsonschema.Schema{
+							Type: "object",
+							Properties: map[string]*jsonschema.Schema{
+								"first_name": {
+									Type:        "string",
+									Description: "The first name of the user.",
+								},
+								"last_name": {
+									Type:        "string",
+									Description: "The last name of the user.",
+								},
+								"email": {
+									Type:        "string",
+									Description: "The email address of the user.",
+								},
+								"title": {
+									Type:        "string",
+									Description: "The job title of the user.",
+								},
+								"type": {
+									Type:        "string",
+									Description: "The type of user, such as 'account', 'collaborator', or 'contact'.",
+									Enum:        enumSchemaValues(userTypes),
+								},
+								"company_id": {
+									Type:        "integer",
+									Description: "The ID of the client/company of the user. Overrides the default company_id.",
+								},
+								"company_name": {
+									Type: "string",
+									Description: "The name of the client/company of the user, used when the ID isn't known. " +
+										"It must match an existing company.",
+								},
+							},
+							Required: []string{"first_name", "last_name", "email"},
+						},
+					},
+					"company_id": {
+						Type:        "integer",
+						Description: "The ID of the client/company of the users without a company.",
+					},
+					"send_invite": {
+						Type:        "boolean",
+						Description: "Whether the users receive the invitation email. Defaults to true.",
+					},
+				},
+				Required: []string{"users"},
+			},
+		},
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var params struct {
+				Users      []userImportRow `json:"users"`
+				CompanyID  *int64          `json:"company_id"`
+				SendInvite *bool           `json:"send_invite"`
+			}
+			if err := json.Unmarshal(request.Params.Arguments, &params); err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("failed to decode request: %s", err.Error())), nil
+			}
+			if len(params.Users) == 0 {
+				return helpers.NewToolResultTextError("invalid parameters: parameter users is required"), nil
+			}
+			if len(params.Users) > userImportMaxUsers {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: at most %d users are allowed",
+					userImportMaxUsers)), nil
+			}
+			sendInvite := params.SendInvite == nil || *params.SendInvite
+
+			companies := userImportCompanies{engine: engine, ids: make(map[string]int64)}
+			emails := make(map[string]struct{}, len(params.Users))
+			result := userImportResult{Results: make([]userImportRowResult, len(params.Users))}
+			for i, row := range params.Users {
+				rowResult := &result.Results[i]
+				rowResult.Row = i + 1
+				rowResult.Email = row.Email
+
+				userID, err := importUser(ctx, engine, &companies, emails, row, params.CompanyID, sendInvite)
+				if err != nil {
+					rowResult.Error = err.Error()
+					result.Failed++
+					continue
+				}
+				rowResult.UserID = userID
+				result.Imported++
+			}
+
+			encoded, err := json.Marshal(result)
+			if err != nil {
+				return nil, err
+			}
+			return helpers.NewToolResultText("%s", encoded), nil
+		},
+	}
+}
+
+// importUser validates and creates a user of the import. The emails already
+// imported in the call are rejected.
+func importUser(
+	ctx context.Context,
+	engine *twapi.Engine,
+	companies *userImportCompanies,
+	emails map[string]struct{},
+	row userImportRow,
+	defaultCompanyID *int64,
+	sendInvite bool,
+) (int64, error) {
+	if row.FirstName == "" || row.LastName == "" || row.Email == "" {
+		return 0, fmt.Errorf("first_name, last_name and email are required")
+	}
+	if row.Type != nil && !slices.Contains(userTypes, *row.Type) {
+		return 0, fmt.Errorf("invalid type %q", *row.Type)
+	}
+	email := strings.ToLower(row.Email)
+	if _, ok := emails[email]; ok {
+		return 0, fmt.Errorf("duplicated email")
+	}
+	emails[email] = struct{}{}
+
+	userCreateRequest := projects.NewUserCreateRequest(row.FirstName, row.LastName, row.Email)
+	userCreateRequest.Title = row.Title
+	userCreateRequest.Type = row.Type
+	switch {
+	case row.CompanyID != nil:
+		userCreateRequest.CompanyID = row.CompanyID
+	case row.CompanyName != "":
+		companyID, err := companies.resolve(ctx, row.CompanyName)
+		if err != nil {
+			return 0, err
+		}
+		userCreateRequest.CompanyID = &companyID
+	default:
+		userCreateRequest.CompanyID = defaultCompanyID
+	}
+
+	response, err := twapi.Execute[userImportRequest, *projects.UserCreateResponse](ctx, engine, userImportRequest{
+		UserCreateRequest: userCreateRequest,
+		SendInvite:        sendInvite,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int64(response.ID), nil
+}
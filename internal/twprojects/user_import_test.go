@@ -0,0 +1,77 @@
+package twprojects_test
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/testutil"
+	"github.com/teamwork/mcp/internal/twprojects"
+)
+
+func TestUserImport(t *testing.T) {
+	var bodies []string
+	mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(r *http.Request) (int, []byte) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /projects/api/v3/companies.json":
+			return http.StatusOK, []byte(`{"companies":[{"id":4,"name":"Acme Inc"},{"id":5,"name":"ACME"}],` +
+				`"meta":{"page":{"hasMore":false}}}`)
+		case "POST /people.json":
+			content, err := io.ReadAll(r.Body)
+			if err != nil {
+				t.Fatalf("failed to read request body: %v", err)
+			}
+			bodies = append(bodies, strings.TrimSpace(string(content)))
+			if strings.Contains(string(content), "taken@example.com") {
+				return http.StatusUnprocessableEntity, []byte(`{"MESSAGE":"email already in use"}`)
+			}
+			return http.StatusCreated, []byte(`{"id":"` + strconv.Itoa(len(bodies)) + `"}`)
+		}
+		t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		return http.StatusNotFound, nil
+	})
+
+	executePreviewedToolRequest(t, mcpServer, twprojects.MethodUserImport.String(), map[string]any{
+		"users": []any{
+			map[string]any{"first_name": "Ada", "last_name": "Lovelace", "email": "ada@example.com"},
+			map[string]any{"first_name": "Alan", "last_name": "Turing", "email": "alan@example.com",
+				"type": "collaborator", "company_name": "acme"},
+			map[string]any{"first_name": "Ada", "last_name": "Again", "email": "ADA@example.com"},
+			map[string]any{"first_name": "Grace", "last_name": "Hopper", "email": "taken@example.com"},
+		},
+		"company_id":  float64(3),
+		"send_invite": false,
+	}, testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+		toolResult, ok := result.(*mcp.CallToolResult)
+		if !ok {
+			t.Fatalf("unexpected result type: %T", result)
+		}
+		if toolResult.IsError {
+			t.Fatalf("tool failed to execute: %v", toolResult.Content)
+		}
+		text := toolResult.Content[0].(*mcp.TextContent).Text
+		for _, fragment := range []string{
+			`"imported":2,"failed":2`,
+			`{"row":1,"email":"ada@example.com","userId":1}`,
+			`{"row":2,"email":"alan@example.com","userId":2}`,
+			`{"row":3,"email":"ADA@example.com","error":"duplicated email"}`,
+			`{"row":4,"email":"taken@example.com","error":`,
+		} {
+			if !strings.Contains(text, fragment) {
+				t.Errorf("expected result to contain %s, got %s", fragment, text)
+			}
+		}
+	}))
+
+	if len(bodies) != 3 {
+		t.Fatalf("expected 3 created users, got %d", len(bodies))
+	}
+	for i, fragment := range []string{`"company-id":3`, `"company-id":5`} {
+		if !strings.Contains(bodies[i], fragment) || !strings.Contains(bodies[i], `"sendInvite":false`) {
+			t.Errorf("expected body %d to contain %s and sendInvite false, got %s", i, fragment, bodies[i])
+		}
+	}
+}
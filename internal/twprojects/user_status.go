@@ -0,0 +1,201 @@
+package twprojects
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/helpers"
+	"github.com/teamwork/mcp/internal/toolsets"
+	twapi "github.com/teamwork/twapi-go-sdk"
+)
+
+// List of methods available in the Teamwork.com MCP service.
+//
+// The naming convention for methods follows a pattern described here:
+// https://github.com/github/github-mcp-server/issues/333
+const (
+	MethodUserDeactivate toolsets.Method = "twprojects-deactivate_user"
+	MethodUserReactivate toolsets.Method = "twprojects-reactivate_user"
+)
+
+const userStatusDescription = "A deactivated user keeps their history, such as the time logged and the comments, " +
+	"but can't log in and doesn't count as a seat of the site. Unlike deleting, it can be reverted by reactivating " +
+	"the user."
+
+func init() {
+	// register the toolset methods
+	toolsets.RegisterMethod(MethodUserDeactivate)
+	toolsets.RegisterMethod(MethodUserReactivate)
+}
+
+// userStatusRequest represents the request to deactivate or reactivate a user.
+// When deactivating, the open tasks of the user can be reassigned to another
+// user in the same operation.
+type userStatusRequest struct {
+	UserID     int64 `json:"-"`
+	Deactivate bool  `json:"-"`
+
+	ReassignToUserID *int64  `json:"reassignToUserId,omitempty"`
+	ProjectIDs       []int64 `json:"projectIds,omitempty"`
+}
+
+// HTTPRequest creates an HTTP request for the userStatusRequest.
+func (u userStatusRequest) HTTPRequest(ctx context.Context, server string) (*http.Request, error) {
+	action := "reactivate"
+	if u.Deactivate {
+		action = "deactivate"
+	}
+	uri := server + "/projects/api/v3/people/" + strconv.FormatInt(u.UserID, 10) + "/" + action + ".json"
+
+	var body bytes.Buffer
+	if err := json.NewEncoder(&body).Encode(u); err != nil {
+		return nil, fmt.Errorf("failed to encode %s user request: %w", action, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, uri, &body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+// userStatusResponse represents the response of deactivating or reactivating a
+// user.
+type userStatusResponse struct {
+	ReassignedTasks int64 `json:"reassignedTasks"`
+}
+
+// HandleHTTPResponse handles the HTTP response for the userStatusResponse. If
+// some unexpected HTTP status code is returned by the API, a twapi.HTTPError is
+// returned.
+func (u *userStatusResponse) HandleHTTPResponse(resp *http.Response) error {
+	switch resp.StatusCode {
+	case http.StatusOK:
+		if err := json.NewDecoder(resp.Body).Decode(u); err != nil {
+			return fmt.Errorf("failed to decode user status response: %w", err)
+		}
+		return nil
+	case http.StatusNoContent:
+		return nil
+	}
+	return twapi.NewHTTPError(resp, "failed to change user status")
+}
+
+// UserDeactivate deactivates a user in Teamwork.com, optionally reassigning
+// their open tasks to another user.
+func UserDeactivate(engine *twapi.Engine) toolsets.ToolWrapper {
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name: string(MethodUserDeactivate),
+			Description: "Deactivate a user in Teamwork.com, e.g. when off-boarding someone. The open tasks of the user " +
+				"can be reassigned to another user in the same operation, optionally only in some projects, so no work " +
+				"is left without an owner. " + userStatusDescription,
+			Annotations: &mcp.ToolAnnotations{
+				Title: "Deactivate User",
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"id": {
+						Type:        "integer",
+						Description: "The ID of the user to deactivate.",
+					},
+					"reassign_to_user_id": {
+						Type: "integer",
+						Description: "The ID of the user receiving the open tasks of the deactivated user. When not " +
+							"provided, the tasks keep the deactivated user as assignee.",
+					},
+					"project_ids": {
+						Type:        "array",
+						Description: "Only reassign the open tasks of these projects. Requires reassign_to_user_id.",
+						Items: &jsonschema.Schema{
+							Type: "integer",
+						},
+					},
+				},
+				Required: []string{"id"},
+			},
+		},
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			statusRequest := userStatusRequest{Deactivate: true}
+
+			var arguments map[string]any
+			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("failed to decode request: %s", err.Error())), nil
+			}
+			err := helpers.ParamGroup(arguments,
+				helpers.RequiredNumericParam(&statusRequest.UserID, "id"),
+				helpers.OptionalNumericPointerParam(&statusRequest.ReassignToUserID, "reassign_to_user_id"),
+				helpers.OptionalNumericListParam(&statusRequest.ProjectIDs, "project_ids"),
+			)
+			if err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
+			}
+			switch {
+			case statusRequest.ReassignToUserID == nil && len(statusRequest.ProjectIDs) > 0:
+				return helpers.NewToolResultTextError("invalid parameters: project_ids requires reassign_to_user_id"), nil
+			case statusRequest.ReassignToUserID != nil && *statusRequest.ReassignToUserID == statusRequest.UserID:
+				return helpers.NewToolResultTextError("invalid parameters: tasks can't be reassigned to the same user"), nil
+			}
+
+			response, err := twapi.Execute[userStatusRequest, *userStatusResponse](ctx, engine, statusRequest)
+			if err != nil {
+				return helpers.HandleAPIError(err, "failed to deactivate user")
+			}
+			if statusRequest.ReassignToUserID != nil {
+				return helpers.NewToolResultText("User deactivated successfully, %d open tasks reassigned to user %d",
+					response.ReassignedTasks, *statusRequest.ReassignToUserID), nil
+			}
+			return helpers.NewToolResultText("User deactivated successfully"), nil
+		},
+	}
+}
+
+// UserReactivate reactivates a deactivated user in Teamwork.com.
+func UserReactivate(engine *twapi.Engine) toolsets.ToolWrapper {
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name:        string(MethodUserReactivate),
+			Description: "Reactivate a deactivated user in Teamwork.com. " + userStatusDescription,
+			Annotations: &mcp.ToolAnnotations{
+				Title: "Reactivate User",
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"id": {
+						Type:        "integer",
+						Description: "The ID of the user to reactivate.",
+					},
+				},
+				Required: []string{"id"},
+			},
+		},
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var statusRequest userStatusRequest
+
+			var arguments map[string]any
+			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("failed to decode request: %s", err.Error())), nil
+			}
+			err := helpers.ParamGroup(arguments,
+				helpers.RequiredNumericParam(&statusRequest.UserID, "id"),
+			)
+			if err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
+			}
+
+			if _, err := twapi.Execute[userStatusRequest, *userStatusResponse](ctx, engine, statusRequest); err != nil {
+				return helpers.HandleAPIError(err, "failed to reactivate user")
+			}
+			return helpers.NewToolResultText("User reactivated successfully"), nil
+		},
+	}
+}
@@ -0,0 +1,60 @@
+package twprojects_test
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/testutil"
+	"github.com/teamwork/mcp/internal/twprojects"
+)
+
+func TestUserDeactivate(t *testing.T) {
+	var body string
+	mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(r *http.Request) (int, []byte) {
+		if r.Method != http.MethodPut || r.URL.Path != "/projects/api/v3/people/123/deactivate.json" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		content, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("failed to read request body: %v", err)
+		}
+		body = string(content)
+		return http.StatusOK, []byte(`{"reassignedTasks":4}`)
+	})
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodUserDeactivate.String(), map[string]any{
+		"id":                  float64(123),
+		"reassign_to_user_id": float64(456),
+		"project_ids":         []any{float64(1), float64(2)},
+	}, testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+		toolResult, ok := result.(*mcp.CallToolResult)
+		if !ok {
+			t.Fatalf("unexpected result type: %T", result)
+		}
+		if toolResult.IsError {
+			t.Fatalf("tool failed to execute: %v", toolResult.Content)
+		}
+		text := toolResult.Content[0].(*mcp.TextContent).Text
+		if expected := "User deactivated successfully, 4 open tasks reassigned to user 456"; text != expected {
+			t.Errorf("expected %s, got %s", expected, text)
+		}
+	}))
+
+	if expected := `{"reassignToUserId":456,"projectIds":[1,2]}`; strings.TrimSpace(body) != expected {
+		t.Errorf("expected body %s, got %s", expected, body)
+	}
+}
+
+func TestUserReactivate(t *testing.T) {
+	mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(r *http.Request) (int, []byte) {
+		if r.Method != http.MethodPut || r.URL.Path != "/projects/api/v3/people/123/reactivate.json" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		return http.StatusNoContent, nil
+	})
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodUserReactivate.String(), map[string]any{
+		"id": float64(123),
+	})
+}
@@ -4,6 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 
 	"github.com/google/jsonschema-go/jsonschema"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -25,6 +30,7 @@ const (
 	MethodUserGetMe         toolsets.Method = "twprojects-get_user_me"
 	MethodUserList          toolsets.Method = "twprojects-list_users"
 	MethodUserListByProject toolsets.Method = "twprojects-list_users_by_project"
+	MethodCompanyUserList   toolsets.Method = "twprojects-list_company_users"
 )
 
 const userDescription = "A user is an individual who has access to one or more projects within a Teamwork site, " +
@@ -49,6 +55,7 @@ func init() {
 	toolsets.RegisterMethod(MethodUserGetMe)
 	toolsets.RegisterMethod(MethodUserList)
 	toolsets.RegisterMethod(MethodUserListByProject)
+	toolsets.RegisterMethod(MethodCompanyUserList)
 
 	var err error
 
@@ -102,6 +109,7 @@ func UserCreate(engine *twapi.Engine) toolsets.ToolWrapper {
 					"type": {
 						Type:        "string",
 						Description: "The type of user, such as 'account', 'collaborator', or 'contact'.",
+						Enum:        enumSchemaValues(userTypes),
 					},
 					"company_id": {
 						Type:        "integer",
@@ -125,7 +133,7 @@ func UserCreate(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.RequiredParam(&userCreateRequest.Email, "email"),
 				helpers.OptionalPointerParam(&userCreateRequest.Admin, "admin"),
 				helpers.OptionalPointerParam(&userCreateRequest.Type, "type",
-					helpers.RestrictValues("account", "collaborator", "contact"),
+					helpers.RestrictValues(userTypes...),
 				),
 				helpers.OptionalNumericPointerParam(&userCreateRequest.CompanyID, "company_id"),
 			)
@@ -154,6 +162,7 @@ func UserUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 			InputSchema: &jsonschema.Schema{
 				Type: "object",
 				Properties: map[string]*jsonschema.Schema{
+					helpers.ExpectedUpdatedAtParam: helpers.ExpectedUpdatedAtSchema("user"),
 					"id": {
 						Type:        "integer",
 						Description: "The ID of the user to update.",
@@ -181,6 +190,7 @@ func UserUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 					"type": {
 						Type:        "string",
 						Description: "The type of user, such as 'account', 'collaborator', or 'contact'.",
+						Enum:        enumSchemaValues(userTypes),
 					},
 					"company_id": {
 						Type:        "integer",
@@ -192,6 +202,7 @@ func UserUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 		},
 		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			var userUpdateRequest projects.UserUpdateRequest
+			var expectedUpdatedAt *time.Time
 
 			var arguments map[string]any
 			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
@@ -199,13 +210,14 @@ func UserUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 			}
 			err := helpers.ParamGroup(arguments,
 				helpers.RequiredNumericParam(&userUpdateRequest.Path.ID, "id"),
+				helpers.OptionalTimePointerParam(&expectedUpdatedAt, helpers.ExpectedUpdatedAtParam),
 				helpers.OptionalPointerParam(&userUpdateRequest.FirstName, "first_name"),
 				helpers.OptionalPointerParam(&userUpdateRequest.LastName, "last_name"),
 				helpers.OptionalPointerParam(&userUpdateRequest.Title, "title"),
 				helpers.OptionalPointerParam(&userUpdateRequest.Email, "email"),
 				helpers.OptionalPointerParam(&userUpdateRequest.Admin, "admin"),
 				helpers.OptionalPointerParam(&userUpdateRequest.Type, "type",
-					helpers.RestrictValues("account", "collaborator", "contact"),
+					helpers.RestrictValues(userTypes...),
 				),
 				helpers.OptionalNumericPointerParam(&userUpdateRequest.CompanyID, "company_id"),
 			)
@@ -213,6 +225,18 @@ func UserUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
 			}
 
+			if expectedUpdatedAt != nil {
+				userResponse, err := projects.UserGet(ctx, engine, projects.NewUserGetRequest(userUpdateRequest.Path.ID))
+				if err != nil {
+					return helpers.HandleAPIError(err, "failed to get user")
+				}
+				conflict := helpers.CheckConflict("user", userUpdateRequest.Path.ID, *expectedUpdatedAt,
+					userResponse.User.UpdatedAt)
+				if conflict != nil {
+					return conflict, nil
+				}
+			}
+
 			_, err = projects.UserUpdate(ctx, engine, userUpdateRequest)
 			if err != nil {
 				return helpers.HandleAPIError(err, "failed to update user")
@@ -282,6 +306,10 @@ func UserGet(engine *twapi.Engine) toolsets.ToolWrapper {
 						Type:        "integer",
 						Description: "The ID of the user to get.",
 					},
+					"include_avatar": {
+						Type:        "boolean",
+						Description: "If true, the user avatar is returned as an image resource link.",
+					},
 				},
 				Required: []string{"id"},
 			},
@@ -289,6 +317,7 @@ func UserGet(engine *twapi.Engine) toolsets.ToolWrapper {
 		},
 		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			var userGetRequest projects.UserGetRequest
+			var includeAvatar bool
 
 			var arguments map[string]any
 			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
@@ -296,30 +325,37 @@ func UserGet(engine *twapi.Engine) toolsets.ToolWrapper {
 			}
 			err := helpers.ParamGroup(arguments,
 				helpers.RequiredNumericParam(&userGetRequest.Path.ID, "id"),
+				helpers.OptionalParam(&includeAvatar, "include_avatar"),
 			)
 			if err != nil {
 				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
 			}
 
-			user, err := projects.UserGet(ctx, engine, userGetRequest)
+			user, err := twapi.Execute[projects.UserGetRequest, *userGetResponse](ctx, engine, userGetRequest)
 			if err != nil {
 				return helpers.HandleAPIError(err, "failed to get user")
 			}
 
-			encoded, err := json.Marshal(user)
+			encoded, err := json.Marshal(user.UserGetResponse)
 			if err != nil {
 				return nil, err
 			}
-			return &mcp.CallToolResult{
+			linked := helpers.WebLinker(ctx, encoded,
+				helpers.WebLinkerWithIDPathBuilder("/app/people"),
+			)
+			result := &mcp.CallToolResult{
+				Meta: helpers.WebLinkedEntitiesMeta(linked),
 				Content: []mcp.Content{
 					&mcp.TextContent{
-						Text: string(helpers.WebLinker(ctx, encoded,
-							helpers.WebLinkerWithIDPathBuilder("/app/people"),
-						)),
+						Text: string(linked),
 					},
 				},
-				StructuredContent: user,
-			}, nil
+				StructuredContent: user.UserGetResponse,
+			}
+			if includeAvatar && user.avatarURL != "" {
+				result.Content = append(result.Content, helpers.NewImageResourceLink("avatar", "User Avatar", user.avatarURL))
+			}
+			return result, nil
 		},
 	}
 }
@@ -348,12 +384,14 @@ func UserGetMe(engine *twapi.Engine) toolsets.ToolWrapper {
 			if err != nil {
 				return nil, err
 			}
+			linked := helpers.WebLinker(ctx, encoded,
+				helpers.WebLinkerWithIDPathBuilder("/app/people"),
+			)
 			return &mcp.CallToolResult{
+				Meta: helpers.WebLinkedEntitiesMeta(linked),
 				Content: []mcp.Content{
 					&mcp.TextContent{
-						Text: string(helpers.WebLinker(ctx, encoded,
-							helpers.WebLinkerWithIDPathBuilder("/app/people"),
-						)),
+						Text: string(linked),
 					},
 				},
 				StructuredContent: user,
@@ -382,8 +420,9 @@ func UserList(engine *twapi.Engine) toolsets.ToolWrapper {
 							"requiring that the word matches are in the same field.",
 					},
 					"type": {
-						Type:        "integer",
+						Type:        "string",
 						Description: "Type of user to filter by. The available options are account, collaborator or contact.",
+						Enum:        enumSchemaValues(userTypes),
 					},
 					"page": {
 						Type:        "integer",
@@ -393,12 +432,14 @@ func UserList(engine *twapi.Engine) toolsets.ToolWrapper {
 						Type:        "integer",
 						Description: "Number of results per page for pagination.",
 					},
+					"count_only": countOnlySchema(),
 				},
 			},
 			OutputSchema: userListOutputSchema,
 		},
 		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			var userListRequest projects.UserListRequest
+			var countOnly bool
 
 			var arguments map[string]any
 			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
@@ -407,15 +448,20 @@ func UserList(engine *twapi.Engine) toolsets.ToolWrapper {
 			err := helpers.ParamGroup(arguments,
 				helpers.OptionalParam(&userListRequest.Filters.SearchTerm, "search_term"),
 				helpers.OptionalParam(&userListRequest.Filters.Type, "type",
-					helpers.RestrictValues("account", "collaborator", "contact"),
+					helpers.RestrictValues(userTypes...),
 				),
 				helpers.OptionalNumericParam(&userListRequest.Filters.Page, "page"),
 				helpers.OptionalNumericParam(&userListRequest.Filters.PageSize, "page_size"),
+				helpers.OptionalParam(&countOnly, "count_only"),
 			)
 			if err != nil {
 				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
 			}
 
+			if countOnly {
+				return listCount(ctx, engine, userListRequest)
+			}
+
 			userList, err := projects.UserList(ctx, engine, userListRequest)
 			if err != nil {
 				return helpers.HandleAPIError(err, "failed to list users")
@@ -425,12 +471,14 @@ func UserList(engine *twapi.Engine) toolsets.ToolWrapper {
 			if err != nil {
 				return nil, err
 			}
+			linked := helpers.WebLinker(ctx, encoded,
+				helpers.WebLinkerWithIDPathBuilder("/app/people"),
+			)
 			return &mcp.CallToolResult{
+				Meta: helpers.WebLinkedEntitiesMeta(linked),
 				Content: []mcp.Content{
 					&mcp.TextContent{
-						Text: string(helpers.WebLinker(ctx, encoded,
-							helpers.WebLinkerWithIDPathBuilder("/app/people"),
-						)),
+						Text: string(linked),
 					},
 				},
 				StructuredContent: userList,
@@ -463,8 +511,9 @@ func UserListByProject(engine *twapi.Engine) toolsets.ToolWrapper {
 							"requiring that the word matches are in the same field.",
 					},
 					"type": {
-						Type:        "integer",
+						Type:        "string",
 						Description: "Type of user to filter by. The available options are account, collaborator or contact.",
+						Enum:        enumSchemaValues(userTypes),
 					},
 					"page": {
 						Type:        "integer",
@@ -474,6 +523,7 @@ func UserListByProject(engine *twapi.Engine) toolsets.ToolWrapper {
 						Type:        "integer",
 						Description: "Number of results per page for pagination.",
 					},
+					"count_only": countOnlySchema(),
 				},
 				Required: []string{"project_id"},
 			},
@@ -481,6 +531,7 @@ func UserListByProject(engine *twapi.Engine) toolsets.ToolWrapper {
 		},
 		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			var userListRequest projects.UserListRequest
+			var countOnly bool
 
 			var arguments map[string]any
 			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
@@ -490,15 +541,20 @@ func UserListByProject(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.RequiredNumericParam(&userListRequest.Path.ProjectID, "project_id"),
 				helpers.OptionalParam(&userListRequest.Filters.SearchTerm, "search_term"),
 				helpers.OptionalParam(&userListRequest.Filters.Type, "type",
-					helpers.RestrictValues("account", "collaborator", "contact"),
+					helpers.RestrictValues(userTypes...),
 				),
 				helpers.OptionalNumericParam(&userListRequest.Filters.Page, "page"),
 				helpers.OptionalNumericParam(&userListRequest.Filters.PageSize, "page_size"),
+				helpers.OptionalParam(&countOnly, "count_only"),
 			)
 			if err != nil {
 				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
 			}
 
+			if countOnly {
+				return listCount(ctx, engine, userListRequest)
+			}
+
 			userList, err := projects.UserList(ctx, engine, userListRequest)
 			if err != nil {
 				return helpers.HandleAPIError(err, "failed to list users")
@@ -508,12 +564,14 @@ func UserListByProject(engine *twapi.Engine) toolsets.ToolWrapper {
 			if err != nil {
 				return nil, err
 			}
+			linked := helpers.WebLinker(ctx, encoded,
+				helpers.WebLinkerWithIDPathBuilder("/app/people"),
+			)
 			return &mcp.CallToolResult{
+				Meta: helpers.WebLinkedEntitiesMeta(linked),
 				Content: []mcp.Content{
 					&mcp.TextContent{
-						Text: string(helpers.WebLinker(ctx, encoded,
-							helpers.WebLinkerWithIDPathBuilder("/app/people"),
-						)),
+						Text: string(linked),
 					},
 				},
 				StructuredContent: userList,
@@ -521,3 +579,167 @@ func UserListByProject(engine *twapi.Engine) toolsets.ToolWrapper {
 		},
 	}
 }
+
+// CompanyUserList lists the users of a client/company in Teamwork.com.
+func CompanyUserList(engine *twapi.Engine) toolsets.ToolWrapper {
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name: string(MethodCompanyUserList),
+			Description: "List the users (people and contacts) belonging to a client/company in Teamwork.com, with their " +
+				"e-mails, titles and job roles. Use it to find the right contact person of a client. " + userDescription,
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "List Company Users",
+				ReadOnlyHint: true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"company_id": {
+						Type:        "integer",
+						Description: "The ID of the client/company from which to retrieve users.",
+					},
+					"search_term": {
+						Type: "string",
+						Description: "A search term to filter users by first or last names, or e-mail. " +
+							"The user will be selected if each word of the term matches the first or last name, or e-mail, not " +
+							"requiring that the word matches are in the same field.",
+					},
+					"type": {
+						Type:        "string",
+						Description: "Type of user to filter by. The available options are account, collaborator or contact.",
+						Enum:        enumSchemaValues(userTypes),
+					},
+					"page": {
+						Type:        "integer",
+						Description: "Page number for pagination of results.",
+					},
+					"page_size": {
+						Type:        "integer",
+						Description: "Number of results per page for pagination.",
+					},
+				},
+				Required: []string{"company_id"},
+			},
+		},
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var userListRequest projects.UserListRequest
+			var companyID int64
+
+			var arguments map[string]any
+			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("failed to decode request: %s", err.Error())), nil
+			}
+			err := helpers.ParamGroup(arguments,
+				helpers.RequiredNumericParam(&companyID, "company_id"),
+				helpers.OptionalParam(&userListRequest.Filters.SearchTerm, "search_term"),
+				helpers.OptionalParam(&userListRequest.Filters.Type, "type",
+					helpers.RestrictValues(userTypes...),
+				),
+				helpers.OptionalNumericParam(&userListRequest.Filters.Page, "page"),
+				helpers.OptionalNumericParam(&userListRequest.Filters.PageSize, "page_size"),
+			)
+			if err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
+			}
+
+			userList, err := executeWithQuery[*companyUserListResponse](ctx, engine, userListRequest, url.Values{
+				"companyIds": []string{strconv.FormatInt(companyID, 10)},
+				"include":    []string{"jobRoles"},
+			})
+			if err != nil {
+				return helpers.HandleAPIError(err, "failed to list company users")
+			}
+
+			type companyUser struct {
+				ID        int64    `json:"id"`
+				FirstName string   `json:"firstName"`
+				LastName  string   `json:"lastName"`
+				Email     string   `json:"email"`
+				Title     *string  `json:"title,omitempty"`
+				Type      string   `json:"type"`
+				Admin     bool     `json:"isAdmin"`
+				JobRoles  []string `json:"jobRoles,omitempty"`
+			}
+			users := make([]companyUser, 0, len(userList.Users))
+			for _, user := range userList.Users {
+				companyUser := companyUser{
+					ID:        user.ID,
+					FirstName: user.FirstName,
+					LastName:  user.LastName,
+					Email:     user.Email,
+					Title:     user.Title,
+					Type:      user.Type,
+					Admin:     user.Admin,
+				}
+				for _, jobRole := range user.JobRoles {
+					if included, ok := userList.Included.JobRoles[strconv.FormatInt(jobRole.ID, 10)]; ok {
+						companyUser.JobRoles = append(companyUser.JobRoles, included.Name)
+					}
+				}
+				users = append(users, companyUser)
+			}
+
+			return helpers.NewToolResultJSON(map[string]any{
+				"users": users,
+				"meta":  userList.Meta,
+			})
+		},
+	}
+}
+
+// companyUserListResponse extends the user list response with the job roles,
+// which aren't decoded by the SDK.
+type companyUserListResponse struct {
+	projects.UserListResponse
+
+	Included struct {
+		JobRoles map[string]struct {
+			Name string `json:"name"`
+		} `json:"jobRoles"`
+	} `json:"included"`
+}
+
+// HandleHTTPResponse handles the HTTP response for the companyUserListResponse.
+func (c *companyUserListResponse) HandleHTTPResponse(resp *http.Response) error {
+	if resp.StatusCode != http.StatusOK {
+		return twapi.NewHTTPError(resp, "failed to list company users")
+	}
+	if err := json.NewDecoder(resp.Body).Decode(c); err != nil {
+		return fmt.Errorf("failed to decode list company users response: %w", err)
+	}
+	return nil
+}
+
+// userGetResponse extends the user response with the avatar URL, which isn't
+// decoded by the SDK.
+type userGetResponse struct {
+	projects.UserGetResponse
+
+	avatarURL string
+}
+
+// HandleHTTPResponse handles the HTTP response for the userGetResponse.
+func (u *userGetResponse) HandleHTTPResponse(resp *http.Response) error {
+	if resp.StatusCode != http.StatusOK {
+		return twapi.NewHTTPError(resp, "failed to retrieve user")
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read retrieve user response: %w", err)
+	}
+	if err := json.Unmarshal(body, &u.UserGetResponse); err != nil {
+		return fmt.Errorf("failed to decode retrieve user response: %w", err)
+	}
+
+	var avatar struct {
+		Person struct {
+			AvatarURL string `json:"avatarUrl"`
+		} `json:"person"`
+	}
+	if err := json.Unmarshal(body, &avatar); err != nil {
+		return fmt.Errorf("failed to decode retrieve user response: %w", err)
+	}
+	u.avatarURL = avatar.Person.AvatarURL
+	return nil
+}
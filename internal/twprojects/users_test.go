@@ -1,9 +1,11 @@
 package twprojects_test
 
 import (
+	"encoding/json"
 	"net/http"
 	"testing"
 
+	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/teamwork/mcp/internal/testutil"
 	"github.com/teamwork/mcp/internal/twprojects"
 )
@@ -51,6 +53,14 @@ func TestUserGet(t *testing.T) {
 	})
 }
 
+func TestUserGetWithAvatar(t *testing.T) {
+	mcpServer := mcpServerMock(t, http.StatusOK, []byte(`{"person":{"id":123,"avatarUrl":"https://example.com/avatar.png"}}`))
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodUserGet.String(), map[string]any{
+		"id":             float64(123),
+		"include_avatar": true,
+	})
+}
+
 func TestUserGetMe(t *testing.T) {
 	mcpServer := mcpServerMock(t, http.StatusOK, []byte(`{}`))
 	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodUserGetMe.String(), map[string]any{})
@@ -76,3 +86,38 @@ func TestUserListByProject(t *testing.T) {
 		"page_size":   float64(10),
 	})
 }
+
+func TestCompanyUserList(t *testing.T) {
+	mcpServer := mcpServerMock(t, http.StatusOK, []byte(`{"people":[{"id":1,"firstName":"Jane","lastName":"Doe",`+
+		`"email":"jane@example.com","type":"contact","jobRoles":[{"id":7,"type":"jobRoles"}]}],`+
+		`"included":{"jobRoles":{"7":{"id":7,"name":"Account Manager"}}}}`))
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodCompanyUserList.String(), map[string]any{
+		"company_id":  float64(123),
+		"search_term": "jane",
+		"type":        "contact",
+		"page":        float64(1),
+		"page_size":   float64(10),
+	}, testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+		toolResult, ok := result.(*mcp.CallToolResult)
+		if !ok {
+			t.Fatalf("unexpected result type: %T", result)
+		}
+		if toolResult.IsError {
+			t.Fatalf("tool failed to execute: %v", toolResult.Content)
+		}
+
+		var response struct {
+			Users []struct {
+				Email    string   `json:"email"`
+				JobRoles []string `json:"jobRoles"`
+			} `json:"users"`
+		}
+		if err := json.Unmarshal([]byte(toolResult.Content[0].(*mcp.TextContent).Text), &response); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if len(response.Users) != 1 || response.Users[0].Email != "jane@example.com" ||
+			len(response.Users[0].JobRoles) != 1 || response.Users[0].JobRoles[0] != "Account Manager" {
+			t.Errorf("unexpected users: %+v", response.Users)
+		}
+	}))
+}
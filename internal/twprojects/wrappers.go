@@ -0,0 +1,80 @@
+package twprojects
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// withInputSchema returns a copy of the tool with the input schema changed by
+// the given function. The schema, its properties and its required parameters
+// are copied before the change, as the originals may be shared with other
+// tools. Tools without a JSON schema are returned unchanged.
+func withInputSchema(tool *mcp.Tool, change func(schema *jsonschema.Schema)) (*mcp.Tool, bool) {
+	schema, ok := tool.InputSchema.(*jsonschema.Schema)
+	if !ok {
+		return tool, false
+	}
+	schemaCopy := *schema
+	schemaCopy.Properties = maps.Clone(schema.Properties)
+	if schemaCopy.Properties == nil {
+		schemaCopy.Properties = make(map[string]*jsonschema.Schema)
+	}
+	schemaCopy.Required = slices.Clone(schema.Required)
+	change(&schemaCopy)
+
+	toolCopy := *tool
+	toolCopy.InputSchema = &schemaCopy
+	return &toolCopy, true
+}
+
+// describeProperty appends to the description of a property of the schema
+// given to withInputSchema. The property is copied, as it's still shared with
+// the original schema.
+func describeProperty(schema *jsonschema.Schema, name, description string) {
+	property := *schema.Properties[name]
+	property.Description += description
+	schema.Properties[name] = &property
+}
+
+// makeOptional removes the parameter from the required parameters of the
+// schema given to withInputSchema.
+func makeOptional(schema *jsonschema.Schema, name string) {
+	schema.Required = slices.DeleteFunc(schema.Required, func(required string) bool {
+		return required == name
+	})
+}
+
+// withArguments returns a copy of the request with the given arguments.
+func withArguments(request *mcp.CallToolRequest, arguments map[string]any) (*mcp.CallToolRequest, error) {
+	encoded, err := json.Marshal(arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode arguments: %w", err)
+	}
+	params := *request.Params
+	params.Arguments = encoded
+	return &mcp.CallToolRequest{
+		Session: request.Session,
+		Params:  &params,
+		Extra:   request.Extra,
+	}, nil
+}
+
+// callWithArguments calls the handler replacing the arguments of the request.
+func callWithArguments(
+	ctx context.Context,
+	handler mcp.ToolHandler,
+	request *mcp.CallToolRequest,
+	arguments map[string]any,
+) (*mcp.CallToolResult, error) {
+	request, err := withArguments(request, arguments)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, request)
+}
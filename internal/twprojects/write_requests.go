@@ -0,0 +1,163 @@
+package twprojects
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/config"
+	"github.com/teamwork/mcp/internal/helpers"
+	"github.com/teamwork/mcp/internal/toolsets"
+	twapi "github.com/teamwork/twapi-go-sdk"
+	"github.com/teamwork/twapi-go-sdk/projects"
+)
+
+// List of methods available in the Teamwork.com MCP service.
+//
+// The naming convention for methods follows a pattern described here:
+// https://github.com/github/github-mcp-server/issues/333
+const (
+	MethodWriteRequestList toolsets.Method = "twprojects-list_write_requests"
+)
+
+// writeRequestDefaultPeriod is the period of the write requests listed when no
+// start is provided.
+const writeRequestDefaultPeriod = 24 * time.Hour
+
+func init() {
+	// register the toolset methods
+	toolsets.RegisterMethod(MethodWriteRequestList)
+}
+
+// writeRequestCall is a single time a write request was sent.
+type writeRequestCall struct {
+	SentAt        time.Time `json:"sentAt"`
+	Status        int       `json:"status,omitempty"`
+	CorrelationID string    `json:"correlationId,omitempty"`
+}
+
+// writeRequest groups the identical write requests, sent with the same method,
+// path, query and body.
+type writeRequest struct {
+	Hash   string             `json:"hash"`
+	Method string             `json:"method"`
+	Path   string             `json:"path"`
+	Count  int                `json:"count"`
+	Calls  []writeRequestCall `json:"calls"`
+}
+
+// WithWriteLog enables the tool listing the write requests recorded in the
+// given log, so the administrators can audit duplicated mutations. Nil doesn't
+// add the tool.
+func WithWriteLog(log *config.WriteLog) ToolsetGroupOption {
+	return func(opts *ToolsetGroupOptions) {
+		opts.writeLog = log
+	}
+}
+
+// WriteRequestList lists the write requests sent to Teamwork.com, grouping the
+// identical ones, so the duplicated mutations can be found after the fact.
+func WriteRequestList(engine *twapi.Engine, log *config.WriteLog) toolsets.ToolWrapper {
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name: string(MethodWriteRequestList),
+			Description: "List the write requests sent to Teamwork.com by this server, grouping the identical " +
+				"requests (same method, path, query and body hash) with the time of each call. Use it to audit " +
+				"whether an agent issued the same mutation more than once. Only available to administrators. The " +
+				"request bodies aren't kept, only their hash, and older requests are discarded.",
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "List Write Requests",
+				ReadOnlyHint: true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"since": {
+						Type:   "string",
+						Format: "date-time",
+						Description: "List the requests sent since this date and time, in RFC3339 format. Defaults to the " +
+							"last 24 hours.",
+					},
+					"duplicates_only": {
+						Type:        "boolean",
+						Description: "If true, only the requests sent more than once are listed.",
+					},
+				},
+			},
+		},
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			since := time.Now().Add(-writeRequestDefaultPeriod)
+			var duplicatesOnly bool
+
+			var arguments map[string]any
+			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("failed to decode request: %s", err.Error())), nil
+			}
+			err := helpers.ParamGroup(arguments,
+				helpers.OptionalTimeParam(&since, "since"),
+				helpers.OptionalParam(&duplicatesOnly, "duplicates_only"),
+			)
+			if err != nil {
+				return helpers.NewToolResultTextError(fmt.Sprintf("invalid parameters: %s", err.Error())), nil
+			}
+
+			me, err := projects.UserGetMe(ctx, engine, projects.NewUserGetMeRequest())
+			if err != nil {
+				return helpers.HandleAPIError(err, "failed to get the authenticated user")
+			}
+			if !me.User.Admin {
+				return helpers.NewToolResultTextError("only administrators can list the write requests"), nil
+			}
+
+			installation, _ := config.CustomerURLFromContext(ctx)
+			var requests []writeRequest
+			indexes := make(map[string]int)
+			for _, entry := range log.Entries(installation, since) {
+				index, ok := indexes[entry.Hash]
+				if !ok {
+					index = len(requests)
+					indexes[entry.Hash] = index
+					requests = append(requests, writeRequest{
+						Hash:   entry.Hash,
+						Method: entry.Method,
+						Path:   entry.Path,
+					})
+				}
+				requests[index].Count++
+				requests[index].Calls = append(requests[index].Calls, writeRequestCall{
+					SentAt:        entry.SentAt,
+					Status:        entry.Status,
+					CorrelationID: entry.CorrelationID,
+				})
+			}
+
+			result := struct {
+				Since      time.Time      `json:"since"`
+				Total      int            `json:"total"`
+				Duplicated int            `json:"duplicated"`
+				Requests   []writeRequest `json:"requests"`
+			}{
+				Since:    since.UTC(),
+				Requests: make([]writeRequest, 0, len(requests)),
+			}
+			for _, request := range requests {
+				result.Total += request.Count
+				if request.Count > 1 {
+					result.Duplicated++
+				} else if duplicatesOnly {
+					continue
+				}
+				result.Requests = append(result.Requests, request)
+			}
+
+			encoded, err := json.Marshal(result)
+			if err != nil {
+				return nil, err
+			}
+			return helpers.NewToolResultText("%s", encoded), nil
+		},
+	}
+}
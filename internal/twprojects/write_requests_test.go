@@ -0,0 +1,102 @@
+package twprojects_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/config"
+	"github.com/teamwork/mcp/internal/testutil"
+	"github.com/teamwork/mcp/internal/twprojects"
+	twapi "github.com/teamwork/twapi-go-sdk"
+)
+
+func TestWriteRequestList(t *testing.T) {
+	log := config.NewWriteLog(10)
+	client := log.Middleware(twapi.HTTPClientFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusCreated, Body: io.NopCloser(strings.NewReader(`{}`))}, nil
+	}))
+	for _, body := range []string{`{"task":{"name":"A"}}`, `{"task":{"name":"A"}}`, `{"task":{"name":"B"}}`} {
+		req, err := http.NewRequest(http.MethodPost, "https://example.com/projects/api/v3/tasklists/1/tasks.json",
+			strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("failed to create request: %v", err)
+		}
+		if _, err := client.Do(req); err != nil {
+			t.Fatalf("failed to send request: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		admin     bool
+		arguments map[string]any
+		requests  []int
+		isError   bool
+	}{{
+		name:     "all requests",
+		admin:    true,
+		requests: []int{2, 1},
+	}, {
+		name:      "duplicates only",
+		admin:     true,
+		arguments: map[string]any{"duplicates_only": true},
+		requests:  []int{2},
+	}, {
+		name:    "not an administrator",
+		isError: true,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mcpServer := testutil.ProjectsMCPServerMockFunc(t, func(r *http.Request) (int, []byte) {
+				if r.URL.Path != "/projects/api/v3/me.json" {
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				}
+				if tt.admin {
+					return http.StatusOK, []byte(`{"person":{"id":7,"isAdmin":true}}`)
+				}
+				return http.StatusOK, []byte(`{"person":{"id":7}}`)
+			}, twprojects.WithWriteLog(log))
+
+			arguments := tt.arguments
+			if arguments == nil {
+				arguments = map[string]any{}
+			}
+			testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodWriteRequestList.String(), arguments,
+				testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+					toolResult, ok := result.(*mcp.CallToolResult)
+					if !ok {
+						t.Fatalf("unexpected result type: %T", result)
+					}
+					if toolResult.IsError != tt.isError {
+						t.Fatalf("unexpected error state %v: %v", toolResult.IsError, toolResult.Content)
+					}
+					if tt.isError {
+						return
+					}
+					var list struct {
+						Total      int `json:"total"`
+						Duplicated int `json:"duplicated"`
+						Requests   []struct {
+							Count int `json:"count"`
+						} `json:"requests"`
+					}
+					if err := json.Unmarshal([]byte(toolResult.Content[0].(*mcp.TextContent).Text), &list); err != nil {
+						t.Fatalf("failed to decode result: %v", err)
+					}
+					if list.Total != 3 || list.Duplicated != 1 || len(list.Requests) != len(tt.requests) {
+						t.Fatalf("unexpected list %+v", list)
+					}
+					for i, count := range tt.requests {
+						if list.Requests[i].Count != count {
+							t.Errorf("expected request %d sent %d times, got %d", i, count, list.Requests[i].Count)
+						}
+					}
+				}))
+		})
+	}
+}
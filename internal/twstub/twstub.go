@@ -0,0 +1,851 @@
+// Package twstub serves an in-memory subset of the Teamwork.com Projects API
+// (projects, tasklists, tasks, timelogs and comments), so the tools can run
+// without credentials, e.g. in the integration tests and the mock mode of the
+// STDIO server.
+//
+// The stub answers in the same formats as the API, so the SDK decodes its
+// responses. The endpoints outside the subset answer 404 Not Found.
+package twstub
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/teamwork/twapi-go-sdk"
+	"github.com/teamwork/twapi-go-sdk/projects"
+)
+
+// UserID is the user authenticated by any bearer token, who creates all the
+// entities.
+const UserID int64 = 1
+
+// defaultPageSize is the page size of the lists when the request doesn't set
+// one.
+const defaultPageSize = 50
+
+// Server is the in-memory Teamwork API. It is safe for concurrent use.
+type Server struct {
+	mu        sync.Mutex
+	lastID    int64
+	projects  map[int64]*projects.Project
+	tasklists map[int64]*projects.Tasklist
+	tasks     map[int64]*projects.Task
+	timelogs  map[int64]*projects.Timelog
+	comments  map[int64]*projects.Comment
+	now       func() time.Time
+}
+
+// New creates the stub, seeded with a project, a tasklist and a task, so the
+// tools have something to work with.
+func New() *Server {
+	s := &Server{
+		projects:  make(map[int64]*projects.Project),
+		tasklists: make(map[int64]*projects.Tasklist),
+		tasks:     make(map[int64]*projects.Task),
+		timelogs:  make(map[int64]*projects.Timelog),
+		comments:  make(map[int64]*projects.Comment),
+		now:       func() time.Time { return time.Now().UTC() },
+	}
+
+	now := s.now()
+	project := &projects.Project{
+		ID:        s.nextID(),
+		Name:      "Demo project",
+		Company:   twapi.Relationship{ID: 1, Type: "companies"},
+		CreatedAt: &now,
+		CreatedBy: twapi.Ptr(UserID),
+		Status:    "active",
+		Type:      "normal",
+	}
+	s.projects[project.ID] = project
+	tasklist := &projects.Tasklist{
+		ID:        s.nextID(),
+		Name:      "General",
+		Project:   twapi.Relationship{ID: project.ID, Type: "projects"},
+		CreatedAt: &now,
+		Status:    "new",
+	}
+	s.tasklists[tasklist.ID] = tasklist
+	task := &projects.Task{
+		ID:        s.nextID(),
+		Name:      "Welcome to the mock mode",
+		Tasklist:  twapi.Relationship{ID: tasklist.ID, Type: "tasklists"},
+		CreatedBy: twapi.Ptr(UserID),
+		CreatedAt: &now,
+		UpdatedAt: now,
+		Status:    "new",
+	}
+	s.tasks[task.ID] = task
+	return s
+}
+
+// route is an endpoint of the stub. The first group of the pattern, if any, is
+// the ID of the entity in the path.
+type route struct {
+	method  string
+	pattern *regexp.Regexp
+	handler func(s *Server, w http.ResponseWriter, r *http.Request, id int64)
+}
+
+var routes = []route{
+	{http.MethodGet, regexp.MustCompile(`^/launchpad/v1/userinfo\.json$`), (*Server).userInfo},
+
+	{http.MethodPost, regexp.MustCompile(`^/projects\.json$`), (*Server).createProject},
+	{http.MethodPut, regexp.MustCompile(`^/projects/(\d+)\.json$`), (*Server).updateProject},
+	{http.MethodDelete, regexp.MustCompile(`^/projects/(\d+)\.json$`), (*Server).deleteProject},
+	{http.MethodGet, regexp.MustCompile(`^/projects/api/v3/projects/(\d+)\.json$`), (*Server).getProject},
+	{http.MethodGet, regexp.MustCompile(`^/projects/api/v3/projects\.json$`), (*Server).listProjects},
+
+	{http.MethodPost, regexp.MustCompile(`^/projects/(\d+)/tasklists\.json$`), (*Server).createTasklist},
+	{http.MethodPut, regexp.MustCompile(`^/tasklists/(\d+)\.json$`), (*Server).updateTasklist},
+	{http.MethodDelete, regexp.MustCompile(`^/tasklists/(\d+)\.json$`), (*Server).deleteTasklist},
+	{http.MethodGet, regexp.MustCompile(`^/projects/api/v3/tasklists/(\d+)\.json$`), (*Server).getTasklist},
+	{http.MethodGet, regexp.MustCompile(`^/projects/api/v3/(?:projects/(\d+)/)?tasklists\.json$`),
+		(*Server).listTasklists},
+
+	{http.MethodPost, regexp.MustCompile(`^/projects/api/v3/tasklists/(\d+)/tasks\.json$`), (*Server).createTask},
+	{http.MethodPut, regexp.MustCompile(`^/projects/api/v3/tasks/(\d+)\.json$`), (*Server).updateTask},
+	{http.MethodDelete, regexp.MustCompile(`^/projects/api/v3/tasks/(\d+)\.json$`), (*Server).deleteTask},
+	{http.MethodGet, regexp.MustCompile(`^/projects/api/v3/tasks/(\d+)\.json$`), (*Server).getTask},
+	{http.MethodGet, regexp.MustCompile(`^/projects/api/v3/tasks\.json$`), (*Server).listTasks},
+	{http.MethodGet, regexp.MustCompile(`^/projects/api/v3/tasklists/(\d+)/tasks\.json$`),
+		(*Server).listTasklistTasks},
+	{http.MethodGet, regexp.MustCompile(`^/projects/api/v3/projects/(\d+)/tasks\.json$`), (*Server).listProjectTasks},
+
+	{http.MethodPost, regexp.MustCompile(`^/projects/api/v3/tasks/(\d+)/time\.json$`), (*Server).createTaskTimelog},
+	{http.MethodPost, regexp.MustCompile(`^/projects/api/v3/projects/(\d+)/time\.json$`),
+		(*Server).createProjectTimelog},
+	{http.MethodPatch, regexp.MustCompile(`^/projects/api/v3/time/(\d+)\.json$`), (*Server).updateTimelog},
+	{http.MethodDelete, regexp.MustCompile(`^/projects/api/v3/time/(\d+)\.json$`), (*Server).deleteTimelog},
+	{http.MethodGet, regexp.MustCompile(`^/projects/api/v3/time/(\d+)\.json$`), (*Server).getTimelog},
+	{http.MethodGet, regexp.MustCompile(`^/projects/api/v3/time\.json$`), (*Server).listTimelogs},
+	{http.MethodGet, regexp.MustCompile(`^/projects/api/v3/tasks/(\d+)/time\.json$`), (*Server).listTaskTimelogs},
+	{http.MethodGet, regexp.MustCompile(`^/projects/api/v3/projects/(\d+)/time\.json$`),
+		(*Server).listProjectTimelogs},
+
+	{http.MethodPost, regexp.MustCompile(`^/tasks/(\d+)/comments\.json$`), (*Server).createComment},
+	{http.MethodPut, regexp.MustCompile(`^/comments/(\d+)\.json$`), (*Server).updateComment},
+	{http.MethodDelete, regexp.MustCompile(`^/comments/(\d+)\.json$`), (*Server).deleteComment},
+	{http.MethodGet, regexp.MustCompile(`^/projects/api/v3/comments/(\d+)\.json$`), (*Server).getComment},
+	{http.MethodGet, regexp.MustCompile(`^/projects/api/v3/(?:tasks/(\d+)/)?comments\.json$`),
+		(*Server).listComments},
+}
+
+// ServeHTTP serves the endpoints of the subset.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	for _, route := range routes {
+		matches := route.pattern.FindStringSubmatch(r.URL.Path)
+		if matches == nil {
+			continue
+		}
+		if r.Method != route.method {
+			continue
+		}
+		var id int64
+		if len(matches) > 1 && matches[1] != "" {
+			var err error
+			if id, err = strconv.ParseInt(matches[1], 10, 64); err != nil {
+				writeError(w, http.StatusBadRequest, "invalid id %q", matches[1])
+				return
+			}
+		}
+
+		s.mu.Lock()
+		route.handler(s, w, r, id)
+		s.mu.Unlock()
+		return
+	}
+	writeError(w, http.StatusNotFound, "%s %s is not supported by the stub", r.Method, r.URL.Path)
+}
+
+func (s *Server) nextID() int64 {
+	s.lastID++
+	return s.lastID
+}
+
+func (s *Server) userInfo(w http.ResponseWriter, r *http.Request, _ int64) {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"user_id":         UserID,
+		"installation_id": 1,
+		"url":             scheme + "://" + r.Host,
+	})
+}
+
+func (s *Server) createProject(w http.ResponseWriter, r *http.Request, _ int64) {
+	var payload struct {
+		Project projects.ProjectCreateRequest `json:"project"`
+	}
+	if !decodeBody(w, r, &payload) {
+		return
+	}
+	if payload.Project.Name == "" {
+		writeError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+	now := s.now()
+	project := &projects.Project{
+		ID:          s.nextID(),
+		Name:        payload.Project.Name,
+		Description: payload.Project.Description,
+		StartAt:     (*time.Time)(payload.Project.StartAt),
+		EndAt:       (*time.Time)(payload.Project.EndAt),
+		Company:     twapi.Relationship{ID: payload.Project.CompanyID, Type: "companies"},
+		Tags:        relationships(payload.Project.TagIDs, "tags"),
+		CreatedAt:   &now,
+		CreatedBy:   twapi.Ptr(UserID),
+		Status:      "active",
+		Type:        "normal",
+	}
+	if payload.Project.OwnerID != nil {
+		project.Owner = &twapi.Relationship{ID: *payload.Project.OwnerID, Type: "users"}
+	}
+	s.projects[project.ID] = project
+	writeJSON(w, http.StatusCreated, map[string]any{"id": strconv.FormatInt(project.ID, 10)})
+}
+
+func (s *Server) updateProject(w http.ResponseWriter, r *http.Request, id int64) {
+	project, ok := s.projects[id]
+	if !ok {
+		writeNotFound(w, "project", id)
+		return
+	}
+	var payload struct {
+		Project projects.ProjectUpdateRequest `json:"project"`
+	}
+	if !decodeBody(w, r, &payload) {
+		return
+	}
+	update := payload.Project
+	if update.Name != nil {
+		project.Name = *update.Name
+	}
+	if update.Description != nil {
+		project.Description = update.Description
+	}
+	if update.StartAt != nil {
+		project.StartAt = (*time.Time)(update.StartAt)
+	}
+	if update.EndAt != nil {
+		project.EndAt = (*time.Time)(update.EndAt)
+	}
+	if update.CompanyID != nil {
+		project.Company = twapi.Relationship{ID: *update.CompanyID, Type: "companies"}
+	}
+	if update.OwnerID != nil {
+		project.Owner = &twapi.Relationship{ID: *update.OwnerID, Type: "users"}
+	}
+	if update.TagIDs != nil {
+		project.Tags = relationships(update.TagIDs, "tags")
+	}
+	now := s.now()
+	project.UpdatedAt = &now
+	project.UpdatedBy = twapi.Ptr(UserID)
+	writeJSON(w, http.StatusOK, struct{}{})
+}
+
+func (s *Server) deleteProject(w http.ResponseWriter, _ *http.Request, id int64) {
+	if _, ok := s.projects[id]; !ok {
+		writeNotFound(w, "project", id)
+		return
+	}
+	delete(s.projects, id)
+	writeJSON(w, http.StatusOK, struct{}{})
+}
+
+func (s *Server) getProject(w http.ResponseWriter, _ *http.Request, id int64) {
+	project, ok := s.projects[id]
+	if !ok {
+		writeNotFound(w, "project", id)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"project": project})
+}
+
+func (s *Server) listProjects(w http.ResponseWriter, r *http.Request, _ int64) {
+	projectIDs := queryIDs(r, "projectIds")
+	searchTerm := strings.ToLower(r.URL.Query().Get("searchTerm"))
+	writeList(w, r, "projects", values(s.projects, func(project *projects.Project) bool {
+		return (len(projectIDs) == 0 || slices.Contains(projectIDs, project.ID)) &&
+			strings.Contains(strings.ToLower(project.Name), searchTerm)
+	}))
+}
+
+func (s *Server) createTasklist(w http.ResponseWriter, r *http.Request, projectID int64) {
+	if _, ok := s.projects[projectID]; !ok {
+		writeNotFound(w, "project", projectID)
+		return
+	}
+	var payload struct {
+		Tasklist projects.TasklistCreateRequest `json:"todo-list"`
+	}
+	if !decodeBody(w, r, &payload) {
+		return
+	}
+	if payload.Tasklist.Name == "" {
+		writeError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+	now := s.now()
+	tasklist := &projects.Tasklist{
+		ID:        s.nextID(),
+		Name:      payload.Tasklist.Name,
+		Project:   twapi.Relationship{ID: projectID, Type: "projects"},
+		CreatedAt: &now,
+		Status:    "new",
+	}
+	if payload.Tasklist.Description != nil {
+		tasklist.Description = *payload.Tasklist.Description
+	}
+	if payload.Tasklist.MilestoneID != nil {
+		tasklist.Milestone = &twapi.Relationship{ID: *payload.Tasklist.MilestoneID, Type: "milestones"}
+	}
+	s.tasklists[tasklist.ID] = tasklist
+	writeJSON(w, http.StatusCreated, map[string]any{"tasklistId": strconv.FormatInt(tasklist.ID, 10)})
+}
+
+func (s *Server) updateTasklist(w http.ResponseWriter, r *http.Request, id int64) {
+	tasklist, ok := s.tasklists[id]
+	if !ok {
+		writeNotFound(w, "tasklist", id)
+		return
+	}
+	var payload struct {
+		Tasklist projects.TasklistUpdateRequest `json:"todo-list"`
+	}
+	if !decodeBody(w, r, &payload) {
+		return
+	}
+	if payload.Tasklist.Name != nil {
+		tasklist.Name = *payload.Tasklist.Name
+	}
+	if payload.Tasklist.Description != nil {
+		tasklist.Description = *payload.Tasklist.Description
+	}
+	if payload.Tasklist.MilestoneID != nil {
+		tasklist.Milestone = &twapi.Relationship{ID: *payload.Tasklist.MilestoneID, Type: "milestones"}
+	}
+	now := s.now()
+	tasklist.UpdatedAt = &now
+	writeJSON(w, http.StatusOK, struct{}{})
+}
+
+func (s *Server) deleteTasklist(w http.ResponseWriter, _ *http.Request, id int64) {
+	if _, ok := s.tasklists[id]; !ok {
+		writeNotFound(w, "tasklist", id)
+		return
+	}
+	delete(s.tasklists, id)
+	writeJSON(w, http.StatusOK, struct{}{})
+}
+
+func (s *Server) getTasklist(w http.ResponseWriter, _ *http.Request, id int64) {
+	tasklist, ok := s.tasklists[id]
+	if !ok {
+		writeNotFound(w, "tasklist", id)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"tasklist": tasklist})
+}
+
+func (s *Server) listTasklists(w http.ResponseWriter, r *http.Request, projectID int64) {
+	searchTerm := strings.ToLower(r.URL.Query().Get("searchTerm"))
+	writeList(w, r, "tasklists", values(s.tasklists, func(tasklist *projects.Tasklist) bool {
+		return (projectID == 0 || tasklist.Project.ID == projectID) &&
+			strings.Contains(strings.ToLower(tasklist.Name), searchTerm)
+	}))
+}
+
+func (s *Server) createTask(w http.ResponseWriter, r *http.Request, tasklistID int64) {
+	if _, ok := s.tasklists[tasklistID]; !ok {
+		writeNotFound(w, "tasklist", tasklistID)
+		return
+	}
+	var payload struct {
+		Task projects.TaskCreateRequest `json:"task"`
+	}
+	if !decodeBody(w, r, &payload) {
+		return
+	}
+	if payload.Task.Name == "" {
+		writeError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+	now := s.now()
+	task := &projects.Task{
+		ID:          s.nextID(),
+		Name:        payload.Task.Name,
+		Description: payload.Task.Description,
+		Priority:    payload.Task.Priority,
+		StartAt:     (*time.Time)(payload.Task.StartAt),
+		DueAt:       (*time.Time)(payload.Task.DueAt),
+		Tasklist:    twapi.Relationship{ID: tasklistID, Type: "tasklists"},
+		Tags:        relationships(payload.Task.TagIDs, "tags"),
+		CreatedBy:   twapi.Ptr(UserID),
+		CreatedAt:   &now,
+		UpdatedAt:   now,
+		Status:      "new",
+	}
+	if payload.Task.Progress != nil {
+		task.Progress = *payload.Task.Progress
+	}
+	if payload.Task.EstimatedMinutes != nil {
+		task.EstimatedMinutes = *payload.Task.EstimatedMinutes
+	}
+	if payload.Task.ParentTaskID != nil {
+		task.ParentTask = &twapi.Relationship{ID: *payload.Task.ParentTaskID, Type: "tasks"}
+	}
+	if payload.Task.Assignees != nil {
+		task.Assignees = assignees(*payload.Task.Assignees)
+	}
+	s.tasks[task.ID] = task
+	writeJSON(w, http.StatusCreated, map[string]any{"task": task})
+}
+
+func (s *Server) updateTask(w http.ResponseWriter, r *http.Request, id int64) {
+	task, ok := s.tasks[id]
+	if !ok {
+		writeNotFound(w, "task", id)
+		return
+	}
+	var payload struct {
+		Task projects.TaskUpdateRequest `json:"task"`
+	}
+	if !decodeBody(w, r, &payload) {
+		return
+	}
+	update := payload.Task
+	if update.TasklistID != nil {
+		if _, ok := s.tasklists[*update.TasklistID]; !ok {
+			writeNotFound(w, "tasklist", *update.TasklistID)
+			return
+		}
+		task.Tasklist = twapi.Relationship{ID: *update.TasklistID, Type: "tasklists"}
+	}
+	if update.Name != nil {
+		task.Name = *update.Name
+	}
+	if update.Description != nil {
+		task.Description = update.Description
+	}
+	if update.Priority != nil {
+		task.Priority = update.Priority
+	}
+	if update.Progress != nil {
+		task.Progress = *update.Progress
+	}
+	if update.StartAt != nil {
+		task.StartAt = (*time.Time)(update.StartAt)
+	}
+	if update.DueAt != nil {
+		task.DueAt = (*time.Time)(update.DueAt)
+	}
+	if update.EstimatedMinutes != nil {
+		task.EstimatedMinutes = *update.EstimatedMinutes
+	}
+	if update.ParentTaskID != nil {
+		task.ParentTask = &twapi.Relationship{ID: *update.ParentTaskID, Type: "tasks"}
+	}
+	if update.Assignees != nil {
+		task.Assignees = assignees(*update.Assignees)
+	}
+	if update.TagIDs != nil {
+		task.Tags = relationships(update.TagIDs, "tags")
+	}
+	task.UpdatedBy = twapi.Ptr(UserID)
+	task.UpdatedAt = s.now()
+	writeJSON(w, http.StatusOK, map[string]any{"task": task})
+}
+
+func (s *Server) deleteTask(w http.ResponseWriter, _ *http.Request, id int64) {
+	if _, ok := s.tasks[id]; !ok {
+		writeNotFound(w, "task", id)
+		return
+	}
+	delete(s.tasks, id)
+	writeJSON(w, http.StatusOK, struct{}{})
+}
+
+func (s *Server) getTask(w http.ResponseWriter, _ *http.Request, id int64) {
+	task, ok := s.tasks[id]
+	if !ok {
+		writeNotFound(w, "task", id)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"task": task})
+}
+
+func (s *Server) listTasks(w http.ResponseWriter, r *http.Request, _ int64) {
+	s.writeTasks(w, r, func(*projects.Task) bool { return true })
+}
+
+func (s *Server) listTasklistTasks(w http.ResponseWriter, r *http.Request, tasklistID int64) {
+	s.writeTasks(w, r, func(task *projects.Task) bool { return task.Tasklist.ID == tasklistID })
+}
+
+func (s *Server) listProjectTasks(w http.ResponseWriter, r *http.Request, projectID int64) {
+	s.writeTasks(w, r, func(task *projects.Task) bool { return s.taskProjectID(task) == projectID })
+}
+
+// writeTasks writes the tasks kept by the function, and the filters of the
+// query.
+func (s *Server) writeTasks(w http.ResponseWriter, r *http.Request, keep func(*projects.Task) bool) {
+	searchTerm := strings.ToLower(r.URL.Query().Get("searchTerm"))
+	assigneeIDs := queryIDs(r, "responsiblePartyIds")
+	tagIDs := queryIDs(r, "tagIds")
+	writeList(w, r, "tasks", values(s.tasks, func(task *projects.Task) bool {
+		return keep(task) &&
+			strings.Contains(strings.ToLower(task.Name), searchTerm) &&
+			(len(assigneeIDs) == 0 || containsRelationship(task.Assignees, assigneeIDs)) &&
+			(len(tagIDs) == 0 || containsRelationship(task.Tags, tagIDs))
+	}))
+}
+
+func (s *Server) createTaskTimelog(w http.ResponseWriter, r *http.Request, taskID int64) {
+	task, ok := s.tasks[taskID]
+	if !ok {
+		writeNotFound(w, "task", taskID)
+		return
+	}
+	s.createTimelog(w, r, s.taskProjectID(task), &twapi.Relationship{ID: taskID, Type: "tasks"})
+}
+
+func (s *Server) createProjectTimelog(w http.ResponseWriter, r *http.Request, projectID int64) {
+	if _, ok := s.projects[projectID]; !ok {
+		writeNotFound(w, "project", projectID)
+		return
+	}
+	s.createTimelog(w, r, projectID, nil)
+}
+
+func (s *Server) createTimelog(w http.ResponseWriter, r *http.Request, projectID int64, task *twapi.Relationship) {
+	var payload struct {
+		Timelog projects.TimelogCreateRequest `json:"timelog"`
+	}
+	if !decodeBody(w, r, &payload) {
+		return
+	}
+	create := payload.Timelog
+	minutes := create.Hours*60 + create.Minutes
+	if minutes <= 0 {
+		writeError(w, http.StatusBadRequest, "the time logged must be positive")
+		return
+	}
+	userID := UserID
+	if create.UserID != nil {
+		userID = *create.UserID
+	}
+	now := s.now()
+	timelog := &projects.Timelog{
+		ID:        s.nextID(),
+		Billable:  create.Billable,
+		Minutes:   minutes,
+		LoggedAt:  loggedAt(create.Date, create.Time),
+		User:      twapi.Relationship{ID: userID, Type: "users"},
+		Task:      task,
+		Project:   twapi.Relationship{ID: projectID, Type: "projects"},
+		Tags:      relationships(create.TagIDs, "tags"),
+		CreatedAt: now,
+		LoggedBy:  UserID,
+	}
+	if create.Description != nil {
+		timelog.Description = *create.Description
+	}
+	s.timelogs[timelog.ID] = timelog
+	writeJSON(w, http.StatusCreated, map[string]any{"timelog": timelog})
+}
+
+func (s *Server) updateTimelog(w http.ResponseWriter, r *http.Request, id int64) {
+	timelog, ok := s.timelogs[id]
+	if !ok {
+		writeNotFound(w, "timelog", id)
+		return
+	}
+	var payload struct {
+		Timelog projects.TimelogUpdateRequest `json:"timelog"`
+	}
+	if !decodeBody(w, r, &payload) {
+		return
+	}
+	update := payload.Timelog
+	if update.Description != nil {
+		timelog.Description = *update.Description
+	}
+	if update.Date != nil || update.Time != nil {
+		date, clock := twapi.Date(timelog.LoggedAt), twapi.Time(timelog.LoggedAt)
+		if update.Date != nil {
+			date = *update.Date
+		}
+		if update.Time != nil {
+			clock = *update.Time
+		}
+		timelog.LoggedAt = loggedAt(date, clock)
+	}
+	if update.Hours != nil || update.Minutes != nil {
+		hours, minutes := timelog.Minutes/60, timelog.Minutes%60
+		if update.Hours != nil {
+			hours = *update.Hours
+		}
+		if update.Minutes != nil {
+			minutes = *update.Minutes
+		}
+		timelog.Minutes = hours*60 + minutes
+	}
+	if update.Billable != nil {
+		timelog.Billable = *update.Billable
+	}
+	if update.UserID != nil {
+		timelog.User = twapi.Relationship{ID: *update.UserID, Type: "users"}
+	}
+	if update.TagIDs != nil {
+		timelog.Tags = relationships(update.TagIDs, "tags")
+	}
+	now := s.now()
+	timelog.UpdatedAt = &now
+	timelog.UpdatedBy = twapi.Ptr(UserID)
+	writeJSON(w, http.StatusOK, struct{}{})
+}
+
+func (s *Server) deleteTimelog(w http.ResponseWriter, _ *http.Request, id int64) {
+	if _, ok := s.timelogs[id]; !ok {
+		writeNotFound(w, "timelog", id)
+		return
+	}
+	delete(s.timelogs, id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) getTimelog(w http.ResponseWriter, _ *http.Request, id int64) {
+	timelog, ok := s.timelogs[id]
+	if !ok {
+		writeNotFound(w, "timelog", id)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"timelog": timelog})
+}
+
+func (s *Server) listTimelogs(w http.ResponseWriter, r *http.Request, _ int64) {
+	writeList(w, r, "timelogs", values(s.timelogs, func(*projects.Timelog) bool { return true }))
+}
+
+func (s *Server) listTaskTimelogs(w http.ResponseWriter, r *http.Request, taskID int64) {
+	writeList(w, r, "timelogs", values(s.timelogs, func(timelog *projects.Timelog) bool {
+		return timelog.Task != nil && timelog.Task.ID == taskID
+	}))
+}
+
+func (s *Server) listProjectTimelogs(w http.ResponseWriter, r *http.Request, projectID int64) {
+	writeList(w, r, "timelogs", values(s.timelogs, func(timelog *projects.Timelog) bool {
+		return timelog.Project.ID == projectID
+	}))
+}
+
+func (s *Server) createComment(w http.ResponseWriter, r *http.Request, taskID int64) {
+	task, ok := s.tasks[taskID]
+	if !ok {
+		writeNotFound(w, "task", taskID)
+		return
+	}
+	var payload struct {
+		Comment projects.CommentCreateRequest `json:"comment"`
+	}
+	if !decodeBody(w, r, &payload) {
+		return
+	}
+	if payload.Comment.Body == "" {
+		writeError(w, http.StatusBadRequest, "body is required")
+		return
+	}
+	now := s.now()
+	comment := &projects.Comment{
+		ID:       s.nextID(),
+		Body:     payload.Comment.Body,
+		HTMLBody: payload.Comment.Body,
+		Object:   &twapi.Relationship{ID: taskID, Type: "tasks"},
+		Project:  twapi.Relationship{ID: s.taskProjectID(task), Type: "projects"},
+		PostedBy: twapi.Ptr(UserID),
+		PostedAt: &now,
+	}
+	comment.ContentType = "TEXT"
+	if payload.Comment.ContentType != nil {
+		comment.ContentType = *payload.Comment.ContentType
+	}
+	s.comments[comment.ID] = comment
+	writeJSON(w, http.StatusCreated, map[string]any{"id": strconv.FormatInt(comment.ID, 10)})
+}
+
+func (s *Server) updateComment(w http.ResponseWriter, r *http.Request, id int64) {
+	comment, ok := s.comments[id]
+	if !ok {
+		writeNotFound(w, "comment", id)
+		return
+	}
+	var payload struct {
+		Comment projects.CommentUpdateRequest `json:"comment"`
+	}
+	if !decodeBody(w, r, &payload) {
+		return
+	}
+	comment.Body = payload.Comment.Body
+	comment.HTMLBody = payload.Comment.Body
+	if payload.Comment.ContentType != nil {
+		comment.ContentType = *payload.Comment.ContentType
+	}
+	now := s.now()
+	comment.EditedAt = &now
+	comment.LastEditedBy = twapi.Ptr(UserID)
+	writeJSON(w, http.StatusOK, struct{}{})
+}
+
+func (s *Server) deleteComment(w http.ResponseWriter, _ *http.Request, id int64) {
+	if _, ok := s.comments[id]; !ok {
+		writeNotFound(w, "comment", id)
+		return
+	}
+	delete(s.comments, id)
+	writeJSON(w, http.StatusOK, struct{}{})
+}
+
+func (s *Server) getComment(w http.ResponseWriter, _ *http.Request, id int64) {
+	comment, ok := s.comments[id]
+	if !ok {
+		writeNotFound(w, "comment", id)
+		return
+	}
+	// the endpoint wraps the comment in the plural key
+	writeJSON(w, http.StatusOK, map[string]any{"comments": comment})
+}
+
+func (s *Server) listComments(w http.ResponseWriter, r *http.Request, taskID int64) {
+	writeList(w, r, "comments", values(s.comments, func(comment *projects.Comment) bool {
+		return taskID == 0 || comment.Object.ID == taskID
+	}))
+}
+
+// taskProjectID returns the project of the task, through its tasklist.
+func (s *Server) taskProjectID(task *projects.Task) int64 {
+	if tasklist, ok := s.tasklists[task.Tasklist.ID]; ok {
+		return tasklist.Project.ID
+	}
+	return 0
+}
+
+// values returns the entities kept by the function, sorted by ID.
+func values[T any](entities map[int64]*T, keep func(*T) bool) []T {
+	list := make([]T, 0, len(entities))
+	for _, id := range slices.Sorted(maps.Keys(entities)) {
+		if keep(entities[id]) {
+			list = append(list, *entities[id])
+		}
+	}
+	return list
+}
+
+// writeList writes the page of the entities requested by the page and pageSize
+// parameters, in the format of the API lists.
+func writeList[T any](w http.ResponseWriter, r *http.Request, key string, entities []T) {
+	page, pageSize := int64(1), int64(defaultPageSize)
+	if value, err := strconv.ParseInt(r.URL.Query().Get("page"), 10, 64); err == nil && value > 0 {
+		page = value
+	}
+	if value, err := strconv.ParseInt(r.URL.Query().Get("pageSize"), 10, 64); err == nil && value > 0 {
+		pageSize = value
+	}
+	start := min((page-1)*pageSize, int64(len(entities)))
+	end := min(start+pageSize, int64(len(entities)))
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		key: entities[start:end],
+		"meta": map[string]any{
+			"page": map[string]any{
+				"pageOffset": page - 1,
+				"pageSize":   pageSize,
+				"count":      len(entities),
+				"hasMore":    end < int64(len(entities)),
+			},
+		},
+	})
+}
+
+// queryIDs parses a comma-separated list of IDs of the query, ignoring the
+// invalid ones.
+func queryIDs(r *http.Request, name string) []int64 {
+	var ids []int64
+	for value := range strings.SplitSeq(r.URL.Query().Get(name), ",") {
+		if id, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// relationships converts the IDs to relationships of the given type.
+func relationships(ids []int64, kind string) []twapi.Relationship {
+	var list []twapi.Relationship
+	for _, id := range ids {
+		list = append(list, twapi.Relationship{ID: id, Type: kind})
+	}
+	return list
+}
+
+// assignees converts the user groups to the relationships of the task
+// assignees.
+func assignees(groups projects.UserGroups) []twapi.Relationship {
+	list := relationships(groups.UserIDs, "users")
+	list = append(list, relationships(groups.CompanyIDs, "companies")...)
+	return append(list, relationships(groups.TeamIDs, "teams")...)
+}
+
+// containsRelationship checks if any of the relationships has one of the IDs.
+func containsRelationship(list []twapi.Relationship, ids []int64) bool {
+	return slices.ContainsFunc(list, func(relationship twapi.Relationship) bool {
+		return slices.Contains(ids, relationship.ID)
+	})
+}
+
+// loggedAt combines the date and time of a timelog.
+func loggedAt(date twapi.Date, clock twapi.Time) time.Time {
+	day, hour := time.Time(date), time.Time(clock)
+	return time.Date(day.Year(), day.Month(), day.Day(), hour.Hour(), hour.Minute(), hour.Second(), 0, time.UTC)
+}
+
+// decodeBody decodes the JSON body of the request, writing a 400 Bad Request
+// response when it is invalid.
+func decodeBody(w http.ResponseWriter, r *http.Request, payload any) bool {
+	if err := json.NewDecoder(r.Body).Decode(payload); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid body: %s", err)
+		return false
+	}
+	return true
+}
+
+func writeNotFound(w http.ResponseWriter, kind string, id int64) {
+	writeError(w, http.StatusNotFound, "%s %d not found", kind, id)
+}
+
+// writeError writes an error in the format of the API errors.
+func writeError(w http.ResponseWriter, status int, format string, args ...any) {
+	writeJSON(w, status, map[string]any{
+		"errors": []map[string]any{{
+			"title":  http.StatusText(status),
+			"detail": fmt.Sprintf(format, args...),
+		}},
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
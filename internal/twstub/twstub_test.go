@@ -0,0 +1,107 @@
+package twstub_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/testutil"
+	"github.com/teamwork/mcp/internal/twprojects"
+	"github.com/teamwork/mcp/internal/twstub"
+)
+
+func TestServer(t *testing.T) {
+	mcpServer := testutil.ProjectsMCPServerStub(t, twstub.New())
+
+	// the stub is seeded with the project 1, the tasklist 2 and the task 3
+	steps := []struct {
+		name      string
+		method    string
+		arguments map[string]any
+		contains  string
+		isError   bool
+	}{{
+		name:      "get seeded project",
+		method:    twprojects.MethodProjectGet.String(),
+		arguments: map[string]any{"id": float64(1)},
+		contains:  "Demo project",
+	}, {
+		name:      "create task",
+		method:    twprojects.MethodTaskCreate.String(),
+		arguments: map[string]any{"name": "Write the release notes", "tasklist_id": float64(2)},
+	}, {
+		name:      "list tasklist tasks",
+		method:    twprojects.MethodTaskListByTasklist.String(),
+		arguments: map[string]any{"tasklist_id": float64(2)},
+		contains:  "Write the release notes",
+	}, {
+		name:      "update task",
+		method:    twprojects.MethodTaskUpdate.String(),
+		arguments: map[string]any{"id": float64(4), "name": "Publish the release notes"},
+	}, {
+		name:      "get task",
+		method:    twprojects.MethodTaskGet.String(),
+		arguments: map[string]any{"id": float64(4)},
+		contains:  "Publish the release notes",
+	}, {
+		name:   "log time",
+		method: twprojects.MethodTimelogCreate.String(),
+		arguments: map[string]any{
+			"task_id": float64(4),
+			"date":    "2025-03-01",
+			"time":    "09:00:00",
+			"hours":   float64(1),
+			"minutes": float64(30),
+		},
+	}, {
+		name:      "list project timelogs",
+		method:    twprojects.MethodTimelogListByProject.String(),
+		arguments: map[string]any{"project_id": float64(1)},
+		contains:  `"minutes":90`,
+	}, {
+		name:      "comment task",
+		method:    twprojects.MethodCommentCreate.String(),
+		arguments: map[string]any{"object": map[string]any{"type": "tasks", "id": float64(4)}, "body": "Done"},
+	}, {
+		name:      "get comment",
+		method:    twprojects.MethodCommentGet.String(),
+		arguments: map[string]any{"id": float64(6)},
+		contains:  `"body":"Done"`,
+	}, {
+		name:      "delete task",
+		method:    twprojects.MethodTaskDelete.String(),
+		arguments: map[string]any{"id": float64(4)},
+	}, {
+		name:      "get deleted task",
+		method:    twprojects.MethodTaskGet.String(),
+		arguments: map[string]any{"id": float64(4)},
+		isError:   true,
+	}}
+
+	// the steps share the state of the stub, so they run in order
+	for _, step := range steps {
+		testutil.ExecuteToolRequest(t, mcpServer, step.method, step.arguments,
+			testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+				toolResult, ok := result.(*mcp.CallToolResult)
+				if !ok {
+					t.Fatalf("%s: unexpected result type: %T", step.name, result)
+				}
+				if toolResult.IsError != step.isError {
+					t.Fatalf("%s: unexpected error state %v: %v", step.name, toolResult.IsError, toolResult.Content)
+				}
+				if step.contains == "" {
+					return
+				}
+				var text string
+				for _, content := range toolResult.Content {
+					if textContent, ok := content.(*mcp.TextContent); ok {
+						text += textContent.Text
+					}
+				}
+				if !strings.Contains(text, step.contains) {
+					t.Errorf("%s: expected result to contain %q, got %s", step.name, step.contains, text)
+				}
+			}),
+		)
+	}
+}
@@ -0,0 +1,315 @@
+// Package webhook serves operator-defined inbound webhooks, converting signed
+// events (e.g. a new email or ticket) into tool calls, such as creating a task.
+// It turns the server into a light automation hub without an external
+// orchestrator.
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/auth"
+	"github.com/teamwork/mcp/internal/config"
+	"github.com/teamwork/mcp/internal/toolsets"
+	"github.com/teamwork/twapi-go-sdk/session"
+)
+
+const (
+	// defaultSignatureHeader is the header with the signature of the Teamwork.com
+	// webhooks.
+	defaultSignatureHeader = "X-Projects-Signature"
+	// defaultTimestampHeader is the header with the time the payload was signed,
+	// in Unix seconds.
+	defaultTimestampHeader = "X-Webhook-Timestamp"
+	// signatureTolerance is the maximum difference between the signing time and
+	// the current time, so captured requests can't be replayed later.
+	signatureTolerance = 5 * time.Minute
+	// eventHeader is the header with the event of the Teamwork.com webhooks.
+	eventHeader = "X-Projects-Event"
+	// maxPayloadSize is the maximum size in bytes of a webhook payload.
+	maxPayloadSize = 1 << 20
+	// callTimeout is the maximum duration of the tool call.
+	callTimeout = 30 * time.Second
+)
+
+// rePlaceholder matches the references to payload fields in the arguments.
+var rePlaceholder = regexp.MustCompile(`{{\s*([\w.]+)\s*}}`)
+
+// Handler serves the inbound webhooks defined in the config file.
+type Handler struct {
+	resources config.Resources
+	tools     []toolsets.ToolWrapper
+	rules     map[string]config.WebhookRule
+
+	// seen are the signatures accepted within the tolerance, so the requests
+	// can't be replayed before they expire either
+	seenMutex sync.Mutex
+	seen      map[string]time.Time
+}
+
+// New creates a Handler for the given webhooks. Only the provided tools can be
+// referenced by the webhooks, and an error is returned if any webhook is
+// invalid, so misconfigurations are detected on startup. The results of the
+// tools are redacted like in the MCP server, as they are returned to the
+// caller of the webhook.
+func New(resources config.Resources, rules []config.WebhookRule, tools []toolsets.ToolWrapper) (*Handler, error) {
+	handler := &Handler{
+		resources: resources,
+		tools:     toolsets.UseToolMiddlewares(tools, config.UnattendedToolMiddlewares(resources)...),
+		rules:     make(map[string]config.WebhookRule, len(rules)),
+		seen:      make(map[string]time.Time),
+	}
+	for _, rule := range rules {
+		if handler.tool(rule.Tool) == nil {
+			return nil, fmt.Errorf("webhook %q: tool %q is not available for webhooks", rule.Name, rule.Tool)
+		}
+		handler.rules[rule.Name] = rule
+	}
+	return handler, nil
+}
+
+// ServeHTTP handles the webhook named in the "name" path value. The payload
+// signature is verified before the tool is called, and events not accepted by
+// the webhook are ignored.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	rule, ok := h.rules[r.PathValue("name")]
+	if !ok {
+		http.Error(w, "Not Found", http.StatusNotFound)
+		return
+	}
+	logger := h.resources.Logger().With(slog.String("webhook", rule.Name))
+
+	payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxPayloadSize))
+	if err != nil {
+		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+		return
+	}
+
+	signatureHeader := rule.SignatureHeader
+	if signatureHeader == "" {
+		signatureHeader = defaultSignatureHeader
+	}
+	timestampHeader := rule.TimestampHeader
+	if timestampHeader == "" {
+		timestampHeader = defaultTimestampHeader
+	}
+	secret := os.Getenv(rule.SecretEnv)
+	signature := r.Header.Get(signatureHeader)
+	if secret == "" || !VerifySignature([]byte(secret), payload, r.Header.Get(timestampHeader), signature) {
+		logger.Warn("invalid webhook signature")
+		http.Error(w, "Invalid signature", http.StatusUnauthorized)
+		return
+	}
+	if h.replayed(rule.Name, signature) {
+		logger.Warn("replayed webhook request")
+		http.Error(w, "Invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal(payload, &decoded); err != nil {
+		http.Error(w, "Invalid JSON payload", http.StatusBadRequest)
+		return
+	}
+	if !acceptsEvent(rule, r.Header.Get(eventHeader), decoded) {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	content, err := h.callTool(r.Context(), rule, decoded)
+	if err != nil {
+		logger.Error("failed to handle webhook", slog.String("error", err.Error()))
+		http.Error(w, "Failed to handle webhook", http.StatusBadGateway)
+		return
+	}
+	logger.Info("webhook handled")
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]string{"result": content})
+}
+
+// VerifySignature checks the hex encoded HMAC-SHA256 signature, optionally
+// prefixed by "sha256=", of the timestamp and the payload joined by a dot. The
+// timestamp is the signing time in Unix seconds, and it must be within the
+// tolerance of the current time.
+func VerifySignature(secret, payload []byte, timestamp, signature string) bool {
+	timestamp = strings.TrimSpace(timestamp)
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	if age := time.Since(time.Unix(seconds, 0)); age > signatureTolerance || age < -signatureTolerance {
+		return false
+	}
+	expected, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(signature), "sha256="))
+	if err != nil || len(expected) == 0 {
+		return false
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+// replayed checks if the signature was already accepted by the webhook,
+// recording it otherwise. The signatures are forgotten once their timestamp is
+// out of the tolerance, as VerifySignature rejects them from then on.
+func (h *Handler) replayed(webhook, signature string) bool {
+	h.seenMutex.Lock()
+	defer h.seenMutex.Unlock()
+
+	now := time.Now()
+	for key, seenAt := range h.seen {
+		if now.Sub(seenAt) > 2*signatureTolerance {
+			delete(h.seen, key)
+		}
+	}
+	key := webhook + "|" + strings.ToLower(strings.TrimPrefix(strings.TrimSpace(signature), "sha256="))
+	if _, ok := h.seen[key]; ok {
+		return true
+	}
+	h.seen[key] = now
+	return false
+}
+
+// acceptsEvent checks if the event, from the header or the payload, is accepted
+// by the webhook.
+func acceptsEvent(rule config.WebhookRule, event string, payload map[string]any) bool {
+	if len(rule.Events) == 0 {
+		return true
+	}
+	if event == "" {
+		event, _ = payload["event"].(string)
+	}
+	return slices.ContainsFunc(rule.Events, func(accepted string) bool {
+		return strings.EqualFold(accepted, event)
+	})
+}
+
+func (h *Handler) tool(name string) *toolsets.ToolWrapper {
+	index := slices.IndexFunc(h.tools, func(tool toolsets.ToolWrapper) bool {
+		return tool.Tool.Name == name
+	})
+	if index == -1 {
+		return nil
+	}
+	return &h.tools[index]
+}
+
+// callTool calls the webhook tool with the arguments built from the payload,
+// returning the text content of the result.
+func (h *Handler) callTool(ctx context.Context, rule config.WebhookRule, payload map[string]any) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, callTimeout)
+	defer cancel()
+
+	token := os.Getenv(rule.TokenEnv)
+	if token == "" {
+		return "", fmt.Errorf("environment variable %s is empty", rule.TokenEnv)
+	}
+	info, err := auth.GetBearerInfo(ctx, h.resources, token)
+	if err != nil {
+		return "", fmt.Errorf("failed to get bearer info: %w", err)
+	}
+	ctx = config.WithCrossRegion(ctx, !strings.EqualFold(h.resources.Info.AWSRegion, info.Region))
+	ctx = config.WithCustomerURL(ctx, info.URL)
+	ctx = config.WithScopes(ctx, info.Meta.Scopes)
+	ctx = config.WithUserID(ctx, info.UserID)
+	ctx = session.WithBearerTokenContext(ctx, session.NewBearerToken(token, info.URL))
+
+	arguments, err := json.Marshal(ReplacePayloadFields(rule.Arguments, payload))
+	if err != nil {
+		return "", fmt.Errorf("failed to encode arguments: %w", err)
+	}
+	result, err := h.tool(rule.Tool).Handler(ctx, &mcp.CallToolRequest{
+		Params: &mcp.CallToolParamsRaw{
+			Name:      rule.Tool,
+			Arguments: arguments,
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to call tool: %w", err)
+	}
+
+	var texts []string
+	for _, item := range result.Content {
+		if text, ok := item.(*mcp.TextContent); ok {
+			texts = append(texts, text.Text)
+		}
+	}
+	content := strings.Join(texts, "\n")
+	if result.IsError {
+		return "", errors.New("tool returned an error: " + content)
+	}
+	return content, nil
+}
+
+// ReplacePayloadFields replaces the "{{path.to.field}}" references in the
+// string values with the payload fields. A value made of a single reference is
+// replaced by the field as is, keeping its type (e.g. numbers for IDs).
+func ReplacePayloadFields(value any, payload map[string]any) any {
+	switch value := value.(type) {
+	case string:
+		if matches := rePlaceholder.FindStringSubmatch(value); matches != nil && matches[0] == value {
+			return payloadField(payload, matches[1])
+		}
+		return rePlaceholder.ReplaceAllStringFunc(value, func(placeholder string) string {
+			field := payloadField(payload, rePlaceholder.FindStringSubmatch(placeholder)[1])
+			switch field := field.(type) {
+			case nil:
+				return ""
+			case string:
+				return field
+			default:
+				encoded, _ := json.Marshal(field)
+				return string(encoded)
+			}
+		})
+	case map[string]any:
+		replaced := make(map[string]any, len(value))
+		for key, item := range value {
+			replaced[key] = ReplacePayloadFields(item, payload)
+		}
+		return replaced
+	case []any:
+		replaced := make([]any, len(value))
+		for i, item := range value {
+			replaced[i] = ReplacePayloadFields(item, payload)
+		}
+		return replaced
+	}
+	return value
+}
+
+// payloadField returns the payload field in the dot separated path, or nil if
+// it doesn't exist.
+func payloadField(payload map[string]any, path string) any {
+	var current any = payload
+	for key := range strings.SplitSeq(path, ".") {
+		object, ok := current.(map[string]any)
+		if !ok {
+			return nil
+		}
+		current = object[key]
+	}
+	return current
+}
@@ -0,0 +1,206 @@
+package webhook_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/config"
+	"github.com/teamwork/mcp/internal/toolsets"
+	"github.com/teamwork/mcp/internal/webhook"
+)
+
+func sign(secret, timestamp, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// timestamp returns the Unix time shifted by the given duration.
+func timestamp(shift time.Duration) string {
+	return strconv.FormatInt(time.Now().Add(shift).Unix(), 10)
+}
+
+func TestVerifySignature(t *testing.T) {
+	payload := `{"event":"TICKET.CREATED"}`
+	now := timestamp(0)
+
+	tests := []struct {
+		name      string
+		timestamp string
+		signature string
+		want      bool
+	}{{
+		name:      "valid",
+		timestamp: now,
+		signature: sign("secret", now, payload),
+		want:      true,
+	}, {
+		name:      "valid with prefix",
+		timestamp: now,
+		signature: "sha256=" + sign("secret", now, payload),
+		want:      true,
+	}, {
+		name:      "wrong secret",
+		timestamp: now,
+		signature: sign("other", now, payload),
+	}, {
+		name:      "not hex",
+		timestamp: now,
+		signature: "invalid",
+	}, {
+		name:      "missing",
+		timestamp: now,
+	}, {
+		name:      "changed timestamp",
+		timestamp: timestamp(-time.Minute),
+		signature: sign("secret", now, payload),
+	}, {
+		name:      "expired timestamp",
+		timestamp: timestamp(-time.Hour),
+		signature: sign("secret", timestamp(-time.Hour), payload),
+	}, {
+		name:      "future timestamp",
+		timestamp: timestamp(time.Hour),
+		signature: sign("secret", timestamp(time.Hour), payload),
+	}, {
+		name:      "missing timestamp",
+		signature: sign("secret", "", payload),
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := webhook.VerifySignature([]byte("secret"), []byte(payload), tt.timestamp, tt.signature)
+			if got != tt.want {
+				t.Errorf("expected %t, got %t", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestReplacePayloadFields(t *testing.T) {
+	payload := map[string]any{
+		"ticket": map[string]any{
+			"id":      float64(123),
+			"subject": "Printer on fire",
+		},
+	}
+	arguments := map[string]any{
+		"tasklist_id": float64(456),
+		"name":        "{{ticket.subject}}",
+		"description": "Ticket #{{ticket.id}}: {{ticket.subject}}{{ticket.missing}}",
+		"ticket_id":   "{{ ticket.id }}",
+		"tags":        []any{"{{ticket.subject}}"},
+	}
+	want := map[string]any{
+		"tasklist_id": float64(456),
+		"name":        "Printer on fire",
+		"description": "Ticket #123: Printer on fire",
+		"ticket_id":   float64(123),
+		"tags":        []any{"Printer on fire"},
+	}
+	if got := webhook.ReplacePayloadFields(arguments, payload); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	t.Setenv("TEST_WEBHOOK_SECRET", "secret")
+	resources, teardown, err := config.Load(io.Discard)
+	if err != nil {
+		t.Fatalf("failed to load configuration: %v", err)
+	}
+	defer teardown()
+
+	rules := []config.WebhookRule{{
+		Name:      "tickets",
+		SecretEnv: "TEST_WEBHOOK_SECRET",
+		Events:    []string{"TICKET.CREATED"},
+		Tool:      "custom-create_task",
+		TokenEnv:  "TEST_WEBHOOK_TOKEN",
+	}}
+	if _, err := webhook.New(resources, rules, nil); err == nil {
+		t.Fatal("expected an error for an unavailable tool")
+	}
+
+	tools := []toolsets.ToolWrapper{{
+		Tool: &mcp.Tool{Name: "custom-create_task"},
+		Handler: func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			t.Error("unexpected tool call")
+			return &mcp.CallToolResult{}, nil
+		},
+	}}
+	handler, err := webhook.New(resources, rules, tools)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/webhooks/{name}", handler)
+
+	now := timestamp(0)
+	tests := []struct {
+		name       string
+		path       string
+		payload    string
+		timestamp  string
+		signature  string
+		wantStatus int
+	}{{
+		name:       "unknown webhook",
+		path:       "/webhooks/unknown",
+		payload:    `{}`,
+		wantStatus: http.StatusNotFound,
+	}, {
+		name:       "invalid signature",
+		path:       "/webhooks/tickets",
+		payload:    `{"event":"TICKET.CREATED"}`,
+		timestamp:  now,
+		signature:  sign("other", now, `{"event":"TICKET.CREATED"}`),
+		wantStatus: http.StatusUnauthorized,
+	}, {
+		name:       "expired request",
+		path:       "/webhooks/tickets",
+		payload:    `{"event":"TICKET.CREATED"}`,
+		timestamp:  timestamp(-time.Hour),
+		signature:  sign("secret", timestamp(-time.Hour), `{"event":"TICKET.CREATED"}`),
+		wantStatus: http.StatusUnauthorized,
+	}, {
+		name:       "ignored event",
+		path:       "/webhooks/tickets",
+		payload:    `{"event":"TICKET.DELETED"}`,
+		timestamp:  now,
+		signature:  sign("secret", now, `{"event":"TICKET.DELETED"}`),
+		wantStatus: http.StatusNoContent,
+	}, {
+		// the same request as the previous case
+		name:       "replayed request",
+		path:       "/webhooks/tickets",
+		payload:    `{"event":"TICKET.DELETED"}`,
+		timestamp:  now,
+		signature:  sign("secret", now, `{"event":"TICKET.DELETED"}`),
+		wantStatus: http.StatusUnauthorized,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.payload))
+			req.Header.Set("X-Projects-Signature", tt.signature)
+			req.Header.Set("X-Webhook-Timestamp", tt.timestamp)
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+		})
+	}
+}
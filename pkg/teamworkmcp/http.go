@@ -0,0 +1,145 @@
+package teamworkmcp
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/auth"
+)
+
+// maxHTTPMessageSize is the maximum size in bytes of a JSON-RPC message
+// received over HTTP.
+const maxHTTPMessageSize = 4 << 20
+
+// httpLoopbackHosts are the hosts always accepted in the Host and Origin
+// headers by the HTTP handlers.
+var httpLoopbackHosts = []string{"localhost", "127.0.0.1", "::1"}
+
+// HTTPHandler returns a handler serving the server over the streamable HTTP
+// transport, with the credentials of the server. It is meant for local or
+// single-user deployments; multi-tenant deployments authenticating each request
+// should use the HTTP server.
+//
+// The clients must send the token set with WithHTTPToken as a bearer token, and
+// only the loopback hosts and the ones set with WithHTTPAllowedHosts are
+// accepted in the Host and Origin headers.
+func (s *Server) HTTPHandler() http.Handler {
+	return s.httpMiddleware(mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server {
+		return s.server
+	}, nil))
+}
+
+// SSEHandler returns a handler serving the server over the legacy SSE
+// transport, with the credentials of the server. See HTTPHandler.
+func (s *Server) SSEHandler() http.Handler {
+	return s.httpMiddleware(mcp.NewSSEHandler(func(*http.Request) *mcp.Server {
+		return s.server
+	}, nil))
+}
+
+// httpMiddleware authenticates the clients and injects the credentials in the
+// request context. Without valid credentials, the messages that require
+// authentication are answered with an unauthorized error, as done by the
+// transport wrapper in Run.
+func (s *Server) httpMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// a web page resolving its own host to the server (DNS rebinding) sends
+		// its host in the headers
+		if !s.httpAllowedHost(r.Host) {
+			http.Error(w, "Forbidden: host not allowed", http.StatusForbidden)
+			return
+		}
+		if origin := r.Header.Get("Origin"); origin != "" {
+			if originURL, err := url.Parse(origin); err != nil || !s.httpAllowedHost(originURL.Host) {
+				http.Error(w, "Forbidden: origin not allowed", http.StatusForbidden)
+				return
+			}
+		}
+		// the requests run with the credentials of the server, so any client
+		// reaching it could use them
+		if !s.httpAuthorized(r) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "Unauthorized: send the TW_MCP_HTTP_TOKEN token as a bearer token",
+				http.StatusUnauthorized)
+			return
+		}
+
+		r = r.WithContext(s.Context(r.Context()))
+		if s.authenticated || r.Method != http.MethodPost {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxHTTPMessageSize))
+		if err != nil {
+			http.Error(w, "Failed to read request body", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		msg, err := jsonrpc.DecodeMessage(body)
+		if err != nil {
+			// invalid messages are answered by the transport
+			next.ServeHTTP(w, r)
+			return
+		}
+		req, ok := msg.(*jsonrpc.Request)
+		if !ok || auth.BypassMethod(req.Method) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !req.IsCall() {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+
+		resp, err := unauthorizedResponse(req.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		encoded, err := jsonrpc.EncodeMessage(resp)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write(encoded)
+	})
+}
+
+// httpAllowedHost reports whether the host, with an optional port, is accepted
+// in the Host and Origin headers.
+func (s *Server) httpAllowedHost(host string) bool {
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+	if host == "" {
+		return false
+	}
+	allowed := slices.Concat(httpLoopbackHosts, s.resources.Info.HTTPAllowedHosts)
+	return slices.ContainsFunc(allowed, func(allowedHost string) bool {
+		return strings.EqualFold(allowedHost, host)
+	})
+}
+
+// httpAuthorized reports whether the request has the token shared with the
+// clients. Without a token, no request is authorized.
+func (s *Server) httpAuthorized(r *http.Request) bool {
+	token := s.resources.Info.HTTPToken
+	if token == "" {
+		return false
+	}
+	bearerToken, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(bearerToken), []byte(token)) == 1
+}
@@ -0,0 +1,403 @@
+// Package teamworkmcp embeds the Teamwork.com MCP server in Go programs, so
+// they can serve it in-process instead of running the STDIO binary.
+//
+// The server is configured with the same environment variables as the STDIO
+// binary (e.g. TW_MCP_API_URL), which can be overridden with the options:
+//
+//	server, err := teamworkmcp.New(ctx,
+//		teamworkmcp.WithBearerToken(token),
+//		teamworkmcp.WithReadOnly(true),
+//	)
+//	if err != nil {
+//		return err
+//	}
+//	defer server.Close()
+//
+//	return server.Run(ctx, &mcp.StdioTransport{})
+package teamworkmcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/auth"
+	"github.com/teamwork/mcp/internal/config"
+	"github.com/teamwork/mcp/internal/notifier"
+	"github.com/teamwork/mcp/internal/textextract"
+	"github.com/teamwork/mcp/internal/toolsets"
+	"github.com/teamwork/mcp/internal/twdesk"
+	"github.com/teamwork/mcp/internal/twprojects"
+	"github.com/teamwork/twapi-go-sdk/session"
+)
+
+// ToolsetProvider provides the tools of a custom toolset, registered with
+// WithToolsetProvider.
+type ToolsetProvider = toolsets.ToolsetProvider
+
+// ToolWrapper is a tool and its handler.
+type ToolWrapper = toolsets.ToolWrapper
+
+// Method identifies a toolset or a tool.
+type Method = toolsets.Method
+
+// ToolMiddleware decorates the tool handlers, registered with
+// WithToolMiddleware.
+type ToolMiddleware = toolsets.ToolMiddleware
+
+// Notifier posts a summary of the write operations, registered with
+// WithNotifier.
+type Notifier = notifier.Notifier
+
+// Notification describes a write operation performed by a tool.
+type Notification = notifier.Notification
+
+// options contains the configuration of the server.
+type options struct {
+	providers        []ToolsetProvider
+	middlewares      []ToolMiddleware
+	notifiers        []toolNotifier
+	toolsets         []string
+	readOnly         bool
+	logOutput        io.Writer
+	bearerToken      *string
+	defaultProjectID *int64
+	finance          *bool
+	httpToken        *string
+	httpAllowedHosts []string
+}
+
+// Option configures the server.
+type Option func(*options)
+
+// WithToolsets restricts the server to the given toolsets or tools (e.g.
+// "twprojects-list_projects"). By default all toolsets are enabled.
+func WithToolsets(toolsets ...string) Option {
+	return func(o *options) {
+		o.toolsets = toolsets
+	}
+}
+
+// WithReadOnly restricts the server to read-only operations.
+func WithReadOnly(readOnly bool) Option {
+	return func(o *options) {
+		o.readOnly = readOnly
+	}
+}
+
+// WithLogOutput sets where the logs are written. By default they are written to
+// the standard error.
+func WithLogOutput(w io.Writer) Option {
+	return func(o *options) {
+		o.logOutput = w
+	}
+}
+
+// WithBearerToken sets the bearer token used to authenticate with Teamwork.com,
+// instead of the TW_MCP_BEARER_TOKEN environment variable.
+func WithBearerToken(token string) Option {
+	return func(o *options) {
+		o.bearerToken = &token
+	}
+}
+
+// WithHTTPToken sets the token the clients of HTTPHandler and SSEHandler must
+// send as a bearer token, instead of the TW_MCP_HTTP_TOKEN environment
+// variable. Without a token, the handlers reject all requests.
+func WithHTTPToken(token string) Option {
+	return func(o *options) {
+		o.httpToken = &token
+	}
+}
+
+// WithHTTPAllowedHosts adds hosts (e.g. "mcp.example.com") accepted in the Host
+// and Origin headers by HTTPHandler and SSEHandler, besides the loopback ones
+// and the TW_MCP_HTTP_ALLOWED_HOSTS environment variable.
+func WithHTTPAllowedHosts(hosts ...string) Option {
+	return func(o *options) {
+		o.httpAllowedHosts = append(o.httpAllowedHosts, hosts...)
+	}
+}
+
+// WithDefaultProject sets the project used by the tools when the project_id
+// argument is omitted, instead of the TW_MCP_DEFAULT_PROJECT_ID environment
+// variable.
+func WithDefaultProject(projectID int64) Option {
+	return func(o *options) {
+		o.defaultProjectID = &projectID
+	}
+}
+
+// WithFinance sets whether the tools and result fields with financial data
+// (e.g. rates, costs and budgets) are available, instead of the TW_MCP_FINANCE
+// environment variable. It is independent of the read-only mode.
+func WithFinance(enabled bool) Option {
+	return func(o *options) {
+		o.finance = &enabled
+	}
+}
+
+// WithToolsetProvider adds custom toolsets to the server (e.g. company-specific
+// composite tools). They are enabled, and restricted to read-only operations, in
+// the same way as the built-in toolsets.
+func WithToolsetProvider(providers ...ToolsetProvider) Option {
+	return func(o *options) {
+		o.providers = append(o.providers, providers...)
+	}
+}
+
+// WithToolMiddleware decorates the handlers of all tools, e.g. to log the calls,
+// collect metrics or enforce policies. The first middleware is the outermost.
+func WithToolMiddleware(middlewares ...ToolMiddleware) Option {
+	return func(o *options) {
+		o.middlewares = append(o.middlewares, middlewares...)
+	}
+}
+
+// toolNotifier is a notifier and the tools that notify it.
+type toolNotifier struct {
+	notifier Notifier
+	tools    []string
+}
+
+// WithNotifier posts a notification each time a write tool succeeds (e.g. to a
+// chat channel). When tools are given, only those tools notify. A Slack
+// notifier is added when the TW_MCP_SLACK_WEBHOOK_URL environment variable is
+// set.
+func WithNotifier(n Notifier, tools ...string) Option {
+	return func(o *options) {
+		o.notifiers = append(o.notifiers, toolNotifier{notifier: n, tools: tools})
+	}
+}
+
+// Server is a Teamwork.com MCP server bound to a single installation.
+type Server struct {
+	server        *mcp.Server
+	groups        []*toolsets.ToolsetGroup
+	resources     config.Resources
+	teardown      func()
+	authenticated bool
+	customerURL   string
+	userID        int64
+}
+
+// New creates the server, resolving the installation of the bearer token. When
+// the bearer token is missing or invalid the server is still created, but only
+// the protocol methods that bypass authentication are served.
+func New(ctx context.Context, opts ...Option) (*Server, error) {
+	o := options{
+		toolsets:  []string{toolsets.MethodAll.String()},
+		logOutput: os.Stderr,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	resources, teardown, err := config.Load(o.logOutput)
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	if o.bearerToken != nil {
+		resources.Info.BearerToken = *o.bearerToken
+	}
+	if o.defaultProjectID != nil {
+		resources.Info.DefaultProjectID = *o.defaultProjectID
+	}
+	if o.finance != nil {
+		resources.Info.Finance = *o.finance
+	}
+	if o.httpToken != nil {
+		resources.Info.HTTPToken = *o.httpToken
+	}
+	resources.Info.HTTPAllowedHosts = append(resources.Info.HTTPAllowedHosts, o.httpAllowedHosts...)
+
+	s := &Server{
+		resources: resources,
+		teardown:  teardown,
+	}
+	if resources.Info.BearerToken != "" {
+		// detect the installation from the bearer token
+		if info, err := auth.GetBearerInfo(ctx, resources, resources.Info.BearerToken); err != nil {
+			resources.Logger().Error("failed to get bearer info",
+				slog.String("error", err.Error()),
+			)
+		} else {
+			s.authenticated = true
+			s.customerURL = info.URL
+			s.userID = info.UserID
+		}
+	}
+
+	projectsGroup := twprojects.DefaultToolsetGroup(o.readOnly, false, resources.TeamworkEngine(),
+		twprojects.WithReportTemplates(resources.FileConfig().Reports),
+		twprojects.WithDefaultProject(resources.Info.DefaultProjectID),
+		twprojects.WithTokenBudget(resources.Info.SessionTokenBudget),
+		twprojects.WithAllowedProjects(resources.Info.AllowedProjectIDs),
+		twprojects.WithFinance(resources.Info.Finance),
+		twprojects.WithCompany(resources.Info.CompanyID),
+		twprojects.WithPassthrough(resources.Info.Passthrough),
+		twprojects.WithCompaction(resources.Info.CompactResponses),
+		twprojects.WithDisplayTimezone(resources.DisplayTimezone()),
+		twprojects.WithExportMemoryLimit(resources.Info.ExportMemoryLimit),
+		twprojects.WithSLARules(resources.FileConfig().SLARules),
+		twprojects.WithRoleTemplates(resources.FileConfig().RoleTemplates),
+		twprojects.WithTextExtractors(textextract.Commands(resources.FileConfig().TextExtractors)),
+		twprojects.WithMacros(resources.FileConfig().Macros),
+		twprojects.WithTaskDefaults(resources.FileConfig().TaskDefaults),
+		twprojects.WithWriteLog(resources.WriteLog()),
+	)
+	customGroup := toolsets.NewToolsetGroup(o.readOnly)
+	for _, provider := range o.providers {
+		customGroup.AddProvider(provider)
+	}
+	groups := []*toolsets.ToolsetGroup{projectsGroup, customGroup}
+	// the desk tools aren't restricted to the customers and tickets of a client
+	// company, so they aren't available when bound to one
+	if resources.Info.CompanyID == 0 {
+		groups = slices.Insert(groups, 1, twdesk.DefaultToolsetGroup(o.readOnly, resources.DeskClient()))
+	}
+
+	// the methods are validated once all toolsets are registered
+	methods, err := parseMethods(o.toolsets)
+	if err != nil {
+		teardown()
+		return nil, err
+	}
+	if err := enableToolsets(methods, groups...); err != nil {
+		teardown()
+		return nil, err
+	}
+
+	if resources.Info.Slack.WebhookURL != "" {
+		o.notifiers = append(o.notifiers, toolNotifier{
+			notifier: notifier.NewSlack(resources.Info.Slack.WebhookURL),
+			tools:    resources.Info.Slack.Tools,
+		})
+	}
+	for _, group := range groups {
+		group.Use(o.middlewares...)
+		for _, n := range o.notifiers {
+			notifier.Apply(group, n.notifier, n.tools, resources.Logger())
+		}
+	}
+
+	s.groups = groups
+	s.server = config.NewMCPServer(resources, s.groups...)
+	return s, nil
+}
+
+// EnableToolset enables a toolset (e.g. "projects") of the running server. The
+// connected clients are notified that the tool list changed.
+func (s *Server) EnableToolset(method Method) error {
+	return s.setToolsetEnabled(method, true)
+}
+
+// DisableToolset disables a toolset (e.g. "projects") of the running server.
+// The connected clients are notified that the tool list changed.
+func (s *Server) DisableToolset(method Method) error {
+	return s.setToolsetEnabled(method, false)
+}
+
+func (s *Server) setToolsetEnabled(method Method, enabled bool) error {
+	for _, group := range s.groups {
+		if _, err := group.GetToolset(method); err != nil {
+			continue
+		}
+		return group.SetToolsetEnabled(s.server, method, enabled)
+	}
+	return toolsets.NewToolsetDoesNotExistError(method)
+}
+
+// enableToolsets enables the toolsets in the group where they exist.
+func enableToolsets(methods []toolsets.Method, groups ...*toolsets.ToolsetGroup) error {
+	for _, method := range methods {
+		var found bool
+		for _, group := range groups {
+			if _, err := group.GetToolset(method); err != nil && method != toolsets.MethodAll {
+				continue
+			}
+			if err := group.EnableToolsets(method); err != nil {
+				return fmt.Errorf("failed to enable toolsets: %w", err)
+			}
+			found = true
+		}
+		if !found {
+			return fmt.Errorf("failed to enable toolsets: %w", toolsets.NewToolsetDoesNotExistError(method))
+		}
+	}
+	return nil
+}
+
+// parseMethods converts the toolset names, checking they are registered.
+func parseMethods(names []string) ([]toolsets.Method, error) {
+	methods := make([]toolsets.Method, 0, len(names))
+	var errs error
+	for _, name := range names {
+		if method := toolsets.Method(name); method.IsRegistered() {
+			methods = append(methods, method)
+		} else {
+			errs = errors.Join(errs, fmt.Errorf("invalid toolset method: %q", name))
+		}
+	}
+	return methods, errs
+}
+
+// MCPServer returns the underlying MCP server, e.g. to add middlewares. The
+// tools must be called with a context prepared by Context.
+func (s *Server) MCPServer() *mcp.Server {
+	return s.server
+}
+
+// Logger returns the logger of the server.
+func (s *Server) Logger() *slog.Logger {
+	return s.resources.Logger()
+}
+
+// Authenticated reports whether the bearer token was accepted by Teamwork.com.
+func (s *Server) Authenticated() bool {
+	return s.authenticated
+}
+
+// Context returns a copy of the context with the installation and credentials
+// used by the tools.
+func (s *Server) Context(ctx context.Context) context.Context {
+	if !s.authenticated {
+		return ctx
+	}
+	// inject customer URL in the context
+	ctx = config.WithCustomerURL(ctx, s.customerURL)
+	// inject user in the context
+	ctx = config.WithUserID(ctx, s.userID)
+	// inject bearer token in the context
+	return session.WithBearerTokenContext(ctx, session.NewBearerToken(s.resources.Info.BearerToken, s.customerURL))
+}
+
+// Run serves a single session over the transport until the client disconnects
+// or the context is cancelled. Without valid credentials, the requests that
+// require authentication are answered with an unauthorized error.
+func (s *Server) Run(ctx context.Context, transport mcp.Transport) error {
+	return s.server.Run(s.Context(ctx), s.transport(transport))
+}
+
+// Connect starts a session over the transport, returning without waiting for
+// the session to end. It is useful with in-memory transports.
+func (s *Server) Connect(ctx context.Context, transport mcp.Transport) (*mcp.ServerSession, error) {
+	return s.server.Connect(s.Context(ctx), s.transport(transport), nil)
+}
+
+func (s *Server) transport(transport mcp.Transport) mcp.Transport {
+	if s.authenticated {
+		return transport
+	}
+	return unauthenticatedTransport{Transport: transport}
+}
+
+// Close releases the resources of the server (e.g. flushing the error reports).
+func (s *Server) Close() {
+	s.teardown()
+}
@@ -0,0 +1,602 @@
+package teamworkmcp_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/pkg/teamworkmcp"
+)
+
+func TestServer(t *testing.T) {
+	t.Setenv("TW_MCP_BEARER_TOKEN", "")
+
+	server, err := teamworkmcp.New(t.Context(),
+		teamworkmcp.WithReadOnly(true),
+		teamworkmcp.WithLogOutput(io.Discard),
+	)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	defer server.Close()
+
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	if _, err := server.Connect(t.Context(), serverTransport); err != nil {
+		t.Fatalf("failed to connect to server: %v", err)
+	}
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	clientSession, err := client.Connect(t.Context(), clientTransport, nil)
+	if err != nil {
+		t.Fatalf("failed to connect to client: %v", err)
+	}
+	defer clientSession.Close() //nolint:errcheck
+
+	tools, err := clientSession.ListTools(t.Context(), nil)
+	if err != nil {
+		t.Fatalf("failed to list tools: %v", err)
+	}
+	if len(tools.Tools) == 0 {
+		t.Error("expected tools to be listed")
+	}
+
+	// the tools require authentication, but the session is kept alive
+	if _, err := clientSession.CallTool(t.Context(), &mcp.CallToolParams{Name: "twprojects-get_enums"}); err == nil {
+		t.Error("expected an unauthorized error")
+	}
+	if _, err := clientSession.ListTools(t.Context(), nil); err != nil {
+		t.Errorf("failed to list tools after an unauthorized call: %v", err)
+	}
+}
+
+func TestServerInfo(t *testing.T) {
+	t.Setenv("TW_MCP_VERSION", "v1.2.3")
+
+	server, err := teamworkmcp.New(t.Context(), teamworkmcp.WithLogOutput(io.Discard))
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	defer server.Close()
+
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	if _, err := server.MCPServer().Connect(t.Context(), serverTransport, nil); err != nil {
+		t.Fatalf("failed to connect to server: %v", err)
+	}
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	clientSession, err := client.Connect(t.Context(), clientTransport, nil)
+	if err != nil {
+		t.Fatalf("failed to connect to client: %v", err)
+	}
+	defer clientSession.Close() //nolint:errcheck
+
+	info, ok := clientSession.InitializeResult().Meta["com.teamwork/server"].(map[string]any)
+	if !ok || info["version"] != "1.2.3" || info["catalogVersion"] == "" {
+		t.Fatalf("unexpected initialize metadata: %v", clientSession.InitializeResult().Meta)
+	}
+
+	result, err := clientSession.CallTool(t.Context(), &mcp.CallToolParams{Name: "get_server_info"})
+	if err != nil {
+		t.Fatalf("failed to call tool: %v", err)
+	}
+	structured, ok := result.StructuredContent.(map[string]any)
+	if !ok || structured["catalogVersion"] != info["catalogVersion"] {
+		t.Errorf("expected the catalog version %v, got %v", info["catalogVersion"], result.StructuredContent)
+	}
+}
+
+func TestServerInvalidToolset(t *testing.T) {
+	if _, err := teamworkmcp.New(t.Context(), teamworkmcp.WithToolsets("unknown")); err == nil {
+		t.Error("expected an error for an unknown toolset")
+	}
+}
+
+func TestServerInvalidCompany(t *testing.T) {
+	t.Setenv("TW_MCP_COMPANY_ID", "12a")
+
+	if _, err := teamworkmcp.New(t.Context(), teamworkmcp.WithLogOutput(io.Discard)); err == nil {
+		t.Error("expected an error for an invalid company ID")
+	}
+}
+
+func TestServerCompany(t *testing.T) {
+	t.Setenv("TW_MCP_COMPANY_ID", "7")
+
+	server, err := teamworkmcp.New(t.Context(), teamworkmcp.WithLogOutput(io.Discard))
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	defer server.Close()
+
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	if _, err := server.Connect(t.Context(), serverTransport); err != nil {
+		t.Fatalf("failed to connect to server: %v", err)
+	}
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	clientSession, err := client.Connect(t.Context(), clientTransport, nil)
+	if err != nil {
+		t.Fatalf("failed to connect to client: %v", err)
+	}
+	defer clientSession.Close() //nolint:errcheck
+
+	tools, err := clientSession.ListTools(t.Context(), nil)
+	if err != nil {
+		t.Fatalf("failed to list tools: %v", err)
+	}
+	if len(tools.Tools) == 0 {
+		t.Error("expected tools to be listed")
+	}
+	// the desk tools aren't restricted to the company
+	for _, tool := range tools.Tools {
+		if strings.HasPrefix(tool.Name, "twdesk-") {
+			t.Errorf("unexpected desk tool %q in a server bound to a company", tool.Name)
+		}
+	}
+
+	if _, err := teamworkmcp.New(t.Context(), teamworkmcp.WithLogOutput(io.Discard),
+		teamworkmcp.WithToolsets("desk")); err == nil {
+		t.Error("expected an error enabling the desk toolsets")
+	}
+}
+
+type customProvider struct{}
+
+func (customProvider) Method() teamworkmcp.Method { return "custom" }
+func (customProvider) Description() string        { return "Custom tools." }
+func (customProvider) Methods() []teamworkmcp.Method {
+	return []teamworkmcp.Method{"custom-get_summary"}
+}
+func (customProvider) WriteTools() []teamworkmcp.ToolWrapper { return nil }
+func (customProvider) ReadTools() []teamworkmcp.ToolWrapper {
+	return []teamworkmcp.ToolWrapper{{
+		Tool: &mcp.Tool{
+			Name:        "custom-get_summary",
+			Description: "Get a summary.",
+			Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+			InputSchema: &jsonschema.Schema{Type: "object"},
+		},
+		Handler: func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return &mcp.CallToolResult{}, nil
+		},
+	}}
+}
+
+func TestServerToolsetProvider(t *testing.T) {
+	server, err := teamworkmcp.New(t.Context(),
+		teamworkmcp.WithToolsetProvider(customProvider{}),
+		teamworkmcp.WithToolsets("custom"),
+		teamworkmcp.WithLogOutput(io.Discard),
+	)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	defer server.Close()
+
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	if _, err := server.Connect(t.Context(), serverTransport); err != nil {
+		t.Fatalf("failed to connect to server: %v", err)
+	}
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	clientSession, err := client.Connect(t.Context(), clientTransport, nil)
+	if err != nil {
+		t.Fatalf("failed to connect to client: %v", err)
+	}
+	defer clientSession.Close() //nolint:errcheck
+
+	tools, err := clientSession.ListTools(t.Context(), nil)
+	if err != nil {
+		t.Fatalf("failed to list tools: %v", err)
+	}
+	if len(tools.Tools) != 1 || tools.Tools[0].Name != "custom-get_summary" {
+		t.Errorf("unexpected tools: %v", tools.Tools)
+	}
+}
+
+func TestServerEnableToolset(t *testing.T) {
+	t.Setenv("TW_MCP_BEARER_TOKEN", "")
+
+	server, err := teamworkmcp.New(t.Context(),
+		teamworkmcp.WithToolsetProvider(customProvider{}),
+		teamworkmcp.WithToolsets("custom"),
+		teamworkmcp.WithLogOutput(io.Discard),
+	)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	defer server.Close()
+
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	if _, err := server.Connect(t.Context(), serverTransport); err != nil {
+		t.Fatalf("failed to connect to server: %v", err)
+	}
+	changed := make(chan struct{}, 1)
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, &mcp.ClientOptions{
+		ToolListChangedHandler: func(context.Context, *mcp.ToolListChangedRequest) {
+			// a notification is sent for each tool added or removed
+			select {
+			case changed <- struct{}{}:
+			default:
+			}
+		},
+	})
+	clientSession, err := client.Connect(t.Context(), clientTransport, nil)
+	if err != nil {
+		t.Fatalf("failed to connect to client: %v", err)
+	}
+	defer clientSession.Close() //nolint:errcheck
+
+	countTools := func() int {
+		tools, err := clientSession.ListTools(t.Context(), nil)
+		if err != nil {
+			t.Fatalf("failed to list tools: %v", err)
+		}
+		return len(tools.Tools)
+	}
+	if count := countTools(); count != 1 {
+		t.Fatalf("expected 1 tool, got %d", count)
+	}
+
+	if err := server.EnableToolset("projects"); err != nil {
+		t.Fatalf("failed to enable toolset: %v", err)
+	}
+	select {
+	case <-changed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a tool list changed notification")
+	}
+	// the cached tool list is invalidated
+	if count := countTools(); count <= 1 {
+		t.Errorf("expected the projects tools, got %d tools", count)
+	}
+
+	if err := server.DisableToolset("projects"); err != nil {
+		t.Fatalf("failed to disable toolset: %v", err)
+	}
+	if count := countTools(); count != 1 {
+		t.Errorf("expected 1 tool after disabling the toolset, got %d", count)
+	}
+}
+
+func TestServerToolMiddleware(t *testing.T) {
+	var calls []string
+	middleware := func(next mcp.ToolHandler) mcp.ToolHandler {
+		return func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			calls = append(calls, request.Params.Name)
+			return next(ctx, request)
+		}
+	}
+
+	server, err := teamworkmcp.New(t.Context(),
+		teamworkmcp.WithToolsetProvider(customProvider{}),
+		teamworkmcp.WithToolsets("custom"),
+		teamworkmcp.WithToolMiddleware(middleware),
+		teamworkmcp.WithLogOutput(io.Discard),
+	)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	defer server.Close()
+
+	// the underlying server is used directly, as the custom tool does not need
+	// authentication
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	if _, err := server.MCPServer().Connect(t.Context(), serverTransport, nil); err != nil {
+		t.Fatalf("failed to connect to server: %v", err)
+	}
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	clientSession, err := client.Connect(t.Context(), clientTransport, nil)
+	if err != nil {
+		t.Fatalf("failed to connect to client: %v", err)
+	}
+	defer clientSession.Close() //nolint:errcheck
+
+	if _, err := clientSession.CallTool(t.Context(), &mcp.CallToolParams{Name: "custom-get_summary"}); err != nil {
+		t.Fatalf("failed to call tool: %v", err)
+	}
+	if len(calls) != 1 || calls[0] != "custom-get_summary" {
+		t.Errorf("unexpected middleware calls: %v", calls)
+	}
+}
+
+// bearerTokenTransport sends the bearer token in all requests.
+type bearerTokenTransport struct {
+	token string
+}
+
+func (t bearerTokenTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.Header.Set("Authorization", "Bearer "+t.token)
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestServerHTTPHandler(t *testing.T) {
+	t.Setenv("TW_MCP_BEARER_TOKEN", "")
+
+	server, err := teamworkmcp.New(t.Context(),
+		teamworkmcp.WithHTTPToken("secret"),
+		teamworkmcp.WithLogOutput(io.Discard),
+	)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	defer server.Close()
+
+	httpServer := httptest.NewServer(server.HTTPHandler())
+	defer httpServer.Close()
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	clientSession, err := client.Connect(t.Context(), &mcp.StreamableClientTransport{
+		Endpoint:   httpServer.URL,
+		HTTPClient: &http.Client{Transport: bearerTokenTransport{token: "secret"}},
+	}, nil)
+	if err != nil {
+		t.Fatalf("failed to connect to server: %v", err)
+	}
+	defer clientSession.Close() //nolint:errcheck
+
+	if _, err := clientSession.ListTools(t.Context(), nil); err != nil {
+		t.Errorf("failed to list tools: %v", err)
+	}
+	if _, err := clientSession.CallTool(t.Context(), &mcp.CallToolParams{Name: "twprojects-get_enums"}); err == nil {
+		t.Error("expected an unauthorized error")
+	}
+}
+
+func TestServerHTTPHandlerAuthentication(t *testing.T) {
+	t.Setenv("TW_MCP_BEARER_TOKEN", "")
+	t.Setenv("TW_MCP_HTTP_TOKEN", "")
+
+	tests := []struct {
+		name    string
+		options []teamworkmcp.Option
+		host    string
+		origin  string
+		token   string
+		status  int
+	}{{
+		name:    "authorized",
+		options: []teamworkmcp.Option{teamworkmcp.WithHTTPToken("secret")},
+		origin:  "http://localhost:6274",
+		token:   "secret",
+		status:  http.StatusOK,
+	}, {
+		name:    "allowed host",
+		options: []teamworkmcp.Option{teamworkmcp.WithHTTPToken("secret"), teamworkmcp.WithHTTPAllowedHosts("mcp.example.com")},
+		host:    "mcp.example.com:8080",
+		token:   "secret",
+		status:  http.StatusOK,
+	}, {
+		name:   "no token configured",
+		token:  "secret",
+		status: http.StatusUnauthorized,
+	}, {
+		name:    "missing token",
+		options: []teamworkmcp.Option{teamworkmcp.WithHTTPToken("secret")},
+		status:  http.StatusUnauthorized,
+	}, {
+		name:    "wrong token",
+		options: []teamworkmcp.Option{teamworkmcp.WithHTTPToken("secret")},
+		token:   "guess",
+		status:  http.StatusUnauthorized,
+	}, {
+		name:    "rebound host",
+		options: []teamworkmcp.Option{teamworkmcp.WithHTTPToken("secret")},
+		host:    "attacker.example.com:8080",
+		token:   "secret",
+		status:  http.StatusForbidden,
+	}, {
+		name:    "cross-origin page",
+		options: []teamworkmcp.Option{teamworkmcp.WithHTTPToken("secret")},
+		origin:  "https://attacker.example.com",
+		token:   "secret",
+		status:  http.StatusForbidden,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, err := teamworkmcp.New(t.Context(), append(tt.options, teamworkmcp.WithLogOutput(io.Discard))...)
+			if err != nil {
+				t.Fatalf("failed to create server: %v", err)
+			}
+			defer server.Close()
+
+			body := `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18",` +
+				`"capabilities":{},"clientInfo":{"name":"test-client","version":"1.0.0"}}}`
+			r := httptest.NewRequest(http.MethodPost, "http://localhost:8080/", strings.NewReader(body))
+			r.Header.Set("Content-Type", "application/json")
+			r.Header.Set("Accept", "application/json, text/event-stream")
+			if tt.host != "" {
+				r.Host = tt.host
+			}
+			if tt.origin != "" {
+				r.Header.Set("Origin", tt.origin)
+			}
+			if tt.token != "" {
+				r.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			w := httptest.NewRecorder()
+			server.HTTPHandler().ServeHTTP(w, r)
+
+			if w.Code != tt.status {
+				t.Errorf("expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+type entityProvider struct{}
+
+func (entityProvider) Method() teamworkmcp.Method { return "entity" }
+func (entityProvider) Description() string        { return "Entity tools." }
+func (entityProvider) Methods() []teamworkmcp.Method {
+	return []teamworkmcp.Method{"entity-get_task", "entity-get_user"}
+}
+func (entityProvider) WriteTools() []teamworkmcp.ToolWrapper { return nil }
+func (entityProvider) ReadTools() []teamworkmcp.ToolWrapper {
+	return []teamworkmcp.ToolWrapper{{
+		Tool: &mcp.Tool{
+			Name:        "entity-get_task",
+			Description: "Get a task.",
+			Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+			InputSchema: &jsonschema.Schema{Type: "object"},
+			OutputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"task": {
+						Type: "object",
+						Properties: map[string]*jsonschema.Schema{
+							"id":        {Type: "integer"},
+							"projectId": {Types: []string{"null", "integer"}},
+							"tagIds":    {Type: "array", Items: &jsonschema.Schema{Type: "integer"}},
+							"progress":  {Type: "integer"},
+						},
+					},
+				},
+			},
+		},
+		Handler: func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			type task struct {
+				ID        int64   `json:"id"`
+				ProjectID int64   `json:"projectId"`
+				TagIDs    []int64 `json:"tagIds"`
+				Progress  int64   `json:"progress"`
+			}
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{
+					Text: `{"task":{"id":9007199254740993,"projectId":12,"tagIds":[3],"progress":50}}`,
+				}},
+				StructuredContent: map[string]task{
+					"task": {ID: 9007199254740993, ProjectID: 12, TagIDs: []int64{3}, Progress: 50},
+				},
+			}, nil
+		},
+	}, {
+		Tool: &mcp.Tool{
+			Name:        "entity-get_user",
+			Description: "Get a user.",
+			Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+			InputSchema: &jsonschema.Schema{Type: "object"},
+		},
+		Handler: func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{
+					Text: `{"user":{"id":5,"firstName":"Jane","email":"jane@example.com","phone-number-mobile":"555 1234",` +
+						`"hourlyRate":{"amount":5000},"title":"Call +353 1 234 5678 or mail jane@example.com"}}`,
+				}},
+			}, nil
+		},
+	}}
+}
+
+func TestServerStringIDs(t *testing.T) {
+	t.Setenv("TW_MCP_STRING_IDS", "true")
+
+	server, err := teamworkmcp.New(t.Context(),
+		teamworkmcp.WithToolsetProvider(entityProvider{}),
+		teamworkmcp.WithToolsets("entity"),
+		teamworkmcp.WithLogOutput(io.Discard),
+	)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	defer server.Close()
+
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	if _, err := server.MCPServer().Connect(t.Context(), serverTransport, nil); err != nil {
+		t.Fatalf("failed to connect to server: %v", err)
+	}
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	clientSession, err := client.Connect(t.Context(), clientTransport, nil)
+	if err != nil {
+		t.Fatalf("failed to connect to client: %v", err)
+	}
+	defer clientSession.Close() //nolint:errcheck
+
+	result, err := clientSession.CallTool(t.Context(), &mcp.CallToolParams{Name: "entity-get_task"})
+	if err != nil {
+		t.Fatalf("failed to call tool: %v", err)
+	}
+	expected := `{"task":{"id":"9007199254740993","progress":50,"projectId":"12","tagIds":["3"]}}`
+	if text, ok := result.Content[0].(*mcp.TextContent); !ok || text.Text != expected {
+		t.Errorf("expected %s, got %v", expected, result.Content[0])
+	}
+	structured, err := json.Marshal(result.StructuredContent)
+	if err != nil {
+		t.Fatalf("failed to encode structured content: %v", err)
+	}
+	if string(structured) != expected {
+		t.Errorf("expected structured content %s, got %s", expected, structured)
+	}
+
+	// the output schema accepts the IDs as strings
+	tools, err := clientSession.ListTools(t.Context(), &mcp.ListToolsParams{})
+	if err != nil {
+		t.Fatalf("failed to list tools: %v", err)
+	}
+	for _, tool := range tools.Tools {
+		if tool.Name != "entity-get_task" {
+			continue
+		}
+		schema, err := json.Marshal(tool.OutputSchema)
+		if err != nil {
+			t.Fatalf("failed to encode output schema: %v", err)
+		}
+		for _, property := range []string{
+			`"id":{"type":["integer","string"]}`,
+			`"projectId":{"type":["null","integer","string"]}`,
+			`"tagIds":{"items":{"type":["integer","string"]},"type":"array"}`,
+			`"progress":{"type":"integer"}`,
+		} {
+			if !strings.Contains(string(schema), property) {
+				t.Errorf("expected output schema with %s, got %s", property, schema)
+			}
+		}
+	}
+}
+
+func TestServerRedaction(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.json")
+	config := `{"redaction":{"emails":true,"phones":true,"rates":true,"fields":["firstName"]}}`
+	if err := os.WriteFile(configFile, []byte(config), 0o600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	t.Setenv("TW_MCP_CONFIG_FILE", configFile)
+
+	server, err := teamworkmcp.New(t.Context(),
+		teamworkmcp.WithToolsetProvider(entityProvider{}),
+		teamworkmcp.WithToolsets("entity"),
+		teamworkmcp.WithLogOutput(io.Discard),
+	)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	defer server.Close()
+
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	if _, err := server.MCPServer().Connect(t.Context(), serverTransport, nil); err != nil {
+		t.Fatalf("failed to connect to server: %v", err)
+	}
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	clientSession, err := client.Connect(t.Context(), clientTransport, nil)
+	if err != nil {
+		t.Fatalf("failed to connect to client: %v", err)
+	}
+	defer clientSession.Close() //nolint:errcheck
+
+	result, err := clientSession.CallTool(t.Context(), &mcp.CallToolParams{Name: "entity-get_user"})
+	if err != nil {
+		t.Fatalf("failed to call tool: %v", err)
+	}
+	expected := `{"user":{"email":"[redacted]","firstName":"[redacted]","hourlyRate":"[redacted]","id":5,` +
+		`"phone-number-mobile":"[redacted]","title":"Call [redacted] or mail [redacted]"}}`
+	if text, ok := result.Content[0].(*mcp.TextContent); !ok || text.Text != expected {
+		t.Errorf("expected %s, got %v", expected, result.Content[0])
+	}
+}
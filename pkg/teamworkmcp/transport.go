@@ -0,0 +1,91 @@
+package teamworkmcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/auth"
+)
+
+// jsonRPCErrorCodeUnauthorized is the JSON-RPC error code of the requests that
+// require authentication.
+const jsonRPCErrorCodeUnauthorized = -32001
+
+// unauthenticatedTransport wraps a transport, answering the requests that
+// require authentication with a JSON-RPC unauthorized error. The session is
+// kept alive, so the client can still call the methods that bypass
+// authentication.
+type unauthenticatedTransport struct {
+	mcp.Transport
+}
+
+// Connect connects the wrapped transport.
+func (t unauthenticatedTransport) Connect(ctx context.Context) (mcp.Connection, error) {
+	conn, err := t.Transport.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return unauthenticatedConnection{Connection: conn}, nil
+}
+
+// unauthenticatedConnection filters the messages that require authentication.
+type unauthenticatedConnection struct {
+	mcp.Connection
+}
+
+// Read reads the next message that can bypass authentication. Calls to other
+// methods are answered directly, and notifications are dropped.
+func (c unauthenticatedConnection) Read(ctx context.Context) (jsonrpc.Message, error) {
+	for {
+		msg, err := c.Connection.Read(ctx)
+		if err != nil {
+			return nil, err
+		}
+		req, ok := msg.(*jsonrpc.Request)
+		if !ok || auth.BypassMethod(req.Method) {
+			return msg, nil
+		}
+		if !req.IsCall() {
+			continue
+		}
+		resp, err := unauthorizedResponse(req.ID)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.Write(ctx, resp); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// unauthorizedResponse builds the JSON-RPC unauthorized error response for the
+// request ID.
+func unauthorizedResponse(id jsonrpc.ID) (*jsonrpc.Response, error) {
+	// the library does not allow building errors with custom codes, so the
+	// response is decoded from its wire format
+	encoded, err := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      id.Raw(),
+		"error": map[string]any{
+			"code": jsonRPCErrorCodeUnauthorized,
+			"message": "not authenticated: configure the TW_MCP_BEARER_TOKEN environment variable with a valid " +
+				"Teamwork.com bearer token",
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode unauthorized response: %w", err)
+	}
+	msg, err := jsonrpc.DecodeMessage(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode unauthorized response: %w", err)
+	}
+	resp, ok := msg.(*jsonrpc.Response)
+	if !ok {
+		return nil, errors.New("unexpected unauthorized response message")
+	}
+	return resp, nil
+}